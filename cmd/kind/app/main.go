@@ -17,8 +17,10 @@ limitations under the License.
 package app
 
 import (
+	"fmt"
 	"io"
 	"os"
+	osexec "os/exec"
 
 	"github.com/spf13/pflag"
 
@@ -33,10 +35,24 @@ import (
 // it will then call os.Exit
 func Main() {
 	if err := Run(cmd.NewLogger(), cmd.StandardIOStreams(), os.Args[1:]); err != nil {
+		// exit with a plugin's exit code
+		if pluginErr, ok := err.(*pluginExitError); ok {
+			os.Exit(pluginErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// pluginExitError is returned by Run when a plugin exits non-zero, the
+// plugin reports its own errors
+type pluginExitError struct {
+	code int
+}
+
+func (e *pluginExitError) Error() string {
+	return fmt.Sprintf("plugin exited with code %d", e.code)
+}
+
 // Run invokes the kind root command, returning the error.
 // See: sigs.k8s.io/kind/pkg/cmd/kind
 func Run(logger log.Logger, streams cmd.IOStreams, args []string) error {
@@ -49,6 +65,10 @@ func Run(logger log.Logger, streams cmd.IOStreams, args []string) error {
 	}
 	// actually run the command
 	c := kind.NewCommand(logger, streams)
+	// unknown commands run a kind-<name> plugin from PATH, if there is one
+	if ran, err := kind.RunPlugin(logger, streams, c, args); ran {
+		return pluginError(logger, err)
+	}
 	c.SetArgs(args)
 	if err := c.Execute(); err != nil {
 		logError(logger, err)
@@ -57,6 +77,19 @@ func Run(logger log.Logger, streams cmd.IOStreams, args []string) error {
 	return nil
 }
 
+// pluginError returns the error for a plugin's err, only logging errors
+// from starting the plugin
+func pluginError(logger log.Logger, err error) error {
+	if err == nil {
+		return nil
+	}
+	if exitErr, ok := err.(*osexec.ExitError); ok && exitErr.ExitCode() > 0 {
+		return &pluginExitError{code: exitErr.ExitCode()}
+	}
+	logError(logger, err)
+	return err
+}
+
 // checkQuiet returns true if -q / --quiet was set in args
 func checkQuiet(args []string) bool {
 	flags := pflag.NewFlagSet("persistent-quiet", pflag.ContinueOnError)
@@ -86,11 +119,11 @@ func logError(logger log.Logger, err error) {
 		logger.Errorf("ERROR: %v", err)
 	}
 	// Display Output if the error was from running a command ...
-	if err := exec.RunErrorForError(err); err != nil {
+	if err := exec.CommandErrorForError(err); err != nil {
 		if colorEnabled {
-			logger.Errorf("\x1b[31mCommand Output\x1b[0m: %s", err.Output)
+			logger.Errorf("\x1b[31mCommand Output\x1b[0m: %s", err.Output())
 		} else {
-			logger.Errorf("\nCommand Output: %s", err.Output)
+			logger.Errorf("\nCommand Output: %s", err.Output())
 		}
 	}
 	// TODO: stacktrace should probably be guarded by a higher level ...?
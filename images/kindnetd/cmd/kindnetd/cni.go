@@ -35,6 +35,8 @@ type CNIConfigInputs struct {
 	PodCIDRs      []string
 	DefaultRoutes []string
 	Mtu           int
+	// PortMapBackend is the portmap plugin backend, e.g. "nftables"
+	PortMapBackend string
 }
 
 // ComputeCNIConfigInputs computes the template inputs for CNIConfigWriter
@@ -112,6 +114,9 @@ const cniConfigTemplate = `
 	},
 	{
 		"type": "portmap",
+		{{if .PortMapBackend}}
+		"backend": "{{ .PortMapBackend }}",
+		{{end}}
 		"capabilities": {
 			"portMappings": true
 		}
@@ -126,11 +131,14 @@ type CNIConfigWriter struct {
 	path       string
 	lastInputs CNIConfigInputs
 	mtu        int
+	// portMapBackend is the portmap plugin backend
+	portMapBackend string
 }
 
 // Write will write the config based on
 func (c *CNIConfigWriter) Write(inputs CNIConfigInputs) error {
 	inputs.Mtu = c.mtu
+	inputs.PortMapBackend = c.portMapBackend
 	if reflect.DeepEqual(inputs, c.lastInputs) {
 		return nil
 	}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net"
@@ -43,6 +44,12 @@ const (
 	probeTCPtimeout = 1 * time.Second
 )
 
+var pprofAddress = flag.String("pprof-address", os.Getenv("PPROF_ADDRESS"),
+	"address to serve the net/http/pprof debug endpoints on, e.g. localhost:6060, disabled if empty")
+
+var metricsAddress = flag.String("metrics-address", os.Getenv("METRICS_ADDRESS"),
+	"address to serve prometheus metrics on, e.g. localhost:9177, disabled if empty")
+
 // kindnetd is a simple networking daemon to complete kind's CNI implementation
 // kindnetd will ensure routes to the other node's PodCIDR via their InternalIP
 // kindnetd will ensure pod to pod communication will not be masquerade
@@ -53,6 +60,8 @@ const (
 // - POD_IP: should be populated by downward API
 // - CNI_CONFIG_TEMPLATE: the cni .conflist template, run with {{ .PodCIDR }}
 // - CONTROL_PLANE_ENDPOINT: control-plane endpoint format host:port
+// - PPROF_ADDRESS: optional default for --pprof-address
+// - METRICS_ADDRESS: optional default for --metrics-address
 
 // TODO: improve logging & error handling
 
@@ -74,6 +83,16 @@ func main() {
 	_ = flag.Set("logtostderr", "true")
 	flag.Parse()
 
+	// optionally serve pprof for profiling in place
+	if *pprofAddress != "" {
+		go servePprof(*pprofAddress)
+	}
+
+	// optionally serve metrics, e.g. reconcile errors
+	if *metricsAddress != "" {
+		go serveMetrics(*metricsAddress)
+	}
+
 	// create a Kubernetes client
 	config, err := rest.InClusterConfig()
 	if err != nil {
@@ -153,6 +172,9 @@ func main() {
 	cniConfigWriter := &CNIConfigWriter{
 		path: cniConfigPath,
 		mtu:  mtu,
+		// hostPorts use nftables like the masquerade agents and network
+		// policies, so the nodes' rules are not split across backends
+		portMapBackend: "nftables",
 	}
 
 	// enforce ip masquerade rules
@@ -198,7 +220,6 @@ func main() {
 		if err != nil {
 			panic(err.Error())
 		}
-
 		go func() {
 			if err := masqAgentIPv6.SyncRulesForever(ctx, time.Second*60); err != nil {
 				panic(err)
@@ -263,17 +284,22 @@ func main() {
 			panic("Reached maximum retries obtaining node list: " + err.Error())
 		}
 
-		// reconcile the nodes with retries
-		for i := 0; i < 5; i++ {
-			err = reconcileNodes(nodes)
-			if err == nil {
-				break
+		// reconcile the nodes, retrying only the nodes that failed with
+		// a backoff, and leaving any still failing to the next resync
+		// rather than crashing, so one bad node does not break the others
+		pending := nodes
+		for i := 0; i < 5 && len(pending) > 0; i++ {
+			if i > 0 {
+				time.Sleep(time.Second * time.Duration(1<<(i-1)))
+			}
+			pending, err = reconcileNodes(pending)
+			if err != nil {
+				klog.Infof("Failed to reconcile %d nodes, retrying after error: %v", len(pending), err)
 			}
-			klog.Infof("Failed to reconcile routes, retrying after error: %v", err)
-			time.Sleep(time.Second * time.Duration(i))
 		}
-		if err != nil {
-			panic("Maximum retries reconciling node routes: " + err.Error())
+		failingNodes.Set(float64(len(pending)))
+		if len(pending) > 0 {
+			klog.Errorf("Giving up reconciling %d nodes until the next resync: %v", len(pending), err)
 		}
 
 		// rate limit
@@ -288,7 +314,7 @@ func main() {
 }
 
 // nodeNodesReconciler returns a reconciliation func for nodes
-func makeNodesReconciler(cniConfig *CNIConfigWriter, hostIP string, ipFamily IPFamily) func([]*corev1.Node) error {
+func makeNodesReconciler(cniConfig *CNIConfigWriter, hostIP string, ipFamily IPFamily) func([]*corev1.Node) ([]*corev1.Node, error) {
 	// reconciles a node
 	reconcileNode := func(node *corev1.Node) error {
 		// first get this node's IPs
@@ -347,14 +373,19 @@ func makeNodesReconciler(cniConfig *CNIConfigWriter, hostIP string, ipFamily IPF
 		return nil
 	}
 
-	// return a reconciler for all the nodes
-	return func(nodes []*corev1.Node) error {
+	// return a reconciler for all the nodes, which continues past nodes
+	// that fail and returns them along with their aggregated errors
+	return func(nodes []*corev1.Node) ([]*corev1.Node, error) {
+		var failed []*corev1.Node
+		var errs []error
 		for _, node := range nodes {
 			if err := reconcileNode(node); err != nil {
-				return err
+				nodeReconcileErrors.WithLabelValues(node.Name).Inc()
+				failed = append(failed, node)
+				errs = append(errs, fmt.Errorf("node %s: %w", node.Name, err))
 			}
 		}
-		return nil
+		return failed, errors.Join(errs...)
 	}
 }
 
@@ -20,37 +20,56 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"time"
 
-	"github.com/coreos/go-iptables/iptables"
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"github.com/google/nftables/userdata"
+	"golang.org/x/sys/unix"
 )
 
 // NewIPMasqAgent returns a new IPMasqAgent
+// The masquerade rules are programmed with nftables over netlink, so no nft
+// or iptables binaries are needed
 func NewIPMasqAgent(ipv6 bool, noMasqueradeCIDRs []string) (*IPMasqAgent, error) {
-	protocol := iptables.ProtocolIPv4
-	if ipv6 {
-		protocol = iptables.ProtocolIPv6
-	}
-	ipt, err := iptables.NewWithProtocol(protocol)
+	nft, err := nftables.New()
 	if err != nil {
 		return nil, err
 	}
+	return newIPMasqAgent(nft, ipv6, noMasqueradeCIDRs)
+}
 
-	// TODO: validate cidrs
-	return &IPMasqAgent{
-		iptables:          ipt,
-		masqChain:         masqChainName,
-		noMasqueradeCIDRs: noMasqueradeCIDRs,
-	}, nil
+func newIPMasqAgent(nft *nftables.Conn, ipv6 bool, noMasqueradeCIDRs []string) (*IPMasqAgent, error) {
+	ma := &IPMasqAgent{
+		nft:    nft,
+		family: nftables.TableFamilyIPv4,
+	}
+	if ipv6 {
+		ma.family = nftables.TableFamilyIPv6
+	}
+	for _, cidr := range noMasqueradeCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid no masquerade CIDR %q: %w", cidr, err)
+		}
+		if (ipNet.IP.To4() == nil) != ipv6 {
+			return nil, fmt.Errorf("no masquerade CIDR %q is not in the agent's IP family", cidr)
+		}
+		ma.noMasqueradeCIDRs = append(ma.noMasqueradeCIDRs, ipNet)
+	}
+	return ma, nil
 }
 
 // IPMasqAgent is based on https://github.com/kubernetes-incubator/ip-masq-agent
 // but collapsed into kindnetd and made ipv6 aware in an opinionated and simplified
-// fashion using "github.com/coreos/go-iptables"
+// fashion using "github.com/google/nftables"
 type IPMasqAgent struct {
-	iptables          *iptables.IPTables
-	masqChain         string
-	noMasqueradeCIDRs []string
+	nft               *nftables.Conn
+	family            nftables.TableFamily
+	noMasqueradeCIDRs []*net.IPNet
 }
 
 // SyncRulesForever syncs ip masquerade rules forever
@@ -78,41 +97,124 @@ func (ma *IPMasqAgent) SyncRulesForever(ctx context.Context, interval time.Durat
 	}
 }
 
-// name of nat chain for iptables masquerade rules
-const masqChainName = "KIND-MASQ-AGENT"
+const (
+	// name of the nftables table for masquerade rules, in the ip or ip6 family
+	masqTableName = "kindnet-ipmasq"
+	// name of the nftables nat postrouting chain
+	masqPostroutingChain = "postrouting"
+	// name of the nftables set of destinations that are not masqueraded
+	noMasqueradeSet = "no-masquerade"
+)
 
-// SyncRules syncs ip masquerade rules
+// SyncRules replaces the masquerade table in a single netlink batch, so the
+// rules are never partially applied
 func (ma *IPMasqAgent) SyncRules() error {
-	// make sure our custom chain for non-masquerade exists
-	exists := false
-	chains, err := ma.iptables.ListChains("nat")
-	if err != nil {
-		return fmt.Errorf("failed to list chains: %v", err)
-	}
-	for _, ch := range chains {
-		if ch == ma.masqChain {
-			exists = true
-			break
-		}
-	}
-	if !exists {
-		if err = ma.iptables.NewChain("nat", ma.masqChain); err != nil {
-			return err
-		}
+	table := &nftables.Table{
+		Name:   masqTableName,
+		Family: ma.family,
 	}
+	// adding the table first ensures deleting it succeeds
+	ma.nft.AddTable(table)
+	ma.nft.DelTable(table)
+	ma.nft.AddTable(table)
 
 	// Packets to this network should not be masquerade, pods should be able to talk to other pods
-	for _, cidr := range ma.noMasqueradeCIDRs {
-		if err := ma.iptables.AppendUnique("nat", ma.masqChain, "-d", cidr, "-j", "RETURN", "-m", "comment", "--comment", "kind-masq-agent: local traffic is not subject to MASQUERADE"); err != nil {
-			return err
-		}
+	keyType, daddrOffset, daddrLen := nftables.TypeIPAddr, uint32(16), uint32(4)
+	if ma.family == nftables.TableFamilyIPv6 {
+		keyType, daddrOffset, daddrLen = nftables.TypeIP6Addr, 24, 16
 	}
-
-	// Masquerade all the other traffic
-	if err := ma.iptables.AppendUnique("nat", ma.masqChain, "-j", "MASQUERADE", "-m", "comment", "--comment", "kind-masq-agent: outbound traffic is subject to MASQUERADE (must be last in chain)"); err != nil {
-		return err
+	set := &nftables.Set{
+		Table:    table,
+		Name:     noMasqueradeSet,
+		KeyType:  keyType,
+		Interval: true,
+	}
+	if err := ma.nft.AddSet(set, intervalElements(ma.noMasqueradeCIDRs)); err != nil {
+		return fmt.Errorf("failed to add set %s: %w", noMasqueradeSet, err)
 	}
 
-	// Send all non-LOCAL destination traffic to our custom KIND-MASQ-AGENT chain
-	return ma.iptables.AppendUnique("nat", "POSTROUTING", "-m", "addrtype", "!", "--dst-type", "LOCAL", "-j", ma.masqChain, "-m", "comment", "--comment", "kind-masq-agent: ensure nat POSTROUTING directs all non-LOCAL destination traffic to our custom KIND-MASQ-AGENT chain")
+	chain := ma.nft.AddChain(&nftables.Chain{
+		Name:     masqPostroutingChain,
+		Table:    table,
+		Type:     nftables.ChainTypeNAT,
+		Hooknum:  nftables.ChainHookPostrouting,
+		Priority: nftables.ChainPriorityNATSource,
+	})
+	// ip daddr @no-masquerade return
+	ma.nft.AddRule(&nftables.Rule{
+		Table: table,
+		Chain: chain,
+		Exprs: []expr.Any{
+			&expr.Payload{
+				DestRegister: 1,
+				Base:         expr.PayloadBaseNetworkHeader,
+				Offset:       daddrOffset,
+				Len:          daddrLen,
+			},
+			&expr.Lookup{
+				SourceRegister: 1,
+				SetName:        set.Name,
+				SetID:          set.ID,
+			},
+			&expr.Verdict{Kind: expr.VerdictReturn},
+		},
+		UserData: ruleComment("kind-masq-agent: local traffic is not subject to MASQUERADE"),
+	})
+	// fib daddr type local return
+	ma.nft.AddRule(&nftables.Rule{
+		Table: table,
+		Chain: chain,
+		Exprs: []expr.Any{
+			&expr.Fib{
+				Register:       1,
+				FlagDADDR:      true,
+				ResultADDRTYPE: true,
+			},
+			&expr.Cmp{
+				Op:       expr.CmpOpEq,
+				Register: 1,
+				Data:     binaryutil.NativeEndian.PutUint32(unix.RTN_LOCAL),
+			},
+			&expr.Verdict{Kind: expr.VerdictReturn},
+		},
+		UserData: ruleComment("kind-masq-agent: LOCAL destination traffic is not subject to MASQUERADE"),
+	})
+	// masquerade
+	ma.nft.AddRule(&nftables.Rule{
+		Table:    table,
+		Chain:    chain,
+		Exprs:    []expr.Any{&expr.Masq{}},
+		UserData: ruleComment("kind-masq-agent: outbound traffic is subject to MASQUERADE (must be last in chain)"),
+	})
+	return ma.nft.Flush()
+}
+
+// ruleComment returns the user data for a rule comment, as nft sets it
+func ruleComment(comment string) []byte {
+	return userdata.AppendString(nil, userdata.TypeComment, comment)
+}
+
+// intervalElements returns the elements of an interval set containing
+// cidrs. Each interval is its first address and the address after its last,
+// which is left out if the interval ends at the last address.
+func intervalElements(cidrs []*net.IPNet) []nftables.SetElement {
+	elements := []nftables.SetElement{}
+	for _, cidr := range cidrs {
+		first := cidr.IP.Mask(cidr.Mask)
+		if ip4 := first.To4(); ip4 != nil {
+			first = ip4
+		}
+		elements = append(elements, nftables.SetElement{Key: first})
+		ones, bits := cidr.Mask.Size()
+		size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+		end := new(big.Int).Add(new(big.Int).SetBytes(first), size)
+		if end.BitLen() > bits {
+			continue
+		}
+		elements = append(elements, nftables.SetElement{
+			Key:         end.FillBytes(make([]byte, len(first))),
+			IntervalEnd: true,
+		})
+	}
+	return elements
 }
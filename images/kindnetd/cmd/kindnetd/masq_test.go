@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/google/nftables"
+	"github.com/mdlayher/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestIntervalElements(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		cidrs    []string
+		expected []nftables.SetElement
+	}{
+		{
+			name:  "ipv4",
+			cidrs: []string{"10.244.0.0/16", "192.168.1.7/32"},
+			expected: []nftables.SetElement{
+				{Key: []byte{10, 244, 0, 0}},
+				{Key: []byte{10, 245, 0, 0}, IntervalEnd: true},
+				{Key: []byte{192, 168, 1, 7}},
+				{Key: []byte{192, 168, 1, 8}, IntervalEnd: true},
+			},
+		},
+		{
+			name:  "ipv4 ending at the last address",
+			cidrs: []string{"255.255.255.0/24"},
+			expected: []nftables.SetElement{
+				{Key: []byte{255, 255, 255, 0}},
+			},
+		},
+		{
+			name:  "ipv6",
+			cidrs: []string{"fd00:10:244::/56"},
+			expected: []nftables.SetElement{
+				{Key: net.ParseIP("fd00:10:244::")},
+				{Key: net.ParseIP("fd00:10:244:100::"), IntervalEnd: true},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cidrs := []*net.IPNet{}
+			for _, cidr := range tc.cidrs {
+				_, ipNet, err := net.ParseCIDR(cidr)
+				if err != nil {
+					t.Fatalf("failed to parse %q: %v", cidr, err)
+				}
+				cidrs = append(cidrs, ipNet)
+			}
+			if elements := intervalElements(cidrs); !reflect.DeepEqual(elements, tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, elements)
+			}
+		})
+	}
+}
+
+func TestNewIPMasqAgentInvalidCIDRs(t *testing.T) {
+	t.Parallel()
+	for _, cidr := range []string{"10.244.0.0", "fd00:10:244::/56"} {
+		if _, err := newIPMasqAgent(&nftables.Conn{}, false, []string{cidr}); err == nil {
+			t.Errorf("expected an error for IPv4 no masquerade CIDR %q", cidr)
+		}
+	}
+}
+
+func TestSyncRules(t *testing.T) {
+	t.Parallel()
+	sent := []netlink.HeaderType{}
+	nft, err := nftables.New(nftables.WithTestDial(func(req []netlink.Message) ([]netlink.Message, error) {
+		for _, msg := range req {
+			if msg.Header.Type>>8 == unix.NFNL_SUBSYS_NFTABLES {
+				sent = append(sent, msg.Header.Type&0xff)
+			}
+		}
+		return req, nil
+	}))
+	if err != nil {
+		t.Fatalf("failed to create nftables connection: %v", err)
+	}
+	ma, err := newIPMasqAgent(nft, false, []string{"10.244.0.0/16"})
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	if err := ma.SyncRules(); err != nil {
+		t.Fatalf("failed to sync rules: %v", err)
+	}
+	// the table is replaced and its contents added in a single batch
+	expected := []netlink.HeaderType{
+		unix.NFT_MSG_NEWTABLE,
+		unix.NFT_MSG_DELTABLE,
+		unix.NFT_MSG_NEWTABLE,
+		unix.NFT_MSG_NEWSET,
+		unix.NFT_MSG_NEWSETELEM,
+		unix.NFT_MSG_NEWCHAIN,
+		unix.NFT_MSG_NEWRULE,
+		unix.NFT_MSG_NEWRULE,
+		unix.NFT_MSG_NEWRULE,
+	}
+	if !reflect.DeepEqual(sent, expected) {
+		t.Errorf("expected messages %v but got %v", expected, sent)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+var (
+	nodeReconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kindnetd_node_reconcile_errors_total",
+		Help: "Number of errors reconciling the routes or CNI config for a node.",
+	}, []string{"node"})
+	failingNodes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kindnetd_failing_nodes",
+		Help: "Number of nodes that could not be reconciled in the last resync.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(nodeReconcileErrors, failingNodes)
+}
+
+// serveMetrics serves the prometheus metrics on address until the process
+// exits
+func serveMetrics(address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	klog.Infof("serving metrics on %s", address)
+	if err := http.ListenAndServe(address, mux); err != nil {
+		klog.Errorf("failed to serve metrics: %v", err)
+	}
+}
@@ -76,6 +76,16 @@ func SetDefaultsCluster(obj *Cluster) {
 	if obj.Networking.KubeProxyMode == "" {
 		obj.Networking.KubeProxyMode = IPTablesProxyMode
 	}
+	// default the bridged network driver to macvlan
+	if obj.Networking.BridgedNetwork != nil && obj.Networking.BridgedNetwork.Driver == "" {
+		obj.Networking.BridgedNetwork.Driver = MacvlanDriver
+	}
+	// default kubeadm patches to strategic merge patches, matching kubeadm
+	for i := range obj.KubeadmPatches {
+		if obj.KubeadmPatches[i].PatchType == "" {
+			obj.KubeadmPatches[i].PatchType = KubeadmPatchTypeStrategic
+		}
+	}
 }
 
 // SetDefaultsNode sets uninitialized fields to their default value.
@@ -87,4 +97,11 @@ func SetDefaultsNode(obj *Node) {
 	if obj.Role == "" {
 		obj.Role = ControlPlaneRole
 	}
+
+	// default interface names to the network names
+	for i := range obj.AdditionalNetworks {
+		if obj.AdditionalNetworks[i].Interface == "" {
+			obj.AdditionalNetworks[i].Interface = obj.AdditionalNetworks[i].Name
+		}
+	}
 }
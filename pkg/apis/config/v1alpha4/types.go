@@ -30,11 +30,42 @@ type Cluster struct {
 	// control plane load balancer will be provisioned implicitly
 	Nodes []Node `yaml:"nodes,omitempty" json:"nodes,omitempty"`
 
+	// AllNodes contains settings applied to every node in Nodes, in addition
+	// to each node's own settings
+	AllNodes NodeDefaults `yaml:"allNodes,omitempty" json:"allNodes,omitempty"`
+
 	/* Advanced fields */
 
 	// Networking contains cluster wide network settings
 	Networking Networking `yaml:"networking,omitempty" json:"networking,omitempty"`
 
+	// Addons contains settings for optional addons installed by kind
+	Addons Addons `yaml:"addons,omitempty" json:"addons,omitempty"`
+
+	// Certificates contains settings for the cluster CA and certificates
+	Certificates Certificates `yaml:"certificates,omitempty" json:"certificates,omitempty"`
+
+	// EncryptionAtRest configures the API server to encrypt resources such
+	// as secrets before storing them in etcd
+	EncryptionAtRest EncryptionAtRest `yaml:"encryptionAtRest,omitempty" json:"encryptionAtRest,omitempty"`
+
+	// PodSecurity configures the cluster-wide defaults of the PodSecurity
+	// admission controller
+	PodSecurity PodSecurity `yaml:"podSecurity,omitempty" json:"podSecurity,omitempty"`
+
+	// Timeouts contains timeouts and retries for the phases of cluster creation
+	Timeouts Timeouts `yaml:"timeouts,omitempty" json:"timeouts,omitempty"`
+
+	// ReadyConditions are additional conditions to wait for before cluster
+	// creation completes, in the format accepted by `kind wait --for`, e.g.
+	// "daemonset=kube-system/kindnet", "deployment=default/my-app" or
+	// "crd=widgets.example.com".
+	//
+	// These are waited for after the control plane is Ready, within the
+	// --wait / timeouts.nodesReady time, or five minutes if neither is set.
+	// Cluster creation fails if they are not met in time.
+	ReadyConditions []string `yaml:"readyConditions,omitempty" json:"readyConditions,omitempty"`
+
 	// FeatureGates contains a map of Kubernetes feature gates to whether they
 	// are enabled. The feature gates specified here are passed to all Kubernetes components as flags or in config.
 	//
@@ -46,6 +77,15 @@ type Cluster struct {
 	// Use this to enable alpha APIs.
 	RuntimeConfig map[string]string `yaml:"runtimeConfig,omitempty" json:"runtimeConfig,omitempty"`
 
+	// SwapBehavior configures how the kubelet lets workloads use swap, one of
+	// "NoSwap" or "LimitedSwap". Nodes only have swap if the host does, see
+	// Node.MemorySwap to limit it.
+	//
+	// https://kubernetes.io/docs/concepts/cluster-administration/swap-memory-management/
+	//
+	// Defaults to the kubelet's default
+	SwapBehavior SwapBehavior `yaml:"swapBehavior,omitempty" json:"swapBehavior,omitempty"`
+
 	// KubeadmConfigPatches are applied to the generated kubeadm config as
 	// merge patches. The `kind` field must match the target object, and
 	// if `apiVersion` is specified it will only be applied to matching objects.
@@ -72,6 +112,28 @@ type Cluster struct {
 	// The cluster-level patches are applied before the node-level patches.
 	KubeadmConfigPatchesJSON6902 []PatchJSON6902 `yaml:"kubeadmConfigPatchesJSON6902,omitempty" json:"kubeadmConfigPatchesJSON6902,omitempty"`
 
+	// KubeadmPatches are per-component patches written to every node and
+	// passed to kubeadm via its patches directory support.
+	// Unlike KubeadmConfigPatches these patch the component manifests and
+	// configuration generated by kubeadm, rather than the kubeadm config.
+	//
+	// Patches for the same target are applied in the order listed.
+	//
+	// https://kubernetes.io/docs/setup/production-environment/tools/kubeadm/control-plane-flags/#patches
+	KubeadmPatches []KubeadmPatch `yaml:"kubeadmPatches,omitempty" json:"kubeadmPatches,omitempty"`
+
+	// ComponentImages overrides the images of the control plane components
+	// kubeadm runs as static pods, e.g. to run a custom kube-scheduler build.
+	// The nodes must be able to pull the images, e.g. from a local registry.
+	ComponentImages ComponentImages `yaml:"componentImages,omitempty" json:"componentImages,omitempty"`
+
+	// KubeadmInitSkipPhases lists additional `kubeadm init` phases to skip,
+	// e.g. "addon/kube-proxy" to avoid deploying the kube-proxy addon.
+	// These are in addition to any phases kind already skips.
+	//
+	// https://kubernetes.io/docs/reference/setup-tools/kubeadm/kubeadm-init-phase/
+	KubeadmInitSkipPhases []string `yaml:"kubeadmInitSkipPhases,omitempty" json:"kubeadmInitSkipPhases,omitempty"`
+
 	// ContainerdConfigPatches are applied to every node's containerd config
 	// in the order listed.
 	// These should be toml stringsto be applied as merge patches
@@ -81,6 +143,68 @@ type Cluster struct {
 	// in the order listed.
 	// These should be YAML or JSON formatting RFC 6902 JSON patches
 	ContainerdConfigPatchesJSON6902 []string `yaml:"containerdConfigPatchesJSON6902,omitempty" json:"containerdConfigPatchesJSON6902,omitempty"`
+
+	// InsecureRegistries lists registries as "host:port" that every node may
+	// pull from and push to over plain HTTP, or HTTPS without verifying the
+	// certificate, e.g. a local development registry.
+	//
+	// For containerd this sets the registry config_path and writes hosts.toml
+	// files, so it cannot be combined with containerdConfigPatches setting
+	// the deprecated registry mirrors.
+	InsecureRegistries []string `yaml:"insecureRegistries,omitempty" json:"insecureRegistries,omitempty"`
+
+	// NeverPullRegistries lists registries as "host[:port]", e.g. "docker.io",
+	// that the nodes never pull from, like a pull policy of Never for their
+	// images. This prevents accidental external pulls in offline test runs
+	// where all images are preloaded, e.g. with `kind load`. Pulling an
+	// image from these registries fails fast, including for pods with a pull
+	// policy of Always, so such pods must use IfNotPresent instead.
+	//
+	// For containerd this sets the registry config_path and writes hosts.toml
+	// files, like InsecureRegistries. A registry may not be in both.
+	NeverPullRegistries []string `yaml:"neverPullRegistries,omitempty" json:"neverPullRegistries,omitempty"`
+
+	// RecordImagePulls records every image the nodes' container runtime is
+	// asked to pull, see `kind get pulled-images`. This helps to find the
+	// images a registry mirror or allowlist must contain.
+	RecordImagePulls bool `yaml:"recordImagePulls,omitempty" json:"recordImagePulls,omitempty"`
+
+	// Diagnostics configures capturing diagnostics on the nodes into
+	// /var/log/kind-diagnostics, which `kind export logs` collects.
+	Diagnostics Diagnostics `yaml:"diagnostics,omitempty" json:"diagnostics,omitempty"`
+
+	// HollowNodes adds kubemark "hollow" nodes to the cluster, for scale
+	// testing the control plane and scheduler with many more nodes than
+	// could otherwise run on one host.
+	HollowNodes HollowNodes `yaml:"hollowNodes,omitempty" json:"hollowNodes,omitempty"`
+}
+
+// HollowNodes configures kubemark hollow nodes. Each hollow node is a small
+// container on the cluster's network running a hollow kubelet that registers
+// with the control plane but does not run containers.
+type HollowNodes struct {
+	// Count is the number of hollow nodes to create
+	Count int32 `yaml:"count,omitempty" json:"count,omitempty"`
+
+	// Image is the kubemark image to run, this is required when Count is set.
+	// It should match the cluster's Kubernetes version, and can be built
+	// from a Kubernetes checkout with cluster/images/kubemark
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+}
+
+// Diagnostics configures capturing diagnostics on the nodes, to help debug
+// nodes or components dying under load. Mount a host directory at
+// /var/log/kind-diagnostics with extraMounts to keep them if a node dies.
+type Diagnostics struct {
+	// OOMEvents records the kernel OOM killer's messages on each node to
+	// /var/log/kind-diagnostics/oom-events.log
+	OOMEvents bool `yaml:"oomEvents,omitempty" json:"oomEvents,omitempty"`
+
+	// CoreDumps enables core dumps for the kubelet and containerd, written
+	// to /var/log/kind-diagnostics/cores. The kernel's core_pattern is
+	// shared with the host and is not changed, the dumps are only written
+	// there if it is a relative file name such as the default "core".
+	CoreDumps bool `yaml:"coreDumps,omitempty" json:"coreDumps,omitempty"`
 }
 
 // TypeMeta partially copies apimachinery/pkg/apis/meta/v1.TypeMeta
@@ -90,6 +214,37 @@ type TypeMeta struct {
 	APIVersion string `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
 }
 
+// NodeDefaults contains settings applied to every node in the `kind` Cluster
+type NodeDefaults struct {
+	// Labels are added to every node's Labels
+	// A node's own Labels take precedence over these for the same key
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+
+	// ExtraMounts are added to every node's ExtraMounts
+	// A node's own ExtraMounts take precedence over these for the same
+	// containerPath
+	ExtraMounts []Mount `yaml:"extraMounts,omitempty" json:"extraMounts,omitempty"`
+
+	// Env is added to every node's Env
+	// A node's own Env takes precedence over this for the same variable
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+
+	// Sysctls are added to every node's Sysctls
+	// A node's own Sysctls take precedence over these for the same key
+	Sysctls map[string]string `yaml:"sysctls,omitempty" json:"sysctls,omitempty"`
+
+	// ContainerdSnapshotter is every node's ContainerdSnapshotter, unless
+	// the node sets its own
+	ContainerdSnapshotter ContainerdSnapshotter `yaml:"containerdSnapshotter,omitempty" json:"containerdSnapshotter,omitempty"`
+
+	// ImageCache enables every node's ImageCache
+	ImageCache bool `yaml:"imageCache,omitempty" json:"imageCache,omitempty"`
+
+	// Logging is every node's Logging, a node's own Logging fields take
+	// precedence over these
+	Logging NodeLogging `yaml:"logging,omitempty" json:"logging,omitempty"`
+}
+
 // Node contains settings for a node in the `kind` Cluster.
 // A node in kind config represent a container that will be provisioned with all the components
 // required for the assigned role in the Kubernetes cluster
@@ -118,6 +273,10 @@ type Node struct {
 	// binded to a host Port
 	ExtraPortMappings []PortMapping `yaml:"extraPortMappings,omitempty" json:"extraPortMappings,omitempty"`
 
+	// AdditionalNetworks attaches the node to some of the cluster's
+	// networking.additionalNetworks, each as a secondary interface
+	AdditionalNetworks []NodeNetwork `yaml:"additionalNetworks,omitempty" json:"additionalNetworks,omitempty"`
+
 	// KubeadmConfigPatches are applied to the generated kubeadm config as
 	// merge patches. The `kind` field must match the target object, and
 	// if `apiVersion` is specified it will only be applied to matching objects.
@@ -145,6 +304,214 @@ type Node struct {
 	// The node-level patches will be applied after the cluster-level patches
 	// have been applied. (See Cluster.KubeadmConfigPatchesJSON6902)
 	KubeadmConfigPatchesJSON6902 []PatchJSON6902 `yaml:"kubeadmConfigPatchesJSON6902,omitempty" json:"kubeadmConfigPatchesJSON6902,omitempty"`
+
+	// SystemdUnits are extra systemd units installed on the node before
+	// kubeadm runs, e.g. for a node-local cache or a logging agent
+	SystemdUnits []SystemdUnit `yaml:"systemdUnits,omitempty" json:"systemdUnits,omitempty"`
+
+	// StaticPods are extra static pod manifests installed on the node before
+	// kubeadm runs, e.g. for node agents or control plane sidecars that must
+	// be running before the API server is
+	StaticPods []StaticPod `yaml:"staticPods,omitempty" json:"staticPods,omitempty"`
+
+	// Env are environment variables set for the node container, and for the
+	// kubelet on the node
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+
+	// Sysctls are set on the node container, e.g. "net.core.somaxconn"
+	// Only sysctls namespaced to the container may be set, others such as
+	// "vm.max_map_count" must be set on the host instead
+	Sysctls map[string]string `yaml:"sysctls,omitempty" json:"sysctls,omitempty"`
+
+	// Memory is the memory limit of the node container, e.g. "4g"
+	Memory string `yaml:"memory,omitempty" json:"memory,omitempty"`
+
+	// MemorySwap is the limit of memory plus swap of the node container,
+	// e.g. "6g" to allow 2g of swap with a Memory of "4g", or "-1" for
+	// unlimited swap. Requires Memory
+	MemorySwap string `yaml:"memorySwap,omitempty" json:"memorySwap,omitempty"`
+
+	// KubeReserved reserves resources for Kubernetes components on this
+	// node from the kubelet's allocatable resources, e.g. {"memory": "1Gi"}.
+	// The keys are "cpu", "memory", "ephemeral-storage" and "pid".
+	// If Memory is set, memory defaults to 10% of it.
+	//
+	// https://kubernetes.io/docs/tasks/administer-cluster/reserve-compute-resources/
+	KubeReserved map[string]string `yaml:"kubeReserved,omitempty" json:"kubeReserved,omitempty"`
+
+	// SystemReserved reserves resources for the rest of the system on this
+	// node, with the same keys as KubeReserved
+	SystemReserved map[string]string `yaml:"systemReserved,omitempty" json:"systemReserved,omitempty"`
+
+	// EvictionHard sets the kubelet's hard eviction thresholds on this node,
+	// e.g. {"memory.available": "500Mi"}. kind disables disk based eviction
+	// by default, these thresholds are kept unless set here.
+	//
+	// The kubelet sees all of the host's memory, so if Memory is set
+	// memory.available defaults to the host memory outside the node's limit
+	// plus 5% of the limit, keeping the node's pods within its limit
+	// instead of the node container running out of memory.
+	EvictionHard map[string]string `yaml:"evictionHard,omitempty" json:"evictionHard,omitempty"`
+
+	// SandboxImage overrides the container runtime's sandbox ("pause")
+	// image on this node, e.g. with a copy in a mirror for air-gapped
+	// setups. The image is pulled into the node before Kubernetes is set up,
+	// so it must be pullable by the node, see containerdConfigPatches for
+	// registry mirrors.
+	//
+	// If unset the pause image in the node image is used
+	SandboxImage string `yaml:"sandboxImage,omitempty" json:"sandboxImage,omitempty"`
+
+	// ContainerdSnapshotter selects the snapshotter containerd stores image
+	// layers and container filesystems with on this node, one of
+	// "overlayfs", "fuse-overlayfs", "native" or "stargz".
+	// "fuse-overlayfs" works where overlayfs is not available, e.g. some
+	// rootless setups, and "stargz" lazily pulls eStargz images.
+	//
+	// If unset the node picks one based on its filesystem, preferring
+	// overlayfs, or uses $KIND_EXPERIMENTAL_CONTAINERD_SNAPSHOTTER
+	ContainerdSnapshotter ContainerdSnapshotter `yaml:"containerdSnapshotter,omitempty" json:"containerdSnapshotter,omitempty"`
+
+	// ImageCache stores this node's containerd content and snapshots
+	// (/var/lib/containerd) in a named volume that is kept when the cluster
+	// is deleted, and reused when a cluster of the same name is created
+	// again, so images pulled or loaded into the node survive recreating
+	// the cluster. The volume is recreated if the node image changes.
+	// Use `kind prune volumes` to remove the volumes.
+	//
+	// Only supported by the docker and podman providers.
+	ImageCache bool `yaml:"imageCache,omitempty" json:"imageCache,omitempty"`
+
+	// Logging configures the log verbosity of the kubelet and containerd
+	// on this node, and the rate limit of its journal, to help debug them.
+	// See also `kind set log-level` to change the verbosity of a running
+	// cluster's nodes
+	Logging NodeLogging `yaml:"logging,omitempty" json:"logging,omitempty"`
+
+	// Nesting configures this node for creating kind clusters inside of it,
+	// for example from CI jobs running in pods on this node ("kind-in-kind")
+	Nesting Nesting `yaml:"nesting,omitempty" json:"nesting,omitempty"`
+
+	// Simulate presents this node to the scheduler as if it ran a different
+	// operating system or architecture, for testing the scheduling of
+	// controllers targeting hybrid clusters. The node still runs linux
+	// containers on the host's architecture.
+	Simulate NodeSimulation `yaml:"simulate,omitempty" json:"simulate,omitempty"`
+}
+
+// NodeSimulation configures the platform a node presents itself as
+type NodeSimulation struct {
+	// Preset fills in the fields below for a well known platform, one of
+	// "windows" or "arm64". Fields set explicitly take precedence.
+	//
+	// "windows" sets OS to "windows", Arch to "amd64" and taints the node
+	// with "os=windows:NoSchedule".
+	// "arm64" sets Arch to "arm64" and taints the node with
+	// "kubernetes.io/arch=arm64:NoSchedule".
+	Preset SimulationPreset `yaml:"preset,omitempty" json:"preset,omitempty"`
+	// OS is reported in the node's kubernetes.io/os label, e.g. "windows"
+	OS string `yaml:"os,omitempty" json:"os,omitempty"`
+	// Arch is reported in the node's kubernetes.io/arch label, e.g. "arm64"
+	Arch string `yaml:"arch,omitempty" json:"arch,omitempty"`
+	// Taints are registered with the node, in the form key[=value]:effect,
+	// e.g. "os=windows:NoSchedule"
+	Taints []string `yaml:"taints,omitempty" json:"taints,omitempty"`
+}
+
+// SimulationPreset is a well known platform for NodeSimulation
+type SimulationPreset string
+
+const (
+	// SimulationPresetWindows presents the node as a windows/amd64 node
+	SimulationPresetWindows SimulationPreset = "windows"
+	// SimulationPresetARM64 presents the node as a linux/arm64 node
+	SimulationPresetARM64 SimulationPreset = "arm64"
+)
+
+// NodeLogging configures the logging of a node's components
+type NodeLogging struct {
+	// KubeletVerbosity is the kubelet's log verbosity (--v), from 0 to 10.
+	// Defaults to the kubelet's default of 0
+	KubeletVerbosity *int32 `yaml:"kubeletVerbosity,omitempty" json:"kubeletVerbosity,omitempty"`
+
+	// ContainerdLogLevel is containerd's log level (--log-level), one of
+	// "trace", "debug", "info", "warn" or "error". Defaults to "info"
+	ContainerdLogLevel string `yaml:"containerdLogLevel,omitempty" json:"containerdLogLevel,omitempty"`
+
+	// JournalRateLimitInterval and JournalRateLimitBurst are the rate limit
+	// of the node's journal, each service may log JournalRateLimitBurst
+	// messages per JournalRateLimitInterval, e.g. "30s", further messages
+	// are dropped. Set either to zero to disable rate limiting.
+	// Default to journald's defaults of 10000 messages per 30s
+	JournalRateLimitInterval string `yaml:"journalRateLimitInterval,omitempty" json:"journalRateLimitInterval,omitempty"`
+	JournalRateLimitBurst    *int32 `yaml:"journalRateLimitBurst,omitempty" json:"journalRateLimitBurst,omitempty"`
+}
+
+// Nesting configures a node for running nested kind clusters
+type Nesting struct {
+	// DevMount bind mounts the host's /dev into the node, so that devices
+	// created after the node started, such as loop devices, are available
+	// to nested clusters
+	DevMount bool `yaml:"devMount,omitempty" json:"devMount,omitempty"`
+	// CgroupNS is the cgroup namespace mode of the node container, one of
+	// "private" or "host". Defaults to "private", "host" may be needed by
+	// nested clusters on cgroup v1 hosts
+	CgroupNS CgroupNSMode `yaml:"cgroupNS,omitempty" json:"cgroupNS,omitempty"`
+}
+
+// ContainerdSnapshotter is a containerd snapshotter supported by the node image
+type ContainerdSnapshotter string
+
+const (
+	// OverlayfsSnapshotter uses the kernel's overlayfs
+	OverlayfsSnapshotter ContainerdSnapshotter = "overlayfs"
+	// FuseOverlayfsSnapshotter uses overlayfs in userspace, for when the
+	// kernel's overlayfs is unavailable
+	FuseOverlayfsSnapshotter ContainerdSnapshotter = "fuse-overlayfs"
+	// NativeSnapshotter copies each layer, and works on any filesystem
+	NativeSnapshotter ContainerdSnapshotter = "native"
+	// StargzSnapshotter lazily pulls eStargz images, starting containers
+	// before their images are fully pulled
+	StargzSnapshotter ContainerdSnapshotter = "stargz"
+)
+
+// SwapBehavior is the kubelet's swap behavior
+type SwapBehavior string
+
+const (
+	// NoSwapBehavior does not let workloads use swap
+	NoSwapBehavior SwapBehavior = "NoSwap"
+	// LimitedSwapBehavior lets Burstable pods use swap in proportion to
+	// their memory requests
+	LimitedSwapBehavior SwapBehavior = "LimitedSwap"
+)
+
+// CgroupNSMode is the cgroup namespace mode of a node container
+type CgroupNSMode string
+
+const (
+	// CgroupNSPrivate runs the node in its own cgroup namespace
+	CgroupNSPrivate CgroupNSMode = "private"
+	// CgroupNSHost runs the node in the host's cgroup namespace
+	CgroupNSHost CgroupNSMode = "host"
+)
+
+// StaticPod is a static pod manifest installed on a node
+type StaticPod struct {
+	// Name is the manifest file name without the ".yaml" suffix
+	Name string `yaml:"name" json:"name"`
+	// Manifest is the Pod manifest
+	Manifest string `yaml:"manifest" json:"manifest"`
+}
+
+// SystemdUnit is a systemd unit installed on a node
+type SystemdUnit struct {
+	// Name is the unit file name including its type suffix, e.g. "ntp.service"
+	Name string `yaml:"name" json:"name"`
+	// Content is the content of the unit file
+	Content string `yaml:"content" json:"content"`
+	// Enabled enables and starts the unit once it is installed
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 }
 
 // NodeRole defines possible role for nodes in a Kubernetes cluster managed by `kind`
@@ -160,6 +527,243 @@ const (
 	WorkerRole NodeRole = "worker"
 )
 
+// Addons contains settings for optional addons installed by kind
+type Addons struct {
+	// MetricsServer installs a metrics-server configured for kind after the
+	// CNI, so that `kubectl top` and the HorizontalPodAutoscaler work.
+	// The metrics-server image is pulled when the cluster is created.
+	//
+	// Defaults to false
+	MetricsServer bool `yaml:"metricsServer,omitempty" json:"metricsServer,omitempty"`
+
+	// DefaultStorage selects the default storage provisioner installed by kind,
+	// use "none" when deploying your own CSI driver.
+	//
+	// Defaults to "local-path"
+	DefaultStorage DefaultStorage `yaml:"defaultStorage,omitempty" json:"defaultStorage,omitempty"`
+
+	// SharedStorage configures an additional "shared" StorageClass whose
+	// volumes support ReadWriteMany across all nodes.
+	SharedStorage SharedStorage `yaml:"sharedStorage,omitempty" json:"sharedStorage,omitempty"`
+
+	// LoadBalancerPool reserves addresses on the node network for
+	// LoadBalancer services, and optionally installs MetalLB to assign them.
+	LoadBalancerPool LoadBalancerPool `yaml:"loadBalancerPool,omitempty" json:"loadBalancerPool,omitempty"`
+}
+
+// LoadBalancerPool reserves addresses on the node network for LoadBalancer
+// services
+type LoadBalancerPool struct {
+	// Size is the number of addresses to reserve in the node network's subnet
+	// for each of the cluster's IP families. They are taken from the end of
+	// the subnet, skipping the nodes' addresses and the pools of other kind
+	// clusters on the same network, while the container runtime assigns
+	// node addresses from the start of the subnet.
+	//
+	// No addresses are reserved when unset
+	Size int32 `yaml:"size,omitempty" json:"size,omitempty"`
+
+	// MetalLB installs MetalLB in L2 mode, assigning the reserved addresses
+	// to LoadBalancer services. The addresses are reachable from the host
+	// on Linux, but not with Docker Desktop.
+	//
+	// Defaults to false
+	MetalLB bool `yaml:"metalLB,omitempty" json:"metalLB,omitempty"`
+}
+
+// SharedStorage configures storage shared between all nodes
+type SharedStorage struct {
+	// HostPath is a directory on the host mounted into every node, volumes
+	// provisioned from the "shared" StorageClass are subdirectories of it.
+	// The directory must already exist.
+	//
+	// Shared storage is disabled when unset
+	HostPath string `yaml:"hostPath,omitempty" json:"hostPath,omitempty"`
+}
+
+// DefaultStorage selects a default storage provisioner
+type DefaultStorage string
+
+const (
+	// NoneDefaultStorage installs no storage provisioner or default StorageClass
+	NoneDefaultStorage DefaultStorage = "none"
+	// LocalPathDefaultStorage installs the local-path-provisioner shipped in
+	// the node image
+	LocalPathDefaultStorage DefaultStorage = "local-path"
+	// CSIHostPathDefaultStorage installs the CSI hostpath driver, which also
+	// supports volume snapshots
+	CSIHostPathDefaultStorage DefaultStorage = "csi-hostpath"
+)
+
+// Certificates contains settings for the cluster CA and certificates
+type Certificates struct {
+	// CACertFile and CAKeyFile are paths on the host to a CA certificate and
+	// key to use as the cluster CA, instead of kubeadm generating a new one.
+	// Both must be set or neither. This allows recreating a cluster with
+	// the same CA.
+	CACertFile string `yaml:"caCertFile,omitempty" json:"caCertFile,omitempty"`
+	CAKeyFile  string `yaml:"caKeyFile,omitempty" json:"caKeyFile,omitempty"`
+
+	// ExportCADir is a directory on the host the cluster CA certificate and
+	// key are written to after the control plane is created, as ca.crt and
+	// ca.key, for use with CACertFile and CAKeyFile
+	ExportCADir string `yaml:"exportCADir,omitempty" json:"exportCADir,omitempty"`
+
+	// CertificateValidityPeriod is the validity of the certificates kubeadm
+	// signs with the cluster CA, e.g. "2h" for testing rotation.
+	// This requires Kubernetes v1.31+. Defaults to the kubeadm default.
+	CertificateValidityPeriod string `yaml:"certificateValidityPeriod,omitempty" json:"certificateValidityPeriod,omitempty"`
+
+	// CACertificateValidityPeriod is the validity of the CA certificates
+	// kubeadm generates, it has no effect with CACertFile.
+	// This requires Kubernetes v1.31+. Defaults to the kubeadm default.
+	CACertificateValidityPeriod string `yaml:"caCertificateValidityPeriod,omitempty" json:"caCertificateValidityPeriod,omitempty"`
+}
+
+// EncryptionAtRest configures encryption at rest of API resources.
+// kind generates an EncryptionConfiguration, writes it to the control plane
+// nodes at /etc/kubernetes/encryption/config.yaml and passes it to the
+// kube-apiserver with --encryption-provider-config. The identity provider is
+// always listed last, so resources written before a provider was added can
+// still be read, e.g. to exercise migrating them.
+//
+// On Kubernetes v1.26+ the kube-apiserver reloads the configuration when it
+// changes, so keys can be rotated by editing the file on every control plane
+// node.
+//
+// https://kubernetes.io/docs/tasks/administer-cluster/encrypt-data/
+type EncryptionAtRest struct {
+	// Provider is the encryption provider, one of "aescbc", "aesgcm",
+	// "secretbox" or "kms". For all but kms kind generates a random key.
+	// For kms the kube-apiserver uses a KMS v2 plugin listening on
+	// KMSEndpoint, which must be running when the control plane starts,
+	// e.g. as a static pod, this requires Kubernetes v1.29+.
+	//
+	// Unset disables encryption at rest
+	Provider EncryptionProvider `yaml:"provider,omitempty" json:"provider,omitempty"`
+
+	// Resources are the resources to encrypt, e.g. "secrets" or
+	// "configmaps". Defaults to ["secrets"]
+	Resources []string `yaml:"resources,omitempty" json:"resources,omitempty"`
+
+	// KMSEndpoint is the endpoint of the KMS v2 plugin for the kms provider.
+	// Defaults to "unix:///var/run/kmsplugin/socket.sock"
+	KMSEndpoint string `yaml:"kmsEndpoint,omitempty" json:"kmsEndpoint,omitempty"`
+}
+
+// EncryptionProvider is an encryption at rest provider
+type EncryptionProvider string
+
+const (
+	// AESCBCEncryptionProvider encrypts with AES-CBC and a generated key
+	AESCBCEncryptionProvider EncryptionProvider = "aescbc"
+	// AESGCMEncryptionProvider encrypts with AES-GCM and a generated key
+	AESGCMEncryptionProvider EncryptionProvider = "aesgcm"
+	// SecretboxEncryptionProvider encrypts with XSalsa20 and Poly1305 and a
+	// generated key
+	SecretboxEncryptionProvider EncryptionProvider = "secretbox"
+	// KMSEncryptionProvider encrypts with a KMS v2 plugin
+	KMSEncryptionProvider EncryptionProvider = "kms"
+)
+
+// PodSecurity configures the cluster-wide defaults of the PodSecurity
+// admission controller, which apply to namespaces without
+// pod-security.kubernetes.io labels.
+// kind generates an AdmissionConfiguration, writes it to the control plane
+// nodes at /etc/kubernetes/admission/config.yaml and passes it to the
+// kube-apiserver with --admission-control-config-file.
+// This requires Kubernetes v1.23+.
+//
+// The namespaces of the components kind installs, e.g. kube-system, are
+// always exempt.
+//
+// https://kubernetes.io/docs/concepts/security/pod-security-admission/
+type PodSecurity struct {
+	// Enforce is the level pods that violate it are rejected for, one of
+	// "privileged", "baseline" or "restricted". Defaults to "privileged"
+	Enforce PodSecurityLevel `yaml:"enforce,omitempty" json:"enforce,omitempty"`
+
+	// Audit is the level violations of which are recorded in the audit log.
+	// Defaults to "privileged"
+	Audit PodSecurityLevel `yaml:"audit,omitempty" json:"audit,omitempty"`
+
+	// Warn is the level violations of which are returned to the client as
+	// warnings. Defaults to "privileged"
+	Warn PodSecurityLevel `yaml:"warn,omitempty" json:"warn,omitempty"`
+
+	// Exemptions are exempt from all of the levels
+	Exemptions PodSecurityExemptions `yaml:"exemptions,omitempty" json:"exemptions,omitempty"`
+}
+
+// PodSecurityLevel is a Pod Security Standards level
+type PodSecurityLevel string
+
+const (
+	// PrivilegedPodSecurityLevel is unrestricted
+	PrivilegedPodSecurityLevel PodSecurityLevel = "privileged"
+	// BaselinePodSecurityLevel prevents known privilege escalations
+	BaselinePodSecurityLevel PodSecurityLevel = "baseline"
+	// RestrictedPodSecurityLevel follows pod hardening best practices
+	RestrictedPodSecurityLevel PodSecurityLevel = "restricted"
+)
+
+// PodSecurityExemptions are exempt from the PodSecurity admission controller
+type PodSecurityExemptions struct {
+	// Usernames are authenticated users whose requests are exempt
+	Usernames []string `yaml:"usernames,omitempty" json:"usernames,omitempty"`
+
+	// RuntimeClasses are runtime class names whose pods are exempt
+	RuntimeClasses []string `yaml:"runtimeClasses,omitempty" json:"runtimeClasses,omitempty"`
+
+	// Namespaces are namespaces whose pods are exempt
+	Namespaces []string `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+}
+
+// ComponentImages contains images replacing those of the control plane
+// components in the static pod manifests kubeadm generates, unset fields
+// keep the images in the node image
+type ComponentImages struct {
+	// KubeAPIServer is the kube-apiserver image
+	KubeAPIServer string `yaml:"kubeAPIServer,omitempty" json:"kubeAPIServer,omitempty"`
+	// KubeControllerManager is the kube-controller-manager image
+	KubeControllerManager string `yaml:"kubeControllerManager,omitempty" json:"kubeControllerManager,omitempty"`
+	// KubeScheduler is the kube-scheduler image
+	KubeScheduler string `yaml:"kubeScheduler,omitempty" json:"kubeScheduler,omitempty"`
+}
+
+// Timeouts contains timeouts and retries for the phases of cluster creation.
+// Timeouts are durations such as "5m", unset timeouts are unlimited.
+type Timeouts struct {
+	// ImagePull is the timeout for each attempt at pulling a node image
+	ImagePull string `yaml:"imagePull,omitempty" json:"imagePull,omitempty"`
+
+	// ImagePullRetries is how many times a failed node image pull is retried
+	// Defaults to 4
+	ImagePullRetries *int32 `yaml:"imagePullRetries,omitempty" json:"imagePullRetries,omitempty"`
+
+	// ImagePullBackoff is the delay before retrying a failed node image pull,
+	// each further retry waits this much longer than the last
+	// Defaults to "1s"
+	ImagePullBackoff string `yaml:"imagePullBackoff,omitempty" json:"imagePullBackoff,omitempty"`
+
+	// ControlPlaneInit is the timeout for `kubeadm init` on the first
+	// control plane node
+	ControlPlaneInit string `yaml:"controlPlaneInit,omitempty" json:"controlPlaneInit,omitempty"`
+
+	// Join is the timeout for `kubeadm join` on each of the other nodes
+	Join string `yaml:"join,omitempty" json:"join,omitempty"`
+
+	// CNIReady is how long to wait for the default CNI to be ready on the
+	// control plane nodes, before joining the other nodes.
+	// Defaults to not waiting.
+	CNIReady string `yaml:"cniReady,omitempty" json:"cniReady,omitempty"`
+
+	// NodesReady is how long to wait for the control plane nodes to be
+	// Ready at the end of cluster creation, the --wait flag overrides this.
+	// Defaults to not waiting.
+	NodesReady string `yaml:"nodesReady,omitempty" json:"nodesReady,omitempty"`
+}
+
 // Networking contains cluster wide network settings
 type Networking struct {
 	// IPFamily is the network cluster model, currently it can be ipv4 or ipv6
@@ -172,25 +776,120 @@ type Networking struct {
 	// This is potentially useful for remote hosts, BUT it means when the container
 	// is restarted it will be randomized. Leave this unset to allow kind to pick it.
 	APIServerPort int32 `yaml:"apiServerPort,omitempty" json:"apiServerPort,omitempty"`
+	// APIServerPortRange restricts the host port kind picks for the
+	// Kubernetes API Server when APIServerPort is unset to a range such as
+	// "30000-30100", e.g. for hosts whose firewall only allows some ports.
+	// It cannot be used together with APIServerPort.
+	APIServerPortRange string `yaml:"apiServerPortRange,omitempty" json:"apiServerPortRange,omitempty"`
 	// APIServerAddress is the listen address on the host for the Kubernetes
 	// API Server. This should be an IP address.
 	//
 	// Defaults to 127.0.0.1
 	APIServerAddress string `yaml:"apiServerAddress,omitempty" json:"apiServerAddress,omitempty"`
+	// APIServerAdditionalAddresses are additional listen addresses on the host
+	// for the Kubernetes API Server, e.g. a LAN IP in addition to 127.0.0.1.
+	// These should be IP addresses, and they are added to the API Server
+	// certificate SANs unless they are unspecified addresses (0.0.0.0 / ::).
+	//
+	// The API Server is published on the same port on every address, so
+	// APIServerPort must be set when using this field.
+	APIServerAdditionalAddresses []string `yaml:"apiServerAdditionalAddresses,omitempty" json:"apiServerAdditionalAddresses,omitempty"`
+	// APIServerCertSANs are additional Subject Alternative Names (IP addresses
+	// or DNS names) for the Kubernetes API Server serving certificate
+	APIServerCertSANs []string `yaml:"apiServerCertSANs,omitempty" json:"apiServerCertSANs,omitempty"`
 	// PodSubnet is the CIDR used for pod IPs
 	// kind will select a default if unspecified
 	PodSubnet string `yaml:"podSubnet,omitempty" json:"podSubnet,omitempty"`
 	// ServiceSubnet is the CIDR used for services VIPs
 	// kind will select a default if unspecified for IPv6
 	ServiceSubnet string `yaml:"serviceSubnet,omitempty" json:"serviceSubnet,omitempty"`
+	// NodeCIDRMaskSizeIPv4 is the mask size of the IPv4 pod CIDR allocated
+	// to each node from the PodSubnet.
+	//
+	// Defaults to the kube-controller-manager default of 24
+	NodeCIDRMaskSizeIPv4 int32 `yaml:"nodeCIDRMaskSizeIPv4,omitempty" json:"nodeCIDRMaskSizeIPv4,omitempty"`
+	// NodeCIDRMaskSizeIPv6 is the mask size of the IPv6 pod CIDR allocated
+	// to each node from the PodSubnet.
+	//
+	// Defaults to the kube-controller-manager default of 64
+	NodeCIDRMaskSizeIPv6 int32 `yaml:"nodeCIDRMaskSizeIPv6,omitempty" json:"nodeCIDRMaskSizeIPv6,omitempty"`
 	// If DisableDefaultCNI is true, kind will not install the default CNI setup.
 	// Instead the user should install their own CNI after creating the cluster.
 	DisableDefaultCNI bool `yaml:"disableDefaultCNI,omitempty" json:"disableDefaultCNI,omitempty"`
+	// If KeepPortmap is true with DisableDefaultCNI, kind keeps hostPort
+	// support working by chaining the portmap CNI plugin into any CNI config
+	// installed on the nodes that does not already include it.
+	// This is useful for CNIs that do not handle hostPorts themselves.
+	KeepPortmap bool `yaml:"keepPortmap,omitempty" json:"keepPortmap,omitempty"`
+	// If HostRoutes is true, kind adds routes on the host to each node's
+	// pod CIDRs via the node, so that pods are reachable from the host.
+	// This is mostly useful for IPv6 clusters, and uses a privileged helper
+	// container in the host network namespace. Rootless providers are not
+	// supported.
+	HostRoutes bool `yaml:"hostRoutes,omitempty" json:"hostRoutes,omitempty"`
 	// KubeProxyMode defines if kube-proxy should operate in iptables, ipvs or nftables mode
 	// Defaults to 'iptables' mode
 	KubeProxyMode ProxyMode `yaml:"kubeProxyMode,omitempty" json:"kubeProxyMode,omitempty"`
 	// DNSSearch defines the DNS search domain to use for nodes. If not set, this will be inherited from the host.
 	DNSSearch *[]string `yaml:"dnsSearch,omitempty" json:"dnsSearch,omitempty"`
+	// BridgedNetwork additionally attaches the nodes to a macvlan or ipvlan
+	// network on a host interface, so that they get addresses on the host's
+	// LAN. This is useful for testing external load balancers, BGP and
+	// on-prem like topologies. Only Linux hosts with rootful docker are
+	// supported.
+	BridgedNetwork *BridgedNetwork `yaml:"bridgedNetwork,omitempty" json:"bridgedNetwork,omitempty"`
+	// AdditionalNetworks are networks kind creates for the cluster, in
+	// addition to the network all nodes are attached to. Nodes are attached
+	// to them with their additionalNetworks, e.g. to test Multus and other
+	// multi-homing CNIs.
+	AdditionalNetworks []AdditionalNetwork `yaml:"additionalNetworks,omitempty" json:"additionalNetworks,omitempty"`
+}
+
+// BridgedNetwork configures a macvlan or ipvlan network on a host interface
+type BridgedNetwork struct {
+	// Driver is the network driver, macvlan or ipvlan
+	//
+	// Defaults to macvlan
+	Driver BridgedNetworkDriver `yaml:"driver,omitempty" json:"driver,omitempty"`
+	// Parent is the host interface to attach the network to, e.g. eth0
+	Parent string `yaml:"parent,omitempty" json:"parent,omitempty"`
+	// Subnet is the CIDR of the network on the parent interface's LAN,
+	// e.g. 192.168.1.0/24
+	Subnet string `yaml:"subnet,omitempty" json:"subnet,omitempty"`
+	// IPRange is the CIDR within Subnet to allocate node addresses from.
+	// This should not overlap any addresses assigned by the LAN's DHCP
+	// server.
+	//
+	// Defaults to all of Subnet
+	IPRange string `yaml:"ipRange,omitempty" json:"ipRange,omitempty"`
+	// Gateway is the LAN's gateway address within Subnet
+	//
+	// Defaults to the first address in Subnet
+	Gateway string `yaml:"gateway,omitempty" json:"gateway,omitempty"`
+}
+
+// BridgedNetworkDriver is the driver of a BridgedNetwork
+type BridgedNetworkDriver string
+
+const (
+	// MacvlanDriver gives every node its own MAC address on the LAN
+	MacvlanDriver BridgedNetworkDriver = "macvlan"
+	// IPVlanDriver shares the parent interface's MAC address, for LANs
+	// that limit the MAC addresses per port such as most wireless networks
+	IPVlanDriver BridgedNetworkDriver = "ipvlan"
+)
+
+// AdditionalNetwork is a network kind creates for the cluster, see
+// Networking.AdditionalNetworks
+type AdditionalNetwork struct {
+	// Name identifies the network in a node's additionalNetworks. This is
+	// also the default name of the node interfaces on the network, so it
+	// must be a valid interface name of at most 15 characters.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Subnet is the network's CIDR
+	//
+	// Defaults to a subnet picked by the node backend (docker)
+	Subnet string `yaml:"subnet,omitempty" json:"subnet,omitempty"`
 }
 
 // ClusterIPFamily defines cluster network IP family
@@ -228,6 +927,66 @@ type PatchJSON6902 struct {
 	Patch string `yaml:"patch" json:"patch"`
 }
 
+// KubeadmPatch represents a single kubeadm component patch
+// In yaml this looks like:
+//
+//	target: kube-apiserver
+//	patchType: strategic
+//	patch: |
+//	  spec:
+//	    containers:
+//	    - name: kube-apiserver
+//	      resources:
+//	        requests:
+//	          cpu: 100m
+//
+// Exactly one of patch or path must be set.
+type KubeadmPatch struct {
+	// Target is the component to patch, one of: etcd, kube-apiserver,
+	// kube-controller-manager, kube-scheduler, kubeletconfiguration
+	Target KubeadmPatchTarget `yaml:"target" json:"target"`
+	// PatchType is the type of patch, one of: strategic, merge, json
+	//
+	// Defaults to strategic
+	PatchType KubeadmPatchType `yaml:"patchType,omitempty" json:"patchType,omitempty"`
+	// Patch is the inline contents of the patch
+	Patch string `yaml:"patch,omitempty" json:"patch,omitempty"`
+	// Path is the path to a file on the host containing the patch, relative
+	// paths are relative to the config file
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// KubeadmPatchTarget represents an "enum" for kubeadm patch targets,
+// see also KubeadmPatch.
+type KubeadmPatchTarget string
+
+const (
+	// KubeadmPatchTargetEtcd targets the etcd static pod manifest
+	KubeadmPatchTargetEtcd KubeadmPatchTarget = "etcd"
+	// KubeadmPatchTargetKubeAPIServer targets the kube-apiserver static pod manifest
+	KubeadmPatchTargetKubeAPIServer KubeadmPatchTarget = "kube-apiserver"
+	// KubeadmPatchTargetKubeControllerManager targets the kube-controller-manager static pod manifest
+	KubeadmPatchTargetKubeControllerManager KubeadmPatchTarget = "kube-controller-manager"
+	// KubeadmPatchTargetKubeScheduler targets the kube-scheduler static pod manifest
+	KubeadmPatchTargetKubeScheduler KubeadmPatchTarget = "kube-scheduler"
+	// KubeadmPatchTargetKubeletConfiguration targets the KubeletConfiguration,
+	// this requires Kubernetes v1.25 or newer
+	KubeadmPatchTargetKubeletConfiguration KubeadmPatchTarget = "kubeletconfiguration"
+)
+
+// KubeadmPatchType represents an "enum" for kubeadm patch types,
+// see also KubeadmPatch.
+type KubeadmPatchType string
+
+const (
+	// KubeadmPatchTypeStrategic specifies a strategic merge patch
+	KubeadmPatchTypeStrategic KubeadmPatchType = "strategic"
+	// KubeadmPatchTypeMerge specifies a JSON merge patch (RFC 7386)
+	KubeadmPatchTypeMerge KubeadmPatchType = "merge"
+	// KubeadmPatchTypeJSON specifies a JSON patch (RFC 6902)
+	KubeadmPatchTypeJSON KubeadmPatchType = "json"
+)
+
 /*
 These types are from
 https://github.com/kubernetes/kubernetes/blob/063e7ff358fdc8b0916e6f39beedc0d025734cb1/pkg/kubelet/apis/cri/runtime/v1alpha2/api.pb.go#L183
@@ -244,10 +1003,13 @@ https://github.com/kubernetes/kubernetes/blob/063e7ff358fdc8b0916e6f39beedc0d025
 //	containerPath: /foo
 //	hostPath: /bar
 //	readOnly: true
-//	selinuxRelabel: false
+//	selinuxRelabel: true
+//	selinuxRelabelMode: Private
 //	propagation: None
+//	createHostPath: true
 //
 // Propagation may be one of: None, HostToContainer, Bidirectional
+// SelinuxRelabelMode may be one of: Shared, Private
 type Mount struct {
 	// Path of the mount within the container.
 	ContainerPath string `yaml:"containerPath,omitempty" json:"containerPath,omitempty"`
@@ -259,8 +1021,14 @@ type Mount struct {
 	Readonly bool `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
 	// If set, the mount needs SELinux relabeling.
 	SelinuxRelabel bool `yaml:"selinuxRelabel,omitempty" json:"selinuxRelabel,omitempty"`
+	// SelinuxRelabelMode selects how the mount is relabeled if SelinuxRelabel
+	// is set, see SelinuxRelabelMode. Defaults to Private.
+	SelinuxRelabelMode SelinuxRelabelMode `yaml:"selinuxRelabelMode,omitempty" json:"selinuxRelabelMode,omitempty"`
 	// Requested propagation mode.
 	Propagation MountPropagation `yaml:"propagation,omitempty" json:"propagation,omitempty"`
+	// CreateHostPath creates the hostPath directory if it doesn't exist,
+	// otherwise a missing hostPath is an error when creating the cluster.
+	CreateHostPath bool `yaml:"createHostPath,omitempty" json:"createHostPath,omitempty"`
 }
 
 // PortMapping specifies a host port mapped into a container port.
@@ -305,6 +1073,19 @@ const (
 	MountPropagationBidirectional MountPropagation = "Bidirectional"
 )
 
+// SelinuxRelabelMode represents an "enum" for SELinux relabeling options,
+// see also Mount.
+type SelinuxRelabelMode string
+
+const (
+	// SelinuxRelabelModeShared relabels the mount so that it can be shared
+	// with other containers ("z" in docker / podman).
+	SelinuxRelabelModeShared SelinuxRelabelMode = "Shared"
+	// SelinuxRelabelModePrivate relabels the mount so that only the node
+	// container can use it ("Z" in docker / podman).
+	SelinuxRelabelModePrivate SelinuxRelabelMode = "Private"
+)
+
 // PortMappingProtocol represents an "enum" for port mapping protocol options,
 // see also PortMapping.
 type PortMappingProtocol string
@@ -317,3 +1098,13 @@ const (
 	// PortMappingProtocolSCTP specifies SCTP protocol
 	PortMappingProtocolSCTP PortMappingProtocol = "SCTP"
 )
+
+// NodeNetwork attaches a node to one of the cluster's AdditionalNetworks
+type NodeNetwork struct {
+	// Name is the name of the network in networking.additionalNetworks
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Interface is the name of the node's interface on the network
+	//
+	// Defaults to the network's name
+	Interface string `yaml:"interface,omitempty" json:"interface,omitempty"`
+}
@@ -44,6 +44,14 @@ func (m *Mount) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	default:
 		return errors.Errorf("Unknown MountPropagation: %q", a.Propagation)
 	}
+	// and the SELinux relabeling mode
+	switch a.SelinuxRelabelMode {
+	case "": // unset, private if relabeling
+	case SelinuxRelabelModeShared:
+	case SelinuxRelabelModePrivate:
+	default:
+		return errors.Errorf("Unknown SelinuxRelabelMode: %q", a.SelinuxRelabelMode)
+	}
 	// and copy over the fields
 	*m = Mount(a)
 	return nil
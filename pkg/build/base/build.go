@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// buildContext is used to build the kind base image, and contains
+// build configuration
+type buildContext struct {
+	context   context.Context
+	logger    log.Logger
+	image     string
+	source    string
+	goVersion string
+	platforms []string
+	builder   string
+	buildArgs map[string]string
+	push      bool
+}
+
+// Build builds the base image using the supplied options
+func Build(options ...Option) error {
+	return BuildContext(context.Background(), options...)
+}
+
+// BuildContext is like Build, but the build is stopped once ctx is cancelled
+func BuildContext(cancelCtx context.Context, options ...Option) error {
+	// default options
+	ctx := &buildContext{
+		context:   cancelCtx,
+		logger:    log.NoopLogger{},
+		image:     DefaultImage,
+		source:    DefaultSource,
+		buildArgs: map[string]string{},
+	}
+
+	// apply user options
+	for _, option := range options {
+		if err := option.apply(ctx); err != nil {
+			return err
+		}
+	}
+
+	// the docker server's platform can always be built without emulation
+	nativePlatform, err := dockerPlatform()
+	if err != nil {
+		return err
+	}
+	if len(ctx.platforms) == 0 {
+		ctx.platforms = []string{nativePlatform}
+	}
+	if err := validatePlatforms(ctx.platforms); err != nil {
+		return err
+	}
+	if len(ctx.platforms) > 1 && !ctx.push {
+		return errors.New("building for more than one platform requires pushing the image")
+	}
+
+	if ctx.goVersion == "" {
+		goVersion, err := readGoVersion(ctx.source)
+		if err != nil {
+			return err
+		}
+		ctx.goVersion = goVersion
+	}
+
+	// make sure the builder can produce every platform before starting a
+	// build that would otherwise fail part way through
+	if err := ctx.checkBuilder(nativePlatform); err != nil {
+		return err
+	}
+
+	return ctx.build()
+}
+
+func (c *buildContext) build() error {
+	c.logger.V(0).Infof("Building %q for %s ...", c.image, strings.Join(c.platforms, ","))
+	cmd := exec.CommandContext(c.context, "docker", c.buildArgv()...)
+	exec.InheritOutput(cmd)
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "failed to build base image")
+	}
+	c.logger.V(0).Infof("Image %q build completed.", c.image)
+	return nil
+}
+
+// buildArgv returns the docker arguments to build the image, equivalent to
+// the images/base Makefile
+func (c *buildContext) buildArgv() []string {
+	args := []string{"buildx"}
+	if c.builder != "" {
+		args = append(args, "--builder", c.builder)
+	}
+	args = append(args,
+		"build",
+		"--platform="+strings.Join(c.platforms, ","),
+	)
+	if c.push {
+		args = append(args, "--push")
+	} else {
+		args = append(args, "--load")
+	}
+	args = append(args,
+		"--progress=auto",
+		"-t", c.image,
+		"--pull",
+		"--build-arg", "GO_VERSION="+c.goVersion,
+	)
+	// sort build args so the command line is stable
+	names := make([]string, 0, len(c.buildArgs))
+	for name := range c.buildArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--build-arg", name+"="+c.buildArgs[name])
+	}
+	return append(args, c.source)
+}
+
+// validatePlatforms returns an error if any of platforms is not supported
+func validatePlatforms(platforms []string) error {
+	for _, platform := range platforms {
+		supported := false
+		for _, p := range SupportedPlatforms {
+			if platform == p {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return errors.Errorf(
+				"unsupported platform %q, supported platforms are: %s",
+				platform, strings.Join(SupportedPlatforms, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// readGoVersion reads the .go-version file at the root of the repo
+// containing source
+func readGoVersion(source string) (string, error) {
+	path := filepath.Join(source, "..", "..", ".go-version")
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read Go version, set it explicitly or build from a kind repo")
+	}
+	return strings.TrimSpace(string(contents)), nil
+}
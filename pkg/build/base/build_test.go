@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func Test_validatePlatforms(t *testing.T) {
+	t.Parallel()
+	assert.ExpectError(t, false, validatePlatforms([]string{"linux/amd64", "linux/arm64", "linux/s390x"}))
+	assert.ExpectError(t, true, validatePlatforms([]string{"linux/ppc64le"}))
+}
+
+func Test_buildArgv(t *testing.T) {
+	t.Parallel()
+	c := &buildContext{
+		image:     "example.com/base:s390x",
+		source:    "images/base",
+		goVersion: "1.23.4",
+		platforms: []string{"linux/arm64", "linux/s390x"},
+		builder:   "kind-builder",
+		buildArgs: map[string]string{
+			"RUNC_VERSION":       "v1.2.3",
+			"CONTAINERD_VERSION": "v2.0.2",
+		},
+		push: true,
+	}
+	assert.DeepEqual(t, []string{
+		"buildx", "--builder", "kind-builder", "build",
+		"--platform=linux/arm64,linux/s390x", "--push",
+		"--progress=auto", "-t", "example.com/base:s390x", "--pull",
+		"--build-arg", "GO_VERSION=1.23.4",
+		"--build-arg", "CONTAINERD_VERSION=v2.0.2",
+		"--build-arg", "RUNC_VERSION=v1.2.3",
+		"images/base",
+	}, c.buildArgv())
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// binfmtImage is the image used by hack/build/init-buildx.sh to install
+// qemu emulators for foreign platforms
+const binfmtImage = "tonistiigi/binfmt:qemu-v7.0.0-28@sha256:66e11bea77a5ea9d6f0fe79b57cd2b189b5d15b93a2bdb925be22949232e4e55"
+
+// builderInfo is the subset of `docker buildx inspect` we need
+type builderInfo struct {
+	driver    string
+	platforms []string
+}
+
+// checkBuilder verifies that the buildx builder can build every requested
+// platform, which for foreign platforms requires qemu emulation
+func (c *buildContext) checkBuilder(nativePlatform string) error {
+	args := []string{"buildx"}
+	if c.builder != "" {
+		args = append(args, "--builder", c.builder)
+	}
+	args = append(args, "inspect", "--bootstrap")
+	lines, err := exec.OutputLines(exec.CommandContext(c.context, "docker", args...))
+	if err != nil {
+		return errors.Wrap(err, "failed to inspect buildx builder")
+	}
+	return checkPlatforms(parseBuilderInfo(lines), nativePlatform, c.platforms)
+}
+
+// checkPlatforms returns an error describing how to set up the builder if it
+// cannot build platforms
+func checkPlatforms(info builderInfo, nativePlatform string, platforms []string) error {
+	// the docker driver builds into the local image store, which cannot
+	// hold more than one platform of an image
+	if info.driver == "docker" && len(platforms) > 1 {
+		return errors.New(
+			"the buildx builder uses the docker driver, which cannot build for more than one platform; " +
+				"create a builder with hack/build/init-buildx.sh or `docker buildx create --use`",
+		)
+	}
+	var missing []string
+	for _, platform := range platforms {
+		if platform == nativePlatform {
+			continue
+		}
+		found := false
+		for _, p := range info.platforms {
+			if p == platform {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, platform)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf(
+			"the buildx builder cannot build for %s, install qemu emulation with `docker run --rm --privileged %s --install all`",
+			strings.Join(missing, ", "), binfmtImage,
+		)
+	}
+	return nil
+}
+
+// parseBuilderInfo parses the output of `docker buildx inspect`
+func parseBuilderInfo(lines []string) builderInfo {
+	info := builderInfo{}
+	for _, line := range lines {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "Driver":
+			if info.driver == "" {
+				info.driver = value
+			}
+		case "Platforms":
+			for _, platform := range strings.Split(value, ",") {
+				// preferred platforms are marked with a trailing *
+				platform = strings.TrimSuffix(strings.TrimSpace(platform), "*")
+				if platform != "" {
+					info.platforms = append(info.platforms, platform)
+				}
+			}
+		}
+	}
+	return info
+}
+
+// dockerPlatform returns the platform of the docker server
+func dockerPlatform() (string, error) {
+	lines, err := exec.OutputLines(exec.Command(
+		"docker", "version", "--format", "{{.Server.Os}}/{{.Server.Arch}}",
+	))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get docker server platform")
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("docker server platform should only be one line, got %d lines", len(lines))
+	}
+	return strings.TrimSpace(lines[0]), nil
+}
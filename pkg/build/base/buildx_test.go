@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func Test_parseBuilderInfo(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"Name:          kind-builder",
+		"Driver:        docker-container",
+		"Last Activity: 2024-12-12 00:00:00 +0000 UTC",
+		"",
+		"Nodes:",
+		"Name:      kind-builder0",
+		"Endpoint:  unix:///var/run/docker.sock",
+		"Status:    running",
+		"Platforms: linux/amd64*, linux/amd64/v2, linux/arm64, linux/s390x",
+	}
+	info := parseBuilderInfo(lines)
+	assert.StringEqual(t, "docker-container", info.driver)
+	assert.DeepEqual(t, []string{"linux/amd64", "linux/amd64/v2", "linux/arm64", "linux/s390x"}, info.platforms)
+}
+
+func Test_checkPlatforms(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Info        builderInfo
+		Platforms   []string
+		ExpectError bool
+	}{
+		{
+			Name:      "native platform without emulation",
+			Info:      builderInfo{driver: "docker", platforms: []string{"linux/amd64"}},
+			Platforms: []string{"linux/amd64"},
+		},
+		{
+			Name:      "foreign platforms with emulation",
+			Info:      builderInfo{driver: "docker-container", platforms: []string{"linux/amd64", "linux/arm64", "linux/s390x"}},
+			Platforms: []string{"linux/amd64", "linux/arm64", "linux/s390x"},
+		},
+		{
+			Name:        "foreign platform without emulation",
+			Info:        builderInfo{driver: "docker-container", platforms: []string{"linux/amd64", "linux/arm64"}},
+			Platforms:   []string{"linux/s390x"},
+			ExpectError: true,
+		},
+		{
+			Name:        "multiple platforms with the docker driver",
+			Info:        builderInfo{driver: "docker", platforms: []string{"linux/amd64", "linux/arm64"}},
+			Platforms:   []string{"linux/amd64", "linux/arm64"},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			err := checkPlatforms(tc.Info, "linux/amd64", tc.Platforms)
+			assert.ExpectError(t, tc.ExpectError, err)
+		})
+	}
+}
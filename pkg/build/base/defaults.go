@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+// DefaultImage is the default name:tag for the built image
+const DefaultImage = "kindest/base:latest"
+
+// DefaultSource is the default path to the base image sources, relative to
+// the root of the kind repo
+const DefaultSource = "images/base"
+
+// SupportedPlatforms are the platforms the base image can be built for
+var SupportedPlatforms = []string{
+	"linux/amd64",
+	"linux/arm64",
+	"linux/s390x",
+}
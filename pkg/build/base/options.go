@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// Option is a configuration option supplied to Build
+type Option interface {
+	apply(*buildContext) error
+}
+
+type optionAdapter func(*buildContext) error
+
+func (c optionAdapter) apply(o *buildContext) error {
+	return c(o)
+}
+
+// WithImage configures a build to tag the built image with `image`
+func WithImage(image string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.image = image
+		return nil
+	})
+}
+
+// WithSource sets the path to the base image sources, the images/base
+// directory of the kind repo or a fork of it
+func WithSource(source string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.source = source
+		return nil
+	})
+}
+
+// WithGoVersion sets the Go version used to build the base image's
+// components, by default this is read from the .go-version file at the root
+// of the repo containing the sources
+func WithGoVersion(version string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.goVersion = version
+		return nil
+	})
+}
+
+// WithPlatforms sets the platforms to build for, e.g. "linux/s390x",
+// by default only the docker server's platform is built
+func WithPlatforms(platforms []string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.platforms = platforms
+		return nil
+	})
+}
+
+// WithBuilder sets the buildx builder instance to build with, by default the
+// currently selected builder is used
+func WithBuilder(builder string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.builder = builder
+		return nil
+	})
+}
+
+// WithBuildArg sets a build argument of the Dockerfile, e.g.
+// "CONTAINERD_VERSION"
+func WithBuildArg(name, value string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.buildArgs[name] = value
+		return nil
+	})
+}
+
+// WithPush configures a build to push the built image to its registry
+// instead of loading it into docker, this is required to build for more
+// than one platform
+func WithPush(push bool) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.push = push
+		return nil
+	})
+}
+
+// WithLogger sets the logger
+func WithLogger(logger log.Logger) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.logger = logger
+		return nil
+	})
+}
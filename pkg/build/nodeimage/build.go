@@ -17,6 +17,7 @@ limitations under the License.
 package nodeimage
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
@@ -24,18 +25,30 @@ import (
 
 	"sigs.k8s.io/kind/pkg/build/nodeimage/internal/kube"
 	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/sets"
 	"sigs.k8s.io/kind/pkg/internal/version"
 	"sigs.k8s.io/kind/pkg/log"
 )
 
 // Build builds a node image using the supplied options
 func Build(options ...Option) error {
+	return BuildContext(context.Background(), options...)
+}
+
+// BuildContext is like Build, but the build stops between steps once ctx is
+// cancelled, and the build container is removed
+func BuildContext(cancelCtx context.Context, options ...Option) error {
 	// default options
 	ctx := &buildContext{
-		image:     DefaultImage,
-		baseImage: DefaultBaseImage,
-		logger:    log.NoopLogger{},
-		arch:      runtime.GOARCH,
+		context:     cancelCtx,
+		image:       DefaultImage,
+		baseImage:   DefaultBaseImage,
+		logger:      log.NoopLogger{},
+		arch:        runtime.GOARCH,
+		cri:         containerdRuntime,
+		crioVersion: DefaultCRIOVersion,
+		compression: "zstd",
+		debugTools:  DefaultDebugTools,
 	}
 
 	// apply user options
@@ -50,6 +63,38 @@ func Build(options ...Option) error {
 		ctx.logger.Warnf("unsupported architecture %q", ctx.arch)
 	}
 
+	// verify that we're using a supported container runtime
+	switch ctx.cri {
+	case containerdRuntime:
+		if ctx.crioVersion != DefaultCRIOVersion {
+			return errors.New("a CRI-O version can only be set for CRI-O node images")
+		}
+	case crioRuntime:
+		ctx.logger.Warn("CRI-O node images are experimental")
+		if ctx.wasmShims {
+			return errors.New("wasm shims are only supported with containerd")
+		}
+		if ctx.runtime.containerd != "" {
+			return errors.New("a containerd version cannot be set for CRI-O node images")
+		}
+	default:
+		return errors.Errorf("unsupported container runtime %q", ctx.cri)
+	}
+
+	if err := validateDebugTools(ctx.logger, ctx.debugTools); err != nil {
+		return err
+	}
+	if ctx.runtime.crictl != "" && !sets.NewString(ctx.debugTools...).Has("crictl") {
+		return errors.New("a crictl version cannot be set when crictl is not one of the debug tools")
+	}
+
+	// verify that we can push with the requested compression
+	switch ctx.compression {
+	case "gzip", "zstd":
+	default:
+		return errors.Errorf("unsupported compression %q", ctx.compression)
+	}
+
 	if ctx.buildType == "" {
 		ctx.buildType = detectBuildType(ctx.kubeParam)
 		if ctx.buildType != "" {
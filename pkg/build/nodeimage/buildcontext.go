@@ -17,6 +17,7 @@ limitations under the License.
 package nodeimage
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"os"
@@ -41,20 +42,32 @@ const (
 	httpsProxy = "HTTPS_PROXY"
 	// noProxy is the NO_PROXY environment variable key
 	noProxy = "NO_PROXY"
+	// tmpKubeadmPath is where kubeadm is copied to list the required images
+	tmpKubeadmPath = "/tmp/kubeadm"
 )
 
 // buildContext is used to build the kind node image, and contains
 // build configuration
 type buildContext struct {
 	// option fields
-	image     string
-	baseImage string
-	logger    log.Logger
-	arch      string
-	buildType string
-	kubeParam string
+	image       string
+	baseImage   string
+	logger      log.Logger
+	arch        string
+	buildType   string
+	kubeParam   string
+	cri         string
+	crioVersion string
+	wasmShims   bool
+	push        bool
+	compression string
+	cache       bool
+	runtime     runtimeVersions
+	debugTools  []string
 	// non-option fields
 	builder kube.Builder
+	// context is cancelled when the build should stop
+	context context.Context
 }
 
 // Build builds the cluster node image, the source dir must be set on
@@ -68,6 +81,9 @@ func (c *buildContext) Build() (err error) {
 		return errors.Wrap(err, "failed to build kubernetes")
 	}
 	c.logger.V(0).Info("Finished building Kubernetes")
+	if err := c.context.Err(); err != nil {
+		return err
+	}
 
 	// then perform the actual docker image build
 	c.logger.V(0).Info("Building node image ...")
@@ -75,13 +91,51 @@ func (c *buildContext) Build() (err error) {
 }
 
 func (c *buildContext) buildImage(bits kube.Bits) error {
+	// the version is written to the image and selects version specific
+	// configuration below
+	// TODO: support grabbing version from a binary instead?
+	// This may or may not be a good idea ...
+	rawVersion := bits.Version()
+	parsedVersion, err := version.ParseSemantic(rawVersion)
+	if err != nil {
+		return errors.Wrap(err, "invalid Kubernetes version")
+	}
+	kubeadmPath := ""
+	for _, binary := range bits.BinaryPaths() {
+		if path.Base(binary) == "kubeadm" {
+			kubeadmPath = binary
+		}
+	}
+	if kubeadmPath == "" {
+		return errors.New("kubeadm was not built")
+	}
+
+	// attempt to explicitly pull the image if it doesn't exist locally
+	// errors here are non-critical; we'll proceed with execution, which includes a pull operation
+	_ = docker.Pull(c.logger, c.baseImage, dockerBuildOsAndArch(c.arch), 4)
+
+	// with the cache, resume from the last stage a previous build with the
+	// same inputs saved
+	startImage, resumed := c.baseImage, noStage
+	runtimeKey, imagesKey := "", ""
+	if c.cache {
+		runtimeKey, imagesKey, err = c.stageKeys(bits, kubeadmPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to compute build cache keys")
+		}
+		if stage, image := cachedStage(runtimeKey, imagesKey); stage != noStage {
+			c.logger.V(0).Infof("Resuming build from cached image %q", image)
+			startImage, resumed = image, stage
+		}
+	}
+
 	// create build container
 	// NOTE: we are using docker run + docker commit, so we can install
 	// debian packages without permanently copying them into the image.
 	// if docker gets proper squash support, we can rm them instead
 	// This also allows the KubeBit implementations to programmatically
 	// install in the image
-	containerID, err := c.createBuildContainer()
+	containerID, err := c.createBuildContainer(startImage)
 	cmder := docker.ContainerCmder(containerID)
 
 	// ensure we will delete it
@@ -97,12 +151,67 @@ func (c *buildContext) buildImage(bits kube.Bits) error {
 
 	c.logger.V(0).Info("Building in container: " + containerID)
 
+	if resumed < runtimeStage {
+		// replace the runtime components if requested, before images are
+		// imported with containerd below
+		if err := installRuntimeVersions(cmder, c.arch, c.runtime); err != nil {
+			c.logger.Errorf("Image build Failed! %v", err)
+			return err
+		}
+
+		if err := installDebugTools(cmder, c.debugTools); err != nil {
+			c.logger.Errorf("Image build Failed! %v", err)
+			return err
+		}
+
+		// install the wasm shims if requested
+		if c.wasmShims {
+			if err := installWasmShims(cmder, c.arch); err != nil {
+				c.logger.Errorf("Image build Failed! Failed to install wasm shims: %v", err)
+				return err
+			}
+		}
+
+		if c.cache {
+			if err := saveStage(containerID, runtimeKey); err != nil {
+				c.logger.Warnf("Failed to cache build stage: %v", err)
+			}
+		}
+	}
+
+	if resumed < imagesStage {
+		// kubeadm lists the required images, it is only copied in
+		// temporarily so that the cached stage does not include it
+		if err := exec.CommandContext(c.context, "docker", "cp", kubeadmPath, containerID+":"+tmpKubeadmPath).Run(); err != nil {
+			return err
+		}
+		if err := cmder.Command("chmod", "+x", tmpKubeadmPath).Run(); err != nil {
+			return err
+		}
+
+		// pre-pull images that were not part of the build and write CNI / storage
+		// manifests
+		if _, err = c.prePullImagesAndWriteManifests(bits, parsedVersion, containerID); err != nil {
+			c.logger.Errorf("Image build Failed! Failed to pull Images: %v", err)
+			return err
+		}
+
+		if err := cmder.Command("rm", "-f", tmpKubeadmPath).Run(); err != nil {
+			return err
+		}
+		if c.cache {
+			if err := saveStage(containerID, imagesKey); err != nil {
+				c.logger.Warnf("Failed to cache build stage: %v", err)
+			}
+		}
+	}
+
 	// copy artifacts in
 	for _, binary := range bits.BinaryPaths() {
 		// TODO: probably should be /usr/local/bin, but the existing kubelet
 		// service file expects /usr/bin/kubelet
 		nodePath := "/usr/bin/" + path.Base(binary)
-		if err := exec.Command("docker", "cp", binary, containerID+":"+nodePath).Run(); err != nil {
+		if err := exec.CommandContext(c.context, "docker", "cp", binary, containerID+":"+nodePath).Run(); err != nil {
 			return err
 		}
 		if err := cmder.Command("chmod", "+x", nodePath).Run(); err != nil {
@@ -114,26 +223,35 @@ func (c *buildContext) buildImage(bits kube.Bits) error {
 	}
 
 	// write version
-	// TODO: support grabbing version from a binary instead?
-	// This may or may not be a good idea ...
-	rawVersion := bits.Version()
-	parsedVersion, err := version.ParseSemantic(rawVersion)
-	if err != nil {
-		return errors.Wrap(err, "invalid Kubernetes version")
-	}
 	if err := createFile(cmder, "/kind/version", rawVersion); err != nil {
 		return err
 	}
 
-	// pre-pull images that were not part of the build and write CNI / storage
-	// manifests
-	if _, err = c.prePullImagesAndWriteManifests(bits, parsedVersion, containerID); err != nil {
-		c.logger.Errorf("Image build Failed! Failed to pull Images: %v", err)
+	// switch to CRI-O if requested, images were exported for it above
+	if c.cri == crioRuntime {
+		containerdConfig, err := exec.Output(cmder.Command("cat", containerdConfigPath))
+		if err != nil {
+			return err
+		}
+		pauseImage, err := findSandboxImage(string(containerdConfig))
+		if err != nil {
+			return err
+		}
+		systemdCgroup := !parsedVersion.LessThan(version.MustParseSemantic("v1.24.0"))
+		if err := installCRIO(cmder, c.arch, c.crioVersion, pauseImage, systemdCgroup); err != nil {
+			c.logger.Errorf("Image build Failed! Failed to install CRI-O: %v", err)
+			return err
+		}
+	}
+
+	// stop before saving the image if the build was cancelled
+	if err := c.context.Err(); err != nil {
 		return err
 	}
 
 	// Save the image changes to a new image
-	if err = exec.Command(
+	if err = exec.CommandContext(
+		c.context,
 		"docker", "commit",
 		// we need to put this back after changing it when running the image
 		"--change", `ENTRYPOINT [ "/usr/local/bin/entrypoint", "/sbin/init" ]`,
@@ -147,6 +265,18 @@ func (c *buildContext) buildImage(bits kube.Bits) error {
 	}
 
 	c.logger.V(0).Infof("Image %q build completed.", c.image)
+
+	// push the image if requested, the digest lets clusters pull exactly
+	// this image
+	if c.push {
+		c.logger.V(0).Infof("Pushing image %q with %s compressed layers ...", c.image, c.compression)
+		pinned, err := docker.Push(c.image, dockerBuildOsAndArch(c.arch), c.compression)
+		if err != nil {
+			c.logger.Errorf("Image push Failed! %v", err)
+			return err
+		}
+		c.logger.V(0).Infof("Image pushed as %q.", pinned)
+	}
 	return nil
 }
 
@@ -163,7 +293,7 @@ func (c *buildContext) getBuiltImages(bits kube.Bits) (sets.String, error) {
 	return images, nil
 }
 
-// must be run after kubernetes has been installed on the node
+// must be run after kubeadm has been copied to tmpKubeadmPath
 func (c *buildContext) prePullImagesAndWriteManifests(bits kube.Bits, parsedVersion *version.Version, containerID string) ([]string, error) {
 	// first get the images we actually built
 	builtImages, err := c.getBuiltImages(bits)
@@ -206,7 +336,7 @@ func (c *buildContext) prePullImagesAndWriteManifests(bits kube.Bits, parsedVers
 
 	// gets the list of images required by kubeadm
 	requiredImages, err := exec.OutputLines(cmder.Command(
-		"kubeadm", "config", "images", "list", "--kubernetes-version", bits.Version(),
+		tmpKubeadmPath, "config", "images", "list", "--kubernetes-version", bits.Version(),
 	))
 	if err != nil {
 		return nil, err
@@ -270,6 +400,9 @@ func (c *buildContext) prePullImagesAndWriteManifests(bits kube.Bits, parsedVers
 	for _, image := range requiredImages {
 		image := image // https://golang.org/doc/faq#closures_and_goroutines
 		fns = append(fns, func() error {
+			if err := c.context.Err(); err != nil {
+				return err
+			}
 			if !builtImages.Has(image) {
 				if err = importer.Pull(image, dockerBuildOsAndArch(c.arch)); err != nil {
 					c.logger.Warnf("Failed to pull %s with error: %v", image, err)
@@ -322,13 +455,33 @@ func (c *buildContext) prePullImagesAndWriteManifests(bits kube.Bits, parsedVers
 		return nil, err
 	}
 
-	return importer.ListImported()
+	imported, err := importer.ListImported()
+	if err != nil {
+		return nil, err
+	}
+
+	// CRI-O cannot use the containerd image store, so export the images
+	// to be loaded on first boot instead
+	if c.cri == crioRuntime {
+		var named []string
+		for _, image := range imported {
+			if !strings.HasPrefix(image, "sha256:") {
+				named = append(named, image)
+			}
+		}
+		if err := cmder.Command("mkdir", "-p", crioImagesPath).Run(); err != nil {
+			return nil, err
+		}
+		if err := importer.Export(crioImagesPath+"/images.tar", dockerBuildOsAndArch(c.arch), named); err != nil {
+			c.logger.Errorf("Image build Failed! Failed to export images for CRI-O %v", err)
+			return nil, err
+		}
+	}
+
+	return imported, nil
 }
 
-func (c *buildContext) createBuildContainer() (id string, err error) {
-	// attempt to explicitly pull the image if it doesn't exist locally
-	// errors here are non-critical; we'll proceed with execution, which includes a pull operation
-	_ = docker.Pull(c.logger, c.baseImage, dockerBuildOsAndArch(c.arch), 4)
+func (c *buildContext) createBuildContainer(image string) (id string, err error) {
 	// this should be good enough: a specific prefix, the current unix time,
 	// and a little random bits in case we have multiple builds simultaneously
 	random := rand.New(rand.NewSource(time.Now().UnixNano())).Int31()
@@ -352,7 +505,7 @@ func (c *buildContext) createBuildContainer() (id string, err error) {
 		}
 	}
 	err = docker.Run(
-		c.baseImage,
+		image,
 		runArgs,
 		[]string{
 			"infinity", // sleep infinitely to keep container running indefinitely
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/build/nodeimage/internal/container/docker"
+	"sigs.k8s.io/kind/pkg/build/nodeimage/internal/kube"
+)
+
+// cacheRepository is the repository of the images that cache build stages,
+// these are only stored locally
+const cacheRepository = "kind-build-cache"
+
+// buildStage is a stage of the build that can be resumed from the cache,
+// in the order they run
+type buildStage int
+
+const (
+	// noStage means the build starts from the base image
+	noStage buildStage = iota
+	// runtimeStage installs the runtime components, debug tools and wasm shims
+	runtimeStage
+	// imagesStage pulls and imports the images and writes the manifests
+	imagesStage
+)
+
+// stageKeys returns the content addressed cache keys of the build stages,
+// derived from all of their inputs so that a stage is only reused if none
+// of them changed. Notably only the image archives and kubeadm, which lists
+// the images, are inputs of the images stage, not kubelet or kubectl.
+func (c *buildContext) stageKeys(bits kube.Bits, kubeadmPath string) (runtimeKey, imagesKey string, err error) {
+	baseImageID, err := docker.ImageID(c.baseImage)
+	if err != nil {
+		return "", "", err
+	}
+	runtimeKey = stageKey(
+		baseImageID, c.arch, c.runtime.containerd, c.runtime.runc, c.runtime.crictl, c.runtime.cniPlugins,
+		strconv.FormatBool(c.wasmShims), strings.Join(c.debugTools, ","),
+	)
+	imagesInputs := []string{
+		runtimeKey, c.cri, bits.Version(),
+		defaultCNIManifest, strings.Join(defaultCNIImages, ","),
+		defaultStorageManifest, strings.Join(defaultStorageImages, ","),
+	}
+	for _, path := range append([]string{kubeadmPath}, bits.ImagePaths()...) {
+		digest, err := fileDigest(path)
+		if err != nil {
+			return "", "", err
+		}
+		imagesInputs = append(imagesInputs, digest)
+	}
+	return runtimeKey, stageKey(imagesInputs...), nil
+}
+
+// cachedStage returns the last stage cached for the keys, and its image
+func cachedStage(runtimeKey, imagesKey string) (buildStage, string) {
+	for _, s := range []struct {
+		stage buildStage
+		key   string
+	}{
+		{imagesStage, imagesKey},
+		{runtimeStage, runtimeKey},
+	} {
+		image := cacheImage(s.key)
+		if _, err := docker.ImageID(image); err == nil {
+			return s.stage, image
+		}
+	}
+	return noStage, ""
+}
+
+// saveStage saves the build container as the cache image for key
+func saveStage(containerID, key string) error {
+	return exec.Command("docker", "commit", containerID, cacheImage(key)).Run()
+}
+
+func cacheImage(key string) string {
+	return cacheRepository + ":" + key
+}
+
+// stageKey hashes the inputs of a stage
+func stageKey(inputs ...string) string {
+	h := sha256.New()
+	for _, input := range inputs {
+		_, _ = io.WriteString(h, input)
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fileDigest returns the sha256 of the file at path
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
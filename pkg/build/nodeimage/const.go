@@ -22,4 +22,7 @@ const (
 	kubernetesVersionLocation      = "/kind/version"
 	defaultCNIManifestLocation     = "/kind/manifests/default-cni.yaml"
 	defaultStorageManifestLocation = "/kind/manifests/default-storage.yaml"
+	runtimeClassesManifestLocation = "/kind/manifests/runtimeclasses.yaml"
+	// criLocation records the container runtime if it is not containerd
+	criLocation = "/kind/cri"
 )
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// supported container runtimes for the node image
+const (
+	containerdRuntime = "containerd"
+	crioRuntime       = "crio"
+)
+
+// DefaultCRIOVersion is the CRI-O static bundle installed in CRI-O node
+// images unless WithCRIOVersion selects another release. The bundles are
+// verified against their published checksums rather than pinned ones, so any
+// release can be selected, see
+// https://github.com/cri-o/packaging#static-binaries
+const DefaultCRIOVersion = "v1.30.4"
+
+const crioConfigPath = "/etc/crio/crio.conf.d/10-kind.conf"
+
+// crioImagesPath is where images for CRI-O are stored in the node image
+// until the node first boots, see crioLoadImagesService
+const crioImagesPath = "/kind/images"
+
+// crioBundleURL is the CRI-O static bundle for an arch and version, the
+// bundles are published with a sha256sum file at the same URL + ".sha256sum"
+const crioBundleURL = "https://storage.googleapis.com/cri-o/artifacts/cri-o.%s.%s.tar.gz"
+
+const crioInstallScript = `
+set -o errexit -o nounset -o pipefail
+clean-install podman
+%s
+tar -C /tmp -xzf /tmp/cri-o.tar.gz
+(cd /tmp/cri-o && ./install)
+rm -rf /tmp/cri-o /tmp/cri-o.tar.gz /etc/cni/net.d/*crio*
+`
+
+const crioConfig = `[crio.image]
+pause_image = "%s"
+
+[crio.runtime]
+cgroup_manager = "%s"
+conmon_cgroup = "%s"
+`
+
+// crioLoadImagesService loads the images exported at build time into the
+// CRI-O image store on first boot. This cannot happen at build time as
+// unpacking requires overlayfs, which is not available inside the build
+// container, while /var is a volume on kind nodes
+const crioLoadImagesService = `[Unit]
+Description=Load kind images into CRI-O storage
+ConditionDirectoryNotEmpty=/kind/images
+Before=crio.service kubelet.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/sh -c 'for f in /kind/images/*.tar; do podman load -q -i "$f" && rm -f "$f"; done'
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// installCRIO installs the CRI-O release crioVersion in the build container
+// and switches the node image from containerd to CRI-O, images must already
+// be exported to crioImagesPath
+func installCRIO(containerCmdr exec.Cmder, arch, crioVersion, pauseImage string, systemdCgroup bool) error {
+	bundleURL := fmt.Sprintf(crioBundleURL, arch, normalizeVersion(crioVersion))
+	download := downloadPublishedScript(bundleURL, "/tmp/cri-o.tar.gz", bundleURL+".sha256sum")
+	if err := containerCmdr.Command(
+		"bash", "-c", fmt.Sprintf(crioInstallScript, download),
+	).Run(); err != nil {
+		return errors.Wrapf(err, "failed to install CRI-O %s", crioVersion)
+	}
+
+	cgroupManager, conmonCgroup := "systemd", "system.slice"
+	if !systemdCgroup {
+		cgroupManager, conmonCgroup = "cgroupfs", "pod"
+	}
+	files := map[string]string{
+		crioConfigPath: fmt.Sprintf(crioConfig, pauseImage, cgroupManager, conmonCgroup),
+		"/etc/systemd/system/kind-load-images.service": crioLoadImagesService,
+		"/etc/crictl.yaml":     "runtime-endpoint: unix:///var/run/crio/crio.sock\n",
+		"/etc/default/kubelet": "KUBELET_EXTRA_ARGS=--runtime-cgroups=/system.slice/crio.service\n",
+		criLocation:            crioRuntime,
+	}
+	for path, contents := range files {
+		if err := createFile(containerCmdr, path, contents); err != nil {
+			return errors.Wrap(err, "failed to configure CRI-O")
+		}
+	}
+
+	// containerd is only used to prepare images during the build
+	if err := containerCmdr.Command(
+		"bash", "-c", "systemctl disable containerd && systemctl enable crio kind-load-images && rm -rf /var/lib/containerd/*",
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to enable CRI-O")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/sets"
+)
+
+// DebugTools are the debug tools that can be included in the node image.
+// iproute2 is not one of them, the node entrypoint needs it
+var DebugTools = []string{"crictl", "tcpdump"}
+
+// DefaultDebugTools are the debug tools included in the node image by
+// default, the base image already contains them
+var DefaultDebugTools = []string{"crictl"}
+
+// debugTool describes how to add a debug tool to the build container, or
+// to remove it for the default debug tools
+type debugTool struct {
+	install string
+	remove  string
+	// usedFor describes what kind needs the tool for, if anything
+	usedFor string
+}
+
+var debugTools = map[string]debugTool{
+	"crictl": {
+		// crictl is not packaged, it is only ever removed from the base image
+		remove:  "rm -f /usr/local/bin/crictl",
+		usedFor: "pulling and listing images, etcd snapshots and certificate renewal",
+	},
+	"tcpdump": {
+		install: "clean-install tcpdump",
+		remove:  "apt-get purge -y --auto-remove tcpdump",
+	},
+}
+
+// validateDebugTools checks that tools are all known debug tools, and warns
+// about the default tools that kind uses if they are left out
+func validateDebugTools(logger log.Logger, tools []string) error {
+	known := sets.NewString(DebugTools...)
+	for _, tool := range tools {
+		if !known.Has(tool) {
+			return errors.Errorf("unknown debug tool %q, must be one of: %s", tool, strings.Join(DebugTools, ", "))
+		}
+	}
+	requested := sets.NewString(tools...)
+	for _, tool := range DefaultDebugTools {
+		if usedFor := debugTools[tool].usedFor; !requested.Has(tool) && usedFor != "" {
+			logger.Warnf("node images without %s do not support %s", tool, usedFor)
+		}
+	}
+	return nil
+}
+
+// installDebugTools adds the requested debug tools to the build container,
+// and removes the default debug tools that were not requested
+func installDebugTools(containerCmdr exec.Cmder, tools []string) error {
+	requested := sets.NewString(tools...)
+	defaults := sets.NewString(DefaultDebugTools...)
+	for _, name := range DebugTools {
+		script := ""
+		switch {
+		case requested.Has(name) && !defaults.Has(name):
+			script = debugTools[name].install
+		case !requested.Has(name) && defaults.Has(name):
+			script = debugTools[name].remove
+		}
+		if script == "" {
+			continue
+		}
+		if err := containerCmdr.Command("bash", "-c", script).Run(); err != nil {
+			return errors.Wrapf(err, "failed to update debug tool %s", name)
+		}
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package nodeimage
 
 import (
+	"fmt"
 	"path"
 	"regexp"
 	"strings"
@@ -42,6 +43,25 @@ func createFile(containerCmder exec.Cmder, filePath, contents string) error {
 	).Run()
 }
 
+// downloadScript returns a bash script downloading url to dest in the
+// container and verifying it against the sha256 hash
+func downloadScript(url, dest, sha256 string) string {
+	return fmt.Sprintf(
+		`curl -sSfL --retry 5 -o "%[2]s" "%[1]s" && echo "%[3]s  %[2]s" | sha256sum -c --quiet -`,
+		url, dest, sha256,
+	)
+}
+
+// downloadPublishedScript is downloadScript for releases publishing their
+// checksums, the hash for url is read from the checksum file at sumURL which
+// either holds only the hash or "<hash>  <file>" lines
+func downloadPublishedScript(url, dest, sumURL string) string {
+	return fmt.Sprintf(
+		`sum="$(curl -sSfL --retry 5 "%[3]s" | awk -v f="%[4]s" '{ sub(/^\*/, "", $2) } NF == 1 || $2 == f { print $1; exit }')" && [ -n "${sum}" ] && curl -sSfL --retry 5 -o "%[2]s" "%[1]s" && echo "${sum}  %[2]s" | sha256sum -c --quiet -`,
+		url, dest, sumURL, path.Base(url),
+	)
+}
+
 func findSandboxImage(config string) (string, error) {
 	match := regexp.MustCompile(`sandbox_image\s+=\s+"([^\n]+)"`).FindStringSubmatch(config)
 	if len(match) < 2 {
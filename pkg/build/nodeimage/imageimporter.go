@@ -68,3 +68,10 @@ func (c *containerdImporter) Tag(src, target string) error {
 func (c *containerdImporter) ListImported() ([]string, error) {
 	return exec.OutputLines(c.containerCmder.Command("ctr", "--namespace=k8s.io", "images", "list", "-q"))
 }
+
+// Export writes the images to an archive at path in the container,
+// only including content for platform
+func (c *containerdImporter) Export(path, platform string, images []string) error {
+	args := []string{"--namespace=k8s.io", "images", "export", "--platform=" + platform, path}
+	return c.containerCmder.Command("ctr", append(args, images...)...).Run()
+}
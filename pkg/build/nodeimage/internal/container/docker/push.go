@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// Push pushes image to its registry and returns the pushed image reference
+// pinned by digest. compression is one of "gzip", as in `docker push`, or
+// "zstd", which recompresses the layers with `docker buildx`.
+func Push(image, platform, compression string) (string, error) {
+	var digest string
+	var err error
+	switch compression {
+	case "gzip":
+		digest, err = pushGzip(image)
+	case "zstd":
+		digest, err = pushZstd(image, platform)
+	default:
+		return "", errors.Errorf("unsupported compression %q", compression)
+	}
+	if err != nil {
+		return "", err
+	}
+	return repository(image) + "@" + digest, nil
+}
+
+// pushDigestRE matches the digest in `docker push` output, e.g.
+// "latest: digest: sha256:... size: 1234"
+var pushDigestRE = regexp.MustCompile(`digest: (sha256:[0-9a-f]{64})`)
+
+func pushGzip(image string) (string, error) {
+	lines, err := exec.OutputLines(exec.Command("docker", "push", image))
+	if err != nil {
+		return "", err
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if match := pushDigestRE.FindStringSubmatch(lines[i]); match != nil {
+			return match[1], nil
+		}
+	}
+	return "", errors.Errorf("failed to find digest of pushed image %q", image)
+}
+
+// pushZstd rebuilds image as-is with buildx, which can recompress the
+// layers when exporting to a registry. This needs a builder that can read
+// image from the local image store, such as the default builder with the
+// containerd image store enabled.
+func pushZstd(image, platform string) (string, error) {
+	dir, err := os.MkdirTemp("", "kind-push-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	metadataFile := filepath.Join(dir, "metadata.json")
+	cmd := exec.Command(
+		"docker", "buildx", "build",
+		"--platform="+platform,
+		"--output=type=registry,name="+image+",compression=zstd,force-compression=true,oci-mediatypes=true",
+		"--metadata-file="+metadataFile,
+		"-",
+	)
+	cmd.SetStdin(strings.NewReader("FROM " + image + "\n"))
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	raw, err := os.ReadFile(metadataFile)
+	if err != nil {
+		return "", err
+	}
+	return parseBuildxDigest(raw)
+}
+
+func parseBuildxDigest(metadata []byte) (string, error) {
+	var parsed struct {
+		Digest string `json:"containerimage.digest"`
+	}
+	if err := json.Unmarshal(metadata, &parsed); err != nil {
+		return "", errors.Wrap(err, "failed to parse buildx metadata")
+	}
+	if parsed.Digest == "" {
+		return "", errors.New("buildx metadata does not contain the image digest")
+	}
+	return parsed.Digest, nil
+}
+
+// repository returns image without its tag or digest, e.g.
+// "localhost:5000/kindest/node:latest" -> "localhost:5000/kindest/node"
+func repository(image string) string {
+	if at := strings.IndexByte(image, '@'); at != -1 {
+		image = image[:at]
+	}
+	if colon := strings.LastIndexByte(image, ':'); colon > strings.LastIndexByte(image, '/') {
+		image = image[:colon]
+	}
+	return image
+}
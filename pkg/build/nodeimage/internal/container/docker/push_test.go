@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import "testing"
+
+func TestRepository(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Image    string
+		Expected string
+	}{
+		{Image: "kindest/node", Expected: "kindest/node"},
+		{Image: "kindest/node:latest", Expected: "kindest/node"},
+		{Image: "localhost:5000/kindest/node", Expected: "localhost:5000/kindest/node"},
+		{Image: "localhost:5000/kindest/node:v1.31.0", Expected: "localhost:5000/kindest/node"},
+		{
+			Image:    "kindest/node:v1.31.0@sha256:28ef97b8686a0b5399129e9b763d5b7e5ff03576aa5580d6f4182a49c5fe1913",
+			Expected: "kindest/node",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture tc
+		t.Run(tc.Image, func(t *testing.T) {
+			t.Parallel()
+			if actual := repository(tc.Image); actual != tc.Expected {
+				t.Errorf("expected %q but got %q", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseBuildxDigest(t *testing.T) {
+	t.Parallel()
+	digest, err := parseBuildxDigest([]byte(`{"containerimage.config.digest": "sha256:aaaa", "containerimage.digest": "sha256:bbbb"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if digest != "sha256:bbbb" {
+		t.Errorf("expected sha256:bbbb but got %q", digest)
+	}
+	if _, err := parseBuildxDigest([]byte(`{}`)); err == nil {
+		t.Errorf("expected an error for metadata without a digest")
+	}
+}
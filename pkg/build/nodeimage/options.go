@@ -82,3 +82,102 @@ func WithBuildType(buildType string) Option {
 		return nil
 	})
 }
+
+// WithCRI sets the container runtime installed in the node image, one of
+// "containerd" (the default) or the experimental "crio"
+func WithCRI(cri string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		if cri != "" {
+			b.cri = cri
+		}
+		return nil
+	})
+}
+
+// WithCRIOVersion configures a CRI-O node image build to install this CRI-O
+// release, e.g. "v1.31.1", instead of DefaultCRIOVersion
+func WithCRIOVersion(version string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		if version != "" {
+			b.crioVersion = version
+		}
+		return nil
+	})
+}
+
+// WithWasmShims configures a build to install containerd shims for running
+// WebAssembly workloads, with a RuntimeClass for each created in the cluster
+func WithWasmShims(wasmShims bool) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.wasmShims = wasmShims
+		return nil
+	})
+}
+
+// WithPush configures a build to push the built image to its registry, with
+// layers compressed using compression, one of "gzip" or "zstd"
+func WithPush(push bool, compression string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.push = push
+		if compression != "" {
+			b.compression = compression
+		}
+		return nil
+	})
+}
+
+// WithContainerdVersion configures a build to install this containerd
+// release, e.g. "v2.0.2", instead of the base image's
+func WithContainerdVersion(version string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.runtime.containerd = version
+		return nil
+	})
+}
+
+// WithRuncVersion configures a build to install this runc release, e.g.
+// "v1.2.3", instead of the base image's
+func WithRuncVersion(version string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.runtime.runc = version
+		return nil
+	})
+}
+
+// WithCrictlVersion configures a build to install this crictl release,
+// e.g. "v1.32.0", instead of the base image's
+func WithCrictlVersion(version string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.runtime.crictl = version
+		return nil
+	})
+}
+
+// WithCNIPluginsVersion configures a build to install this CNI plugins
+// release, e.g. "v1.6.1", instead of the base image's
+func WithCNIPluginsVersion(version string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.runtime.cniPlugins = version
+		return nil
+	})
+}
+
+// WithDebugTools configures a build to include exactly these DebugTools in
+// the node image, instead of the DefaultDebugTools. An empty list builds a
+// minimal image without any of them
+func WithDebugTools(tools []string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.debugTools = tools
+		return nil
+	})
+}
+
+// WithCache configures a build to save the expensive stages of the build as
+// local images, and to resume from them when their inputs are unchanged,
+// e.g. when only kubelet changed
+func WithCache(cache bool) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.cache = cache
+		return nil
+	})
+}
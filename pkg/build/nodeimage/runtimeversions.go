@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// runtimeVersions are versions of the container runtime components to
+// install from their official releases instead of those in the base image,
+// empty versions are not replaced
+type runtimeVersions struct {
+	containerd string
+	runc       string
+	crictl     string
+	cniPlugins string
+}
+
+// runtimeComponent describes how to install a runtime component release
+type runtimeComponent struct {
+	name string
+	// script installs the release downloaded to runtimeDownloadPath
+	script string
+	// url returns the release URL for a version (with the "v" prefix) and arch
+	url func(version, arch string) string
+	// sumURL returns the checksum file published with the release at url
+	sumURL func(url string) string
+}
+
+// runtimeDownloadPath is where runtime component releases are downloaded and
+// verified before they are installed
+const runtimeDownloadPath = "/tmp/runtime-component"
+
+// sha256sumSuffix returns the checksum file published next to the release
+func sha256sumSuffix(suffix string) func(string) string {
+	return func(url string) string {
+		return url + suffix
+	}
+}
+
+var (
+	containerdComponent = runtimeComponent{
+		name: "containerd",
+		// only the binaries the base image has, older releases also
+		// contain deprecated shims
+		script: `tar -C /usr/local -xzf ` + runtimeDownloadPath + ` bin/containerd bin/ctr bin/containerd-shim-runc-v2`,
+		url: func(version, arch string) string {
+			return fmt.Sprintf(
+				"https://github.com/containerd/containerd/releases/download/%s/containerd-%s-linux-%s.tar.gz",
+				version, strings.TrimPrefix(version, "v"), arch,
+			)
+		},
+		sumURL: sha256sumSuffix(".sha256sum"),
+	}
+	runcComponent = runtimeComponent{
+		name:   "runc",
+		script: `install -m 755 ` + runtimeDownloadPath + ` /usr/local/sbin/runc`,
+		url: func(version, arch string) string {
+			return fmt.Sprintf("https://github.com/opencontainers/runc/releases/download/%s/runc.%s", version, arch)
+		},
+		// runc publishes a single checksum file for all architectures
+		sumURL: func(url string) string {
+			return url[:strings.LastIndex(url, "/")+1] + "runc.sha256sum"
+		},
+	}
+	crictlComponent = runtimeComponent{
+		name:   "crictl",
+		script: `tar -C /usr/local/bin -xzf ` + runtimeDownloadPath + ` crictl`,
+		url: func(version, arch string) string {
+			return fmt.Sprintf(
+				"https://github.com/kubernetes-sigs/cri-tools/releases/download/%s/crictl-%s-linux-%s.tar.gz",
+				version, version, arch,
+			)
+		},
+		sumURL: sha256sumSuffix(".sha256"),
+	}
+	cniPluginsComponent = runtimeComponent{
+		name: "CNI plugins",
+		// only the plugins the base image has
+		script: `tar -C /opt/cni/bin -xzf ` + runtimeDownloadPath + ` ./host-local ./loopback ./ptp ./portmap`,
+		url: func(version, arch string) string {
+			return fmt.Sprintf(
+				"https://github.com/containernetworking/plugins/releases/download/%s/cni-plugins-linux-%s-%s.tgz",
+				version, arch, version,
+			)
+		},
+		sumURL: sha256sumSuffix(".sha256"),
+	}
+)
+
+// installRuntimeVersions replaces the runtime components in the build
+// container with the requested versions
+func installRuntimeVersions(containerCmdr exec.Cmder, arch string, versions runtimeVersions) error {
+	for _, c := range []struct {
+		component runtimeComponent
+		version   string
+	}{
+		{containerdComponent, versions.containerd},
+		{runcComponent, versions.runc},
+		{crictlComponent, versions.crictl},
+		{cniPluginsComponent, versions.cniPlugins},
+	} {
+		if c.version == "" {
+			continue
+		}
+		url := c.component.url(normalizeVersion(c.version), arch)
+		download := downloadPublishedScript(url, runtimeDownloadPath, c.component.sumURL(url))
+		if err := containerCmdr.Command(
+			"bash", "-c", download+" && "+c.component.script+" && rm -f "+runtimeDownloadPath,
+		).Run(); err != nil {
+			return errors.Wrapf(err, "failed to install %s %s", c.component.name, c.version)
+		}
+	}
+	return nil
+}
+
+// normalizeVersion adds the "v" prefix the releases are tagged with
+func normalizeVersion(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/internal/patch"
+)
+
+// wasmShim is a containerd shim for running WebAssembly workloads
+type wasmShim struct {
+	// handler is the containerd runtime and RuntimeClass handler name
+	handler string
+	// runtimeType is the containerd runtime_type for the shim
+	runtimeType string
+	// url is the release tarball containing the shim binary, with the
+	// architecture (as in uname -m) substituted for %s
+	url string
+	// sha256 pins the tarball hash for each architecture (as in uname -m),
+	// these must be updated together with url
+	sha256 map[string]string
+}
+
+var wasmShims = []wasmShim{
+	{
+		handler:     "spin",
+		runtimeType: "io.containerd.spin.v2",
+		url:         "https://github.com/spinkube/containerd-shim-spin/releases/download/v0.15.1/containerd-shim-spin-v2-linux-%s.tar.gz",
+		sha256:      map[string]string{},
+	},
+	{
+		handler:     "wasmtime",
+		runtimeType: "io.containerd.wasmtime.v1",
+		url:         "https://github.com/containerd/runwasi/releases/download/containerd-shim-wasmtime%%2Fv0.5.0/containerd-shim-wasmtime-%s-linux-musl.tar.gz",
+		sha256:      map[string]string{},
+	},
+}
+
+const wasmShimContainerdConfigPatch = `
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.%s]
+  runtime_type = "%s"
+`
+
+const wasmRuntimeClass = `---
+apiVersion: node.k8s.io/v1
+kind: RuntimeClass
+metadata:
+  name: %s
+handler: %s
+`
+
+// installWasmShims installs the WebAssembly containerd shims in the build
+// container, registers them with containerd and writes RuntimeClass objects
+// for them to be created at cluster creation
+func installWasmShims(containerCmdr exec.Cmder, arch string) error {
+	machine, err := unameMachine(arch)
+	if err != nil {
+		return err
+	}
+
+	patches := []string{}
+	runtimeClasses := ""
+	for _, shim := range wasmShims {
+		sha256, ok := shim.sha256[machine]
+		if !ok {
+			return errors.Errorf("no pinned sha256 for the %s shim on %s", shim.handler, machine)
+		}
+		download := downloadScript(fmt.Sprintf(shim.url, machine), "/tmp/wasm-shim.tar.gz", sha256)
+		if err := containerCmdr.Command(
+			"bash", "-c", download+" && tar -C /usr/local/bin -xzf /tmp/wasm-shim.tar.gz && rm -f /tmp/wasm-shim.tar.gz",
+		).Run(); err != nil {
+			return errors.Wrapf(err, "failed to install %s shim", shim.handler)
+		}
+		patches = append(patches, fmt.Sprintf(wasmShimContainerdConfigPatch, shim.handler, shim.runtimeType))
+		runtimeClasses += fmt.Sprintf(wasmRuntimeClass, shim.handler, shim.handler)
+	}
+
+	// register the shims with containerd
+	config, err := exec.Output(containerCmdr.Command("cat", containerdConfigPath))
+	if err != nil {
+		return errors.Wrap(err, "failed to read containerd config")
+	}
+	patched, err := patch.TOML(string(config), patches, []string{})
+	if err != nil {
+		return errors.Wrap(err, "failed to configure containerd wasm shims")
+	}
+	if err := containerCmdr.Command(
+		"cp", "/dev/stdin", containerdConfigPath,
+	).SetStdin(strings.NewReader(patched)).Run(); err != nil {
+		return errors.Wrap(err, "failed to configure containerd wasm shims")
+	}
+
+	return createFile(containerCmdr, runtimeClassesManifestLocation, runtimeClasses)
+}
+
+// unameMachine returns the uname -m machine name used by the shim releases
+func unameMachine(arch string) (string, error) {
+	switch arch {
+	case "amd64":
+		return "x86_64", nil
+	case "arm64":
+		return "aarch64", nil
+	}
+	return "", errors.Errorf("wasm shims are not available for architecture %q", arch)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	internalcreate "sigs.k8s.io/kind/pkg/cluster/internal/create"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/recordconfig"
+	"sigs.k8s.io/kind/pkg/internal/sets"
+)
+
+// Clone creates the cluster dst with the same config the existing cluster
+// src was created with, including its nodes, mounts and port mappings, with
+// fixed host ports that are in use replaced by free ports. options apply on
+// top of the source config, e.g. CreateWithWaitForReady.
+// If copyImages is true, the images loaded into src's nodes, e.g. with
+// `kind load`, are then copied to the matching nodes of dst.
+func (p *Provider) Clone(ctx context.Context, src, dst string, copyImages bool, options ...CreateOption) error {
+	srcNodes, err := p.provider.ListNodes(defaultName(src))
+	if err != nil {
+		return err
+	}
+	if len(srcNodes) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(src))
+	}
+	cfg, err := recordconfig.Read(srcNodes)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the config of cluster %q", defaultName(src))
+	}
+
+	// apply options
+	opts := &internalcreate.ClusterOptions{
+		Config:                    cfg,
+		NameOverride:              dst,
+		AutoRemapPorts:            true,
+		KubeconfigContextTemplate: p.kubeconfigContextTemplate,
+	}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return err
+		}
+	}
+	if err := internalcreate.Cluster(ctx, p.logger, p.provider, opts); err != nil {
+		return err
+	}
+
+	if !copyImages {
+		return nil
+	}
+	return p.copyLoadedImages(ctx, defaultName(src), srcNodes, opts.Config.Name)
+}
+
+// copyLoadedImages copies the images each of src's nodes has, but the node
+// of dst with the same role and index does not
+func (p *Provider) copyLoadedImages(ctx context.Context, src string, srcNodes []nodes.Node, dst string) error {
+	srcKubeNodes, err := nodeutils.InternalNodes(srcNodes)
+	if err != nil {
+		return err
+	}
+	dstNodes, err := p.provider.ListNodes(dst)
+	if err != nil {
+		return err
+	}
+	dstKubeNodes, err := nodeutils.InternalNodes(dstNodes)
+	if err != nil {
+		return err
+	}
+	// nodes are named <cluster>-<role><index>
+	srcBySuffix := map[string]nodes.Node{}
+	for _, n := range srcKubeNodes {
+		srcBySuffix[strings.TrimPrefix(n.String(), src+"-")] = n
+	}
+	for _, dstNode := range dstKubeNodes {
+		srcNode, ok := srcBySuffix[strings.TrimPrefix(dstNode.String(), dst+"-")]
+		if !ok {
+			continue
+		}
+		missing, err := missingImages(srcNode, dstNode)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		p.logger.V(0).Infof("Copying %d images from node %q to node %q", len(missing), srcNode, dstNode)
+		if err := copyImages(ctx, srcNode, dstNode, missing); err != nil {
+			return errors.Wrapf(err, "failed to copy images to node %q", dstNode)
+		}
+	}
+	return nil
+}
+
+// missingImages returns the tagged images on src that dst does not have
+func missingImages(src, dst nodes.Node) ([]string, error) {
+	dstImages, err := nodeutils.Images(dst)
+	if err != nil {
+		return nil, err
+	}
+	have := sets.NewString()
+	for _, image := range dstImages {
+		have.Insert(image.RepoTags...)
+	}
+	srcImages, err := nodeutils.Images(src)
+	if err != nil {
+		return nil, err
+	}
+	missing := sets.NewString()
+	for _, image := range srcImages {
+		for _, tag := range image.RepoTags {
+			if !have.Has(tag) {
+				missing.Insert(tag)
+			}
+		}
+	}
+	return missing.List(), nil
+}
+
+// copyImages streams an archive of images from src to dst
+func copyImages(ctx context.Context, src, dst nodes.Node, images []string) error {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(nodeutils.ExportImageArchive(ctx, src, pw, images...))
+	}()
+	err := nodeutils.LoadImageArchiveContext(ctx, dst, pr)
+	// unblock the export if loading failed early
+	pr.CloseWithError(err)
+	return err
+}
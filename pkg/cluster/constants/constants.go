@@ -46,4 +46,11 @@ const (
 	// Please note that `kind` nodes hosting external etcd are not
 	// kubernetes nodes
 	ExternalEtcdNodeRoleValue string = "external-etcd"
+
+	// HollowNodeRoleValue identifies a node that runs a kubemark hollow
+	// kubelet, which registers with the control plane without running pods.
+	//
+	// Please note that `kind` hollow nodes are Kubernetes nodes, but they are
+	// not otherwise managed by kind like control-plane and worker nodes
+	HollowNodeRoleValue string = "hollow"
 )
@@ -17,6 +17,7 @@ limitations under the License.
 package cluster
 
 import (
+	"os"
 	"time"
 
 	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
@@ -70,8 +71,56 @@ func CreateWithNodeImage(nodeImage string) CreateOption {
 	})
 }
 
+// CreateWithControlPlaneImage overrides the image on the control plane
+// nodes in config, taking precedence over CreateWithNodeImage
+func CreateWithControlPlaneImage(image string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.ControlPlaneImage = image
+		return nil
+	})
+}
+
+// CreateWithWorkerImage overrides the image on the worker nodes in config,
+// taking precedence over CreateWithNodeImage, e.g. to test kubelet version
+// skew with older workers
+func CreateWithWorkerImage(image string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.WorkerImage = image
+		return nil
+	})
+}
+
+// CreateWithInsecureRegistries allows every node to use the registries, as
+// "host:port", over plain HTTP or HTTPS without verifying the certificate,
+// in addition to the config's insecureRegistries
+func CreateWithInsecureRegistries(registries ...string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.InsecureRegistries = append(o.InsecureRegistries, registries...)
+		return nil
+	})
+}
+
+// CreateWithProtection marks the cluster as protected, protected clusters
+// are only deleted when forced, see DeleteWithForce
+func CreateWithProtection(protect bool) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.Protect = protect
+		return nil
+	})
+}
+
+// CreateWithAutoRemapPorts replaces host ports requested by the config that
+// are already in use with free ports instead of failing
+func CreateWithAutoRemapPorts(remap bool) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.AutoRemapPorts = remap
+		return nil
+	})
+}
+
 // CreateWithRetain disables deletion of nodes and any other cleanup
-// that would normally occur after a failure to create
+// that would normally occur after a failure to create, or after creation
+// is cancelled
 // This is mainly used for debugging purposes
 func CreateWithRetain(retain bool) CreateOption {
 	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
@@ -80,6 +129,16 @@ func CreateWithRetain(retain bool) CreateOption {
 	})
 }
 
+// CreateWithRetainOnFailure disables deletion of nodes after a failure to
+// create, for debugging, while nodes are still deleted if creation is
+// cancelled, e.g. when a CI job times out
+func CreateWithRetainOnFailure(retain bool) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.RetainOnFailure = retain
+		return nil
+	})
+}
+
 // CreateWithWaitForReady configures a maximum wait time for the control plane
 // node(s) to be ready. By default no waiting is performed
 func CreateWithWaitForReady(waitTime time.Duration) CreateOption {
@@ -89,6 +148,46 @@ func CreateWithWaitForReady(waitTime time.Duration) CreateOption {
 	})
 }
 
+// CreateWithDefaultNodeImage sets the image for nodes that would otherwise
+// use kind's default image, unlike CreateWithNodeImage images set in the
+// config take precedence over it
+func CreateWithDefaultNodeImage(nodeImage string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.DefaultNodeImage = nodeImage
+		return nil
+	})
+}
+
+// CreateWithDefaultWaitForReady sets the wait time for the control plane
+// node(s) to be ready when neither CreateWithWaitForReady nor the config's
+// timeouts.nodesReady set one
+func CreateWithDefaultWaitForReady(waitTime time.Duration) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.DefaultWaitForReady = waitTime
+		return nil
+	})
+}
+
+// CreateWithVerify runs smoke tests once the cluster is created, logging a
+// pass / fail matrix. Creation fails if any test fails but the cluster is
+// not deleted.
+func CreateWithVerify(verify bool) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.Verify = verify
+		return nil
+	})
+}
+
+// CreateWithForeground makes CreateContext keep running once the cluster
+// is created, until its context is cancelled, and then delete the cluster.
+// The cluster is also deleted if creation fails, unless it is retained.
+func CreateWithForeground(foreground bool) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.Foreground = foreground
+		return nil
+	})
+}
+
 // CreateWithKubeconfigPath sets the explicit --kubeconfig path
 func CreateWithKubeconfigPath(explicitPath string) CreateOption {
 	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
@@ -97,6 +196,23 @@ func CreateWithKubeconfigPath(explicitPath string) CreateOption {
 	})
 }
 
+// CreateWithKubeconfigMode sets the mode of the kubeconfig file, see
+// ExportKubeConfigWithMode
+func CreateWithKubeconfigMode(mode os.FileMode) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.KubeconfigFileOptions.Mode = mode
+		return nil
+	})
+}
+
+// CreateWithKubeconfigOwner sets the owner of the kubeconfig file, see
+// ExportKubeConfigWithOwner
+func CreateWithKubeconfigOwner(owner string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		return applyKubeconfigOwner(&o.KubeconfigFileOptions, owner)
+	})
+}
+
 // CreateWithStopBeforeSettingUpKubernetes enables skipping setting up
 // kubernetes (kubeadm init etc.) after creating node containers
 // This generally shouldn't be used and is only lightly supported, but allows
@@ -124,3 +240,47 @@ func CreateWithDisplaySalutation(displaySalutation bool) CreateOption {
 		return nil
 	})
 }
+
+// AddNodeOption is a Provider.AddNode option
+type AddNodeOption interface {
+	apply(*internalcreate.NodeOptions) error
+}
+
+type addNodeOptionAdapter func(*internalcreate.NodeOptions) error
+
+func (c addNodeOptionAdapter) apply(o *internalcreate.NodeOptions) error {
+	return c(o)
+}
+
+// AddNodeWithName names the new node, instead of the next free
+// <cluster>-worker<n> name
+func AddNodeWithName(name string) AddNodeOption {
+	return addNodeOptionAdapter(func(o *internalcreate.NodeOptions) error {
+		o.Name = name
+		return nil
+	})
+}
+
+// AddNodeWithImage overrides the image of the new node if non-empty
+func AddNodeWithImage(image string) AddNodeOption {
+	return addNodeOptionAdapter(func(o *internalcreate.NodeOptions) error {
+		o.Image = image
+		return nil
+	})
+}
+
+// AddNodeWithLabels adds labels to the labels of the new node
+func AddNodeWithLabels(labels map[string]string) AddNodeOption {
+	return addNodeOptionAdapter(func(o *internalcreate.NodeOptions) error {
+		o.Labels = labels
+		return nil
+	})
+}
+
+// AddNodeWithRetain keeps the node if adding it fails
+func AddNodeWithRetain(retain bool) AddNodeOption {
+	return addNodeOptionAdapter(func(o *internalcreate.NodeOptions) error {
+		o.Retain = retain
+		return nil
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	internaldelete "sigs.k8s.io/kind/pkg/cluster/internal/delete"
+)
+
+// DeleteOption is a Provider.Delete option
+type DeleteOption interface {
+	apply(*deleteOptions) error
+}
+
+// deleteOptions holds cluster deletion options
+type deleteOptions struct {
+	Force bool
+	// ExportLogsDir is where to export the cluster logs before deleting it,
+	// if non-empty
+	ExportLogsDir string
+}
+
+type deleteOptionAdapter func(*deleteOptions) error
+
+func (c deleteOptionAdapter) apply(o *deleteOptions) error {
+	return c(o)
+}
+
+// DeleteWithForce deletes the cluster even if it is protected
+func DeleteWithForce(force bool) DeleteOption {
+	return deleteOptionAdapter(func(o *deleteOptions) error {
+		o.Force = force
+		return nil
+	})
+}
+
+// DeleteWithExportLogs exports the cluster logs to dir before deleting the
+// cluster, as with Provider.CollectLogs. Failing to export logs does not
+// prevent deletion. Logs are not exported if dir is empty.
+func DeleteWithExportLogs(dir string) DeleteOption {
+	return deleteOptionAdapter(func(o *deleteOptions) error {
+		o.ExportLogsDir = dir
+		return nil
+	})
+}
+
+// DeleteNodeOption is a Provider.DeleteNode option
+type DeleteNodeOption interface {
+	apply(*internaldelete.NodeOptions) error
+}
+
+type deleteNodeOptionAdapter func(*internaldelete.NodeOptions) error
+
+func (c deleteNodeOptionAdapter) apply(o *internaldelete.NodeOptions) error {
+	return c(o)
+}
+
+// DeleteNodeWithDrain cordons and drains the node before deleting it, so its
+// pods are evicted respecting PodDisruptionBudgets instead of being killed
+func DeleteNodeWithDrain(drain bool) DeleteNodeOption {
+	return deleteNodeOptionAdapter(func(o *internaldelete.NodeOptions) error {
+		o.Drain = drain
+		return nil
+	})
+}
+
+// DeleteNodeWithGracePeriod overrides the pods' termination grace period
+// when draining, a negative period uses each pod's own
+func DeleteNodeWithGracePeriod(gracePeriod time.Duration) DeleteNodeOption {
+	return deleteNodeOptionAdapter(func(o *internaldelete.NodeOptions) error {
+		o.GracePeriod = gracePeriod
+		return nil
+	})
+}
+
+// DeleteNodeWithDrainTimeout sets how long to wait for draining to complete
+// before giving up, without deleting the node. Zero waits forever.
+func DeleteNodeWithDrainTimeout(timeout time.Duration) DeleteNodeOption {
+	return deleteNodeOptionAdapter(func(o *internaldelete.NodeOptions) error {
+		if timeout < 0 {
+			return errors.New("drain timeout must not be negative")
+		}
+		o.DrainTimeout = timeout
+		return nil
+	})
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	internalcreate "sigs.k8s.io/kind/pkg/cluster/internal/create"
+)
+
+// ConfigChange is a difference between the config a cluster was created
+// with and another config
+type ConfigChange struct {
+	// Field is the changed config field, e.g. "nodes[1].image"
+	Field string `json:"field"`
+	// Old is the value the cluster was created with, empty if it was unset
+	Old string `json:"old,omitempty"`
+	// New is the value in the other config, empty if it is unset
+	New string `json:"new,omitempty"`
+	// InPlace is true if the change can be applied to the existing cluster
+	InPlace bool `json:"inPlace"`
+	// Apply is the kind command applying the change to the existing cluster
+	// if InPlace, empty if there is nothing to apply. Otherwise the cluster
+	// has to be recreated.
+	Apply string `json:"apply,omitempty"`
+}
+
+// Diff returns the changes from the config the existing cluster name was
+// created with to the config of options, e.g. CreateWithConfigFile, and
+// whether each can be applied without recreating the cluster. The cluster
+// must have been created by a kind version that records its config.
+func (p *Provider) Diff(name string, options ...CreateOption) ([]ConfigChange, error) {
+	// apply options
+	opts := &internalcreate.ClusterOptions{}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := internalcreate.Diff(p.provider, defaultName(name), opts)
+	if err != nil {
+		return nil, err
+	}
+	diff := make([]ConfigChange, 0, len(changes))
+	for _, c := range changes {
+		diff = append(diff, ConfigChange(c))
+	}
+	return diff, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// Provider is implemented by external providers written in Go, see Main.
+// The methods implement the commands of the same names.
+type Provider interface {
+	// Info describes the provider, APIVersion is filled in by Run
+	Info() (*InfoResponse, error)
+	// Provision creates and starts the requested nodes
+	Provision(ctx context.Context, req *ProvisionRequest) error
+	// ListClusters returns the names of the clusters that have nodes
+	ListClusters() ([]string, error)
+	// ListNodes returns the nodes of the cluster, which may not be running
+	ListNodes(cluster string) ([]Node, error)
+	// DeleteNodes deletes the named nodes
+	DeleteNodes(names []string) error
+	// APIServerEndpoint returns the host:port of the cluster's API server
+	// for the host running kind
+	APIServerEndpoint(cluster string) (string, error)
+	// APIServerInternalEndpoint returns the host:port of the cluster's API
+	// server for the nodes
+	APIServerInternalEndpoint(cluster string) (string, error)
+	// Exec runs a command in a node. If the command exits non-zero the
+	// error should have an `ExitCode() int` method, like *exec.ExitError
+	// from os/exec, so that Main exits with the same code.
+	Exec(ctx context.Context, req *ExecRequest, streams cmd.IOStreams) error
+	// Logs writes the node's console or boot logs to w
+	Logs(node string, w io.Writer) error
+}
+
+// Main runs the command in os.Args with p and exits, it is meant to be
+// called from the main function of a kind-provider-<name> executable
+func Main(p Provider) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := Run(ctx, p, os.Args[1:], cmd.StandardIOStreams())
+	stop()
+	if err == nil {
+		os.Exit(0)
+	}
+	if code := exitCode(err); code > 0 {
+		os.Exit(code)
+	}
+	fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+	os.Exit(1)
+}
+
+// Run runs the command in args with p, reading requests from and writing
+// responses to streams
+func Run(ctx context.Context, p Provider, args []string, streams cmd.IOStreams) error {
+	if len(args) == 0 {
+		return errors.New("a command is required")
+	}
+	command, args := args[0], args[1:]
+	switch command {
+	case CommandInfo:
+		info, err := p.Info()
+		if err != nil {
+			return err
+		}
+		info.APIVersion = APIVersion
+		return writeResponse(streams.Out, info)
+	case CommandProvision:
+		req := &ProvisionRequest{}
+		if err := json.NewDecoder(streams.In).Decode(req); err != nil {
+			return errors.Wrap(err, "failed to decode provision request")
+		}
+		return p.Provision(ctx, req)
+	case CommandListClusters:
+		clusters, err := p.ListClusters()
+		if err != nil {
+			return err
+		}
+		if clusters == nil {
+			clusters = []string{}
+		}
+		return writeResponse(streams.Out, clusters)
+	case CommandListNodes:
+		cluster, err := singleArg(command, args)
+		if err != nil {
+			return err
+		}
+		nodes, err := p.ListNodes(cluster)
+		if err != nil {
+			return err
+		}
+		if nodes == nil {
+			nodes = []Node{}
+		}
+		return writeResponse(streams.Out, nodes)
+	case CommandDeleteNodes:
+		return p.DeleteNodes(args)
+	case CommandAPIServerEndpoint, CommandAPIServerInternalEndpoint:
+		cluster, err := singleArg(command, args)
+		if err != nil {
+			return err
+		}
+		endpoint, err := p.APIServerEndpoint(cluster)
+		if command == CommandAPIServerInternalEndpoint {
+			endpoint, err = p.APIServerInternalEndpoint(cluster)
+		}
+		if err != nil {
+			return err
+		}
+		return writeResponse(streams.Out, &EndpointResponse{Endpoint: endpoint})
+	case CommandExec:
+		req := &ExecRequest{}
+		if err := json.Unmarshal([]byte(os.Getenv(ExecRequestEnv)), req); err != nil {
+			return errors.Wrapf(err, "failed to decode exec request from $%s", ExecRequestEnv)
+		}
+		if len(req.Command) == 0 {
+			return errors.New("exec request has no command")
+		}
+		return p.Exec(ctx, req, streams)
+	case CommandLogs:
+		node, err := singleArg(command, args)
+		if err != nil {
+			return err
+		}
+		return p.Logs(node, streams.Out)
+	default:
+		return errors.Errorf("unknown command %q", command)
+	}
+}
+
+type exitCoder interface {
+	ExitCode() int
+}
+
+// exitCode returns the exit code of the command that failed with err, or 0
+// if err is not from a command
+func exitCode(err error) int {
+	found := errors.Find(err, func(e error) bool {
+		_, ok := e.(exitCoder)
+		return ok
+	})
+	if found == nil {
+		return 0
+	}
+	return found.(exitCoder).ExitCode()
+}
+
+// singleArg returns the one argument of command
+func singleArg(command string, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.Errorf("%s requires exactly one argument, got %d", command, len(args))
+	}
+	return args[0], nil
+}
+
+func writeResponse(w io.Writer, response interface{}) error {
+	return json.NewEncoder(w).Encode(response)
+}
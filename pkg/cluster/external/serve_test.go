@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+type fakeProvider struct {
+	provisioned *ProvisionRequest
+	deleted     []string
+	exec        *ExecRequest
+}
+
+func (f *fakeProvider) Info() (*InfoResponse, error) {
+	return &InfoResponse{Cgroup2: true}, nil
+}
+
+func (f *fakeProvider) Provision(ctx context.Context, req *ProvisionRequest) error {
+	f.provisioned = req
+	return nil
+}
+
+func (f *fakeProvider) ListClusters() ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) ListNodes(cluster string) ([]Node, error) {
+	return []Node{{Name: cluster + "-control-plane", Role: "control-plane", IPv4: "10.0.0.2"}}, nil
+}
+
+func (f *fakeProvider) DeleteNodes(names []string) error {
+	f.deleted = names
+	return nil
+}
+
+func (f *fakeProvider) APIServerEndpoint(cluster string) (string, error) {
+	return "127.0.0.1:6443", nil
+}
+
+func (f *fakeProvider) APIServerInternalEndpoint(cluster string) (string, error) {
+	return cluster + "-control-plane:6443", nil
+}
+
+func (f *fakeProvider) Exec(ctx context.Context, req *ExecRequest, streams cmd.IOStreams) error {
+	f.exec = req
+	_, err := io.Copy(streams.Out, streams.In)
+	return err
+}
+
+func (f *fakeProvider) Logs(node string, w io.Writer) error {
+	_, err := io.WriteString(w, "booted "+node+"\n")
+	return err
+}
+
+func runFake(t *testing.T, f *fakeProvider, stdin string, args ...string) (string, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	err := Run(context.Background(), f, args, cmd.IOStreams{
+		In:     strings.NewReader(stdin),
+		Out:    out,
+		ErrOut: io.Discard,
+	})
+	return out.String(), err
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Args        []string
+		Stdin       string
+		Expected    string
+		ExpectError bool
+	}{
+		{
+			Name:     "info sets the api version",
+			Args:     []string{CommandInfo},
+			Expected: `{"apiVersion":"kind.x-k8s.io/provider/v1alpha1","cgroup2":true}` + "\n",
+		},
+		{
+			Name:     "no clusters is an empty list",
+			Args:     []string{CommandListClusters},
+			Expected: "[]\n",
+		},
+		{
+			Name:     "list nodes",
+			Args:     []string{CommandListNodes, "kind"},
+			Expected: `[{"name":"kind-control-plane","role":"control-plane","ipv4":"10.0.0.2"}]` + "\n",
+		},
+		{
+			Name:        "list nodes requires a cluster",
+			Args:        []string{CommandListNodes},
+			ExpectError: true,
+		},
+		{
+			Name:     "internal endpoint",
+			Args:     []string{CommandAPIServerInternalEndpoint, "kind"},
+			Expected: `{"endpoint":"kind-control-plane:6443"}` + "\n",
+		},
+		{
+			Name:     "logs",
+			Args:     []string{CommandLogs, "kind-worker"},
+			Expected: "booted kind-worker\n",
+		},
+		{
+			Name:        "invalid provision request",
+			Args:        []string{CommandProvision},
+			Stdin:       "{",
+			ExpectError: true,
+		},
+		{
+			Name:        "unknown command",
+			Args:        []string{"frobnicate"},
+			ExpectError: true,
+		},
+		{
+			Name:        "no command",
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			out, err := runFake(t, &fakeProvider{}, tc.Stdin, tc.Args...)
+			assert.ExpectError(t, tc.ExpectError, err)
+			if err == nil {
+				assert.StringEqual(t, tc.Expected, out)
+			}
+		})
+	}
+}
+
+func TestRunProvisionAndDelete(t *testing.T) {
+	t.Parallel()
+	f := &fakeProvider{}
+	_, err := runFake(t, f, `{"cluster":"kind","nodes":[{"name":"kind-control-plane","role":"control-plane","image":"kindest/node"}],"networking":{"ipFamily":"ipv4"}}`, CommandProvision)
+	assert.ExpectError(t, false, err)
+	assert.DeepEqual(t, &ProvisionRequest{
+		Cluster: "kind",
+		Nodes: []NodeSpec{
+			{Name: "kind-control-plane", Role: "control-plane", Image: "kindest/node"},
+		},
+		Networking: Networking{IPFamily: "ipv4"},
+	}, f.provisioned)
+
+	_, err = runFake(t, f, "", CommandDeleteNodes, "kind-control-plane", "kind-worker")
+	assert.ExpectError(t, false, err)
+	assert.DeepEqual(t, []string{"kind-control-plane", "kind-worker"}, f.deleted)
+}
+
+func TestRunExec(t *testing.T) {
+	t.Setenv(ExecRequestEnv, `{"node":"kind-control-plane","command":["cat"],"stdin":true}`)
+	f := &fakeProvider{}
+	out, err := runFake(t, f, "hello\n", CommandExec)
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "hello\n", out)
+	assert.DeepEqual(t, &ExecRequest{
+		Node:    "kind-control-plane",
+		Command: []string{"cat"},
+		Stdin:   true,
+	}, f.exec)
+
+	t.Setenv(ExecRequestEnv, `{"node":"kind-control-plane"}`)
+	_, err = runFake(t, f, "", CommandExec)
+	assert.ExpectError(t, true, err)
+}
+
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return "exit status"
+}
+
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+	assert.DeepEqual(t, 3, exitCode(errors.Wrap(&exitError{code: 3}, "command failed")))
+	assert.DeepEqual(t, 0, exitCode(errors.New("not a command")))
+}
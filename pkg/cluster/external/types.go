@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package external defines the protocol between kind and external node
+// providers, and implements the provider side of it for providers written
+// in Go.
+//
+// An external provider is an executable named kind-provider-<name> on PATH,
+// selected with `kind --runtime <name>` or KIND_EXPERIMENTAL_PROVIDER=<name>.
+// It creates the nodes of a cluster on some substrate, e.g. virtual machines,
+// while kind bootstraps Kubernetes on them and writes the kubeconfig by
+// running commands in the nodes through the provider.
+//
+// kind runs the executable with one of the commands below as the first
+// argument. Requests are written as JSON to its standard input and responses
+// are read as JSON from its standard output. The provider reports errors by
+// exiting non-zero, with a message on standard error.
+//
+// This protocol is alpha-grade and may change between kind releases, kind
+// checks that the provider reports the same APIVersion.
+package external
+
+import (
+	osexec "os/exec"
+)
+
+// Prefix is the prefix of provider executable names, the provider named foo
+// is the executable kind-provider-foo
+const Prefix = "kind-provider-"
+
+// IsAvailable checks if the executable of the provider named name is on PATH
+func IsAvailable(name string) bool {
+	if name == "" {
+		return false
+	}
+	_, err := osexec.LookPath(Prefix + name)
+	return err == nil
+}
+
+// APIVersion is the version of the protocol, see InfoResponse
+const APIVersion = "kind.x-k8s.io/provider/v1alpha1"
+
+// The commands a provider implements
+const (
+	// CommandInfo has no request, and responds with an InfoResponse
+	CommandInfo = "info"
+	// CommandProvision is requested with a ProvisionRequest and has no
+	// response. It creates and starts the nodes, which must run the
+	// requested node image, just short of starting Kubernetes.
+	CommandProvision = "provision"
+	// CommandListClusters has no request, and responds with the names of the
+	// clusters that have nodes, as a JSON list of strings
+	CommandListClusters = "list-clusters"
+	// CommandListNodes is run with the cluster name as the second argument
+	// and responds with the cluster's nodes as a JSON list of Node
+	CommandListNodes = "list-nodes"
+	// CommandDeleteNodes is run with the names of the nodes to delete as the
+	// remaining arguments, and has no response
+	CommandDeleteNodes = "delete-nodes"
+	// CommandAPIServerEndpoint is run with the cluster name as the second
+	// argument and responds with an EndpointResponse, the host:port at
+	// which the cluster's API server is reachable from the host running kind
+	CommandAPIServerEndpoint = "api-server-endpoint"
+	// CommandAPIServerInternalEndpoint is like CommandAPIServerEndpoint, but
+	// responds with the host:port at which the nodes reach the API server
+	CommandAPIServerInternalEndpoint = "api-server-internal-endpoint"
+	// CommandExec is requested with an ExecRequest and runs a command in a
+	// node, with the provider's standard input, output and error connected to
+	// the command's. The provider exits with the command's exit code.
+	// The request is passed in the ExecRequestEnv environment variable, as
+	// standard input belongs to the command.
+	CommandExec = "exec"
+	// CommandLogs is run with the node name as the second argument, and
+	// writes the node's console or boot logs to standard output
+	CommandLogs = "logs"
+)
+
+// ExecRequestEnv is the environment variable the ExecRequest is passed in
+const ExecRequestEnv = "KIND_PROVIDER_EXEC_REQUEST"
+
+// InfoResponse describes the provider
+type InfoResponse struct {
+	// APIVersion is the protocol version the provider implements, this
+	// must be APIVersion
+	APIVersion string `json:"apiVersion"`
+	// Rootless is true if the nodes run without root on the host
+	Rootless bool `json:"rootless,omitempty"`
+	// Cgroup2 is true if the nodes use cgroup v2
+	Cgroup2 bool `json:"cgroup2,omitempty"`
+	// SupportsMemoryLimit is true if the nodes may be limited in memory
+	SupportsMemoryLimit bool `json:"supportsMemoryLimit,omitempty"`
+	// SupportsPidsLimit is true if the nodes may be limited in pids
+	SupportsPidsLimit bool `json:"supportsPidsLimit,omitempty"`
+	// SupportsCPUShares is true if the nodes may be limited in cpu shares
+	SupportsCPUShares bool `json:"supportsCPUShares,omitempty"`
+}
+
+// ProvisionRequest asks the provider to create a cluster's nodes
+type ProvisionRequest struct {
+	// Cluster is the name of the cluster, ListClusters and ListNodes must
+	// find the nodes by this name
+	Cluster string `json:"cluster"`
+	// Nodes are the nodes to create, including the external load balancer
+	// for clusters with several control plane nodes
+	Nodes []NodeSpec `json:"nodes"`
+	// Networking is the cluster's networking config
+	Networking Networking `json:"networking"`
+}
+
+// NodeSpec is a node to create
+type NodeSpec struct {
+	// Name is the name of the node, which must also be its hostname
+	Name string `json:"name"`
+	// Role is one of "control-plane", "worker" or "external-load-balancer"
+	Role string `json:"role"`
+	// Image is the container image the node runs, a kind node image or the
+	// load balancer image
+	Image string `json:"image"`
+	// Labels are the node's Kubernetes labels
+	Labels map[string]string `json:"labels,omitempty"`
+	// Env is the environment of the node's init process
+	Env map[string]string `json:"env,omitempty"`
+	// Sysctls are set in the node
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// ExtraMounts are host paths to mount in the node
+	ExtraMounts []Mount `json:"extraMounts,omitempty"`
+	// ExtraPortMappings are node ports to expose on the host
+	ExtraPortMappings []PortMapping `json:"extraPortMappings,omitempty"`
+}
+
+// Networking is the cluster's networking config.
+// The provider must expose the API server port (6443) of the external load
+// balancer node if there is one, or else of the control plane node, at the
+// APIServerAddress and APIServerPort on the host, or at an endpoint it
+// chooses if they are unset, and report it from CommandAPIServerEndpoint.
+type Networking struct {
+	// IPFamily is one of "ipv4", "ipv6" or "dual"
+	IPFamily string `json:"ipFamily"`
+	// APIServerAddress is the host address to expose the API server at
+	APIServerAddress string `json:"apiServerAddress,omitempty"`
+	// APIServerPort is the host port to expose the API server at
+	APIServerPort int32 `json:"apiServerPort,omitempty"`
+}
+
+// Mount is a host path to mount in a node
+type Mount struct {
+	HostPath      string `json:"hostPath"`
+	ContainerPath string `json:"containerPath"`
+	ReadOnly      bool   `json:"readOnly,omitempty"`
+}
+
+// PortMapping is a node port to expose on the host
+type PortMapping struct {
+	ContainerPort int32  `json:"containerPort"`
+	HostPort      int32  `json:"hostPort,omitempty"`
+	ListenAddress string `json:"listenAddress,omitempty"`
+	// Protocol is one of "TCP", "UDP" or "SCTP"
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// Node is a node of a cluster, as listed by CommandListNodes
+type Node struct {
+	// Name is the node's name, as passed to the other commands
+	Name string `json:"name"`
+	// Role is the node's role, see NodeSpec
+	Role string `json:"role"`
+	// IPv4 and IPv6 are the node's addresses, at least one must be set
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+// EndpointResponse is the response to the endpoint commands
+type EndpointResponse struct {
+	// Endpoint is host:port, IPv6 hosts are in brackets
+	Endpoint string `json:"endpoint"`
+}
+
+// ExecRequest runs a command in a node
+type ExecRequest struct {
+	// Node is the name of the node
+	Node string `json:"node"`
+	// Command is the command and its arguments
+	Command []string `json:"command"`
+	// Env is added to the command's environment, as KEY=VALUE
+	Env []string `json:"env,omitempty"`
+	// Stdin is true if the command reads standard input
+	Stdin bool `json:"stdin,omitempty"`
+	// TTY is true if the command should run in a terminal
+	TTY bool `json:"tty,omitempty"`
+	// Privileged is true if the command needs all privileges of the node,
+	// kind always sets this
+	Privileged bool `json:"privileged,omitempty"`
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certs implements renewing the certificates of a kind cluster
+package certs
+
+import (
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+// restartControlPlaneScript stops the control plane static pod containers,
+// the kubelet restarts them with the renewed certificates
+const restartControlPlaneScript = `for c in etcd kube-apiserver kube-controller-manager kube-scheduler; do
+  crictl ps --name "^${c}$" -q | xargs -r crictl stop
+done`
+
+// readyTimeout is how long to wait for each control plane node to serve
+// again after restarting it
+const readyTimeout = 2 * time.Minute
+
+// Renew renews the kubeadm managed certificates on every control plane node
+// in allNodes and restarts the control plane components to use them.
+// Nodes are renewed one at a time so multi control plane clusters stay up.
+func Renew(logger log.Logger, allNodes []nodes.Node) error {
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	if len(controlPlanes) == 0 {
+		return errors.New("no control plane nodes found")
+	}
+	for _, node := range controlPlanes {
+		logger.V(0).Infof("Renewing certificates on node %q ...", node)
+		if err := renewNode(node); err != nil {
+			return errors.Wrapf(err, "failed to renew certificates on node %q", node)
+		}
+	}
+	return nil
+}
+
+func renewNode(node nodes.Node) error {
+	rawVersion, err := nodeutils.KubeVersion(node)
+	if err != nil {
+		return err
+	}
+	kubeVersion, err := version.ParseSemantic(rawVersion)
+	if err != nil {
+		return errors.Wrap(err, "could not parse Kubernetes version")
+	}
+
+	// certs renew graduated from alpha in kubeadm v1.20
+	args := []string{"certs", "renew", "all"}
+	if kubeVersion.LessThan(version.MustParseSemantic("v1.20.0")) {
+		args = append([]string{"alpha"}, args...)
+	}
+	if err := node.Command("kubeadm", args...).Run(); err != nil {
+		return errors.Wrap(err, "failed to run kubeadm certs renew")
+	}
+
+	// the control plane components only read certificates on startup
+	if err := node.Command("bash", "-c", restartControlPlaneScript).Run(); err != nil {
+		return errors.Wrap(err, "failed to restart the control plane")
+	}
+	if err := node.Command("systemctl", "restart", "kubelet").Run(); err != nil {
+		return errors.Wrap(err, "failed to restart kubelet")
+	}
+
+	// wait for the API server to serve with the renewed certificates
+	until := time.Now().Add(readyTimeout)
+	for until.After(time.Now()) {
+		lines, err := exec.OutputLines(node.Command(
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "--raw", "/readyz",
+		))
+		if err == nil && len(lines) > 0 && strings.TrimSpace(lines[0]) == "ok" {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return errors.New("timed out waiting for the API server to become ready")
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package actions
 
 import (
+	"context"
 	"sync"
 
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
@@ -35,6 +36,9 @@ type Action interface {
 
 // ActionContext is data supplied to all actions
 type ActionContext struct {
+	// Context is cancelled when cluster creation should stop, actions
+	// should use it for long running commands
+	Context  context.Context
 	Logger   log.Logger
 	Status   *cli.Status
 	Config   *config.Cluster
@@ -44,12 +48,14 @@ type ActionContext struct {
 
 // NewActionContext returns a new ActionContext
 func NewActionContext(
+	ctx context.Context,
 	logger log.Logger,
 	status *cli.Status,
 	provider providers.Provider,
 	cfg *config.Cluster,
 ) *ActionContext {
 	return &ActionContext{
+		Context:  ctx,
 		Logger:   logger,
 		Status:   status,
 		Provider: provider,
@@ -58,6 +64,21 @@ func NewActionContext(
 	}
 }
 
+// NewNodesActionContext returns a new ActionContext for running actions
+// against only nodes, e.g. when adding them to an existing cluster
+func NewNodesActionContext(
+	ctx context.Context,
+	logger log.Logger,
+	status *cli.Status,
+	provider providers.Provider,
+	cfg *config.Cluster,
+	n []nodes.Node,
+) *ActionContext {
+	ac := NewActionContext(ctx, logger, status, provider, cfg)
+	ac.cache.setNodes(n)
+	return ac
+}
+
 type cachedData struct {
 	mu    sync.RWMutex
 	nodes []nodes.Node
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capturediagnostics implements the action to capture OOM killer
+// events and core dumps on the nodes
+package capturediagnostics
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// Dir is where each node captures diagnostics, under /var/log so that
+// `kind export logs` collects them
+const Dir = "/var/log/kind-diagnostics"
+
+const (
+	oomUnitName = "kind-oom-events.service"
+	oomUnitPath = "/etc/systemd/system/" + oomUnitName
+	coresDir    = Dir + "/cores"
+)
+
+// the kernel log is shared with the host, only follow new messages so that
+// OOM kills from before the node was created are not recorded
+const oomUnit = `[Unit]
+Description=kind OOM event recorder
+
+[Service]
+ExecStart=/bin/sh -c 'dmesg --follow-new --time-format=iso | grep --line-buffered -e "invoked oom-killer" -e "Out of memory" -e "oom-kill:" -e "Killed process" >> ` + Dir + `/oom-events.log'
+Restart=always
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// coreDumpDropIns are the drop-ins enabling core dumps for the node
+// components, the kernel writes dumps for a relative core_pattern to the
+// crashing process's working directory, and Go programs only dump core
+// with GOTRACEBACK=crash
+var coreDumpDropIns = []string{
+	"/etc/systemd/system/kubelet.service.d/30-kind-core-dumps.conf",
+	"/etc/systemd/system/containerd.service.d/30-kind-core-dumps.conf",
+}
+
+const coreDumpDropIn = `[Service]
+LimitCORE=infinity
+WorkingDirectory=` + coresDir + `
+Environment="GOTRACEBACK=crash"
+`
+
+type action struct {
+	diagnostics config.Diagnostics
+}
+
+// NewAction returns a new action for capturing diagnostics
+func NewAction(diagnostics config.Diagnostics) actions.Action {
+	return &action{
+		diagnostics: diagnostics,
+	}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Capturing diagnostics 🩺")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+
+	fns := []func() error{}
+	for _, node := range kubeNodes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			return errors.Wrapf(a.configure(node), "failed to capture diagnostics on node %q", node)
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return err
+	}
+
+	// core_pattern is the same for every node and the host
+	if a.diagnostics.CoreDumps && len(kubeNodes) > 0 {
+		lines, err := exec.OutputLines(kubeNodes[0].Command("cat", "/proc/sys/kernel/core_pattern"))
+		if err == nil && len(lines) == 1 && !relativeCorePattern(lines[0]) {
+			ctx.Logger.Warnf("The host's kernel.core_pattern is %q, core dumps will not be written to %s", lines[0], coresDir)
+		}
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
+
+func (a *action) configure(node nodes.Node) error {
+	if err := node.Command("mkdir", "-p", coresDir).Run(); err != nil {
+		return err
+	}
+	if a.diagnostics.OOMEvents {
+		if err := nodeutils.WriteFile(node, oomUnitPath, oomUnit); err != nil {
+			return err
+		}
+	}
+	if a.diagnostics.CoreDumps {
+		for _, dropIn := range coreDumpDropIns {
+			if err := nodeutils.WriteFile(node, dropIn, coreDumpDropIn); err != nil {
+				return err
+			}
+		}
+	}
+	if err := node.Command("systemctl", "daemon-reload").Run(); err != nil {
+		return err
+	}
+	if a.diagnostics.OOMEvents {
+		if err := node.Command("systemctl", "enable", "--now", oomUnitName).Run(); err != nil {
+			return err
+		}
+	}
+	// kubeadm restarts the kubelet, containerd needs restarting to pick up
+	// the drop-in, skip if containerd is not running
+	if a.diagnostics.CoreDumps {
+		return node.Command("bash", "-c", `! pgrep --exact containerd || systemctl restart containerd`).Run()
+	}
+	return nil
+}
+
+// relativeCorePattern returns whether the kernel core_pattern writes dumps
+// relative to the crashing process's working directory, rather than to an
+// absolute path or piping them to a program such as systemd-coredump
+func relativeCorePattern(pattern string) bool {
+	pattern = strings.TrimSpace(pattern)
+	return pattern != "" && !strings.HasPrefix(pattern, "|") && !strings.HasPrefix(pattern, "/")
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capturediagnostics
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestRelativeCorePattern(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Pattern  string
+		Expected bool
+	}{
+		{
+			Name:     "default",
+			Pattern:  "core",
+			Expected: true,
+		},
+		{
+			Name:     "relative with specifiers",
+			Pattern:  "core.%e.%p\n",
+			Expected: true,
+		},
+		{
+			Name:    "systemd-coredump",
+			Pattern: "|/lib/systemd/systemd-coredump %P %u %g %s %t 9223372036854775808 %h",
+		},
+		{
+			Name:    "absolute",
+			Pattern: "/var/crash/core.%p",
+		},
+		{
+			Name: "empty",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.BoolEqual(t, tc.Expected, relativeCorePattern(tc.Pattern))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeadm"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/sets"
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+// podSecurityExemptNamespaces are the namespaces of the components kind
+// installs, which need privileges the baseline and restricted levels deny
+var podSecurityExemptNamespaces = []string{
+	"kube-system",
+	"local-path-storage",
+	"local-path-shared-storage",
+	"metallb-system",
+}
+
+// admissionConfig returns a kube-apiserver AdmissionConfiguration with the
+// PodSecurity admission defaults and exemptions for Kubernetes kubeVersion
+func admissionConfig(podSecurity config.PodSecurity, kubeVersion *version.Version) (string, error) {
+	// the PodSecurityConfiguration API went GA with the plugin in 1.25
+	podSecurityAPIVersion := "pod-security.admission.config.k8s.io/v1"
+	if kubeVersion.LessThan(version.MustParseSemantic("v1.23.0")) {
+		return "", errors.New("podSecurity requires Kubernetes v1.23+")
+	} else if kubeVersion.LessThan(version.MustParseSemantic("v1.25.0")) {
+		podSecurityAPIVersion = "pod-security.admission.config.k8s.io/v1beta1"
+	}
+
+	var b strings.Builder
+	b.WriteString("apiVersion: apiserver.config.k8s.io/v1\n")
+	b.WriteString("kind: AdmissionConfiguration\n")
+	b.WriteString("plugins:\n")
+	b.WriteString("- name: PodSecurity\n")
+	b.WriteString("  configuration:\n")
+	fmt.Fprintf(&b, "    apiVersion: %s\n", podSecurityAPIVersion)
+	b.WriteString("    kind: PodSecurityConfiguration\n")
+	b.WriteString("    defaults:\n")
+	modes := []string{"enforce", "audit", "warn"}
+	for i, level := range []config.PodSecurityLevel{podSecurity.Enforce, podSecurity.Audit, podSecurity.Warn} {
+		if level == "" {
+			level = config.PrivilegedPodSecurityLevel
+		}
+		fmt.Fprintf(&b, "      %s: %q\n", modes[i], level)
+		fmt.Fprintf(&b, "      %s-version: \"latest\"\n", modes[i])
+	}
+	b.WriteString("    exemptions:\n")
+	writeList := func(name string, values []string) {
+		if len(values) == 0 {
+			fmt.Fprintf(&b, "      %s: []\n", name)
+			return
+		}
+		fmt.Fprintf(&b, "      %s:\n", name)
+		for _, value := range values {
+			fmt.Fprintf(&b, "      - %q\n", value)
+		}
+	}
+	writeList("usernames", podSecurity.Exemptions.Usernames)
+	writeList("runtimeClasses", podSecurity.Exemptions.RuntimeClasses)
+	namespaces := append([]string{}, podSecurityExemptNamespaces...)
+	seen := sets.NewString(namespaces...)
+	for _, namespace := range podSecurity.Exemptions.Namespaces {
+		if !seen.Has(namespace) {
+			seen.Insert(namespace)
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	writeList("namespaces", namespaces)
+	return b.String(), nil
+}
+
+// writeAdmissionConfig writes the AdmissionConfiguration for each control
+// plane node's Kubernetes version to the node
+func writeAdmissionConfig(podSecurity config.PodSecurity, allNodes []nodes.Node) error {
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	fns := make([]func() error, 0, len(controlPlanes))
+	for _, node := range controlPlanes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			kubeVersion, err := nodeutils.KubeVersion(node)
+			if err != nil {
+				return errors.Wrap(err, "failed to get kubernetes version from node")
+			}
+			ver, err := version.ParseGeneric(kubeVersion)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse kubernetes version %q", kubeVersion)
+			}
+			contents, err := admissionConfig(podSecurity, ver)
+			if err != nil {
+				return err
+			}
+			if err := nodeutils.WriteFile(node, kubeadm.AdmissionConfigPath, contents); err != nil {
+				return errors.Wrapf(err, "failed to write admission config to %s", node.String())
+			}
+			return nil
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+func TestAdmissionConfig(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		PodSecurity config.PodSecurity
+		Version     string
+		Expected    string
+		ExpectError bool
+	}{
+		{
+			Name: "restricted with exemptions",
+			PodSecurity: config.PodSecurity{
+				Enforce: config.BaselinePodSecurityLevel,
+				Warn:    config.RestrictedPodSecurityLevel,
+				Exemptions: config.PodSecurityExemptions{
+					Usernames:  []string{"system:serviceaccount:ci:deployer"},
+					Namespaces: []string{"ingress-nginx", "kube-system"},
+				},
+			},
+			Version: "v1.31.0",
+			Expected: `apiVersion: apiserver.config.k8s.io/v1
+kind: AdmissionConfiguration
+plugins:
+- name: PodSecurity
+  configuration:
+    apiVersion: pod-security.admission.config.k8s.io/v1
+    kind: PodSecurityConfiguration
+    defaults:
+      enforce: "baseline"
+      enforce-version: "latest"
+      audit: "privileged"
+      audit-version: "latest"
+      warn: "restricted"
+      warn-version: "latest"
+    exemptions:
+      usernames:
+      - "system:serviceaccount:ci:deployer"
+      runtimeClasses: []
+      namespaces:
+      - "kube-system"
+      - "local-path-storage"
+      - "local-path-shared-storage"
+      - "metallb-system"
+      - "ingress-nginx"
+`,
+		},
+		{
+			Name: "v1beta1 before Kubernetes v1.25",
+			PodSecurity: config.PodSecurity{
+				Audit: config.RestrictedPodSecurityLevel,
+			},
+			Version: "v1.24.7",
+			Expected: `apiVersion: apiserver.config.k8s.io/v1
+kind: AdmissionConfiguration
+plugins:
+- name: PodSecurity
+  configuration:
+    apiVersion: pod-security.admission.config.k8s.io/v1beta1
+    kind: PodSecurityConfiguration
+    defaults:
+      enforce: "privileged"
+      enforce-version: "latest"
+      audit: "restricted"
+      audit-version: "latest"
+      warn: "privileged"
+      warn-version: "latest"
+    exemptions:
+      usernames: []
+      runtimeClasses: []
+      namespaces:
+      - "kube-system"
+      - "local-path-storage"
+      - "local-path-shared-storage"
+      - "metallb-system"
+`,
+		},
+		{
+			Name: "unsupported before Kubernetes v1.23",
+			PodSecurity: config.PodSecurity{
+				Enforce: config.RestrictedPodSecurityLevel,
+			},
+			Version:     "v1.22.15",
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := admissionConfig(tc.PodSecurity, version.MustParseSemantic(tc.Version))
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.StringEqual(t, tc.Expected, actual)
+		})
+	}
+}
@@ -18,9 +18,10 @@ limitations under the License.
 package config
 
 import (
-	"bytes"
 	"fmt"
 	"net"
+	"os"
+	"path"
 	"strings"
 
 	"sigs.k8s.io/kind/pkg/cluster/constants"
@@ -67,21 +68,13 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 	fns := []func() error{}
 
 	provider := fmt.Sprintf("%s", ctx.Provider)
-	configData := kubeadm.ConfigData{
-		NodeProvider:         provider,
-		ClusterName:          ctx.Config.Name,
-		ControlPlaneEndpoint: controlPlaneEndpoint,
-		APIBindPort:          common.APIServerInternalPort,
-		APIServerAddress:     ctx.Config.Networking.APIServerAddress,
-		Token:                kubeadm.Token,
-		PodSubnet:            ctx.Config.Networking.PodSubnet,
-		KubeProxyMode:        string(ctx.Config.Networking.KubeProxyMode),
-		ServiceSubnet:        ctx.Config.Networking.ServiceSubnet,
-		ControlPlane:         true,
-		IPFamily:             ctx.Config.Networking.IPFamily,
-		FeatureGates:         ctx.Config.FeatureGates,
-		RuntimeConfig:        ctx.Config.RuntimeConfig,
-		RootlessProvider:     providerInfo.Rootless,
+	configData := ClusterConfigData(ctx.Config, provider, controlPlaneEndpoint, providerInfo.Rootless)
+
+	// read any kubeadm component patches up front, these are the same for
+	// every node
+	kubeadmPatches, err := kubeadmPatchFiles(kubeadm.ComponentPatches(ctx.Config))
+	if err != nil {
+		return err
 	}
 
 	kubeadmConfigPlusPatches := func(node nodes.Node, data kubeadm.ConfigData) func() error {
@@ -94,6 +87,9 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 			}
 
 			ctx.Logger.V(2).Infof("Using the following kubeadm config for node %s:\n%s", node.String(), kubeadmConfig)
+			if err := writeKubeadmPatches(kubeadmPatches, node); err != nil {
+				return err
+			}
 			return writeKubeadmConfig(kubeadmConfig, node)
 		}
 	}
@@ -104,6 +100,17 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 		return err
 	}
 
+	// nodes may use different images, e.g. to test version skew, so check
+	// that kubeadm can create a cluster from them before it runs
+	versions, err := validateVersionSkew(kubeNodes)
+	if err != nil {
+		return err
+	}
+	// kubeadm silently ignores patches for targets it does not support yet
+	if err := checkKubeadmPatchTargets(ctx.Config.KubeadmPatches, versions); err != nil {
+		return err
+	}
+
 	for _, node := range kubeNodes {
 		node := node             // capture loop variable
 		configData := configData // copy config data
@@ -115,43 +122,97 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 		return err
 	}
 
-	// if we have containerd config, patch all the nodes concurrently
-	if len(ctx.Config.ContainerdConfigPatches) > 0 || len(ctx.Config.ContainerdConfigPatchesJSON6902) > 0 {
-		fns := make([]func() error, len(kubeNodes))
-		for i, node := range kubeNodes {
-			node := node // capture loop variable
-			fns[i] = func() error {
-				// read and patch the config
-				const containerdConfigPath = "/etc/containerd/config.toml"
-				var buff bytes.Buffer
-				if err := node.Command("cat", containerdConfigPath).SetStdout(&buff).Run(); err != nil {
-					return errors.Wrap(err, "failed to read containerd config from node")
-				}
-				patched, err := patch.TOML(buff.String(), ctx.Config.ContainerdConfigPatches, ctx.Config.ContainerdConfigPatchesJSON6902)
-				if err != nil {
-					return errors.Wrap(err, "failed to patch containerd config")
-				}
-				if err := nodeutils.WriteFile(node, containerdConfigPath, patched); err != nil {
-					return errors.Wrap(err, "failed to write patched containerd config")
-				}
-				// restart containerd now that we've re-configured it
-				// skip if containerd is not running
-				if err := node.Command("bash", "-c", `! pgrep --exact containerd || systemctl restart containerd`).Run(); err != nil {
-					return errors.Wrap(err, "failed to restart containerd after patching config")
-				}
-				return nil
-			}
+	// if we have a custom CA, write it where kubeadm init will pick it up,
+	// kubeadminit copies it to any other control plane nodes
+	if ctx.Config.Certificates.CACertFile != "" {
+		node, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+		if err != nil {
+			return err
 		}
-		if err := errors.UntilErrorConcurrent(fns); err != nil {
+		if err := writeCA(node, ctx.Config.Certificates.CACertFile, ctx.Config.Certificates.CAKeyFile); err != nil {
 			return err
 		}
 	}
 
+	// write the encryption at rest configuration the kube-apiserver reads
+	if ctx.Config.EncryptionAtRest.Provider != "" {
+		if err := writeEncryptionConfig(ctx.Config.EncryptionAtRest, allNodes); err != nil {
+			return err
+		}
+	}
+
+	// write the pod security admission defaults the kube-apiserver reads
+	if config.ClusterHasPodSecurity(ctx.Config) {
+		if err := writeAdmissionConfig(ctx.Config.PodSecurity, allNodes); err != nil {
+			return err
+		}
+	}
+
+	// configure the container runtime on all the nodes concurrently
+	fns = make([]func() error, 0, len(kubeNodes))
+	for _, node := range kubeNodes {
+		node := node // capture loop variable
+		configNode, err := configNodeFor(ctx.Config, node)
+		if err != nil {
+			return err
+		}
+		fns = append(fns, func() error {
+			if err := configureRuntime(ctx, node, configNode); err != nil {
+				return err
+			}
+			if configNode.Role != config.ControlPlaneRole {
+				return nil
+			}
+			return pullComponentImages(ctx, node)
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return err
+	}
+
 	// mark success
 	ctx.Status.End(true)
 	return nil
 }
 
+// ClusterConfigData returns the kubeadm config data shared by all of the
+// cluster's nodes, the node specific fields are filled in per node
+func ClusterConfigData(cfg *config.Cluster, provider, controlPlaneEndpoint string, rootless bool) kubeadm.ConfigData {
+	data := kubeadm.ConfigData{
+		NodeProvider:                 provider,
+		ClusterName:                  cfg.Name,
+		ControlPlaneEndpoint:         controlPlaneEndpoint,
+		APIBindPort:                  common.APIServerInternalPort,
+		APIServerAddress:             cfg.Networking.APIServerAddress,
+		APIServerAdditionalAddresses: cfg.Networking.APIServerAdditionalAddresses,
+		APIServerCertSANs:            cfg.Networking.APIServerCertSANs,
+		Token:                        kubeadm.Token,
+		PodSubnet:                    cfg.Networking.PodSubnet,
+		KubeProxyMode:                string(cfg.Networking.KubeProxyMode),
+		ServiceSubnet:                cfg.Networking.ServiceSubnet,
+		NodeCIDRMaskSizeIPv4:         cfg.Networking.NodeCIDRMaskSizeIPv4,
+		NodeCIDRMaskSizeIPv6:         cfg.Networking.NodeCIDRMaskSizeIPv6,
+		ControlPlane:                 true,
+		IPFamily:                     cfg.Networking.IPFamily,
+		FeatureGates:                 cfg.FeatureGates,
+		RuntimeConfig:                cfg.RuntimeConfig,
+		SwapBehavior:                 string(cfg.SwapBehavior),
+		RootlessProvider:             rootless,
+		ExtraInitSkipPhases:          cfg.KubeadmInitSkipPhases,
+		CertificateValidityPeriod:    cfg.Certificates.CertificateValidityPeriod,
+		CACertificateValidityPeriod:  cfg.Certificates.CACertificateValidityPeriod,
+		EncryptionProvider:           string(cfg.EncryptionAtRest.Provider),
+		EncryptionKMSEndpoint:        cfg.EncryptionAtRest.KMSEndpoint,
+		PodSecurity:                  config.ClusterHasPodSecurity(cfg),
+	}
+	// kubeadm component patches are written to the same directory on every
+	// node
+	if len(kubeadm.ComponentPatches(cfg)) > 0 {
+		data.PatchesDirectory = kubeadm.PatchesDir
+	}
+	return data
+}
+
 // getKubeadmConfig generates the kubeadm config contents for the cluster
 // by running data through the template and applying patches as needed.
 func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.Node, provider string) (path string, err error) {
@@ -162,21 +223,18 @@ func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.N
 	}
 	data.KubernetesVersion = kubeVersion
 
-	// TODO: gross hack!
-	// identify node in config by matching name (since these are named in order)
-	// we should really just streamline the bootstrap code and maintain
-	// this mapping ... something for the next major refactor
-	var configNode *config.Node
-	namer := common.MakeNodeNamer("")
-	for i := range cfg.Nodes {
-		n := &cfg.Nodes[i]
-		nodeSuffix := namer(string(n.Role))
-		if strings.HasSuffix(node.String(), nodeSuffix) {
-			configNode = n
-		}
+	// use the CRI socket of the runtime in the node image
+	runtime, err := nodeutils.ContainerRuntime(node)
+	if err != nil {
+		return "", err
 	}
-	if configNode == nil {
-		return "", errors.Errorf("failed to match node %q to config", node.String())
+	if runtime == "crio" {
+		data.CRISocket = kubeadm.CRIOCRISocket
+	}
+
+	configNode, err := configNodeFor(cfg, node)
+	if err != nil {
+		return "", err
 	}
 
 	// get the node ip address
@@ -193,14 +251,13 @@ func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.N
 		}
 		data.NodeAddress = nodeAddressIPv6
 		if cfg.Networking.IPFamily == config.DualStackFamily {
-			// order matters since the nodeAddress will be used later to configure the apiserver advertise address
-			// Ref: #2484
-			primaryServiceSubnet := strings.Split(cfg.Networking.ServiceSubnet, ",")[0]
-			ip, _, err := net.ParseCIDR(primaryServiceSubnet)
-			if err != nil {
-				return "", fmt.Errorf("failed to parse primary Service Subnet %s (%s): %w", primaryServiceSubnet, cfg.Networking.ServiceSubnet, err)
+			if ip := net.ParseIP(nodeAddress); ip.To4() == nil {
+				return "", errors.Errorf("failed to get IPv4 address for node %s; is %s configured to use IPv4 correctly?", node.String(), provider)
 			}
-			if ip.To4() != nil {
+			// order matters since the nodeAddress will be used later to configure the apiserver advertise address,
+			// it must match the primary family of the service and pod subnets
+			// Ref: #2484
+			if config.ClusterPrimaryIPFamily(cfg) == config.IPv4Family {
 				data.NodeAddress = fmt.Sprintf("%s,%s", nodeAddress, nodeAddressIPv6)
 			} else {
 				data.NodeAddress = fmt.Sprintf("%s,%s", nodeAddressIPv6, nodeAddress)
@@ -208,10 +265,26 @@ func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.N
 		}
 	}
 
-	// configure the node labels
-	if len(configNode.Labels) > 0 {
-		data.NodeLabels = hashMapLabelsToCommaSeparatedLabels(configNode.Labels)
+	// configure the kubelet resource reservations
+	data.KubeReserved, data.EvictionHard, err = kubeletReservations(node, configNode)
+	if err != nil {
+		return "", err
+	}
+
+	return KubeadmConfig(cfg, data, configNode)
+}
+
+// KubeadmConfig generates the kubeadm config contents for configNode, given
+// data with the fields that depend on the node container filled in:
+// KubernetesVersion, NodeName, NodeAddress, CRISocket, KubeReserved and
+// EvictionHard
+func KubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, configNode *config.Node) (string, error) {
+	// configure the node labels, and the simulated platform
+	if labels := nodeLabels(configNode); len(labels) > 0 {
+		data.NodeLabels = hashMapLabelsToCommaSeparatedLabels(labels)
 	}
+	data.NodeTaints = strings.Join(configNode.Simulate.Taints, ",")
+	data.SystemReserved = configNode.SystemReserved
 
 	// set the node role
 	data.ControlPlane = string(configNode.Role) == constants.ControlPlaneNodeRoleValue
@@ -222,8 +295,13 @@ func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.N
 		return "", err
 	}
 
+	// apply cluster-level patches first, converted to the kubeadm API
+	// version of the generated config
 	clusterPatches, clusterJSONPatches := allPatchesFromConfig(cfg)
-	// apply cluster-level patches first
+	clusterPatches, clusterJSONPatches, err = kubeadm.ConvertPatches(cf, clusterPatches, clusterJSONPatches)
+	if err != nil {
+		return "", err
+	}
 	patchedConfig, err := patch.KubeYAML(cf, clusterPatches, clusterJSONPatches)
 	if err != nil {
 		return "", err
@@ -231,7 +309,11 @@ func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.N
 
 	// if needed, apply current node's patches
 	if len(configNode.KubeadmConfigPatches) > 0 || len(configNode.KubeadmConfigPatchesJSON6902) > 0 {
-		patchedConfig, err = patch.KubeYAML(patchedConfig, configNode.KubeadmConfigPatches, configNode.KubeadmConfigPatchesJSON6902)
+		nodePatches, nodeJSONPatches, err := kubeadm.ConvertPatches(patchedConfig, configNode.KubeadmConfigPatches, configNode.KubeadmConfigPatchesJSON6902)
+		if err != nil {
+			return "", err
+		}
+		patchedConfig, err = patch.KubeYAML(patchedConfig, nodePatches, nodeJSONPatches)
 		if err != nil {
 			return "", err
 		}
@@ -241,6 +323,27 @@ func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.N
 	return removeMetadata(patchedConfig), nil
 }
 
+// configNodeFor returns the config of node
+func configNodeFor(cfg *config.Cluster, node nodes.Node) (*config.Node, error) {
+	// TODO: gross hack!
+	// identify node in config by matching name (since these are named in order)
+	// we should really just streamline the bootstrap code and maintain
+	// this mapping ... something for the next major refactor
+	var configNode *config.Node
+	namer := common.MakeNodeNamer("")
+	for i := range cfg.Nodes {
+		n := &cfg.Nodes[i]
+		nodeSuffix := common.NodeName(namer, n)
+		if strings.HasSuffix(node.String(), nodeSuffix) {
+			configNode = n
+		}
+	}
+	if configNode == nil {
+		return nil, errors.Errorf("failed to match node %q to config", node.String())
+	}
+	return configNode, nil
+}
+
 // trims out the metadata.name we put in the config for kustomize matching,
 // kubeadm will complain about this otherwise
 func removeMetadata(kustomized string) string {
@@ -269,6 +372,73 @@ func writeKubeadmConfig(kubeadmConfig string, node nodes.Node) error {
 	return nil
 }
 
+// writeCA copies the CA certificate and key files from the host to node
+func writeCA(node nodes.Node, certFile, keyFile string) error {
+	for _, f := range []struct{ src, dest string }{
+		{certFile, "/etc/kubernetes/pki/ca.crt"},
+		{keyFile, "/etc/kubernetes/pki/ca.key"},
+	} {
+		contents, err := os.ReadFile(f.src)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read CA file %q", f.src)
+		}
+		if err := nodeutils.WriteFile(node, f.dest, string(contents)); err != nil {
+			return errors.Wrapf(err, "failed to write %q to node", f.dest)
+		}
+	}
+	return nil
+}
+
+// kubeadmPatchFiles returns the kubeadm component patches from the config
+// as a map of file name to contents, reading any patches from disk.
+// Files are named target[suffix][+patchtype].extension as kubeadm expects,
+// with the index as the suffix so patches are applied in the order listed.
+func kubeadmPatchFiles(patches []config.KubeadmPatch) (map[string]string, error) {
+	files := make(map[string]string, len(patches))
+	for i, p := range patches {
+		contents := p.Patch
+		if p.Path != "" {
+			raw, err := os.ReadFile(p.Path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read kubeadm patch %q", p.Path)
+			}
+			contents = string(raw)
+		}
+		name := fmt.Sprintf("%s%03d+%s.yaml", p.Target, i, p.PatchType)
+		files[name] = contents
+	}
+	return files, nil
+}
+
+// writeKubeadmPatches writes the kubeadm component patches to the node
+func writeKubeadmPatches(files map[string]string, node nodes.Node) error {
+	for name, contents := range files {
+		if err := nodeutils.WriteFile(node, path.Join(kubeadm.PatchesDir, name), contents); err != nil {
+			return errors.Wrap(err, "failed to copy kubeadm patches to node")
+		}
+	}
+	return nil
+}
+
+// nodeLabels returns the labels for configNode, including the labels for
+// the simulated platform, the node's own labels take precedence
+func nodeLabels(configNode *config.Node) map[string]string {
+	labels := map[string]string{}
+	// the kubelet also sets the deprecated beta labels, keep them consistent
+	if os := configNode.Simulate.OS; os != "" {
+		labels["kubernetes.io/os"] = os
+		labels["beta.kubernetes.io/os"] = os
+	}
+	if arch := configNode.Simulate.Arch; arch != "" {
+		labels["kubernetes.io/arch"] = arch
+		labels["beta.kubernetes.io/arch"] = arch
+	}
+	for key, value := range configNode.Labels {
+		labels[key] = value
+	}
+	return labels
+}
+
 // hashMapLabelsToCommaSeparatedLabels converts labels in hashmap form to labels in a comma-separated string form like "key1=value1,key2=value2"
 func hashMapLabelsToCommaSeparatedLabels(labels map[string]string) string {
 	output := ""
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeadm"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// encryptionConfig returns a kube-apiserver EncryptionConfiguration for
+// encryption, generating a key for the providers that need one from random.
+// identity is always the last provider so unencrypted data can still be read.
+func encryptionConfig(encryption config.EncryptionAtRest, random io.Reader) (string, error) {
+	var b strings.Builder
+	b.WriteString("apiVersion: apiserver.config.k8s.io/v1\n")
+	b.WriteString("kind: EncryptionConfiguration\n")
+	b.WriteString("resources:\n")
+	b.WriteString("- resources:\n")
+	for _, resource := range encryption.Resources {
+		fmt.Fprintf(&b, "  - %q\n", resource)
+	}
+	b.WriteString("  providers:\n")
+	switch encryption.Provider {
+	case config.KMSEncryptionProvider:
+		b.WriteString("  - kms:\n")
+		b.WriteString("      apiVersion: v2\n")
+		b.WriteString("      name: kind\n")
+		fmt.Fprintf(&b, "      endpoint: %q\n", encryption.KMSEndpoint)
+		b.WriteString("      timeout: 3s\n")
+	case config.AESCBCEncryptionProvider, config.AESGCMEncryptionProvider, config.SecretboxEncryptionProvider:
+		key := make([]byte, 32)
+		if _, err := io.ReadFull(random, key); err != nil {
+			return "", errors.Wrap(err, "failed to generate encryption key")
+		}
+		fmt.Fprintf(&b, "  - %s:\n", encryption.Provider)
+		b.WriteString("      keys:\n")
+		b.WriteString("      - name: key1\n")
+		fmt.Fprintf(&b, "        secret: %s\n", base64.StdEncoding.EncodeToString(key))
+	default:
+		return "", errors.Errorf("unknown encryption provider %q", encryption.Provider)
+	}
+	b.WriteString("  - identity: {}\n")
+	return b.String(), nil
+}
+
+// writeEncryptionConfig writes the same EncryptionConfiguration to every
+// control plane node, they must share keys to read each other's writes
+func writeEncryptionConfig(encryption config.EncryptionAtRest, allNodes []nodes.Node) error {
+	contents, err := encryptionConfig(encryption, rand.Reader)
+	if err != nil {
+		return err
+	}
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	fns := make([]func() error, 0, len(controlPlanes))
+	for _, node := range controlPlanes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			if err := nodeutils.WriteFile(node, kubeadm.EncryptionConfigPath, contents); err != nil {
+				return errors.Wrapf(err, "failed to write encryption config to %s", node.String())
+			}
+			return nil
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestEncryptionConfig(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Encryption  config.EncryptionAtRest
+		Expected    string
+		ExpectError bool
+	}{
+		{
+			Name: "aescbc",
+			Encryption: config.EncryptionAtRest{
+				Provider:  config.AESCBCEncryptionProvider,
+				Resources: []string{"secrets", "configmaps"},
+			},
+			Expected: `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- resources:
+  - "secrets"
+  - "configmaps"
+  providers:
+  - aescbc:
+      keys:
+      - name: key1
+        secret: AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=
+  - identity: {}
+`,
+		},
+		{
+			Name: "kms",
+			Encryption: config.EncryptionAtRest{
+				Provider:    config.KMSEncryptionProvider,
+				Resources:   []string{"secrets"},
+				KMSEndpoint: "unix:///var/run/kmsplugin/socket.sock",
+			},
+			Expected: `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- resources:
+  - "secrets"
+  providers:
+  - kms:
+      apiVersion: v2
+      name: kind
+      endpoint: "unix:///var/run/kmsplugin/socket.sock"
+      timeout: 3s
+  - identity: {}
+`,
+		},
+		{
+			Name: "unknown provider",
+			Encryption: config.EncryptionAtRest{
+				Provider:  "rot13",
+				Resources: []string{"secrets"},
+			},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := encryptionConfig(tc.Encryption, bytes.NewReader(make([]byte, 32)))
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.StringEqual(t, tc.Expected, actual)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+// kubeletConfigurationPatchVersion is the first Kubernetes version whose
+// kubeadm applies patches to the kubeletconfiguration target, older
+// versions silently ignore them
+var kubeletConfigurationPatchVersion = version.MustParseSemantic("v1.25.0")
+
+// checkKubeadmPatchTargets returns an error if the kubeadm of any of the
+// nodes would ignore one of the patches
+func checkKubeadmPatchTargets(patches []config.KubeadmPatch, nodes []nodeVersion) error {
+	for _, p := range patches {
+		if p.Target != config.KubeadmPatchTargetKubeletConfiguration {
+			continue
+		}
+		for _, n := range nodes {
+			if n.version.LessThan(kubeletConfigurationPatchVersion) {
+				return errors.Errorf(
+					"kubeadmPatches for %s require Kubernetes %s or newer, but node %q runs %s",
+					p.Target, kubeletConfigurationPatchVersion, n.name, n.version,
+				)
+			}
+		}
+	}
+	return nil
+}
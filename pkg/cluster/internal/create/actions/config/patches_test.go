@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+func TestCheckKubeadmPatchTargets(t *testing.T) {
+	t.Parallel()
+	node := func(name, v string) nodeVersion {
+		return nodeVersion{name: name, version: version.MustParseSemantic(v)}
+	}
+	kubeletPatch := config.KubeadmPatch{Target: config.KubeadmPatchTargetKubeletConfiguration}
+	apiServerPatch := config.KubeadmPatch{Target: config.KubeadmPatchTargetKubeAPIServer}
+	cases := []struct {
+		Name        string
+		Patches     []config.KubeadmPatch
+		Nodes       []nodeVersion
+		ExpectError bool
+	}{
+		{
+			Name:    "kubeletconfiguration on new nodes",
+			Patches: []config.KubeadmPatch{kubeletPatch},
+			Nodes:   []nodeVersion{node("cp1", "v1.25.0"), node("w1", "v1.32.0")},
+		},
+		{
+			Name:        "kubeletconfiguration on an old node",
+			Patches:     []config.KubeadmPatch{apiServerPatch, kubeletPatch},
+			Nodes:       []nodeVersion{node("cp1", "v1.25.3"), node("w1", "v1.24.7")},
+			ExpectError: true,
+		},
+		{
+			Name:    "static pods on old nodes",
+			Patches: []config.KubeadmPatch{apiServerPatch},
+			Nodes:   []nodeVersion{node("cp1", "v1.24.7")},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.ExpectError(t, tc.ExpectError, checkKubeadmPatchTargets(tc.Patches, tc.Nodes))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+const (
+	// minEvictionMargin is the smallest memory margin kept free within the
+	// node's memory limit, the kubelet's default memory.available threshold
+	minEvictionMargin = 100 << 20
+	mebibyte          = 1 << 20
+)
+
+// kubeletReservations returns the kubelet reservations and hard eviction
+// thresholds for node, defaulting the memory ones from the node's memory
+// limit if it has one
+func kubeletReservations(node nodes.Node, configNode *config.Node) (kubeReserved, evictionHard map[string]string, err error) {
+	kubeReserved, evictionHard = configNode.KubeReserved, configNode.EvictionHard
+	if configNode.Memory == "" {
+		return kubeReserved, evictionHard, nil
+	}
+	limit, err := parseMemory(configNode.Memory)
+	if err != nil {
+		return nil, nil, err
+	}
+	hostMemory, err := nodeMemTotal(node)
+	if err != nil {
+		return nil, nil, err
+	}
+	kubeReserved, evictionHard = defaultMemoryReservations(limit, hostMemory, kubeReserved, evictionHard)
+	return kubeReserved, evictionHard, nil
+}
+
+// defaultMemoryReservations defaults the memory reservation and eviction
+// threshold for a node with a memory limit of limit bytes on a host with
+// hostMemory bytes, keeping any that are set
+//
+// The kubelet sees all of the host's memory as the node's capacity, so
+// memory.available is reduced by the host memory outside the limit. Pods are
+// evicted before the node runs out of memory, instead of the kernel killing
+// processes in the node container.
+func defaultMemoryReservations(limit, hostMemory int64, kubeReserved, evictionHard map[string]string) (map[string]string, map[string]string) {
+	kubeReserved = copyMap(kubeReserved)
+	if _, ok := kubeReserved["memory"]; !ok {
+		kubeReserved["memory"] = fmt.Sprintf("%dMi", limit/10/mebibyte)
+	}
+	evictionHard = copyMap(evictionHard)
+	if _, ok := evictionHard["memory.available"]; !ok {
+		margin := limit / 20
+		if margin < minEvictionMargin {
+			margin = minEvictionMargin
+		}
+		outside := hostMemory - limit
+		if outside < 0 {
+			outside = 0
+		}
+		evictionHard["memory.available"] = fmt.Sprintf("%dMi", (outside+margin)/mebibyte)
+	}
+	return kubeReserved, evictionHard
+}
+
+// nodeMemTotal returns the memory in bytes the kubelet on node sees, which
+// is the host's memory
+func nodeMemTotal(node nodes.Node) (int64, error) {
+	lines, err := exec.OutputLines(node.Command("cat", "/proc/meminfo"))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read node memory")
+	}
+	return parseMemTotal(lines)
+}
+
+// parseMemTotal returns the MemTotal in bytes from /proc/meminfo lines
+func parseMemTotal(lines []string) (int64, error) {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == "MemTotal:" && fields[2] == "kB" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, errors.Wrap(err, "failed to parse MemTotal")
+			}
+			return kb << 10, nil
+		}
+	}
+	return 0, errors.New("failed to find MemTotal in /proc/meminfo")
+}
+
+// parseMemory parses a memory limit as accepted by docker and podman, a
+// number of bytes with an optional b, k, m or g unit suffix
+func parseMemory(memory string) (int64, error) {
+	shift := 0
+	switch strings.ToLower(memory[len(memory)-1:]) {
+	case "b":
+	case "k":
+		shift = 10
+	case "m":
+		shift = 20
+	case "g":
+		shift = 30
+	default:
+		memory += "b"
+	}
+	n, err := strconv.ParseInt(memory[:len(memory)-1], 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid memory limit %q", memory)
+	}
+	return n << shift, nil
+}
+
+func copyMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
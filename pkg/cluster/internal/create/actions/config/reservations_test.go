@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestDefaultMemoryReservations(t *testing.T) {
+	cases := []struct {
+		Name                 string
+		Limit                int64
+		HostMemory           int64
+		KubeReserved         map[string]string
+		EvictionHard         map[string]string
+		ExpectedKubeReserved map[string]string
+		ExpectedEvictionHard map[string]string
+	}{
+		{
+			Name:                 "4g node on a 16g host",
+			Limit:                4 << 30,
+			HostMemory:           16 << 30,
+			ExpectedKubeReserved: map[string]string{"memory": "409Mi"},
+			ExpectedEvictionHard: map[string]string{"memory.available": "12492Mi"},
+		},
+		{
+			Name:                 "small node uses the minimum margin",
+			Limit:                1 << 30,
+			HostMemory:           2 << 30,
+			ExpectedKubeReserved: map[string]string{"memory": "102Mi"},
+			ExpectedEvictionHard: map[string]string{"memory.available": "1124Mi"},
+		},
+		{
+			Name:                 "limit above host memory",
+			Limit:                8 << 30,
+			HostMemory:           4 << 30,
+			ExpectedKubeReserved: map[string]string{"memory": "819Mi"},
+			ExpectedEvictionHard: map[string]string{"memory.available": "409Mi"},
+		},
+		{
+			Name:                 "explicit values are kept",
+			Limit:                4 << 30,
+			HostMemory:           16 << 30,
+			KubeReserved:         map[string]string{"memory": "1Gi", "cpu": "500m"},
+			EvictionHard:         map[string]string{"memory.available": "13Gi"},
+			ExpectedKubeReserved: map[string]string{"memory": "1Gi", "cpu": "500m"},
+			ExpectedEvictionHard: map[string]string{"memory.available": "13Gi"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			kubeReserved, evictionHard := defaultMemoryReservations(tc.Limit, tc.HostMemory, tc.KubeReserved, tc.EvictionHard)
+			assert.DeepEqual(t, tc.ExpectedKubeReserved, kubeReserved)
+			assert.DeepEqual(t, tc.ExpectedEvictionHard, evictionHard)
+		})
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	cases := map[string]int64{
+		"1024": 1024,
+		"512b": 512,
+		"4k":   4 << 10,
+		"64M":  64 << 20,
+		"4g":   4 << 30,
+	}
+	for memory, expected := range cases {
+		result, err := parseMemory(memory)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", memory, err)
+		} else if result != expected {
+			t.Errorf("expected %q to be %d bytes, got %d", memory, expected, result)
+		}
+	}
+}
+
+func TestParseMemTotal(t *testing.T) {
+	lines := []string{
+		"MemTotal:       16318644 kB",
+		"MemFree:         1185424 kB",
+	}
+	result, err := parseMemTotal(lines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != 16318644<<10 {
+		t.Errorf("unexpected MemTotal %d", result)
+	}
+	if _, err := parseMemTotal(lines[1:]); err == nil {
+		t.Errorf("expected error without MemTotal")
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeadm"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/patch"
+)
+
+const (
+	containerdConfigPath = "/etc/containerd/config.toml"
+	// crioSandboxConfigPath sorts after the node image's CRI-O config
+	crioSandboxConfigPath = "/etc/crio/crio.conf.d/20-kind-sandbox.conf"
+	// containerdRegistryConfigDir holds a hosts.toml per registry
+	containerdRegistryConfigDir = "/etc/containerd/certs.d"
+	// crioRegistriesConfigPath is a drop-in for the registries.conf used by
+	// CRI-O to pull images
+	crioRegistriesConfigPath = "/etc/containers/registries.conf.d/50-kind-insecure.conf"
+	// crioNeverPullConfigPath is a registries.conf drop-in like
+	// crioRegistriesConfigPath, blocking the never pull registries
+	crioNeverPullConfigPath = "/etc/containers/registries.conf.d/50-kind-never-pull.conf"
+	// neverPullServer is the registry host containerd is pointed at for the
+	// never pull registries, the .invalid TLD never resolves so pulls fail
+	// fast, and the name explains the failure
+	neverPullServer = "https://never-pull.kind.invalid"
+)
+
+// configureRuntime applies the containerd config patches, the insecure and
+// never pull registries and the node's sandbox image to node's container
+// runtime, and pulls the sandbox image
+func configureRuntime(ctx *actions.ActionContext, node nodes.Node, configNode *config.Node) error {
+	insecureRegistries := ctx.Config.InsecureRegistries
+	neverPullRegistries := ctx.Config.NeverPullRegistries
+	runtime := ""
+	if configNode.SandboxImage != "" || len(insecureRegistries) > 0 || len(neverPullRegistries) > 0 {
+		var err error
+		if runtime, err = nodeutils.ContainerRuntime(node); err != nil {
+			return err
+		}
+	}
+
+	// the registries' config path is applied before the cluster's patches,
+	// so that they may override it, and the node's sandbox image after them
+	patches := ctx.Config.ContainerdConfigPatches
+	if len(insecureRegistries)+len(neverPullRegistries) > 0 && runtime != "crio" {
+		patches = append([]string{containerdRegistryConfigPathPatch()}, patches...)
+		for _, registry := range insecureRegistries {
+			if err := nodeutils.WriteFile(node, path.Join(containerdRegistryConfigDir, registry, "hosts.toml"), containerdInsecureHostsTOML(registry)); err != nil {
+				return errors.Wrapf(err, "failed to write containerd registry config for %q", registry)
+			}
+		}
+		for _, registry := range neverPullRegistries {
+			if err := nodeutils.WriteFile(node, path.Join(containerdRegistryConfigDir, registry, "hosts.toml"), containerdNeverPullHostsTOML()); err != nil {
+				return errors.Wrapf(err, "failed to write containerd registry config for %q", registry)
+			}
+		}
+	}
+	if configNode.SandboxImage != "" && runtime != "crio" {
+		patches = append(patches[:len(patches):len(patches)], containerdSandboxImagePatch(configNode.SandboxImage))
+	}
+	if len(patches) > 0 || len(ctx.Config.ContainerdConfigPatchesJSON6902) > 0 {
+		// read and patch the config
+		var buff bytes.Buffer
+		if err := node.Command("cat", containerdConfigPath).SetStdout(&buff).Run(); err != nil {
+			return errors.Wrap(err, "failed to read containerd config from node")
+		}
+		patched, err := patch.TOML(buff.String(), patches, ctx.Config.ContainerdConfigPatchesJSON6902)
+		if err != nil {
+			return errors.Wrap(err, "failed to patch containerd config")
+		}
+		if err := nodeutils.WriteFile(node, containerdConfigPath, patched); err != nil {
+			return errors.Wrap(err, "failed to write patched containerd config")
+		}
+		// restart containerd now that we've re-configured it
+		// skip if containerd is not running
+		if err := node.Command("bash", "-c", `! pgrep --exact containerd || systemctl restart containerd`).Run(); err != nil {
+			return errors.Wrap(err, "failed to restart containerd after patching config")
+		}
+	}
+
+	if runtime == "crio" {
+		if len(insecureRegistries) > 0 {
+			if err := nodeutils.WriteFile(node, crioRegistriesConfigPath, crioInsecureRegistriesConfig(insecureRegistries)); err != nil {
+				return errors.Wrap(err, "failed to write CRI-O insecure registries config")
+			}
+		}
+		if len(neverPullRegistries) > 0 {
+			if err := nodeutils.WriteFile(node, crioNeverPullConfigPath, crioNeverPullRegistriesConfig(neverPullRegistries)); err != nil {
+				return errors.Wrap(err, "failed to write CRI-O never pull registries config")
+			}
+		}
+		if configNode.SandboxImage != "" {
+			if err := nodeutils.WriteFile(node, crioSandboxConfigPath, crioSandboxImageConfig(configNode.SandboxImage)); err != nil {
+				return errors.Wrap(err, "failed to write CRI-O sandbox image config")
+			}
+		}
+		if err := node.Command("bash", "-c", `! pgrep --exact crio || systemctl restart crio`).Run(); err != nil {
+			return errors.Wrap(err, "failed to restart CRI-O after configuring it")
+		}
+	}
+
+	if configNode.SandboxImage == "" {
+		return nil
+	}
+	// pull the sandbox image now, instead of failing to start pods later
+	if err := nodeutils.PullImage(ctx.Context, node, configNode.SandboxImage); err != nil {
+		return errors.Wrapf(err, "failed to pull sandbox image %q", configNode.SandboxImage)
+	}
+	return nil
+}
+
+// pullComponentImages pulls any overridden control plane component images on
+// a control plane node, instead of kubeadm timing out waiting for them
+func pullComponentImages(ctx *actions.ActionContext, node nodes.Node) error {
+	for _, c := range kubeadm.ComponentImages(ctx.Config) {
+		if err := nodeutils.PullImage(ctx.Context, node, c.Image); err != nil {
+			return errors.Wrapf(err, "failed to pull %s image %q", c.Target, c.Image)
+		}
+	}
+	return nil
+}
+
+// containerdSandboxImagePatch returns a containerd config patch setting
+// the sandbox image
+func containerdSandboxImagePatch(image string) string {
+	return fmt.Sprintf("[plugins.\"io.containerd.grpc.v1.cri\"]\n  sandbox_image = %q\n", image)
+}
+
+// crioSandboxImageConfig returns a CRI-O config drop-in setting the sandbox
+// image
+func crioSandboxImageConfig(image string) string {
+	return fmt.Sprintf("[crio.image]\npause_image = %q\n", image)
+}
+
+// containerdRegistryConfigPathPatch returns a containerd config patch reading
+// the registry hosts from containerdRegistryConfigDir
+func containerdRegistryConfigPathPatch() string {
+	return fmt.Sprintf("[plugins.\"io.containerd.grpc.v1.cri\".registry]\n  config_path = %q\n", containerdRegistryConfigDir)
+}
+
+// containerdInsecureHostsTOML returns the containerd hosts.toml for registry,
+// trying plain HTTP first and then HTTPS without verifying the certificate
+func containerdInsecureHostsTOML(registry string) string {
+	return fmt.Sprintf(`server = "http://%[1]s"
+
+[host."http://%[1]s"]
+  capabilities = ["pull", "resolve", "push"]
+
+[host."https://%[1]s"]
+  capabilities = ["pull", "resolve", "push"]
+  skip_verify = true
+`, registry)
+}
+
+// crioInsecureRegistriesConfig returns a registries.conf drop-in marking the
+// registries as insecure, which allows plain HTTP and unverified HTTPS
+func crioInsecureRegistriesConfig(registries []string) string {
+	var b strings.Builder
+	for _, registry := range registries {
+		fmt.Fprintf(&b, "[[registry]]\nlocation = %q\ninsecure = true\n\n", registry)
+	}
+	return b.String()
+}
+
+// containerdNeverPullHostsTOML returns the containerd hosts.toml for a never
+// pull registry, replacing the registry with neverPullServer
+func containerdNeverPullHostsTOML() string {
+	return fmt.Sprintf("server = %q\n", neverPullServer)
+}
+
+// crioNeverPullRegistriesConfig returns a registries.conf drop-in blocking
+// pulls from the registries
+func crioNeverPullRegistriesConfig(registries []string) string {
+	var b strings.Builder
+	for _, registry := range registries {
+		fmt.Fprintf(&b, "[[registry]]\nlocation = %q\nblocked = true\n\n", registry)
+	}
+	return b.String()
+}
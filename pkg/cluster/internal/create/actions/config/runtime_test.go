@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/patch"
+)
+
+func TestContainerdSandboxImagePatch(t *testing.T) {
+	t.Parallel()
+	const containerdConfig = `version = 2
+
+[plugins."io.containerd.grpc.v1.cri"]
+  sandbox_image = "registry.k8s.io/pause:3.10"
+  tolerate_missing_hugepages_controller = true
+`
+	patched, err := patch.TOML(containerdConfig, []string{
+		containerdSandboxImagePatch("mirror.example.com/pause:3.10"),
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to patch containerd config: %v", err)
+	}
+	if !strings.Contains(patched, `sandbox_image = "mirror.example.com/pause:3.10"`) {
+		t.Errorf("sandbox image not set in patched config:\n%s", patched)
+	}
+	if !strings.Contains(patched, "tolerate_missing_hugepages_controller = true") {
+		t.Errorf("other settings not kept in patched config:\n%s", patched)
+	}
+}
+
+func TestContainerdRegistryConfigPathPatch(t *testing.T) {
+	t.Parallel()
+	const containerdConfig = `version = 2
+
+[plugins."io.containerd.grpc.v1.cri"]
+  sandbox_image = "registry.k8s.io/pause:3.10"
+`
+	patched, err := patch.TOML(containerdConfig, []string{
+		containerdRegistryConfigPathPatch(),
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to patch containerd config: %v", err)
+	}
+	if !strings.Contains(patched, `config_path = "/etc/containerd/certs.d"`) {
+		t.Errorf("registry config path not set in patched config:\n%s", patched)
+	}
+	if !strings.Contains(patched, `sandbox_image = "registry.k8s.io/pause:3.10"`) {
+		t.Errorf("other settings not kept in patched config:\n%s", patched)
+	}
+}
+
+func TestContainerdInsecureHostsTOML(t *testing.T) {
+	t.Parallel()
+	hosts := containerdInsecureHostsTOML("localhost:5001")
+	for _, expected := range []string{
+		`server = "http://localhost:5001"`,
+		`[host."http://localhost:5001"]`,
+		`[host."https://localhost:5001"]`,
+		`skip_verify = true`,
+	} {
+		if !strings.Contains(hosts, expected) {
+			t.Errorf("expected %q in hosts.toml:\n%s", expected, hosts)
+		}
+	}
+}
+
+func TestContainerdNeverPullHostsTOML(t *testing.T) {
+	t.Parallel()
+	expected := "server = \"https://never-pull.kind.invalid\"\n"
+	if hosts := containerdNeverPullHostsTOML(); hosts != expected {
+		t.Errorf("expected hosts.toml %q but got %q", expected, hosts)
+	}
+}
+
+func TestCRIONeverPullRegistriesConfig(t *testing.T) {
+	t.Parallel()
+	expected := "[[registry]]\nlocation = \"docker.io\"\nblocked = true\n\n" +
+		"[[registry]]\nlocation = \"quay.io\"\nblocked = true\n\n"
+	if config := crioNeverPullRegistriesConfig([]string{"docker.io", "quay.io"}); config != expected {
+		t.Errorf("expected config %q but got %q", expected, config)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+// nodeVersion is the Kubernetes version of a node
+type nodeVersion struct {
+	name    string
+	version *version.Version
+}
+
+// validateVersionSkew returns the Kubernetes versions of the kubeNodes, or
+// an error if they are not a supported combination, see checkVersionSkew
+func validateVersionSkew(kubeNodes []nodes.Node) ([]nodeVersion, error) {
+	controlPlanes, workers := []nodeVersion{}, []nodeVersion{}
+	for _, node := range kubeNodes {
+		role, err := node.Role()
+		if err != nil {
+			return nil, err
+		}
+		v, err := nodeutils.KubeVersion(node)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get kubernetes version from node")
+		}
+		parsed, err := version.ParseGeneric(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse kubernetes version %q of node %q", v, node)
+		}
+		if role == constants.ControlPlaneNodeRoleValue {
+			controlPlanes = append(controlPlanes, nodeVersion{node.String(), parsed})
+		} else {
+			workers = append(workers, nodeVersion{node.String(), parsed})
+		}
+	}
+	if err := checkVersionSkew(controlPlanes, workers); err != nil {
+		return nil, err
+	}
+	return append(controlPlanes, workers...), nil
+}
+
+// checkVersionSkew returns an error if the control plane nodes do not all
+// run the same version, or if a worker's kubelet is newer than the control
+// plane or more than one minor version older. The kubelet version skew
+// policy allows older kubelets, but workers are joined with their own
+// kubeadm, which only supports joining a control plane one minor newer
+// https://kubernetes.io/docs/setup/production-environment/tools/kubeadm/create-cluster-kubeadm/#version-skew-policy
+func checkVersionSkew(controlPlanes, workers []nodeVersion) error {
+	if len(controlPlanes) == 0 {
+		return nil
+	}
+	cp := controlPlanes[0]
+	for _, n := range controlPlanes[1:] {
+		if n.version.String() != cp.version.String() {
+			return errors.Errorf(
+				"control plane nodes must run the same Kubernetes version, but %q runs %s and %q runs %s",
+				cp.name, cp.version, n.name, n.version,
+			)
+		}
+	}
+	const maxSkew = 1
+	for _, n := range workers {
+		if n.version.Major() != cp.version.Major() || n.version.Minor() > cp.version.Minor() {
+			return errors.Errorf(
+				"node %q runs Kubernetes %s, which is newer than the control plane's %s",
+				n.name, n.version, cp.version,
+			)
+		}
+		if cp.version.Minor()-n.version.Minor() > maxSkew {
+			return errors.Errorf(
+				"node %q runs Kubernetes %s, more than %d minor version older than the control plane's %s, which kubeadm join does not support",
+				n.name, n.version, maxSkew, cp.version,
+			)
+		}
+	}
+	return nil
+}
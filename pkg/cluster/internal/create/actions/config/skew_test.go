@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+func TestCheckVersionSkew(t *testing.T) {
+	t.Parallel()
+	node := func(name, v string) nodeVersion {
+		return nodeVersion{name: name, version: version.MustParseSemantic(v)}
+	}
+	cases := []struct {
+		Name          string
+		ControlPlanes []nodeVersion
+		Workers       []nodeVersion
+		ExpectError   bool
+	}{
+		{
+			Name:          "same versions",
+			ControlPlanes: []nodeVersion{node("cp1", "v1.32.0"), node("cp2", "v1.32.0")},
+			Workers:       []nodeVersion{node("w1", "v1.32.0")},
+		},
+		{
+			Name:          "older workers within skew",
+			ControlPlanes: []nodeVersion{node("cp1", "v1.32.0")},
+			Workers:       []nodeVersion{node("w1", "v1.31.4"), node("w2", "v1.32.0")},
+		},
+		{
+			Name:          "newer patch worker",
+			ControlPlanes: []nodeVersion{node("cp1", "v1.32.0")},
+			Workers:       []nodeVersion{node("w1", "v1.32.2")},
+		},
+		{
+			Name:          "mixed control plane versions",
+			ControlPlanes: []nodeVersion{node("cp1", "v1.32.0"), node("cp2", "v1.31.0")},
+			ExpectError:   true,
+		},
+		{
+			Name:          "newer worker",
+			ControlPlanes: []nodeVersion{node("cp1", "v1.31.0")},
+			Workers:       []nodeVersion{node("w1", "v1.32.0")},
+			ExpectError:   true,
+		},
+		{
+			Name:          "worker too old",
+			ControlPlanes: []nodeVersion{node("cp1", "v1.32.0")},
+			Workers:       []nodeVersion{node("w1", "v1.30.0")},
+			ExpectError:   true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.ExpectError(t, tc.ExpectError, checkVersionSkew(tc.ControlPlanes, tc.Workers))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configurelogging implements the action to configure the logging
+// of the node components from the node config
+package configurelogging
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/loglevel"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+type action struct{}
+
+// NewAction returns a new action for configuring logging
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Configuring logging 📝")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+
+	// identify each node in config by matching name (since these are named
+	// in order), the same as the config action does
+	namer := common.MakeNodeNamer("")
+	suffixes := make([]string, len(ctx.Config.Nodes))
+	for i := range ctx.Config.Nodes {
+		suffixes[i] = common.NodeName(namer, &ctx.Config.Nodes[i])
+	}
+
+	fns := []func() error{}
+	for _, node := range kubeNodes {
+		node := node // capture loop variable
+		var logging config.NodeLogging
+		for i, suffix := range suffixes {
+			if strings.HasSuffix(node.String(), suffix) {
+				logging = ctx.Config.Nodes[i].Logging
+			}
+		}
+		fns = append(fns, func() error {
+			return errors.Wrapf(loglevel.Apply(node, logging), "failed to configure logging on node %q", node)
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return err
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
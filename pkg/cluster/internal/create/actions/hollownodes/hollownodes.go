@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hollownodes implements the action to create kubemark hollow nodes
+package hollownodes
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+type action struct {
+	hollowNodes config.HollowNodes
+}
+
+// NewAction returns a new action for creating hollow nodes
+func NewAction(hollowNodes config.HollowNodes) actions.Action {
+	return &action{hollowNodes: hollowNodes}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Creating hollow nodes 👻")
+	defer ctx.Status.End(false)
+
+	// the hollow kubelets reach the API server over the cluster's network
+	kubeconfig, err := kubeconfig.Get(ctx.Provider, ctx.Config.Name, "", false)
+	if err != nil {
+		return errors.Wrap(err, "failed to get kubeconfig for hollow nodes")
+	}
+
+	namer := common.MakeNodeNamer(ctx.Config.Name)
+	names := make([]string, 0, a.hollowNodes.Count)
+	for i := int32(0); i < a.hollowNodes.Count; i++ {
+		names = append(names, namer(constants.HollowNodeRoleValue))
+	}
+	if err := ctx.Provider.CreateHollowNodes(ctx.Config.Name, a.hollowNodes.Image, kubeconfig, names); err != nil {
+		return err
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
@@ -128,6 +128,18 @@ func (a *action) Execute(ctx *actions.ActionContext) error {
 		return errors.Wrap(err, "failed to apply overlay network")
 	}
 
+	// optionally wait for the CNI to be rolled out to every node
+	if timeout := config.TimeoutDuration(ctx.Config.Timeouts.CNIReady); timeout > 0 {
+		if err := node.CommandContext(
+			ctx.Context,
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+			"--namespace=kube-system", "rollout", "status", "daemonset/kindnet",
+			"--timeout="+timeout.String(),
+		).Run(); err != nil {
+			return errors.Wrapf(err, "CNI was not ready after %v, consider increasing timeouts.cniReady", timeout)
+		}
+	}
+
 	// mark success
 	ctx.Status.End(true)
 	return nil
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package installhostroutes implements the action to add routes on the host
+// to the nodes' pod CIDRs
+package installhostroutes
+
+import (
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/hostroutes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+)
+
+// podCIDRTimeout is how long to wait for every node to be allocated pod CIDRs
+const podCIDRTimeout = time.Minute
+
+type action struct{}
+
+// NewAction returns a new action for adding host routes
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Adding host routes to pods 🛣️")
+	defer ctx.Status.End(false)
+
+	info, err := ctx.Provider.Info()
+	if err != nil {
+		return err
+	}
+	if info.Rootless {
+		return errors.New("networking.hostRoutes is not supported with rootless providers")
+	}
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	internalNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	node := controlPlanes[0] // kind expects at least one always
+
+	// pod CIDRs are allocated by kube-controller-manager shortly after
+	// nodes register, so they may not be allocated yet
+	var podCIDRs map[string][]string
+	for start := time.Now(); ; time.Sleep(time.Second) {
+		podCIDRs, err = hostroutes.PodCIDRs(node)
+		if err == nil && len(podCIDRs) == len(internalNodes) {
+			break
+		}
+		if ctx.Context.Err() != nil {
+			return ctx.Context.Err()
+		}
+		if time.Since(start) > podCIDRTimeout {
+			if err == nil {
+				err = errors.Errorf("only %d of %d nodes were allocated pod CIDRs", len(podCIDRs), len(internalNodes))
+			}
+			return errors.Wrap(err, "timed out waiting for pod CIDRs")
+		}
+	}
+
+	routes, err := hostroutes.ForNodes(internalNodes, podCIDRs)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		ctx.Logger.V(1).Infof("Adding host route %s", route)
+	}
+	if err := hostroutes.Add(ctx.Provider, routes); err != nil {
+		return err
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package installloadbalancerpool implements the action to reserve node
+// network addresses for LoadBalancer services and optionally install MetalLB
+package installloadbalancerpool
+
+import (
+	"net"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// PoolPath is where the reserved address ranges are recorded on the
+// bootstrap control plane node, one per line
+const PoolPath = "/kind/loadbalancer-pool"
+
+type action struct {
+	pool config.LoadBalancerPool
+}
+
+// NewAction returns a new action for reserving the load balancer pool
+func NewAction(pool config.LoadBalancerPool) actions.Action {
+	return &action{pool: pool}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Reserving LoadBalancer addresses 🎯")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	node, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return err
+	}
+	network, err := ctx.Provider.GetNetwork(ctx.Config.Name)
+	if err != nil {
+		return err
+	}
+	reserved, err := reservedRanges(ctx, allNodes)
+	if err != nil {
+		return err
+	}
+
+	// reserve addresses in the subnet of each of the cluster's IP families
+	family := ctx.Config.Networking.IPFamily
+	pool := []addressRange{}
+	for _, s := range network.Subnets {
+		_, subnet, err := net.ParseCIDR(s)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse network %q subnet", network.Name)
+		}
+		if isIPv4(subnet.IP) && family == config.IPv6Family || !isIPv4(subnet.IP) && family == config.IPv4Family {
+			continue
+		}
+		// the pool is carved out of the subnet outside of the range the
+		// runtime allocates container addresses from
+		allocated, err := allocatedRange(subnet, network.IPRanges)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse network %q IP range", network.Name)
+		}
+		if allocated == nil {
+			ctx.Logger.Warnf(
+				"Network %q allocates container addresses from all of subnet %s, they may conflict with LoadBalancer addresses. "+
+					"Delete it when no clusters use it to have kind recreate it with a reserved range.",
+				network.Name, subnet,
+			)
+		} else {
+			reserved = append(reserved, *allocated)
+		}
+		r, err := reserveRange(subnet, int64(a.pool.Size), reserved)
+		if err != nil {
+			return errors.Wrapf(err, "failed to reserve LoadBalancer addresses in network %q", network.Name)
+		}
+		pool = append(pool, r)
+	}
+	if len(pool) == 0 {
+		return errors.Errorf("network %q has no subnet for ipFamily %s", network.Name, family)
+	}
+
+	// record the pool, so that it is reported and other clusters skip it
+	ranges := make([]string, 0, len(pool))
+	for _, r := range pool {
+		ranges = append(ranges, r.String())
+	}
+	if err := node.Command("cp", "/dev/stdin", PoolPath).SetStdin(
+		strings.NewReader(strings.Join(ranges, "\n") + "\n"),
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to record LoadBalancer addresses")
+	}
+	ctx.Status.End(true)
+	ctx.Logger.V(1).Infof("Reserved LoadBalancer addresses %s", strings.Join(ranges, ", "))
+
+	if !a.pool.MetalLB {
+		return nil
+	}
+	return installMetalLB(ctx, node, ranges)
+}
+
+// ReadPool returns the address ranges reserved for the cluster with
+// allNodes, if any
+func ReadPool(allNodes []nodes.Node) ([]string, error) {
+	node, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := exec.OutputLines(node.Command(
+		"sh", "-c", "[ ! -f "+PoolPath+" ] || cat "+PoolPath,
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read LoadBalancer addresses")
+	}
+	ranges := []string{}
+	for _, line := range lines {
+		if line != "" {
+			ranges = append(ranges, line)
+		}
+	}
+	return ranges, nil
+}
+
+// reservedRanges returns the addresses the pool must not overlap: the
+// cluster's node addresses and the pools of other clusters
+func reservedRanges(ctx *actions.ActionContext, allNodes []nodes.Node) ([]addressRange, error) {
+	reserved := []addressRange{}
+	for _, n := range allNodes {
+		ipv4, ipv6, err := n.IP()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get addresses of node %q", n.String())
+		}
+		for _, ip := range []string{ipv4, ipv6} {
+			if parsed := net.ParseIP(ip); parsed != nil {
+				reserved = append(reserved, addressRange{start: parsed, end: parsed})
+			}
+		}
+	}
+	clusters, err := ctx.Provider.ListClusters()
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		if cluster == ctx.Config.Name {
+			continue
+		}
+		// best effort, the pools of stopped clusters cannot be read
+		n, err := ctx.Provider.ListNodes(cluster)
+		if err != nil {
+			continue
+		}
+		ranges, err := ReadPool(n)
+		if err != nil {
+			continue
+		}
+		for _, s := range ranges {
+			if r, err := parseRange(s); err == nil {
+				reserved = append(reserved, r)
+			}
+		}
+	}
+	return reserved, nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installloadbalancerpool
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/internal/sets"
+)
+
+func TestMetalLBManifest(t *testing.T) {
+	t.Parallel()
+	manifest := fmt.Sprintf(metalLBManifest, metalLBVersion)
+	for _, doc := range strings.Split(manifest, "\n---\n") {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			t.Fatalf("failed to parse manifest document: %v\n%s", err, doc)
+		}
+		if obj["kind"] == nil || obj["apiVersion"] == nil {
+			t.Errorf("manifest document is missing kind or apiVersion:\n%s", doc)
+		}
+	}
+	// the images installed must match MetalLBImages
+	images := sets.NewString()
+	for _, line := range strings.Split(manifest, "\n") {
+		if image := strings.TrimPrefix(strings.TrimSpace(line), "image: "); image != strings.TrimSpace(line) {
+			images.Insert(image)
+		}
+	}
+	if expected := sets.NewString(MetalLBImages...); !images.Equal(expected) {
+		t.Errorf("manifest images %v do not match MetalLBImages %v", images.List(), expected.List())
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installloadbalancerpool
+
+import (
+	"math/big"
+	"net"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// addressRange is an inclusive range of IP addresses of one family
+type addressRange struct {
+	start, end net.IP
+}
+
+// String returns the range in the start-end form MetalLB accepts
+func (r addressRange) String() string {
+	return r.start.String() + "-" + r.end.String()
+}
+
+// parseRange parses a range in the start-end form
+func parseRange(s string) (addressRange, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return addressRange{}, errors.Errorf("invalid address range %q", s)
+	}
+	r := addressRange{start: net.ParseIP(parts[0]), end: net.ParseIP(parts[1])}
+	if r.start == nil || r.end == nil || isIPv4(r.start) != isIPv4(r.end) {
+		return addressRange{}, errors.Errorf("invalid address range %q", s)
+	}
+	return r, nil
+}
+
+// allocatedRange returns the range of the ipRanges within subnet as an
+// addressRange, or nil if subnet has none
+func allocatedRange(subnet *net.IPNet, ipRanges []string) (*addressRange, error) {
+	for _, s := range ipRanges {
+		_, ipRange, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		if !subnet.Contains(ipRange.IP) {
+			continue
+		}
+		ones, bits := ipRange.Mask.Size()
+		last := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+		last.Add(last, ipToInt(ipRange.IP)).Sub(last, big.NewInt(1))
+		v4 := isIPv4(ipRange.IP)
+		return &addressRange{start: intToIP(ipToInt(ipRange.IP), v4), end: intToIP(last, v4)}, nil
+	}
+	return nil, nil
+}
+
+// reserveRange returns the last range of size addresses in subnet that does
+// not overlap any of the reserved ranges, the network and broadcast
+// addresses are never included
+func reserveRange(subnet *net.IPNet, size int64, reserved []addressRange) (addressRange, error) {
+	v4 := isIPv4(subnet.IP)
+	first := new(big.Int).Add(ipToInt(subnet.IP), big.NewInt(1))
+	ones, bits := subnet.Mask.Size()
+	last := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	last.Add(last, ipToInt(subnet.IP)).Sub(last, big.NewInt(2))
+
+	end := last
+	for {
+		start := new(big.Int).Sub(end, big.NewInt(size-1))
+		if start.Cmp(first) < 0 {
+			return addressRange{}, errors.Errorf("subnet %s does not have %d free addresses", subnet, size)
+		}
+		// move below the lowest overlapping reserved range, if any
+		var lowest *big.Int
+		for _, r := range reserved {
+			if isIPv4(r.start) != v4 {
+				continue
+			}
+			rStart, rEnd := ipToInt(r.start), ipToInt(r.end)
+			if rStart.Cmp(end) <= 0 && rEnd.Cmp(start) >= 0 && (lowest == nil || rStart.Cmp(lowest) < 0) {
+				lowest = rStart
+			}
+		}
+		if lowest == nil {
+			return addressRange{start: intToIP(start, v4), end: intToIP(end, v4)}, nil
+		}
+		end = new(big.Int).Sub(lowest, big.NewInt(1))
+	}
+}
+
+func isIPv4(ip net.IP) bool {
+	return ip.To4() != nil
+}
+
+func ipToInt(ip net.IP) *big.Int {
+	if v4 := ip.To4(); v4 != nil {
+		return new(big.Int).SetBytes(v4)
+	}
+	return new(big.Int).SetBytes(ip.To16())
+}
+
+func intToIP(i *big.Int, v4 bool) net.IP {
+	size := net.IPv6len
+	if v4 {
+		size = net.IPv4len
+	}
+	return net.IP(i.FillBytes(make([]byte, size)))
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installloadbalancerpool
+
+import (
+	"net"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestReserveRange(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Subnet      string
+		Size        int64
+		Reserved    []string
+		Expected    string
+		ExpectError bool
+	}{
+		{
+			Name:     "end of ipv4 subnet",
+			Subnet:   "172.18.0.0/16",
+			Size:     32,
+			Expected: "172.18.255.223-172.18.255.254",
+		},
+		{
+			Name:     "below another cluster's pool",
+			Subnet:   "172.18.0.0/16",
+			Size:     32,
+			Reserved: []string{"172.18.255.223-172.18.255.254"},
+			Expected: "172.18.255.191-172.18.255.222",
+		},
+		{
+			Name:     "below overlapping pools and node addresses",
+			Subnet:   "172.18.0.0/24",
+			Size:     16,
+			Reserved: []string{"172.18.0.250-172.18.0.254", "172.18.0.240-172.18.0.240", "fc00::1-fc00::ff"},
+			Expected: "172.18.0.224-172.18.0.239",
+		},
+		{
+			Name:     "end of ipv6 subnet",
+			Subnet:   "fc00:f853:ccd:e793::/64",
+			Size:     256,
+			Expected: "fc00:f853:ccd:e793:ffff:ffff:ffff:feff-fc00:f853:ccd:e793:ffff:ffff:ffff:fffe",
+		},
+		{
+			Name:        "subnet too small",
+			Subnet:      "172.18.0.0/28",
+			Size:        10,
+			Reserved:    []string{"172.18.0.2-172.18.0.5"},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			_, subnet, err := net.ParseCIDR(tc.Subnet)
+			if err != nil {
+				t.Fatalf("invalid test subnet: %v", err)
+			}
+			reserved := []addressRange{}
+			for _, s := range tc.Reserved {
+				r, err := parseRange(s)
+				if err != nil {
+					t.Fatalf("invalid test range: %v", err)
+				}
+				reserved = append(reserved, r)
+			}
+			r, err := reserveRange(subnet, tc.Size, reserved)
+			assert.ExpectError(t, tc.ExpectError, err)
+			if err == nil {
+				assert.StringEqual(t, tc.Expected, r.String())
+			}
+		})
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+	for _, s := range []string{"172.18.0.1", "172.18.0.1-", "172.18.0.1-fc00::1", "a-b"} {
+		if _, err := parseRange(s); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+}
+
+func TestAllocatedRange(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Subnet   string
+		IPRanges []string
+		Expected string
+	}{
+		{
+			Name:     "ipv4 range",
+			Subnet:   "172.18.0.0/16",
+			IPRanges: []string{"fc00:f853:ccd:e793::/65", "172.18.0.0/17"},
+			Expected: "172.18.0.0-172.18.127.255",
+		},
+		{
+			Name:     "ipv6 range",
+			Subnet:   "fc00:f853:ccd:e793::/64",
+			IPRanges: []string{"172.18.0.0/17", "fc00:f853:ccd:e793::/65"},
+			Expected: "fc00:f853:ccd:e793::-fc00:f853:ccd:e793:7fff:ffff:ffff:ffff",
+		},
+		{
+			Name:     "no range",
+			Subnet:   "172.18.0.0/16",
+			IPRanges: []string{"172.19.0.0/17"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			_, subnet, err := net.ParseCIDR(tc.Subnet)
+			if err != nil {
+				t.Fatalf("invalid test subnet: %v", err)
+			}
+			r, err := allocatedRange(subnet, tc.IPRanges)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual := ""
+			if r != nil {
+				actual = r.String()
+			}
+			assert.StringEqual(t, tc.Expected, actual)
+		})
+	}
+}
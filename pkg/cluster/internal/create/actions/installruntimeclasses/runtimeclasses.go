@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package installruntimeclasses implements an action to create the
+// RuntimeClasses shipped in the node image, if any
+package installruntimeclasses
+
+import (
+	"bytes"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+)
+
+// runtimeClassesManifest is written by node images built with extra
+// runtime handlers, such as the wasm shims
+const runtimeClassesManifest = "/kind/manifests/runtimeclasses.yaml"
+
+type action struct{}
+
+// NewAction returns a new action for installing RuntimeClasses
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+
+	// get the target node for this task
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	node := controlPlanes[0] // kind expects at least one always
+
+	// most node images do not ship any RuntimeClasses, there is nothing to do
+	var manifest bytes.Buffer
+	if err := node.Command(
+		"sh", "-c", "cat "+runtimeClassesManifest+" 2>/dev/null || true",
+	).SetStdout(&manifest).Run(); err != nil {
+		return errors.Wrap(err, "failed to read RuntimeClasses manifest")
+	}
+	if strings.TrimSpace(manifest.String()) == "" {
+		return nil
+	}
+
+	ctx.Status.Start("Installing RuntimeClasses 🧩")
+	defer ctx.Status.End(false)
+
+	if err := node.Command(
+		"kubectl",
+		"--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-",
+	).SetStdin(&manifest).Run(); err != nil {
+		return errors.Wrap(err, "failed to apply RuntimeClasses")
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package installstaticpods implements the action to install the extra
+// static pod manifests from the node config
+package installstaticpods
+
+import (
+	"path"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// manifestsDir is the kubelet's static pod directory on the node
+const manifestsDir = "/etc/kubernetes/manifests"
+
+type action struct{}
+
+// NewAction returns a new action for installing static pod manifests
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Installing static pods 📜")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+
+	fns := []func() error{}
+	for _, node := range kubeNodes {
+		node := node // capture loop variable
+		pods := nodeStaticPods(ctx.Config, node.String())
+		if len(pods) == 0 {
+			continue
+		}
+		fns = append(fns, func() error {
+			return errors.Wrapf(installStaticPods(node, pods), "failed to install static pods on node %q", node)
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return err
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
+
+// nodeStaticPods returns the static pods configured for the node named
+// nodeName, identifying each node in config by matching name (since these
+// are named in order), the same as the config action does
+func nodeStaticPods(cfg *config.Cluster, nodeName string) []config.StaticPod {
+	namer := common.MakeNodeNamer("")
+	var pods []config.StaticPod
+	for i := range cfg.Nodes {
+		if strings.HasSuffix(nodeName, common.NodeName(namer, &cfg.Nodes[i])) {
+			pods = cfg.Nodes[i].StaticPods
+		}
+	}
+	return pods
+}
+
+// installStaticPods writes the manifests to the kubelet's static pod
+// directory, the kubelet starts them once kubeadm starts it
+func installStaticPods(node nodes.Node, pods []config.StaticPod) error {
+	for _, pod := range pods {
+		if err := nodeutils.WriteFile(node, path.Join(manifestsDir, pod.Name+".yaml"), pod.Manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installstaticpods
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestNodeStaticPods(t *testing.T) {
+	t.Parallel()
+	agent := config.StaticPod{Name: "agent", Manifest: "apiVersion: v1\nkind: Pod\n"}
+	sidecar := config.StaticPod{Name: "sidecar", Manifest: "apiVersion: v1\nkind: Pod\n"}
+	cfg := &config.Cluster{
+		Nodes: []config.Node{
+			{Role: config.ControlPlaneRole, StaticPods: []config.StaticPod{sidecar}},
+			{Role: config.WorkerRole},
+			{Role: config.WorkerRole, StaticPods: []config.StaticPod{agent}},
+		},
+	}
+	assert.DeepEqual(t, []config.StaticPod{sidecar}, nodeStaticPods(cfg, "kind-control-plane"))
+	assert.DeepEqual(t, []config.StaticPod(nil), nodeStaticPods(cfg, "kind-worker"))
+	assert.DeepEqual(t, []config.StaticPod{agent}, nodeStaticPods(cfg, "kind-worker2"))
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installstorage
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/sets"
+)
+
+func TestSharedStorageManifest(t *testing.T) {
+	t.Parallel()
+	images := manifestImages(t, sharedStorageManifest)
+	// the images pulled when creating a cluster must match the manifest
+	if expected := sets.NewString(SharedStorageImages...); !images.Equal(expected) {
+		t.Errorf("manifest images %v do not match SharedStorageImages %v", images.List(), expected.List())
+	}
+	// the provisioner must use the path every node mounts
+	if !strings.Contains(sharedStorageManifest, `"sharedFileSystemPath": "`+SharedStoragePath+`"`) {
+		t.Errorf("manifest does not configure sharedFileSystemPath %q", SharedStoragePath)
+	}
+}
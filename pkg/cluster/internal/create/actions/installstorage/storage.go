@@ -24,17 +24,24 @@ import (
 
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
 	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 )
 
-type action struct{}
+type action struct {
+	defaultStorage config.DefaultStorage
+	sharedStorage  bool
+}
 
 // NewAction returns a new action for installing storage
-func NewAction() actions.Action {
-	return &action{}
+func NewAction(cfg *config.Cluster) actions.Action {
+	return &action{
+		defaultStorage: cfg.Addons.DefaultStorage,
+		sharedStorage:  cfg.Addons.SharedStorage.HostPath != "",
+	}
 }
 
 // Execute runs the action
@@ -55,8 +62,23 @@ func (a *action) Execute(ctx *actions.ActionContext) error {
 	node := controlPlanes[0] // kind expects at least one always
 
 	// add the default storage class
-	if err := addDefaultStorage(ctx.Logger, node); err != nil {
-		return errors.Wrap(err, "failed to add default storage class")
+	switch a.defaultStorage {
+	case config.NoneDefaultStorage:
+	case config.CSIHostPathDefaultStorage:
+		if err := addCSIHostPathStorage(node); err != nil {
+			return errors.Wrap(err, "failed to add CSI hostpath storage")
+		}
+	default:
+		if err := addDefaultStorage(ctx.Logger, node); err != nil {
+			return errors.Wrap(err, "failed to add default storage class")
+		}
+	}
+
+	// add the shared storage class
+	if a.sharedStorage {
+		if err := applyManifest(node, sharedStorageManifest); err != nil {
+			return errors.Wrap(err, "failed to add shared storage class")
+		}
 	}
 
 	// mark success
@@ -88,7 +110,27 @@ func addDefaultStorage(logger log.Logger, controlPlane nodes.Node) error {
 		manifest = raw.String()
 	}
 
-	// apply the manifest
+	return applyManifest(controlPlane, manifest)
+}
+
+func addCSIHostPathStorage(controlPlane nodes.Node) error {
+	// the snapshot CRDs must be established before creating a snapshot class
+	if err := applyManifest(controlPlane, csiSnapshotCRDs); err != nil {
+		return err
+	}
+	if err := controlPlane.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"wait", "--for=condition=established", "--timeout=1m",
+		"crd/volumesnapshotclasses.snapshot.storage.k8s.io",
+		"crd/volumesnapshotcontents.snapshot.storage.k8s.io",
+		"crd/volumesnapshots.snapshot.storage.k8s.io",
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed waiting for snapshot CRDs")
+	}
+	return applyManifest(controlPlane, csiHostPathManifest)
+}
+
+func applyManifest(controlPlane nodes.Node, manifest string) error {
 	in := strings.NewReader(manifest)
 	cmd := controlPlane.Command(
 		"kubectl",
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package installsystemdunits implements the action to install the extra
+// systemd units and kubelet environment from the node config
+package installsystemdunits
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// unitDir is where the units are installed on the node
+const unitDir = "/etc/systemd/system"
+
+// kubeletEnvDropIn is the kubelet drop-in setting the node's Env
+const kubeletEnvDropIn = "/etc/systemd/system/kubelet.service.d/20-kind-env.conf"
+
+type action struct{}
+
+// NewAction returns a new action for installing systemd units
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Installing systemd units 🧩")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+
+	// identify each node in config by matching name (since these are named
+	// in order), the same as the config action does
+	namer := common.MakeNodeNamer("")
+	suffixes := make([]string, len(ctx.Config.Nodes))
+	for i := range ctx.Config.Nodes {
+		suffixes[i] = common.NodeName(namer, &ctx.Config.Nodes[i])
+	}
+
+	fns := []func() error{}
+	for _, node := range kubeNodes {
+		node := node // capture loop variable
+		var units []config.SystemdUnit
+		var env map[string]string
+		for i, suffix := range suffixes {
+			if strings.HasSuffix(node.String(), suffix) {
+				units = ctx.Config.Nodes[i].SystemdUnits
+				env = ctx.Config.Nodes[i].Env
+			}
+		}
+		if len(units) == 0 && len(env) == 0 {
+			continue
+		}
+		fns = append(fns, func() error {
+			return errors.Wrapf(installUnits(node, units, env), "failed to install systemd units on node %q", node)
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return err
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
+
+func installUnits(node nodes.Node, units []config.SystemdUnit, env map[string]string) error {
+	// kubeadm restarts the kubelet, so the drop-in only needs a daemon-reload
+	if len(env) > 0 {
+		if err := nodeutils.WriteFile(node, kubeletEnvDropIn, kubeletEnvContent(env)); err != nil {
+			return err
+		}
+	}
+	enabled := []string{}
+	for _, unit := range units {
+		if err := nodeutils.WriteFile(node, path.Join(unitDir, unit.Name), unit.Content); err != nil {
+			return err
+		}
+		if unit.Enabled {
+			enabled = append(enabled, unit.Name)
+		}
+	}
+	if err := node.Command("systemctl", "daemon-reload").Run(); err != nil {
+		return err
+	}
+	if len(enabled) == 0 {
+		return nil
+	}
+	return node.Command("systemctl", append([]string{"enable", "--now"}, enabled...)...).Run()
+}
+
+// kubeletEnvContent returns a kubelet drop-in setting env
+func kubeletEnvContent(env map[string]string) string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("[Service]\n")
+	// https://www.freedesktop.org/software/systemd/man/systemd.exec.html#Environment=
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%")
+	for _, name := range names {
+		fmt.Fprintf(&b, "Environment=\"%s=%s\"\n", name, escaper.Replace(env[name]))
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installsystemdunits
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestKubeletEnvContent(t *testing.T) {
+	t.Parallel()
+	env := map[string]string{
+		"GODEBUG": "http2debug=1",
+		"QUOTED":  `say "hi" \ 100%`,
+		"EMPTY":   "",
+	}
+	expected := `[Service]
+Environment="EMPTY="
+Environment="GODEBUG=http2debug=1"
+Environment="QUOTED=say \"hi\" \\ 100%%"
+`
+	assert.StringEqual(t, expected, kubeletEnvContent(env))
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keepportmap implements the action to keep hostPort support with
+// a CNI other than the default, by chaining the portmap plugin into its config
+package keepportmap
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+)
+
+const (
+	scriptPath  = "/kind/bin/chain-portmap.sh"
+	servicePath = "/etc/systemd/system/kind-chain-portmap.service"
+	pathUnit    = "kind-chain-portmap.path"
+	pathUnitDir = "/etc/systemd/system/"
+)
+
+// script appends the portmap plugin to each CNI config list lacking it,
+// the same as the default CNI config chains it.
+// CNI only loads .conf, .conflist and .json files, so the temporary file is
+// ignored until it is renamed over the original
+const script = `#!/bin/bash
+set -o errexit -o nounset -o pipefail
+shopt -s nullglob
+for conflist in /etc/cni/net.d/*.conflist; do
+  if jq -e 'any(.plugins[]; .type == "portmap")' "${conflist}" >/dev/null; then
+    continue
+  fi
+  jq '.plugins += [{"type": "portmap", "capabilities": {"portMappings": true}}]' \
+    "${conflist}" > "${conflist}.kind-portmap"
+  mv "${conflist}.kind-portmap" "${conflist}"
+done
+`
+
+// service runs the script, it is triggered by the path unit
+const service = `[Unit]
+Description=Chain the portmap CNI plugin into CNI configs lacking it
+
+[Service]
+Type=oneshot
+ExecStart=/bin/bash ` + scriptPath + `
+`
+
+// path triggers the service whenever the CNI config changes, including
+// when a CNI is first installed. The script is idempotent so its own
+// changes trigger at most one more run
+const path = `[Unit]
+Description=Watch the CNI config to chain the portmap CNI plugin
+
+[Path]
+PathChanged=/etc/cni/net.d
+MakeDirectory=yes
+
+[Install]
+WantedBy=multi-user.target
+`
+
+type action struct{}
+
+// NewAction returns a new action for keeping hostPort support
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Keeping hostPort support 🚪")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+
+	fns := []func() error{}
+	for _, node := range kubeNodes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			return errors.Wrapf(installPortmapChaining(node), "failed to keep hostPort support on node %q", node)
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return err
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
+
+// installPortmapChaining installs the script and the units running it on
+// node, these must be in place before any CNI config is written
+func installPortmapChaining(node nodes.Node) error {
+	if err := nodeutils.WriteFile(node, scriptPath, script); err != nil {
+		return err
+	}
+	if err := nodeutils.WriteFile(node, servicePath, service); err != nil {
+		return err
+	}
+	if err := nodeutils.WriteFile(node, pathUnitDir+pathUnit, path); err != nil {
+		return err
+	}
+	if err := node.Command("systemctl", "daemon-reload").Run(); err != nil {
+		return err
+	}
+	return node.Command("systemctl", "enable", "--now", pathUnit).Run()
+}
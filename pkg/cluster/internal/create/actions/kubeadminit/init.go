@@ -18,14 +18,20 @@ limitations under the License.
 package kubeadminit
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/errors"
-	"sigs.k8s.io/kind/pkg/exec"
 
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
 	"sigs.k8s.io/kind/pkg/internal/version"
 )
@@ -35,11 +41,21 @@ import (
 // CNI network plugin.
 type action struct {
 	skipKubeProxy bool
+	skipPhases    []string
+	usePatches    bool
+	exportCADir   string
+	timeout       time.Duration
 }
 
 // NewAction returns a new action for kubeadm init
 func NewAction(cfg *config.Cluster) actions.Action {
-	return &action{skipKubeProxy: cfg.Networking.KubeProxyMode == config.NoneProxyMode}
+	return &action{
+		skipKubeProxy: cfg.Networking.KubeProxyMode == config.NoneProxyMode,
+		skipPhases:    cfg.KubeadmInitSkipPhases,
+		usePatches:    len(kubeadm.ComponentPatches(cfg)) > 0,
+		exportCADir:   cfg.Certificates.ExportCADir,
+		timeout:       config.TimeoutDuration(cfg.Timeouts.ControlPlaneInit),
+	}
 }
 
 // Execute runs the action
@@ -83,16 +99,20 @@ func (a *action) Execute(ctx *actions.ActionContext) error {
 	if kubeVersion.LessThan(version.MustParseSemantic("v1.23.0")) {
 		// Skip preflight to avoid pulling images.
 		// Kind pre-pulls images and preflight may conflict with that.
-		skipPhases := "preflight"
+		skipPhases := []string{"preflight"}
 		if a.skipKubeProxy {
-			skipPhases += ",addon/kube-proxy"
+			skipPhases = append(skipPhases, "addon/kube-proxy")
+		}
+		skipPhases = append(skipPhases, a.skipPhases...)
+		args = append(args, "--skip-phases="+strings.Join(skipPhases, ","))
+		// Patches are also set in the config file for newer versions.
+		if a.usePatches {
+			args = append(args, "--experimental-patches="+kubeadm.PatchesDir)
 		}
-		args = append(args, "--skip-phases="+skipPhases)
 	}
 
 	// run kubeadm
-	cmd := node.Command("kubeadm", args...)
-	lines, err := exec.CombinedOutputLines(cmd)
+	lines, err := common.RunInNodeWithTimeout(ctx.Context, node, a.timeout, "controlPlaneInit", "kubeadm", args...)
 	ctx.Logger.V(3).Info(strings.Join(lines, "\n"))
 	if err != nil {
 		return errors.Wrap(err, "failed to init node with kubeadm")
@@ -121,6 +141,14 @@ func (a *action) Execute(ctx *actions.ActionContext) error {
 		}
 	}
 
+	// export the cluster CA to the host if requested
+	if a.exportCADir != "" {
+		if err := exportCA(node, a.exportCADir); err != nil {
+			return err
+		}
+		ctx.Logger.V(0).Infof("Exported cluster CA to: %s", a.exportCADir)
+	}
+
 	// if we are only provisioning one node, remove the control plane taint
 	// https://kubernetes.io/docs/setup/independent/create-cluster-kubeadm/#master-isolation
 	if len(allNodes) == 1 {
@@ -171,3 +199,20 @@ func (a *action) Execute(ctx *actions.ActionContext) error {
 	ctx.Status.End(true)
 	return nil
 }
+
+// exportCA copies the cluster CA certificate and key from node to dir on the host
+func exportCA(node nodes.Node, dir string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return errors.Wrap(err, "failed to create CA export directory")
+	}
+	for _, name := range []string{"ca.crt", "ca.key"} {
+		var buff bytes.Buffer
+		if err := node.Command("cat", "/etc/kubernetes/pki/"+name).SetStdout(&buff).Run(); err != nil {
+			return errors.Wrapf(err, "failed to read %s from node", name)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), buff.Bytes(), 0600); err != nil {
+			return errors.Wrapf(err, "failed to export %s", name)
+		}
+	}
+	return nil
+}
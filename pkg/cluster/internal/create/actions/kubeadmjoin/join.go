@@ -18,18 +18,22 @@ limitations under the License.
 package kubeadmjoin
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/cluster/constants"
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/errors"
-	"sigs.k8s.io/kind/pkg/exec"
 	"sigs.k8s.io/kind/pkg/internal/version"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
 
 // Action implements action for creating the kubeadm join
@@ -84,7 +88,9 @@ func joinSecondaryControlPlanes(
 	// (this is not safe currently)
 	for _, node := range secondaryControlPlanes {
 		node := node // capture loop variable
-		if err := runKubeadmJoin(ctx.Logger, node); err != nil {
+		err := runKubeadmJoin(ctx.Context, ctx.Logger, node, len(kubeadm.ComponentPatches(ctx.Config)) > 0, config.TimeoutDuration(ctx.Config.Timeouts.Join))
+		ctx.Status.NodeEnd(node.String(), err == nil)
+		if err != nil {
 			return err
 		}
 	}
@@ -105,7 +111,9 @@ func joinWorkers(
 	for _, node := range workers {
 		node := node // capture loop variable
 		fns = append(fns, func() error {
-			return runKubeadmJoin(ctx.Logger, node)
+			err := runKubeadmJoin(ctx.Context, ctx.Logger, node, len(kubeadm.ComponentPatches(ctx.Config)) > 0, config.TimeoutDuration(ctx.Config.Timeouts.Join))
+			ctx.Status.NodeEnd(node.String(), err == nil)
+			return err
 		})
 	}
 	if err := errors.UntilErrorConcurrent(fns); err != nil {
@@ -117,7 +125,7 @@ func joinWorkers(
 }
 
 // runKubeadmJoin executes kubeadm join command
-func runKubeadmJoin(logger log.Logger, node nodes.Node) error {
+func runKubeadmJoin(ctx context.Context, logger log.Logger, node nodes.Node, usePatches bool, timeout time.Duration) error {
 	kubeVersionStr, err := nodeutils.KubeVersion(node)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kubernetes version from node")
@@ -139,11 +147,14 @@ func runKubeadmJoin(logger log.Logger, node nodes.Node) error {
 		// Skip preflight to avoid pulling images.
 		// Kind pre-pulls images and preflight may conflict with that.
 		args = append(args, "--skip-phases=preflight")
+		// Patches are also set in the config file for newer versions.
+		if usePatches {
+			args = append(args, "--experimental-patches="+kubeadm.PatchesDir)
+		}
 	}
 
 	// run kubeadm join
-	cmd := node.Command("kubeadm", args...)
-	lines, err := exec.CombinedOutputLines(cmd)
+	lines, err := common.RunInNodeWithTimeout(ctx, node, timeout, "join", "kubeadm", args...)
 	logger.V(3).Info(strings.Join(lines, "\n"))
 	if err != nil {
 		return errors.Wrap(err, "failed to join node with kubeadm")
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recordconfig implements the action to record the cluster's config
+// in its nodes, so that the cluster can be cloned later
+package recordconfig
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// ConfigPath is where the control plane nodes record the defaulted cluster
+// config the cluster was created with
+const ConfigPath = "/kind/cluster-config.json"
+
+type action struct{}
+
+// NewAction returns a new action for recording the cluster config
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	encoded, err := encode(ctx.Config)
+	if err != nil {
+		return err
+	}
+	for _, node := range controlPlanes {
+		if err := nodeutils.WriteFile(node, ConfigPath, encoded); err != nil {
+			return errors.Wrapf(err, "failed to record cluster config on node %q", node)
+		}
+	}
+	return nil
+}
+
+// Read returns the config the cluster with allNodes was created with, or an
+// error if it was created by a kind version that did not record it
+func Read(allNodes []nodes.Node) (*config.Cluster, error) {
+	node, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	if err := node.Command("test", "-f", ConfigPath).Run(); err != nil {
+		return nil, errors.New("the cluster config was not recorded, the cluster was created with an older kind version")
+	}
+	raw, err := exec.Output(node.Command("cat", ConfigPath))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the cluster config")
+	}
+	return decode(raw)
+}
+
+// encode encodes the internal config, the recorded config is only read by
+// the same kind version or later ones
+func encode(cfg *config.Cluster) (string, error) {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode the cluster config")
+	}
+	return string(raw) + "\n", nil
+}
+
+func decode(raw []byte) (*config.Cluster, error) {
+	cfg := &config.Cluster{}
+	if err := json.Unmarshal(raw, cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the cluster config")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recordconfig
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Cluster{}
+	config.SetDefaultsCluster(cfg)
+	cfg.Name = "source"
+	cfg.FeatureGates = map[string]bool{"SomeGate": true}
+	cfg.Nodes = append(cfg.Nodes, config.Node{
+		Role:  config.WorkerRole,
+		Image: "kindest/node:v1.31.0",
+		ExtraPortMappings: []config.PortMapping{{
+			ContainerPort: 80,
+			HostPort:      8080,
+			Protocol:      config.PortMappingProtocolTCP,
+		}},
+		Labels: map[string]string{"tier": "frontend"},
+	})
+	encoded, err := encode(cfg)
+	assert.ExpectError(t, false, err)
+	decoded, err := decode([]byte(encoded))
+	assert.ExpectError(t, false, err)
+	assert.DeepEqual(t, cfg, decoded)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := decode([]byte("not json"))
+	assert.ExpectError(t, true, err)
+}
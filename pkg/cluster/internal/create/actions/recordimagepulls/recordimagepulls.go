@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recordimagepulls implements the action to record the images the
+// nodes' container runtime is asked to pull
+package recordimagepulls
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+)
+
+// LogPath is where each node records the container runtime's image pull
+// log lines
+const LogPath = "/kind/image-pulls.log"
+
+const unitPath = "/etc/systemd/system/kind-image-pulls.service"
+
+// the recorder follows the container runtime's log from the start, so no
+// pulls are missed when it restarts, duplicates are removed when reading
+const unit = `[Unit]
+Description=kind image pull recorder
+After=containerd.service crio.service
+
+[Service]
+ExecStart=/bin/sh -c 'journalctl --follow --lines=all --output=cat --unit=containerd --unit=crio | grep --line-buffered -e "PullImage " -e "Pulling image: " >> ` + LogPath + `'
+Restart=always
+
+[Install]
+WantedBy=multi-user.target
+`
+
+type action struct{}
+
+// NewAction returns a new action for recording image pulls
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Recording image pulls 📼")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+
+	fns := []func() error{}
+	for _, node := range kubeNodes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			if err := nodeutils.WriteFile(node, unitPath, unit); err != nil {
+				return err
+			}
+			if err := node.Command("systemctl", "enable", "--now", "kind-image-pulls.service").Run(); err != nil {
+				return errors.Wrapf(err, "failed to start recording image pulls on node %q", node)
+			}
+			return nil
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return err
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
+
+// Read returns the images pulled on the cluster with allNodes, sorted and
+// fully qualified, or an error if the pulls were not recorded
+func Read(allNodes []nodes.Node) ([]string, error) {
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{}
+	for _, node := range kubeNodes {
+		if err := node.Command("test", "-f", LogPath).Run(); err != nil {
+			return nil, errors.Errorf("image pulls are not recorded on node %q, create the cluster with recordImagePulls: true", node)
+		}
+		nodeLines, err := exec.OutputLines(node.Command("cat", LogPath))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read image pulls on node %q", node)
+		}
+		lines = append(lines, nodeLines...)
+	}
+	return parsePulls(lines), nil
+}
+
+// parsePulls returns the unique, fully qualified images in the recorded
+// containerd and CRI-O log lines, sorted
+func parsePulls(lines []string) []string {
+	seen := map[string]bool{}
+	images := []string{}
+	for _, line := range lines {
+		image := pulledImage(line)
+		if image == "" || seen[image] {
+			continue
+		}
+		seen[image] = true
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images
+}
+
+// pulledImage returns the image from a CRI-O log line such as
+//
+//	msg="Pulling image: nginx:latest" id=... name=/runtime.v1.ImageService/PullImage
+//
+// or a containerd log line such as
+//
+//	msg="PullImage \"nginx:latest\""
+//
+// or "" if there is none
+func pulledImage(line string) string {
+	// CRI-O lines also name the PullImage method, so check for these first
+	if i := strings.Index(line, "Pulling image: "); i != -1 {
+		fields := strings.Fields(line[i+len("Pulling image: "):])
+		if len(fields) == 0 {
+			return ""
+		}
+		return normalizeImage(strings.TrimRight(fields[0], `"`))
+	}
+	if i := strings.Index(line, "PullImage "); i != -1 {
+		// the image is quoted, and the quotes are escaped in the logfmt msg
+		rest := strings.TrimPrefix(strings.TrimPrefix(line[i+len("PullImage "):], `\`), `"`)
+		end := strings.IndexAny(rest, `\"`)
+		if end <= 0 {
+			return ""
+		}
+		return normalizeImage(rest[:end])
+	}
+	return ""
+}
+
+// normalizeImage returns the fully qualified form of image, e.g.
+// docker.io/library/nginx:latest for nginx
+func normalizeImage(image string) string {
+	const (
+		defaultDomain    = "docker.io"
+		officialRepoName = "library"
+	)
+	normalized := image
+	i := strings.IndexRune(normalized, '/')
+	if i == -1 || (!strings.ContainsAny(normalized[:i], ".:") && normalized[:i] != "localhost") {
+		if i == -1 {
+			normalized = officialRepoName + "/" + normalized
+		}
+		normalized = defaultDomain + "/" + normalized
+	}
+	// add the default tag if there is neither a tag nor a digest, the tag
+	// follows the last path component so that registry ports are not tags
+	name := normalized[strings.LastIndex(normalized, "/")+1:]
+	if !strings.ContainsAny(name, ":@") {
+		normalized += ":latest"
+	}
+	return normalized
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recordimagepulls
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParsePulls(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		`time="2024-06-01T10:00:00.000000000Z" level=info msg="PullImage \"nginx\""`,
+		`time="2024-06-01T10:00:05.000000000Z" level=info msg="PullImage \"nginx\" returns image reference \"sha256:0123\""`,
+		`time="2024-06-01T10:00:06.000000000Z" level=info msg="PullImage \"localhost:5001/app:dev\""`,
+		`time="2024-06-01T10:00:07.000000000Z" level=info msg="PullImage \"registry.k8s.io/e2e-test-images/agnhost:2.53\""`,
+		`time="2024-06-01T10:00:08.000000000Z" level=info msg="Pulling image: quay.io/metallb/speaker:v0.14.8" id=abc name=/runtime.v1.ImageService/PullImage extra=1`,
+		`time="2024-06-01T10:00:09.000000000Z" level=info msg="Pulling image: busybox"`,
+		`time="2024-06-01T10:00:10.000000000Z" level=info msg="ImageCreate event name:\"docker.io/library/nginx:latest\""`,
+	}
+	assert.DeepEqual(t, []string{
+		"docker.io/library/busybox:latest",
+		"docker.io/library/nginx:latest",
+		"localhost:5001/app:dev",
+		"quay.io/metallb/speaker:v0.14.8",
+		"registry.k8s.io/e2e-test-images/agnhost:2.53",
+	}, parsePulls(lines))
+}
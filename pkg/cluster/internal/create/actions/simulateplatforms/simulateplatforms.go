@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulateplatforms implements an action to keep the node level
+// system DaemonSets running on nodes simulating another operating system
+package simulateplatforms
+
+import (
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// removeOSSelectorPatch drops the kubernetes.io/os: linux node selector, the
+// simulated nodes still run linux containers and need networking
+const removeOSSelectorPatch = `{"spec":{"template":{"spec":{"nodeSelector":{"kubernetes.io/os":null}}}}}`
+
+type action struct{}
+
+// NewAction returns a new action for nodes simulating other platforms
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	daemonSets := systemDaemonSets(ctx.Config)
+	if len(daemonSets) == 0 {
+		return nil
+	}
+
+	ctx.Status.Start("Simulating node platforms 🪟")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+
+	// get the target node for this task
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	node := controlPlanes[0] // kind expects at least one always
+
+	for _, name := range daemonSets {
+		if err := node.Command(
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+			"-n", "kube-system", "patch", "daemonset", name, "-p", removeOSSelectorPatch,
+		).Run(); err != nil {
+			return errors.Wrapf(err, "failed to patch daemonset %q", name)
+		}
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
+
+// systemDaemonSets returns the kube-system DaemonSets kind installs that
+// must also run on the nodes in cfg simulating an OS other than linux
+func systemDaemonSets(cfg *config.Cluster) []string {
+	simulated := false
+	for _, node := range cfg.Nodes {
+		if node.Simulate.OS != "" && node.Simulate.OS != "linux" {
+			simulated = true
+		}
+	}
+	if !simulated {
+		return nil
+	}
+	daemonSets := []string{}
+	if cfg.Networking.KubeProxyMode != config.NoneProxyMode {
+		daemonSets = append(daemonSets, "kube-proxy")
+	}
+	if !cfg.Networking.DisableDefaultCNI {
+		daemonSets = append(daemonSets, "kindnet")
+	}
+	return daemonSets
+}
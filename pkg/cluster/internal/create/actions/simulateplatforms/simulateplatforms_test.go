@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulateplatforms
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestSystemDaemonSets(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Cluster  func(cfg *config.Cluster)
+		Expected []string
+	}{
+		{
+			Name:     "no simulated nodes",
+			Cluster:  func(cfg *config.Cluster) {},
+			Expected: nil,
+		},
+		{
+			Name: "simulated arch only",
+			Cluster: func(cfg *config.Cluster) {
+				cfg.Nodes[0].Simulate.Arch = "arm64"
+			},
+			Expected: nil,
+		},
+		{
+			Name: "simulated windows",
+			Cluster: func(cfg *config.Cluster) {
+				cfg.Nodes[0].Simulate.OS = "windows"
+			},
+			Expected: []string{"kube-proxy", "kindnet"},
+		},
+		{
+			Name: "simulated windows without kube-proxy or the default CNI",
+			Cluster: func(cfg *config.Cluster) {
+				cfg.Nodes[0].Simulate.OS = "windows"
+				cfg.Networking.KubeProxyMode = config.NoneProxyMode
+				cfg.Networking.DisableDefaultCNI = true
+			},
+			Expected: []string{},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &config.Cluster{}
+			config.SetDefaultsCluster(cfg)
+			tc.Cluster(cfg)
+			assert.DeepEqual(t, tc.Expected, systemDaemonSets(cfg))
+		})
+	}
+}
@@ -19,31 +19,44 @@ package waitforready
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
-	"sigs.k8s.io/kind/pkg/cluster/nodes"
-	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cluster/internal/ready"
 	"sigs.k8s.io/kind/pkg/errors"
-	"sigs.k8s.io/kind/pkg/exec"
-	"sigs.k8s.io/kind/pkg/internal/version"
 )
 
+// defaultConditionsWaitTime is how long to wait for the additional conditions
+// when no wait time is set
+const defaultConditionsWaitTime = 5 * time.Minute
+
 // Action implements an action for waiting for the cluster to be ready
 type Action struct {
-	waitTime time.Duration
+	waitTime   time.Duration
+	conditions []ready.Condition
 }
 
-// NewAction returns a new action for waiting for the cluster to be ready
-func NewAction(waitTime time.Duration) actions.Action {
+// NewAction returns a new action for waiting for the cluster to be ready,
+// and then for any additional conditions
+func NewAction(waitTime time.Duration, conditions ...ready.Condition) actions.Action {
 	return &Action{
-		waitTime: waitTime,
+		waitTime:   waitTime,
+		conditions: conditions,
 	}
 }
 
 // Execute runs the action
 func (a *Action) Execute(ctx *actions.ActionContext) error {
+	startTime := time.Now()
+	if err := a.waitForControlPlane(ctx, startTime); err != nil {
+		return err
+	}
+	return a.waitForConditions(ctx, startTime)
+}
+
+// waitForControlPlane waits for the control plane nodes to be Ready, only
+// warning if they are not
+func (a *Action) waitForControlPlane(ctx *actions.ActionContext, startTime time.Time) error {
 	// skip entirely if the wait time is 0
 	if a.waitTime == time.Duration(0) {
 		return nil
@@ -59,32 +72,15 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 	if err != nil {
 		return err
 	}
-	// get a control plane node to use to check cluster status
-	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
-	if err != nil {
-		return err
-	}
-	node := controlPlanes[0] // kind expects at least one always
-
-	// Wait for the nodes to reach Ready status.
-	startTime := time.Now()
 
-	// TODO: Remove the below handling once kubeadm 1.23 is no longer supported.
-	// https://github.com/kubernetes-sigs/kind/issues/1699
-	rawVersion, err := nodeutils.KubeVersion(node)
+	// Wait for the control plane nodes to reach Ready status.
+	isReady, err := ready.Wait(ctx.Context, allNodes, ready.Condition{Type: ready.ControlPlaneReady}, startTime.Add(a.waitTime))
 	if err != nil {
-		return errors.Wrap(err, "failed to get Kubernetes version from node")
-	}
-	kubeVersion, err := version.ParseSemantic(rawVersion)
-	if err != nil {
-		return errors.Wrap(err, "could not parse Kubernetes version")
+		return err
 	}
-	selectorLabel := "node-role.kubernetes.io/control-plane"
-	if kubeVersion.LessThan(version.MustParseSemantic("v1.24.0-alpha.1.591+a3d5e5598290df")) {
-		selectorLabel = "node-role.kubernetes.io/master"
+	if err := ctx.Context.Err(); err != nil {
+		return err
 	}
-
-	isReady := waitForReady(node, startTime.Add(a.waitTime), selectorLabel)
 	if !isReady {
 		ctx.Status.End(false)
 		ctx.Logger.V(0).Info(" • WARNING: Timed out waiting for Ready ⚠️")
@@ -97,49 +93,47 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 	return nil
 }
 
-// WaitForReady uses kubectl inside the "node" container to check if the
-// control plane nodes are "Ready".
-func waitForReady(node nodes.Node, until time.Time, selectorLabel string) bool {
-	return tryUntil(until, func() bool {
-		cmd := node.Command(
-			"kubectl",
-			"--kubeconfig=/etc/kubernetes/admin.conf",
-			"get",
-			"nodes",
-			"--selector="+selectorLabel,
-			// When the node reaches status ready, the status field will be set
-			// to true.
-			"-o=jsonpath='{.items..status.conditions[-1:].status}'",
+// waitForConditions waits for each of the additional conditions in order,
+// failing if any is not met in time
+func (a *Action) waitForConditions(ctx *actions.ActionContext, startTime time.Time) error {
+	if len(a.conditions) == 0 {
+		return nil
+	}
+	waitTime := a.waitTime
+	if waitTime == time.Duration(0) {
+		waitTime = defaultConditionsWaitTime
+	}
+	until := startTime.Add(waitTime)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+
+	for _, condition := range a.conditions {
+		ctx.Status.Start(
+			fmt.Sprintf(
+				"Waiting ≤ %s for %s ⏳",
+				formatDuration(time.Until(until)),
+				condition,
+			),
 		)
-		lines, err := exec.OutputLines(cmd)
+		met, err := ready.Wait(ctx.Context, allNodes, condition, until)
 		if err != nil {
-			return false
+			ctx.Status.End(false)
+			return err
 		}
-
-		// 'lines' will return the status of all nodes labeled as master. For
-		// example, if we have three control plane nodes, and all are ready,
-		// then the status will have the following format: `True True True'.
-		status := strings.Fields(lines[0])
-		for _, s := range status {
-			// Check node status. If node is ready then this will be 'True',
-			// 'False' or 'Unknown' otherwise.
-			if !strings.Contains(s, "True") {
-				return false
-			}
+		if err := ctx.Context.Err(); err != nil {
+			ctx.Status.End(false)
+			return err
 		}
-		return true
-	})
-}
-
-// helper that calls `try()“ in a loop until the deadline `until`
-// has passed or `try()`returns true, returns whether try ever returned true
-func tryUntil(until time.Time, try func() bool) bool {
-	for until.After(time.Now()) {
-		if try() {
-			return true
+		if !met {
+			ctx.Status.End(false)
+			return errors.Errorf("timed out after %s waiting for ready condition %s", formatDuration(waitTime), condition)
 		}
+		ctx.Status.End(true)
 	}
-	return false
+	return nil
 }
 
 func formatDuration(duration time.Duration) string {
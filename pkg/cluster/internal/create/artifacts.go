@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installloadbalancerpool"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installmetricsserver"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installstorage"
+	"sigs.k8s.io/kind/pkg/cluster/internal/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// Artifacts returns the sorted list of container images needed to create
+// a cluster with opts: the node images, the images preloaded in each node
+// image, and the loadbalancer, addon and hollow node images if the config
+// requires them
+func Artifacts(p providers.Provider, opts *ClusterOptions) ([]string, error) {
+	// default / process options (namely config)
+	if err := fixupOptions(opts); err != nil {
+		return nil, err
+	}
+	if err := opts.Config.Validate(); err != nil {
+		return nil, err
+	}
+
+	images := common.RequiredNodeImages(opts.Config)
+	for _, nodeImage := range images.List() {
+		bundled, err := p.ListBundledImages(nodeImage)
+		if err != nil {
+			return nil, err
+		}
+		images.Insert(bundled...)
+	}
+	if config.ClusterHasImplicitLoadBalancer(opts.Config) {
+		images.Insert(loadbalancer.Image)
+	}
+	if opts.Config.Addons.MetricsServer {
+		images.Insert(installmetricsserver.Image)
+	}
+	if opts.Config.Addons.LoadBalancerPool.MetalLB {
+		images.Insert(installloadbalancerpool.MetalLBImages...)
+	}
+	if opts.Config.Addons.DefaultStorage == config.CSIHostPathDefaultStorage {
+		images.Insert(installstorage.CSIHostPathImages...)
+	}
+	if opts.Config.Addons.SharedStorage.HostPath != "" {
+		images.Insert(installstorage.SharedStorageImages...)
+	}
+	if opts.Config.HollowNodes.Count > 0 {
+		images.Insert(opts.Config.HollowNodes.Image)
+	}
+	return images.List(), nil
+}
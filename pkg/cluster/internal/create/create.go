@@ -17,29 +17,51 @@ limitations under the License.
 package create
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
+	"path"
+	"reflect"
 	"time"
 
 	"al.essio.dev/pkg/shellescape"
 
+	"sigs.k8s.io/kind/pkg/apis/config/defaults"
 	"sigs.k8s.io/kind/pkg/cluster/internal/delete"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/ready"
+	"sigs.k8s.io/kind/pkg/cluster/internal/verify"
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
 	"sigs.k8s.io/kind/pkg/internal/apis/config/encoding"
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/sets"
+	"sigs.k8s.io/kind/pkg/internal/tracing"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/capturediagnostics"
 	configaction "sigs.k8s.io/kind/pkg/cluster/internal/create/actions/config"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/configurelogging"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/hollownodes"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installcni"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installhostroutes"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installloadbalancerpool"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installmetricsserver"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installruntimeclasses"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installstaticpods"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installstorage"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installsystemdunits"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/keepportmap"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/kubeadminit"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/kubeadmjoin"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/recordconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/recordimagepulls"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/simulateplatforms"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/waitforready"
 	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/protection"
 )
 
 const (
@@ -53,10 +75,39 @@ type ClusterOptions struct {
 	Config       *config.Cluster
 	NameOverride string // overrides config.Name
 	// NodeImage overrides the nodes' images in Config if non-zero
-	NodeImage      string
-	Retain         bool
+	NodeImage string
+	// ControlPlaneImage and WorkerImage override the images of the nodes
+	// with that role in Config if non-zero, taking precedence over NodeImage
+	ControlPlaneImage string
+	WorkerImage       string
+	// DefaultNodeImage replaces kind's default image in Config if non-zero
+	DefaultNodeImage string
+	// InsecureRegistries are added to the Config's insecure registries
+	InsecureRegistries []string
+	// Retain keeps the nodes when creation fails or is cancelled
+	Retain bool
+	// RetainOnFailure keeps the nodes when creation fails, but not when it
+	// is cancelled
+	RetainOnFailure bool
+	// Protect marks the cluster as protected from deletion without force
+	Protect bool
+	// AutoRemapPorts replaces conflicting host ports with free ports
+	AutoRemapPorts bool
 	WaitForReady   time.Duration
-	KubeconfigPath string
+	// DefaultWaitForReady is used if neither WaitForReady nor the config's
+	// nodesReady timeout are set
+	DefaultWaitForReady time.Duration
+	KubeconfigPath      string
+	// KubeconfigFileOptions set the mode and owner of the kubeconfig file
+	KubeconfigFileOptions kubeconfig.FileOptions
+	// KubeconfigContextTemplate names the cluster's kubeconfig entries,
+	// see kubeconfig.ContextForCluster
+	KubeconfigContextTemplate string
+	// Verify runs the smoke tests once the cluster is created
+	Verify bool
+	// Foreground keeps Cluster running once the cluster is created, until
+	// ctx is cancelled, and then deletes the cluster
+	Foreground bool
 	// see https://github.com/kubernetes-sigs/kind/issues/324
 	StopBeforeSettingUpKubernetes bool // if false kind should setup kubernetes after creating nodes
 	// Options to control output
@@ -64,8 +115,10 @@ type ClusterOptions struct {
 	DisplaySalutation bool
 }
 
-// Cluster creates a cluster
-func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) error {
+// Cluster creates a cluster, cancelling ctx stops creation and deletes any
+// partially created nodes unless opts.Retain is set. Failing to create
+// deletes them unless opts.Retain or opts.RetainOnFailure is set.
+func Cluster(ctx context.Context, logger log.Logger, p providers.Provider, opts *ClusterOptions) (err error) {
 	// validate provider first
 	if err := validateProvider(p); err != nil {
 		return err
@@ -90,6 +143,43 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 	if err := opts.Config.Validate(); err != nil {
 		return err
 	}
+	// the kubeconfig is only written once the cluster is up, so check that
+	// its entries can be named before provisioning anything
+	if _, err := kubeconfig.ContextForCluster(opts.Config.Name, opts.KubeconfigContextTemplate); err != nil {
+		return err
+	}
+	if opts.Config.HollowNodes.Count > 0 && !p.SupportsHollowNodes() {
+		return errors.Errorf("hollowNodes are not supported by the %s provider", p)
+	}
+	// hostPorts only work if the CNI installed instead handles them
+	if opts.Config.Networking.DisableDefaultCNI && !opts.Config.Networking.KeepPortmap {
+		logger.Warn("networking.disableDefaultCNI is set: hostPorts will not work unless the CNI you install handles them, " +
+			"e.g. by chaining the portmap plugin. Set networking.keepPortmap to have kind chain it for you")
+	}
+	readyConditions := make([]ready.Condition, 0, len(opts.Config.ReadyConditions))
+	for _, condition := range opts.Config.ReadyConditions {
+		c, err := ready.ParseCondition(condition)
+		if err != nil {
+			return errors.Wrap(err, "invalid readyConditions")
+		}
+		readyConditions = append(readyConditions, c)
+	}
+
+	// check the requested host ports are free before creating anything,
+	// a remote runtime's host ports can only be checked for duplicates
+	if err := checkHostPorts(logger, opts.Config, opts.AutoRemapPorts, p.IsLocal()); err != nil {
+		return err
+	}
+
+	// check the mounted host paths exist, rather than failing in the runtime.
+	// a remote runtime mounts paths from its own host, which we cannot check
+	if p.IsLocal() {
+		if err := checkHostPaths(logger, opts.Config); err != nil {
+			return err
+		}
+	} else {
+		logger.V(1).Info("Not checking extraMounts hostPaths, the container runtime is not local")
+	}
 
 	// setup a status object to show progress to the user
 	status := cli.StatusForLogger(logger)
@@ -98,18 +188,56 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 	logger.V(0).Infof("Creating cluster %q ...\n", opts.Config.Name)
 
 	// Create node containers implementing defined config Nodes
-	if err := p.Provision(status, opts.Config); err != nil {
+	span := tracing.Start("provision")
+	if err := p.Provision(ctx, status, opts.Config); err != nil {
+		span.End(err)
 		// In case of errors nodes are deleted (except if retain is explicitly set)
-		if !opts.Retain {
-			_ = delete.Cluster(logger, p, opts.Config.Name, opts.KubeconfigPath)
-		}
-		return err
+		cleanupFailedCreate(ctx, logger, p, opts)
+		return wrapCancelled(ctx, err)
 	}
+	span.End(nil)
 
 	// TODO(bentheelder): make this controllable from the command line?
 	actionsToRun := []actions.Action{
 		loadbalancer.NewAction(), // setup external loadbalancer
 		configaction.NewAction(), // setup kubeadm config
+		recordconfig.NewAction(), // record the cluster config for cloning
+	}
+	// install any extra systemd units and kubelet env before kubeadm runs
+	for _, node := range opts.Config.Nodes {
+		if len(node.SystemdUnits) > 0 || len(node.Env) > 0 {
+			actionsToRun = append(actionsToRun, installsystemdunits.NewAction())
+			break
+		}
+	}
+	// chain portmap into the CNI config before the CNI is installed
+	if opts.Config.Networking.KeepPortmap {
+		actionsToRun = append(actionsToRun, keepportmap.NewAction())
+	}
+	// install any extra static pods before kubeadm starts the kubelet, so
+	// that they start before the API server
+	for _, node := range opts.Config.Nodes {
+		if len(node.StaticPods) > 0 {
+			actionsToRun = append(actionsToRun, installstaticpods.NewAction())
+			break
+		}
+	}
+	// configure the node components' logging before kubeadm runs, so that
+	// the kubelet starts with it
+	for _, node := range opts.Config.Nodes {
+		if node.Logging != (config.NodeLogging{}) {
+			actionsToRun = append(actionsToRun, configurelogging.NewAction())
+			break
+		}
+	}
+	// record image pulls before kubeadm runs, so that none are missed
+	if opts.Config.RecordImagePulls {
+		actionsToRun = append(actionsToRun, recordimagepulls.NewAction())
+	}
+	// capture diagnostics before kubeadm runs, so that the kubelet starts
+	// with core dumps enabled
+	if opts.Config.Diagnostics.OOMEvents || opts.Config.Diagnostics.CoreDumps {
+		actionsToRun = append(actionsToRun, capturediagnostics.NewAction(opts.Config.Diagnostics))
 	}
 	if !opts.StopBeforeSettingUpKubernetes {
 		actionsToRun = append(actionsToRun,
@@ -121,56 +249,205 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 				installcni.NewAction(), // install CNI
 			)
 		}
+		// keep the CNI and kube-proxy on nodes simulating another OS, if any
+		actionsToRun = append(actionsToRun,
+			simulateplatforms.NewAction(),
+		)
+		// optionally install metrics-server once the CNI is in place
+		if opts.Config.Addons.MetricsServer {
+			actionsToRun = append(actionsToRun,
+				installmetricsserver.NewAction(), // install metrics-server
+			)
+		}
+		// optionally install the default and shared storage provisioners
+		if opts.Config.Addons.DefaultStorage != config.NoneDefaultStorage || opts.Config.Addons.SharedStorage.HostPath != "" {
+			actionsToRun = append(actionsToRun,
+				installstorage.NewAction(opts.Config), // install StorageClass
+			)
+		}
+		// the --wait flag overrides the nodesReady timeout from the config
+		waitForReady := opts.WaitForReady
+		if waitForReady == 0 {
+			waitForReady = config.TimeoutDuration(opts.Config.Timeouts.NodesReady)
+		}
+		if waitForReady == 0 {
+			waitForReady = opts.DefaultWaitForReady
+		}
 		// add remaining steps
 		actionsToRun = append(actionsToRun,
-			installstorage.NewAction(),                // install StorageClass
-			kubeadmjoin.NewAction(),                   // run kubeadm join
-			waitforready.NewAction(opts.WaitForReady), // wait for cluster readiness
+			installruntimeclasses.NewAction(), // install RuntimeClasses
+			kubeadmjoin.NewAction(),           // run kubeadm join
+		)
+		// optionally route the nodes' pod CIDRs from the host once all
+		// nodes have joined
+		if opts.Config.Networking.HostRoutes {
+			actionsToRun = append(actionsToRun,
+				installhostroutes.NewAction(), // add host routes
+			)
+		}
+		// optionally reserve LoadBalancer addresses and install MetalLB,
+		// once all nodes have joined so that MetalLB can be scheduled
+		if opts.Config.Addons.LoadBalancerPool.Size > 0 {
+			actionsToRun = append(actionsToRun,
+				installloadbalancerpool.NewAction(opts.Config.Addons.LoadBalancerPool), // reserve LoadBalancer addresses
+			)
+		}
+		// optionally add hollow nodes, once the real nodes are set up as
+		// they only register with the control plane
+		if opts.Config.HollowNodes.Count > 0 {
+			actionsToRun = append(actionsToRun,
+				hollownodes.NewAction(opts.Config.HollowNodes), // create hollow nodes
+			)
+		}
+		actionsToRun = append(actionsToRun,
+			waitforready.NewAction(waitForReady, readyConditions...), // wait for cluster readiness
 		)
 	}
 
 	// run all actions
-	actionsContext := actions.NewActionContext(logger, status, p, opts.Config)
+	actionsContext := actions.NewActionContext(ctx, logger, status, p, opts.Config)
 	for _, action := range actionsToRun {
-		if err := action.Execute(actionsContext); err != nil {
-			if !opts.Retain {
-				_ = delete.Cluster(logger, p, opts.Config.Name, opts.KubeconfigPath)
+		// stop before the next action if creation was cancelled
+		err := ctx.Err()
+		if err == nil {
+			span := tracing.Start("action " + actionName(action))
+			err = action.Execute(actionsContext)
+			span.End(err)
+		}
+		if err != nil {
+			cleanupFailedCreate(ctx, logger, p, opts)
+			return wrapCancelled(ctx, err)
+		}
+	}
+
+	// in the foreground the cluster is deleted once ctx is cancelled, or if
+	// the remaining steps fail, unless retained after failing
+	if opts.Foreground {
+		defer func() {
+			if err != nil && retainNodes(opts, ctx.Err() != nil) {
+				return
+			}
+			logger.V(0).Infof("Deleting cluster %q ...", opts.Config.Name)
+			if deleteErr := delete.Cluster(logger, p, opts.Config.Name, opts.KubeconfigContextTemplate, opts.KubeconfigPath, true); err == nil {
+				err = deleteErr
 			}
+		}()
+	}
+
+	// protect the cluster from deletion if requested
+	if opts.Protect {
+		n, err := p.ListNodes(opts.Config.Name)
+		if err != nil {
+			return err
+		}
+		if err := protection.Protect(n); err != nil {
 			return err
 		}
 	}
 
 	// skip the rest if we're not setting up kubernetes
 	if opts.StopBeforeSettingUpKubernetes {
+		runInForeground(ctx, logger, opts)
 		return nil
 	}
 
-	// try exporting kubeconfig with backoff for locking failures
-	// TODO: factor out into a public errors API w/ backoff handling?
-	// for now this is easier than coming up with a good API
-	var err error
-	for _, b := range []time.Duration{0, time.Millisecond, time.Millisecond * 50, time.Millisecond * 100} {
-		time.Sleep(b)
-		if err = kubeconfig.Export(p, opts.Config.Name, opts.KubeconfigPath, true); err == nil {
-			break
-		}
-	}
-	if err != nil {
+	// export the kubeconfig, this waits for concurrent writers to release
+	// the kubeconfig lock
+	if err := kubeconfig.Export(p, opts.Config.Name, opts.KubeconfigContextTemplate, opts.KubeconfigPath, true, opts.KubeconfigFileOptions); err != nil {
 		return err
 	}
 
+	// optionally run the smoke tests, a cluster failing them is kept
+	if opts.Verify {
+		if err := verifyCluster(ctx, logger, p, opts.Config.Name); err != nil {
+			return err
+		}
+	}
+
 	// optionally display usage
 	if opts.DisplayUsage {
-		logUsage(logger, opts.Config.Name, opts.KubeconfigPath)
+		logUsage(logger, opts.Config.Name, opts.KubeconfigContextTemplate, opts.KubeconfigPath)
 	}
 	// optionally give the user a friendly salutation
 	if opts.DisplaySalutation {
 		logger.V(0).Info("")
 		logSalutation(logger)
 	}
+	runInForeground(ctx, logger, opts)
 	return nil
 }
 
+// runInForeground waits until ctx is cancelled if opts.Foreground is set,
+// the cluster is then deleted by Cluster
+func runInForeground(ctx context.Context, logger log.Logger, opts *ClusterOptions) {
+	if !opts.Foreground {
+		return
+	}
+	logger.V(0).Infof("Running cluster %q in the foreground, interrupt to delete it", opts.Config.Name)
+	<-ctx.Done()
+}
+
+// verifyCluster runs the smoke tests against the created cluster
+func verifyCluster(ctx context.Context, logger log.Logger, p providers.Provider, name string) error {
+	n, err := p.ListNodes(name)
+	if err != nil {
+		return err
+	}
+	logger.V(0).Info("Verifying cluster ...")
+	span := tracing.Start("verify")
+	results, err := verify.Run(ctx, n)
+	if err == nil {
+		err = verify.Report(logger, results)
+	}
+	span.End(err)
+	return errors.Wrap(err, "cluster verification failed")
+}
+
+// cleanupFailedCreate deletes the nodes of a cluster that failed to be
+// created, or whose creation was cancelled, unless opts.Retain is set.
+// opts.RetainOnFailure only keeps them if creation was not cancelled.
+func cleanupFailedCreate(ctx context.Context, logger log.Logger, p providers.Provider, opts *ClusterOptions) {
+	cancelled := ctx.Err() != nil
+	if retainNodes(opts, cancelled) {
+		if cancelled {
+			logger.V(0).Infof("Cluster creation was cancelled, retaining the nodes of cluster %q", opts.Config.Name)
+		} else {
+			logger.V(0).Infof("Retaining the nodes of cluster %q for debugging, delete them with `kind delete cluster --name %s`", opts.Config.Name, opts.Config.Name)
+		}
+		return
+	}
+	if cancelled {
+		logger.V(0).Infof("Cluster creation was cancelled, deleting cluster %q ...", opts.Config.Name)
+	}
+	// delete.Cluster reports the deleted nodes
+	_ = delete.Cluster(logger, p, opts.Config.Name, opts.KubeconfigContextTemplate, opts.KubeconfigPath, true)
+}
+
+// retainNodes returns true if the nodes of a cluster that failed to be
+// created, or whose creation was cancelled, should be kept
+func retainNodes(opts *ClusterOptions, cancelled bool) bool {
+	return opts.Retain || (opts.RetainOnFailure && !cancelled)
+}
+
+// actionName returns the name of the package implementing action, e.g.
+// "kubeadminit", which identifies the action
+func actionName(action actions.Action) string {
+	t := reflect.TypeOf(action)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return path.Base(t.PkgPath())
+}
+
+// wrapCancelled notes in err that creation was cancelled if ctx is done,
+// otherwise the error from a killed command does not explain itself
+func wrapCancelled(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(err, "cluster creation was cancelled")
+	}
+	return err
+}
+
 // alreadyExists returns an error if the cluster name already exists
 // or if we had an error checking
 func alreadyExists(p providers.Provider, name string) error {
@@ -184,9 +461,10 @@ func alreadyExists(p providers.Provider, name string) error {
 	return nil
 }
 
-func logUsage(logger log.Logger, name, explicitKubeconfigPath string) {
+func logUsage(logger log.Logger, name, contextTemplate, explicitKubeconfigPath string) {
 	// construct a sample command for interacting with the cluster
-	kctx := kubeconfig.ContextForCluster(name)
+	// the context template was already validated before provisioning
+	kctx, _ := kubeconfig.ContextForCluster(name, contextTemplate)
 	sampleCommand := fmt.Sprintf("kubectl cluster-info --context %s", kctx)
 	if explicitKubeconfigPath != "" {
 		// explicit path, include this
@@ -233,10 +511,45 @@ func fixupOptions(opts *ClusterOptions) error {
 		}
 	}
 
+	// add any insecure registries to those from the config
+	insecureRegistries := sets.NewString(opts.Config.InsecureRegistries...)
+	for _, registry := range opts.InsecureRegistries {
+		if !insecureRegistries.Has(registry) {
+			insecureRegistries.Insert(registry)
+			opts.Config.InsecureRegistries = append(opts.Config.InsecureRegistries, registry)
+		}
+	}
+
 	// default config fields (important for usage as a library, where the config
 	// may be constructed in memory rather than from disk)
 	config.SetDefaultsCluster(opts.Config)
 
+	// override the images by role, e.g. to test version skew
+	for i := range opts.Config.Nodes {
+		switch {
+		case opts.Config.Nodes[i].Role == config.ControlPlaneRole && opts.ControlPlaneImage != "":
+			opts.Config.Nodes[i].Image = opts.ControlPlaneImage
+		case opts.Config.Nodes[i].Role == config.WorkerRole && opts.WorkerImage != "":
+			opts.Config.Nodes[i].Image = opts.WorkerImage
+		}
+	}
+
+	// replace kind's default image if another default was requested
+	if opts.DefaultNodeImage != "" {
+		for i := range opts.Config.Nodes {
+			if opts.Config.Nodes[i].Image == defaults.Image {
+				opts.Config.Nodes[i].Image = opts.DefaultNodeImage
+			}
+		}
+	}
+
+	// mount the shared storage directory at the same path in every node
+	if hostPath := opts.Config.Addons.SharedStorage.HostPath; hostPath != "" {
+		for i := range opts.Config.Nodes {
+			opts.Config.Nodes[i].ExtraMounts = append(opts.Config.Nodes[i].ExtraMounts, installstorage.SharedStorageMount(hostPath))
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestRetainNodes(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name      string
+		Opts      ClusterOptions
+		Cancelled bool
+		Expected  bool
+	}{
+		{
+			Name:     "failed",
+			Expected: false,
+		},
+		{
+			Name:      "cancelled",
+			Cancelled: true,
+			Expected:  false,
+		},
+		{
+			Name:     "failed with retain",
+			Opts:     ClusterOptions{Retain: true},
+			Expected: true,
+		},
+		{
+			Name:      "cancelled with retain",
+			Opts:      ClusterOptions{Retain: true},
+			Cancelled: true,
+			Expected:  true,
+		},
+		{
+			Name:     "failed with retain on failure",
+			Opts:     ClusterOptions{RetainOnFailure: true},
+			Expected: true,
+		},
+		{
+			Name:      "cancelled with retain on failure",
+			Opts:      ClusterOptions{RetainOnFailure: true},
+			Cancelled: true,
+			Expected:  false,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.BoolEqual(t, tc.Expected, retainNodes(&tc.Opts, tc.Cancelled))
+		})
+	}
+}
+
+func TestFixupOptionsInsecureRegistries(t *testing.T) {
+	t.Parallel()
+	opts := &ClusterOptions{
+		Config: &config.Cluster{
+			InsecureRegistries: []string{"localhost:5001"},
+		},
+		InsecureRegistries: []string{"kind-registry:5000", "localhost:5001"},
+	}
+	if err := fixupOptions(opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.DeepEqual(t, []string{"localhost:5001", "kind-registry:5000"}, opts.Config.InsecureRegistries)
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/recordconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// creationOnlyFields are the cluster wide config fields that are only used
+// while creating a cluster, changing them changes nothing for an existing one
+var creationOnlyFields = []string{"timeouts", "readyConditions"}
+
+// ConfigChange is a difference between the config a cluster was created
+// with and another config
+type ConfigChange struct {
+	// Field is the changed config field, e.g. "nodes[1].image"
+	Field string
+	// Old is the value the cluster was created with, empty if it was unset
+	Old string
+	// New is the value in the other config, empty if it is unset
+	New string
+	// InPlace is true if the change can be applied to the existing cluster
+	InPlace bool
+	// Apply is the command applying the change to the existing cluster if
+	// InPlace, empty if there is nothing to apply
+	Apply string
+}
+
+// Diff returns the changes from the config the existing cluster name was
+// created with to opts.Config, after defaulting and validating opts.Config
+// like creating would. Nothing is changed.
+func Diff(p providers.Provider, name string, opts *ClusterOptions) ([]ConfigChange, error) {
+	allNodes, err := p.ListNodes(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing nodes")
+	}
+	if len(allNodes) == 0 {
+		return nil, errors.Errorf("unknown cluster %q", name)
+	}
+	old, err := recordconfig.Read(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	opts.NameOverride = name
+	if err := fixupOptions(opts); err != nil {
+		return nil, err
+	}
+	if err := opts.Config.Validate(); err != nil {
+		return nil, err
+	}
+	return diffConfigs(old, opts.Config), nil
+}
+
+// diffConfigs returns the changes from old to new, the cluster wide changes
+// first and then those of each node
+func diffConfigs(old, new *config.Cluster) []ConfigChange {
+	// nodes are compared separately, they can be added and removed
+	oldCluster, newCluster := old.DeepCopy(), new.DeepCopy()
+	oldCluster.Nodes, newCluster.Nodes = nil, nil
+	changes := []ConfigChange{}
+	for _, c := range diffValues("", oldCluster, newCluster) {
+		for _, field := range creationOnlyFields {
+			if c.Field == field || strings.HasPrefix(c.Field, field+".") || strings.HasPrefix(c.Field, field+"[") {
+				c.InPlace = true
+			}
+		}
+		changes = append(changes, c)
+	}
+
+	names := nodeNames(old)
+	for i := 0; i < len(old.Nodes) || i < len(new.Nodes); i++ {
+		field := fmt.Sprintf("nodes[%d]", i)
+		switch {
+		case i >= len(new.Nodes):
+			changes = append(changes, removedNode(field, old, &old.Nodes[i], names[i]))
+		case i >= len(old.Nodes):
+			changes = append(changes, addedNode(field, old, &new.Nodes[i]))
+		default:
+			changes = append(changes, diffNode(field, old.Name, names[i], &old.Nodes[i], &new.Nodes[i])...)
+		}
+	}
+	return changes
+}
+
+// nodeNames returns the names of cfg's nodes, named like the providers do
+func nodeNames(cfg *config.Cluster) []string {
+	namer := common.MakeNodeNamer(cfg.Name)
+	names := make([]string, len(cfg.Nodes))
+	for i := range cfg.Nodes {
+		names[i] = common.NodeName(namer, &cfg.Nodes[i])
+	}
+	return names
+}
+
+// removedNode describes removing node, named name, from the cluster with cfg
+func removedNode(field string, cfg *config.Cluster, node *config.Node, name string) ConfigChange {
+	c := ConfigChange{
+		Field: field,
+		Old:   fmt.Sprintf("%s %s", node.Role, name),
+	}
+	if node.Role == config.WorkerRole {
+		c.InPlace = true
+		c.Apply = fmt.Sprintf("kind delete node --name %s %s", cfg.Name, name)
+	}
+	return c
+}
+
+// addedNode describes adding node to the cluster with cfg, which can be done
+// in place for workers that `kind create node` would configure the same way
+func addedNode(field string, cfg *config.Cluster, node *config.Node) ConfigChange {
+	c := ConfigChange{
+		Field: field,
+		New:   string(node.Role),
+	}
+	if node.Role != config.WorkerRole {
+		return c
+	}
+	opts := &NodeOptions{Image: node.Image, Labels: node.Labels}
+	added, err := nodeConfig(cfg, nil, opts)
+	if err != nil {
+		return c
+	}
+	added.Name = node.Name
+	if len(diffValues("", added, node)) > 0 {
+		return c
+	}
+	args := []string{"kind", "create", "node", "--name", cfg.Name, "--image", node.Image}
+	labels := make([]string, 0, len(node.Labels))
+	for k, v := range node.Labels {
+		labels = append(labels, "--label "+k+"="+v)
+	}
+	sort.Strings(labels)
+	args = append(args, labels...)
+	if node.Name != "" {
+		args = append(args, node.Name)
+	}
+	c.InPlace = true
+	c.Apply = strings.Join(args, " ")
+	return c
+}
+
+// diffNode returns the changes from old to new of the node named name in
+// the cluster clusterName. Added TCP port mappings can be forwarded with
+// `kind port-forward` instead, everything else needs recreating.
+func diffNode(field, clusterName, name string, old, new *config.Node) []ConfigChange {
+	oldNode, newNode := old.DeepCopy(), new.DeepCopy()
+	oldNode.ExtraPortMappings, newNode.ExtraPortMappings = nil, nil
+	changes := diffValues(field, oldNode, newNode)
+
+	for i, m := range new.ExtraPortMappings {
+		if hasPortMapping(old.ExtraPortMappings, m) {
+			continue
+		}
+		c := ConfigChange{
+			Field: fmt.Sprintf("%s.extraPortMappings[%d]", field, i),
+			New:   formatPortMapping(m),
+		}
+		if m.Protocol == "" || m.Protocol == config.PortMappingProtocolTCP {
+			c.InPlace = true
+			c.Apply = portForwardCommand(clusterName, name, m)
+		}
+		changes = append(changes, c)
+	}
+	for i, m := range old.ExtraPortMappings {
+		if !hasPortMapping(new.ExtraPortMappings, m) {
+			changes = append(changes, ConfigChange{
+				Field: fmt.Sprintf("%s.extraPortMappings[%d]", field, i),
+				Old:   formatPortMapping(m),
+			})
+		}
+	}
+	return changes
+}
+
+func hasPortMapping(mappings []config.PortMapping, m config.PortMapping) bool {
+	for _, other := range mappings {
+		if other == m {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPortMapping formats m like "127.0.0.1:8080->80/TCP"
+func formatPortMapping(m config.PortMapping) string {
+	protocol := m.Protocol
+	if protocol == "" {
+		protocol = config.PortMappingProtocolTCP
+	}
+	hostPort := fmt.Sprint(m.HostPort)
+	if m.ListenAddress != "" {
+		hostPort = m.ListenAddress + ":" + hostPort
+	}
+	return fmt.Sprintf("%s->%d/%s", hostPort, m.ContainerPort, protocol)
+}
+
+// portForwardCommand returns the `kind port-forward` command forwarding
+// m's host port to the node, while it runs
+func portForwardCommand(clusterName, nodeName string, m config.PortMapping) string {
+	args := []string{"kind", "port-forward", "--name", clusterName, "--node", nodeName}
+	if m.ListenAddress != "" && m.ListenAddress != "127.0.0.1" {
+		args = append(args, "--address", m.ListenAddress)
+	}
+	// a random host port is forwarded from the same local port instead
+	if m.HostPort > 0 {
+		args = append(args, fmt.Sprintf("%d:%d", m.HostPort, m.ContainerPort))
+	} else {
+		args = append(args, fmt.Sprint(m.ContainerPort))
+	}
+	return strings.Join(args, " ")
+}
+
+// diffValues returns the fields that differ between old and new, which must
+// be of the same type, named under field. Unset and zero values are equal.
+func diffValues(field string, old, new interface{}) []ConfigChange {
+	oldValues, newValues := map[string]string{}, map[string]string{}
+	flattenValue(field, reflect.ValueOf(old), oldValues)
+	flattenValue(field, reflect.ValueOf(new), newValues)
+	fields := []string{}
+	for f := range oldValues {
+		fields = append(fields, f)
+	}
+	for f := range newValues {
+		if _, ok := oldValues[f]; !ok {
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields)
+	changes := []ConfigChange{}
+	for _, f := range fields {
+		if oldValues[f] != newValues[f] {
+			changes = append(changes, ConfigChange{
+				Field: f,
+				Old:   oldValues[f],
+				New:   newValues[f],
+			})
+		}
+	}
+	return changes
+}
+
+// flattenValue adds the non-zero values in v to values by their field
+// name under field, e.g. "networking.podSubnet" or "labels[tier]"
+func flattenValue(field string, v reflect.Value, values map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			flattenValue(field, v.Elem(), values)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			switch {
+			case f.PkgPath != "":
+				// unexported
+			case f.Anonymous:
+				flattenValue(field, v.Field(i), values)
+			case field == "":
+				flattenValue(yamlFieldName(f.Name), v.Field(i), values)
+			default:
+				flattenValue(field+"."+yamlFieldName(f.Name), v.Field(i), values)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			flattenValue(fmt.Sprintf("%s[%d]", field, i), v.Index(i), values)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			flattenValue(fmt.Sprintf("%s[%v]", field, k.Interface()), v.MapIndex(k), values)
+		}
+	default:
+		if !v.IsZero() {
+			values[field] = fmt.Sprint(v.Interface())
+		}
+	}
+}
+
+// yamlFieldName returns the v1alpha4 YAML name of an internal config field,
+// e.g. "apiServerPort" for APIServerPort
+func yamlFieldName(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	// keep the first letter of the next word in an acronym prefix upper case
+	if upper > 1 && upper < len(runes) && unicode.IsLower(runes[upper]) {
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
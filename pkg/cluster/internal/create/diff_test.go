@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	t.Parallel()
+	newConfig := func() *config.Cluster {
+		cfg := &config.Cluster{
+			Name: "dev",
+			Nodes: []config.Node{
+				{Role: config.ControlPlaneRole, Image: "kindest/node:v1.31.0"},
+				{Role: config.WorkerRole, Image: "kindest/node:v1.31.0"},
+			},
+		}
+		config.SetDefaultsCluster(cfg)
+		return cfg
+	}
+	cases := []struct {
+		Name     string
+		Change   func(cfg *config.Cluster)
+		Expected []ConfigChange
+	}{
+		{
+			Name:     "unchanged",
+			Change:   func(cfg *config.Cluster) {},
+			Expected: []ConfigChange{},
+		},
+		{
+			Name: "cluster wide",
+			Change: func(cfg *config.Cluster) {
+				cfg.Networking.PodSubnet = "10.100.0.0/16"
+				cfg.Timeouts.NodesReady = "5m"
+			},
+			Expected: []ConfigChange{
+				{Field: "networking.podSubnet", Old: "10.244.0.0/16", New: "10.100.0.0/16"},
+				{Field: "timeouts.nodesReady", New: "5m", InPlace: true},
+			},
+		},
+		{
+			Name: "node image",
+			Change: func(cfg *config.Cluster) {
+				cfg.Nodes[1].Image = "kindest/node:v1.32.0"
+			},
+			Expected: []ConfigChange{
+				{Field: "nodes[1].image", Old: "kindest/node:v1.31.0", New: "kindest/node:v1.32.0"},
+			},
+		},
+		{
+			Name: "port mappings",
+			Change: func(cfg *config.Cluster) {
+				cfg.Nodes[0].ExtraPortMappings = []config.PortMapping{
+					{ContainerPort: 80, HostPort: 8080},
+					{ContainerPort: 53, HostPort: 5353, Protocol: config.PortMappingProtocolUDP},
+					{ContainerPort: 30000, ListenAddress: "0.0.0.0"},
+				}
+			},
+			Expected: []ConfigChange{
+				{
+					Field:   "nodes[0].extraPortMappings[0]",
+					New:     "8080->80/TCP",
+					InPlace: true,
+					Apply:   "kind port-forward --name dev --node dev-control-plane 8080:80",
+				},
+				{
+					Field: "nodes[0].extraPortMappings[1]",
+					New:   "5353->53/UDP",
+				},
+				{
+					Field:   "nodes[0].extraPortMappings[2]",
+					New:     "0.0.0.0:0->30000/TCP",
+					InPlace: true,
+					Apply:   "kind port-forward --name dev --node dev-control-plane --address 0.0.0.0 30000",
+				},
+			},
+		},
+		{
+			Name: "added workers",
+			Change: func(cfg *config.Cluster) {
+				cfg.Nodes = append(cfg.Nodes,
+					config.Node{
+						Role:   config.WorkerRole,
+						Image:  "kindest/node:v1.32.0",
+						Labels: map[string]string{"tier": "frontend"},
+					},
+					config.Node{
+						Role:              config.WorkerRole,
+						Image:             "kindest/node:v1.31.0",
+						ExtraPortMappings: []config.PortMapping{{ContainerPort: 80, HostPort: 8080}},
+					},
+				)
+			},
+			Expected: []ConfigChange{
+				{
+					Field:   "nodes[2]",
+					New:     "worker",
+					InPlace: true,
+					Apply:   "kind create node --name dev --image kindest/node:v1.32.0 --label tier=frontend",
+				},
+				{
+					Field: "nodes[3]",
+					New:   "worker",
+				},
+			},
+		},
+		{
+			Name: "removed worker",
+			Change: func(cfg *config.Cluster) {
+				cfg.Nodes = cfg.Nodes[:1]
+			},
+			Expected: []ConfigChange{
+				{
+					Field:   "nodes[1]",
+					Old:     "worker dev-worker",
+					InPlace: true,
+					Apply:   "kind delete node --name dev dev-worker",
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			new := newConfig()
+			tc.Change(new)
+			assert.DeepEqual(t, tc.Expected, diffConfigs(newConfig(), new))
+		})
+	}
+}
+
+func TestYAMLFieldName(t *testing.T) {
+	t.Parallel()
+	for name, expected := range map[string]string{
+		"Nodes":                "nodes",
+		"APIServerPort":        "apiServerPort",
+		"IPFamily":             "ipFamily",
+		"CACertFile":           "caCertFile",
+		"NodeCIDRMaskSizeIPv4": "nodeCIDRMaskSizeIPv4",
+		"DNS":                  "dns",
+	} {
+		assert.StringEqual(t, expected, yamlFieldName(name))
+	}
+}
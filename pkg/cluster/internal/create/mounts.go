@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// checkHostPaths verifies that the extraMounts hostPaths in cfg exist on the
+// host, creating them for mounts with createHostPath set. Otherwise a missing
+// hostPath fails in the container runtime, or worse docker creates it owned
+// by root.
+func checkHostPaths(logger log.Logger, cfg *config.Cluster) error {
+	errs := []error{}
+	// name nodes the same way the providers do
+	nodeNamer := common.MakeNodeNamer(cfg.Name)
+	for i := range cfg.Nodes {
+		node := &cfg.Nodes[i]
+		name := common.NodeName(nodeNamer, node)
+		for _, m := range node.ExtraMounts {
+			if err := checkHostPath(logger, m); err != nil {
+				errs = append(errs, errors.Wrapf(err, "node %q", name))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Wrap(errors.NewAggregate(errs), "invalid extraMounts")
+	}
+	return nil
+}
+
+// checkHostPath verifies that m.HostPath exists, or creates it if
+// m.CreateHostPath is set
+func checkHostPath(logger log.Logger, m config.Mount) error {
+	// relative paths are resolved the same way by the providers
+	hostPath, err := filepath.Abs(m.HostPath)
+	if err != nil {
+		return errors.Wrapf(err, "unable to resolve absolute path for hostPath %q", m.HostPath)
+	}
+	_, err = os.Stat(hostPath)
+	switch {
+	case err == nil:
+		return nil
+	case !os.IsNotExist(err):
+		return errors.Wrapf(err, "unable to check hostPath %q", m.HostPath)
+	case !m.CreateHostPath:
+		return errors.Errorf("hostPath %q for containerPath %q does not exist, create it or set createHostPath: true", m.HostPath, m.ContainerPath)
+	}
+	if err := os.MkdirAll(hostPath, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create hostPath %q", m.HostPath)
+	}
+	logger.V(1).Infof("Created hostPath %q for containerPath %q", hostPath, m.ContainerPath)
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+func newMountsCluster(mounts ...config.Mount) *config.Cluster {
+	cfg := &config.Cluster{Name: "kind"}
+	config.SetDefaultsCluster(cfg)
+	cfg.Nodes[0].ExtraMounts = mounts
+	return cfg
+}
+
+func TestCheckHostPathsExists(t *testing.T) {
+	t.Parallel()
+	cfg := newMountsCluster(config.Mount{HostPath: t.TempDir(), ContainerPath: "/data"})
+	assert.ExpectError(t, false, checkHostPaths(log.NoopLogger{}, cfg))
+}
+
+func TestCheckHostPathsMissing(t *testing.T) {
+	t.Parallel()
+	hostPath := filepath.Join(t.TempDir(), "missing")
+	cfg := newMountsCluster(config.Mount{HostPath: hostPath, ContainerPath: "/data"})
+	assert.ExpectError(t, true, checkHostPaths(log.NoopLogger{}, cfg))
+	if _, err := os.Stat(hostPath); !os.IsNotExist(err) {
+		t.Fatalf("expected hostPath not to be created, got: %v", err)
+	}
+}
+
+func TestCheckHostPathsCreate(t *testing.T) {
+	t.Parallel()
+	hostPath := filepath.Join(t.TempDir(), "created", "data")
+	cfg := newMountsCluster(config.Mount{HostPath: hostPath, ContainerPath: "/data", CreateHostPath: true})
+	assert.ExpectError(t, false, checkHostPaths(log.NoopLogger{}, cfg))
+	info, err := os.Stat(hostPath)
+	if err != nil {
+		t.Fatalf("expected hostPath to be created: %v", err)
+	}
+	if !info.IsDir() {
+		t.Fatal("expected hostPath to be a directory")
+	}
+}
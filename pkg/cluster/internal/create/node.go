@@ -0,0 +1,251 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/sets"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	configaction "sigs.k8s.io/kind/pkg/cluster/internal/create/actions/config"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/configurelogging"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installstaticpods"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installsystemdunits"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/keepportmap"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/kubeadmjoin"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/recordconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// joinTokenTTL is how long the bootstrap token recreated for adding a node
+// is valid, the token the cluster was created with expires after a day
+const joinTokenTTL = time.Hour
+
+// NodeOptions holds options for adding a node to an existing cluster
+type NodeOptions struct {
+	// Name is the name of the new node, if empty the next free
+	// <cluster>-worker<n> name is used
+	Name string
+	// Image overrides the image of the new node if non-zero
+	Image string
+	// Labels are added to the labels of the new node
+	Labels map[string]string
+	// Retain keeps the node if adding it fails
+	Retain bool
+}
+
+// Node adds a worker node to the existing cluster name and joins it to
+// Kubernetes. The node is configured like the first worker the cluster was
+// created with, or like its bootstrap control plane if it has no workers.
+func Node(ctx context.Context, logger log.Logger, p providers.Provider, name string, opts *NodeOptions) (string, error) {
+	allNodes, err := p.ListNodes(name)
+	if err != nil {
+		return "", errors.Wrap(err, "error listing nodes")
+	}
+	if len(allNodes) == 0 {
+		return "", errors.Errorf("unknown cluster %q", name)
+	}
+	cfg, err := recordconfig.Read(allNodes)
+	if err != nil {
+		return "", err
+	}
+	nodeCfg, err := nodeConfig(cfg, allNodes, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// provision only the new node, the cluster's CA is already in place
+	addCfg := cfg.DeepCopy()
+	addCfg.Nodes = []config.Node{*nodeCfg}
+	addCfg.Certificates.CACertFile = ""
+	addCfg.Certificates.CAKeyFile = ""
+	if addCfg.Networking.HostRoutes {
+		logger.Warnf("networking.hostRoutes: no host route is added for the pod CIDR of node %q", nodeCfg.Name)
+	}
+
+	status := cli.StatusForLogger(logger)
+	logger.V(0).Infof("Adding node %q to cluster %q ...\n", nodeCfg.Name, name)
+	if err := p.Provision(ctx, status, addCfg); err != nil {
+		cleanupFailedNode(logger, p, name, nodeCfg.Name, opts.Retain)
+		return "", err
+	}
+	if err := joinNode(ctx, logger, status, p, addCfg, allNodes); err != nil {
+		cleanupFailedNode(logger, p, name, nodeCfg.Name, opts.Retain)
+		return "", err
+	}
+	return nodeCfg.Name, nil
+}
+
+// nodeConfig returns the config of the node to add to the cluster with cfg
+// and allNodes
+func nodeConfig(cfg *config.Cluster, allNodes []nodes.Node, opts *NodeOptions) (*config.Node, error) {
+	var template *config.Node
+	for i := range cfg.Nodes {
+		if cfg.Nodes[i].Role == config.WorkerRole {
+			template = &cfg.Nodes[i]
+			break
+		}
+	}
+	if template == nil {
+		template = &cfg.Nodes[0]
+	}
+	node := template.DeepCopy()
+	node.Role = config.WorkerRole
+	// host ports can only be mapped to a single node
+	node.ExtraPortMappings = nil
+	if opts.Image != "" {
+		node.Image = opts.Image
+	}
+	if len(opts.Labels) > 0 {
+		labels := make(map[string]string, len(node.Labels)+len(opts.Labels))
+		for k, v := range node.Labels {
+			labels[k] = v
+		}
+		for k, v := range opts.Labels {
+			labels[k] = v
+		}
+		node.Labels = labels
+	}
+
+	existing := sets.NewString()
+	for _, n := range allNodes {
+		existing.Insert(n.String())
+	}
+	node.Name = opts.Name
+	if node.Name == "" {
+		node.Name = nextNodeName(cfg.Name, existing)
+	} else if existing.Has(node.Name) {
+		return nil, errors.Errorf("node %q already exists", node.Name)
+	}
+	return node, nil
+}
+
+// nextNodeName returns the first worker name for the cluster name that is
+// not in existing, following the provider's node naming
+func nextNodeName(name string, existing sets.String) string {
+	namer := common.MakeNodeNamer(name)
+	for {
+		nodeName := namer(constants.WorkerNodeRoleValue)
+		if !existing.Has(nodeName) {
+			return nodeName
+		}
+	}
+}
+
+// joinNode writes the new node's configuration and joins it to the cluster
+// with allNodes
+func joinNode(ctx context.Context, logger log.Logger, status *cli.Status, p providers.Provider, addCfg *config.Cluster, allNodes []nodes.Node) error {
+	newNode, err := findNode(p, addCfg.Name, addCfg.Nodes[0].Name)
+	if err != nil {
+		return err
+	}
+	if newNode == nil {
+		return errors.Errorf("node %q was not created", addCfg.Nodes[0].Name)
+	}
+	controlPlane, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return err
+	}
+	if err := refreshJoinToken(controlPlane); err != nil {
+		return err
+	}
+
+	node := addCfg.Nodes[0]
+	actionsToRun := []actions.Action{
+		configaction.NewAction(),
+	}
+	if len(node.SystemdUnits) > 0 || len(node.Env) > 0 {
+		actionsToRun = append(actionsToRun, installsystemdunits.NewAction())
+	}
+	if addCfg.Networking.KeepPortmap {
+		actionsToRun = append(actionsToRun, keepportmap.NewAction())
+	}
+	if len(node.StaticPods) > 0 {
+		actionsToRun = append(actionsToRun, installstaticpods.NewAction())
+	}
+	if node.Logging != (config.NodeLogging{}) {
+		actionsToRun = append(actionsToRun, configurelogging.NewAction())
+	}
+	actionsToRun = append(actionsToRun, kubeadmjoin.NewAction())
+
+	actionsContext := actions.NewNodesActionContext(ctx, logger, status, p, addCfg, []nodes.Node{newNode})
+	for _, action := range actionsToRun {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := action.Execute(actionsContext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshJoinToken recreates the bootstrap token nodes join with, which
+// has expired if the cluster is older than a day
+func refreshJoinToken(controlPlane nodes.Node) error {
+	// the token may have been removed already once expired
+	_ = controlPlane.Command("kubeadm", "token", "delete", kubeadm.Token).Run()
+	if err := controlPlane.Command(
+		"kubeadm", "token", "create", kubeadm.Token,
+		"--ttl", joinTokenTTL.String(),
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to create bootstrap token")
+	}
+	return nil
+}
+
+// findNode returns the node nodeName of the cluster name, or nil if it does
+// not exist
+func findNode(p providers.Provider, name, nodeName string) (nodes.Node, error) {
+	allNodes, err := p.ListNodes(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing nodes")
+	}
+	for _, n := range allNodes {
+		if n.String() == nodeName {
+			return n, nil
+		}
+	}
+	return nil, nil
+}
+
+// cleanupFailedNode deletes the node that failed to be added unless retain
+// is set
+func cleanupFailedNode(logger log.Logger, p providers.Provider, name, nodeName string, retain bool) {
+	if retain {
+		logger.V(0).Infof("Retaining node %q for debugging, delete it with `kind delete node --name %s %s`", nodeName, name, nodeName)
+		return
+	}
+	node, err := findNode(p, name, nodeName)
+	if err != nil || node == nil {
+		return
+	}
+	if err := p.DeleteNodes([]nodes.Node{node}); err != nil {
+		logger.Errorf("failed to delete node %q: %v", nodeName, err)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+	"sigs.k8s.io/kind/pkg/internal/sets"
+)
+
+func TestNextNodeName(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Existing []string
+		Expected string
+	}{
+		{
+			Name:     "no workers",
+			Existing: []string{"kind-control-plane"},
+			Expected: "kind-worker",
+		},
+		{
+			Name:     "after the last worker",
+			Existing: []string{"kind-control-plane", "kind-worker", "kind-worker2"},
+			Expected: "kind-worker3",
+		},
+		{
+			Name:     "first free name",
+			Existing: []string{"kind-control-plane", "kind-worker", "kind-worker3"},
+			Expected: "kind-worker2",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.StringEqual(t, tc.Expected, nextNodeName("kind", sets.NewString(tc.Existing...)))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/internal/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// PlannedNode is a node container that creating a cluster would create
+type PlannedNode struct {
+	Name  string
+	Role  string
+	Image string
+	// PortMappings are the ports the node would publish on the host besides
+	// the API server, a zero HostPort is picked when the node is created
+	PortMappings []config.PortMapping
+}
+
+// Plan returns the defaulted and validated config of creating a cluster
+// with opts, and the node containers that would be created, in the order
+// the providers name them. Nothing is created.
+func Plan(logger log.Logger, p providers.Provider, opts *ClusterOptions) ([]PlannedNode, error) {
+	// default / process options (namely config)
+	if err := fixupOptions(opts); err != nil {
+		return nil, err
+	}
+	if err := alreadyExists(p, opts.Config.Name); err != nil {
+		return nil, err
+	}
+	if err := opts.Config.Validate(); err != nil {
+		return nil, err
+	}
+	// this may remap ports in the config, like creating would
+	if err := checkHostPorts(logger, opts.Config, opts.AutoRemapPorts, p.IsLocal()); err != nil {
+		return nil, err
+	}
+
+	return plannedNodes(opts.Config), nil
+}
+
+// plannedNodes returns the node containers creating a cluster with cfg
+// would create, the load balancer is last
+func plannedNodes(cfg *config.Cluster) []PlannedNode {
+	// name nodes the same way the providers do
+	nodeNamer := common.MakeNodeNamer(cfg.Name)
+	planned := []PlannedNode{}
+	for i := range cfg.Nodes {
+		node := &cfg.Nodes[i]
+		planned = append(planned, PlannedNode{
+			Name:         common.NodeName(nodeNamer, node),
+			Role:         string(node.Role),
+			Image:        node.Image,
+			PortMappings: node.ExtraPortMappings,
+		})
+	}
+	if config.ClusterHasImplicitLoadBalancer(cfg) {
+		planned = append(planned, PlannedNode{
+			Name:  nodeNamer(constants.ExternalLoadBalancerNodeRoleValue),
+			Role:  constants.ExternalLoadBalancerNodeRoleValue,
+			Image: loadbalancer.Image,
+		})
+	}
+	return planned
+}
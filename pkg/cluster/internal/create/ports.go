@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// maxRemapAttempts bounds the attempts to find a free port when remapping
+const maxRemapAttempts = 10
+
+// hostPortRequest is a fixed host port requested by the cluster config
+type hostPortRequest struct {
+	// owner describes what requested the port, for messages
+	owner    string
+	protocol config.PortMappingProtocol
+	// addresses the port is published on, these all share the port
+	addresses []string
+	// port points at the config field holding the port so it can be remapped
+	port *int32
+}
+
+// checkHostPorts verifies that the fixed host ports requested by cfg are
+// not in use on the host and not requested twice. If remap is true,
+// conflicting ports are replaced in cfg with free ports instead.
+// local is false for a remote container runtime, whose host ports cannot be
+// probed from here, then only ports requested twice are conflicts.
+func checkHostPorts(logger log.Logger, cfg *config.Cluster, remap, local bool) error {
+	claimed := []claimedPort{}
+	errs := []error{}
+	for _, r := range hostPortRequests(cfg) {
+		conflict := r.conflict(claimed, *r.port, local)
+		if conflict == "" {
+			claimed = r.claim(claimed, *r.port)
+			continue
+		}
+		if !remap {
+			errs = append(errs, errors.Errorf("host port %d/%s on %s for %s %s",
+				*r.port, r.protocol, strings.Join(r.addresses, ","), r.owner, conflict))
+			continue
+		}
+		port, err := r.freePort(claimed, local)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		logger.V(0).Infof("Remapped host port %d/%s for %s to %d", *r.port, r.protocol, r.owner, port)
+		*r.port = port
+		claimed = r.claim(claimed, port)
+	}
+	if len(errs) > 0 {
+		return errors.Wrap(errors.NewAggregate(errs), "host port conflicts, use --auto-remap-ports to pick free ports instead")
+	}
+	return nil
+}
+
+// hostPortRequests returns the fixed host ports requested by cfg, random
+// ports (0 and -1) are picked later and cannot conflict
+func hostPortRequests(cfg *config.Cluster) []hostPortRequest {
+	defaultAddress := "0.0.0.0"
+	if cfg.Networking.IPFamily == config.IPv6Family {
+		defaultAddress = "::"
+	}
+	requests := []hostPortRequest{}
+	add := func(r hostPortRequest) {
+		if *r.port != 0 && *r.port != -1 {
+			requests = append(requests, r)
+		}
+	}
+
+	add(hostPortRequest{
+		owner:     "the API server",
+		protocol:  config.PortMappingProtocolTCP,
+		addresses: append([]string{cfg.Networking.APIServerAddress}, cfg.Networking.APIServerAdditionalAddresses...),
+		port:      &cfg.Networking.APIServerPort,
+	})
+
+	// name nodes the same way the providers do
+	nodeNamer := common.MakeNodeNamer(cfg.Name)
+	for i := range cfg.Nodes {
+		node := &cfg.Nodes[i]
+		name := common.NodeName(nodeNamer, node)
+		for j := range node.ExtraPortMappings {
+			mapping := &node.ExtraPortMappings[j]
+			address := mapping.ListenAddress
+			if address == "" {
+				address = defaultAddress
+			}
+			protocol := mapping.Protocol
+			if protocol == "" {
+				protocol = config.PortMappingProtocolTCP
+			}
+			add(hostPortRequest{
+				owner:     fmt.Sprintf("node %q", name),
+				protocol:  protocol,
+				addresses: []string{address},
+				port:      &mapping.HostPort,
+			})
+		}
+	}
+	return requests
+}
+
+// claimedPort is a host port already requested by another hostPortRequest
+type claimedPort struct {
+	owner    string
+	protocol config.PortMappingProtocol
+	address  net.IP
+	port     int32
+}
+
+// claim returns claimed with r's addresses on port added
+func (r *hostPortRequest) claim(claimed []claimedPort, port int32) []claimedPort {
+	for _, address := range r.addresses {
+		claimed = append(claimed, claimedPort{
+			owner:    r.owner,
+			protocol: r.protocol,
+			address:  net.ParseIP(address),
+			port:     port,
+		})
+	}
+	return claimed
+}
+
+// conflict describes why r cannot use port, or returns "" if it can.
+// Whether port is in use on the host is only probed if local is true
+func (r *hostPortRequest) conflict(claimed []claimedPort, port int32, local bool) string {
+	for _, address := range r.addresses {
+		ip := net.ParseIP(address)
+		for _, c := range claimed {
+			if c.port == port && c.protocol == r.protocol &&
+				(c.address.Equal(ip) || c.address.IsUnspecified() || ip.IsUnspecified()) {
+				return "is also requested by " + c.owner
+			}
+		}
+		if local && !hostPortAvailable(r.protocol, address, port) {
+			return "is already in use"
+		}
+	}
+	return ""
+}
+
+// freePort finds a port that r can use instead of its requested port
+func (r *hostPortRequest) freePort(claimed []claimedPort, local bool) (int32, error) {
+	for i := 0; i < maxRemapAttempts; i++ {
+		port, release, err := common.GetFreePort(r.addresses[0])
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get a free host port for %s", r.owner)
+		}
+		release()
+		if r.conflict(claimed, port, local) == "" {
+			return port, nil
+		}
+	}
+	return 0, errors.Errorf("failed to get a free host port for %s", r.owner)
+}
+
+// hostPortAvailable returns false if port is known to be in use on address.
+// Other errors binding the port are not conflicts: an unprivileged user may
+// not bind privileged ports that the container runtime can, and address may
+// not be local to this host, e.g. with a remote container runtime
+func hostPortAvailable(protocol config.PortMappingProtocol, address string, port int32) bool {
+	hostPort := net.JoinHostPort(address, fmt.Sprint(port))
+	switch protocol {
+	case config.PortMappingProtocolTCP:
+		l, err := net.Listen("tcp", hostPort)
+		if err != nil {
+			return !stderrors.Is(err, syscall.EADDRINUSE)
+		}
+		l.Close()
+	case config.PortMappingProtocolUDP:
+		l, err := net.ListenPacket("udp", hostPort)
+		if err != nil {
+			return !stderrors.Is(err, syscall.EADDRINUSE)
+		}
+		l.Close()
+	}
+	// SCTP cannot be checked from go without extra dependencies
+	return true
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"net"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// listenTCP returns a listening port on 127.0.0.1 and a cleanup function
+func listenTCP(t *testing.T) (int32, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	return int32(l.Addr().(*net.TCPAddr).Port), func() { l.Close() }
+}
+
+func newPortsCluster(mappings ...[]config.PortMapping) *config.Cluster {
+	cfg := &config.Cluster{Name: "kind"}
+	config.SetDefaultsCluster(cfg)
+	cfg.Nodes = nil
+	for _, m := range mappings {
+		cfg.Nodes = append(cfg.Nodes, config.Node{
+			Role:              config.ControlPlaneRole,
+			ExtraPortMappings: m,
+		})
+	}
+	return cfg
+}
+
+func TestCheckHostPortsInUse(t *testing.T) {
+	t.Parallel()
+	port, cleanup := listenTCP(t)
+	defer cleanup()
+
+	cfg := newPortsCluster([]config.PortMapping{
+		{ListenAddress: "127.0.0.1", HostPort: port, ContainerPort: 80},
+	})
+	assert.ExpectError(t, true, checkHostPorts(log.NoopLogger{}, cfg, false, true))
+	assert.DeepEqual(t, port, cfg.Nodes[0].ExtraPortMappings[0].HostPort)
+}
+
+func TestCheckHostPortsRemap(t *testing.T) {
+	t.Parallel()
+	port, cleanup := listenTCP(t)
+	defer cleanup()
+
+	cfg := newPortsCluster([]config.PortMapping{
+		{ListenAddress: "127.0.0.1", HostPort: port, ContainerPort: 80},
+		{ListenAddress: "127.0.0.1", ContainerPort: 443},
+	})
+	assert.ExpectError(t, false, checkHostPorts(log.NoopLogger{}, cfg, true, true))
+	if remapped := cfg.Nodes[0].ExtraPortMappings[0].HostPort; remapped == port || remapped <= 0 {
+		t.Errorf("expected host port %d to be remapped, got %d", port, remapped)
+	}
+	// random ports are left to the provider
+	assert.DeepEqual(t, int32(0), cfg.Nodes[0].ExtraPortMappings[1].HostPort)
+}
+
+func TestCheckHostPortsNotLocal(t *testing.T) {
+	t.Parallel()
+	// 192.0.2.0/24 is reserved for documentation, so it cannot be bound here
+	// but may still be valid for a remote container runtime
+	cfg := newPortsCluster([]config.PortMapping{
+		{ListenAddress: "192.0.2.1", HostPort: 8080, ContainerPort: 80},
+	})
+	assert.ExpectError(t, false, checkHostPorts(log.NoopLogger{}, cfg, false, true))
+	assert.DeepEqual(t, int32(8080), cfg.Nodes[0].ExtraPortMappings[0].HostPort)
+}
+
+func TestCheckHostPortsRemote(t *testing.T) {
+	t.Parallel()
+	port, cleanup := listenTCP(t)
+	defer cleanup()
+
+	// ports in use on this host do not conflict with a remote runtime's
+	cfg := newPortsCluster([]config.PortMapping{
+		{ListenAddress: "127.0.0.1", HostPort: port, ContainerPort: 80},
+	})
+	assert.ExpectError(t, false, checkHostPorts(log.NoopLogger{}, cfg, false, false))
+	assert.DeepEqual(t, port, cfg.Nodes[0].ExtraPortMappings[0].HostPort)
+
+	// but requesting the same port twice still does
+	cfg = newPortsCluster(
+		[]config.PortMapping{{ListenAddress: "127.0.0.1", HostPort: port, ContainerPort: 80}},
+		[]config.PortMapping{{HostPort: port, ContainerPort: 80}},
+	)
+	assert.ExpectError(t, true, checkHostPorts(log.NoopLogger{}, cfg, false, false))
+}
+
+func TestCheckHostPortsDuplicateAcrossNodes(t *testing.T) {
+	t.Parallel()
+	// find a free port, then release it so both nodes can request it
+	port, cleanup := listenTCP(t)
+	cleanup()
+
+	cfg := newPortsCluster(
+		[]config.PortMapping{{ListenAddress: "127.0.0.1", HostPort: port, ContainerPort: 80}},
+		[]config.PortMapping{{HostPort: port, ContainerPort: 80}},
+	)
+	assert.ExpectError(t, true, checkHostPorts(log.NoopLogger{}, cfg, false, true))
+
+	assert.ExpectError(t, false, checkHostPorts(log.NoopLogger{}, cfg, true, true))
+	if cfg.Nodes[0].ExtraPortMappings[0].HostPort == cfg.Nodes[1].ExtraPortMappings[0].HostPort {
+		t.Errorf("expected duplicate host port %d to be remapped", port)
+	}
+}
+
+func TestHostPortRequestsNodeNames(t *testing.T) {
+	t.Parallel()
+	cfg := newPortsCluster(
+		[]config.PortMapping{{HostPort: 8080, ContainerPort: 80}},
+		[]config.PortMapping{{HostPort: 8081, ContainerPort: 80}},
+	)
+	// the API server port is random, and nodes added to an existing cluster
+	// are named by their config
+	cfg.Nodes[1].Name = "kind-extra"
+	owners := []string{}
+	for _, r := range hostPortRequests(cfg) {
+		owners = append(owners, r.owner)
+	}
+	assert.DeepEqual(t, []string{`node "kind-control-plane"`, `node "kind-extra"`}, owners)
+}
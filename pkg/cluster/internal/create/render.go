@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	configaction "sigs.k8s.io/kind/pkg/cluster/internal/create/actions/config"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// node addresses are only known once the node containers exist, the
+// rendered kubeadm configs use these instead
+const (
+	nodeIPv4Placeholder = "<node-ipv4>"
+	nodeIPv6Placeholder = "<node-ipv6>"
+)
+
+// imageVersionRE matches the Kubernetes version in a node image's tag,
+// e.g. kindest/node:v1.32.0@sha256:...
+var imageVersionRE = regexp.MustCompile(`:(v\d+\.\d+\.\d+[^@/:]*)(@|$)`)
+
+// RenderedNode is what creating a cluster would generate for one of its
+// node containers
+type RenderedNode struct {
+	PlannedNode
+	// RunArgs are the container run arguments of the node
+	RunArgs []string
+	// KubeadmConfig is the node's kubeadm config, this is empty for nodes
+	// that are not Kubernetes nodes
+	KubeadmConfig string
+}
+
+// Render returns what creating a cluster with opts would generate for each
+// node container, after defaulting and validating opts.Config like creating
+// would. Nothing is created and the container runtime need not be running.
+// The kubeadm configs use placeholders for the node addresses, and
+// kubernetesVersion if set or else the version in each node image's tag.
+// They are rendered for a rootful runtime, as telling a rootless runtime
+// apart requires the runtime.
+func Render(p providers.Provider, opts *ClusterOptions, kubernetesVersion string) ([]RenderedNode, error) {
+	// default / process options (namely config)
+	if err := fixupOptions(opts); err != nil {
+		return nil, err
+	}
+	if err := opts.Config.Validate(); err != nil {
+		return nil, err
+	}
+	cfg := opts.Config
+
+	runArgs, err := p.RunArgs(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// the providers use the node names as hostnames, and the API server is
+	// reached through the load balancer if there is one
+	planned := plannedNodes(cfg)
+	endpointNode := ""
+	for _, n := range planned {
+		if n.Role == constants.ExternalLoadBalancerNodeRoleValue ||
+			(endpointNode == "" && n.Role == constants.ControlPlaneNodeRoleValue) {
+			endpointNode = n.Name
+		}
+	}
+	controlPlaneEndpoint := net.JoinHostPort(endpointNode, fmt.Sprintf("%d", common.APIServerInternalPort))
+	data := configaction.ClusterConfigData(cfg, fmt.Sprintf("%s", p), controlPlaneEndpoint, false)
+
+	rendered := make([]RenderedNode, 0, len(planned))
+	for i, n := range planned {
+		r := RenderedNode{
+			PlannedNode: n,
+			RunArgs:     runArgs[n.Name],
+		}
+		// the load balancer is planned after the config's nodes
+		if i < len(cfg.Nodes) {
+			configNode := &cfg.Nodes[i]
+			nodeData := data // copy config data
+			nodeData.NodeName = n.Name
+			nodeData.NodeAddress = placeholderNodeAddress(cfg)
+			nodeData.KubernetesVersion = kubernetesVersion
+			if nodeData.KubernetesVersion == "" {
+				nodeData.KubernetesVersion, err = imageKubernetesVersion(configNode.Image)
+				if err != nil {
+					return nil, err
+				}
+			}
+			// memory limits only default the reservations given the host's
+			// memory, which is read from the node
+			nodeData.KubeReserved = configNode.KubeReserved
+			nodeData.EvictionHard = configNode.EvictionHard
+			r.KubeadmConfig, err = configaction.KubeadmConfig(cfg, nodeData, configNode)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to generate kubeadm config for node %q", n.Name)
+			}
+		}
+		rendered = append(rendered, r)
+	}
+	return rendered, nil
+}
+
+// placeholderNodeAddress returns the node address placeholders for the
+// cluster's IP family, in the order the config action uses
+func placeholderNodeAddress(cfg *config.Cluster) string {
+	switch cfg.Networking.IPFamily {
+	case config.IPv6Family:
+		return nodeIPv6Placeholder
+	case config.DualStackFamily:
+		if config.ClusterPrimaryIPFamily(cfg) == config.IPv4Family {
+			return nodeIPv4Placeholder + "," + nodeIPv6Placeholder
+		}
+		return nodeIPv6Placeholder + "," + nodeIPv4Placeholder
+	}
+	return nodeIPv4Placeholder
+}
+
+// imageKubernetesVersion returns the Kubernetes version in image's tag
+func imageKubernetesVersion(image string) (string, error) {
+	match := imageVersionRE.FindStringSubmatch(image)
+	if match == nil {
+		return "", errors.Errorf("cannot tell the Kubernetes version of node image %q from its tag, set it explicitly", image)
+	}
+	return match[1], nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestImageKubernetesVersion(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		image       string
+		want        string
+		expectError bool
+	}{
+		{image: "kindest/node:v1.32.0", want: "v1.32.0"},
+		{image: "kindest/node:v1.32.0@sha256:c48c62eac5da28cdadcf560d1d8616cfa6783b58f0d94cf63ad1bf49600cb027", want: "v1.32.0"},
+		{image: "localhost:5000/node:v1.33.0-alpha.1", want: "v1.33.0-alpha.1"},
+		{image: "kindest/node:latest", expectError: true},
+		{image: "localhost:5000/node", expectError: true},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.image, func(t *testing.T) {
+			t.Parallel()
+			got, err := imageKubernetesVersion(tc.image)
+			assert.ExpectError(t, tc.expectError, err)
+			assert.StringEqual(t, tc.want, got)
+		})
+	}
+}
+
+func TestPlaceholderNodeAddress(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name          string
+		family        config.ClusterIPFamily
+		serviceSubnet string
+		want          string
+	}{
+		{name: "ipv4", family: config.IPv4Family, want: "<node-ipv4>"},
+		{name: "ipv6", family: config.IPv6Family, want: "<node-ipv6>"},
+		{name: "dual stack", family: config.DualStackFamily, serviceSubnet: "10.96.0.0/16,fd00:10:96::/112", want: "<node-ipv4>,<node-ipv6>"},
+		{name: "dual stack ipv6 first", family: config.DualStackFamily, serviceSubnet: "fd00:10:96::/112,10.96.0.0/16", want: "<node-ipv6>,<node-ipv4>"},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &config.Cluster{}
+			cfg.Networking.IPFamily = tc.family
+			cfg.Networking.ServiceSubnet = tc.serviceSubnet
+			assert.StringEqual(t, tc.want, placeholderNodeAddress(cfg))
+		})
+	}
+}
@@ -18,34 +18,78 @@ package delete
 
 import (
 	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/tracing"
 	"sigs.k8s.io/kind/pkg/log"
 
+	"sigs.k8s.io/kind/pkg/cluster/internal/hostroutes"
 	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/protection"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
 )
 
+// CheckProtection returns an error if the cluster name made up of allNodes
+// is protected from deletion, or if this cannot be determined
+func CheckProtection(name string, allNodes []nodes.Node) error {
+	protected, err := protection.IsProtected(allNodes)
+	if err != nil {
+		return errors.Wrapf(err, "cannot tell if cluster %q is protected from deletion, delete it with --force", name)
+	}
+	if protected {
+		return errors.Errorf("cluster %q is protected from deletion, unprotect it with \"kind unprotect cluster --name %s\" or delete it with --force", name, name)
+	}
+	return nil
+}
+
 // Cluster deletes the cluster identified by ctx
 // explicitKubeconfigPath is --kubeconfig, following the rules from
 // https://kubernetes.io/docs/reference/generated/kubectl/kubectl-commands
-func Cluster(logger log.Logger, p providers.Provider, name, explicitKubeconfigPath string) error {
+// and contextTemplate names the cluster's kubeconfig entries.
+// Protected clusters are only deleted if force is true
+func Cluster(logger log.Logger, p providers.Provider, name, contextTemplate, explicitKubeconfigPath string, force bool) error {
 	n, err := p.ListNodes(name)
 	if err != nil {
 		return errors.Wrap(err, "error listing nodes")
 	}
 
-	kerr := kubeconfig.Remove(name, explicitKubeconfigPath)
+	if !force {
+		if err := CheckProtection(name, n); err != nil {
+			return err
+		}
+	}
+
+	span := tracing.Start("remove kubeconfig")
+	kerr := kubeconfig.Remove(name, contextTemplate, explicitKubeconfigPath)
+	span.End(kerr)
 	if kerr != nil {
 		logger.Errorf("failed to update kubeconfig: %v", kerr)
 	}
 
 	if len(n) > 0 {
+		// host routes via the nodes must be removed while the nodes exist
+		span = tracing.Start("remove host routes")
+		err = hostroutes.Remove(p, n)
+		span.End(err)
+		if err != nil {
+			logger.Errorf("failed to remove host routes: %v", err)
+		}
+		span = tracing.Start("delete nodes")
 		err = p.DeleteNodes(n)
+		span.End(err)
 		if err != nil {
 			return err
 		}
 		logger.V(0).Infof("Deleted nodes: %q", n)
 	}
 
+	// networks may be left over from a failed create even without nodes
+	span = tracing.Start("delete cluster networks")
+	err = p.DeleteClusterNetworks(name)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+
 	if kerr != nil {
 		return kerr
 	}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delete
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/hostroutes"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+// NodeOptions configures deleting a node from a cluster
+type NodeOptions struct {
+	// Drain cordons the node and evicts its pods before deleting it,
+	// respecting PodDisruptionBudgets
+	Drain bool
+	// GracePeriod overrides the pods' termination grace period when
+	// draining, if non-negative
+	GracePeriod time.Duration
+	// DrainTimeout is how long to wait for the drain, zero waits forever
+	DrainTimeout time.Duration
+}
+
+// Node deletes the worker or hollow node nodeName from the cluster name, removing it
+// from Kubernetes and then deleting its container
+func Node(logger log.Logger, p providers.Provider, name, nodeName string, opts NodeOptions) error {
+	allNodes, err := p.ListNodes(name)
+	if err != nil {
+		return errors.Wrap(err, "error listing nodes")
+	}
+	var node nodes.Node
+	for _, n := range allNodes {
+		if n.String() == nodeName {
+			node = n
+			break
+		}
+	}
+	if node == nil {
+		return errors.Errorf("no node %q found for cluster %q", nodeName, name)
+	}
+	// deleting control plane nodes would break etcd quorum and the API
+	// server endpoint, so only workers and hollow nodes may be removed
+	role, err := node.Role()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get role of node %q", nodeName)
+	}
+	if role != constants.WorkerNodeRoleValue && role != constants.HollowNodeRoleValue {
+		return errors.Errorf("only worker and hollow nodes can be deleted, %q is a %s node", nodeName, role)
+	}
+	controlPlane, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return err
+	}
+
+	if opts.Drain {
+		logger.V(0).Infof("Draining node %q ...", nodeName)
+		if err := controlPlane.Command("kubectl", drainArgs(nodeName, opts)...).Run(); err != nil {
+			return errors.Wrapf(err, "failed to drain node %q", nodeName)
+		}
+	}
+
+	// remove the node from Kubernetes so it is not left NotReady, this is
+	// best effort as the container should be deleted even if the control
+	// plane is unhealthy
+	if err := controlPlane.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"delete", "node", "--ignore-not-found", nodeName,
+	).Run(); err != nil {
+		logger.Warnf("Failed to remove node %q from Kubernetes: %v", nodeName, err)
+	}
+
+	// host routes via the node must be removed while it exists
+	if err := hostroutes.Remove(p, []nodes.Node{node}); err != nil {
+		logger.Errorf("failed to remove host routes: %v", err)
+	}
+	if err := p.DeleteNodes([]nodes.Node{node}); err != nil {
+		return err
+	}
+	logger.V(0).Infof("Deleted node: %q", nodeName)
+	return nil
+}
+
+// drainArgs returns the kubectl arguments to cordon and drain nodeName
+func drainArgs(nodeName string, opts NodeOptions) []string {
+	args := []string{
+		"--kubeconfig=/etc/kubernetes/admin.conf", "drain", nodeName,
+		// daemonset pods would be recreated on the node immediately
+		"--ignore-daemonsets",
+		// emptyDir data is lost with the node regardless
+		"--delete-emptydir-data",
+	}
+	if opts.GracePeriod >= 0 {
+		args = append(args, fmt.Sprintf("--grace-period=%d", int64(opts.GracePeriod/time.Second)))
+	}
+	if opts.DrainTimeout > 0 {
+		args = append(args, "--timeout="+opts.DrainTimeout.String())
+	}
+	return args
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delete
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func Test_drainArgs(t *testing.T) {
+	t.Parallel()
+	common := []string{
+		"--kubeconfig=/etc/kubernetes/admin.conf", "drain", "kind-worker",
+		"--ignore-daemonsets", "--delete-emptydir-data",
+	}
+	assert.DeepEqual(t, common, drainArgs("kind-worker", NodeOptions{Drain: true, GracePeriod: -1}))
+	assert.DeepEqual(t,
+		append(common, "--grace-period=30", "--timeout=5m0s"),
+		drainArgs("kind-worker", NodeOptions{Drain: true, GracePeriod: 30 * time.Second, DrainTimeout: 5 * time.Minute}),
+	)
+	// a zero grace period is not the pods' default
+	assert.DeepEqual(t,
+		append(common, "--grace-period=0"),
+		drainArgs("kind-worker", NodeOptions{Drain: true}),
+	)
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package etcd implements saving and restoring etcd snapshots of the
+// stacked etcd kubeadm runs on each control plane node
+package etcd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+const (
+	manifestsDir = "/etc/kubernetes/manifests"
+	// stoppedManifestsDir holds the static pod manifests while restoring
+	stoppedManifestsDir = "/etc/kubernetes/kind-restore-manifests"
+	// dataDir is the etcd data dir, it is mounted into the etcd container
+	// at the same path so files written here are visible to etcdctl
+	dataDir = "/var/lib/etcd"
+	// snapshotPath and restoreDir are temporary paths under dataDir
+	snapshotPath = dataDir + "/kind-snapshot.db"
+	restoreDir   = dataDir + "/kind-restore"
+	// timeout is how long to wait for the control plane to stop or start
+	timeout = 2 * time.Minute
+)
+
+// etcdctl flags to talk to the local etcd member, as kubeadm configures it
+var etcdctlClientArgs = []string{
+	"--endpoints=https://127.0.0.1:2379",
+	"--cacert=/etc/kubernetes/pki/etcd/ca.crt",
+	"--cert=/etc/kubernetes/pki/etcd/healthcheck-client.crt",
+	"--key=/etc/kubernetes/pki/etcd/healthcheck-client.key",
+}
+
+// Save writes a snapshot of the cluster's etcd made up of allNodes to w
+func Save(allNodes []nodes.Node, w io.Writer) error {
+	node, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return err
+	}
+	id, err := etcdContainer(node)
+	if err != nil {
+		return err
+	}
+	args := append([]string{"exec", id, "etcdctl"}, etcdctlClientArgs...)
+	args = append(args, "snapshot", "save", snapshotPath)
+	if err := node.Command("crictl", args...).Run(); err != nil {
+		return errors.Wrap(err, "failed to save etcd snapshot")
+	}
+	defer func() { _ = node.Command("rm", "-f", snapshotPath).Run() }()
+	if err := node.Command("cat", snapshotPath).SetStdout(w).Run(); err != nil {
+		return errors.Wrap(err, "failed to copy etcd snapshot from node")
+	}
+	return nil
+}
+
+// member is an etcd member on a control plane node
+type member struct {
+	node    nodes.Node
+	name    string
+	peerURL string
+}
+
+// Restore replaces the etcd data of the cluster made up of allNodes with
+// snapshot. The API servers and etcd are stopped on every control plane node
+// while the data is swapped, and the previous data is discarded.
+func Restore(logger log.Logger, allNodes []nodes.Node, snapshot []byte) error {
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	if len(controlPlanes) == 0 {
+		return errors.New("no control plane nodes found")
+	}
+
+	// every member must be restored with the full initial cluster
+	members := make([]member, 0, len(controlPlanes))
+	initialCluster := make([]string, 0, len(controlPlanes))
+	for _, node := range controlPlanes {
+		m, err := memberForNode(node)
+		if err != nil {
+			return err
+		}
+		members = append(members, m)
+		initialCluster = append(initialCluster, m.name+"="+m.peerURL)
+	}
+
+	// restore into a new data dir using etcdctl from the running etcd
+	// container, the control plane keeps running until the data is ready
+	logger.V(0).Info("Restoring etcd snapshot ...")
+	for _, m := range members {
+		if err := restoreMember(m, snapshot, strings.Join(initialCluster, ",")); err != nil {
+			return errors.Wrapf(err, "failed to restore etcd snapshot on node %q", m.node)
+		}
+	}
+
+	// stop etcd and the API server on every node before swapping the data
+	// so that no member starts with stale data
+	logger.V(0).Info("Stopping control plane ...")
+	for _, m := range members {
+		if err := stopStaticPods(m.node); err != nil {
+			return errors.Wrapf(err, "failed to stop control plane on node %q", m.node)
+		}
+	}
+	for _, m := range members {
+		if err := m.node.Command(
+			"bash", "-c",
+			fmt.Sprintf("rm -rf %[1]s/member && mv %[2]s/member %[1]s/member && rm -rf %[2]s %[3]s", dataDir, restoreDir, snapshotPath),
+		).Run(); err != nil {
+			return errors.Wrapf(err, "failed to replace etcd data on node %q", m.node)
+		}
+	}
+
+	logger.V(0).Info("Starting control plane ...")
+	for _, m := range members {
+		if err := m.node.Command(
+			"bash", "-c", fmt.Sprintf("mv %s/*.yaml %s/ && rmdir %s", stoppedManifestsDir, manifestsDir, stoppedManifestsDir),
+		).Run(); err != nil {
+			return errors.Wrapf(err, "failed to start control plane on node %q", m.node)
+		}
+	}
+	return waitForAPIServer(members[0].node)
+}
+
+// memberForNode reads the etcd member name and peer URL from the etcd
+// static pod manifest kubeadm wrote to node
+func memberForNode(node nodes.Node) (member, error) {
+	var manifest bytes.Buffer
+	if err := node.Command("cat", manifestsDir+"/etcd.yaml").SetStdout(&manifest).Run(); err != nil {
+		return member{}, errors.Wrapf(err, "failed to read etcd manifest from node %q", node)
+	}
+	name := manifestFlag(manifest.String(), "name")
+	peerURL := manifestFlag(manifest.String(), "initial-advertise-peer-urls")
+	if name == "" || peerURL == "" {
+		return member{}, errors.Errorf("failed to find etcd member for node %q", node)
+	}
+	return member{node: node, name: name, peerURL: peerURL}, nil
+}
+
+// manifestFlag returns the value of the --flag=value command line flag in
+// a static pod manifest, or "" if it is not set
+func manifestFlag(manifest, flag string) string {
+	match := regexp.MustCompile(`(?m)^\s*- --` + regexp.QuoteMeta(flag) + `=(\S+)\s*$`).FindStringSubmatch(manifest)
+	if len(match) < 2 {
+		return ""
+	}
+	return match[1]
+}
+
+func restoreMember(m member, snapshot []byte, initialCluster string) error {
+	if err := m.node.Command("cp", "/dev/stdin", snapshotPath).SetStdin(bytes.NewReader(snapshot)).Run(); err != nil {
+		return errors.Wrap(err, "failed to copy snapshot to node")
+	}
+	if err := m.node.Command("rm", "-rf", restoreDir).Run(); err != nil {
+		return err
+	}
+	id, err := etcdContainer(m.node)
+	if err != nil {
+		return err
+	}
+	restoreArgs := []string{
+		"snapshot", "restore", snapshotPath,
+		"--data-dir=" + restoreDir,
+		"--name=" + m.name,
+		"--initial-cluster=" + initialCluster,
+		"--initial-advertise-peer-urls=" + m.peerURL,
+	}
+	// etcdutl replaces etcdctl for offline operations in etcd v3.5+ and
+	// etcdctl snapshot restore was removed in v3.6
+	etcdutlErr := m.node.Command("crictl", append([]string{"exec", id, "etcdutl"}, restoreArgs...)...).Run()
+	if etcdutlErr == nil {
+		return nil
+	}
+	etcdctlErr := m.node.Command("crictl", append([]string{"exec", id, "etcdctl"}, restoreArgs...)...).Run()
+	if etcdctlErr == nil {
+		return nil
+	}
+	// report both, the etcdutl error is the relevant one on etcd v3.6+
+	return errors.Wrap(
+		errors.NewAggregate([]error{
+			errors.Wrap(etcdutlErr, "etcdutl"),
+			errors.Wrap(etcdctlErr, "etcdctl"),
+		}),
+		"failed to restore snapshot",
+	)
+}
+
+// stopStaticPods stops etcd and the API server by moving their static pod
+// manifests away and waiting for the kubelet to stop them
+func stopStaticPods(node nodes.Node) error {
+	if err := node.Command(
+		"bash", "-c",
+		fmt.Sprintf("mkdir -p %[2]s && mv %[1]s/etcd.yaml %[1]s/kube-apiserver.yaml %[2]s/", manifestsDir, stoppedManifestsDir),
+	).Run(); err != nil {
+		return err
+	}
+	until := time.Now().Add(timeout)
+	for until.After(time.Now()) {
+		lines, err := exec.OutputLines(node.Command(
+			"crictl", "ps", "--name", "^(etcd|kube-apiserver)$", "-q",
+		))
+		if err == nil && len(lines) == 0 {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return errors.New("timed out waiting for etcd and the API server to stop")
+}
+
+// etcdContainer returns the ID of the running etcd container on node
+func etcdContainer(node nodes.Node) (string, error) {
+	lines, err := exec.OutputLines(node.Command("crictl", "ps", "--name", "^etcd$", "-q"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find etcd container")
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("expected one running etcd container on node %q, found %d", node, len(lines))
+	}
+	return lines[0], nil
+}
+
+func waitForAPIServer(node nodes.Node) error {
+	until := time.Now().Add(timeout)
+	for until.After(time.Now()) {
+		lines, err := exec.OutputLines(node.Command(
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "--raw", "/readyz",
+		))
+		if err == nil && len(lines) > 0 && strings.TrimSpace(lines[0]) == "ok" {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return errors.New("timed out waiting for the API server to become ready")
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+)
+
+const etcdManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: etcd
+  namespace: kube-system
+spec:
+  containers:
+  - command:
+    - etcd
+    - --advertise-client-urls=https://172.18.0.2:2379
+    - --data-dir=/var/lib/etcd
+    - --initial-advertise-peer-urls=https://172.18.0.2:2380
+    - --initial-cluster=kind-control-plane=https://172.18.0.2:2380
+    - --name=kind-control-plane
+    image: registry.k8s.io/etcd:3.5.15-0
+`
+
+func TestManifestFlag(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Flag     string
+		Expected string
+	}{
+		{
+			Name:     "name",
+			Flag:     "name",
+			Expected: "kind-control-plane",
+		},
+		{
+			Name:     "peer urls",
+			Flag:     "initial-advertise-peer-urls",
+			Expected: "https://172.18.0.2:2380",
+		},
+		{
+			Name:     "prefix of another flag",
+			Flag:     "initial",
+			Expected: "",
+		},
+		{
+			Name:     "missing",
+			Flag:     "listen-metrics-urls",
+			Expected: "",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			if got := manifestFlag(etcdManifest, tc.Flag); got != tc.Expected {
+				t.Errorf("expected %q but got %q", tc.Expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hostroutes implements adding routes on the host to the pod CIDRs
+// of a cluster's nodes, and removing them again
+package hostroutes
+
+import (
+	"bytes"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+// markerPath is the file on each node recording the host routes via that
+// node, so that they can be removed when the cluster is deleted
+const markerPath = "/kind/host-routes"
+
+// Route is a host route to a node's pod CIDR via the node's IP
+type Route struct {
+	Node nodes.Node
+	CIDR string
+	Via  string
+}
+
+// String returns the route in `ip route` syntax
+func (r Route) String() string {
+	return r.CIDR + " via " + r.Via
+}
+
+// PodCIDRs returns the pod CIDRs allocated to each node by name, as read
+// from the API server on controlPlane. Nodes without pod CIDRs are omitted.
+func PodCIDRs(controlPlane nodes.Node) (map[string][]string, error) {
+	lines, err := exec.OutputLines(controlPlane.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "nodes",
+		`-o=jsonpath={range .items[*]}{.metadata.name}{range .spec.podCIDRs[*]}{" "}{@}{end}{"\n"}{end}`,
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node pod CIDRs")
+	}
+	return parsePodCIDRs(lines), nil
+}
+
+func parsePodCIDRs(lines []string) map[string][]string {
+	podCIDRs := map[string][]string{}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) > 1 {
+			podCIDRs[fields[0]] = fields[1:]
+		}
+	}
+	return podCIDRs
+}
+
+// ForNodes returns the host routes to the pod CIDRs of allNodes, via each
+// node's IP of the same family
+func ForNodes(allNodes []nodes.Node, podCIDRs map[string][]string) ([]Route, error) {
+	routes := []Route{}
+	for _, node := range allNodes {
+		cidrs, ok := podCIDRs[node.String()]
+		if !ok {
+			continue
+		}
+		ipv4, ipv6, err := node.IP()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get IP for node %q", node)
+		}
+		for _, cidr := range cidrs {
+			via := ipv4
+			if strings.Contains(cidr, ":") {
+				via = ipv6
+			}
+			if via == "" {
+				return nil, errors.Errorf("node %q has no IP in the family of its pod CIDR %s", node, cidr)
+			}
+			routes = append(routes, Route{Node: node, CIDR: cidr, Via: via})
+		}
+	}
+	return routes, nil
+}
+
+// Add adds routes on the host using p's privileged helper, and records
+// them on their nodes for Remove
+func Add(p providers.Provider, routes []Route) error {
+	if len(routes) == 0 {
+		return nil
+	}
+	if err := p.RunHostNetworkHelper(routes[0].Node, "sh", "-c", "set -e\n"+routeScript("replace", routes)); err != nil {
+		return errors.Wrap(err, "failed to add host routes")
+	}
+	// record the routes via each node on that node
+	byNode := map[nodes.Node][]string{}
+	order := []nodes.Node{}
+	for _, route := range routes {
+		if _, ok := byNode[route.Node]; !ok {
+			order = append(order, route.Node)
+		}
+		byNode[route.Node] = append(byNode[route.Node], route.String())
+	}
+	for _, node := range order {
+		content := strings.Join(byNode[node], "\n") + "\n"
+		if err := nodeutils.WriteFile(node, markerPath, content); err != nil {
+			return errors.Wrapf(err, "failed to record host routes on node %q", node)
+		}
+	}
+	return nil
+}
+
+// Remove removes the host routes recorded on allNodes by Add, if any.
+// Nodes that cannot be inspected, e.g. because they are stopped, are skipped.
+func Remove(p providers.Provider, allNodes []nodes.Node) error {
+	internalNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	routes := []Route{}
+	for _, node := range internalNodes {
+		var buff bytes.Buffer
+		if err := node.Command("cat", markerPath).SetStdout(&buff).Run(); err != nil {
+			continue
+		}
+		routes = append(routes, parseRoutes(node, buff.String())...)
+	}
+	if len(routes) == 0 {
+		return nil
+	}
+	// routes may already be gone, e.g. if the host was rebooted
+	script := routeScript("del", routes) + "true\n"
+	if err := p.RunHostNetworkHelper(routes[0].Node, "sh", "-c", script); err != nil {
+		return errors.Wrap(err, "failed to remove host routes")
+	}
+	return nil
+}
+
+func parseRoutes(node nodes.Node, content string) []Route {
+	routes := []Route{}
+	for _, line := range strings.Split(content, "\n") {
+		if fields := strings.Fields(line); len(fields) == 3 && fields[1] == "via" {
+			routes = append(routes, Route{Node: node, CIDR: fields[0], Via: fields[2]})
+		}
+	}
+	return routes
+}
+
+// routeScript returns a shell script running `ip route <op>` for each route
+func routeScript(op string, routes []Route) string {
+	var b strings.Builder
+	for _, route := range routes {
+		b.WriteString("ip route " + op + " " + route.String() + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostroutes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePodCIDRs(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"kind-control-plane 10.244.0.0/24 fd00:10:244::/64",
+		"kind-worker fd00:10:244:1::/64",
+		// not yet allocated
+		"kind-worker2",
+	}
+	expected := map[string][]string{
+		"kind-control-plane": {"10.244.0.0/24", "fd00:10:244::/64"},
+		"kind-worker":        {"fd00:10:244:1::/64"},
+	}
+	if got := parsePodCIDRs(lines); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v but got %v", expected, got)
+	}
+}
+
+func TestRoutesRoundTrip(t *testing.T) {
+	t.Parallel()
+	routes := []Route{
+		{CIDR: "10.244.1.0/24", Via: "172.18.0.3"},
+		{CIDR: "fd00:10:244:1::/64", Via: "fc00:f853:ccd:e793::3"},
+	}
+	script := routeScript("replace", routes)
+	expectedScript := "ip route replace 10.244.1.0/24 via 172.18.0.3\n" +
+		"ip route replace fd00:10:244:1::/64 via fc00:f853:ccd:e793::3\n"
+	if script != expectedScript {
+		t.Errorf("expected script %q but got %q", expectedScript, script)
+	}
+	// the marker file content written by Add should parse back to the routes
+	content := routes[0].String() + "\n" + routes[1].String() + "\n"
+	if got := parseRoutes(nil, content); !reflect.DeepEqual(got, routes) {
+		t.Errorf("expected %v but got %v", routes, got)
+	}
+}
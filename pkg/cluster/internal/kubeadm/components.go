@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// ComponentImage is a control plane component image to override
+type ComponentImage struct {
+	Target config.KubeadmPatchTarget
+	Image  string
+}
+
+// ComponentImages returns the component images cfg overrides
+func ComponentImages(cfg *config.Cluster) []ComponentImage {
+	images := []ComponentImage{}
+	for _, c := range []ComponentImage{
+		{config.KubeadmPatchTargetKubeAPIServer, cfg.ComponentImages.KubeAPIServer},
+		{config.KubeadmPatchTargetKubeControllerManager, cfg.ComponentImages.KubeControllerManager},
+		{config.KubeadmPatchTargetKubeScheduler, cfg.ComponentImages.KubeScheduler},
+	} {
+		if c.Image != "" {
+			images = append(images, c)
+		}
+	}
+	return images
+}
+
+// ComponentPatches returns the kubeadm component patches for cfg, its
+// KubeadmPatches followed by patches replacing the component images
+func ComponentPatches(cfg *config.Cluster) []config.KubeadmPatch {
+	patches := cfg.KubeadmPatches[:len(cfg.KubeadmPatches):len(cfg.KubeadmPatches)]
+	for _, c := range ComponentImages(cfg) {
+		// the static pod container is named after the component
+		patches = append(patches, config.KubeadmPatch{
+			Target:    c.Target,
+			PatchType: config.KubeadmPatchTypeStrategic,
+			Patch:     fmt.Sprintf("spec:\n  containers:\n  - name: %s\n    image: %q\n", c.Target, c.Image),
+		})
+	}
+	return patches
+}
@@ -19,6 +19,8 @@ package kubeadm
 import (
 	"bytes"
 	"fmt"
+	"net"
+	"path"
 	"sort"
 	"strings"
 
@@ -42,6 +44,10 @@ type ConfigData struct {
 	APIBindPort int
 	// The API server external listen IP (which we will port forward)
 	APIServerAddress string
+	// Additional API server external listen IPs (also port forwarded)
+	APIServerAdditionalAddresses []string
+	// Additional API server certificate SANs
+	APIServerCertSANs []string
 
 	// this should really be used for the --provider-id flag
 	// ideally cluster config should not depend on the node backend otherwise ...
@@ -63,6 +69,9 @@ type ConfigData struct {
 	PodSubnet string
 	// The subnet used for services
 	ServiceSubnet string
+	// The mask sizes of the pod CIDRs allocated to each node, if non-zero
+	NodeCIDRMaskSizeIPv4 int32
+	NodeCIDRMaskSizeIPv6 int32
 
 	// Kubernetes FeatureGates
 	FeatureGates map[string]bool
@@ -70,15 +79,60 @@ type ConfigData struct {
 	// Kubernetes API Server RuntimeConfig
 	RuntimeConfig map[string]string
 
+	// SwapBehavior is the kubelet memorySwap.swapBehavior, if set
+	SwapBehavior string
+
+	// KubeReserved and SystemReserved are the node's kubelet resource
+	// reservations, e.g. {"memory": "1Gi"}, if set
+	KubeReserved   map[string]string
+	SystemReserved map[string]string
+	// EvictionHard are the node's kubelet hard eviction thresholds, e.g.
+	// {"memory.available": "100Mi"}, if set. kind's thresholds disabling
+	// disk based eviction are kept unless overridden.
+	EvictionHard map[string]string
+
 	// IPFamily of the cluster, it can be IPv4, IPv6 or DualStack
 	IPFamily config.ClusterIPFamily
 
 	// Labels are the labels, in the format "key1=val1,key2=val2", with which the respective node will be labeled
 	NodeLabels string
 
+	// NodeTaints are the taints, in the format "key1=val1:NoSchedule,key2:NoExecute",
+	// the kubelet registers the respective node with, if set
+	NodeTaints string
+
 	// RootlessProvider is true if kind is running with rootless mode
 	RootlessProvider bool
 
+	// CRISocket is the container runtime endpoint on the node,
+	// defaults to ContainerdCRISocket
+	CRISocket string
+
+	// CertificateValidityPeriod and CACertificateValidityPeriod are the
+	// validity of the certificates kubeadm generates, if set.
+	// These require the v1beta4 ClusterConfiguration (Kubernetes v1.31+)
+	CertificateValidityPeriod   string
+	CACertificateValidityPeriod string
+
+	// PatchesDirectory is the directory on the node containing kubeadm
+	// component patches, if any
+	PatchesDirectory string
+
+	// EncryptionProvider is the encryption at rest provider of the
+	// EncryptionConfiguration at EncryptionConfigPath, if any
+	EncryptionProvider string
+	// EncryptionKMSEndpoint is the KMS v2 plugin endpoint for the kms
+	// EncryptionProvider
+	EncryptionKMSEndpoint string
+
+	// PodSecurity is true if the kube-apiserver reads PodSecurity admission
+	// defaults from the AdmissionConfiguration at AdmissionConfigPath
+	PodSecurity bool
+
+	// ExtraInitSkipPhases are user requested kubeadm init phases to skip,
+	// in addition to those kind skips by default
+	ExtraInitSkipPhases []string
+
 	// DerivedConfigData contains fields computed from the other fields for use
 	// in the config templates and should only be populated by calling Derive()
 	DerivedConfigData
@@ -97,6 +151,12 @@ type DerivedConfigData struct {
 	FeatureGatesString string
 	// RuntimeConfigString is of the form `Foo=true,Baz=false`
 	RuntimeConfigString string
+	// KubeReservedString and SystemReservedString are of the form
+	// `cpu=500m,memory=1Gi`
+	KubeReservedString   string
+	SystemReservedString string
+	// EvictionHardString is of the form `memory.available<100Mi`
+	EvictionHardString string
 	// KubeadmFeatureGates contains Kubeadm only feature gates
 	KubeadmFeatureGates map[string]bool
 	// IPv4 values take precedence over IPv6 by default, if true set IPv6 default values
@@ -107,6 +167,29 @@ type DerivedConfigData struct {
 	JoinSkipPhases []string
 	// InitSkipPhases are the skipPhases values for the InitConfiguration.
 	InitSkipPhases []string
+	// CertSANs are the API server certificate SANs: localhost, the API server
+	// listen addresses (other than unspecified ones) and APIServerCertSANs
+	CertSANs []string
+	// NodeCIDRMaskSizeArgs are the kube-controller-manager node CIDR mask
+	// size flags and values, these differ between single and dual stack
+	NodeCIDRMaskSizeArgs map[string]string
+	// EncryptionConfigPath is EncryptionConfigPath if EncryptionProvider is set
+	EncryptionConfigPath string
+	// EncryptionConfigAutomaticReload is true if the kube-apiserver should
+	// reload the EncryptionConfiguration when it changes, requires v1.26+
+	EncryptionConfigAutomaticReload bool
+	// AdmissionConfigPath is AdmissionConfigPath if PodSecurity is set
+	AdmissionConfigPath string
+	// APIServerExtraVolumes are host directories mounted into the kube-apiserver
+	APIServerExtraVolumes []HostPathMount
+}
+
+// HostPathMount is a host directory mounted at the same path into a control
+// plane component
+type HostPathMount struct {
+	Name     string
+	Path     string
+	ReadOnly bool
 }
 
 type FeatureGate struct {
@@ -120,6 +203,11 @@ func (c *ConfigData) Derive() {
 	// TODO: refactor and move all deriving logic to this method
 	c.CgroupDriver = "systemd"
 
+	// default to the containerd CRI socket
+	if c.CRISocket == "" {
+		c.CRISocket = ContainerdCRISocket
+	}
+
 	// get the first address to use it as the API advertised address
 	c.AdvertiseAddress = strings.Split(c.NodeAddress, ",")[0]
 
@@ -167,6 +255,23 @@ func (c *ConfigData) Derive() {
 	}
 	c.RuntimeConfigString = strings.Join(runtimeConfig, ",")
 
+	// create sorted strings of the kubelet reservations for the per node
+	// kubelet flags, the eviction flag replaces the KubeletConfiguration's
+	// thresholds so it must include kind's defaults
+	c.KubeReservedString = sortedJoin(c.KubeReserved, "=")
+	c.SystemReservedString = sortedJoin(c.SystemReserved, "=")
+	if len(c.EvictionHard) > 0 {
+		evictionHard := map[string]string{
+			"nodefs.available":  "0%",
+			"nodefs.inodesFree": "0%",
+			"imagefs.available": "0%",
+		}
+		for k, v := range c.EvictionHard {
+			evictionHard[k] = v
+		}
+		c.EvictionHardString = sortedJoin(evictionHard, "<")
+	}
+
 	// Skip preflight to avoid pulling images.
 	// Kind pre-pulls images and preflight may conflict with that.
 	// requires kubeadm 1.22+
@@ -175,6 +280,82 @@ func (c *ConfigData) Derive() {
 	if c.KubeProxyMode == string(config.NoneProxyMode) {
 		c.InitSkipPhases = append(c.InitSkipPhases, "addon/kube-proxy")
 	}
+	c.InitSkipPhases = appendMissing(c.InitSkipPhases, c.ExtraInitSkipPhases...)
+
+	// the API server must be reachable by name / address on every host
+	// address it is published on, 0.0.0.0 and :: are not meaningful SANs
+	c.CertSANs = []string{"localhost"}
+	for _, address := range append([]string{c.APIServerAddress}, c.APIServerAdditionalAddresses...) {
+		if ip := net.ParseIP(address); ip != nil && ip.IsUnspecified() {
+			continue
+		}
+		c.CertSANs = appendMissing(c.CertSANs, address)
+	}
+	c.CertSANs = appendMissing(c.CertSANs, c.APIServerCertSANs...)
+
+	// the kube-apiserver reads the encryption configuration, and for the
+	// kms provider connects to the plugin's socket, on the host
+	c.APIServerExtraVolumes = nil
+	if c.EncryptionProvider != "" {
+		c.EncryptionConfigPath = EncryptionConfigPath
+		c.APIServerExtraVolumes = append(c.APIServerExtraVolumes, HostPathMount{
+			Name:     "encryption-config",
+			Path:     EncryptionConfigDir,
+			ReadOnly: true,
+		})
+		if c.EncryptionKMSEndpoint != "" {
+			c.APIServerExtraVolumes = append(c.APIServerExtraVolumes, HostPathMount{
+				Name: "kms-plugin",
+				Path: path.Dir(strings.TrimPrefix(c.EncryptionKMSEndpoint, "unix://")),
+			})
+		}
+	}
+	if c.PodSecurity {
+		c.AdmissionConfigPath = AdmissionConfigPath
+		c.APIServerExtraVolumes = append(c.APIServerExtraVolumes, HostPathMount{
+			Name:     "admission-config",
+			Path:     AdmissionConfigDir,
+			ReadOnly: true,
+		})
+	}
+
+	// kube-controller-manager only accepts the per-family node CIDR mask
+	// size flags for dual stack clusters
+	c.NodeCIDRMaskSizeArgs = make(map[string]string)
+	switch c.IPFamily {
+	case config.DualStackFamily:
+		if c.NodeCIDRMaskSizeIPv4 != 0 {
+			c.NodeCIDRMaskSizeArgs["node-cidr-mask-size-ipv4"] = fmt.Sprint(c.NodeCIDRMaskSizeIPv4)
+		}
+		if c.NodeCIDRMaskSizeIPv6 != 0 {
+			c.NodeCIDRMaskSizeArgs["node-cidr-mask-size-ipv6"] = fmt.Sprint(c.NodeCIDRMaskSizeIPv6)
+		}
+	case config.IPv6Family:
+		if c.NodeCIDRMaskSizeIPv6 != 0 {
+			c.NodeCIDRMaskSizeArgs["node-cidr-mask-size"] = fmt.Sprint(c.NodeCIDRMaskSizeIPv6)
+		}
+	default:
+		if c.NodeCIDRMaskSizeIPv4 != 0 {
+			c.NodeCIDRMaskSizeArgs["node-cidr-mask-size"] = fmt.Sprint(c.NodeCIDRMaskSizeIPv4)
+		}
+	}
+}
+
+// appendMissing appends the items not already in list to list, in order
+func appendMissing(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range list {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
 }
 
 // See docs for these APIs at:
@@ -199,18 +380,43 @@ controlPlaneEndpoint: "{{ .ControlPlaneEndpoint }}"
 # so we need to ensure the cert is valid for localhost so we can talk
 # to the cluster after rewriting the kubeconfig to point to localhost
 apiServer:
-  certSANs: [localhost, "{{.APIServerAddress}}"]
+  certSANs:
+{{ range $san := .CertSANs }}
+  - "{{ $san }}"
+{{ end }}
   extraArgs:
     "runtime-config": "{{ .RuntimeConfigString }}"
 {{ if .FeatureGates }}
     "feature-gates": "{{ .FeatureGatesString }}"
 {{ end}}
+{{ if .EncryptionConfigPath }}
+    "encryption-provider-config": "{{ .EncryptionConfigPath }}"
+{{ if .EncryptionConfigAutomaticReload }}
+    "encryption-provider-config-automatic-reload": "true"
+{{ end }}
+{{ end }}
+{{ if .AdmissionConfigPath }}
+    "admission-control-config-file": "{{ .AdmissionConfigPath }}"
+{{ end }}
+{{ if .APIServerExtraVolumes }}
+  extraVolumes:
+{{ range $volume := .APIServerExtraVolumes }}
+  - name: "{{ $volume.Name }}"
+    hostPath: "{{ $volume.Path }}"
+    mountPath: "{{ $volume.Path }}"
+    readOnly: {{ $volume.ReadOnly }}
+    pathType: DirectoryOrCreate
+{{ end }}
+{{ end }}
 controllerManager:
   extraArgs:
 {{ if .FeatureGates }}
     "feature-gates": "{{ .FeatureGatesString }}"
 {{ end }}
     enable-hostpath-provisioner: "true"
+{{ range $key, $value := .NodeCIDRMaskSizeArgs }}
+    "{{ (StructuralData $key) }}": "{{ $value }}"
+{{ end }}
     # configure ipv6 default addresses for IPv6 clusters
     {{ if .IPv6 -}}
     bind-address: "::"
@@ -241,11 +447,23 @@ localAPIEndpoint:
   advertiseAddress: "{{ .AdvertiseAddress }}"
   bindPort: {{.APIBindPort}}
 nodeRegistration:
-  criSocket: "unix:///run/containerd/containerd.sock"
+  criSocket: "{{ .CRISocket }}"
   kubeletExtraArgs:
     node-ip: "{{ .NodeAddress }}"
     provider-id: "kind://{{.NodeProvider}}/{{.ClusterName}}/{{.NodeName}}"
     node-labels: "{{ .NodeLabels }}"
+{{- if .KubeReservedString }}
+    kube-reserved: "{{ .KubeReservedString }}"
+{{- end }}
+{{- if .SystemReservedString }}
+    system-reserved: "{{ .SystemReservedString }}"
+{{- end }}
+{{- if .EvictionHardString }}
+    eviction-hard: "{{ .EvictionHardString }}"
+{{- end }}
+{{- if .NodeTaints }}
+    register-with-taints: "{{ .NodeTaints }}"
+{{- end }}
 ---
 # no-op entry that exists solely so it can be patched
 apiVersion: kubeadm.k8s.io/v1beta2
@@ -259,11 +477,23 @@ controlPlane:
     bindPort: {{.APIBindPort}}
 {{- end }}
 nodeRegistration:
-  criSocket: "unix:///run/containerd/containerd.sock"
+  criSocket: "{{ .CRISocket }}"
   kubeletExtraArgs:
     node-ip: "{{ .NodeAddress }}"
     provider-id: "kind://{{.NodeProvider}}/{{.ClusterName}}/{{.NodeName}}"
     node-labels: "{{ .NodeLabels }}"
+{{- if .KubeReservedString }}
+    kube-reserved: "{{ .KubeReservedString }}"
+{{- end }}
+{{- if .SystemReservedString }}
+    system-reserved: "{{ .SystemReservedString }}"
+{{- end }}
+{{- if .EvictionHardString }}
+    eviction-hard: "{{ .EvictionHardString }}"
+{{- end }}
+{{- if .NodeTaints }}
+    register-with-taints: "{{ .NodeTaints }}"
+{{- end }}
 discovery:
   bootstrapToken:
     apiServerEndpoint: "{{ .ControlPlaneEndpoint }}"
@@ -342,18 +572,43 @@ controlPlaneEndpoint: "{{ .ControlPlaneEndpoint }}"
 # so we need to ensure the cert is valid for localhost so we can talk
 # to the cluster after rewriting the kubeconfig to point to localhost
 apiServer:
-  certSANs: [localhost, "{{.APIServerAddress}}"]
+  certSANs:
+{{ range $san := .CertSANs }}
+  - "{{ $san }}"
+{{ end }}
   extraArgs:
     "runtime-config": "{{ .RuntimeConfigString }}"
 {{ if .FeatureGates }}
     "feature-gates": "{{ .FeatureGatesString }}"
 {{ end}}
+{{ if .EncryptionConfigPath }}
+    "encryption-provider-config": "{{ .EncryptionConfigPath }}"
+{{ if .EncryptionConfigAutomaticReload }}
+    "encryption-provider-config-automatic-reload": "true"
+{{ end }}
+{{ end }}
+{{ if .AdmissionConfigPath }}
+    "admission-control-config-file": "{{ .AdmissionConfigPath }}"
+{{ end }}
+{{ if .APIServerExtraVolumes }}
+  extraVolumes:
+{{ range $volume := .APIServerExtraVolumes }}
+  - name: "{{ $volume.Name }}"
+    hostPath: "{{ $volume.Path }}"
+    mountPath: "{{ $volume.Path }}"
+    readOnly: {{ $volume.ReadOnly }}
+    pathType: DirectoryOrCreate
+{{ end }}
+{{ end }}
 controllerManager:
   extraArgs:
 {{ if .FeatureGates }}
     "feature-gates": "{{ .FeatureGatesString }}"
 {{ end }}
     enable-hostpath-provisioner: "true"
+{{ range $key, $value := .NodeCIDRMaskSizeArgs }}
+    "{{ (StructuralData $key) }}": "{{ $value }}"
+{{ end }}
     # configure ipv6 default addresses for IPv6 clusters
     {{ if .IPv6 -}}
     bind-address: "::"
@@ -384,11 +639,27 @@ localAPIEndpoint:
   advertiseAddress: "{{ .AdvertiseAddress }}"
   bindPort: {{.APIBindPort}}
 nodeRegistration:
-  criSocket: "unix:///run/containerd/containerd.sock"
+  criSocket: "{{ .CRISocket }}"
   kubeletExtraArgs:
     node-ip: "{{ .NodeAddress }}"
     provider-id: "kind://{{.NodeProvider}}/{{.ClusterName}}/{{.NodeName}}"
     node-labels: "{{ .NodeLabels }}"
+{{- if .KubeReservedString }}
+    kube-reserved: "{{ .KubeReservedString }}"
+{{- end }}
+{{- if .SystemReservedString }}
+    system-reserved: "{{ .SystemReservedString }}"
+{{- end }}
+{{- if .EvictionHardString }}
+    eviction-hard: "{{ .EvictionHardString }}"
+{{- end }}
+{{- if .NodeTaints }}
+    register-with-taints: "{{ .NodeTaints }}"
+{{- end }}
+{{ if .PatchesDirectory -}}
+patches:
+  directory: "{{ .PatchesDirectory }}"
+{{ end -}}
 {{ if .InitSkipPhases -}}
 skipPhases:
   {{- range $phase := .InitSkipPhases }}
@@ -408,16 +679,280 @@ controlPlane:
     bindPort: {{.APIBindPort}}
 {{- end }}
 nodeRegistration:
-  criSocket: "unix:///run/containerd/containerd.sock"
+  criSocket: "{{ .CRISocket }}"
   kubeletExtraArgs:
     node-ip: "{{ .NodeAddress }}"
     provider-id: "kind://{{.NodeProvider}}/{{.ClusterName}}/{{.NodeName}}"
     node-labels: "{{ .NodeLabels }}"
+{{- if .KubeReservedString }}
+    kube-reserved: "{{ .KubeReservedString }}"
+{{- end }}
+{{- if .SystemReservedString }}
+    system-reserved: "{{ .SystemReservedString }}"
+{{- end }}
+{{- if .EvictionHardString }}
+    eviction-hard: "{{ .EvictionHardString }}"
+{{- end }}
+{{- if .NodeTaints }}
+    register-with-taints: "{{ .NodeTaints }}"
+{{- end }}
+discovery:
+  bootstrapToken:
+    apiServerEndpoint: "{{ .ControlPlaneEndpoint }}"
+    token: "{{ .Token }}"
+    unsafeSkipCAVerification: true
+{{ if .PatchesDirectory -}}
+patches:
+  directory: "{{ .PatchesDirectory }}"
+{{ end -}}
+{{ if .JoinSkipPhases -}}
+skipPhases:
+  {{ range $phase := .JoinSkipPhases -}}
+  - "{{ $phase }}"
+  {{- end }}
+{{- end }}
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+metadata:
+  name: config
+cgroupDriver: {{ .CgroupDriver }}
+cgroupRoot: /kubelet
+failSwapOn: false
+{{ if .SwapBehavior -}}
+memorySwap:
+  swapBehavior: {{ .SwapBehavior }}
+{{ end -}}
+# configure ipv6 addresses in IPv6 mode
+{{ if .IPv6 -}}
+address: "::"
+healthzBindAddress: "::"
+{{- end }}
+# disable disk resource management by default
+# kubelet will see the host disk that the inner container runtime
+# is ultimately backed by and attempt to recover disk space. we don't want that.
+imageGCHighThresholdPercent: 100
+evictionHard:
+  nodefs.available: "0%"
+  nodefs.inodesFree: "0%"
+  imagefs.available: "0%"
+{{if .FeatureGates}}featureGates:
+{{ range $index, $gate := .SortedFeatureGates }}
+  "{{ (StructuralData $gate.Name) }}": {{ $gate.Value }}
+{{end}}{{end}}
+{{if ne .KubeProxyMode "none"}}
+---
+apiVersion: kubeproxy.config.k8s.io/v1alpha1
+kind: KubeProxyConfiguration
+metadata:
+  name: config
+mode: "{{ .KubeProxyMode }}"
+{{if .FeatureGates}}featureGates:
+{{ range $index, $gate := .SortedFeatureGates }}
+  "{{ (StructuralData $gate.Name) }}": {{ $gate.Value }}
+{{end}}{{end}}
+iptables:
+  minSyncPeriod: 1s
+conntrack:
+# Skip setting sysctl value "net.netfilter.nf_conntrack_max"
+# It is a global variable that affects other namespaces
+  maxPerCore: 0
+# Set sysctl value "net.netfilter.nf_conntrack_tcp_be_liberal"
+# for nftables proxy (theoretically for kernels older than 6.1)
+# xref: https://github.com/kubernetes/kubernetes/issues/117924
+{{if and (eq .KubeProxyMode "nftables") (not .RootlessProvider)}}
+  tcpBeLiberal: true
+{{end}}
+{{if .RootlessProvider}}
+# Skip setting "net.netfilter.nf_conntrack_tcp_timeout_established"
+  tcpEstablishedTimeout: 0s
+# Skip setting "net.netfilter.nf_conntrack_tcp_timeout_close"
+  tcpCloseWaitTimeout: 0s
+{{end}}{{end}}
+`
+
+// ConfigTemplateBetaV4 is the kubeadm config template for API version v1beta4,
+// component extraArgs are lists of name / value pairs instead of maps
+const ConfigTemplateBetaV4 = `# config generated by kind
+apiVersion: kubeadm.k8s.io/v1beta4
+kind: ClusterConfiguration
+metadata:
+  name: config
+kubernetesVersion: {{.KubernetesVersion}}
+clusterName: "{{.ClusterName}}"
+{{ if .CertificateValidityPeriod -}}
+certificateValidityPeriod: "{{ .CertificateValidityPeriod }}"
+{{ end -}}
+{{ if .CACertificateValidityPeriod -}}
+caCertificateValidityPeriod: "{{ .CACertificateValidityPeriod }}"
+{{ end -}}
+{{ if .KubeadmFeatureGates}}featureGates:
+{{ range $key, $value := .KubeadmFeatureGates }}
+  "{{ (StructuralData $key) }}": {{ $value }}
+{{end}}{{end}}
+controlPlaneEndpoint: "{{ .ControlPlaneEndpoint }}"
+# on docker for mac we have to expose the api server via port forward,
+# so we need to ensure the cert is valid for localhost so we can talk
+# to the cluster after rewriting the kubeconfig to point to localhost
+apiServer:
+  certSANs:
+{{ range $san := .CertSANs }}
+  - "{{ $san }}"
+{{ end }}
+  extraArgs:
+  - name: "runtime-config"
+    value: "{{ .RuntimeConfigString }}"
+{{ if .FeatureGates }}
+  - name: "feature-gates"
+    value: "{{ .FeatureGatesString }}"
+{{ end}}
+{{ if .EncryptionConfigPath }}
+  - name: "encryption-provider-config"
+    value: "{{ .EncryptionConfigPath }}"
+{{ if .EncryptionConfigAutomaticReload }}
+  - name: "encryption-provider-config-automatic-reload"
+    value: "true"
+{{ end }}
+{{ end }}
+{{ if .AdmissionConfigPath }}
+  - name: "admission-control-config-file"
+    value: "{{ .AdmissionConfigPath }}"
+{{ end }}
+{{ if .APIServerExtraVolumes }}
+  extraVolumes:
+{{ range $volume := .APIServerExtraVolumes }}
+  - name: "{{ $volume.Name }}"
+    hostPath: "{{ $volume.Path }}"
+    mountPath: "{{ $volume.Path }}"
+    readOnly: {{ $volume.ReadOnly }}
+    pathType: DirectoryOrCreate
+{{ end }}
+{{ end }}
+controllerManager:
+  extraArgs:
+{{ if .FeatureGates }}
+  - name: "feature-gates"
+    value: "{{ .FeatureGatesString }}"
+{{ end }}
+  - name: enable-hostpath-provisioner
+    value: "true"
+{{ range $key, $value := .NodeCIDRMaskSizeArgs }}
+  - name: "{{ (StructuralData $key) }}"
+    value: "{{ $value }}"
+{{ end }}
+  # configure ipv6 default addresses for IPv6 clusters
+  {{ if .IPv6 -}}
+  - name: bind-address
+    value: "::"
+  {{- end }}
+scheduler:
+  extraArgs:
+{{ if .FeatureGates }}
+  - name: "feature-gates"
+    value: "{{ .FeatureGatesString }}"
+{{ end }}
+  # configure ipv6 default addresses for IPv6 clusters
+  {{ if .IPv6 -}}
+  - name: bind-address
+    value: "::1"
+  {{- end }}
+networking:
+  podSubnet: "{{ .PodSubnet }}"
+  serviceSubnet: "{{ .ServiceSubnet }}"
+---
+apiVersion: kubeadm.k8s.io/v1beta4
+kind: InitConfiguration
+metadata:
+  name: config
+# we use a well know token for TLS bootstrap
+bootstrapTokens:
+- token: "{{ .Token }}"
+# we use a well know port for making the API server discoverable inside docker network. 
+# from the host machine such port will be accessible via a random local port instead.
+localAPIEndpoint:
+  advertiseAddress: "{{ .AdvertiseAddress }}"
+  bindPort: {{.APIBindPort}}
+nodeRegistration:
+  criSocket: "{{ .CRISocket }}"
+  kubeletExtraArgs:
+  - name: node-ip
+    value: "{{ .NodeAddress }}"
+  - name: provider-id
+    value: "kind://{{.NodeProvider}}/{{.ClusterName}}/{{.NodeName}}"
+  - name: node-labels
+    value: "{{ .NodeLabels }}"
+{{- if .KubeReservedString }}
+  - name: kube-reserved
+    value: "{{ .KubeReservedString }}"
+{{- end }}
+{{- if .SystemReservedString }}
+  - name: system-reserved
+    value: "{{ .SystemReservedString }}"
+{{- end }}
+{{- if .EvictionHardString }}
+  - name: eviction-hard
+    value: "{{ .EvictionHardString }}"
+{{- end }}
+{{- if .NodeTaints }}
+  - name: register-with-taints
+    value: "{{ .NodeTaints }}"
+{{- end }}
+{{ if .PatchesDirectory -}}
+patches:
+  directory: "{{ .PatchesDirectory }}"
+{{ end -}}
+{{ if .InitSkipPhases -}}
+skipPhases:
+  {{- range $phase := .InitSkipPhases }}
+  - "{{ $phase }}"
+  {{- end }}
+{{- end }}
+---
+# no-op entry that exists solely so it can be patched
+apiVersion: kubeadm.k8s.io/v1beta4
+kind: JoinConfiguration
+metadata:
+  name: config
+{{ if .ControlPlane -}}
+controlPlane:
+  localAPIEndpoint:
+    advertiseAddress: "{{ .AdvertiseAddress }}"
+    bindPort: {{.APIBindPort}}
+{{- end }}
+nodeRegistration:
+  criSocket: "{{ .CRISocket }}"
+  kubeletExtraArgs:
+  - name: node-ip
+    value: "{{ .NodeAddress }}"
+  - name: provider-id
+    value: "kind://{{.NodeProvider}}/{{.ClusterName}}/{{.NodeName}}"
+  - name: node-labels
+    value: "{{ .NodeLabels }}"
+{{- if .KubeReservedString }}
+  - name: kube-reserved
+    value: "{{ .KubeReservedString }}"
+{{- end }}
+{{- if .SystemReservedString }}
+  - name: system-reserved
+    value: "{{ .SystemReservedString }}"
+{{- end }}
+{{- if .EvictionHardString }}
+  - name: eviction-hard
+    value: "{{ .EvictionHardString }}"
+{{- end }}
+{{- if .NodeTaints }}
+  - name: register-with-taints
+    value: "{{ .NodeTaints }}"
+{{- end }}
 discovery:
   bootstrapToken:
     apiServerEndpoint: "{{ .ControlPlaneEndpoint }}"
     token: "{{ .Token }}"
     unsafeSkipCAVerification: true
+{{ if .PatchesDirectory -}}
+patches:
+  directory: "{{ .PatchesDirectory }}"
+{{ end -}}
 {{ if .JoinSkipPhases -}}
 skipPhases:
   {{ range $phase := .JoinSkipPhases -}}
@@ -432,6 +967,10 @@ metadata:
 cgroupDriver: {{ .CgroupDriver }}
 cgroupRoot: /kubelet
 failSwapOn: false
+{{ if .SwapBehavior -}}
+memorySwap:
+  swapBehavior: {{ .SwapBehavior }}
+{{ end -}}
 # configure ipv6 addresses in IPv6 mode
 {{ if .IPv6 -}}
 address: "::"
@@ -502,8 +1041,36 @@ func Config(data ConfigData) (config string, err error) {
 		data.FeatureGates["KubeletInUserNamespace"] = true
 	}
 
+	if data.SwapBehavior != "" {
+		if ver.LessThan(version.MustParseSemantic("v1.28.0")) {
+			return "", errors.New("swapBehavior requires Kubernetes v1.28+")
+		}
+		if data.SwapBehavior == "NoSwap" && ver.LessThan(version.MustParseSemantic("v1.30.0")) {
+			return "", errors.New("swapBehavior NoSwap requires Kubernetes v1.30+")
+		}
+		// NodeSwap is beta but disabled by default before v1.30
+		if _, set := data.FeatureGates["NodeSwap"]; !set && ver.LessThan(version.MustParseSemantic("v1.30.0")) {
+			data.FeatureGates["NodeSwap"] = true
+		}
+	}
+
+	if data.EncryptionProvider == "kms" && ver.LessThan(version.MustParseSemantic("v1.29.0")) {
+		return "", errors.New("the kms encryption at rest provider requires Kubernetes v1.29+")
+	}
+
+	if data.PodSecurity && ver.LessThan(version.MustParseSemantic("v1.23.0")) {
+		return "", errors.New("podSecurity requires Kubernetes v1.23+")
+	}
+
 	// assume the latest API version, then fallback if the k8s version is too low
-	templateSource := ConfigTemplateBetaV3
+	templateSource := ConfigTemplateBetaV4
+	if ver.LessThan(version.MustParseSemantic("v1.31.0")) {
+		// certificate validity periods are only available in v1beta4
+		if data.CertificateValidityPeriod != "" || data.CACertificateValidityPeriod != "" {
+			return "", errors.New("certificate validity periods require Kubernetes v1.31+")
+		}
+		templateSource = ConfigTemplateBetaV3
+	}
 	if ver.LessThan(version.MustParseSemantic("v1.23.0")) {
 		templateSource = ConfigTemplateBetaV2
 	}
@@ -527,6 +1094,10 @@ func Config(data ConfigData) (config string, err error) {
 		data.KubeadmFeatureGates["IPv6DualStack"] = true
 	}
 
+	// the kube-apiserver can reload the encryption configuration since 1.26
+	data.EncryptionConfigAutomaticReload = data.EncryptionConfigPath != "" &&
+		ver.AtLeast(version.MustParseSemantic("v1.26.0"))
+
 	// before 1.24 kind uses cgroupfs
 	// after 1.24 kind uses systemd starting in kind v0.13.0
 	// before kind v0.13.0 kubernetes 1.24 wasn't released yet
@@ -542,3 +1113,18 @@ func Config(data ConfigData) (config string, err error) {
 	}
 	return buff.String(), nil
 }
+
+// sortedJoin returns the key<sep>value pairs of m sorted by key and joined
+// with commas, as in kubelet map flags
+func sortedJoin(m map[string]string, sep string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+sep+m[k])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -22,3 +22,31 @@ const Token = "abcdef.0123456789abcdef"
 // ObjectName is the name every generated object will have
 // I.E. `metadata:\nname: config`
 const ObjectName = "config"
+
+// PatchesDir is the directory on the node kind writes kubeadm component
+// patches to, see: kubeadm init --patches
+const PatchesDir = "/kind/patches"
+
+// EncryptionConfigDir is the directory on the control plane nodes kind writes
+// the kube-apiserver EncryptionConfiguration to, it is mounted into the
+// kube-apiserver so the configuration can be edited to rotate keys
+const EncryptionConfigDir = "/etc/kubernetes/encryption"
+
+// EncryptionConfigPath is the kube-apiserver EncryptionConfiguration file
+const EncryptionConfigPath = EncryptionConfigDir + "/config.yaml"
+
+// AdmissionConfigDir is the directory on the control plane nodes kind writes
+// the kube-apiserver AdmissionConfiguration to, it is mounted into the
+// kube-apiserver
+const AdmissionConfigDir = "/etc/kubernetes/admission"
+
+// AdmissionConfigPath is the kube-apiserver AdmissionConfiguration file
+const AdmissionConfigPath = AdmissionConfigDir + "/config.yaml"
+
+// ContainerdCRISocket is the CRI endpoint of containerd, the default container
+// runtime in kind node images
+const ContainerdCRISocket = "unix:///run/containerd/containerd.sock"
+
+// CRIOCRISocket is the CRI endpoint of CRI-O, used by node images built with
+// the experimental CRI-O runtime
+const CRIOCRISocket = "unix:///var/run/crio/crio.sock"
@@ -0,0 +1,352 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// Group is the API group of the kubeadm config types
+const Group = "kubeadm.k8s.io"
+
+// listArgsVersion is the first kubeadm API version with component arguments
+// as lists of name / value pairs instead of maps
+const listArgsVersion = "v1beta4"
+
+// argsFields are the component argument fields of each kubeadm kind
+var argsFields = map[string][][]string{
+	"ClusterConfiguration": {
+		{"apiServer", "extraArgs"},
+		{"controllerManager", "extraArgs"},
+		{"scheduler", "extraArgs"},
+		{"etcd", "local", "extraArgs"},
+	},
+	"InitConfiguration": {{"nodeRegistration", "kubeletExtraArgs"}},
+	"JoinConfiguration": {{"nodeRegistration", "kubeletExtraArgs"}},
+}
+
+// removedField is a field that patches can not be converted for
+type removedField struct {
+	kind string
+	path []string
+	// removedIn is the first API version without the field
+	removedIn string
+	// hint is what to use instead
+	hint string
+}
+
+var removedFields = []removedField{
+	{"ClusterConfiguration", []string{"useHyperKubeImage"}, "v1beta3", "remove it"},
+	{"ClusterConfiguration", []string{"dns", "type"}, "v1beta3", "remove it, CoreDNS is the only DNS server"},
+	{"ClusterConfiguration", []string{"apiServer", "timeoutForControlPlane"}, "v1beta4", "set timeouts.controlPlaneComponentHealthCheck in the InitConfiguration"},
+	{"JoinConfiguration", []string{"discovery", "timeout"}, "v1beta4", "set timeouts.discovery"},
+}
+
+// ConvertPatches converts kubeadmConfigPatches and kubeadmConfigPatchesJSON6902
+// for the kubeadm config types to the API versions used in kubeadmConfig,
+// the generated kubeadm config they are applied to.
+//
+// Patches for another API version of a kind are converted only if there
+// are no patches for the API version in use, otherwise they are left to be
+// ignored as before, so patches can still be written for each version.
+// Component arguments written as maps are converted to the lists used since
+// v1beta4, including in patches without an apiVersion. Patches that can not
+// be converted are rejected with an error explaining why.
+func ConvertPatches(kubeadmConfig string, patches []string, patches6902 []config.PatchJSON6902) ([]string, []config.PatchJSON6902, error) {
+	docs, err := kubeadmDocuments(kubeadmConfig)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse kubeadm config")
+	}
+
+	// parse the merge patches, and find the kinds with patches for the API
+	// version in use
+	parsed := make([]map[string]interface{}, len(patches))
+	exact := map[string]bool{}
+	for i, raw := range patches {
+		p := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(raw), &p); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse kubeadmConfigPatches")
+		}
+		parsed[i] = p
+		kind, apiVersion := typeMeta(p)
+		if doc, ok := docs[kind]; ok && apiVersion == doc.apiVersion {
+			exact[kind] = true
+		}
+	}
+	for _, p := range patches6902 {
+		if doc, ok := docs[p.Kind]; ok && p.Group+"/"+p.Version == doc.apiVersion {
+			exact[p.Kind] = true
+		}
+	}
+
+	converted := make([]string, len(patches))
+	for i, p := range parsed {
+		c, changed, err := convertMergePatch(p, docs, exact)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !changed {
+			converted[i] = patches[i]
+			continue
+		}
+		b, err := json.Marshal(c)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to encode converted patch")
+		}
+		converted[i] = string(b)
+	}
+
+	converted6902 := make([]config.PatchJSON6902, len(patches6902))
+	for i, p := range patches6902 {
+		c, err := convertJSON6902Patch(p, docs, exact)
+		if err != nil {
+			return nil, nil, err
+		}
+		converted6902[i] = c
+	}
+	return converted, converted6902, nil
+}
+
+// kubeadmDocument is a kubeadm config type in the generated config
+type kubeadmDocument struct {
+	apiVersion string
+	// args are the current component arguments, by argsFields path
+	args map[string]interface{}
+}
+
+// kubeadmDocuments returns the kubeadm config types in kubeadmConfig by kind
+func kubeadmDocuments(kubeadmConfig string) (map[string]*kubeadmDocument, error) {
+	docs := map[string]*kubeadmDocument{}
+	decoder := yaml.NewDecoder(strings.NewReader(kubeadmConfig))
+	for {
+		doc := map[string]interface{}{}
+		if err := decoder.Decode(&doc); err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		kind, apiVersion := typeMeta(doc)
+		if apiGroup(apiVersion) != Group {
+			continue
+		}
+		d := &kubeadmDocument{apiVersion: apiVersion, args: map[string]interface{}{}}
+		for _, path := range argsFields[kind] {
+			if v, ok := lookup(doc, path); ok {
+				d.args[strings.Join(path, ".")] = v
+			}
+		}
+		docs[kind] = d
+	}
+}
+
+// convertMergePatch returns patch converted to the API version of the
+// document it applies to, and whether it was changed
+func convertMergePatch(patch map[string]interface{}, docs map[string]*kubeadmDocument, exact map[string]bool) (map[string]interface{}, bool, error) {
+	kind, apiVersion := typeMeta(patch)
+	doc, ok := docs[kind]
+	if !ok || (apiVersion != "" && apiGroup(apiVersion) != Group) {
+		return patch, false, nil
+	}
+	target := apiVersionVersion(doc.apiVersion)
+	changed := false
+	if apiVersion != "" && apiVersion != doc.apiVersion {
+		// leave version specific patches to be ignored
+		if exact[kind] {
+			return patch, false, nil
+		}
+		if err := checkConvertible(kind, apiVersion, doc.apiVersion); err != nil {
+			return nil, false, err
+		}
+		patch["apiVersion"] = doc.apiVersion
+		changed = true
+	}
+	for _, f := range removedFields {
+		if f.kind != kind || target < f.removedIn {
+			continue
+		}
+		if apiVersion != "" && apiVersionVersion(apiVersion) >= f.removedIn {
+			continue
+		}
+		if _, ok := lookup(patch, f.path); ok {
+			return nil, false, errors.Errorf(
+				"kubeadmConfigPatches for %s set %s, which %s does not have: %s instead",
+				kind, strings.Join(f.path, "."), doc.apiVersion, f.hint,
+			)
+		}
+	}
+	if target < listArgsVersion {
+		return patch, changed, nil
+	}
+	for _, path := range argsFields[kind] {
+		key := strings.Join(path, ".")
+		v, _ := lookup(patch, path)
+		switch v := v.(type) {
+		case map[string]interface{}:
+			// merge patches replace lists, so merge the arguments into the
+			// current ones and patch the result
+			list := mergeArgs(doc.args[key], v)
+			set(patch, path, list)
+			doc.args[key] = list
+			changed = true
+		case []interface{}:
+			doc.args[key] = v
+		}
+	}
+	return patch, changed, nil
+}
+
+// convertJSON6902Patch returns patch converted to the API version of the
+// document it applies to
+func convertJSON6902Patch(patch config.PatchJSON6902, docs map[string]*kubeadmDocument, exact map[string]bool) (config.PatchJSON6902, error) {
+	doc, ok := docs[patch.Kind]
+	apiVersion := patch.Group + "/" + patch.Version
+	if !ok || patch.Group != Group || apiVersion == doc.apiVersion || exact[patch.Kind] {
+		return patch, nil
+	}
+	if err := checkConvertible(patch.Kind, apiVersion, doc.apiVersion); err != nil {
+		return patch, err
+	}
+	ops := []struct {
+		Path string `yaml:"path"`
+		From string `yaml:"from"`
+	}{}
+	if err := yaml.Unmarshal([]byte(patch.Patch), &ops); err != nil {
+		return patch, errors.Wrap(err, "failed to parse kubeadmConfigPatchesJSON6902")
+	}
+	// the paths must not cross any field that changed between the versions
+	changed := [][]string{}
+	if patch.Version < listArgsVersion && apiVersionVersion(doc.apiVersion) >= listArgsVersion {
+		changed = append(changed, argsFields[patch.Kind]...)
+	}
+	for _, f := range removedFields {
+		if f.kind == patch.Kind && patch.Version < f.removedIn && apiVersionVersion(doc.apiVersion) >= f.removedIn {
+			changed = append(changed, f.path)
+		}
+	}
+	for _, op := range ops {
+		for _, p := range []string{op.Path, op.From} {
+			for _, c := range changed {
+				pointer := "/" + strings.Join(c, "/")
+				if p == pointer || strings.HasPrefix(p, pointer+"/") {
+					return patch, errors.Errorf(
+						"kubeadmConfigPatchesJSON6902 for %s %s patch %s, which changed in %s, write the patch for %s instead",
+						apiVersion, patch.Kind, p, doc.apiVersion, doc.apiVersion,
+					)
+				}
+			}
+		}
+	}
+	patch.Version = apiVersionVersion(doc.apiVersion)
+	return patch, nil
+}
+
+// checkConvertible returns an error if patches for kind in apiVersion can
+// not be converted to target
+func checkConvertible(kind, apiVersion, target string) error {
+	if apiVersionVersion(apiVersion) > apiVersionVersion(target) {
+		return errors.Errorf(
+			"patches for %s %s are newer than the %s the node image's Kubernetes version uses, write them for %s instead",
+			apiVersion, kind, target, target,
+		)
+	}
+	return nil
+}
+
+// mergeArgs merges args, a map of argument name to value as in kubeadm
+// API versions before v1beta4, into current, a list of name / value pairs.
+// Arguments set to null are removed.
+func mergeArgs(current interface{}, args map[string]interface{}) []interface{} {
+	list := []interface{}{}
+	if l, ok := current.([]interface{}); ok {
+		list = append(list, l...)
+	}
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := args[name]
+		merged := []interface{}{}
+		found := false
+		for _, entry := range list {
+			if e, ok := entry.(map[string]interface{}); ok && e["name"] == name {
+				if value == nil || found {
+					continue
+				}
+				entry = map[string]interface{}{"name": name, "value": fmt.Sprint(value)}
+				found = true
+			}
+			merged = append(merged, entry)
+		}
+		if value != nil && !found {
+			merged = append(merged, map[string]interface{}{"name": name, "value": fmt.Sprint(value)})
+		}
+		list = merged
+	}
+	return list
+}
+
+func typeMeta(obj map[string]interface{}) (kind, apiVersion string) {
+	kind, _ = obj["kind"].(string)
+	apiVersion, _ = obj["apiVersion"].(string)
+	return kind, apiVersion
+}
+
+// apiGroup returns the group of apiVersion, e.g. kubeadm.k8s.io
+func apiGroup(apiVersion string) string {
+	if i := strings.LastIndex(apiVersion, "/"); i != -1 {
+		return apiVersion[:i]
+	}
+	return ""
+}
+
+// apiVersionVersion returns the version of apiVersion, e.g. v1beta4
+func apiVersionVersion(apiVersion string) string {
+	return apiVersion[strings.LastIndex(apiVersion, "/")+1:]
+}
+
+// lookup returns the value at path in obj
+func lookup(obj map[string]interface{}, path []string) (interface{}, bool) {
+	var v interface{} = obj
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// set sets the value at path in obj, which must exist
+func set(obj map[string]interface{}, path []string, value interface{}) {
+	parent, _ := lookup(obj, path[:len(path)-1])
+	parent.(map[string]interface{})[path[len(path)-1]] = value
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+// v1beta4Config is a kubeadm config as generated for Kubernetes v1.31+
+const v1beta4Config = `apiVersion: kubeadm.k8s.io/v1beta4
+kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+  - name: a
+    value: "1"
+  - name: b
+    value: "2"
+---
+apiVersion: kubeadm.k8s.io/v1beta4
+kind: InitConfiguration
+nodeRegistration:
+  kubeletExtraArgs:
+  - name: node-ip
+    value: 172.18.0.2
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+`
+
+// v1beta3Config is a kubeadm config as generated for older Kubernetes
+const v1beta3Config = `apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    a: "1"
+`
+
+func TestConvertPatches(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name            string
+		Config          string
+		Patches         []string
+		Patches6902     []config.PatchJSON6902
+		ExpectedPatches []string
+		Expected6902    []config.PatchJSON6902
+		ExpectError     bool
+	}{
+		{
+			Name:   "map args are merged into the list args, null removes an arg",
+			Config: v1beta4Config,
+			Patches: []string{`apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    a: null
+    b: "3"
+    c: "4"
+`},
+			ExpectedPatches: []string{
+				`{"apiServer":{"extraArgs":[{"name":"b","value":"3"},{"name":"c","value":"4"}]},"apiVersion":"kubeadm.k8s.io/v1beta4","kind":"ClusterConfiguration"}`,
+			},
+			Patches6902:  []config.PatchJSON6902{},
+			Expected6902: []config.PatchJSON6902{},
+		},
+		{
+			Name:   "patches without an apiVersion are converted",
+			Config: v1beta4Config,
+			Patches: []string{`kind: InitConfiguration
+nodeRegistration:
+  kubeletExtraArgs:
+    v: 4
+`},
+			ExpectedPatches: []string{
+				`{"kind":"InitConfiguration","nodeRegistration":{"kubeletExtraArgs":[{"name":"node-ip","value":"172.18.0.2"},{"name":"v","value":"4"}]}}`,
+			},
+			Patches6902:  []config.PatchJSON6902{},
+			Expected6902: []config.PatchJSON6902{},
+		},
+		{
+			Name:   "patches for an older version are ignored if there are patches for the version in use",
+			Config: v1beta4Config,
+			Patches: []string{
+				`apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    c: "4"
+`,
+				`apiVersion: kubeadm.k8s.io/v1beta4
+kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+  - name: c
+    value: "4"
+`,
+			},
+			ExpectedPatches: []string{
+				`apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    c: "4"
+`,
+				`apiVersion: kubeadm.k8s.io/v1beta4
+kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+  - name: c
+    value: "4"
+`,
+			},
+			Patches6902:  []config.PatchJSON6902{},
+			Expected6902: []config.PatchJSON6902{},
+		},
+		{
+			Name:   "patches for other kinds are not changed",
+			Config: v1beta4Config,
+			Patches: []string{`apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+maxPods: 200
+`},
+			ExpectedPatches: []string{`apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+maxPods: 200
+`},
+			Patches6902:  []config.PatchJSON6902{},
+			Expected6902: []config.PatchJSON6902{},
+		},
+		{
+			Name:   "patches are not converted for older kubeadm versions",
+			Config: v1beta3Config,
+			Patches: []string{`kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    b: "2"
+`},
+			ExpectedPatches: []string{`kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    b: "2"
+`},
+			Patches6902:  []config.PatchJSON6902{},
+			Expected6902: []config.PatchJSON6902{},
+		},
+		{
+			Name:   "removed fields are rejected",
+			Config: v1beta4Config,
+			Patches: []string{`apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+apiServer:
+  timeoutForControlPlane: 4m0s
+`},
+			ExpectError: true,
+		},
+		{
+			Name:   "patches for a newer version are rejected",
+			Config: v1beta3Config,
+			Patches: []string{`apiVersion: kubeadm.k8s.io/v1beta4
+kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+  - name: b
+    value: "2"
+`},
+			ExpectError: true,
+		},
+		{
+			Name:    "JSON6902 patches are converted",
+			Config:  v1beta4Config,
+			Patches: []string{},
+			Patches6902: []config.PatchJSON6902{{
+				Group:   "kubeadm.k8s.io",
+				Version: "v1beta3",
+				Kind:    "ClusterConfiguration",
+				Patch:   `[{"op": "add", "path": "/apiServer/certSANs", "value": ["kind.example.com"]}]`,
+			}},
+			ExpectedPatches: []string{},
+			Expected6902: []config.PatchJSON6902{{
+				Group:   "kubeadm.k8s.io",
+				Version: "v1beta4",
+				Kind:    "ClusterConfiguration",
+				Patch:   `[{"op": "add", "path": "/apiServer/certSANs", "value": ["kind.example.com"]}]`,
+			}},
+		},
+		{
+			Name:    "JSON6902 patches of changed fields are rejected",
+			Config:  v1beta4Config,
+			Patches: []string{},
+			Patches6902: []config.PatchJSON6902{{
+				Group:   "kubeadm.k8s.io",
+				Version: "v1beta3",
+				Kind:    "ClusterConfiguration",
+				Patch:   `[{"op": "add", "path": "/apiServer/extraArgs/c", "value": "4"}]`,
+			}},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			patches, patches6902, err := ConvertPatches(tc.Config, tc.Patches, tc.Patches6902)
+			assert.ExpectError(t, tc.ExpectError, err)
+			if tc.ExpectError {
+				return
+			}
+			assert.DeepEqual(t, tc.ExpectedPatches, patches)
+			assert.DeepEqual(t, tc.Expected6902, patches6902)
+		})
+	}
+}
+
+func TestMergeArgs(t *testing.T) {
+	t.Parallel()
+	current := []interface{}{
+		map[string]interface{}{"name": "a", "value": "1"},
+		map[string]interface{}{"name": "b", "value": "2"},
+		map[string]interface{}{"name": "b", "value": "3"},
+	}
+	merged := mergeArgs(current, map[string]interface{}{
+		"a": nil,
+		"b": 4,
+		"c": "5",
+	})
+	assert.DeepEqual(t, []interface{}{
+		map[string]interface{}{"name": "b", "value": "4"},
+		map[string]interface{}{"name": "c", "value": "5"},
+	}, merged)
+	// nothing is merged into nothing
+	assert.DeepEqual(t, []interface{}{}, mergeArgs(nil, map[string]interface{}{"a": nil}))
+}
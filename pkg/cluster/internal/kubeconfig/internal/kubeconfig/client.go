@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"encoding/base64"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// ClientConfig is the endpoint and credentials needed to connect to a
+// cluster, read from a kind kubeconfig
+type ClientConfig struct {
+	// Server is the address of the API server (https://hostname:port)
+	Server string
+	// CertificateAuthorityData is the PEM encoded cluster CA certificate
+	CertificateAuthorityData []byte
+	// ClientCertificateData is the PEM encoded client certificate
+	ClientCertificateData []byte
+	// ClientKeyData is the PEM encoded client key
+	ClientKeyData []byte
+}
+
+// Client returns the ClientConfig from a kind kubeconfig, as returned by
+// KINDFromRawKubeadm
+func Client(cfg *Config) (*ClientConfig, error) {
+	if err := checkKubeadmExpectations(cfg); err != nil {
+		return nil, err
+	}
+	ca, err := decodeData(cfg.Clusters[0].Cluster.OtherFields, "certificate-authority-data")
+	if err != nil {
+		return nil, err
+	}
+	cert, err := decodeData(cfg.Users[0].User, "client-certificate-data")
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeData(cfg.Users[0].User, "client-key-data")
+	if err != nil {
+		return nil, err
+	}
+	return &ClientConfig{
+		Server:                   cfg.Clusters[0].Cluster.Server,
+		CertificateAuthorityData: ca,
+		ClientCertificateData:    cert,
+		ClientKeyData:            key,
+	}, nil
+}
+
+// decodeData decodes the base64 encoded field from a kubeconfig entry
+func decodeData(fields map[string]interface{}, field string) ([]byte, error) {
+	encoded, ok := fields[field].(string)
+	if !ok {
+		return nil, errors.Errorf("kubeconfig is missing %s", field)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s", field)
+	}
+	return data, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	const rawConfig = `apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: Y2E=
+    server: https://kind-control-plane:6443
+  name: kind
+contexts:
+- context:
+    cluster: kind
+    user: kubernetes-admin
+  name: kubernetes-admin@kind
+current-context: kubernetes-admin@kind
+kind: Config
+preferences: {}
+users:
+- name: kubernetes-admin
+  user:
+    client-certificate-data: Y2VydA==
+    client-key-data: a2V5
+`
+	t.Run("valid config", func(t *testing.T) {
+		t.Parallel()
+		cfg, err := KINDFromRawKubeadm(rawConfig, "kind", "", "https://127.0.0.1:6443")
+		assert.ExpectError(t, false, err)
+		client, err := Client(cfg)
+		assert.ExpectError(t, false, err)
+		assert.DeepEqual(t, &ClientConfig{
+			Server:                   "https://127.0.0.1:6443",
+			CertificateAuthorityData: []byte("ca"),
+			ClientCertificateData:    []byte("cert"),
+			ClientKeyData:            []byte("key"),
+		}, client)
+	})
+	t.Run("missing key", func(t *testing.T) {
+		t.Parallel()
+		cfg, err := KINDFromRawKubeadm(rawConfig, "kind", "", "")
+		assert.ExpectError(t, false, err)
+		delete(cfg.Users[0].User, "client-key-data")
+		_, err = Client(cfg)
+		assert.ExpectError(t, true, err)
+	})
+	t.Run("invalid data", func(t *testing.T) {
+		t.Parallel()
+		cfg, err := KINDFromRawKubeadm(rawConfig, "kind", "", "")
+		assert.ExpectError(t, false, err)
+		cfg.Clusters[0].Cluster.OtherFields["certificate-authority-data"] = "not base64!"
+		_, err = Client(cfg)
+		assert.ExpectError(t, true, err)
+	})
+}
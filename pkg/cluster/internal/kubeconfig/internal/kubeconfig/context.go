@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"os"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// previousContextExtension is the name of the context extension in which
+// kind records the context that was current before switching to a kind
+// cluster, so it can be restored when the kind cluster is unset or deleted
+const previousContextExtension = "kind.x-k8s.io/previous-context"
+
+// UnsetKIND restores the previous current context in the KUBECONFIG files
+// at configPaths if the current context is the kind cluster kindClusterName,
+// given its contextTemplate (see KINDClusterKey)
+func UnsetKIND(kindClusterName, contextTemplate, explicitPath string) error {
+	key, err := KINDClusterKey(kindClusterName, contextTemplate)
+	if err != nil {
+		return err
+	}
+	for _, configPath := range paths(explicitPath, os.Getenv) {
+		if err := func(configPath string) error {
+			// lock before modifying
+			if err := lockFile(configPath); err != nil {
+				return errors.Wrap(err, "failed to lock config file")
+			}
+			defer func(configPath string) {
+				_ = unlockFile(configPath)
+			}(configPath)
+
+			// read in existing
+			existing, err := read(configPath)
+			if err != nil {
+				return errors.Wrap(err, "failed to read kubeconfig to unset KIND context")
+			}
+
+			// write out the updated config if we modified anything
+			if restorePreviousContext(existing, key) {
+				if err := write(existing, configPath, FileOptions{}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}(configPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCurrentContext sets the current context of cfg to key, recording the
+// previously current context on the context entry for key
+func setCurrentContext(cfg *Config, key string) {
+	if cfg.CurrentContext != "" && cfg.CurrentContext != key {
+		if c := findContext(cfg, key); c != nil {
+			c.Context.setPreviousContext(cfg.CurrentContext)
+		}
+	}
+	cfg.CurrentContext = key
+}
+
+// restorePreviousContext switches cfg back to the context that was current
+// before key if key is the current context, or unsets the current context
+// if that context no longer exists. It returns true if cfg was modified.
+func restorePreviousContext(cfg *Config, key string) bool {
+	if cfg.CurrentContext != key {
+		return false
+	}
+	previous := ""
+	if c := findContext(cfg, key); c != nil {
+		previous = c.Context.previousContext()
+		c.Context.setPreviousContext("")
+	}
+	if previous != "" && findContext(cfg, previous) == nil {
+		previous = ""
+	}
+	cfg.CurrentContext = previous
+	return true
+}
+
+// findContext returns the context entry named name, or nil if there is none
+func findContext(cfg *Config, name string) *NamedContext {
+	for i := range cfg.Contexts {
+		if cfg.Contexts[i].Name == name {
+			return &cfg.Contexts[i]
+		}
+	}
+	return nil
+}
+
+// previousContext returns the previous context recorded on c, if any
+func (c *Context) previousContext() string {
+	extensions, _ := c.OtherFields["extensions"].([]interface{})
+	for _, e := range extensions {
+		extension, _ := e.(map[string]interface{})
+		if extension["name"] != previousContextExtension {
+			continue
+		}
+		value, _ := extension["extension"].(map[string]interface{})
+		previous, _ := value["context"].(string)
+		return previous
+	}
+	return ""
+}
+
+// setPreviousContext records previous on c, removing the record if previous
+// is empty. OtherFields is copied rather than modified in place as it may be
+// shared with another config.
+func (c *Context) setPreviousContext(previous string) {
+	existing, _ := c.OtherFields["extensions"].([]interface{})
+	extensions := make([]interface{}, 0, len(existing)+1)
+	for _, e := range existing {
+		if extension, ok := e.(map[string]interface{}); ok && extension["name"] == previousContextExtension {
+			continue
+		}
+		extensions = append(extensions, e)
+	}
+	if previous != "" {
+		extensions = append(extensions, map[string]interface{}{
+			"name": previousContextExtension,
+			"extension": map[string]interface{}{
+				"context": previous,
+			},
+		})
+	}
+
+	otherFields := make(map[string]interface{}, len(c.OtherFields)+1)
+	for k, v := range c.OtherFields {
+		otherFields[k] = v
+	}
+	if len(extensions) > 0 {
+		otherFields["extensions"] = extensions
+	} else {
+		delete(otherFields, "extensions")
+	}
+	if len(otherFields) == 0 {
+		otherFields = nil
+	}
+	c.OtherFields = otherFields
+}
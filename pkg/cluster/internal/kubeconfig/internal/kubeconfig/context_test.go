@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestSetAndRestoreCurrentContext(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{
+		Contexts: []NamedContext{
+			{Name: "prod"},
+			{Name: "kind-a"},
+			{Name: "kind-b"},
+		},
+		CurrentContext: "prod",
+	}
+
+	// switch prod -> kind-a -> kind-b, then back again
+	setCurrentContext(cfg, "kind-a")
+	setCurrentContext(cfg, "kind-b")
+	assert.StringEqual(t, "kind-b", cfg.CurrentContext)
+	assert.StringEqual(t, "kind-a", cfg.Contexts[2].Context.previousContext())
+
+	// re-selecting the current context must not record itself
+	setCurrentContext(cfg, "kind-b")
+	assert.StringEqual(t, "kind-a", cfg.Contexts[2].Context.previousContext())
+
+	// only the current context is restored
+	assert.BoolEqual(t, false, restorePreviousContext(cfg, "kind-a"))
+	assert.BoolEqual(t, true, restorePreviousContext(cfg, "kind-b"))
+	assert.StringEqual(t, "kind-a", cfg.CurrentContext)
+	assert.StringEqual(t, "", cfg.Contexts[2].Context.previousContext())
+	assert.BoolEqual(t, true, restorePreviousContext(cfg, "kind-a"))
+	assert.StringEqual(t, "prod", cfg.CurrentContext)
+}
+
+func TestRestorePreviousContextMissing(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{
+		Contexts: []NamedContext{
+			{Name: "prod"},
+			{Name: "kind-a"},
+		},
+		CurrentContext: "prod",
+	}
+	setCurrentContext(cfg, "kind-a")
+	// the previous context was removed in the meantime
+	cfg.Contexts = cfg.Contexts[1:]
+	assert.BoolEqual(t, true, restorePreviousContext(cfg, "kind-a"))
+	assert.StringEqual(t, "", cfg.CurrentContext)
+}
+
+func TestSetPreviousContextPreservesOtherExtensions(t *testing.T) {
+	t.Parallel()
+	other := map[string]interface{}{"name": "example.com/other"}
+	c := Context{
+		OtherFields: map[string]interface{}{
+			"namespace":  "default",
+			"extensions": []interface{}{other},
+		},
+	}
+	c.setPreviousContext("prod")
+	assert.StringEqual(t, "prod", c.previousContext())
+	c.setPreviousContext("")
+	assert.DeepEqual(t, map[string]interface{}{
+		"namespace":  "default",
+		"extensions": []interface{}{other},
+	}, c.OtherFields)
+}
@@ -45,7 +45,7 @@ users:
     client-certificate-data: seemslegit
     client-key-data: yup
 `
-	cfg, err := KINDFromRawKubeadm(aConfig, "kind", "")
+	cfg, err := KINDFromRawKubeadm(aConfig, "kind", "", "")
 	if err != nil {
 		t.Fatalf("failed to decode kubeconfig: %v", err)
 	}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// FileOptions control the mode and owner of the kubeconfig files kind writes
+type FileOptions struct {
+	// Mode is the file mode, if zero existing files keep their mode and new
+	// files are created with 0600
+	Mode os.FileMode
+	// Owner is the file owner, if nil existing files keep their owner where
+	// permitted and new files are owned by the current user
+	Owner *Owner
+}
+
+// Owner is a file owner, -1 for UID or GID leaves it unchanged
+type Owner struct {
+	UID int
+	GID int
+}
+
+// ParseOwner parses owner as "user[:group]", where user and group are
+// names or numeric IDs. The group is left unchanged if it is omitted.
+func ParseOwner(owner string) (*Owner, error) {
+	userPart, groupPart := owner, ""
+	if i := strings.Index(owner, ":"); i >= 0 {
+		userPart, groupPart = owner[:i], owner[i+1:]
+	}
+	if userPart == "" {
+		return nil, errors.Errorf("invalid owner %q: user is required", owner)
+	}
+	o := &Owner{UID: -1, GID: -1}
+	uid, err := lookupID(userPart, func(name string) (string, error) {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return u.Uid, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid owner %q", owner)
+	}
+	o.UID = uid
+	if groupPart != "" {
+		gid, err := lookupID(groupPart, func(name string) (string, error) {
+			g, err := user.LookupGroup(name)
+			if err != nil {
+				return "", err
+			}
+			return g.Gid, nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid owner %q", owner)
+		}
+		o.GID = gid
+	}
+	return o, nil
+}
+
+// lookupID returns nameOrID if it is numeric, otherwise the numeric ID
+// lookup returns for it
+func lookupID(nameOrID string, lookup func(string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(nameOrID); err == nil {
+		if id < 0 {
+			return 0, errors.Errorf("invalid ID %d", id)
+		}
+		return id, nil
+	}
+	id, err := lookup(nameOrID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, errors.Errorf("%q does not have a numeric ID", nameOrID)
+	}
+	return n, nil
+}
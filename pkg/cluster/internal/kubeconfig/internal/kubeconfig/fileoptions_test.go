@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParseOwner(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Owner       string
+		Expected    *Owner
+		ExpectError bool
+	}{
+		{
+			Name:     "uid",
+			Owner:    "1000",
+			Expected: &Owner{UID: 1000, GID: -1},
+		},
+		{
+			Name:     "uid and gid",
+			Owner:    "1000:100",
+			Expected: &Owner{UID: 1000, GID: 100},
+		},
+		{
+			Name:     "empty group",
+			Owner:    "1000:",
+			Expected: &Owner{UID: 1000, GID: -1},
+		},
+		{
+			Name:        "no user",
+			Owner:       ":100",
+			ExpectError: true,
+		},
+		{
+			Name:        "negative uid",
+			Owner:       "-1",
+			ExpectError: true,
+		},
+		{
+			Name:        "unknown user",
+			Owner:       "kind-no-such-user",
+			ExpectError: true,
+		},
+		{
+			Name:        "unknown group",
+			Owner:       "1000:kind-no-such-group",
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			owner, err := ParseOwner(tc.Owner)
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.DeepEqual(t, tc.Expected, owner)
+		})
+	}
+}
@@ -17,12 +17,62 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"bytes"
+	"os"
+	"text/template"
+
 	"sigs.k8s.io/kind/pkg/errors"
 )
 
-// KINDClusterKey identifies kind clusters in kubeconfig files
-func KINDClusterKey(clusterName string) string {
-	return "kind-" + clusterName
+// ContextTemplateEnv is the environment variable that overrides the names
+// of the kind cluster entries in kubeconfig files, see KINDClusterKey
+const ContextTemplateEnv = "KIND_KUBECONFIG_CONTEXT_TEMPLATE"
+
+// DefaultContextTemplate is the default template for KINDClusterKey
+const DefaultContextTemplate = "kind-{{.Name}}"
+
+// KINDClusterKey identifies kind clusters in kubeconfig files, it is used
+// for the cluster, user and context entries of the cluster.
+// The key is contextTemplate, a text/template executed with the cluster's
+// .Name, unless KIND_KUBECONFIG_CONTEXT_TEMPLATE is set and overrides it.
+// If neither is set the key is "kind-<clusterName>"
+func KINDClusterKey(clusterName, contextTemplate string) (string, error) {
+	tmpl, source := resolveContextTemplate(contextTemplate, os.Getenv)
+	key, err := executeContextTemplate(tmpl, clusterName)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid %s", source)
+	}
+	return key, nil
+}
+
+// resolveContextTemplate returns the kubeconfig entry name template to use
+// given the configured contextTemplate, and where it came from
+func resolveContextTemplate(contextTemplate string, getEnv func(string) string) (tmpl, source string) {
+	if tmpl := getEnv(ContextTemplateEnv); tmpl != "" {
+		return tmpl, ContextTemplateEnv
+	}
+	if contextTemplate != "" {
+		return contextTemplate, "kubeconfig context template"
+	}
+	return DefaultContextTemplate, "default kubeconfig context template"
+}
+
+// executeContextTemplate executes the kubeconfig entry name template tmpl
+// for the kind cluster clusterName
+func executeContextTemplate(tmpl, clusterName string) (string, error) {
+	t, err := template.New("context").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, struct{ Name string }{Name: clusterName}); err != nil {
+		return "", errors.WithStack(err)
+	}
+	key := b.String()
+	if key == "" {
+		return "", errors.Errorf("template %q produced an empty name", tmpl)
+	}
+	return key, nil
 }
 
 // checkKubeadmExpectations validates that a kubeadm created KUBECONFIG meets
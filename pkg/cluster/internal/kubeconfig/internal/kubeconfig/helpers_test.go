@@ -24,7 +24,98 @@ import (
 
 func TestKINDClusterKey(t *testing.T) {
 	t.Parallel()
-	assert.StringEqual(t, "kind-foobar", KINDClusterKey("foobar"))
+	key, err := KINDClusterKey("foobar", "")
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "kind-foobar", key)
+}
+
+func TestResolveContextTemplate(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name            string
+		ContextTemplate string
+		Env             string
+		ExpectTemplate  string
+	}{
+		{
+			Name:           "default",
+			ExpectTemplate: DefaultContextTemplate,
+		},
+		{
+			Name:            "configured",
+			ContextTemplate: "dev-{{.Name}}",
+			ExpectTemplate:  "dev-{{.Name}}",
+		},
+		{
+			Name:            "environment overrides configured",
+			ContextTemplate: "dev-{{.Name}}",
+			Env:             "ci-{{.Name}}",
+			ExpectTemplate:  "ci-{{.Name}}",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			tmpl, _ := resolveContextTemplate(tc.ContextTemplate, func(name string) string {
+				if name == ContextTemplateEnv {
+					return tc.Env
+				}
+				return ""
+			})
+			assert.StringEqual(t, tc.ExpectTemplate, tmpl)
+		})
+	}
+}
+
+func TestExecuteContextTemplate(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Template    string
+		ExpectKey   string
+		ExpectError bool
+	}{
+		{
+			Name:      "default",
+			Template:  DefaultContextTemplate,
+			ExpectKey: "kind-foobar",
+		},
+		{
+			Name:      "custom prefix and suffix",
+			Template:  "dev-{{.Name}}-local",
+			ExpectKey: "dev-foobar-local",
+		},
+		{
+			Name:      "template functions",
+			Template:  `{{printf "%s@kind" .Name}}`,
+			ExpectKey: "foobar@kind",
+		},
+		{
+			Name:        "invalid syntax",
+			Template:    "kind-{{.Name",
+			ExpectError: true,
+		},
+		{
+			Name:        "unknown field",
+			Template:    "{{.Cluster}}",
+			ExpectError: true,
+		},
+		{
+			Name:        "empty name",
+			Template:    `{{if false}}{{.Name}}{{end}}`,
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			key, err := executeContextTemplate(tc.Template, "foobar")
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.StringEqual(t, tc.ExpectKey, key)
+		})
+	}
 }
 
 func TestCheckKubeadmExpectations(t *testing.T) {
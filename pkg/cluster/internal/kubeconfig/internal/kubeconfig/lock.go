@@ -19,10 +19,21 @@ package kubeconfig
 import (
 	"os"
 	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
 )
 
+// lockTimeout is how long lockFile waits for a lock held by another kind or
+// kubectl invocation to be released
+const lockTimeout = 10 * time.Second
+
+// lockRetryInterval is how often lockFile retries a held lock
+const lockRetryInterval = 50 * time.Millisecond
+
 // these are from
 // https://github.com/kubernetes/client-go/blob/611184f7c43ae2d520727f01d49620c7ed33412d/tools/clientcmd/loader.go#L439-L440
+// except that lockFile waits up to lockTimeout for a held lock
 
 func lockFile(filename string) error {
 	// Make sure the dir exists before we try to create a lock file.
@@ -32,12 +43,24 @@ func lockFile(filename string) error {
 			return err
 		}
 	}
-	f, err := os.OpenFile(lockName(filename), os.O_CREATE|os.O_EXCL, 0)
-	if err != nil {
-		return err
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockName(filename), os.O_CREATE|os.O_EXCL, 0)
+		if err == nil {
+			f.Close()
+			return nil
+		}
+		if !os.IsExist(err) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf(
+				"timed out waiting for %s held by another kind or kubectl invocation, remove it if none is running",
+				lockName(filename),
+			)
+		}
+		time.Sleep(lockRetryInterval)
 	}
-	f.Close()
-	return nil
 }
 
 func unlockFile(filename string) error {
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockFileWaits(t *testing.T) {
+	t.Parallel()
+	dir, err := os.MkdirTemp("", "kind-testlock")
+	if err != nil {
+		t.Fatalf("Failed to create tempdir: %d", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// another invocation holds the lock for a while
+	path := filepath.Join(dir, "config")
+	if err := lockFile(path); err != nil {
+		t.Fatalf("Failed to lock: %v", err)
+	}
+	const held = 200 * time.Millisecond
+	go func() {
+		time.Sleep(held)
+		_ = unlockFile(path)
+	}()
+
+	// so locking again waits for it to be released
+	start := time.Now()
+	if err := lockFile(path); err != nil {
+		t.Fatalf("Failed to lock after the lock was released: %v", err)
+	}
+	if waited := time.Since(start); waited < held {
+		t.Errorf("Expected to wait for the lock for at least %v but waited %v", held, waited)
+	}
+	if err := unlockFile(path); err != nil {
+		t.Errorf("Failed to unlock: %v", err)
+	}
+}
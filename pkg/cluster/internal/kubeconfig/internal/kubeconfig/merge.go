@@ -24,8 +24,8 @@ import (
 
 // WriteMerged writes a kind kubeconfig (see KINDFromRawKubeadm) into configPath
 // merging with the existing contents if any and setting the current context to
-// the kind config's current context.
-func WriteMerged(kindConfig *Config, explicitConfigPath string) error {
+// the kind config's current context. opts set the mode and owner of the file.
+func WriteMerged(kindConfig *Config, explicitConfigPath string, opts FileOptions) error {
 	// figure out what filepath we should use
 	configPath := pathForMerge(explicitConfigPath, os.Getenv)
 
@@ -49,7 +49,7 @@ func WriteMerged(kindConfig *Config, explicitConfigPath string) error {
 	}
 
 	// write back out
-	return write(existing, configPath)
+	return write(existing, configPath, opts)
 }
 
 // merge kind config into an existing config
@@ -87,7 +87,10 @@ func merge(existing, kind *Config) error {
 	shouldAppend = true
 	for i := range existing.Contexts {
 		if existing.Contexts[i].Name == kind.Contexts[0].Name {
+			// keep the context to restore when this one is unset
+			previous := existing.Contexts[i].Context.previousContext()
 			existing.Contexts[i] = kind.Contexts[0]
+			existing.Contexts[i].Context.setPreviousContext(previous)
 			shouldAppend = false
 		}
 	}
@@ -95,8 +98,8 @@ func merge(existing, kind *Config) error {
 		existing.Contexts = append(existing.Contexts, kind.Contexts[0])
 	}
 
-	// set the current context
-	existing.CurrentContext = kind.CurrentContext
+	// set the current context, remembering the previous one
+	setCurrentContext(existing, kind.CurrentContext)
 
 	// TODO: We should not need this, but it allows broken clients that depend
 	// on apiVersion and kind to work. Notably the upstream javascript client.
@@ -17,6 +17,7 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -230,6 +231,52 @@ func TestWriteMerged(t *testing.T) {
 	t.Run("normal merge", testWriteMergedNormal)
 	t.Run("bad kind config", testWriteMergedBogusConfig)
 	t.Run("merge into non-existent file", testWriteMergedNoExistingFile)
+	t.Run("concurrent merges", testWriteMergedConcurrent)
+}
+
+func testWriteMergedConcurrent(t *testing.T) {
+	t.Parallel()
+	dir, err := os.MkdirTemp("", "kind-testwritemerged")
+	if err != nil {
+		t.Fatalf("Failed to create tempdir: %d", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// merge many clusters into the same file at once, as parallel
+	// kind create cluster invocations do
+	const writers = 20
+	configPath := filepath.Join(dir, "config")
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func(name string) {
+			errs <- WriteMerged(&Config{
+				Clusters:       []NamedCluster{{Name: name}},
+				Contexts:       []NamedContext{{Name: name, Context: Context{User: name, Cluster: name}}},
+				Users:          []NamedUser{{Name: name}},
+				CurrentContext: name,
+			}, configPath, FileOptions{})
+		}(fmt.Sprintf("kind-%d", i))
+	}
+	for i := 0; i < writers; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Failed to write merged kubeconfig: %v", err)
+		}
+	}
+
+	// every writer's entries must have been kept
+	cfg, err := read(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read merged kubeconfig: %v", err)
+	}
+	if len(cfg.Clusters) != writers || len(cfg.Contexts) != writers || len(cfg.Users) != writers {
+		t.Errorf(
+			"Expected %d clusters, contexts and users but got %d, %d and %d",
+			writers, len(cfg.Clusters), len(cfg.Contexts), len(cfg.Users),
+		)
+	}
+	if _, err := os.Stat(lockName(configPath)); !os.IsNotExist(err) {
+		t.Errorf("Expected the lock to be released but got: %v", err)
+	}
 }
 
 func testWriteMergedNormal(t *testing.T) {
@@ -304,7 +351,7 @@ users:
 		},
 	}
 	// ensure that we can write this merged config
-	if err := WriteMerged(kindConfig, existingConfigPath); err != nil {
+	if err := WriteMerged(kindConfig, existingConfigPath, FileOptions{}); err != nil {
 		t.Fatalf("Failed to write merged kubeconfig: %v", err)
 	}
 
@@ -334,6 +381,10 @@ contexts:
   name: kind-foo
 - context:
     cluster: kind-kind
+    extensions:
+    - extension:
+        context: kind-foo
+      name: kind.x-k8s.io/previous-context
     user: kind-kind
   name: kind-kind
 current-context: kind-kind
@@ -360,7 +411,7 @@ func testWriteMergedBogusConfig(t *testing.T) {
 	}
 	defer os.RemoveAll(dir)
 
-	err = WriteMerged(&Config{}, filepath.Join(dir, "bogus"))
+	err = WriteMerged(&Config{}, filepath.Join(dir, "bogus"), FileOptions{})
 	assert.ExpectError(t, true, err)
 }
 
@@ -411,7 +462,7 @@ func testWriteMergedNoExistingFile(t *testing.T) {
 	}
 
 	nonExistentPath := filepath.Join(dir, "bogus", "extra-bogus")
-	err = WriteMerged(kindConfig, nonExistentPath)
+	err = WriteMerged(kindConfig, nonExistentPath, FileOptions{})
 	assert.ExpectError(t, false, err)
 
 	// ensure the output matches expected
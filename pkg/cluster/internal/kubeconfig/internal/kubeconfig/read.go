@@ -26,9 +26,9 @@ import (
 )
 
 // KINDFromRawKubeadm returns a kind kubeconfig derived from the raw kubeadm kubeconfig,
-// the kind clusterName, and the server.
+// the kind clusterName, its contextTemplate (see KINDClusterKey), and the server.
 // server is ignored if unset.
-func KINDFromRawKubeadm(rawKubeadmKubeConfig, clusterName, server string) (*Config, error) {
+func KINDFromRawKubeadm(rawKubeadmKubeConfig, clusterName, contextTemplate, server string) (*Config, error) {
 	cfg := &Config{}
 	if err := yaml.Unmarshal([]byte(rawKubeadmKubeConfig), cfg); err != nil {
 		return nil, err
@@ -40,7 +40,10 @@ func KINDFromRawKubeadm(rawKubeadmKubeConfig, clusterName, server string) (*Conf
 	}
 
 	// compute unique kubeconfig key for this cluster
-	key := KINDClusterKey(clusterName)
+	key, err := KINDClusterKey(clusterName, contextTemplate)
+	if err != nil {
+		return nil, err
+	}
 
 	// use the unique key for all named references
 	cfg.Clusters[0].Name = key
@@ -67,6 +70,7 @@ func read(configPath string) (*Config, error) {
 	} else if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	defer f.Close()
 
 	// otherwise read in and deserialize
 	cfg := &Config{}
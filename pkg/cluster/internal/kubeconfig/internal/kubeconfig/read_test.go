@@ -28,7 +28,7 @@ func TestKINDFromRawKubeadm(t *testing.T) {
 	// test that a bogus config is caught
 	t.Run("bad config", func(t *testing.T) {
 		t.Parallel()
-		_, err := KINDFromRawKubeadm("	", "kind", "")
+		_, err := KINDFromRawKubeadm("	", "kind", "", "")
 		assert.ExpectError(t, true, err)
 	})
 	// test reading a legitimate kubeadm config and converting it to a kind config
@@ -91,7 +91,7 @@ users:
 				"preferences": map[string]interface{}{},
 			},
 		}
-		cfg, err := KINDFromRawKubeadm(rawConfig, "kind", server)
+		cfg, err := KINDFromRawKubeadm(rawConfig, "kind", "", server)
 		if err != nil {
 			t.Fatalf("failed to decode kubeconfig: %v", err)
 		}
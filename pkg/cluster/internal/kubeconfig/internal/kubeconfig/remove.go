@@ -23,8 +23,14 @@ import (
 )
 
 // RemoveKIND removes the kind cluster kindClusterName from the KUBECONFIG
-// files at configPaths
-func RemoveKIND(kindClusterName string, explicitPath string) error {
+// files at configPaths, given its contextTemplate (see KINDClusterKey)
+func RemoveKIND(kindClusterName, contextTemplate, explicitPath string) error {
+	// get kind cluster identifier
+	key, err := KINDClusterKey(kindClusterName, contextTemplate)
+	if err != nil {
+		return err
+	}
+
 	// remove kind from each if present
 	for _, configPath := range paths(explicitPath, os.Getenv) {
 		if err := func(configPath string) error {
@@ -43,9 +49,9 @@ func RemoveKIND(kindClusterName string, explicitPath string) error {
 			}
 
 			// remove the kind cluster from the config
-			if remove(existing, kindClusterName) {
+			if remove(existing, key) {
 				// write out the updated config if we modified anything
-				if err := write(existing, configPath); err != nil {
+				if err := write(existing, configPath, FileOptions{}); err != nil {
 					return err
 				}
 			}
@@ -58,12 +64,15 @@ func RemoveKIND(kindClusterName string, explicitPath string) error {
 	return nil
 }
 
-// remove drops kindClusterName entries from the cfg
-func remove(cfg *Config, kindClusterName string) bool {
+// remove drops the entries for the kind cluster identified by key from the cfg
+func remove(cfg *Config, key string) bool {
 	mutated := false
 
-	// get kind cluster identifier
-	key := KINDClusterKey(kindClusterName)
+	// restore the previous current context if it points to this cluster,
+	// this must happen before the context entry is removed
+	if restorePreviousContext(cfg, key) {
+		mutated = true
+	}
 
 	// filter out kind cluster from clusters
 	kept := 0
@@ -101,11 +110,5 @@ func remove(cfg *Config, kindClusterName string) bool {
 	}
 	cfg.Contexts = cfg.Contexts[:kept]
 
-	// unset current context if it points to this cluster
-	if cfg.CurrentContext == key {
-		cfg.CurrentContext = ""
-		mutated = true
-	}
-
 	return mutated
 }
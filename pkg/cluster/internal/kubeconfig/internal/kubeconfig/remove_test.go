@@ -124,12 +124,78 @@ func TestRemove(t *testing.T) {
 			},
 			ExpectModified: true,
 		},
+		{
+			Name: "remove kind, restore previous context",
+			Existing: &Config{
+				Clusters: []NamedCluster{
+					{
+						Name: "kops-blah",
+					},
+					{
+						Name: "kind-kind",
+					},
+				},
+				Users: []NamedUser{
+					{
+						Name: "kops-blah",
+					},
+					{
+						Name: "kind-kind",
+					},
+				},
+				Contexts: []NamedContext{
+					{
+						Name: "kops-blah",
+					},
+					{
+						Name: "kind-kind",
+						Context: Context{
+							OtherFields: map[string]interface{}{
+								"extensions": []interface{}{
+									map[string]interface{}{
+										"name": previousContextExtension,
+										"extension": map[string]interface{}{
+											"context": "kops-blah",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				CurrentContext: "kind-kind",
+			},
+			ClusterName: "kind",
+			Expected: &Config{
+				Clusters: []NamedCluster{
+					{
+						Name: "kops-blah",
+					},
+				},
+				Users: []NamedUser{
+					{
+						Name: "kops-blah",
+					},
+				},
+				Contexts: []NamedContext{
+					{
+						Name: "kops-blah",
+					},
+				},
+				CurrentContext: "kops-blah",
+			},
+			ExpectModified: true,
+		},
 	}
 	for _, tc := range cases {
 		tc := tc
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
-			modified := remove(tc.Existing, tc.ClusterName)
+			key, err := KINDClusterKey(tc.ClusterName, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			modified := remove(tc.Existing, key)
 			if modified != tc.ExpectModified {
 				if tc.ExpectModified {
 					t.Errorf("Expected config to be modified but got modified == false")
@@ -183,7 +249,7 @@ users:
 	}
 
 	// ensure that we can write this merged config
-	if err := RemoveKIND("foo", existingConfigPath); err != nil {
+	if err := RemoveKIND("foo", "", existingConfigPath); err != nil {
 		t.Fatalf("Failed to remove kind from kubeconfig: %v", err)
 	}
 
@@ -251,7 +317,7 @@ users:
 	}
 
 	// ensure that we can write this merged config
-	if err := RemoveKIND("foo", existingConfigPath); err != nil {
+	if err := RemoveKIND("foo", "", existingConfigPath); err != nil {
 		t.Fatalf("Failed to remove kind from kubeconfig: %v", err)
 	}
 
@@ -17,19 +17,32 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"bytes"
+	stderrors "errors"
 	"os"
 	"path/filepath"
+	"syscall"
 
 	"sigs.k8s.io/kind/pkg/errors"
 )
 
 // write writes cfg to configPath
 // it will ensure the directories in the path if necessary
-func write(cfg *Config, configPath string) error {
+//
+// The file is replaced atomically with a temporary file in the same
+// directory, so readers never see a partially written KUBECONFIG. Existing
+// files keep their mode and, where permitted, owner unless opts sets them.
+// A KUBECONFIG that cannot be replaced, such as a bind mounted file in a
+// container, is instead overwritten in place under the caller's lock.
+func write(cfg *Config, configPath string, opts FileOptions) error {
 	encoded, err := Encode(cfg)
 	if err != nil {
 		return err
 	}
+	// replace the target of a symlinked KUBECONFIG rather than the symlink
+	if resolved, err := filepath.EvalSymlinks(configPath); err == nil {
+		configPath = resolved
+	}
 	// NOTE: 0755 / 0600 are to match client-go
 	dir := filepath.Dir(configPath)
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -37,8 +50,100 @@ func write(cfg *Config, configPath string) error {
 			return errors.Wrap(err, "failed to create directory for KUBECONFIG")
 		}
 	}
-	if err := os.WriteFile(configPath, encoded, 0600); err != nil {
+	mode := os.FileMode(0600)
+	var existingOwner *Owner
+	if info, err := os.Stat(configPath); err == nil {
+		mode = info.Mode().Perm()
+		existingOwner, _ = fileOwner(info)
+	}
+	if opts.Mode != 0 {
+		mode = opts.Mode
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(configPath)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to write KUBECONFIG")
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+	_, err = f.Write(encoded)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
 		return errors.Wrap(err, "failed to write KUBECONFIG")
 	}
+	if err := validateWritten(tmpPath, encoded); err != nil {
+		return errors.Wrap(err, "failed to validate written KUBECONFIG")
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return errors.Wrap(err, "failed to set KUBECONFIG mode")
+	}
+	if opts.Owner != nil {
+		if err := os.Chown(tmpPath, opts.Owner.UID, opts.Owner.GID); err != nil {
+			return errors.Wrap(err, "failed to set KUBECONFIG owner")
+		}
+	} else if existingOwner != nil {
+		// only root may give files away, so this is best effort
+		_ = os.Chown(tmpPath, existingOwner.UID, existingOwner.GID)
+	}
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		if !isRenameUnsupported(err) {
+			return errors.Wrap(err, "failed to write KUBECONFIG")
+		}
+		if err := writeInPlace(configPath, encoded, mode, opts.Owner); err != nil {
+			return errors.Wrap(err, "failed to write KUBECONFIG")
+		}
+	}
+	return nil
+}
+
+// isRenameUnsupported returns true if err is from renaming onto a file that
+// cannot be replaced: a mount point (EBUSY) or a different filesystem (EXDEV)
+func isRenameUnsupported(err error) bool {
+	return stderrors.Is(err, syscall.EBUSY) || stderrors.Is(err, syscall.EXDEV)
+}
+
+// writeInPlace truncates and rewrites the file at path, this is not atomic
+// so readers may see a partial write, but works where the file is a mount
+func writeInPlace(path string, encoded []byte, mode os.FileMode, owner *Owner) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = f.Write(encoded)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := validateWritten(path, encoded); err != nil {
+		return err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		return errors.Wrap(err, "failed to set KUBECONFIG mode")
+	}
+	if owner != nil {
+		if err := os.Chown(path, owner.UID, owner.GID); err != nil {
+			return errors.Wrap(err, "failed to set KUBECONFIG owner")
+		}
+	}
+	return nil
+}
+
+// validateWritten checks that the file at path contains the expected
+// encoded KUBECONFIG and parses, before it replaces the existing KUBECONFIG
+func validateWritten(path string, expected []byte) error {
+	written, err := os.ReadFile(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !bytes.Equal(written, expected) {
+		return errors.Errorf("wrote %d bytes but read back %d different bytes", len(expected), len(written))
+	}
+	if _, err := read(path); err != nil {
+		return err
+	}
 	return nil
 }
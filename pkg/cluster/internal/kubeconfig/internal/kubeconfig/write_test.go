@@ -20,6 +20,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"syscall"
 	"testing"
 
 	"sigs.k8s.io/kind/pkg/internal/assert"
@@ -28,6 +30,136 @@ import (
 func TestWrite(t *testing.T) {
 	t.Parallel()
 	t.Run("non-existent file", testWriteNoExistingFile)
+	t.Run("file mode", testWriteFileMode)
+	t.Run("file owner", testWriteFileOwner)
+	t.Run("symlink", testWriteSymlink)
+}
+
+func testWriteFileMode(t *testing.T) {
+	t.Parallel()
+	dir, err := os.MkdirTemp("", "kind-testwrite")
+	if err != nil {
+		t.Fatalf("Failed to create tempdir: %d", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// new files default to 0600
+	path := filepath.Join(dir, "config")
+	if err := write(&Config{}, path, FileOptions{}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	expectMode(t, path, 0600)
+
+	// an explicit mode is applied
+	if err := write(&Config{}, path, FileOptions{Mode: 0640}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	expectMode(t, path, 0640)
+
+	// existing files keep their mode
+	if err := write(&Config{}, path, FileOptions{}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	expectMode(t, path, 0640)
+}
+
+func testWriteFileOwner(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("file owners are not supported on windows")
+	}
+	dir, err := os.MkdirTemp("", "kind-testwrite")
+	if err != nil {
+		t.Fatalf("Failed to create tempdir: %d", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// anyone may give files to themselves
+	path := filepath.Join(dir, "config")
+	owner := &Owner{UID: os.Getuid(), GID: os.Getgid()}
+	if err := write(&Config{}, path, FileOptions{Owner: owner}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat: %v", err)
+	}
+	actual, ok := fileOwner(info)
+	if !ok {
+		t.Fatalf("Failed to get the file owner")
+	}
+	assert.DeepEqual(t, owner, actual)
+}
+
+func testWriteSymlink(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on windows")
+	}
+	dir, err := os.MkdirTemp("", "kind-testwrite")
+	if err != nil {
+		t.Fatalf("Failed to create tempdir: %d", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the target of a symlink is replaced, not the symlink
+	target := filepath.Join(dir, "target")
+	link := filepath.Join(dir, "link")
+	if err := os.WriteFile(target, nil, 0600); err != nil {
+		t.Fatalf("Failed to create target: %v", err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	if err := write(&Config{CurrentContext: "kind-kind"}, link, FileOptions{}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Expected %s to still be a symlink: %v", link, err)
+	}
+	contents, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	assert.StringEqual(t, "current-context: kind-kind\n", string(contents))
+}
+
+func TestValidateWritten(t *testing.T) {
+	t.Parallel()
+	dir, err := os.MkdirTemp("", "kind-testwrite")
+	if err != nil {
+		t.Fatalf("Failed to create tempdir: %d", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	valid := []byte("current-context: kind-kind\n")
+	if err := os.WriteFile(path, valid, 0600); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	assert.ExpectError(t, false, validateWritten(path, valid))
+	// a truncated write
+	assert.ExpectError(t, true, validateWritten(path, append(valid, "users: []\n"...)))
+
+	invalid := []byte("current-context: [\n")
+	if err := os.WriteFile(path, invalid, 0600); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	assert.ExpectError(t, true, validateWritten(path, invalid))
+}
+
+func expectMode(t *testing.T, path string, mode os.FileMode) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat: %v", err)
+	}
+	if info.Mode().Perm() != mode {
+		t.Errorf("Expected mode %v but got %v", mode, info.Mode().Perm())
+	}
 }
 
 func testWriteNoExistingFile(t *testing.T) {
@@ -77,7 +209,7 @@ func testWriteNoExistingFile(t *testing.T) {
 	}
 
 	nonExistentPath := filepath.Join(dir, "bogus", "extra-bogus")
-	err = write(kindConfig, nonExistentPath)
+	err = write(kindConfig, nonExistentPath, FileOptions{})
 	assert.ExpectError(t, false, err)
 
 	// ensure the output matches expected
@@ -111,3 +243,35 @@ users:
 `
 	assert.StringEqual(t, expected, string(contents))
 }
+
+func TestIsRenameUnsupported(t *testing.T) {
+	t.Parallel()
+	linkError := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: "config.tmp", New: "config", Err: err}
+	}
+	assert.BoolEqual(t, true, isRenameUnsupported(linkError(syscall.EBUSY)))
+	assert.BoolEqual(t, true, isRenameUnsupported(linkError(syscall.EXDEV)))
+	assert.BoolEqual(t, false, isRenameUnsupported(linkError(syscall.EACCES)))
+}
+
+func TestWriteInPlace(t *testing.T) {
+	t.Parallel()
+	dir, err := os.MkdirTemp("", "kind-testwrite")
+	if err != nil {
+		t.Fatalf("Failed to create tempdir: %d", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, []byte("current-context: kind-previous-and-longer\n"), 0644); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	encoded := []byte("current-context: kind-kind\n")
+	assert.ExpectError(t, false, writeInPlace(path, encoded, 0600, nil))
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	assert.StringEqual(t, string(encoded), string(contents))
+	expectMode(t, path, 0600)
+}
@@ -30,28 +30,53 @@ import (
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
 )
 
+// FileOptions control the mode and owner of the kubeconfig files kind writes
+type FileOptions = kubeconfig.FileOptions
+
+// ParseOwner parses a kubeconfig file owner as "user[:group]", where user
+// and group are names or numeric IDs
+func ParseOwner(owner string) (*kubeconfig.Owner, error) {
+	return kubeconfig.ParseOwner(owner)
+}
+
 // Export exports the kubeconfig given the cluster context and a path to write it to
 // This will always be an external kubeconfig
-func Export(p providers.Provider, name, explicitPath string, external bool) error {
-	cfg, err := get(p, name, external)
+// contextTemplate names the cluster's entries, see ContextForCluster
+func Export(p providers.Provider, name, contextTemplate, explicitPath string, external bool, opts FileOptions) error {
+	cfg, err := get(p, name, contextTemplate, external)
 	if err != nil {
 		return err
 	}
-	return kubeconfig.WriteMerged(cfg, explicitPath)
+	return kubeconfig.WriteMerged(cfg, explicitPath, opts)
+}
+
+// Path returns the file Export writes the kubeconfig to, given explicitPath
+// or $KUBECONFIG or $HOME/.kube/config, following the rules set by kubectl
+func Path(explicitPath string) string {
+	return kubeconfig.PathForMerge(explicitPath)
 }
 
 // Remove removes clusterName from the kubeconfig paths detected based on
 // either explicitPath being set or $KUBECONFIG or $HOME/.kube/config, following
 // the rules set by kubectl
-// clusterName must identify a kind cluster.
-func Remove(clusterName, explicitPath string) error {
-	return kubeconfig.RemoveKIND(clusterName, explicitPath)
+// clusterName must identify a kind cluster, named with contextTemplate.
+func Remove(clusterName, contextTemplate, explicitPath string) error {
+	return kubeconfig.RemoveKIND(clusterName, contextTemplate, explicitPath)
+}
+
+// Unset restores the previous current context in the kubeconfig paths
+// detected based on either explicitPath being set or $KUBECONFIG or
+// $HOME/.kube/config, if the current context is the kind cluster clusterName
+// named with contextTemplate
+func Unset(clusterName, contextTemplate, explicitPath string) error {
+	return kubeconfig.UnsetKIND(clusterName, contextTemplate, explicitPath)
 }
 
 // Get returns the kubeconfig for the cluster
 // external controls if the internal IP address is used or the host endpoint
-func Get(p providers.Provider, name string, external bool) (string, error) {
-	cfg, err := get(p, name, external)
+// contextTemplate names the cluster's entries, see ContextForCluster
+func Get(p providers.Provider, name, contextTemplate string, external bool) (string, error) {
+	cfg, err := get(p, name, contextTemplate, external)
 	if err != nil {
 		return "", err
 	}
@@ -62,13 +87,29 @@ func Get(p providers.Provider, name string, external bool) (string, error) {
 	return string(b), err
 }
 
+// ClientConfig is the endpoint and credentials needed to connect to a cluster
+type ClientConfig = kubeconfig.ClientConfig
+
+// GetClient returns the ClientConfig for the cluster
+// external controls if the internal IP address is used or the host endpoint
+func GetClient(p providers.Provider, name string, external bool) (*ClientConfig, error) {
+	cfg, err := get(p, name, "", external)
+	if err != nil {
+		return nil, err
+	}
+	return kubeconfig.Client(cfg)
+}
+
 // ContextForCluster returns the context name for a kind cluster based on
-// its name. This key is used for all list entries of kind clusters
-func ContextForCluster(kindClusterName string) string {
-	return kubeconfig.KINDClusterKey(kindClusterName)
+// its name and contextTemplate, a text/template executed with the cluster's
+// .Name. This key is used for all list entries of kind clusters.
+// KIND_KUBECONFIG_CONTEXT_TEMPLATE overrides contextTemplate, and the key
+// defaults to "kind-<name>" if neither is set
+func ContextForCluster(kindClusterName, contextTemplate string) (string, error) {
+	return kubeconfig.KINDClusterKey(kindClusterName, contextTemplate)
 }
 
-func get(p providers.Provider, name string, external bool) (*kubeconfig.Config, error) {
+func get(p providers.Provider, name, contextTemplate string, external bool) (*kubeconfig.Config, error) {
 	// find a control plane node to get the kubeadm config from
 	n, err := p.ListNodes(name)
 	if err != nil {
@@ -101,5 +142,5 @@ func get(p providers.Provider, name string, external bool) (*kubeconfig.Config,
 	}
 
 	// actually encode
-	return kubeconfig.KINDFromRawKubeadm(buff.String(), name, server)
+	return kubeconfig.KINDFromRawKubeadm(buff.String(), name, contextTemplate, server)
 }
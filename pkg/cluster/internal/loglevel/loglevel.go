@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loglevel configures the logging of the components on a node with
+// systemd drop-ins
+package loglevel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// the drop-ins sort after those in the node image, so that their ExecStart
+// replaces the node image's
+const (
+	kubeletDropIn    = "/etc/systemd/system/kubelet.service.d/40-kind-log-level.conf"
+	containerdDropIn = "/etc/systemd/system/containerd.service.d/40-kind-log-level.conf"
+	journaldDropIn   = "/etc/systemd/journald.conf.d/40-kind-rate-limit.conf"
+)
+
+// Apply writes the drop-ins for the set fields of logging to node, and
+// restarts the components it changed that are running
+func Apply(node nodes.Node, logging config.NodeLogging) error {
+	files := map[string]string{}
+	var units []string
+	if logging.KubeletVerbosity != nil {
+		files[kubeletDropIn] = kubeletContent(*logging.KubeletVerbosity)
+		units = append(units, "kubelet")
+	}
+	if logging.ContainerdLogLevel != "" {
+		files[containerdDropIn] = containerdContent(logging.ContainerdLogLevel)
+		units = append(units, "containerd")
+	}
+	if logging.JournalRateLimitInterval != "" || logging.JournalRateLimitBurst != nil {
+		content, err := journaldContent(logging)
+		if err != nil {
+			return err
+		}
+		files[journaldDropIn] = content
+		units = append(units, "systemd-journald")
+	}
+	if len(units) == 0 {
+		return nil
+	}
+
+	for path, content := range files {
+		if err := nodeutils.WriteFile(node, path, content); err != nil {
+			return errors.Wrapf(err, "failed to write %s", path)
+		}
+	}
+	if err := node.Command("systemctl", "daemon-reload").Run(); err != nil {
+		return errors.Wrap(err, "failed to reload systemd")
+	}
+	// before kubeadm runs the kubelet is not running yet and picks up the
+	// drop-in when kubeadm starts it
+	if err := node.Command("systemctl", append([]string{"try-restart"}, units...)...).Run(); err != nil {
+		return errors.Wrapf(err, "failed to restart %s", strings.Join(units, ", "))
+	}
+	return nil
+}
+
+// kubeletContent returns the kubelet drop-in, the kubelet's flags come from
+// the node image's drop-in, which does not leave room for more
+func kubeletContent(verbosity int32) string {
+	return fmt.Sprintf(`[Service]
+ExecStart=
+ExecStart=/usr/bin/kubelet $KUBELET_KUBECONFIG_ARGS $KUBELET_CONFIG_ARGS $KUBELET_KUBEADM_ARGS $KUBELET_EXTRA_ARGS --v=%d
+`, verbosity)
+}
+
+func containerdContent(level string) string {
+	return fmt.Sprintf(`[Service]
+ExecStart=
+ExecStart=/usr/local/bin/containerd --log-level=%s
+`, level)
+}
+
+// journaldContent returns the journald drop-in for the set rate limit fields
+func journaldContent(logging config.NodeLogging) (string, error) {
+	var b strings.Builder
+	b.WriteString("[Journal]\n")
+	if logging.JournalRateLimitInterval != "" {
+		interval, err := time.ParseDuration(logging.JournalRateLimitInterval)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid journal rate limit interval %q", logging.JournalRateLimitInterval)
+		}
+		fmt.Fprintf(&b, "RateLimitIntervalSec=%dms\n", interval.Milliseconds())
+	}
+	if logging.JournalRateLimitBurst != nil {
+		fmt.Fprintf(&b, "RateLimitBurst=%d\n", *logging.JournalRateLimitBurst)
+	}
+	return b.String(), nil
+}
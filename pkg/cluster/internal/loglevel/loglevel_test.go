@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loglevel
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestJournaldContent(t *testing.T) {
+	t.Parallel()
+	burst := int32(0)
+	cases := []struct {
+		Name        string
+		Logging     config.NodeLogging
+		Expected    string
+		ExpectError bool
+	}{
+		{
+			Name:     "interval",
+			Logging:  config.NodeLogging{JournalRateLimitInterval: "1m30s"},
+			Expected: "[Journal]\nRateLimitIntervalSec=90000ms\n",
+		},
+		{
+			Name:     "disabled",
+			Logging:  config.NodeLogging{JournalRateLimitInterval: "0s", JournalRateLimitBurst: &burst},
+			Expected: "[Journal]\nRateLimitIntervalSec=0ms\nRateLimitBurst=0\n",
+		},
+		{
+			Name:        "invalid interval",
+			Logging:     config.NodeLogging{JournalRateLimitInterval: "soon"},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := journaldContent(tc.Logging)
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.StringEqual(t, tc.Expected, actual)
+		})
+	}
+}
+
+func TestKubeletContent(t *testing.T) {
+	t.Parallel()
+	assert.StringEqual(t, `[Service]
+ExecStart=
+ExecStart=/usr/bin/kubelet $KUBELET_KUBECONFIG_ARGS $KUBELET_CONFIG_ARGS $KUBELET_KUBEADM_ARGS $KUBELET_EXTRA_ARGS --v=4
+`, kubeletContent(4))
+}
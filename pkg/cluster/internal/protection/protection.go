@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package protection implements deletion protection for kind clusters
+package protection
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// markerPath is the file on each node marking the cluster as protected.
+// Container labels cannot be changed after creation, so a file is used to
+// allow protection to be toggled on existing clusters.
+const markerPath = "/kind/protected"
+
+// fileChecker is implemented by nodes that can check for a file without
+// exec'ing into the node, allowing stopped nodes to be checked
+type fileChecker interface {
+	HasFile(path string) (bool, error)
+}
+
+// Protect marks the cluster made up of allNodes as protected from deletion
+func Protect(allNodes []nodes.Node) error {
+	internalNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	fns := []func() error{}
+	for _, node := range internalNodes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			return errors.Wrapf(nodeutils.WriteFile(node, markerPath, ""), "failed to protect node %q", node)
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
+
+// Unprotect removes deletion protection from the cluster made up of allNodes
+func Unprotect(allNodes []nodes.Node) error {
+	internalNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	fns := []func() error{}
+	for _, node := range internalNodes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			return errors.Wrapf(node.Command("rm", "-f", markerPath).Run(), "failed to unprotect node %q", node)
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
+
+// IsProtected returns true if any node in allNodes is marked as protected.
+// Nodes are checked without exec where the provider supports it, so stopped
+// nodes can be checked too. Otherwise an error is returned if a node cannot
+// be inspected, as the cluster may then be protected.
+func IsProtected(allNodes []nodes.Node) (bool, error) {
+	internalNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return false, err
+	}
+	for _, node := range internalNodes {
+		if checker, ok := node.(fileChecker); ok {
+			protected, err := checker.HasFile(markerPath)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to check deletion protection on node %q", node)
+			}
+			if protected {
+				return true, nil
+			}
+			continue
+		}
+		// print the result rather than using the exit code of test, which
+		// could not be told apart from failing to exec in the node
+		lines, err := exec.OutputLines(node.Command(
+			"sh", "-c", `if [ -f "$1" ]; then echo protected; fi`, "sh", markerPath,
+		))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check deletion protection on node %q", node)
+		}
+		if len(lines) == 1 && lines[0] == "protected" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
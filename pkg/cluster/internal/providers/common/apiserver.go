@@ -0,0 +1,36 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliep.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// APIServerAdditionalPortMappings returns the host port mappings publishing
+// the API server on each of cfg.Networking.APIServerAdditionalAddresses,
+// these share cfg.Networking.APIServerPort with the primary address
+func APIServerAdditionalPortMappings(cfg *config.Cluster) []config.PortMapping {
+	mappings := make([]config.PortMapping, 0, len(cfg.Networking.APIServerAdditionalAddresses))
+	for _, address := range cfg.Networking.APIServerAdditionalAddresses {
+		mappings = append(mappings, config.PortMapping{
+			ListenAddress: address,
+			HostPort:      cfg.Networking.APIServerPort,
+			ContainerPort: APIServerInternalPort,
+		})
+	}
+	return mappings
+}
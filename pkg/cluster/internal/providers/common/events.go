@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+// StreamEvents runs `binaryName args...`, which must print one event per
+// line, and calls handle with each event parse recognizes until the command
+// exits or ctx is cancelled. parse returns false for lines that are not
+// interesting events. Cancelling ctx is not an error.
+func StreamEvents(ctx context.Context, binaryName string, args []string, parse func(line string) (providers.Event, bool, error), handle func(providers.Event)) error {
+	// stop the command if we return early on a parse error
+	cmdCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer pr.Close()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(cmdCtx, binaryName, args...)
+	cmd.SetStdout(pw)
+	cmd.SetStderr(&stderr)
+	cmdErrC := make(chan error, 1)
+	go func() {
+		defer pw.Close()
+		cmdErrC <- cmd.Run()
+	}()
+
+	sc := bufio.NewScanner(pr)
+	// events include all of the container's labels and may be long
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		event, ok, err := parse(sc.Text())
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse %s event", binaryName)
+		}
+		if ok {
+			handle(event)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return errors.Wrapf(err, "failed to read %s events", binaryName)
+	}
+
+	cmdErr := <-cmdErrC
+	if ctx.Err() != nil {
+		return nil
+	}
+	if cmdErr != nil {
+		return errors.Wrapf(cmdErr, "failed to stream %s events: %s", binaryName, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
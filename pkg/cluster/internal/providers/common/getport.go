@@ -17,7 +17,13 @@ limitations under the License.
 package common
 
 import (
+	"fmt"
+	"math/rand"
 	"net"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
 
 // PortOrGetFreePort is a helper that either returns the provided port
@@ -47,3 +53,70 @@ func GetFreePort(listenAddr string) (int32, func(), error) {
 	port := dummyListener.Addr().(*net.TCPAddr).Port
 	return int32(port), func() { dummyListener.Close() }, nil
 }
+
+// GetFreePortInRange is GetFreePort restricted to the inclusive port range
+// min-max, ports are tried in a random order so that concurrent callers are
+// unlikely to race for the same port
+func GetFreePortInRange(listenAddr string, min, max int32) (int32, func(), error) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for _, i := range r.Perm(int(max-min) + 1) {
+		port := min + int32(i)
+		dummyListener, err := net.Listen("tcp", net.JoinHostPort(listenAddr, fmt.Sprint(port)))
+		if err != nil {
+			continue
+		}
+		return port, func() { dummyListener.Close() }, nil
+	}
+	return 0, nil, errors.Errorf("no free port in range %d-%d on %s", min, max, listenAddr)
+}
+
+// APIServerHostPort returns the host port to publish the API server on,
+// picking a free port from cfg.Networking.APIServerPortRange if it is set,
+// otherwise cfg.Networking.APIServerPort for PortOrGetFreePort to handle.
+// The cleanup function is nil unless a port was picked.
+func APIServerHostPort(cfg *config.Cluster) (int32, func(), error) {
+	if cfg.Networking.APIServerPort != 0 || cfg.Networking.APIServerPortRange == "" {
+		return cfg.Networking.APIServerPort, nil, nil
+	}
+	min, max, err := config.ParsePortRange(cfg.Networking.APIServerPortRange)
+	if err != nil {
+		return 0, nil, err
+	}
+	port, release, err := GetFreePortInRange(cfg.Networking.APIServerAddress, min, max)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to get a free host port from apiServerPortRange")
+	}
+	return port, release, nil
+}
+
+// NodeWithAPIServerHostPort returns a copy of node with the host port of its
+// port mapping at index apiServerMapping, which publishes the API server,
+// set by APIServerHostPort. The cleanup function is nil unless a port was
+// picked.
+func NodeWithAPIServerHostPort(cfg *config.Cluster, node *config.Node, apiServerMapping int) (*config.Node, func(), error) {
+	port, release, err := APIServerHostPort(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	node = node.DeepCopy()
+	node.ExtraPortMappings[apiServerMapping].HostPort = port
+	return node, release, nil
+}
+
+// WithoutRandomHostPorts returns a copy of cfg where the host ports kind
+// would pick at random are left to the container runtime instead, so that
+// the nodes' run args can be computed without picking ports
+func WithoutRandomHostPorts(cfg *config.Cluster) *config.Cluster {
+	cfg = cfg.DeepCopy()
+	if cfg.Networking.APIServerPort == 0 {
+		cfg.Networking.APIServerPort = -1
+	}
+	for i := range cfg.Nodes {
+		for j := range cfg.Nodes[i].ExtraPortMappings {
+			if cfg.Nodes[i].ExtraPortMappings[j].HostPort == 0 {
+				cfg.Nodes[i].ExtraPortMappings[j].HostPort = -1
+			}
+		}
+	}
+	return cfg
+}
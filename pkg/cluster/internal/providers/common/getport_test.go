@@ -16,7 +16,12 @@ limitations under the License.
 
 package common
 
-import "testing"
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
 
 func TestPortOrGetFreePort(t *testing.T) {
 	t.Parallel()
@@ -101,3 +106,62 @@ func TestGetFreePort(t *testing.T) {
 		})
 	}
 }
+
+func TestWithoutRandomHostPorts(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Cluster{}
+	cfg.Nodes = []config.Node{{
+		Role: config.ControlPlaneRole,
+		ExtraPortMappings: []config.PortMapping{
+			{ContainerPort: 80, HostPort: 8080},
+			{ContainerPort: 443},
+		},
+	}}
+	got := WithoutRandomHostPorts(cfg)
+	assert.DeepEqual(t, int32(-1), got.Networking.APIServerPort)
+	assert.DeepEqual(t, []config.PortMapping{
+		{ContainerPort: 80, HostPort: 8080},
+		{ContainerPort: 443, HostPort: -1},
+	}, got.Nodes[0].ExtraPortMappings)
+	// the original config is not modified
+	assert.DeepEqual(t, int32(0), cfg.Networking.APIServerPort)
+	assert.DeepEqual(t, int32(0), cfg.Nodes[0].ExtraPortMappings[1].HostPort)
+}
+
+func TestGetFreePortInRange(t *testing.T) {
+	t.Parallel()
+	port, release, err := GetFreePortInRange("127.0.0.1", 1024, 65535)
+	assert.ExpectError(t, false, err)
+	if port < 1024 {
+		t.Errorf("GetFreePortInRange() = %v is not in the range", port)
+	}
+	// the port is held until released, so a range of just that port is full
+	_, _, err = GetFreePortInRange("127.0.0.1", port, port)
+	assert.ExpectError(t, true, err)
+	release()
+}
+
+func TestAPIServerHostPort(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Cluster{}
+	cfg.Networking.APIServerAddress = "127.0.0.1"
+
+	// without a range the configured port is left to PortOrGetFreePort
+	for _, configured := range []int32{-1, 0, 6443} {
+		cfg.Networking.APIServerPort = configured
+		port, release, err := APIServerHostPort(cfg)
+		assert.ExpectError(t, false, err)
+		assert.DeepEqual(t, configured, port)
+		assert.BoolEqual(t, true, release == nil)
+	}
+
+	cfg.Networking.APIServerPort = 0
+	cfg.Networking.APIServerPortRange = "30000-65535"
+	port, release, err := APIServerHostPort(cfg)
+	assert.ExpectError(t, false, err)
+	assert.BoolEqual(t, true, release != nil)
+	release()
+	if port < 30000 {
+		t.Errorf("APIServerHostPort() = %v is not in apiServerPortRange", port)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// HollowNodeLabel is the Kubernetes node label set on hollow nodes, to
+// select or avoid them when scheduling
+const HollowNodeLabel = "kind.x-k8s.io/hollow-node"
+
+// HollowNodeKubeconfigPath is where providers copy the kubeconfig into
+// the hollow node containers
+const HollowNodeKubeconfigPath = "/kubeconfig"
+
+// HollowNodeEntrypoint is the kubemark binary in the kubemark image
+const HollowNodeEntrypoint = "/kubemark"
+
+// hollowNodeBatchSize is how many hollow nodes are created at once, there
+// may be hundreds and the container runtime should not be overwhelmed
+const hollowNodeBatchSize = 10
+
+// HollowNodeArgs returns the kubemark arguments running a hollow kubelet
+// for the node name, these are the same for all providers
+func HollowNodeArgs(name string) []string {
+	return []string{
+		"--morph=kubelet",
+		"--name=" + name,
+		"--kubeconfig=" + HollowNodeKubeconfigPath,
+		"--node-labels=" + HollowNodeLabel + "=true",
+	}
+}
+
+// CreateHollowNodes calls create for each of names, concurrently in batches
+func CreateHollowNodes(names []string, create func(name string) error) error {
+	for start := 0; start < len(names); start += hollowNodeBatchSize {
+		end := start + hollowNodeBatchSize
+		if end > len(names) {
+			end = len(names)
+		}
+		fns := []func() error{}
+		for _, name := range names[start:end] {
+			name := name // capture name
+			fns = append(fns, func() error {
+				return create(name)
+			})
+		}
+		if err := errors.AggregateConcurrent(fns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
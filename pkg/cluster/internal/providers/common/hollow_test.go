@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestHollowNodeArgs(t *testing.T) {
+	t.Parallel()
+	assert.DeepEqual(t, []string{
+		"--morph=kubelet",
+		"--name=kind-hollow2",
+		"--kubeconfig=/kubeconfig",
+		"--node-labels=kind.x-k8s.io/hollow-node=true",
+	}, HollowNodeArgs("kind-hollow2"))
+}
+
+func TestCreateHollowNodes(t *testing.T) {
+	t.Parallel()
+	namer := MakeNodeNamer("kind")
+	names := []string{}
+	for i := 0; i < 25; i++ {
+		names = append(names, namer("hollow"))
+	}
+
+	var mu sync.Mutex
+	created := []string{}
+	err := CreateHollowNodes(names, func(name string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		created = append(created, name)
+		return nil
+	})
+	assert.ExpectError(t, false, err)
+	sort.Strings(created)
+	want := append([]string{}, names...)
+	sort.Strings(want)
+	assert.DeepEqual(t, want, created)
+}
+
+func TestCreateHollowNodesError(t *testing.T) {
+	t.Parallel()
+	names := []string{"kind-hollow", "kind-hollow2", "kind-hollow3"}
+	var mu sync.Mutex
+	created := 0
+	err := CreateHollowNodes(append(names, make([]string, hollowNodeBatchSize)...), func(name string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		created++
+		if name == "kind-hollow2" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	assert.ExpectError(t, true, err)
+	// the first batch is finished but later batches are not started
+	assert.DeepEqual(t, hollowNodeBatchSize, created)
+}
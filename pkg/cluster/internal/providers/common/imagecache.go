@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// ImageCacheMountPath is where a node's image cache volume is mounted,
+// containerd keeps its content and snapshots here
+const ImageCacheMountPath = "/var/lib/containerd"
+
+// ImageCacheLabelKey is applied to each image cache volume, the value is the
+// name of the cluster the volume belongs to
+const ImageCacheLabelKey = "io.x-k8s.kind.image-cache"
+
+// ImageCacheImageLabelKey is applied to each image cache volume, the value
+// is the node image the volume was populated from
+const ImageCacheImageLabelKey = "io.x-k8s.kind.image"
+
+// ImageCacheVolumeName returns the name of the image cache volume for the
+// node container named nodeName.
+// Node names are derived from the cluster name and the node's role, so the
+// same node of a recreated cluster gets the same volume.
+func ImageCacheVolumeName(nodeName string) string {
+	return nodeName + "-containerd"
+}
+
+// ParseImageCacheVolumes parses lines of "<volume name>\t<cluster name>"
+// as listed by the providers
+func ParseImageCacheVolumes(lines []string) ([]providers.Volume, error) {
+	volumes := make([]providers.Volume, 0, len(lines))
+	for _, line := range lines {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid output when listing image cache volumes: %q", line)
+		}
+		volumes = append(volumes, providers.Volume{
+			Name:    parts[0],
+			Cluster: parts[1],
+		})
+	}
+	return volumes, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestImageCacheVolumeName(t *testing.T) {
+	t.Parallel()
+	assert.StringEqual(t, "kind-worker2-containerd", ImageCacheVolumeName("kind-worker2"))
+}
+
+func TestParseImageCacheVolumes(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Lines       []string
+		Expected    []providers.Volume
+		ExpectError bool
+	}{
+		{
+			Name:     "no volumes",
+			Lines:    []string{},
+			Expected: []providers.Volume{},
+		},
+		{
+			Name: "volumes of two clusters",
+			Lines: []string{
+				"kind-control-plane-containerd\tkind",
+				"dev-worker-containerd\tdev",
+			},
+			Expected: []providers.Volume{
+				{Name: "kind-control-plane-containerd", Cluster: "kind"},
+				{Name: "dev-worker-containerd", Cluster: "dev"},
+			},
+		},
+		{
+			Name:        "missing cluster",
+			Lines:       []string{"kind-control-plane-containerd"},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			volumes, err := ParseImageCacheVolumes(tc.Lines)
+			assert.ExpectError(t, tc.ExpectError, err)
+			if err == nil {
+				assert.DeepEqual(t, tc.Expected, volumes)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package common
 
 import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
 	"sigs.k8s.io/kind/pkg/internal/sets"
 )
@@ -31,3 +34,56 @@ func RequiredNodeImages(cfg *config.Cluster) sets.String {
 	}
 	return images
 }
+
+// BundledImagesScript is a shell script that, when run inside a node image,
+// prints the images that are preloaded into that node image, one per line:
+// the kubeadm images for the image's Kubernetes version, the sandbox (pause)
+// image from the containerd config, and the images referenced by the
+// default CNI and storage manifests
+const BundledImagesScript = `kubeadm config images list --kubernetes-version "$(cat /kind/version)" 2>/dev/null | grep -v /pause:
+sed -n -E 's/^\s*sandbox_image\s*=\s*"([^"]+)"\s*$/\1/p' /etc/containerd/config.toml
+sed -n -E 's/^\s*image:\s*"?([^"]+)"?\s*$/\1/p' /kind/manifests/*.yaml
+`
+
+// ParseBundledImages returns the set of images from the output of running
+// BundledImagesScript within a node image
+func ParseBundledImages(lines []string) sets.String {
+	images := sets.NewString()
+	for _, line := range lines {
+		if image := strings.TrimSpace(line); image != "" {
+			images.Insert(image)
+		}
+	}
+	return images
+}
+
+// ImagePlatformError returns an error if image, built for imagePlatform,
+// does not match the container runtime host's hostPlatform. Both are
+// os/arch[/variant] platforms, e.g. "linux/arm64", variants are ignored.
+// Nodes from such an image fail to start with exec format errors, or run
+// too slowly under emulation to work.
+func ImagePlatformError(image, imagePlatform, hostPlatform string) error {
+	if osArch(imagePlatform) == osArch(hostPlatform) {
+		return nil
+	}
+	_, arch := splitOSArch(hostPlatform)
+	return errors.Errorf(
+		"node image %q is built for %s, but the container runtime runs on %s; "+
+			"use a node image built for %s, e.g. with \"kind build node-image --arch %s\"",
+		image, imagePlatform, hostPlatform, osArch(hostPlatform), arch,
+	)
+}
+
+// osArch returns the os/arch of an os/arch[/variant] platform
+func osArch(platform string) string {
+	osName, arch := splitOSArch(platform)
+	return osName + "/" + arch
+}
+
+func splitOSArch(platform string) (osName, arch string) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(platform)), "/")
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
@@ -66,3 +66,76 @@ func TestRequiredNodeImages(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBundledImages(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		lines []string
+		want  sets.String
+	}{
+		{
+			name:  "No output",
+			lines: nil,
+			want:  sets.NewString(),
+		},
+		{
+			name: "Duplicates and blank lines",
+			lines: []string{
+				"registry.k8s.io/kube-apiserver:v1.32.0",
+				"",
+				"  registry.k8s.io/pause:3.10  ",
+				"registry.k8s.io/pause:3.10",
+			},
+			want: sets.NewString(
+				"registry.k8s.io/kube-apiserver:v1.32.0",
+				"registry.k8s.io/pause:3.10",
+			),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ParseBundledImages(tt.lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBundledImages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImagePlatformError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		imagePlatform string
+		hostPlatform  string
+		wantErr       bool
+	}{
+		{
+			name:          "Matching",
+			imagePlatform: "linux/arm64",
+			hostPlatform:  "linux/arm64",
+		},
+		{
+			name:          "Variant is ignored",
+			imagePlatform: "linux/arm64/v8",
+			hostPlatform:  "linux/arm64",
+		},
+		{
+			name:          "Amd64 image on an arm64 host",
+			imagePlatform: "linux/amd64",
+			hostPlatform:  "linux/arm64",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := ImagePlatformError("kindest/node:test", tt.imagePlatform, tt.hostPlatform); (err != nil) != tt.wantErr {
+				t.Errorf("ImagePlatformError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
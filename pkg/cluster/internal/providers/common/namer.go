@@ -18,6 +18,8 @@ package common
 
 import (
 	"fmt"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
 
 // MakeNodeNamer returns a func(role string)(nodeName string)
@@ -35,3 +37,14 @@ func MakeNodeNamer(clusterName string) func(string) string {
 		return fmt.Sprintf("%s-%s%s", clusterName, role, suffix)
 	}
 }
+
+// NodeName returns the name of node from namer, which must be called for
+// every node of the config in order. Nodes added to an existing cluster are
+// named explicitly by their config instead
+func NodeName(namer func(string) string, node *config.Node) string {
+	name := namer(string(node.Role))
+	if node.Name != "" {
+		return node.Name
+	}
+	return name
+}
@@ -19,6 +19,7 @@ package common
 import (
 	"testing"
 
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 	"sigs.k8s.io/kind/pkg/internal/assert"
 )
 
@@ -62,3 +63,19 @@ func TestMakeNodeNamer(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeName(t *testing.T) {
+	t.Parallel()
+	nodes := []config.Node{
+		{Role: config.ControlPlaneRole},
+		{Role: config.WorkerRole},
+		{Role: config.WorkerRole, Name: "kind-small-1"},
+		{Role: config.WorkerRole},
+	}
+	namer := MakeNodeNamer("kind")
+	var names []string
+	for i := range nodes {
+		names = append(names, NodeName(namer, &nodes[i]))
+	}
+	assert.DeepEqual(t, []string{"kind-control-plane", "kind-worker", "kind-small-1", "kind-worker3"}, names)
+}
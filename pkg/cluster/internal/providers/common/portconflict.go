@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// hostPortConflictRetries bounds how often creating a container is retried
+// after another process bound one of its randomly picked host ports
+const hostPortConflictRetries = 4
+
+// hostPortConflictBackoff is the delay before the first such retry, each
+// further retry waits twice as long as the last
+var hostPortConflictBackoff = 250 * time.Millisecond
+
+// hostPortConflictRE matches the container runtimes' errors for a host port
+// that is already bound, e.g. docker's
+// "Bind for 127.0.0.1:40000 failed: port is already allocated" and podman's
+// "listen tcp 127.0.0.1:40000: bind: address already in use"
+var hostPortConflictRE = regexp.MustCompile(`:(\d+)(?: failed)?: (?:bind: )?(?:port is already allocated|address already in use)`)
+
+// HostPortConflict returns the host port if err is the container runtime
+// failing to publish it because it is already bound, which can happen when
+// another process binds a port after kind picked it but before the container
+// runtime did
+func HostPortConflict(err error) (int32, bool) {
+	rerr := exec.RunErrorForError(err)
+	if rerr == nil {
+		return 0, false
+	}
+	match := hostPortConflictRE.FindSubmatch(rerr.Output)
+	if match == nil {
+		return 0, false
+	}
+	port, err := strconv.ParseInt(string(match[1]), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(port), true
+}
+
+// FixedHostPorts returns the host ports of mappings that are set in the
+// config, rather than picked by kind or the container runtime
+func FixedHostPorts(mappings ...config.PortMapping) []int32 {
+	ports := []int32{}
+	for _, pm := range mappings {
+		if pm.HostPort > 0 {
+			ports = append(ports, pm.HostPort)
+		}
+	}
+	return ports
+}
+
+// RetryOnHostPortConflict calls create until it does not fail with a host
+// port conflict, up to hostPortConflictRetries more times with an
+// exponential backoff. Before each retry cleanup is called to remove the
+// container that failed to start. create must pick its random host ports
+// again on each call for retrying to help, so conflicts on fixedHostPorts
+// are not retried.
+func RetryOnHostPortConflict(fixedHostPorts []int32, create func() error, cleanup func() error) error {
+	retryable := func(err error) bool {
+		port, ok := HostPortConflict(err)
+		if !ok {
+			return false
+		}
+		for _, fixed := range fixedHostPorts {
+			if port == fixed {
+				return false
+			}
+		}
+		return true
+	}
+	backoff := hostPortConflictBackoff
+	err := create()
+	for i := 0; i < hostPortConflictRetries && retryable(err); i++ {
+		if cleanupErr := cleanup(); cleanupErr != nil {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		err = create()
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+// runError returns a container runtime error with output
+func runError(output string) error {
+	return &exec.RunError{
+		Command: []string{"docker", "run"},
+		Output:  []byte(output),
+		Inner:   errors.New("exit status 125"),
+	}
+}
+
+func TestHostPortConflict(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name         string
+		Err          error
+		ExpectedPort int32
+		ExpectedOK   bool
+	}{
+		{
+			Name:         "docker",
+			Err:          runError("docker: Error response from daemon: driver failed programming external connectivity on endpoint kind-control-plane: Bind for 127.0.0.1:40000 failed: port is already allocated."),
+			ExpectedPort: 40000,
+			ExpectedOK:   true,
+		},
+		{
+			Name:         "podman",
+			Err:          runError("Error: rootlessport listen tcp 127.0.0.1:40000: bind: address already in use"),
+			ExpectedPort: 40000,
+			ExpectedOK:   true,
+		},
+		{
+			Name: "other error",
+			Err:  runError("Error: no such image"),
+		},
+		{
+			Name: "not a run error",
+			Err:  errors.New("listen tcp 127.0.0.1:40000: bind: address already in use"),
+		},
+		{
+			Name: "nil",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			port, ok := HostPortConflict(tc.Err)
+			assert.BoolEqual(t, tc.ExpectedOK, ok)
+			assert.DeepEqual(t, tc.ExpectedPort, port)
+		})
+	}
+}
+
+func TestFixedHostPorts(t *testing.T) {
+	t.Parallel()
+	assert.DeepEqual(t, []int32{8080}, FixedHostPorts(
+		config.PortMapping{HostPort: 8080},
+		config.PortMapping{HostPort: 0},
+		config.PortMapping{HostPort: -1},
+	))
+}
+
+func TestRetryOnHostPortConflict(t *testing.T) {
+	hostPortConflictBackoff = time.Millisecond
+	conflict := runError("Bind for 127.0.0.1:40000 failed: port is already allocated")
+
+	// retried until create succeeds, cleaning up before each retry
+	creates, cleanups := 0, 0
+	err := RetryOnHostPortConflict([]int32{8080}, func() error {
+		creates++
+		if creates < 3 {
+			return conflict
+		}
+		return nil
+	}, func() error {
+		cleanups++
+		return nil
+	})
+	assert.ExpectError(t, false, err)
+	assert.DeepEqual(t, []int{3, 2}, []int{creates, cleanups})
+
+	// other errors are not retried
+	creates, cleanups = 0, 0
+	other := errors.New("boom")
+	err = RetryOnHostPortConflict(nil, func() error {
+		creates++
+		return other
+	}, func() error {
+		cleanups++
+		return nil
+	})
+	assert.BoolEqual(t, true, err == other)
+	assert.DeepEqual(t, []int{1, 0}, []int{creates, cleanups})
+
+	// conflicts on host ports set in the config cannot be fixed by retrying
+	creates, cleanups = 0, 0
+	err = RetryOnHostPortConflict([]int32{40000}, func() error {
+		creates++
+		return conflict
+	}, func() error {
+		cleanups++
+		return nil
+	})
+	assert.BoolEqual(t, true, err == conflict)
+	assert.DeepEqual(t, []int{1, 0}, []int{creates, cleanups})
+
+	// retries are bounded
+	creates = 0
+	err = RetryOnHostPortConflict(nil, func() error {
+		creates++
+		return conflict
+	}, func() error { return nil })
+	assert.ExpectError(t, true, err)
+	assert.DeepEqual(t, hostPortConflictRetries+1, creates)
+}
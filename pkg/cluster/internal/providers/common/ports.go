@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+// ParsePorts parses the output of `docker port <container>`, which podman
+// and nerdctl share, with one mapping per line such as
+// "6443/tcp -> 127.0.0.1:41234"
+func ParsePorts(lines []string) ([]providers.PortMapping, error) {
+	ports := []providers.PortMapping{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, " -> ")
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid port mapping %q", line)
+		}
+		protocol := "tcp"
+		containerPort := parts[0]
+		if i := strings.Index(containerPort, "/"); i >= 0 {
+			containerPort, protocol = containerPort[:i], containerPort[i+1:]
+		}
+		cPort, err := strconv.ParseInt(containerPort, 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid container port in port mapping %q", line)
+		}
+		hostIP, hostPort, err := net.SplitHostPort(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid host address in port mapping %q", line)
+		}
+		hPort, err := strconv.ParseInt(hostPort, 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid host port in port mapping %q", line)
+		}
+		ports = append(ports, providers.PortMapping{
+			ContainerPort: int32(cPort),
+			Protocol:      protocol,
+			HostIP:        hostIP,
+			HostPort:      int32(hPort),
+		})
+	}
+	return ports, nil
+}
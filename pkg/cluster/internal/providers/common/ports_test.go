@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParsePorts(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Lines       []string
+		Expected    []providers.PortMapping
+		ExpectError bool
+	}{
+		{
+			Name:     "no ports",
+			Lines:    []string{},
+			Expected: []providers.PortMapping{},
+		},
+		{
+			Name: "ipv4 and ipv6",
+			Lines: []string{
+				"6443/tcp -> 127.0.0.1:41234",
+				"80/tcp -> 0.0.0.0:8080",
+				"80/tcp -> [::]:8080",
+				"53/udp -> 0.0.0.0:5353",
+				"",
+			},
+			Expected: []providers.PortMapping{
+				{ContainerPort: 6443, Protocol: "tcp", HostIP: "127.0.0.1", HostPort: 41234},
+				{ContainerPort: 80, Protocol: "tcp", HostIP: "0.0.0.0", HostPort: 8080},
+				{ContainerPort: 80, Protocol: "tcp", HostIP: "::", HostPort: 8080},
+				{ContainerPort: 53, Protocol: "udp", HostIP: "0.0.0.0", HostPort: 5353},
+			},
+		},
+		{
+			Name:        "missing host address",
+			Lines:       []string{"6443/tcp"},
+			ExpectError: true,
+		},
+		{
+			Name:        "invalid host port",
+			Lines:       []string{"6443/tcp -> 127.0.0.1:http"},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			ports, err := ParsePorts(tc.Lines)
+			assert.ExpectError(t, tc.ExpectError, err)
+			if err == nil {
+				assert.DeepEqual(t, tc.Expected, ports)
+			}
+		})
+	}
+}
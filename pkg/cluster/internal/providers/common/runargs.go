@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// EnvAndSysctlArgs returns the container run args setting the node's Env
+// and Sysctls, these are the same for all providers
+func EnvAndSysctlArgs(node *config.Node) []string {
+	args := []string{}
+	for _, name := range sortedKeys(node.Env) {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", name, node.Env[name]))
+	}
+	// the entrypoint configures containerd with this snapshotter, this comes
+	// after the node's Env to take precedence
+	if node.ContainerdSnapshotter != "" {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", snapshotterEnv, node.ContainerdSnapshotter))
+	}
+	for _, name := range sortedKeys(node.Sysctls) {
+		args = append(args, fmt.Sprintf("--sysctl=%s=%s", name, node.Sysctls[name]))
+	}
+	return args
+}
+
+// snapshotterEnv is read by the node image entrypoint to select containerd's
+// snapshotter
+const snapshotterEnv = "KIND_EXPERIMENTAL_CONTAINERD_SNAPSHOTTER"
+
+// MemoryArgs returns the container run args limiting the node's memory and
+// swap, these are the same for all providers
+func MemoryArgs(node *config.Node) []string {
+	args := []string{}
+	if node.Memory != "" {
+		args = append(args, "--memory", node.Memory)
+	}
+	if node.MemorySwap != "" {
+		args = append(args, "--memory-swap", node.MemorySwap)
+	}
+	return args
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestEnvAndSysctlArgs(t *testing.T) {
+	t.Parallel()
+	node := &config.Node{
+		Env: map[string]string{
+			"ZED":     "z",
+			"GODEBUG": "http2debug=1",
+		},
+		Sysctls: map[string]string{
+			"net.core.somaxconn":           "1024",
+			"net.ipv4.ip_local_port_range": "1024 65000",
+		},
+		ContainerdSnapshotter: config.StargzSnapshotter,
+	}
+	assert.DeepEqual(t, []string{
+		"-e", "GODEBUG=http2debug=1",
+		"-e", "ZED=z",
+		"-e", "KIND_EXPERIMENTAL_CONTAINERD_SNAPSHOTTER=stargz",
+		"--sysctl=net.core.somaxconn=1024",
+		"--sysctl=net.ipv4.ip_local_port_range=1024 65000",
+	}, EnvAndSysctlArgs(node))
+	assert.DeepEqual(t, []string{}, EnvAndSysctlArgs(&config.Node{}))
+}
+
+func TestMemoryArgs(t *testing.T) {
+	t.Parallel()
+	node := &config.Node{
+		Memory:     "4g",
+		MemorySwap: "6g",
+	}
+	assert.DeepEqual(t, []string{
+		"--memory", "4g",
+		"--memory-swap", "6g",
+	}, MemoryArgs(node))
+	assert.DeepEqual(t, []string{}, MemoryArgs(&config.Node{}))
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliep.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// ImagePullOptions controls retrying and timing out node image pulls
+type ImagePullOptions struct {
+	// Retries is how many times a failed pull is retried
+	Retries int
+	// Backoff is the delay before the first retry, each further retry
+	// waits this much longer than the last
+	Backoff time.Duration
+	// Timeout limits each pull attempt, zero means no limit
+	Timeout time.Duration
+}
+
+// DefaultImagePullOptions are used when pulling images outside of cluster
+// creation, matching the config defaults
+var DefaultImagePullOptions = ImagePullOptions{
+	Retries: 4,
+	Backoff: time.Second,
+}
+
+// ImagePullOptionsForConfig returns the ImagePullOptions for the node
+// images of the (defaulted) cluster config
+func ImagePullOptionsForConfig(cfg *config.Cluster) ImagePullOptions {
+	opts := DefaultImagePullOptions
+	if cfg.Timeouts.ImagePullRetries != nil {
+		opts.Retries = int(*cfg.Timeouts.ImagePullRetries)
+	}
+	if cfg.Timeouts.ImagePullBackoff != "" {
+		opts.Backoff = config.TimeoutDuration(cfg.Timeouts.ImagePullBackoff)
+	}
+	opts.Timeout = config.TimeoutDuration(cfg.Timeouts.ImagePull)
+	return opts
+}
+
+// RunWithTimeout calls run with a context derived from ctx that is cancelled
+// after timeout, zero meaning no timeout, run should use it to create its
+// commands.
+// field is the name of the timeouts config field the timeout came from,
+// and is named in the returned error so users know what to increase.
+func RunWithTimeout(ctx context.Context, timeout time.Duration, field string, run func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return run(ctx)
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := run(runCtx)
+	// only blame the timeout if the caller's context is still live
+	if err != nil && runCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return errors.Wrapf(err, "timed out after %v, consider increasing timeouts.%s", timeout, field)
+	}
+	return err
+}
+
+// nodeTimeoutGrace is how long a command timed out inside a node has to exit
+// before it is killed, and how much longer the host waits for the node
+const nodeTimeoutGrace = 10 * time.Second
+
+// RunInNodeWithTimeout runs command in node, returning its combined output
+// lines, with the same timeout semantics as RunWithTimeout.
+// Cancelling a node command's context only stops the client on the host and
+// leaves the command running in the node, so the timeout is enforced by
+// running the command under timeout(1) inside the node instead. The host only
+// gives up on the node if it does not return within a grace period after that.
+func RunInNodeWithTimeout(ctx context.Context, node nodes.Node, timeout time.Duration, field, command string, args ...string) ([]string, error) {
+	if timeout <= 0 {
+		return exec.CombinedOutputLines(node.CommandContext(ctx, command, args...))
+	}
+	var lines []string
+	err := RunWithTimeout(ctx, timeout+nodeTimeoutGrace, field, func(ctx context.Context) error {
+		var err error
+		lines, err = exec.CombinedOutputLines(node.CommandContext(ctx, "timeout", inNodeTimeoutArgs(timeout, command, args...)...))
+		return err
+	})
+	if timedOutInNode(err) && ctx.Err() == nil {
+		return lines, errors.Wrapf(err, "timed out after %v, consider increasing timeouts.%s", timeout, field)
+	}
+	return lines, err
+}
+
+// inNodeTimeoutArgs returns the timeout(1) arguments to run command for at
+// most timeout, killing it if it has not exited nodeTimeoutGrace after that
+func inNodeTimeoutArgs(timeout time.Duration, command string, args ...string) []string {
+	return append([]string{
+		fmt.Sprintf("--kill-after=%gs", nodeTimeoutGrace.Seconds()),
+		fmt.Sprintf("%gs", timeout.Seconds()),
+		command,
+	}, args...)
+}
+
+// timedOutInNode returns true if err is from timeout(1) stopping a command,
+// it exits 124 if the command was terminated and 137 if it had to be killed
+func timedOutInNode(err error) bool {
+	runErr := exec.RunErrorForError(err)
+	if runErr == nil {
+		return false
+	}
+	code := runErr.ExitCode()
+	return code == 124 || code == 137
+}
+
+// Sleep waits for d, returning early with ctx.Err() if ctx is done first
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
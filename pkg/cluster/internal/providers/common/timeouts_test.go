@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+func TestImagePullOptionsForConfig(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Cluster{}
+	if got := ImagePullOptionsForConfig(cfg); got != DefaultImagePullOptions {
+		t.Errorf("expected defaults %+v but got %+v", DefaultImagePullOptions, got)
+	}
+	retries := int32(0)
+	cfg.Timeouts = config.Timeouts{
+		ImagePull:        "5m",
+		ImagePullRetries: &retries,
+		ImagePullBackoff: "10s",
+	}
+	expected := ImagePullOptions{
+		Retries: 0,
+		Backoff: 10 * time.Second,
+		Timeout: 5 * time.Minute,
+	}
+	if got := ImagePullOptionsForConfig(cfg); got != expected {
+		t.Errorf("expected %+v but got %+v", expected, got)
+	}
+}
+
+func TestRunWithTimeout(t *testing.T) {
+	t.Parallel()
+	err := RunWithTimeout(context.Background(), 10*time.Millisecond, "join", func(ctx context.Context) error {
+		return exec.CommandContext(ctx, "sleep", "5").Run()
+	})
+	if err == nil || !strings.Contains(err.Error(), "timeouts.join") {
+		t.Errorf("expected an error naming timeouts.join but got: %v", err)
+	}
+	err = RunWithTimeout(context.Background(), 0, "join", func(ctx context.Context) error {
+		return exec.CommandContext(ctx, "true").Run()
+	})
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+	// a cancelled caller context is not blamed on the timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = RunWithTimeout(ctx, time.Minute, "join", func(ctx context.Context) error {
+		return exec.CommandContext(ctx, "sleep", "5").Run()
+	})
+	if err == nil || strings.Contains(err.Error(), "timeouts.join") {
+		t.Errorf("expected an error not naming timeouts.join but got: %v", err)
+	}
+}
+
+func TestInNodeTimeoutArgs(t *testing.T) {
+	t.Parallel()
+	args := inNodeTimeoutArgs(90*time.Second, "kubeadm", "join", "--v=6")
+	expected := []string{"--kill-after=10s", "90s", "kubeadm", "join", "--v=6"}
+	if strings.Join(args, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected %v but got %v", expected, args)
+	}
+}
+
+func TestTimedOutInNode(t *testing.T) {
+	t.Parallel()
+	if timedOutInNode(nil) {
+		t.Errorf("expected no error not to be a timeout")
+	}
+	err := exec.Command("sh", "-c", "exit 124").Run()
+	if !timedOutInNode(err) {
+		t.Errorf("expected exit status 124 to be a timeout, got: %v", err)
+	}
+	err = exec.Command("sh", "-c", "exit 1").Run()
+	if timedOutInNode(err) {
+		t.Errorf("expected exit status 1 not to be a timeout, got: %v", err)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	t.Parallel()
+	if err := Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, time.Minute); err != context.Canceled {
+		t.Errorf("expected context.Canceled but got: %v", err)
+	}
+}
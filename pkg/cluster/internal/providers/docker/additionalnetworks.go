@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// additionalNetworkPrefix prefixes the names of the networks kind creates
+// for a cluster's networking.additionalNetworks
+const additionalNetworkPrefix = "kind-net-"
+
+// additionalNetworkName returns the name of the docker network for the
+// cluster's additional network
+func additionalNetworkName(cluster, network string) string {
+	return fmt.Sprintf("%s%s-%s", additionalNetworkPrefix, cluster, network)
+}
+
+// isSecondaryNetwork returns true if nodes are attached to the network name
+// in addition to their primary network
+func isSecondaryNetwork(name string) bool {
+	return isBridgedNetwork(name) || strings.HasPrefix(name, additionalNetworkPrefix)
+}
+
+// ensureAdditionalNetworks creates the cluster's additional networks, these
+// are labeled with the cluster and deleted with it
+func ensureAdditionalNetworks(cluster string, networks []config.AdditionalNetwork) error {
+	for _, network := range networks {
+		name := additionalNetworkName(cluster, network.Name)
+		args := []string{"network", "create", "-d=bridge",
+			"--label", fmt.Sprintf("%s=%s", clusterLabelKey, cluster),
+		}
+		if network.Subnet != "" {
+			args = append(args, "--subnet", network.Subnet)
+		}
+		args = append(args, name)
+		if err := exec.Command("docker", args...).Run(); err != nil && !isNetworkAlreadyExistsError(err) {
+			return errors.Wrapf(err, "failed to create network %q", name)
+		}
+	}
+	return nil
+}
+
+// renameInterfaceScript renames the interface with the MAC address $1 to $2
+const renameInterfaceScript = `for dev in /sys/class/net/*; do
+  if [ "$(cat "${dev}/address")" = "$1" ]; then
+    ip link set dev "${dev##*/}" down && ip link set dev "${dev##*/}" name "$2" && ip link set dev "$2" up
+    exit
+  fi
+done
+echo "no interface with address $1" >&2
+exit 1`
+
+// attachAdditionalNetworks attaches the node container to its additional
+// networks, renaming the interfaces as configured since docker names them
+// eth1, eth2 ... in no guaranteed order
+func attachAdditionalNetworks(cluster, node string, networks []config.NodeNetwork) error {
+	for _, network := range networks {
+		name := additionalNetworkName(cluster, network.Name)
+		if err := exec.Command("docker", "network", "connect", name, node).Run(); err != nil {
+			return errors.Wrapf(err, "failed to attach node %q to network %q", node, name)
+		}
+		lines, err := exec.OutputLines(exec.Command(
+			"docker", "inspect",
+			"--format", fmt.Sprintf(`{{(index .NetworkSettings.Networks %q).MacAddress}}`, name),
+			node,
+		))
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the address of node %q on network %q", node, name)
+		}
+		if len(lines) != 1 || lines[0] == "" {
+			return errors.Errorf("failed to get the address of node %q on network %q", node, name)
+		}
+		if err := exec.Command(
+			"docker", "exec", node, "sh", "-c", renameInterfaceScript, "-", lines[0], network.Interface,
+		).Run(); err != nil {
+			return errors.Wrapf(err, "failed to rename the interface of node %q on network %q to %q", node, name, network.Interface)
+		}
+	}
+	return nil
+}
+
+// deleteAdditionalNetworks deletes the additional networks of the cluster,
+// this must be called once its nodes are deleted
+func deleteAdditionalNetworks(cluster string) error {
+	networks, err := exec.OutputLines(exec.Command(
+		"docker", "network", "ls",
+		"--filter", fmt.Sprintf("label=%s=%s", clusterLabelKey, cluster),
+		"--format", "{{.Name}}",
+	))
+	if err != nil {
+		return errors.Wrap(err, "failed to list networks")
+	}
+	if len(networks) == 0 {
+		return nil
+	}
+	if err := deleteNetworks(networks...); err != nil {
+		return errors.Wrap(err, "failed to delete networks")
+	}
+	return nil
+}
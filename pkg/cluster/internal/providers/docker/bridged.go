@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// bridgedNetworkPrefix prefixes the names of the macvlan / ipvlan networks
+// kind creates, these are shared by all clusters bridged to an interface
+// and are left in place like the kind network
+const bridgedNetworkPrefix = "kind-bridged-"
+
+// bridgedNetworkName returns the name of the docker network for n
+func bridgedNetworkName(n *config.BridgedNetwork) string {
+	return fmt.Sprintf("%s%s-%s", bridgedNetworkPrefix, n.Driver, n.Parent)
+}
+
+// isBridgedNetwork returns true if the network name is a bridged network,
+// nodes are attached to one in addition to their primary network
+func isBridgedNetwork(name string) bool {
+	return strings.HasPrefix(name, bridgedNetworkPrefix)
+}
+
+// ensureBridgedNetwork checks that the host supports bridged networking and
+// creates the docker network for n if it does not exist, returning its name
+func ensureBridgedNetwork(info *providers.ProviderInfo, n *config.BridgedNetwork) (string, error) {
+	if info.Rootless {
+		return "", errors.Errorf("networking.bridgedNetwork is not supported with rootless docker, %s networks require root", n.Driver)
+	}
+	// with docker desktop the parent interface is in the desktop VM, so the
+	// nodes would not be reachable from the host's LAN
+	operatingSystem, err := exec.Output(exec.Command("docker", "info", "--format", "{{.OperatingSystem}}"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get docker info")
+	}
+	if strings.Contains(string(operatingSystem), "Docker Desktop") {
+		return "", errors.New("networking.bridgedNetwork is not supported with Docker Desktop, only with docker on a Linux host")
+	}
+
+	name := bridgedNetworkName(n)
+	exists, err := checkIfNetworkExists(name)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if err := createBridgedNetwork(name, n); err != nil && !isNetworkAlreadyExistsError(err) {
+			return "", errors.Wrapf(err, "failed to create %s network on %q", n.Driver, n.Parent)
+		}
+	}
+	// the network may have been created for another cluster, which is
+	// fine as long as it is on the same LAN
+	network, err := inspectNetwork(name)
+	if err != nil {
+		return "", err
+	}
+	if err := validateBridgedNetwork(network, n); err != nil {
+		return "", errors.Wrapf(err, "existing docker network %q does not match networking.bridgedNetwork, remove it with `docker network rm %s`", name, name)
+	}
+	return name, nil
+}
+
+func createBridgedNetwork(name string, n *config.BridgedNetwork) error {
+	args := []string{"network", "create", "-d=" + string(n.Driver),
+		"-o", "parent=" + n.Parent,
+		"--subnet", n.Subnet,
+	}
+	if n.Driver == config.IPVlanDriver {
+		args = append(args, "-o", "ipvlan_mode=l2")
+	}
+	if n.IPRange != "" {
+		args = append(args, "--ip-range", n.IPRange)
+	}
+	if n.Gateway != "" {
+		args = append(args, "--gateway", n.Gateway)
+	}
+	args = append(args, name)
+	return exec.Command("docker", args...).Run()
+}
+
+// validateBridgedNetwork checks that the existing docker network matches n
+func validateBridgedNetwork(network *networkInspectEntry, n *config.BridgedNetwork) error {
+	if network.Driver != string(n.Driver) || network.Options["parent"] != n.Parent {
+		return errors.Errorf("the network is a %s network on %q", network.Driver, network.Options["parent"])
+	}
+	for _, c := range network.IPAM.Config {
+		if c.Subnet != n.Subnet {
+			continue
+		}
+		if n.IPRange != "" && c.IPRange != n.IPRange {
+			return errors.Errorf("the network's ipRange is %q", c.IPRange)
+		}
+		if n.Gateway != "" && c.Gateway != n.Gateway {
+			return errors.Errorf("the network's gateway is %q", c.Gateway)
+		}
+		return nil
+	}
+	return errors.Errorf("the network's subnets are %v", network.subnets())
+}
+
+// connectBridgedNetwork attaches the nodes to the bridged network
+func connectBridgedNetwork(name string, allNodes []nodes.Node) error {
+	fns := []func() error{}
+	for _, node := range allNodes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			if err := exec.Command("docker", "network", "connect", name, node.String()).Run(); err != nil {
+				return errors.Wrapf(err, "failed to attach node %q to network %q", node.String(), name)
+			}
+			return nil
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func Test_validateBridgedNetwork(t *testing.T) {
+	t.Parallel()
+	const inspect = `{"Name": "kind-bridged-macvlan-eth0", "Id": "abc", "Driver": "macvlan",
+		"IPAM": {"Config": [{"Subnet": "192.168.1.0/24", "IPRange": "192.168.1.192/27", "Gateway": "192.168.1.1"}]},
+		"Options": {"parent": "eth0"}}`
+	cases := []struct {
+		Name        string
+		Bridged     config.BridgedNetwork
+		ExpectError bool
+	}{
+		{
+			Name: "matching",
+			Bridged: config.BridgedNetwork{
+				Driver:  config.MacvlanDriver,
+				Parent:  "eth0",
+				Subnet:  "192.168.1.0/24",
+				IPRange: "192.168.1.192/27",
+				Gateway: "192.168.1.1",
+			},
+		},
+		{
+			Name: "defaulted ipRange and gateway",
+			Bridged: config.BridgedNetwork{
+				Driver: config.MacvlanDriver,
+				Parent: "eth0",
+				Subnet: "192.168.1.0/24",
+			},
+		},
+		{
+			Name: "different driver",
+			Bridged: config.BridgedNetwork{
+				Driver: config.IPVlanDriver,
+				Parent: "eth0",
+				Subnet: "192.168.1.0/24",
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "different subnet",
+			Bridged: config.BridgedNetwork{
+				Driver: config.MacvlanDriver,
+				Parent: "eth0",
+				Subnet: "192.168.2.0/24",
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "different ipRange",
+			Bridged: config.BridgedNetwork{
+				Driver:  config.MacvlanDriver,
+				Parent:  "eth0",
+				Subnet:  "192.168.1.0/24",
+				IPRange: "192.168.1.128/27",
+			},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			var entry networkInspectEntry
+			if err := json.Unmarshal([]byte(inspect), &entry); err != nil {
+				t.Fatalf("failed to decode network: %v", err)
+			}
+			err := validateBridgedNetwork(&entry, &tc.Bridged)
+			assert.ExpectError(t, tc.ExpectError, err)
+		})
+	}
+}
+
+func Test_nodeIPs(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name         string
+		Lines        []string
+		ExpectedIPv4 string
+		ExpectedIPv6 string
+		ExpectError  bool
+	}{
+		{
+			Name:         "one network",
+			Lines:        []string{"kind,172.18.0.2,fc00:f853:ccd:e793::2", ""},
+			ExpectedIPv4: "172.18.0.2",
+			ExpectedIPv6: "fc00:f853:ccd:e793::2",
+		},
+		{
+			Name:         "bridged network",
+			Lines:        []string{"kind,172.18.0.2,", "kind-bridged-macvlan-eth0,192.168.1.192,", ""},
+			ExpectedIPv4: "172.18.0.2",
+		},
+		{
+			Name:         "additional networks",
+			Lines:        []string{"kind-net-kind-net1,10.10.0.2,", "kind,172.18.0.2,", "kind-net-kind-net2,10.11.0.2,"},
+			ExpectedIPv4: "172.18.0.2",
+		},
+		{
+			Name:        "multiple networks",
+			Lines:       []string{"kind,172.18.0.2,", "other,172.19.0.2,"},
+			ExpectError: true,
+		},
+		{
+			Name:        "no networks",
+			Lines:       []string{""},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			ipv4, ipv6, err := nodeIPs(tc.Lines)
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.StringEqual(t, tc.ExpectedIPv4, ipv4)
+			assert.StringEqual(t, tc.ExpectedIPv6, ipv6)
+		})
+	}
+}
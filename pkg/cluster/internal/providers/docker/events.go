@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// Events is part of the providers.Provider interface
+func (p *provider) Events(ctx context.Context, cluster string, since time.Time, watch bool, handle func(providers.Event)) error {
+	args := []string{
+		"events",
+		"--filter", "type=container",
+		"--filter", "label=" + clusterLabelKey + "=" + cluster,
+		"--format", "{{json .}}",
+	}
+	for _, action := range []string{
+		providers.EventStart, providers.EventStop, providers.EventKill,
+		providers.EventDie, providers.EventOOM, providers.EventRestart,
+	} {
+		args = append(args, "--filter", "event="+action)
+	}
+	if !since.IsZero() {
+		args = append(args, "--since", strconv.FormatInt(since.Unix(), 10))
+	}
+	if !watch {
+		// without --until docker keeps streaming new events
+		args = append(args, "--until", strconv.FormatInt(time.Now().Unix()+1, 10))
+	}
+	return common.StreamEvents(ctx, "docker", args, parseEvent, handle)
+}
+
+// dockerEvent is the subset of `docker events --format '{{json .}}'` we use
+type dockerEvent struct {
+	Type   string `json:"Type"`
+	Action string `json:"Action"`
+	Actor  struct {
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+	TimeNano int64 `json:"timeNano"`
+}
+
+// parseEvent parses one line of `docker events --format '{{json .}}'`
+func parseEvent(line string) (providers.Event, bool, error) {
+	var e dockerEvent
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		return providers.Event{}, false, err
+	}
+	if e.Type != "container" {
+		return providers.Event{}, false, nil
+	}
+	switch e.Action {
+	case providers.EventStart, providers.EventStop, providers.EventKill,
+		providers.EventDie, providers.EventOOM, providers.EventRestart:
+	default:
+		return providers.Event{}, false, nil
+	}
+	event := providers.Event{
+		Time:   time.Unix(0, e.TimeNano),
+		Node:   e.Actor.Attributes["name"],
+		Role:   e.Actor.Attributes[nodeRoleLabelKey],
+		Action: e.Action,
+	}
+	if code, ok := e.Actor.Attributes["exitCode"]; ok {
+		exitCode, err := strconv.Atoi(code)
+		if err != nil {
+			return providers.Event{}, false, err
+		}
+		event.ExitCode = exitCode
+	}
+	return event, true, nil
+}
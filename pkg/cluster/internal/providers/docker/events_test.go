@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func Test_parseEvent(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		line        string
+		event       providers.Event
+		ok          bool
+		expectError bool
+	}{
+		{
+			name: "die",
+			line: `{"status":"die","id":"abc","from":"kindest/node","Type":"container","Action":"die","Actor":{"ID":"abc","Attributes":{"exitCode":"137","io.x-k8s.kind.cluster":"kind","io.x-k8s.kind.role":"worker","name":"kind-worker"}},"scope":"local","time":1700000000,"timeNano":1700000000123456789}`,
+			event: providers.Event{
+				Time:     time.Unix(0, 1700000000123456789),
+				Node:     "kind-worker",
+				Role:     "worker",
+				Action:   providers.EventDie,
+				ExitCode: 137,
+			},
+			ok: true,
+		},
+		{
+			name: "oom",
+			line: `{"Type":"container","Action":"oom","Actor":{"ID":"abc","Attributes":{"io.x-k8s.kind.role":"control-plane","name":"kind-control-plane"}},"timeNano":1700000000000000000}`,
+			event: providers.Event{
+				Time:   time.Unix(0, 1700000000000000000),
+				Node:   "kind-control-plane",
+				Role:   "control-plane",
+				Action: providers.EventOOM,
+			},
+			ok: true,
+		},
+		{
+			name: "uninteresting action",
+			line: `{"Type":"container","Action":"exec_start: ls","Actor":{"Attributes":{"name":"kind-control-plane"}},"timeNano":1700000000000000000}`,
+		},
+		{
+			name: "other type",
+			line: `{"Type":"network","Action":"connect","Actor":{"Attributes":{"name":"kind"}},"timeNano":1700000000000000000}`,
+		},
+		{
+			name:        "invalid exit code",
+			line:        `{"Type":"container","Action":"die","Actor":{"Attributes":{"exitCode":"x","name":"kind-control-plane"}},"timeNano":1700000000000000000}`,
+			expectError: true,
+		},
+		{
+			name:        "not json",
+			line:        "Error: no such daemon",
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			event, ok, err := parseEvent(tc.line)
+			assert.ExpectError(t, tc.expectError, err)
+			assert.BoolEqual(t, tc.ok, ok)
+			assert.DeepEqual(t, tc.event, event)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// ensureImageCacheVolume ensures the image cache volume for the node exists
+// and was populated from image, a volume populated from another node image
+// is replaced as it would hide the images bundled with image
+func ensureImageCacheVolume(cluster, nodeName, image string) error {
+	name := common.ImageCacheVolumeName(nodeName)
+	lines, err := exec.OutputLines(exec.Command(
+		"docker", "volume", "inspect",
+		"--format", fmt.Sprintf(`{{index .Labels %q}}`, common.ImageCacheImageLabelKey),
+		name,
+	))
+	if err == nil {
+		if len(lines) == 1 && lines[0] == image {
+			return nil
+		}
+		if err := exec.Command("docker", "volume", "rm", name).Run(); err != nil {
+			return errors.Wrapf(err, "failed to remove outdated image cache volume %q", name)
+		}
+	}
+	if err := exec.Command(
+		"docker", "volume", "create",
+		"--label", fmt.Sprintf("%s=%s", common.ImageCacheLabelKey, cluster),
+		"--label", fmt.Sprintf("%s=%s", common.ImageCacheImageLabelKey, image),
+		name,
+	).Run(); err != nil {
+		return errors.Wrapf(err, "failed to create image cache volume %q", name)
+	}
+	return nil
+}
+
+// ListImageCacheVolumes is part of the providers.Provider interface
+func (p *provider) ListImageCacheVolumes() ([]providers.Volume, error) {
+	lines, err := exec.OutputLines(exec.Command(
+		"docker", "volume", "ls",
+		"--filter", "label="+common.ImageCacheLabelKey,
+		"--format", fmt.Sprintf(`{{.Name}}\t{{.Label %q}}`, common.ImageCacheLabelKey),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list image cache volumes")
+	}
+	return common.ParseImageCacheVolumes(lines)
+}
+
+// DeleteVolumes is part of the providers.Provider interface
+func (p *provider) DeleteVolumes(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	args := append([]string{"volume", "rm"}, names...)
+	if err := exec.Command("docker", args...).Run(); err != nil {
+		return errors.Wrap(err, "failed to delete volumes")
+	}
+	return nil
+}
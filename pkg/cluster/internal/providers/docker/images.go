@@ -17,6 +17,7 @@ limitations under the License.
 package docker
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -32,24 +33,47 @@ import (
 
 // ensureNodeImages ensures that the node images used by the create
 // configuration are present
-func ensureNodeImages(logger log.Logger, status *cli.Status, cfg *config.Cluster) error {
-	// pull each required image
-	for _, image := range common.RequiredNodeImages(cfg).List() {
-		// prints user friendly message
-		friendlyImageName, image := sanitizeImage(image)
-		status.Start(fmt.Sprintf("Ensuring node image (%s) 🖼", friendlyImageName))
-		if _, err := pullIfNotPresent(logger, image, 4); err != nil {
-			status.End(false)
-			return err
-		}
+func ensureNodeImages(ctx context.Context, logger log.Logger, status *cli.Status, cfg *config.Cluster) error {
+	opts := common.ImagePullOptionsForConfig(cfg)
+	images := common.RequiredNodeImages(cfg).List()
+	friendlyImageNames := make([]string, len(images))
+	for i := range images {
+		friendlyImageNames[i], images[i] = sanitizeImage(images[i])
+	}
+	// prints user friendly message
+	status.Start(fmt.Sprintf("Ensuring node image (%s) 🖼", strings.Join(friendlyImageNames, ", ")))
+	// pull the required images concurrently, these are large and a cluster
+	// with mixed node versions would otherwise pull them one at a time
+	// nodes from an image for another architecture fail with exec format
+	// errors, so check the images against the host's platform
+	hostPlatform, err := serverPlatform()
+	if err != nil {
+		logger.Warnf("Failed to get the host platform, not checking node image platforms: %v", err)
+	}
+	fns := make([]func() error, 0, len(images))
+	for _, image := range images {
+		image := image // capture loop variable
+		fns = append(fns, func() error {
+			if _, err := pullIfNotPresent(ctx, logger, image, opts); err != nil {
+				return err
+			}
+			if hostPlatform == "" {
+				return nil
+			}
+			return ensureImagePlatform(ctx, logger, image, hostPlatform)
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		status.End(false)
+		return err
 	}
 	return nil
 }
 
 // pullIfNotPresent will pull an image if it is not present locally
-// retrying up to retries times
+// retrying as configured by opts
 // it returns true if it attempted to pull, and any errors from pulling
-func pullIfNotPresent(logger log.Logger, image string, retries int) (pulled bool, err error) {
+func pullIfNotPresent(ctx context.Context, logger log.Logger, image string, opts common.ImagePullOptions) (pulled bool, err error) {
 	// TODO(bentheelder): switch most (all) of the logging here to debug level
 	// once we have configurable log levels
 	// if this did not return an error, then the image exists locally
@@ -59,20 +83,26 @@ func pullIfNotPresent(logger log.Logger, image string, retries int) (pulled bool
 		return false, nil
 	}
 	// otherwise try to pull it
-	return true, pull(logger, image, retries)
+	return true, pull(ctx, logger, image, opts)
 }
 
-// pull pulls an image, retrying up to retries times
-func pull(logger log.Logger, image string, retries int) error {
+// pull pulls an image, retrying with a linear backoff as configured by opts
+func pull(ctx context.Context, logger log.Logger, image string, opts common.ImagePullOptions) error {
 	logger.V(1).Infof("Pulling image: %s ...", image)
-	err := exec.Command("docker", "pull", image).Run()
+	pullOnce := func() error {
+		return common.RunWithTimeout(ctx, opts.Timeout, "imagePull", func(ctx context.Context) error {
+			return exec.CommandContext(ctx, "docker", "pull", image).Run()
+		})
+	}
+	err := pullOnce()
 	// retry pulling up to retries times if necessary
 	if err != nil {
-		for i := 0; i < retries; i++ {
-			time.Sleep(time.Second * time.Duration(i+1))
+		for i := 0; i < opts.Retries; i++ {
+			if err := common.Sleep(ctx, opts.Backoff*time.Duration(i+1)); err != nil {
+				return errors.Wrapf(err, "failed to pull image %q", image)
+			}
 			logger.V(1).Infof("Trying again to pull image: %q ... %v", image, err)
-			// TODO(bentheelder): add some backoff / sleep?
-			err = exec.Command("docker", "pull", image).Run()
+			err = pullOnce()
 			if err == nil {
 				break
 			}
@@ -81,6 +111,52 @@ func pull(logger log.Logger, image string, retries int) error {
 	return errors.Wrapf(err, "failed to pull image %q", image)
 }
 
+// ensureImagePlatform makes sure the local image matches hostPlatform,
+// pulling hostPlatform from the image's manifest list if it does not, e.g.
+// after the image was pulled for another platform
+func ensureImagePlatform(ctx context.Context, logger log.Logger, image, hostPlatform string) error {
+	platform, err := imagePlatform(image)
+	if err != nil {
+		return err
+	}
+	if common.ImagePlatformError(image, platform, hostPlatform) == nil {
+		return nil
+	}
+	logger.V(1).Infof("Image: %s is %s, pulling %s ...", image, platform, hostPlatform)
+	if err := exec.CommandContext(ctx, "docker", "pull", "--platform="+hostPlatform, image).Run(); err == nil {
+		if platform, err = imagePlatform(image); err != nil {
+			return err
+		}
+	}
+	return common.ImagePlatformError(image, platform, hostPlatform)
+}
+
+// imagePlatform returns the os/arch of the local image
+func imagePlatform(image string) (string, error) {
+	lines, err := exec.OutputLines(exec.Command(
+		"docker", "image", "inspect", "--format", "{{.Os}}/{{.Architecture}}", image,
+	))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to inspect image %q", image)
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("image platform should only be one line, got %d lines", len(lines))
+	}
+	return lines[0], nil
+}
+
+// serverPlatform returns the os/arch of the host running the containers
+func serverPlatform() (string, error) {
+	lines, err := exec.OutputLines(exec.Command("docker", "version", "--format", "{{.Server.Os}}/{{.Server.Arch}}"))
+	if err != nil {
+		return "", err
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("host platform should only be one line, got %d lines", len(lines))
+	}
+	return lines[0], nil
+}
+
 // sanitizeImage is a helper to return human readable image name and
 // the docker pullable image name from the provided image
 func sanitizeImage(image string) (string, string) {
@@ -89,3 +165,19 @@ func sanitizeImage(image string) (string, string) {
 	}
 	return image, image
 }
+
+// listBundledImages returns the images preloaded in the given node image
+func listBundledImages(logger log.Logger, image string) ([]string, error) {
+	_, image = sanitizeImage(image)
+	if _, err := pullIfNotPresent(context.Background(), logger, image, common.DefaultImagePullOptions); err != nil {
+		return nil, err
+	}
+	lines, err := exec.OutputLines(exec.Command(
+		"docker", "run", "--rm", "--entrypoint=/bin/sh", image,
+		"-c", common.BundledImagesScript,
+	))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list images bundled in %q", image)
+	}
+	return common.ParseBundledImages(lines).List(), nil
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"regexp"
 	"sort"
 	"strconv"
@@ -31,6 +32,10 @@ import (
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
 
 // This may be overridden by KIND_EXPERIMENTAL_DOCKER_NETWORK env,
@@ -46,6 +51,14 @@ import (
 // networks.
 const fixedNetworkName = "kind"
 
+// clusterNetworkName returns the network new nodes are attached to
+func clusterNetworkName() string {
+	if n := os.Getenv("KIND_EXPERIMENTAL_DOCKER_NETWORK"); n != "" {
+		return n
+	}
+	return fixedNetworkName
+}
+
 // ensureNetwork checks if docker network by name exists, if not it creates it
 func ensureNetwork(name string) error {
 	// check if network exists already and remove any duplicate networks
@@ -55,7 +68,8 @@ func ensureNetwork(name string) error {
 	}
 
 	// network already exists, we're good
-	// TODO: the network might already exist and not have ipv6 ... :|
+	// NOTE: the network might already exist and not have ipv6, this is
+	// handled by ensureNetworkForCluster once we know the cluster config
 	// discussion: https://github.com/kubernetes-sigs/kind/pull/1508#discussion_r414594198
 	if exists {
 		return nil
@@ -125,14 +139,173 @@ func ensureNetwork(name string) error {
 	return errors.New("exhausted attempts trying to find a non-overlapping subnet")
 }
 
+// ipv6UnavailableRemediation explains how to enable IPv6 in docker
+const ipv6UnavailableRemediation = `IPv6 appears to be unavailable in docker on this host.
+Enable it by setting "ipv6": true and "ip6tables": true in /etc/docker/daemon.json,
+ensure ip6tables is installed, and restart docker.
+See: https://docs.docker.com/config/daemon/ipv6/`
+
+// ensureNetworkForCluster checks that the network is usable for the
+// cluster's IP family and subnets.
+//
+// If the cluster requires IPv6 and the network lacks it, the network is
+// recreated with an IPv6 subnet when nothing else is attached to it,
+// otherwise an error with remediation steps is returned.
+func ensureNetworkForCluster(logger log.Logger, name string, cfg *config.Cluster) error {
+	network, err := inspectNetwork(name)
+	if err != nil {
+		return err
+	}
+
+	if config.ClusterHasIPv6(cfg) && !network.EnableIPv6 {
+		if len(network.Containers) > 0 {
+			return errors.Errorf(
+				"docker network %q does not have IPv6 enabled, which is required for ipFamily %q.\n"+
+					"It is in use by %d container(s), so kind will not recreate it.\n"+
+					"Delete all kind clusters (kind delete clusters --all) and any other containers using it, "+
+					"then remove it with `docker network rm %s` and retry; kind will recreate it with IPv6 enabled.",
+				name, cfg.Networking.IPFamily, len(network.Containers), name,
+			)
+		}
+		logger.Warnf("docker network %q does not have IPv6 enabled, recreating it for ipFamily %q", name, cfg.Networking.IPFamily)
+		if err := deleteNetworks(network.ID); err != nil {
+			return errors.Wrapf(err, "failed to remove docker network %q", name)
+		}
+		if err := createNetworkNoDuplicates(name, generateULASubnetFromName(name, 0), getDefaultNetworkMTU()); err != nil {
+			if isIPv6UnavailableError(err) {
+				return errors.Wrap(err, ipv6UnavailableRemediation)
+			}
+			return errors.Wrapf(err, "failed to recreate docker network %q with IPv6", name)
+		}
+		if network, err = inspectNetwork(name); err != nil {
+			return err
+		}
+	}
+
+	return validateClusterSubnets(name, network.subnets(), cfg)
+}
+
+// validateClusterSubnets returns an error if the cluster's pod or service
+// subnets overlap with the node network's subnets
+func validateClusterSubnets(networkName string, networkSubnets []string, cfg *config.Cluster) error {
+	networkCIDRs := make([]*net.IPNet, 0, len(networkSubnets))
+	for _, s := range networkSubnets {
+		_, cidr, err := net.ParseCIDR(s)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse docker network %q subnet %q", networkName, s)
+		}
+		networkCIDRs = append(networkCIDRs, cidr)
+	}
+	errs := []error{}
+	for _, clusterSubnets := range []struct {
+		name    string
+		subnets string
+	}{
+		{"podSubnet", cfg.Networking.PodSubnet},
+		{"serviceSubnet", cfg.Networking.ServiceSubnet},
+	} {
+		for _, s := range strings.Split(clusterSubnets.subnets, ",") {
+			_, cidr, err := net.ParseCIDR(s)
+			if err != nil {
+				// these are validated elsewhere
+				continue
+			}
+			for _, networkCIDR := range networkCIDRs {
+				if cidr.Contains(networkCIDR.IP) || networkCIDR.Contains(cidr.IP) {
+					errs = append(errs, errors.Errorf(
+						"%s %s overlaps with docker network %q subnet %s, choose a different %s",
+						clusterSubnets.name, cidr, networkName, networkCIDR, clusterSubnets.name,
+					))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+	return nil
+}
+
+func inspectNetwork(name string) (*networkInspectEntry, error) {
+	networks, err := inspectNetworks([]string{name})
+	if err != nil {
+		return nil, err
+	}
+	if len(networks) != 1 {
+		return nil, errors.Errorf("failed to inspect docker network %q", name)
+	}
+	return &networks[0], nil
+}
+
 func createNetworkNoDuplicates(name, ipv6Subnet string, mtu int) error {
-	if err := createNetwork(name, ipv6Subnet, mtu); err != nil && !isNetworkAlreadyExistsError(err) {
+	created := true
+	if err := createNetwork(name, ipv6Subnet, mtu); err != nil {
+		if !isNetworkAlreadyExistsError(err) {
+			return err
+		}
+		created = false
+	}
+	if _, err := removeDuplicateNetworks(name); err != nil {
+		return err
+	}
+	if !created {
+		return nil
+	}
+	return limitNetworkIPRanges(name, mtu)
+}
+
+// limitNetworkIPRanges recreates the network we just created so that docker
+// only allocates container addresses from the lower half of each subnet,
+// leaving the upper half free for LoadBalancer addresses.
+//
+// docker only accepts --ip-range along with an explicit --subnet, so the
+// network is first created with docker picking a free IPv4 subnet.
+func limitNetworkIPRanges(name string, mtu int) error {
+	network, err := inspectNetwork(name)
+	if err != nil {
 		return err
 	}
-	_, err := removeDuplicateNetworks(name)
+	// the network is in use already, another kind process raced us
+	if len(network.Containers) > 0 {
+		return nil
+	}
+	args := networkCreateArgs(mtu)
+	for _, c := range network.IPAM.Config {
+		if c.IPRange != "" {
+			return nil
+		}
+		_, subnet, err := net.ParseCIDR(c.Subnet)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse docker network %q subnet", name)
+		}
+		args = append(args, "--subnet", c.Subnet, "--ip-range", lowerHalf(subnet).String())
+		if c.Gateway != "" {
+			args = append(args, "--gateway", c.Gateway)
+		}
+	}
+	if network.EnableIPv6 {
+		args = append(args, "--ipv6")
+	}
+	if err := deleteNetworks(network.ID); err != nil {
+		return errors.Wrapf(err, "failed to recreate docker network %q", name)
+	}
+	args = append(args, name)
+	if err := exec.Command("docker", args...).Run(); err != nil && !isNetworkAlreadyExistsError(err) {
+		return errors.Wrapf(err, "failed to recreate docker network %q", name)
+	}
+	_, err = removeDuplicateNetworks(name)
 	return err
 }
 
+// lowerHalf returns the lower half of subnet
+func lowerHalf(subnet *net.IPNet) *net.IPNet {
+	ones, bits := subnet.Mask.Size()
+	if ones == bits {
+		return subnet
+	}
+	return &net.IPNet{IP: subnet.IP, Mask: net.CIDRMask(ones+1, bits)}
+}
+
 func removeDuplicateNetworks(name string) (bool, error) {
 	networks, err := sortedNetworksWithName(name)
 	if err != nil {
@@ -147,17 +320,24 @@ func removeDuplicateNetworks(name string) (bool, error) {
 }
 
 func createNetwork(name, ipv6Subnet string, mtu int) error {
+	args := networkCreateArgs(mtu)
+	if ipv6Subnet != "" {
+		args = append(args, "--ipv6", "--subnet", ipv6Subnet)
+	}
+	args = append(args, name)
+	return exec.Command("docker", args...).Run()
+}
+
+// networkCreateArgs returns the docker network create args common to
+// all of kind's networks
+func networkCreateArgs(mtu int) []string {
 	args := []string{"network", "create", "-d=bridge",
 		"-o", "com.docker.network.bridge.enable_ip_masquerade=true",
 	}
 	if mtu > 0 {
 		args = append(args, "-o", fmt.Sprintf("com.docker.network.driver.mtu=%d", mtu))
 	}
-	if ipv6Subnet != "" {
-		args = append(args, "--ipv6", "--subnet", ipv6Subnet)
-	}
-	args = append(args, name)
-	return exec.Command("docker", args...).Run()
+	return args
 }
 
 // getDefaultNetworkMTU obtains the MTU from the docker default network
@@ -227,10 +407,70 @@ func inspectNetworks(networkIDs []string) ([]networkInspectEntry, error) {
 }
 
 type networkInspectEntry struct {
-	ID string `json:"Id"`
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Driver string `json:"Driver"`
 	// NOTE: we don't care about the contents here but we need to parse
 	// how many entries exist in the containers map
 	Containers map[string]map[string]string `json:"Containers"`
+	EnableIPv6 bool                         `json:"EnableIPv6"`
+	IPAM       struct {
+		Config []struct {
+			Subnet  string `json:"Subnet"`
+			IPRange string `json:"IPRange"`
+			Gateway string `json:"Gateway"`
+		} `json:"Config"`
+	} `json:"IPAM"`
+	Options map[string]string `json:"Options"`
+}
+
+// network returns the network's details
+func (n *networkInspectEntry) network() *providers.Network {
+	network := &providers.Network{
+		Name:    n.Name,
+		Subnets: n.subnets(),
+	}
+	for _, c := range n.IPAM.Config {
+		if c.Gateway != "" {
+			network.Gateways = append(network.Gateways, c.Gateway)
+		}
+		if c.IPRange != "" {
+			network.IPRanges = append(network.IPRanges, c.IPRange)
+		}
+	}
+	// an unset or invalid MTU is left as the default
+	network.MTU, _ = strconv.Atoi(n.Options["com.docker.network.driver.mtu"])
+	return network
+}
+
+// nodeNetworkName returns the name of the network node is attached to,
+// ignoring any secondary networks
+func nodeNetworkName(node string) (string, error) {
+	lines, err := exec.OutputLines(exec.Command(
+		"docker", "inspect",
+		"--format", `{{range $name, $_ := .NetworkSettings.Networks}}{{$name}}{{"\n"}}{{end}}`,
+		node,
+	))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get network of node %q", node)
+	}
+	for _, line := range lines {
+		if line != "" && !isSecondaryNetwork(line) {
+			return line, nil
+		}
+	}
+	return "", errors.Errorf("node %q is not attached to a network", node)
+}
+
+// subnets returns the subnets configured for the network
+func (n *networkInspectEntry) subnets() []string {
+	subnets := make([]string, 0, len(n.IPAM.Config))
+	for _, c := range n.IPAM.Config {
+		if c.Subnet != "" {
+			subnets = append(subnets, c.Subnet)
+		}
+	}
+	return subnets
 }
 
 // networksWithName returns a list of network IDs for networks with this name
@@ -17,9 +17,13 @@ limitations under the License.
 package docker
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
 	"testing"
 
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 	"sigs.k8s.io/kind/pkg/internal/assert"
 )
 
@@ -160,3 +164,121 @@ func Test_sortNetworkInspectEntries(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateClusterSubnets(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name           string
+		NetworkSubnets []string
+		PodSubnet      string
+		ServiceSubnet  string
+		ExpectError    bool
+	}{
+		{
+			Name:           "defaults",
+			NetworkSubnets: []string{"172.18.0.0/16", "fc00:f853:ccd:e793::/64"},
+			PodSubnet:      "10.244.0.0/16,fd00:10:244::/56",
+			ServiceSubnet:  "10.96.0.0/16,fd00:10:96::/112",
+		},
+		{
+			Name:           "pod subnet contains network subnet",
+			NetworkSubnets: []string{"172.18.0.0/16"},
+			PodSubnet:      "172.16.0.0/12",
+			ServiceSubnet:  "10.96.0.0/16",
+			ExpectError:    true,
+		},
+		{
+			Name:           "network subnet contains service subnet",
+			NetworkSubnets: []string{"172.18.0.0/16", "fc00:f853:ccd:e793::/64"},
+			PodSubnet:      "10.244.0.0/16",
+			ServiceSubnet:  "fc00:f853:ccd:e793::/112",
+			ExpectError:    true,
+		},
+		{
+			Name:           "no network subnets",
+			NetworkSubnets: nil,
+			PodSubnet:      "10.244.0.0/16",
+			ServiceSubnet:  "10.96.0.0/16",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &config.Cluster{}
+			cfg.Networking.PodSubnet = tc.PodSubnet
+			cfg.Networking.ServiceSubnet = tc.ServiceSubnet
+			err := validateClusterSubnets("kind", tc.NetworkSubnets, cfg)
+			assert.ExpectError(t, tc.ExpectError, err)
+		})
+	}
+}
+
+func Test_networkInspectEntry_network(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Inspect  string
+		Expected *providers.Network
+	}{
+		{
+			Name: "dual stack with mtu",
+			Inspect: `{"Name": "kind", "Id": "abc", "EnableIPv6": true,
+				"IPAM": {"Config": [
+					{"Subnet": "172.18.0.0/16", "IPRange": "172.18.0.0/17", "Gateway": "172.18.0.1"},
+					{"Subnet": "fc00:f853:ccd:e793::/64", "IPRange": "fc00:f853:ccd:e793::/65", "Gateway": "fc00:f853:ccd:e793::1"}
+				]},
+				"Options": {"com.docker.network.bridge.enable_ip_masquerade": "true", "com.docker.network.driver.mtu": "1450"}}`,
+			Expected: &providers.Network{
+				Name:     "kind",
+				Subnets:  []string{"172.18.0.0/16", "fc00:f853:ccd:e793::/64"},
+				Gateways: []string{"172.18.0.1", "fc00:f853:ccd:e793::1"},
+				IPRanges: []string{"172.18.0.0/17", "fc00:f853:ccd:e793::/65"},
+				MTU:      1450,
+			},
+		},
+		{
+			Name: "default mtu",
+			Inspect: `{"Name": "kind", "Id": "abc",
+				"IPAM": {"Config": [{"Subnet": "172.18.0.0/16"}]}}`,
+			Expected: &providers.Network{
+				Name:    "kind",
+				Subnets: []string{"172.18.0.0/16"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			var entry networkInspectEntry
+			if err := json.Unmarshal([]byte(tc.Inspect), &entry); err != nil {
+				t.Fatalf("failed to decode network: %v", err)
+			}
+			assert.DeepEqual(t, tc.Expected, entry.network())
+		})
+	}
+}
+
+func Test_lowerHalf(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Subnet   string
+		Expected string
+	}{
+		{Subnet: "172.18.0.0/16", Expected: "172.18.0.0/17"},
+		{Subnet: "fc00:f853:ccd:e793::/64", Expected: "fc00:f853:ccd:e793::/65"},
+		{Subnet: "172.18.0.1/32", Expected: "172.18.0.1/32"},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Subnet, func(t *testing.T) {
+			t.Parallel()
+			_, subnet, err := net.ParseCIDR(tc.Subnet)
+			if err != nil {
+				t.Fatalf("invalid test subnet: %v", err)
+			}
+			assert.StringEqual(t, tc.Expected, lowerHalf(subnet).String())
+		})
+	}
+}
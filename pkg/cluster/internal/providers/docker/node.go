@@ -53,21 +53,36 @@ func (n *node) Role() (string, error) {
 func (n *node) IP() (ipv4 string, ipv6 string, err error) {
 	// retrieve the IP address of the node using docker inspect
 	cmd := exec.Command("docker", "inspect",
-		"-f", "{{range .NetworkSettings.Networks}}{{.IPAddress}},{{.GlobalIPv6Address}}{{end}}",
+		"-f", `{{range $name, $net := .NetworkSettings.Networks}}{{printf "%s,%s,%s\n" $name $net.IPAddress $net.GlobalIPv6Address}}{{end}}`,
 		n.name, // ... against the "node" container
 	)
 	lines, err := exec.OutputLines(cmd)
 	if err != nil {
 		return "", "", errors.Wrap(err, "failed to get container details")
 	}
-	if len(lines) != 1 {
-		return "", "", errors.Errorf("file should only be one line, got %d lines", len(lines))
+	return nodeIPs(lines)
+}
+
+// nodeIPs returns the addresses on the node's primary network from
+// "name,ipv4,ipv6" lines, ignoring any secondary networks
+func nodeIPs(lines []string) (ipv4 string, ipv6 string, err error) {
+	found := [][]string{}
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return "", "", errors.Errorf("container addresses should have 3 values, got %d values", len(fields))
+		}
+		if !isSecondaryNetwork(fields[0]) {
+			found = append(found, fields)
+		}
 	}
-	ips := strings.Split(lines[0], ",")
-	if len(ips) != 2 {
-		return "", "", errors.Errorf("container addresses should have 2 values, got %d values", len(ips))
+	if len(found) != 1 {
+		return "", "", errors.Errorf("container should be attached to one network, got %d networks", len(found))
 	}
-	return ips[0], ips[1], nil
+	return found[0][1], found[0][2], nil
 }
 
 func (n *node) Command(command string, args ...string) exec.Cmd {
@@ -97,6 +112,7 @@ type nodeCmd struct {
 	stdout   io.Writer
 	stderr   io.Writer
 	ctx      context.Context
+	tty      bool // allocate a TTY, see SetTTY
 }
 
 func (c *nodeCmd) Run() error {
@@ -111,6 +127,11 @@ func (c *nodeCmd) Run() error {
 		args = append(args,
 			"-i", // interactive so we can supply input
 		)
+		if c.tty {
+			args = append(args,
+				"-t", // allocate a TTY for interactive shells
+			)
+		}
 	}
 	// set env
 	for _, env := range c.env {
@@ -166,6 +187,31 @@ func (c *nodeCmd) SetStderr(w io.Writer) exec.Cmd {
 	return c
 }
 
+// SetTTY implements exec.TTYCmd, the TTY is only allocated with stdin set
+func (c *nodeCmd) SetTTY(tty bool) exec.Cmd {
+	c.tty = tty
+	return c
+}
+
 func (n *node) SerialLogs(w io.Writer) error {
 	return exec.Command("docker", "logs", n.name).SetStdout(w).SetStderr(w).Run()
 }
+
+// HasFile returns true if path exists in the node. The file is copied out of
+// the container rather than tested with exec, so this works on stopped nodes.
+func (n *node) HasFile(path string) (bool, error) {
+	err := exec.Command("docker", "cp", n.name+":"+path, "-").SetStdout(io.Discard).Run()
+	if err == nil {
+		return true, nil
+	}
+	if runErr := exec.RunErrorForError(err); runErr != nil && isFileNotFound(runErr.Output) {
+		return false, nil
+	}
+	return false, errors.Wrapf(err, "failed to check for %q in node", path)
+}
+
+// isFileNotFound returns true if the output of docker cp reports that the
+// source file does not exist, as opposed to the container or daemon failing
+func isFileNotFound(output []byte) bool {
+	return strings.Contains(string(output), "Could not find the file")
+}
@@ -17,11 +17,11 @@ limitations under the License.
 package docker
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -61,23 +61,41 @@ func (p *provider) String() string {
 }
 
 // Provision is part of the providers.Provider interface
-func (p *provider) Provision(status *cli.Status, cfg *config.Cluster) (err error) {
+func (p *provider) Provision(ctx context.Context, status *cli.Status, cfg *config.Cluster) (err error) {
 	// TODO: validate cfg
 	// ensure node images are pulled before actually provisioning
-	if err := ensureNodeImages(p.logger, status, cfg); err != nil {
+	if err := ensureNodeImages(ctx, p.logger, status, cfg); err != nil {
 		return err
 	}
 
 	// ensure the pre-requisite network exists
-	networkName := fixedNetworkName
-	if n := os.Getenv("KIND_EXPERIMENTAL_DOCKER_NETWORK"); n != "" {
+	networkName := clusterNetworkName()
+	if networkName != fixedNetworkName {
 		p.logger.Warn("WARNING: Overriding docker network due to KIND_EXPERIMENTAL_DOCKER_NETWORK")
 		p.logger.Warn("WARNING: Here be dragons! This is not supported currently.")
-		networkName = n
 	}
 	if err := ensureNetwork(networkName); err != nil {
 		return errors.Wrap(err, "failed to ensure docker network")
 	}
+	if err := ensureNetworkForCluster(p.logger, networkName, cfg); err != nil {
+		return errors.Wrap(err, "docker network is not usable for this cluster")
+	}
+	// ensure the cluster's additional networks exist
+	if err := ensureAdditionalNetworks(cfg.Name, cfg.Networking.AdditionalNetworks); err != nil {
+		return err
+	}
+	// optionally ensure the network bridging the nodes to the host's LAN
+	bridgedNetwork := ""
+	if cfg.Networking.BridgedNetwork != nil {
+		info, err := p.Info()
+		if err != nil {
+			return err
+		}
+		bridgedNetwork, err = ensureBridgedNetwork(info, cfg.Networking.BridgedNetwork)
+		if err != nil {
+			return errors.Wrap(err, "failed to ensure bridged network")
+		}
+	}
 
 	// actually provision the cluster
 	icons := strings.Repeat("📦 ", len(cfg.Nodes))
@@ -91,7 +109,29 @@ func (p *provider) Provision(status *cli.Status, cfg *config.Cluster) (err error
 	}
 
 	// actually create nodes
-	return errors.UntilErrorConcurrent(createContainerFuncs)
+	if err := errors.UntilErrorConcurrent(createContainerFuncs); err != nil {
+		return err
+	}
+
+	// attach the kubernetes nodes to the bridged network, in addition to
+	// the kind network that the host reaches them through
+	if bridgedNetwork != "" {
+		allNodes, err := p.ListNodes(cfg.Name)
+		if err != nil {
+			return err
+		}
+		internalNodes, err := nodeutils.InternalNodes(allNodes)
+		if err != nil {
+			return err
+		}
+		return connectBridgedNetwork(bridgedNetwork, internalNodes)
+	}
+	return nil
+}
+
+// RunArgs is part of the providers.Provider interface
+func (p *provider) RunArgs(cfg *config.Cluster) (map[string][]string, error) {
+	return planRunArgs(cfg, clusterNetworkName())
 }
 
 // ListClusters is part of the providers.Provider interface
@@ -154,6 +194,11 @@ func (p *provider) DeleteNodes(n []nodes.Node) error {
 	return nil
 }
 
+// DeleteClusterNetworks is part of the providers.Provider interface
+func (p *provider) DeleteClusterNetworks(cluster string) error {
+	return deleteAdditionalNetworks(cluster)
+}
+
 // GetAPIServerEndpoint is part of the providers.Provider interface
 func (p *provider) GetAPIServerEndpoint(cluster string) (string, error) {
 	// locate the node that hosts this
@@ -284,6 +329,77 @@ func (p *provider) CollectLogs(dir string, nodes []nodes.Node) error {
 	return errors.NewAggregate(errs)
 }
 
+// ListBundledImages returns the images preloaded in the given node image
+func (p *provider) ListBundledImages(image string) ([]string, error) {
+	return listBundledImages(p.logger, image)
+}
+
+// RunHostNetworkHelper runs command in a short-lived privileged container
+// in the host's network namespace, using node's image
+func (p *provider) RunHostNetworkHelper(node nodes.Node, command ...string) error {
+	lines, err := exec.OutputLines(exec.Command(
+		"docker", "inspect", "--format={{.Config.Image}}", node.String(),
+	))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get image of node %q", node)
+	}
+	if len(lines) != 1 {
+		return errors.Errorf("failed to get image of node %q: unexpected output %v", node, lines)
+	}
+	args := []string{
+		"run", "--rm", "--privileged", "--network=host",
+		"--entrypoint=" + command[0], lines[0],
+	}
+	args = append(args, command[1:]...)
+	return exec.Command("docker", args...).Run()
+}
+
+// GetNetwork is part of the providers.Provider interface
+func (p *provider) GetNetwork(cluster string) (*providers.Network, error) {
+	n, err := p.ListNodes(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	if len(n) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", cluster)
+	}
+	name, err := nodeNetworkName(n[0].String())
+	if err != nil {
+		return nil, err
+	}
+	network, err := inspectNetwork(name)
+	if err != nil {
+		return nil, err
+	}
+	return network.network(), nil
+}
+
+// ContainerID is part of the providers.Provider interface
+func (p *provider) ContainerID(node nodes.Node) (string, error) {
+	lines, err := exec.OutputLines(exec.Command("docker", "inspect", "--format", "{{.Id}}", node.String()))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get container ID of %s", node.String())
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("expected one line of output for the container ID of %s, got %d", node.String(), len(lines))
+	}
+	return lines[0], nil
+}
+
+// ListPorts is part of the providers.Provider interface
+func (p *provider) ListPorts(node nodes.Node) ([]providers.PortMapping, error) {
+	lines, err := exec.OutputLines(exec.Command("docker", "port", node.String()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list ports of %s", node.String())
+	}
+	return common.ParsePorts(lines)
+}
+
+// IsLocal is part of the providers.Provider interface
+func (p *provider) IsLocal() bool {
+	return isLocal()
+}
+
 // Info returns the provider info.
 // The info is cached on the first time of the execution.
 func (p *provider) Info() (*providers.ProviderInfo, error) {
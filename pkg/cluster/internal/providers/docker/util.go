@@ -18,6 +18,7 @@ package docker
 
 import (
 	"encoding/json"
+	"os"
 	"strings"
 
 	"sigs.k8s.io/kind/pkg/exec"
@@ -33,6 +34,27 @@ func IsAvailable() bool {
 	return strings.HasPrefix(lines[0], "Docker version")
 }
 
+// isLocal returns true unless docker is configured to use a daemon on
+// another host, with DOCKER_HOST or the current docker context
+func isLocal() bool {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		lines, err := exec.OutputLines(exec.Command(
+			"docker", "context", "inspect", "--format", "{{.Endpoints.docker.Host}}",
+		))
+		if err != nil || len(lines) != 1 {
+			return true
+		}
+		host = lines[0]
+	}
+	return isLocalHost(host)
+}
+
+// isLocalHost returns true if the docker host address is a local socket
+func isLocalHost(host string) bool {
+	return host == "" || strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://")
+}
+
 // usernsRemap checks if userns-remap is enabled in dockerd
 func usernsRemap() bool {
 	cmd := exec.Command("docker", "info", "--format", "'{{json .SecurityOptions}}'")
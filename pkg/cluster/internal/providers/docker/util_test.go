@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+)
+
+func Test_isLocalHost(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		host  string
+		local bool
+	}{
+		{host: "", local: true},
+		{host: "unix:///var/run/docker.sock", local: true},
+		{host: "npipe:////./pipe/docker_engine", local: true},
+		{host: "tcp://192.168.1.10:2376"},
+		{host: "ssh://user@builder"},
+	}
+	for _, tc := range cases {
+		if local := isLocalHost(tc.host); local != tc.local {
+			t.Errorf("isLocalHost(%q) = %v but expected %v", tc.host, local, tc.local)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"sigs.k8s.io/kind/pkg/cluster/external"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// nodes.Node implementation for external providers
+type node struct {
+	binary string
+	name   string
+	role   string
+	ipv4   string
+	ipv6   string
+}
+
+func (n *node) String() string {
+	return n.name
+}
+
+func (n *node) Role() (string, error) {
+	return n.role, nil
+}
+
+func (n *node) IP() (ipv4 string, ipv6 string, err error) {
+	return n.ipv4, n.ipv6, nil
+}
+
+func (n *node) Command(command string, args ...string) exec.Cmd {
+	return &nodeCmd{
+		binary:  n.binary,
+		node:    n.name,
+		command: command,
+		args:    args,
+	}
+}
+
+func (n *node) CommandContext(ctx context.Context, command string, args ...string) exec.Cmd {
+	return &nodeCmd{
+		binary:  n.binary,
+		node:    n.name,
+		command: command,
+		args:    args,
+		ctx:     ctx,
+	}
+}
+
+// nodeCmd implements exec.Cmd for external provider nodes
+type nodeCmd struct {
+	binary  string
+	node    string
+	command string
+	args    []string
+	env     []string
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	ctx     context.Context
+	tty     bool // allocate a TTY, see SetTTY
+}
+
+func (c *nodeCmd) Run() error {
+	req := external.ExecRequest{
+		Node:    c.node,
+		Command: append([]string{c.command}, c.args...),
+		Env:     c.env,
+		// run with privileges so we can remount etc..
+		// like the other providers
+		Privileged: true,
+	}
+	if c.stdin != nil {
+		req.Stdin = true
+		req.TTY = c.tty
+	}
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var cmd exec.Cmd
+	if c.ctx != nil {
+		cmd = exec.CommandContext(c.ctx, c.binary, external.CommandExec)
+	} else {
+		cmd = exec.Command(c.binary, external.CommandExec)
+	}
+	// the request is passed in the environment as stdin belongs to the
+	// command, the last value of a variable wins if it was already set
+	cmd.SetEnv(append(os.Environ(), external.ExecRequestEnv+"="+string(encoded))...)
+	if c.stdin != nil {
+		cmd.SetStdin(c.stdin)
+	}
+	if c.stderr != nil {
+		cmd.SetStderr(c.stderr)
+	}
+	if c.stdout != nil {
+		cmd.SetStdout(c.stdout)
+	}
+	return cmd.Run()
+}
+
+func (c *nodeCmd) SetEnv(env ...string) exec.Cmd {
+	c.env = env
+	return c
+}
+
+func (c *nodeCmd) SetStdin(r io.Reader) exec.Cmd {
+	c.stdin = r
+	return c
+}
+
+func (c *nodeCmd) SetStdout(w io.Writer) exec.Cmd {
+	c.stdout = w
+	return c
+}
+
+func (c *nodeCmd) SetStderr(w io.Writer) exec.Cmd {
+	c.stderr = w
+	return c
+}
+
+// SetTTY implements exec.TTYCmd, the TTY is only allocated with stdin set
+func (c *nodeCmd) SetTTY(tty bool) exec.Cmd {
+	c.tty = tty
+	return c
+}
+
+func (n *node) SerialLogs(w io.Writer) error {
+	return exec.Command(n.binary, external.CommandLogs, n.name).SetStdout(w).SetStderr(w).Run()
+}
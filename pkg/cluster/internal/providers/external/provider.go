@@ -0,0 +1,298 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package external implements a provider that delegates to an external
+// provider executable, see sigs.k8s.io/kind/pkg/cluster/external
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/external"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	internallogs "sigs.k8s.io/kind/pkg/cluster/internal/logs"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/cli"
+)
+
+// NewProvider returns a new provider based on executing
+// `kind-provider-<name> ...`
+func NewProvider(logger log.Logger, name string) providers.Provider {
+	return &provider{
+		logger: logger,
+		name:   name,
+		binary: external.Prefix + name,
+	}
+}
+
+// Provider implements provider.Provider
+// see NewProvider
+type provider struct {
+	logger log.Logger
+	name   string
+	binary string
+	info   *providers.ProviderInfo
+}
+
+// String implements fmt.Stringer
+// NOTE: the value of this should not currently be relied upon for anything!
+// This is only used for setting the Node's providerID
+func (p *provider) String() string {
+	return p.name
+}
+
+// Provision is part of the providers.Provider interface
+func (p *provider) Provision(ctx context.Context, status *cli.Status, cfg *config.Cluster) (err error) {
+	if cfg.Networking.BridgedNetwork != nil {
+		return errors.New("networking.bridgedNetwork is only supported by the docker provider")
+	}
+	if len(cfg.Networking.AdditionalNetworks) > 0 {
+		return errors.New("networking.additionalNetworks is only supported by the docker provider")
+	}
+	for _, node := range cfg.Nodes {
+		if node.ImageCache {
+			return errors.New("imageCache is only supported by the docker and podman providers")
+		}
+	}
+	req, err := provisionRequest(cfg)
+	if err != nil {
+		return err
+	}
+
+	icons := strings.Repeat("📦 ", len(cfg.Nodes))
+	status.Start(fmt.Sprintf("Preparing nodes %s", icons))
+	defer func() { status.End(err == nil) }()
+
+	cmd := exec.CommandContext(ctx, p.binary, external.CommandProvision)
+	if err := exec.RunWithStdinWriter(cmd, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(req)
+	}); err != nil {
+		return errors.Wrapf(err, "%s failed to provision nodes", p.binary)
+	}
+	return nil
+}
+
+// RunArgs is part of the providers.Provider interface
+func (p *provider) RunArgs(cfg *config.Cluster) (map[string][]string, error) {
+	return nil, p.unsupported("rendering node run arguments")
+}
+
+// ListClusters is part of the providers.Provider interface
+func (p *provider) ListClusters() ([]string, error) {
+	clusters := []string{}
+	if err := p.run(&clusters, external.CommandListClusters); err != nil {
+		return nil, errors.Wrap(err, "failed to list clusters")
+	}
+	return clusters, nil
+}
+
+// ListNodes is part of the providers.Provider interface
+func (p *provider) ListNodes(cluster string) ([]nodes.Node, error) {
+	listed := []external.Node{}
+	if err := p.run(&listed, external.CommandListNodes, cluster); err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	ret := make([]nodes.Node, 0, len(listed))
+	for _, n := range listed {
+		ret = append(ret, &node{
+			binary: p.binary,
+			name:   n.Name,
+			role:   n.Role,
+			ipv4:   n.IPv4,
+			ipv6:   n.IPv6,
+		})
+	}
+	return ret, nil
+}
+
+// DeleteNodes is part of the providers.Provider interface
+func (p *provider) DeleteNodes(n []nodes.Node) error {
+	if len(n) == 0 {
+		return nil
+	}
+	args := make([]string, 0, len(n)+1)
+	args = append(args, external.CommandDeleteNodes)
+	for _, node := range n {
+		args = append(args, node.String())
+	}
+	if err := exec.Command(p.binary, args...).Run(); err != nil {
+		return errors.Wrap(err, "failed to delete nodes")
+	}
+	return nil
+}
+
+// DeleteClusterNetworks is part of the providers.Provider interface
+// No networks are created per cluster by this provider
+func (p *provider) DeleteClusterNetworks(cluster string) error {
+	return nil
+}
+
+// GetAPIServerEndpoint is part of the providers.Provider interface
+func (p *provider) GetAPIServerEndpoint(cluster string) (string, error) {
+	res := &external.EndpointResponse{}
+	if err := p.run(res, external.CommandAPIServerEndpoint, cluster); err != nil {
+		return "", errors.Wrap(err, "failed to get api server endpoint")
+	}
+	return res.Endpoint, nil
+}
+
+// GetAPIServerInternalEndpoint is part of the providers.Provider interface
+func (p *provider) GetAPIServerInternalEndpoint(cluster string) (string, error) {
+	res := &external.EndpointResponse{}
+	if err := p.run(res, external.CommandAPIServerInternalEndpoint, cluster); err != nil {
+		return "", errors.Wrap(err, "failed to get api server internal endpoint")
+	}
+	return res.Endpoint, nil
+}
+
+// CollectLogs will populate dir with cluster logs and other debug files
+func (p *provider) CollectLogs(dir string, nodes []nodes.Node) error {
+	var errs []error
+	fns := []func() error{}
+	for _, n := range nodes {
+		node := n // https://golang.org/doc/faq#closures_and_goroutines
+		path := filepath.Join(dir, node.String())
+		if err := internallogs.DumpDir(p.logger, node, "/var/log", path); err != nil {
+			errs = append(errs, err)
+		}
+		fns = append(fns,
+			func() error { return common.CollectLogs(node, path) },
+			func() error {
+				f, err := common.FileOnHost(filepath.Join(path, "serial.log"))
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				return node.SerialLogs(f)
+			},
+		)
+	}
+	errs = append(errs, errors.AggregateConcurrent(fns))
+	return errors.NewAggregate(errs)
+}
+
+// ListBundledImages is part of the providers.Provider interface
+func (p *provider) ListBundledImages(image string) ([]string, error) {
+	return nil, p.unsupported("listing the images bundled in a node image")
+}
+
+// RunHostNetworkHelper is part of the providers.Provider interface
+func (p *provider) RunHostNetworkHelper(node nodes.Node, command ...string) error {
+	return p.unsupported("running commands in the host network")
+}
+
+// GetNetwork is part of the providers.Provider interface
+func (p *provider) GetNetwork(cluster string) (*providers.Network, error) {
+	return nil, p.unsupported("inspecting the cluster network")
+}
+
+// ContainerID is part of the providers.Provider interface
+func (p *provider) ContainerID(node nodes.Node) (string, error) {
+	// nodes are only known to external providers by name
+	return node.String(), nil
+}
+
+// ListPorts is part of the providers.Provider interface
+func (p *provider) ListPorts(node nodes.Node) ([]providers.PortMapping, error) {
+	return nil, p.unsupported("listing the ports of nodes")
+}
+
+// Events is part of the providers.Provider interface
+func (p *provider) Events(ctx context.Context, cluster string, since time.Time, watch bool, handle func(providers.Event)) error {
+	return p.unsupported("streaming node events")
+}
+
+// CreateHollowNodes is part of the providers.Provider interface
+func (p *provider) CreateHollowNodes(cluster, image, kubeconfig string, names []string) error {
+	return p.unsupported("creating hollow nodes")
+}
+
+// SupportsHollowNodes is part of the providers.Provider interface
+func (p *provider) SupportsHollowNodes() bool {
+	return false
+}
+
+// ListImageCacheVolumes is part of the providers.Provider interface
+func (p *provider) ListImageCacheVolumes() ([]providers.Volume, error) {
+	// image cache volumes are never created by this provider
+	return nil, nil
+}
+
+// DeleteVolumes is part of the providers.Provider interface
+func (p *provider) DeleteVolumes(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	return p.unsupported("deleting volumes")
+}
+
+// IsLocal is part of the providers.Provider interface
+// External providers may run nodes anywhere, e.g. in VMs, so they are not
+// assumed to share the host's paths and ports
+func (p *provider) IsLocal() bool {
+	return false
+}
+
+// Info is part of the providers.Provider interface
+// The info is cached on the first time of the execution.
+func (p *provider) Info() (*providers.ProviderInfo, error) {
+	if p.info != nil {
+		return p.info, nil
+	}
+	res := &external.InfoResponse{}
+	if err := p.run(res, external.CommandInfo); err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s info", p.binary)
+	}
+	if res.APIVersion != external.APIVersion {
+		return nil, errors.Errorf("%s implements %q, but kind requires %q", p.binary, res.APIVersion, external.APIVersion)
+	}
+	p.info = &providers.ProviderInfo{
+		Rootless:            res.Rootless,
+		Cgroup2:             res.Cgroup2,
+		SupportsMemoryLimit: res.SupportsMemoryLimit,
+		SupportsPidsLimit:   res.SupportsPidsLimit,
+		SupportsCPUShares:   res.SupportsCPUShares,
+	}
+	return p.info, nil
+}
+
+// run runs the provider with args and decodes its response into res
+func (p *provider) run(res interface{}, args ...string) error {
+	out, err := exec.Output(exec.Command(p.binary, args...))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, res); err != nil {
+		return errors.Wrapf(err, "failed to decode %s %s response", p.binary, args[0])
+	}
+	return nil
+}
+
+func (p *provider) unsupported(what string) error {
+	return errors.Errorf("%s is not supported by external provider %q", what, p.name)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/external"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// provisionRequest converts cfg to the request sent to the provider
+func provisionRequest(cfg *config.Cluster) (*external.ProvisionRequest, error) {
+	req := &external.ProvisionRequest{
+		Cluster: cfg.Name,
+		Nodes:   make([]external.NodeSpec, 0, len(cfg.Nodes)+1),
+		Networking: external.Networking{
+			IPFamily:         string(cfg.Networking.IPFamily),
+			APIServerAddress: cfg.Networking.APIServerAddress,
+			APIServerPort:    cfg.Networking.APIServerPort,
+		},
+	}
+
+	// name the nodes like the other providers, the load balancer comes last
+	nodeNamer := common.MakeNodeNamer(cfg.Name)
+	for i := range cfg.Nodes {
+		node := &cfg.Nodes[i]
+		name := common.NodeName(nodeNamer, node)
+		spec := external.NodeSpec{
+			Name:              name,
+			Role:              string(node.Role),
+			Image:             node.Image,
+			Labels:            node.Labels,
+			Env:               node.Env,
+			Sysctls:           node.Sysctls,
+			ExtraMounts:       make([]external.Mount, 0, len(node.ExtraMounts)),
+			ExtraPortMappings: make([]external.PortMapping, 0, len(node.ExtraPortMappings)),
+		}
+		for _, m := range node.ExtraMounts {
+			// providers may run elsewhere, so resolve relative paths here
+			hostPath, err := filepath.Abs(m.HostPath)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to resolve absolute path for hostPath: %q", m.HostPath)
+			}
+			spec.ExtraMounts = append(spec.ExtraMounts, external.Mount{
+				HostPath:      hostPath,
+				ContainerPath: m.ContainerPath,
+				ReadOnly:      m.Readonly,
+			})
+		}
+		for _, pm := range node.ExtraPortMappings {
+			spec.ExtraPortMappings = append(spec.ExtraPortMappings, external.PortMapping{
+				ContainerPort: pm.ContainerPort,
+				HostPort:      pm.HostPort,
+				ListenAddress: pm.ListenAddress,
+				Protocol:      string(pm.Protocol),
+			})
+		}
+		req.Nodes = append(req.Nodes, spec)
+	}
+	if config.ClusterHasImplicitLoadBalancer(cfg) {
+		req.Nodes = append(req.Nodes, external.NodeSpec{
+			Name:  nodeNamer(constants.ExternalLoadBalancerNodeRoleValue),
+			Role:  constants.ExternalLoadBalancerNodeRoleValue,
+			Image: loadbalancer.Image,
+		})
+	}
+	return req, nil
+}
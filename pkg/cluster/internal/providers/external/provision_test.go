@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/external"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/loadbalancer"
+)
+
+func TestProvisionRequest(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Cluster{
+		Name: "dev",
+		Nodes: []config.Node{
+			{
+				Role:  config.ControlPlaneRole,
+				Image: "kindest/node:v1.31.0",
+				ExtraPortMappings: []config.PortMapping{
+					{ContainerPort: 80, HostPort: 8080, Protocol: config.PortMappingProtocolTCP},
+				},
+			},
+			{
+				Role:  config.ControlPlaneRole,
+				Image: "kindest/node:v1.31.0",
+			},
+			{
+				Role:        config.WorkerRole,
+				Image:       "kindest/node:v1.31.0",
+				Labels:      map[string]string{"tier": "worker"},
+				ExtraMounts: []config.Mount{{HostPath: "/data", ContainerPath: "/data", Readonly: true}},
+			},
+		},
+		Networking: config.Networking{
+			IPFamily:         config.IPv4Family,
+			APIServerAddress: "127.0.0.1",
+			APIServerPort:    6443,
+		},
+	}
+	req, err := provisionRequest(cfg)
+	assert.ExpectError(t, false, err)
+	assert.DeepEqual(t, &external.ProvisionRequest{
+		Cluster: "dev",
+		Nodes: []external.NodeSpec{
+			{
+				Name:        "dev-control-plane",
+				Role:        "control-plane",
+				Image:       "kindest/node:v1.31.0",
+				ExtraMounts: []external.Mount{},
+				ExtraPortMappings: []external.PortMapping{
+					{ContainerPort: 80, HostPort: 8080, Protocol: "TCP"},
+				},
+			},
+			{
+				Name:              "dev-control-plane2",
+				Role:              "control-plane",
+				Image:             "kindest/node:v1.31.0",
+				ExtraMounts:       []external.Mount{},
+				ExtraPortMappings: []external.PortMapping{},
+			},
+			{
+				Name:              "dev-worker",
+				Role:              "worker",
+				Image:             "kindest/node:v1.31.0",
+				Labels:            map[string]string{"tier": "worker"},
+				ExtraMounts:       []external.Mount{{HostPath: "/data", ContainerPath: "/data", ReadOnly: true}},
+				ExtraPortMappings: []external.PortMapping{},
+			},
+			{
+				Name:  "dev-external-load-balancer",
+				Role:  "external-load-balancer",
+				Image: loadbalancer.Image,
+			},
+		},
+		Networking: external.Networking{
+			IPFamily:         "ipv4",
+			APIServerAddress: "127.0.0.1",
+			APIServerPort:    6443,
+		},
+	}, req)
+}
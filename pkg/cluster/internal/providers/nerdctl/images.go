@@ -17,6 +17,7 @@ limitations under the License.
 package nerdctl
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -32,24 +33,36 @@ import (
 
 // ensureNodeImages ensures that the node images used by the create
 // configuration are present
-func ensureNodeImages(logger log.Logger, status *cli.Status, cfg *config.Cluster, binaryName string) error {
-	// pull each required image
-	for _, image := range common.RequiredNodeImages(cfg).List() {
-		// prints user friendly message
-		friendlyImageName, image := sanitizeImage(image)
-		status.Start(fmt.Sprintf("Ensuring node image (%s) 🖼", friendlyImageName))
-		if _, err := pullIfNotPresent(logger, image, 4, binaryName); err != nil {
-			status.End(false)
+func ensureNodeImages(ctx context.Context, logger log.Logger, status *cli.Status, cfg *config.Cluster, binaryName string) error {
+	opts := common.ImagePullOptionsForConfig(cfg)
+	images := common.RequiredNodeImages(cfg).List()
+	friendlyImageNames := make([]string, len(images))
+	for i := range images {
+		friendlyImageNames[i], images[i] = sanitizeImage(images[i])
+	}
+	// prints user friendly message
+	status.Start(fmt.Sprintf("Ensuring node image (%s) 🖼", strings.Join(friendlyImageNames, ", ")))
+	// pull the required images concurrently, these are large and a cluster
+	// with mixed node versions would otherwise pull them one at a time
+	fns := make([]func() error, 0, len(images))
+	for _, image := range images {
+		image := image // capture loop variable
+		fns = append(fns, func() error {
+			_, err := pullIfNotPresent(ctx, logger, image, opts, binaryName)
 			return err
-		}
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		status.End(false)
+		return err
 	}
 	return nil
 }
 
 // pullIfNotPresent will pull an image if it is not present locally
-// retrying up to retries times
+// retrying as configured by opts
 // it returns true if it attempted to pull, and any errors from pulling
-func pullIfNotPresent(logger log.Logger, image string, retries int, binaryName string) (pulled bool, err error) {
+func pullIfNotPresent(ctx context.Context, logger log.Logger, image string, opts common.ImagePullOptions, binaryName string) (pulled bool, err error) {
 	// TODO(bentheelder): switch most (all) of the logging here to debug level
 	// once we have configurable log levels
 	// if this did not return an error, then the image exists locally
@@ -59,20 +72,26 @@ func pullIfNotPresent(logger log.Logger, image string, retries int, binaryName s
 		return false, nil
 	}
 	// otherwise try to pull it
-	return true, pull(logger, image, retries, binaryName)
+	return true, pull(ctx, logger, image, opts, binaryName)
 }
 
-// pull pulls an image, retrying up to retries times
-func pull(logger log.Logger, image string, retries int, binaryName string) error {
+// pull pulls an image, retrying with a linear backoff as configured by opts
+func pull(ctx context.Context, logger log.Logger, image string, opts common.ImagePullOptions, binaryName string) error {
 	logger.V(1).Infof("Pulling image: %s ...", image)
-	err := exec.Command(binaryName, "pull", image).Run()
+	pullOnce := func() error {
+		return common.RunWithTimeout(ctx, opts.Timeout, "imagePull", func(ctx context.Context) error {
+			return exec.CommandContext(ctx, binaryName, "pull", image).Run()
+		})
+	}
+	err := pullOnce()
 	// retry pulling up to retries times if necessary
 	if err != nil {
-		for i := 0; i < retries; i++ {
-			time.Sleep(time.Second * time.Duration(i+1))
+		for i := 0; i < opts.Retries; i++ {
+			if err := common.Sleep(ctx, opts.Backoff*time.Duration(i+1)); err != nil {
+				return errors.Wrapf(err, "failed to pull image %q", image)
+			}
 			logger.V(1).Infof("Trying again to pull image: %q ... %v", image, err)
-			// TODO(bentheelder): add some backoff / sleep?
-			err = exec.Command(binaryName, "pull", image).Run()
+			err = pullOnce()
 			if err == nil {
 				break
 			}
@@ -89,3 +108,19 @@ func sanitizeImage(image string) (string, string) {
 	}
 	return image, image
 }
+
+// listBundledImages returns the images preloaded in the given node image
+func listBundledImages(logger log.Logger, image, binaryName string) ([]string, error) {
+	_, image = sanitizeImage(image)
+	if _, err := pullIfNotPresent(context.Background(), logger, image, common.DefaultImagePullOptions, binaryName); err != nil {
+		return nil, err
+	}
+	lines, err := exec.OutputLines(exec.Command(
+		binaryName, "run", "--rm", "--entrypoint=/bin/sh", image,
+		"-c", common.BundledImagesScript,
+	))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list images bundled in %q", image)
+	}
+	return common.ParseBundledImages(lines).List(), nil
+}
@@ -19,6 +19,7 @@ package nerdctl
 import (
 	"crypto/sha1"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"net"
 	"strconv"
@@ -26,6 +27,8 @@ import (
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
 )
 
 // This may be overridden by KIND_EXPERIMENTAL_DOCKER_NETWORK env,
@@ -156,6 +159,43 @@ func checkIfNetworkExists(name, binaryName string) (bool, error) {
 	return strings.HasPrefix(string(out), name), err
 }
 
+type networkInspectEntry struct {
+	Name string `json:"Name"`
+	IPAM struct {
+		Config []struct {
+			Subnet  string `json:"Subnet"`
+			Gateway string `json:"Gateway"`
+		} `json:"Config"`
+	} `json:"IPAM"`
+	Options map[string]string `json:"Options"`
+}
+
+// inspectNetwork returns the details of the network name
+func inspectNetwork(name, binaryName string) (*providers.Network, error) {
+	out, err := exec.Output(exec.Command(binaryName, "network", "inspect", name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to inspect network %q", name)
+	}
+	networks := []networkInspectEntry{}
+	if err := json.Unmarshal(out, &networks); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode network %q", name)
+	}
+	if len(networks) != 1 {
+		return nil, errors.Errorf("failed to inspect network %q", name)
+	}
+	n := networks[0]
+	network := &providers.Network{Name: n.Name}
+	for _, c := range n.IPAM.Config {
+		network.Subnets = append(network.Subnets, c.Subnet)
+		if c.Gateway != "" {
+			network.Gateways = append(network.Gateways, c.Gateway)
+		}
+	}
+	// an unset or invalid MTU is left as the default
+	network.MTU, _ = strconv.Atoi(n.Options["com.docker.network.driver.mtu"])
+	return network, nil
+}
+
 func isIPv6UnavailableError(err error) bool {
 	rerr := exec.RunErrorForError(err)
 	return rerr != nil && strings.HasPrefix(string(rerr.Output), "Error response from daemon: Cannot read IPv6 setup for bridge")
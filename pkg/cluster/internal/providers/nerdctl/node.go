@@ -101,6 +101,7 @@ type nodeCmd struct {
 	stdout     io.Writer
 	stderr     io.Writer
 	ctx        context.Context
+	tty        bool // allocate a TTY, see SetTTY
 }
 
 func (c *nodeCmd) Run() error {
@@ -115,6 +116,11 @@ func (c *nodeCmd) Run() error {
 		args = append(args,
 			"-i", // interactive so we can supply input
 		)
+		if c.tty {
+			args = append(args,
+				"-t", // allocate a TTY for interactive shells
+			)
+		}
 	}
 	// set env
 	for _, env := range c.env {
@@ -170,6 +176,12 @@ func (c *nodeCmd) SetStderr(w io.Writer) exec.Cmd {
 	return c
 }
 
+// SetTTY implements exec.TTYCmd, the TTY is only allocated with stdin set
+func (c *nodeCmd) SetTTY(tty bool) exec.Cmd {
+	c.tty = tty
+	return c
+}
+
 func (n *node) SerialLogs(w io.Writer) error {
 	return exec.Command(n.binaryName, "logs", n.name).SetStdout(w).SetStderr(w).Run()
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package nerdctl
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 	osexec "os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/errors"
@@ -83,10 +85,21 @@ func (p *provider) Binary() string {
 }
 
 // Provision is part of the providers.Provider interface
-func (p *provider) Provision(status *cli.Status, cfg *config.Cluster) (err error) {
+func (p *provider) Provision(ctx context.Context, status *cli.Status, cfg *config.Cluster) (err error) {
+	if cfg.Networking.BridgedNetwork != nil {
+		return errors.New("networking.bridgedNetwork is only supported by the docker provider")
+	}
+	if len(cfg.Networking.AdditionalNetworks) > 0 {
+		return errors.New("networking.additionalNetworks is only supported by the docker provider")
+	}
+	for _, node := range cfg.Nodes {
+		if node.ImageCache {
+			return errors.New("imageCache is only supported by the docker and podman providers")
+		}
+	}
 	// TODO: validate cfg
 	// ensure node images are pulled before actually provisioning
-	if err := ensureNodeImages(p.logger, status, cfg, p.Binary()); err != nil {
+	if err := ensureNodeImages(ctx, p.logger, status, cfg, p.Binary()); err != nil {
 		return err
 	}
 
@@ -117,6 +130,11 @@ func (p *provider) Provision(status *cli.Status, cfg *config.Cluster) (err error
 	return nil
 }
 
+// RunArgs is part of the providers.Provider interface
+func (p *provider) RunArgs(cfg *config.Cluster) (map[string][]string, error) {
+	return planRunArgs(cfg, fixedNetworkName, p.Binary())
+}
+
 // ListClusters is part of the providers.Provider interface
 func (p *provider) ListClusters() ([]string, error) {
 	cmd := exec.Command(p.Binary(),
@@ -201,6 +219,12 @@ func (p *provider) DeleteNodes(n []nodes.Node) error {
 	return nil
 }
 
+// DeleteClusterNetworks is part of the providers.Provider interface
+// No networks are created per cluster by this provider
+func (p *provider) DeleteClusterNetworks(cluster string) error {
+	return nil
+}
+
 // GetAPIServerEndpoint is part of the providers.Provider interface
 func (p *provider) GetAPIServerEndpoint(cluster string) (string, error) {
 	// locate the node that hosts this
@@ -332,6 +356,99 @@ func (p *provider) CollectLogs(dir string, nodes []nodes.Node) error {
 	return errors.NewAggregate(errs)
 }
 
+// ListBundledImages returns the images preloaded in the given node image
+func (p *provider) ListBundledImages(image string) ([]string, error) {
+	return listBundledImages(p.logger, image, p.Binary())
+}
+
+// RunHostNetworkHelper runs command in a short-lived privileged container
+// in the host's network namespace, using node's image
+func (p *provider) RunHostNetworkHelper(node nodes.Node, command ...string) error {
+	lines, err := exec.OutputLines(exec.Command(
+		p.binaryName, "inspect", "--format={{.Config.Image}}", node.String(),
+	))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get image of node %q", node)
+	}
+	if len(lines) != 1 {
+		return errors.Errorf("failed to get image of node %q: unexpected output %v", node, lines)
+	}
+	args := []string{
+		"run", "--rm", "--privileged", "--network=host",
+		"--entrypoint=" + command[0], lines[0],
+	}
+	args = append(args, command[1:]...)
+	return exec.Command(p.binaryName, args...).Run()
+}
+
+// GetNetwork is part of the providers.Provider interface
+func (p *provider) GetNetwork(cluster string) (*providers.Network, error) {
+	// nodes are always attached to the fixed network
+	return inspectNetwork(fixedNetworkName, p.Binary())
+}
+
+// ContainerID is part of the providers.Provider interface
+func (p *provider) ContainerID(node nodes.Node) (string, error) {
+	lines, err := exec.OutputLines(exec.Command(p.binaryName, "inspect", "--format", "{{.Id}}", node.String()))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get container ID of %s", node.String())
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("expected one line of output for the container ID of %s, got %d", node.String(), len(lines))
+	}
+	return lines[0], nil
+}
+
+// ListPorts is part of the providers.Provider interface
+func (p *provider) ListPorts(node nodes.Node) ([]providers.PortMapping, error) {
+	lines, err := exec.OutputLines(exec.Command(p.binaryName, "port", node.String()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list ports of %s", node.String())
+	}
+	return common.ParsePorts(lines)
+}
+
+// CreateHollowNodes is part of the providers.Provider interface
+func (p *provider) CreateHollowNodes(cluster, image, kubeconfig string, names []string) error {
+	return errors.Errorf("hollow nodes are not supported by %s", p.binaryName)
+}
+
+// SupportsHollowNodes is part of the providers.Provider interface
+func (p *provider) SupportsHollowNodes() bool {
+	return false
+}
+
+// ListImageCacheVolumes is part of the providers.Provider interface
+func (p *provider) ListImageCacheVolumes() ([]providers.Volume, error) {
+	// image cache volumes are never created by this provider
+	return nil, nil
+}
+
+// DeleteVolumes is part of the providers.Provider interface
+func (p *provider) DeleteVolumes(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	args := append([]string{"volume", "rm"}, names...)
+	if err := exec.Command(p.binaryName, args...).Run(); err != nil {
+		return errors.Wrap(err, "failed to delete volumes")
+	}
+	return nil
+}
+
+// Events is part of the providers.Provider interface
+func (p *provider) Events(ctx context.Context, cluster string, since time.Time, watch bool, handle func(providers.Event)) error {
+	// nerdctl events only reports raw containerd task events, which are
+	// keyed by container ID and cannot be filtered by label
+	return errors.Errorf("streaming container events is not supported by %s", p.binaryName)
+}
+
+// IsLocal is part of the providers.Provider interface
+// nerdctl always runs containers on this host
+func (p *provider) IsLocal() bool {
+	return true
+}
+
 // Info returns the provider info.
 // The info is cached on the first time of the execution.
 func (p *provider) Info() (*providers.ProviderInfo, error) {
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// Events is part of the providers.Provider interface
+func (p *provider) Events(ctx context.Context, cluster string, since time.Time, watch bool, handle func(providers.Event)) error {
+	args := []string{
+		"events",
+		"--filter", "type=container",
+		"--filter", "label=" + clusterLabelKey + "=" + cluster,
+		"--format", "json",
+	}
+	if !since.IsZero() {
+		args = append(args, "--since", since.Format(time.RFC3339))
+	}
+	if !watch {
+		args = append(args, "--stream=false")
+	}
+	return common.StreamEvents(ctx, "podman", args, parseEvent, handle)
+}
+
+// podmanEvent is the subset of `podman events --format json` we use
+type podmanEvent struct {
+	Name              string            `json:"Name"`
+	Status            string            `json:"Status"`
+	Time              time.Time         `json:"Time"`
+	Type              string            `json:"Type"`
+	Attributes        map[string]string `json:"Attributes"`
+	ContainerExitCode int               `json:"ContainerExitCode"`
+}
+
+// podmanEventActions maps podman event statuses to providers.Event actions,
+// other statuses such as "exec" or "cleanup" are ignored
+var podmanEventActions = map[string]string{
+	"start":   providers.EventStart,
+	"stop":    providers.EventStop,
+	"kill":    providers.EventKill,
+	"died":    providers.EventDie,
+	"oom":     providers.EventOOM,
+	"restart": providers.EventRestart,
+}
+
+// parseEvent parses one line of `podman events --format json`
+func parseEvent(line string) (providers.Event, bool, error) {
+	var e podmanEvent
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		return providers.Event{}, false, err
+	}
+	action, ok := podmanEventActions[e.Status]
+	if e.Type != "container" || !ok {
+		return providers.Event{}, false, nil
+	}
+	event := providers.Event{
+		Time:   e.Time,
+		Node:   e.Name,
+		Role:   e.Attributes[nodeRoleLabelKey],
+		Action: action,
+	}
+	if action == providers.EventDie {
+		event.ExitCode = e.ContainerExitCode
+	}
+	return event, true, nil
+}
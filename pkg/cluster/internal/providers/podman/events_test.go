@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func Test_parseEvent(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		line        string
+		event       providers.Event
+		ok          bool
+		expectError bool
+	}{
+		{
+			name: "died",
+			line: `{"ID":"abc","Image":"kindest/node","Name":"kind-worker","Status":"died","Time":"2023-11-14T22:13:20.5Z","Type":"container","Attributes":{"io.x-k8s.kind.cluster":"kind","io.x-k8s.kind.role":"worker"},"ContainerExitCode":137}`,
+			event: providers.Event{
+				Time:     time.Date(2023, 11, 14, 22, 13, 20, 500000000, time.UTC),
+				Node:     "kind-worker",
+				Role:     "worker",
+				Action:   providers.EventDie,
+				ExitCode: 137,
+			},
+			ok: true,
+		},
+		{
+			name: "restart",
+			line: `{"Name":"kind-control-plane","Status":"restart","Time":"2023-11-14T22:13:20Z","Type":"container","Attributes":{"io.x-k8s.kind.role":"control-plane"}}`,
+			event: providers.Event{
+				Time:   time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC),
+				Node:   "kind-control-plane",
+				Role:   "control-plane",
+				Action: providers.EventRestart,
+			},
+			ok: true,
+		},
+		{
+			name: "uninteresting status",
+			line: `{"Name":"kind-control-plane","Status":"cleanup","Time":"2023-11-14T22:13:20Z","Type":"container"}`,
+		},
+		{
+			name:        "not json",
+			line:        "Error: no such socket",
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			event, ok, err := parseEvent(tc.line)
+			assert.ExpectError(t, tc.expectError, err)
+			assert.BoolEqual(t, tc.ok, ok)
+			assert.DeepEqual(t, tc.event, event)
+		})
+	}
+}
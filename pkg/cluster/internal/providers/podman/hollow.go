@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// CreateHollowNodes is part of the providers.Provider interface
+func (p *provider) CreateHollowNodes(cluster, image, kubeconfig string, names []string) error {
+	network, err := p.GetNetwork(cluster)
+	if err != nil {
+		return err
+	}
+	// the kubeconfig is copied into each container before it starts
+	f, err := os.CreateTemp("", "kind-hollow-kubeconfig")
+	if err != nil {
+		return errors.Wrap(err, "failed to create kubeconfig file")
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(kubeconfig); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to write kubeconfig file")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to write kubeconfig file")
+	}
+	return common.CreateHollowNodes(names, func(name string) error {
+		args := []string{
+			"create", "--name", name,
+			"--hostname", name, // make hostname match container name
+			"--label", fmt.Sprintf("%s=%s", clusterLabelKey, cluster),
+			"--label", fmt.Sprintf("%s=%s", nodeRoleLabelKey, constants.HollowNodeRoleValue),
+			"--net", network.Name,
+			// the hollow kubelet mounts filesystems like a real kubelet
+			"--privileged",
+			// the hollow kubelet exits if it cannot reach the API server
+			"--restart=on-failure",
+			"--entrypoint=" + common.HollowNodeEntrypoint,
+			image,
+		}
+		args = append(args, common.HollowNodeArgs(name)...)
+		if err := exec.Command("podman", args...).Run(); err != nil {
+			return errors.Wrapf(err, "failed to create hollow node %q", name)
+		}
+		if err := exec.Command("podman", "cp", f.Name(), name+":"+common.HollowNodeKubeconfigPath).Run(); err != nil {
+			return errors.Wrapf(err, "failed to copy kubeconfig to hollow node %q", name)
+		}
+		if err := exec.Command("podman", "start", name).Run(); err != nil {
+			return errors.Wrapf(err, "failed to start hollow node %q", name)
+		}
+		return nil
+	})
+}
+
+// SupportsHollowNodes is part of the providers.Provider interface
+func (p *provider) SupportsHollowNodes() bool {
+	return true
+}
@@ -19,12 +19,17 @@ package podman
 import (
 	"crypto/sha1"
 	"encoding/binary"
+	"encoding/json"
 	"net"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
 )
 
 // This may be overridden by KIND_EXPERIMENTAL_PODMAN_NETWORK env,
@@ -40,6 +45,14 @@ import (
 // networks.
 const fixedNetworkName = "kind"
 
+// clusterNetworkName returns the network new nodes are attached to
+func clusterNetworkName() string {
+	if n := os.Getenv("KIND_EXPERIMENTAL_PODMAN_NETWORK"); n != "" {
+		return n
+	}
+	return fixedNetworkName
+}
+
 // ensureNetwork creates a new network
 // podman only creates IPv6 networks for versions >= 2.2.0
 func ensureNetwork(name string) error {
@@ -102,6 +115,68 @@ func checkIfNetworkExists(name string) bool {
 	return err == nil
 }
 
+// nodeNetworkName returns the name of the network node is attached to
+func nodeNetworkName(node string) (string, error) {
+	lines, err := exec.OutputLines(exec.Command(
+		"podman", "inspect",
+		"--format", `{{range $name, $_ := .NetworkSettings.Networks}}{{$name}}{{"\n"}}{{end}}`,
+		node,
+	))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get network of node %q", node)
+	}
+	for _, line := range lines {
+		if line != "" {
+			return line, nil
+		}
+	}
+	return "", errors.Errorf("node %q is not attached to a network", node)
+}
+
+// inspectNetwork returns the details of the network name
+func inspectNetwork(name string) (*providers.Network, error) {
+	out, err := exec.Output(exec.Command("podman", "network", "inspect", name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to inspect network %q", name)
+	}
+	networks := podmanNetworks{}
+	if err := json.Unmarshal(out, &networks); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode network %q", name)
+	}
+	if len(networks) != 1 {
+		return nil, errors.Errorf("failed to inspect network %q", name)
+	}
+	return parseNetwork(networks), nil
+}
+
+// parseNetwork returns the details of the single inspected network
+func parseNetwork(networks podmanNetworks) *providers.Network {
+	n := networks[0]
+	network := &providers.Network{Name: n.Name}
+	for _, subnet := range n.Subnets {
+		network.Subnets = append(network.Subnets, subnet.Subnet)
+		if subnet.Gateway != "" {
+			network.Gateways = append(network.Gateways, subnet.Gateway)
+		}
+	}
+	// an unset or invalid MTU is left as the default
+	network.MTU, _ = strconv.Atoi(n.Options["mtu"])
+	for _, plugin := range n.Plugins {
+		if plugin.MTU != 0 {
+			network.MTU = plugin.MTU
+		}
+		for _, r := range plugin.Ipam.Ranges {
+			for _, rr := range r {
+				network.Subnets = append(network.Subnets, rr.Subnet)
+				if rr.Gateway != "" {
+					network.Gateways = append(network.Gateways, rr.Gateway)
+				}
+			}
+		}
+	}
+	return network
+}
+
 func isUnknownIPv6FlagError(err error) bool {
 	rerr := exec.RunErrorForError(err)
 	return rerr != nil &&
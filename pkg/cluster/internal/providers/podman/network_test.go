@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import (
+	"encoding/json"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func Test_parseNetwork(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Inspect  string
+		Expected *providers.Network
+	}{
+		{
+			Name: "netavark",
+			Inspect: `[{"name": "kind", "driver": "bridge",
+				"subnets": [
+					{"subnet": "10.89.0.0/24", "gateway": "10.89.0.1"},
+					{"subnet": "fc00:f853:ccd:e793::/64", "gateway": "fc00:f853:ccd:e793::1"}
+				],
+				"options": {"mtu": "1400"}}]`,
+			Expected: &providers.Network{
+				Name:     "kind",
+				Subnets:  []string{"10.89.0.0/24", "fc00:f853:ccd:e793::/64"},
+				Gateways: []string{"10.89.0.1", "fc00:f853:ccd:e793::1"},
+				MTU:      1400,
+			},
+		},
+		{
+			Name: "cni",
+			Inspect: `[{"name": "kind", "plugins": [
+				{"type": "bridge", "mtu": 1450, "ipam": {"ranges": [[{"subnet": "10.89.0.0/24", "gateway": "10.89.0.1"}]]}},
+				{"type": "portmap"}
+			]}]`,
+			Expected: &providers.Network{
+				Name:     "kind",
+				Subnets:  []string{"10.89.0.0/24"},
+				Gateways: []string{"10.89.0.1"},
+				MTU:      1450,
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			networks := podmanNetworks{}
+			if err := json.Unmarshal([]byte(tc.Inspect), &networks); err != nil {
+				t.Fatalf("failed to decode network: %v", err)
+			}
+			assert.DeepEqual(t, tc.Expected, parseNetwork(networks))
+		})
+	}
+}
@@ -97,6 +97,7 @@ type nodeCmd struct {
 	stdout   io.Writer
 	stderr   io.Writer
 	ctx      context.Context
+	tty      bool // allocate a TTY, see SetTTY
 }
 
 func (c *nodeCmd) Run() error {
@@ -111,6 +112,11 @@ func (c *nodeCmd) Run() error {
 		args = append(args,
 			"-i", // interactive so we can supply input
 		)
+		if c.tty {
+			args = append(args,
+				"-t", // allocate a TTY for interactive shells
+			)
+		}
 	}
 	// set env
 	for _, env := range c.env {
@@ -166,6 +172,31 @@ func (c *nodeCmd) SetStderr(w io.Writer) exec.Cmd {
 	return c
 }
 
+// SetTTY implements exec.TTYCmd, the TTY is only allocated with stdin set
+func (c *nodeCmd) SetTTY(tty bool) exec.Cmd {
+	c.tty = tty
+	return c
+}
+
 func (n *node) SerialLogs(w io.Writer) error {
 	return exec.Command("podman", "logs", n.name).SetStdout(w).SetStderr(w).Run()
 }
+
+// HasFile returns true if path exists in the node. The file is copied out of
+// the container rather than tested with exec, so this works on stopped nodes.
+func (n *node) HasFile(path string) (bool, error) {
+	err := exec.Command("podman", "cp", n.name+":"+path, "-").SetStdout(io.Discard).Run()
+	if err == nil {
+		return true, nil
+	}
+	if runErr := exec.RunErrorForError(err); runErr != nil && isFileNotFound(runErr.Output) {
+		return false, nil
+	}
+	return false, errors.Wrapf(err, "failed to check for %q in node", path)
+}
+
+// isFileNotFound returns true if the output of podman cp reports that the
+// source file does not exist, as opposed to the container or podman failing
+func isFileNotFound(output []byte) bool {
+	return strings.Contains(string(output), "could not be found on container")
+}
@@ -17,10 +17,10 @@ limitations under the License.
 package podman
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -43,6 +43,7 @@ import (
 // NewProvider returns a new provider based on executing `podman ...`
 func NewProvider(logger log.Logger) providers.Provider {
 	logger.Warn("enabling experimental podman provider")
+	activateService(logger)
 	return &provider{
 		logger: logger,
 	}
@@ -63,23 +64,28 @@ func (p *provider) String() string {
 }
 
 // Provision is part of the providers.Provider interface
-func (p *provider) Provision(status *cli.Status, cfg *config.Cluster) (err error) {
+func (p *provider) Provision(ctx context.Context, status *cli.Status, cfg *config.Cluster) (err error) {
 	if err := ensureMinVersion(); err != nil {
 		return err
 	}
+	if cfg.Networking.BridgedNetwork != nil {
+		return errors.New("networking.bridgedNetwork is only supported by the docker provider")
+	}
+	if len(cfg.Networking.AdditionalNetworks) > 0 {
+		return errors.New("networking.additionalNetworks is only supported by the docker provider")
+	}
 
 	// TODO: validate cfg
 	// ensure node images are pulled before actually provisioning
-	if err := ensureNodeImages(p.logger, status, cfg); err != nil {
+	if err := ensureNodeImages(ctx, p.logger, status, cfg); err != nil {
 		return err
 	}
 
 	// ensure the pre-requisite network exists
-	networkName := fixedNetworkName
-	if n := os.Getenv("KIND_EXPERIMENTAL_PODMAN_NETWORK"); n != "" {
+	networkName := clusterNetworkName()
+	if networkName != fixedNetworkName {
 		p.logger.Warn("WARNING: Overriding podman network due to KIND_EXPERIMENTAL_PODMAN_NETWORK")
 		p.logger.Warn("WARNING: Here be dragons! This is not supported currently.")
-		networkName = n
 	}
 	if err := ensureNetwork(networkName); err != nil {
 		return errors.Wrap(err, "failed to ensure podman network")
@@ -100,6 +106,11 @@ func (p *provider) Provision(status *cli.Status, cfg *config.Cluster) (err error
 	return errors.UntilErrorConcurrent(createContainerFuncs)
 }
 
+// RunArgs is part of the providers.Provider interface
+func (p *provider) RunArgs(cfg *config.Cluster) (map[string][]string, error) {
+	return planRunArgs(cfg, clusterNetworkName())
+}
+
 // ListClusters is part of the providers.Provider interface
 func (p *provider) ListClusters() ([]string, error) {
 	cmd := exec.Command("podman",
@@ -180,6 +191,12 @@ func getHostIPOrDefault(hostIP string) string {
 	return hostIP
 }
 
+// DeleteClusterNetworks is part of the providers.Provider interface
+// No networks are created per cluster by this provider
+func (p *provider) DeleteClusterNetworks(cluster string) error {
+	return nil
+}
+
 // GetAPIServerEndpoint is part of the providers.Provider interface
 func (p *provider) GetAPIServerEndpoint(cluster string) (string, error) {
 	// locate the node that hosts this
@@ -365,6 +382,73 @@ func (p *provider) CollectLogs(dir string, nodes []nodes.Node) error {
 	return errors.NewAggregate(errs)
 }
 
+// ListBundledImages returns the images preloaded in the given node image
+func (p *provider) ListBundledImages(image string) ([]string, error) {
+	return listBundledImages(p.logger, image)
+}
+
+// RunHostNetworkHelper runs command in a short-lived privileged container
+// in the host's network namespace, using node's image
+func (p *provider) RunHostNetworkHelper(node nodes.Node, command ...string) error {
+	lines, err := exec.OutputLines(exec.Command(
+		"podman", "inspect", "--format={{.Config.Image}}", node.String(),
+	))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get image of node %q", node)
+	}
+	if len(lines) != 1 {
+		return errors.Errorf("failed to get image of node %q: unexpected output %v", node, lines)
+	}
+	args := []string{
+		"run", "--rm", "--privileged", "--network=host",
+		"--entrypoint=" + command[0], lines[0],
+	}
+	args = append(args, command[1:]...)
+	return exec.Command("podman", args...).Run()
+}
+
+// GetNetwork is part of the providers.Provider interface
+func (p *provider) GetNetwork(cluster string) (*providers.Network, error) {
+	n, err := p.ListNodes(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	if len(n) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", cluster)
+	}
+	name, err := nodeNetworkName(n[0].String())
+	if err != nil {
+		return nil, err
+	}
+	return inspectNetwork(name)
+}
+
+// ContainerID is part of the providers.Provider interface
+func (p *provider) ContainerID(node nodes.Node) (string, error) {
+	lines, err := exec.OutputLines(exec.Command("podman", "inspect", "--format", "{{.Id}}", node.String()))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get container ID of %s", node.String())
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("expected one line of output for the container ID of %s, got %d", node.String(), len(lines))
+	}
+	return lines[0], nil
+}
+
+// ListPorts is part of the providers.Provider interface
+func (p *provider) ListPorts(node nodes.Node) ([]providers.PortMapping, error) {
+	lines, err := exec.OutputLines(exec.Command("podman", "port", node.String()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list ports of %s", node.String())
+	}
+	return common.ParsePorts(lines)
+}
+
+// IsLocal is part of the providers.Provider interface
+func (p *provider) IsLocal() bool {
+	return isLocal()
+}
+
 // Info returns the provider info.
 // The info is cached on the first time of the execution.
 func (p *provider) Info() (*providers.ProviderInfo, error) {
@@ -34,19 +34,76 @@ import (
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
 
+// container is a node container planned by planContainers
+type container struct {
+	name string
+	// args returns the container's run args, this may pick random host ports
+	args func() ([]string, error)
+	// fixedHostPorts are the host ports set in the config, which are not
+	// picked again when retrying host port conflicts
+	fixedHostPorts []int32
+	// create creates the container with args
+	create func(args []string) error
+}
+
 // planCreation creates a slice of funcs that will create the containers
 func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs []func() error, err error) {
+	containers, err := planContainers(cfg, networkName, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range containers {
+		c := c // capture c
+		createContainerFuncs = append(createContainerFuncs, func() error {
+			// random host ports may be taken before podman binds them,
+			// in which case we retry with new ones
+			return common.RetryOnHostPortConflict(c.fixedHostPorts, func() error {
+				args, err := c.args()
+				if err != nil {
+					return err
+				}
+				return c.create(args)
+			}, func() error {
+				return deleteContainer(c.name)
+			})
+		})
+	}
+	return createContainerFuncs, nil
+}
+
+// planRunArgs returns the run args of each container planCreation would
+// create by name, without creating anything or picking random host ports
+func planRunArgs(cfg *config.Cluster, networkName string) (map[string][]string, error) {
+	containers, err := planContainers(common.WithoutRandomHostPorts(cfg), networkName, -1)
+	if err != nil {
+		return nil, err
+	}
+	runArgs := make(map[string][]string, len(containers))
+	for _, c := range containers {
+		args, err := c.args()
+		if err != nil {
+			return nil, err
+		}
+		runArgs[c.name] = args
+	}
+	return runArgs, nil
+}
+
+// planContainers plans the cluster's containers, randomHostPort is the
+// control planes' API server host port when there is a load balancer,
+// 0 for kind to pick a random port or -1 to leave it to podman
+func planContainers(cfg *config.Cluster, networkName string, randomHostPort int32) (containers []container, err error) {
 	// these apply to all container creation
 	nodeNamer := common.MakeNodeNamer(cfg.Name)
 	names := make([]string, len(cfg.Nodes))
-	for i, node := range cfg.Nodes {
-		name := nodeNamer(string(node.Role)) // name the node
-		names[i] = name
+	for i := range cfg.Nodes {
+		names[i] = common.NodeName(nodeNamer, &cfg.Nodes[i]) // name the node
 	}
 	haveLoadbalancer := config.ClusterHasImplicitLoadBalancer(cfg)
 	if haveLoadbalancer {
 		names = append(names, nodeNamer(constants.ExternalLoadBalancerNodeRoleValue))
 	}
+
 	genericArgs, err := commonArgs(cfg, networkName, names)
 	if err != nil {
 		return nil, err
@@ -55,13 +112,13 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 	// only the external LB should reflect the port if we have multiple control planes
 	apiServerPort := cfg.Networking.APIServerPort
 	apiServerAddress := cfg.Networking.APIServerAddress
-	if config.ClusterHasImplicitLoadBalancer(cfg) {
+	if haveLoadbalancer {
 		// TODO: picking ports locally is less than ideal with a remote runtime
 		// (does podman have this?)
 		// but this is supposed to be an implementation detail and NOT picking
 		// them breaks host reboot ...
 		// For now remote podman + multi control plane is not supported
-		apiServerPort = 0              // replaced with random ports
+		apiServerPort = randomHostPort // replaced with random ports
 		apiServerAddress = "127.0.0.1" // only the LB needs to be non-local
 		// only for IPv6 only clusters
 		if cfg.Networking.IPFamily == config.IPv6Family {
@@ -69,12 +126,15 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 		}
 		// plan loadbalancer node
 		name := names[len(names)-1]
-		createContainerFuncs = append(createContainerFuncs, func() error {
-			args, err := runArgsForLoadBalancer(cfg, name, genericArgs)
-			if err != nil {
-				return err
-			}
-			return createContainer(name, args)
+		containers = append(containers, container{
+			name: name,
+			args: func() ([]string, error) {
+				return runArgsForLoadBalancer(cfg, name, genericArgs)
+			},
+			fixedHostPorts: common.FixedHostPorts(config.PortMapping{HostPort: cfg.Networking.APIServerPort}),
+			create: func(args []string) error {
+				return createContainer(name, args)
+			},
 		})
 	}
 
@@ -94,35 +154,60 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 		}
 
 		// plan actual creation based on role
+		apiServerMapping := -1 // index of the port mapping publishing the API server
 		switch node.Role {
 		case config.ControlPlaneRole:
-			createContainerFuncs = append(createContainerFuncs, func() error {
+			// without a loadbalancer the control plane publishes the API server
+			if !haveLoadbalancer {
+				apiServerMapping = len(node.ExtraPortMappings)
+			}
+			node.ExtraPortMappings = append(node.ExtraPortMappings,
+				config.PortMapping{
+					ListenAddress: apiServerAddress,
+					HostPort:      apiServerPort,
+					ContainerPort: common.APIServerInternalPort,
+				},
+			)
+			// without a loadbalancer the control plane is also published
+			// on the additional API server addresses
+			if !haveLoadbalancer {
 				node.ExtraPortMappings = append(node.ExtraPortMappings,
-					config.PortMapping{
-						ListenAddress: apiServerAddress,
-						HostPort:      apiServerPort,
-						ContainerPort: common.APIServerInternalPort,
-					},
+					common.APIServerAdditionalPortMappings(cfg)...,
 				)
-				args, err := runArgsForNode(node, cfg.Networking.IPFamily, name, genericArgs)
-				if err != nil {
-					return err
-				}
-				return createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args)
-			})
+			}
 		case config.WorkerRole:
-			createContainerFuncs = append(createContainerFuncs, func() error {
-				args, err := runArgsForNode(node, cfg.Networking.IPFamily, name, genericArgs)
-				if err != nil {
-					return err
-				}
-				return createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args)
-			})
 		default:
 			return nil, errors.Errorf("unknown node role: %q", node.Role)
 		}
+		containers = append(containers, container{
+			name: name,
+			args: func() ([]string, error) {
+				if apiServerMapping < 0 {
+					return runArgsForNode(node, cfg.Networking.IPFamily, name, genericArgs)
+				}
+				node, releaseHostPortFn, err := common.NodeWithAPIServerHostPort(cfg, node, apiServerMapping)
+				if err != nil {
+					return nil, err
+				}
+				if releaseHostPortFn != nil {
+					defer releaseHostPortFn()
+				}
+				return runArgsForNode(node, cfg.Networking.IPFamily, name, genericArgs)
+			},
+			fixedHostPorts: common.FixedHostPorts(node.ExtraPortMappings...),
+			create: func(args []string) error {
+				// ensure the image cache volume exists with our labels before
+				// creating the node, otherwise an unlabeled volume would be created
+				if node.ImageCache {
+					if err := ensureImageCacheVolume(cfg.Name, name, node.Image); err != nil {
+						return err
+					}
+				}
+				return createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args)
+			},
+		})
 	}
-	return createContainerFuncs, nil
+	return containers, nil
 }
 
 // commonArgs computes static arguments that apply to all containers
@@ -212,8 +297,25 @@ func runArgsForNode(node *config.Node, clusterIPFamily config.ClusterIPFamily, n
 		args...,
 	)
 
+	// keep containerd's content and snapshots in the image cache volume,
+	// this is mounted over the /var volume with the same options
+	if node.ImageCache {
+		args = append(args, "--volume", fmt.Sprintf("%s:%s:suid,exec,dev", common.ImageCacheVolumeName(name), common.ImageCacheMountPath))
+	}
+
 	// convert mounts and port mappings to container run args
 	args = append(args, generateMountBindings(node.ExtraMounts...)...)
+
+	// prepare the node for nested clusters if requested, node specific
+	// args come after the common args so --cgroupns here takes precedence
+	if node.Nesting.DevMount {
+		args = append(args, "--volume", "/dev:/dev")
+	}
+	if node.Nesting.CgroupNS != "" {
+		args = append(args, "--cgroupns="+string(node.Nesting.CgroupNS))
+	}
+	args = append(args, common.EnvAndSysctlArgs(node)...)
+	args = append(args, common.MemoryArgs(node)...)
 	mappingArgs, err := generatePortMappings(clusterIPFamily, node.ExtraPortMappings...)
 	if err != nil {
 		return nil, err
@@ -240,12 +342,19 @@ func runArgsForLoadBalancer(cfg *config.Cluster, name string, args []string) ([]
 	)
 
 	// load balancer port mapping
+	apiServerPort, releaseHostPortFn, err := common.APIServerHostPort(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if releaseHostPortFn != nil {
+		defer releaseHostPortFn()
+	}
 	mappingArgs, err := generatePortMappings(cfg.Networking.IPFamily,
-		config.PortMapping{
+		append([]config.PortMapping{{
 			ListenAddress: cfg.Networking.APIServerAddress,
-			HostPort:      cfg.Networking.APIServerPort,
+			HostPort:      apiServerPort,
 			ContainerPort: common.APIServerInternalPort,
-		},
+		}}, common.APIServerAdditionalPortMappings(cfg)...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -283,13 +392,16 @@ func getProxyEnv(cfg *config.Cluster, networkName string, nodeNames []string) (m
 }
 
 type podmanNetworks []struct {
+	Name string `json:"name"`
 	// v4+
 	Subnets []struct {
 		Subnet  string `json:"subnet"`
 		Gateway string `json:"gateway"`
 	} `json:"subnets"`
+	Options map[string]string `json:"options"`
 	// v3 and anything still using CNI/IPAM
 	Plugins []struct {
+		MTU  int `json:"mtu"`
 		Ipam struct {
 			Ranges [][]struct {
 				Gateway string `json:"gateway"`
@@ -336,7 +448,7 @@ func getSubnets(networkName string) ([]string, error) {
 // '<HostPath>:<ContainerPath>[:options]', where 'options'
 // is a comma-separated list of the following strings:
 // 'ro', if the path is read only
-// 'Z', if the volume requires SELinux relabeling
+// 'z' or 'Z', if the volume requires SELinux relabeling
 func generateMountBindings(mounts ...config.Mount) []string {
 	args := make([]string, 0, len(mounts))
 	for _, m := range mounts {
@@ -350,7 +462,11 @@ func generateMountBindings(mounts ...config.Mount) []string {
 		// the container's randomly allocated MCS label. This would restrict access
 		// to the volume to the container which mounts it first.
 		if m.SelinuxRelabel {
-			attrs = append(attrs, "Z")
+			if m.SelinuxRelabelMode == config.SelinuxRelabelModeShared {
+				attrs = append(attrs, "z")
+			} else {
+				attrs = append(attrs, "Z")
+			}
 		}
 		switch m.Propagation {
 		case config.MountPropagationNone:
@@ -420,6 +536,19 @@ func generatePortMappings(clusterIPFamily config.ClusterIPFamily, portMappings .
 	return args, nil
 }
 
+// deleteContainer removes a container that failed to start and the
+// anonymous volumes created for it, so that its name can be used again
+func deleteContainer(name string) error {
+	if err := exec.Command("podman", "rm", "-f", "-v", name).Run(); err != nil {
+		return err
+	}
+	volumes, err := getVolumes(name)
+	if err != nil || len(volumes) == 0 {
+		return err
+	}
+	return deleteVolumes(volumes)
+}
+
 func createContainer(name string, args []string) error {
 	return exec.Command("podman", append([]string{"run", "--name", name}, args...)...).Run()
 }
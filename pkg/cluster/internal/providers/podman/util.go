@@ -19,10 +19,15 @@ package podman
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"os"
+	"runtime"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/version"
 )
@@ -37,6 +42,57 @@ func IsAvailable() bool {
 	return strings.HasPrefix(lines[0], "podman version")
 }
 
+// IsConfiguredRemote checks if podman is configured with CONTAINER_HOST to
+// use a podman service, which indicates the user intends to use podman
+func IsConfiguredRemote() bool {
+	return os.Getenv("CONTAINER_HOST") != ""
+}
+
+// isLocal returns true unless podman runs containers on another host, with
+// a remote CONTAINER_HOST or in a podman machine VM, as on macOS and Windows
+func isLocal() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	host := os.Getenv("CONTAINER_HOST")
+	if host == "" {
+		return true
+	}
+	_, ok := unixSocketPath(host)
+	return ok
+}
+
+// activateService starts the podman.socket systemd user unit if podman is
+// configured to use a unix socket that is not listening, as happens with
+// rootless podman set up for socket activation when the unit is not started
+func activateService(logger log.Logger) {
+	path, ok := unixSocketPath(os.Getenv("CONTAINER_HOST"))
+	if !ok {
+		return
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return
+	}
+	// only start the service if the user has one installed
+	if err := exec.Command("systemctl", "--user", "cat", "podman.socket").Run(); err != nil {
+		logger.V(1).Infof("podman socket %s is not listening and there is no podman.socket user unit", path)
+		return
+	}
+	logger.V(0).Infof("Starting podman.socket user unit, as %s is not listening ...", path)
+	if err := exec.Command("systemctl", "--user", "start", "podman.socket").Run(); err != nil {
+		logger.Warnf("Failed to start podman.socket user unit: %v", err)
+	}
+}
+
+// unixSocketPath returns the path of a unix:// CONTAINER_HOST
+func unixSocketPath(containerHost string) (string, bool) {
+	if !strings.HasPrefix(containerHost, "unix://") {
+		return "", false
+	}
+	return strings.TrimPrefix(containerHost, "unix://"), true
+}
+
 func getPodmanVersion() (*version.Version, error) {
 	cmd := exec.Command("podman", "--version")
 	lines, err := exec.OutputLines(cmd)
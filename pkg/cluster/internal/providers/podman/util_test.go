@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import (
+	"testing"
+)
+
+func Test_unixSocketPath(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		containerHost string
+		path          string
+		ok            bool
+	}{
+		{
+			containerHost: "unix:///run/user/1000/podman/podman.sock",
+			path:          "/run/user/1000/podman/podman.sock",
+			ok:            true,
+		},
+		{
+			containerHost: "ssh://core@localhost:53685/run/user/501/podman/podman.sock",
+		},
+		{
+			containerHost: "",
+		},
+	}
+	for _, tc := range cases {
+		path, ok := unixSocketPath(tc.containerHost)
+		if path != tc.path || ok != tc.ok {
+			t.Errorf("unixSocketPath(%q) = %q, %v but expected %q, %v", tc.containerHost, path, ok, tc.path, tc.ok)
+		}
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package providers
 
 import (
+	"context"
+	"time"
+
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
@@ -27,8 +30,14 @@ import (
 // This is an alpha-grade internal API
 type Provider interface {
 	// Provision should create and start the nodes, just short of
-	// actually starting up Kubernetes, based on the given cluster config
-	Provision(status *cli.Status, cfg *config.Cluster) error
+	// actually starting up Kubernetes, based on the given cluster config.
+	// Cancelling ctx stops long running steps such as pulling node images
+	Provision(ctx context.Context, status *cli.Status, cfg *config.Cluster) error
+	// RunArgs returns the container run arguments Provision would create
+	// each of the cluster's node containers with, by name. Nothing is
+	// created, and host ports that would be picked at random are left empty,
+	// e.g. --publish=127.0.0.1::6443
+	RunArgs(cfg *config.Cluster) (map[string][]string, error)
 	// ListClusters discovers the clusters that currently have resources
 	// under this providers
 	ListClusters() ([]string, error)
@@ -39,14 +48,100 @@ type Provider interface {
 	// These should be from results previously returned by this provider
 	// E.G. by ListNodes()
 	DeleteNodes([]nodes.Node) error
+	// DeleteClusterNetworks deletes any networks the provider created for
+	// the cluster alone, it is called once the cluster's nodes are deleted
+	DeleteClusterNetworks(cluster string) error
 	// GetAPIServerEndpoint returns the host endpoint for the cluster's API server
 	GetAPIServerEndpoint(cluster string) (string, error)
 	// GetAPIServerInternalEndpoint returns the internal network endpoint for the cluster's API server
 	GetAPIServerInternalEndpoint(cluster string) (string, error)
 	// CollectLogs will populate dir with cluster logs and other debug files
 	CollectLogs(dir string, nodes []nodes.Node) error
+	// ListBundledImages returns the images preloaded in the given node image
+	ListBundledImages(image string) ([]string, error)
+	// RunHostNetworkHelper runs command in a short-lived privileged
+	// container in the host's network namespace, using node's image.
+	// This is used for changes to the host such as adding routes.
+	RunHostNetworkHelper(node nodes.Node, command ...string) error
+	// GetNetwork returns the container network the cluster's nodes are
+	// attached to
+	GetNetwork(cluster string) (*Network, error)
+	// ContainerID returns the full ID of node's container
+	ContainerID(node nodes.Node) (string, error)
+	// ListPorts returns the container ports node publishes on the host
+	ListPorts(node nodes.Node) ([]PortMapping, error)
+	// Events calls handle with the container runtime events for the
+	// cluster's node containers since the given time, which may be zero.
+	// If watch is true it keeps streaming new events until ctx is cancelled,
+	// otherwise it returns after the events up to now
+	Events(ctx context.Context, cluster string, since time.Time, watch bool, handle func(Event)) error
+	// CreateHollowNodes creates and starts kubemark hollow nodes with the
+	// given names in the cluster's network, running image with kubeconfig
+	CreateHollowNodes(cluster, image, kubeconfig string, names []string) error
+	// SupportsHollowNodes returns true if CreateHollowNodes is implemented,
+	// so that configs with hollowNodes are rejected before provisioning
+	SupportsHollowNodes() bool
+	// ListImageCacheVolumes returns the image cache volumes of all clusters,
+	// including clusters that have been deleted
+	ListImageCacheVolumes() ([]Volume, error)
+	// DeleteVolumes deletes the named volumes
+	DeleteVolumes(names []string) error
 	// Info returns the provider info
 	Info() (*ProviderInfo, error)
+	// IsLocal returns true if the node containers run on this host, so that
+	// host paths and ports can be checked by kind. It does not contact the
+	// container runtime.
+	IsLocal() bool
+}
+
+// Volume is a named volume kind created for a cluster's node
+type Volume struct {
+	Name    string
+	Cluster string
+}
+
+// Network describes a container network
+type Network struct {
+	Name     string
+	Subnets  []string
+	Gateways []string
+	// IPRanges are the ranges within Subnets the runtime allocates container
+	// addresses from, containers get addresses from anywhere in a subnet
+	// without one
+	IPRanges []string
+	// MTU is 0 if the network uses the runtime's default MTU
+	MTU int
+}
+
+// PortMapping is a container port published on the host
+type PortMapping struct {
+	ContainerPort int32
+	// Protocol is "tcp", "udp" or "sctp"
+	Protocol string
+	HostIP   string
+	HostPort int32
+}
+
+// Event actions reported by Provider.Events
+const (
+	EventStart   = "start"
+	EventStop    = "stop"
+	EventKill    = "kill"
+	EventDie     = "die"
+	EventOOM     = "oom"
+	EventRestart = "restart"
+)
+
+// Event is a container runtime event for a node container
+type Event struct {
+	Time time.Time
+	Node string
+	// Role is the node's role label, it may be empty for older runtimes
+	Role string
+	// Action is one of the Event* constants
+	Action string
+	// ExitCode is the container's exit code for EventDie
+	ExitCode int
 }
 
 // ProviderInfo is the info of the provider
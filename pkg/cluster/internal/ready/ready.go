@@ -0,0 +1,310 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ready implements waiting for cluster conditions, such as the
+// control plane being Ready
+package ready
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+// The supported condition types
+const (
+	// ControlPlaneReady is met when all control plane nodes are Ready
+	ControlPlaneReady = "ready"
+	// APIServerAvailable is met when the API server reports it is ready
+	APIServerAvailable = "apiserver-available"
+	// NodesReady is met when at least Condition.Nodes nodes are Ready
+	NodesReady = "nodes"
+	// DaemonSetReady is met when the DaemonSet Condition.Namespace /
+	// Condition.Name exists and all of its scheduled pods are ready
+	DaemonSetReady = "daemonset"
+	// DeploymentAvailable is met when the Deployment Condition.Namespace /
+	// Condition.Name exists and is Available
+	DeploymentAvailable = "deployment"
+	// CRDEstablished is met when the CustomResourceDefinition Condition.Name
+	// exists and is Established
+	CRDEstablished = "crd"
+)
+
+// Condition is a cluster condition to wait for
+type Condition struct {
+	// Type is one of the condition types above
+	Type string
+	// Nodes is the number of Ready nodes required for NodesReady
+	Nodes int
+	// Namespace is the namespace of the object for DaemonSetReady and
+	// DeploymentAvailable
+	Namespace string
+	// Name is the name of the object for DaemonSetReady, DeploymentAvailable
+	// and CRDEstablished
+	Name string
+}
+
+// ParseCondition parses a condition such as "ready", "apiserver-available",
+// "nodes=3", "daemonset=kube-system/kindnet", "deployment=default/app" or
+// "crd=widgets.example.com"
+func ParseCondition(condition string) (Condition, error) {
+	parts := strings.SplitN(condition, "=", 2)
+	switch parts[0] {
+	case ControlPlaneReady, APIServerAvailable:
+		if len(parts) == 1 {
+			return Condition{Type: parts[0]}, nil
+		}
+	case NodesReady:
+		if len(parts) == 2 {
+			n, err := strconv.Atoi(parts[1])
+			if err == nil && n > 0 {
+				return Condition{Type: NodesReady, Nodes: n}, nil
+			}
+		}
+		return Condition{}, errors.Errorf("invalid condition %q, expected nodes=<count> with a positive count", condition)
+	case DaemonSetReady, DeploymentAvailable:
+		if len(parts) == 2 {
+			object := strings.SplitN(parts[1], "/", 2)
+			if len(object) == 2 && object[0] != "" && object[1] != "" {
+				return Condition{Type: parts[0], Namespace: object[0], Name: object[1]}, nil
+			}
+		}
+		return Condition{}, errors.Errorf("invalid condition %q, expected %s=<namespace>/<name>", condition, parts[0])
+	case CRDEstablished:
+		if len(parts) == 2 && parts[1] != "" {
+			return Condition{Type: CRDEstablished, Name: parts[1]}, nil
+		}
+		return Condition{}, errors.Errorf("invalid condition %q, expected crd=<name>", condition)
+	}
+	return Condition{}, errors.Errorf(
+		"invalid condition %q, expected one of %q, %q, %q, %q, %q or %q",
+		condition, ControlPlaneReady, APIServerAvailable, NodesReady+"=<count>",
+		DaemonSetReady+"=<namespace>/<name>", DeploymentAvailable+"=<namespace>/<name>",
+		CRDEstablished+"=<name>",
+	)
+}
+
+// String returns the condition in the format accepted by ParseCondition
+func (c Condition) String() string {
+	switch c.Type {
+	case NodesReady:
+		return NodesReady + "=" + strconv.Itoa(c.Nodes)
+	case DaemonSetReady, DeploymentAvailable:
+		return c.Type + "=" + c.Namespace + "/" + c.Name
+	case CRDEstablished:
+		return CRDEstablished + "=" + c.Name
+	}
+	return c.Type
+}
+
+// Wait waits until condition is met for the cluster with allNodes, until
+// the deadline has passed or ctx is done. It returns whether the condition
+// was met.
+func Wait(ctx context.Context, allNodes []nodes.Node, condition Condition, until time.Time) (bool, error) {
+	// use a control plane node to check cluster status
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return false, err
+	}
+	if len(controlPlanes) == 0 {
+		return false, errors.New("no control plane nodes found")
+	}
+	node := controlPlanes[0]
+
+	var check func() bool
+	switch condition.Type {
+	case ControlPlaneReady:
+		selector, err := controlPlaneSelector(node)
+		if err != nil {
+			return false, err
+		}
+		check = func() bool {
+			ready, total, ok := readyNodes(ctx, node, selector)
+			return ok && total > 0 && ready == total
+		}
+	case NodesReady:
+		check = func() bool {
+			ready, _, ok := readyNodes(ctx, node, "")
+			return ok && ready >= condition.Nodes
+		}
+	case APIServerAvailable:
+		check = func() bool {
+			return apiServerReady(ctx, node)
+		}
+	case DaemonSetReady:
+		check = func() bool {
+			return daemonSetReady(ctx, node, condition.Namespace, condition.Name)
+		}
+	case DeploymentAvailable:
+		check = func() bool {
+			return conditionTrue(ctx, node, "Available", "deployment", condition.Name, "--namespace="+condition.Namespace)
+		}
+	case CRDEstablished:
+		check = func() bool {
+			return conditionTrue(ctx, node, "Established", "customresourcedefinition", condition.Name)
+		}
+	default:
+		return false, errors.Errorf("unknown condition %q", condition.Type)
+	}
+	return tryUntil(ctx, until, check), nil
+}
+
+// controlPlaneSelector returns the label selector for control plane nodes
+// on the Kubernetes version node is running
+// TODO: Remove the below handling once kubeadm 1.23 is no longer supported.
+// https://github.com/kubernetes-sigs/kind/issues/1699
+func controlPlaneSelector(node nodes.Node) (string, error) {
+	rawVersion, err := nodeutils.KubeVersion(node)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get Kubernetes version from node")
+	}
+	kubeVersion, err := version.ParseSemantic(rawVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse Kubernetes version")
+	}
+	if kubeVersion.LessThan(version.MustParseSemantic("v1.24.0-alpha.1.591+a3d5e5598290df")) {
+		return "node-role.kubernetes.io/master", nil
+	}
+	return "node-role.kubernetes.io/control-plane", nil
+}
+
+// readyNodes uses kubectl inside the "node" container to count the Ready
+// nodes matching selector, ok is false if kubectl failed
+func readyNodes(ctx context.Context, node nodes.Node, selector string) (ready, total int, ok bool) {
+	args := []string{
+		"--kubeconfig=/etc/kubernetes/admin.conf",
+		"get",
+		"nodes",
+		// When the node reaches status ready, the status field will be set
+		// to true.
+		"-o=jsonpath='{.items..status.conditions[-1:].status}'",
+	}
+	if selector != "" {
+		args = append(args, "--selector="+selector)
+	}
+	lines, err := exec.OutputLines(node.CommandContext(ctx, "kubectl", args...))
+	if err != nil || len(lines) == 0 {
+		return 0, 0, false
+	}
+	ready, total = countReady(lines[0])
+	return ready, total, true
+}
+
+// countReady counts the statuses in the output of readyNodes, e.g. if we
+// have three nodes and two are ready, then the output has the following
+// format: `True False True'
+func countReady(output string) (ready, total int) {
+	for _, s := range strings.Fields(strings.Trim(output, "'")) {
+		total++
+		// Check node status. If node is ready then this will be 'True',
+		// 'False' or 'Unknown' otherwise.
+		if s == "True" {
+			ready++
+		}
+	}
+	return ready, total
+}
+
+// apiServerReady uses kubectl inside the "node" container to check the API
+// server's readyz endpoint
+func apiServerReady(ctx context.Context, node nodes.Node) bool {
+	return node.CommandContext(
+		ctx,
+		"kubectl",
+		"--kubeconfig=/etc/kubernetes/admin.conf",
+		"get",
+		"--raw=/readyz",
+	).Run() == nil
+}
+
+// daemonSetReady uses kubectl inside the "node" container to check that the
+// DaemonSet namespace/name has been observed by its controller and all of its
+// scheduled pods are ready
+func daemonSetReady(ctx context.Context, node nodes.Node, namespace, name string) bool {
+	lines, err := exec.OutputLines(node.CommandContext(
+		ctx,
+		"kubectl",
+		"--kubeconfig=/etc/kubernetes/admin.conf",
+		"get",
+		"daemonset",
+		name,
+		"--namespace="+namespace,
+		"-o=jsonpath={.metadata.generation} {.status.observedGeneration} {.status.desiredNumberScheduled} {.status.numberReady}",
+	))
+	if err != nil || len(lines) == 0 {
+		return false
+	}
+	return daemonSetStatusReady(lines[0])
+}
+
+// daemonSetStatusReady parses the output of daemonSetReady, which has the
+// format: `<generation> <observedGeneration> <desired> <ready>`
+func daemonSetStatusReady(output string) bool {
+	fields := strings.Fields(output)
+	if len(fields) != 4 {
+		return false
+	}
+	// the controller has not yet seen the latest spec
+	if fields[0] != fields[1] {
+		return false
+	}
+	desired, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return false
+	}
+	ready, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return false
+	}
+	return ready == desired
+}
+
+// conditionTrue uses kubectl inside the "node" container to check that the
+// object kind/name exists and its status condition conditionType is True
+func conditionTrue(ctx context.Context, node nodes.Node, conditionType, kind, name string, extraArgs ...string) bool {
+	args := []string{
+		"--kubeconfig=/etc/kubernetes/admin.conf",
+		"get",
+		kind,
+		name,
+		`-o=jsonpath={.status.conditions[?(@.type=="` + conditionType + `")].status}`,
+	}
+	args = append(args, extraArgs...)
+	lines, err := exec.OutputLines(node.CommandContext(ctx, "kubectl", args...))
+	if err != nil || len(lines) == 0 {
+		return false
+	}
+	return strings.TrimSpace(lines[0]) == "True"
+}
+
+// helper that calls `try()“ in a loop until the deadline `until`
+// has passed, ctx is done, or `try()`returns true, returns whether try ever
+// returned true
+func tryUntil(ctx context.Context, until time.Time, try func() bool) bool {
+	for until.After(time.Now()) && ctx.Err() == nil {
+		if try() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ready
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParseCondition(t *testing.T) {
+	cases := []struct {
+		Name        string
+		Condition   string
+		Expected    Condition
+		ExpectError bool
+	}{
+		{
+			Name:      "ready",
+			Condition: "ready",
+			Expected:  Condition{Type: ControlPlaneReady},
+		},
+		{
+			Name:      "apiserver available",
+			Condition: "apiserver-available",
+			Expected:  Condition{Type: APIServerAvailable},
+		},
+		{
+			Name:      "nodes",
+			Condition: "nodes=3",
+			Expected:  Condition{Type: NodesReady, Nodes: 3},
+		},
+		{
+			Name:        "nodes without count",
+			Condition:   "nodes",
+			ExpectError: true,
+		},
+		{
+			Name:        "nodes with zero count",
+			Condition:   "nodes=0",
+			ExpectError: true,
+		},
+		{
+			Name:      "daemonset",
+			Condition: "daemonset=kube-system/kindnet",
+			Expected:  Condition{Type: DaemonSetReady, Namespace: "kube-system", Name: "kindnet"},
+		},
+		{
+			Name:      "deployment",
+			Condition: "deployment=default/app",
+			Expected:  Condition{Type: DeploymentAvailable, Namespace: "default", Name: "app"},
+		},
+		{
+			Name:      "crd",
+			Condition: "crd=widgets.example.com",
+			Expected:  Condition{Type: CRDEstablished, Name: "widgets.example.com"},
+		},
+		{
+			Name:        "daemonset without namespace",
+			Condition:   "daemonset=kindnet",
+			ExpectError: true,
+		},
+		{
+			Name:        "deployment without name",
+			Condition:   "deployment=default/",
+			ExpectError: true,
+		},
+		{
+			Name:        "crd without name",
+			Condition:   "crd",
+			ExpectError: true,
+		},
+		{
+			Name:        "ready with value",
+			Condition:   "ready=true",
+			ExpectError: true,
+		},
+		{
+			Name:        "unknown",
+			Condition:   "healthy",
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			c, err := ParseCondition(tc.Condition)
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.DeepEqual(t, tc.Expected, c)
+			if err == nil {
+				assert.StringEqual(t, tc.Condition, c.String())
+			}
+		})
+	}
+}
+
+func TestCountReady(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Output        string
+		ExpectedReady int
+		ExpectedTotal int
+	}{
+		{
+			Name:          "all ready",
+			Output:        "'True True True'",
+			ExpectedReady: 3,
+			ExpectedTotal: 3,
+		},
+		{
+			Name:          "some ready",
+			Output:        "'True False Unknown'",
+			ExpectedReady: 1,
+			ExpectedTotal: 3,
+		},
+		{
+			Name:   "no nodes",
+			Output: "''",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			ready, total := countReady(tc.Output)
+			if ready != tc.ExpectedReady || total != tc.ExpectedTotal {
+				t.Errorf("expected %d/%d ready, got %d/%d", tc.ExpectedReady, tc.ExpectedTotal, ready, total)
+			}
+		})
+	}
+}
+
+func TestDaemonSetStatusReady(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Output   string
+		Expected bool
+	}{
+		{
+			Name:     "all pods ready",
+			Output:   "2 2 3 3",
+			Expected: true,
+		},
+		{
+			Name:   "some pods ready",
+			Output: "2 2 3 1",
+		},
+		{
+			Name:   "not yet observed",
+			Output: "2 1 3 3",
+		},
+		{
+			Name:   "no status",
+			Output: "1",
+		},
+		{
+			Name:     "no pods scheduled",
+			Output:   "1 1 0 0",
+			Expected: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.BoolEqual(t, tc.Expected, daemonSetStatusReady(tc.Output))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package verify implements smoke tests for a running cluster
+package verify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// Image is the image the smoke tests run, the Kubernetes e2e test image
+const Image = "registry.k8s.io/e2e-test-images/agnhost:2.53"
+
+// the smoke test objects, the namespace is deleted afterwards
+const (
+	namespace = "kind-verify"
+	podName   = "smoke-test"
+	hostPort  = 32123
+)
+
+const podManifest = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %[1]s
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: %[2]s
+  namespace: %[1]s
+spec:
+  terminationGracePeriodSeconds: 0
+  containers:
+  - name: agnhost
+    image: %[3]s
+    args: ["netexec", "--http-port=8080"]
+    ports:
+    - containerPort: 8080
+      hostPort: %[4]d
+`
+
+// The smoke tests, in the order they run
+const (
+	SchedulePod    = "schedule a pod"
+	PullImage      = "pull an image"
+	ResolveDNS     = "resolve DNS"
+	ReachClusterIP = "reach a ClusterIP"
+	ReachHostPort  = "reach a hostPort"
+)
+
+// Result is the result of one smoke test
+type Result struct {
+	// Name is the smoke test, e.g. SchedulePod
+	Name string
+	// Err is why the test failed, nil if it passed
+	Err error
+	// Skipped is true if the test did not run because the smoke test pod
+	// is not running
+	Skipped bool
+}
+
+func skipped(name string) Result {
+	return Result{Name: name, Skipped: true}
+}
+
+// Run runs the smoke tests against the cluster with allNodes, returning a
+// result for every test. An error is only returned if the tests could not
+// be run at all.
+func Run(ctx context.Context, allNodes []nodes.Node) ([]Result, error) {
+	node, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	k := kubectl{ctx: ctx, node: node}
+
+	manifest := fmt.Sprintf(podManifest, namespace, podName, Image, hostPort)
+	if err := k.command("apply", "-f", "-").SetStdin(strings.NewReader(manifest)).Run(); err != nil {
+		return nil, errors.Wrap(err, "failed to create the smoke test pod")
+	}
+	defer func() {
+		_ = node.Command(
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+			"delete", "namespace", namespace, "--wait=false",
+		).Run()
+	}()
+
+	// the pod must be running for the other tests, they are skipped if not
+	results := []Result{}
+	err = k.wait("PodScheduled", "2m")
+	results = append(results, Result{Name: SchedulePod, Err: err})
+	if err == nil {
+		err = k.waitRunning()
+		results = append(results, Result{Name: PullImage, Err: err})
+	} else {
+		results = append(results, skipped(PullImage))
+	}
+	for _, check := range []struct {
+		name string
+		run  func() error
+	}{
+		{ResolveDNS, func() error {
+			return k.connect("kubernetes.default:443")
+		}},
+		{ReachClusterIP, func() error {
+			lines, err := exec.OutputLines(k.command(
+				"get", "service", "kubernetes", "--namespace=default", "-o=jsonpath={.spec.clusterIP}",
+			))
+			if err != nil || len(lines) != 1 {
+				return errors.New("failed to get the kubernetes service ClusterIP")
+			}
+			return k.connect(net.JoinHostPort(lines[0], "443"))
+		}},
+		{ReachHostPort, func() error {
+			hostIP := k.get("{.status.hostIP}")
+			if hostIP == "" {
+				return errors.New("failed to get the pod's host IP")
+			}
+			url := fmt.Sprintf("http://%s/hostname", net.JoinHostPort(hostIP, fmt.Sprint(hostPort)))
+			return node.CommandContext(ctx, "curl", "-sSf", "--max-time", "10", url).Run()
+		}},
+	} {
+		if err != nil {
+			results = append(results, skipped(check.name))
+			continue
+		}
+		results = append(results, Result{Name: check.name, Err: check.run()})
+	}
+	return results, nil
+}
+
+// Report logs results as a pass / fail matrix, returning an error if any
+// smoke test failed
+func Report(logger log.Logger, results []Result) error {
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+		logger.V(0).Info(formatResult(r))
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d smoke tests failed", failed, len(results))
+	}
+	return nil
+}
+
+func formatResult(r Result) string {
+	switch {
+	case r.Skipped:
+		return fmt.Sprintf(" - %s (skipped)", r.Name)
+	case r.Err != nil:
+		return fmt.Sprintf(" ✗ %s: %v", r.Name, r.Err)
+	default:
+		return fmt.Sprintf(" ✓ %s", r.Name)
+	}
+}
+
+// kubectl runs kubectl on a control plane node
+type kubectl struct {
+	ctx  context.Context
+	node nodes.Node
+}
+
+func (k kubectl) command(args ...string) exec.Cmd {
+	return k.node.CommandContext(k.ctx, "kubectl", append([]string{"--kubeconfig=/etc/kubernetes/admin.conf"}, args...)...)
+}
+
+// wait waits for the smoke test pod to have condition
+func (k kubectl) wait(condition, timeout string) error {
+	return k.command(
+		"wait", "--namespace="+namespace, "--for=condition="+condition, "--timeout="+timeout, "pod/"+podName,
+	).Run()
+}
+
+// waitRunning waits for the smoke test pod to be Ready, which requires
+// pulling its image, and explains why it is not if possible
+func (k kubectl) waitRunning() error {
+	err := k.wait("Ready", "3m")
+	if err == nil {
+		return nil
+	}
+	if reason := k.get("{.status.containerStatuses[0].state.waiting.reason}"); reason != "" {
+		return errors.Errorf("the pod is not running: %s", reason)
+	}
+	return err
+}
+
+// get returns the jsonpath from the smoke test pod, or "" on errors
+func (k kubectl) get(jsonpath string) string {
+	lines, err := exec.OutputLines(k.command(
+		"get", "--namespace="+namespace, "pod/"+podName, "-o=jsonpath="+jsonpath,
+	))
+	if err != nil || len(lines) != 1 {
+		return ""
+	}
+	return lines[0]
+}
+
+// connect checks a TCP connection to address from the smoke test pod
+func (k kubectl) connect(address string) error {
+	return k.command(
+		"exec", "--namespace="+namespace, podName, "--", "/agnhost", "connect", "--timeout=10s", address,
+	).Run()
+}
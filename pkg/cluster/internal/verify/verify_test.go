@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestFormatResult(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Result   Result
+		Expected string
+	}{
+		{
+			Name:     "passed",
+			Result:   Result{Name: SchedulePod},
+			Expected: " ✓ schedule a pod",
+		},
+		{
+			Name:     "failed",
+			Result:   Result{Name: ResolveDNS, Err: errors.New("exit status 1")},
+			Expected: " ✗ resolve DNS: exit status 1",
+		},
+		{
+			Name:     "skipped",
+			Result:   skipped(ReachHostPort),
+			Expected: " - reach a hostPort (skipped)",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.StringEqual(t, tc.Expected, formatResult(tc.Result))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
+)
+
+// ExportKubeConfigOption is a Provider.ExportKubeConfig option
+type ExportKubeConfigOption interface {
+	apply(*kubeconfig.FileOptions) error
+}
+
+type exportKubeConfigOptionAdapter func(*kubeconfig.FileOptions) error
+
+func (c exportKubeConfigOptionAdapter) apply(o *kubeconfig.FileOptions) error {
+	return c(o)
+}
+
+// ExportKubeConfigWithMode sets the mode of the kubeconfig file, by default
+// new files are created with 0600 and existing files keep their mode
+func ExportKubeConfigWithMode(mode os.FileMode) ExportKubeConfigOption {
+	return exportKubeConfigOptionAdapter(func(o *kubeconfig.FileOptions) error {
+		o.Mode = mode
+		return nil
+	})
+}
+
+// ExportKubeConfigWithOwner sets the owner of the kubeconfig file to owner,
+// "user[:group]" where user and group are names or numeric IDs. This is
+// useful when kind runs as root, e.g. under sudo, as only root may change
+// the owner of a file to another user. By default new files are owned by
+// the current user and existing files keep their owner where permitted.
+// An empty owner is ignored.
+func ExportKubeConfigWithOwner(owner string) ExportKubeConfigOption {
+	return exportKubeConfigOptionAdapter(func(o *kubeconfig.FileOptions) error {
+		return applyKubeconfigOwner(o, owner)
+	})
+}
+
+// applyKubeconfigOwner parses owner into o, unless owner is empty
+func applyKubeconfigOwner(o *kubeconfig.FileOptions, owner string) error {
+	if owner == "" {
+		return nil
+	}
+	parsed, err := kubeconfig.ParseOwner(owner)
+	if err != nil {
+		return err
+	}
+	o.Owner = parsed
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/loglevel"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// LogLevels are the log levels of a node's components, unset fields are not
+// changed, see Provider.SetLogLevel
+type LogLevels struct {
+	// KubeletVerbosity is the kubelet's log verbosity (--v), from 0 to 10
+	KubeletVerbosity *int32
+	// ContainerdLogLevel is containerd's log level (--log-level), one of
+	// "trace", "debug", "info", "warn" or "error"
+	ContainerdLogLevel string
+}
+
+// SetLogLevel sets the log levels of the components on the named nodes of
+// the cluster, or on all of its nodes if none are named. The components are
+// restarted to pick up the levels, running containers are not affected.
+func (p *Provider) SetLogLevel(name string, levels LogLevels, nodeNames ...string) error {
+	logging := config.NodeLogging{
+		KubeletVerbosity:   levels.KubeletVerbosity,
+		ContainerdLogLevel: levels.ContainerdLogLevel,
+	}
+	if logging == (config.NodeLogging{}) {
+		return errors.New("no log levels to set")
+	}
+	if err := logging.Validate(); err != nil {
+		return err
+	}
+
+	allNodes, err := p.ListInternalNodes(defaultName(name))
+	if err != nil {
+		return err
+	}
+	if len(allNodes) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	selected := allNodes
+	if len(nodeNames) > 0 {
+		selected = make([]nodes.Node, 0, len(nodeNames))
+		for _, nodeName := range nodeNames {
+			found := false
+			for _, n := range allNodes {
+				if n.String() == nodeName {
+					selected = append(selected, n)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return errors.Errorf("unknown node %q in cluster %q", nodeName, defaultName(name))
+			}
+		}
+	}
+
+	fns := make([]func() error, 0, len(selected))
+	for _, n := range selected {
+		n := n // capture loop variable
+		fns = append(fns, func() error {
+			return errors.Wrapf(loglevel.Apply(n, logging), "failed to set log levels on node %q", n)
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
@@ -18,13 +18,16 @@ package nodeutils
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/pelletier/go-toml"
+	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/errors"
@@ -45,6 +48,43 @@ func KubeVersion(n nodes.Node) (version string, err error) {
 	return lines[0], nil
 }
 
+// ContainerRuntime returns the container runtime installed on the node,
+// "containerd" unless the node image was built for another runtime
+func ContainerRuntime(n nodes.Node) (string, error) {
+	cmd := n.Command("sh", "-c", "cat /kind/cri 2>/dev/null || echo containerd")
+	lines, err := exec.OutputLines(cmd)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to detect container runtime")
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("file should only be one line, got %d lines", len(lines))
+	}
+	return lines[0], nil
+}
+
+// CRIEndpoint returns the CRI endpoint of the container runtime on the node,
+// as configured for crictl in the node image
+func CRIEndpoint(n nodes.Node) (string, error) {
+	out, err := exec.Output(n.Command("cat", "/etc/crictl.yaml"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read crictl config")
+	}
+	return parseCRIEndpoint(out)
+}
+
+func parseCRIEndpoint(config []byte) (string, error) {
+	parsed := struct {
+		RuntimeEndpoint string `json:"runtime-endpoint"`
+	}{}
+	if err := yaml.Unmarshal(config, &parsed); err != nil {
+		return "", errors.Wrap(err, "failed to parse crictl config")
+	}
+	if parsed.RuntimeEndpoint == "" {
+		return "", errors.New("crictl config has no runtime-endpoint")
+	}
+	return parsed.RuntimeEndpoint, nil
+}
+
 // WriteFile writes content to dest on the node
 func WriteFile(n nodes.Node, dest, content string) error {
 	// create destination directory
@@ -79,17 +119,53 @@ func CopyNodeToNode(a, b nodes.Node, file string) error {
 
 // LoadImageArchive loads image onto the node, where image is a Reader over an image archive
 func LoadImageArchive(n nodes.Node, image io.Reader) error {
+	return LoadImageArchiveContext(context.Background(), n, image)
+}
+
+// LoadImageArchiveContext is like LoadImageArchive, but stops loading the
+// image when ctx is cancelled
+func LoadImageArchiveContext(ctx context.Context, n nodes.Node, image io.Reader) error {
+	runtime, err := ContainerRuntime(n)
+	if err != nil {
+		return err
+	}
+	// CRI-O shares its image store with podman
+	if runtime == "crio" {
+		if err := n.CommandContext(ctx, "podman", "load", "-q").SetStdin(image).Run(); err != nil {
+			return errors.Wrap(err, "failed to load image")
+		}
+		return nil
+	}
 	snapshotter, err := getSnapshotter(n)
 	if err != nil {
 		return err
 	}
-	cmd := n.Command("ctr", "--namespace=k8s.io", "images", "import", "--all-platforms", "--digests", "--snapshotter="+snapshotter, "-").SetStdin(image)
+	cmd := n.CommandContext(ctx, "ctr", "--namespace=k8s.io", "images", "import", "--all-platforms", "--digests", "--snapshotter="+snapshotter, "-").SetStdin(image)
 	if err := cmd.Run(); err != nil {
 		return errors.Wrap(err, "failed to load image")
 	}
 	return nil
 }
 
+// ExportImageArchive writes an archive of images in the node's container
+// runtime to out, which LoadImageArchive can load onto another node
+func ExportImageArchive(ctx context.Context, n nodes.Node, out io.Writer, images ...string) error {
+	runtime, err := ContainerRuntime(n)
+	if err != nil {
+		return err
+	}
+	var cmd exec.Cmd
+	if runtime == "crio" {
+		cmd = n.CommandContext(ctx, "podman", append([]string{"save", "-q", "--multi-image-archive"}, images...)...)
+	} else {
+		cmd = n.CommandContext(ctx, "ctr", append([]string{"--namespace=k8s.io", "images", "export", "-"}, images...)...)
+	}
+	if err := cmd.SetStdout(out).Run(); err != nil {
+		return errors.Wrap(err, "failed to export images")
+	}
+	return nil
+}
+
 func getSnapshotter(n nodes.Node) (string, error) {
 	out, err := exec.Output(n.Command("containerd", "config", "dump"))
 	if err != nil {
@@ -125,6 +201,15 @@ func parseSnapshotter(config string) (string, error) {
 	return snapshotter, nil
 }
 
+// PullImage pulls image onto the node with the node's container runtime,
+// using the registry mirrors and credentials the runtime is configured with
+func PullImage(ctx context.Context, n nodes.Node, image string) error {
+	if err := n.CommandContext(ctx, "crictl", "pull", image).Run(); err != nil {
+		return errors.Wrapf(err, "failed to pull image %q", image)
+	}
+	return nil
+}
+
 // ImageID returns ID of image on the node with the given image name if present
 func ImageID(n nodes.Node, image string) (string, error) {
 	var out bytes.Buffer
@@ -165,8 +250,61 @@ func ImageTags(n nodes.Node, imageID string) (map[string]bool, error) {
 	return tags, nil
 }
 
+// Image is an image in a node's container runtime
+type Image struct {
+	ID          string
+	RepoTags    []string
+	RepoDigests []string
+	Size        uint64
+}
+
+// Images lists the images in the node's container runtime, like `crictl images`
+func Images(n nodes.Node) ([]Image, error) {
+	var out bytes.Buffer
+	if err := n.Command("crictl", "images", "-o", "json").SetStdout(&out).Run(); err != nil {
+		return nil, errors.Wrap(err, "failed to list images")
+	}
+	return parseImages(out.Bytes())
+}
+
+func parseImages(data []byte) ([]Image, error) {
+	crictlOut := struct {
+		Images []struct {
+			ID          string   `json:"id"`
+			RepoTags    []string `json:"repoTags"`
+			RepoDigests []string `json:"repoDigests"`
+			// crictl encodes the uint64 size as a string
+			Size string `json:"size"`
+		} `json:"images"`
+	}{}
+	if err := json.Unmarshal(data, &crictlOut); err != nil {
+		return nil, errors.Wrap(err, "failed to parse images")
+	}
+	images := make([]Image, 0, len(crictlOut.Images))
+	for _, image := range crictlOut.Images {
+		size, err := strconv.ParseUint(image.Size, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid size for image %q", image.ID)
+		}
+		images = append(images, Image{
+			ID:          image.ID,
+			RepoTags:    image.RepoTags,
+			RepoDigests: image.RepoDigests,
+			Size:        size,
+		})
+	}
+	return images, nil
+}
+
 // ReTagImage is used to tag an ImageID with a custom tag specified by imageName parameter
 func ReTagImage(n nodes.Node, imageID, imageName string) error {
+	runtime, err := ContainerRuntime(n)
+	if err != nil {
+		return err
+	}
 	var out bytes.Buffer
+	if runtime == "crio" {
+		return n.Command("podman", "tag", imageID, imageName).SetStdout(&out).Run()
+	}
 	return n.Command("ctr", "--namespace=k8s.io", "images", "tag", "--force", imageID, imageName).SetStdout(&out).Run()
 }
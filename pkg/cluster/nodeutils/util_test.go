@@ -17,6 +17,7 @@ limitations under the License.
 package nodeutils
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -262,3 +263,69 @@ func TestParseSnapshotter(t *testing.T) {
 		t.Fatal("expected error parsing invalid config")
 	}
 }
+
+func TestParseImages(t *testing.T) {
+	// trimmed output of `crictl images -o json` on a kind node
+	data := []byte(`{
+  "images": [
+    {
+      "id": "sha256:2e96e5913fc06e3d26915af3d0f2ca5048cc4b6327e661e80da792cbf8d8d9d4",
+      "repoTags": ["registry.k8s.io/etcd:3.5.16-0"],
+      "repoDigests": ["registry.k8s.io/etcd@sha256:c6a9d11cc5c04b114ccdef39a9265eeef818e3d02f5359be035ae784097fdec5"],
+      "size": "57680541",
+      "uid": null,
+      "username": "",
+      "spec": null,
+      "pinned": false
+    },
+    {
+      "id": "sha256:9bd4a8ea36ab2ef3d8a9ac4ab40ea09f3ba1c85e2a4a3d5a7a0a0bd1c3ad1e0d",
+      "repoTags": ["docker.io/library/my-app:dev"],
+      "repoDigests": [],
+      "size": "1024",
+      "uid": null,
+      "username": "",
+      "spec": null,
+      "pinned": false
+    }
+  ]
+}`)
+	images, err := parseImages(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Image{
+		{
+			ID:          "sha256:2e96e5913fc06e3d26915af3d0f2ca5048cc4b6327e661e80da792cbf8d8d9d4",
+			RepoTags:    []string{"registry.k8s.io/etcd:3.5.16-0"},
+			RepoDigests: []string{"registry.k8s.io/etcd@sha256:c6a9d11cc5c04b114ccdef39a9265eeef818e3d02f5359be035ae784097fdec5"},
+			Size:        57680541,
+		},
+		{
+			ID:          "sha256:9bd4a8ea36ab2ef3d8a9ac4ab40ea09f3ba1c85e2a4a3d5a7a0a0bd1c3ad1e0d",
+			RepoTags:    []string{"docker.io/library/my-app:dev"},
+			RepoDigests: []string{},
+			Size:        1024,
+		},
+	}
+	if !reflect.DeepEqual(images, expected) {
+		t.Errorf("expected %#v but got %#v", expected, images)
+	}
+	if _, err := parseImages([]byte(`{"images": [{"id": "sha256:abc", "size": "big"}]}`)); err == nil {
+		t.Errorf("expected an error for an invalid size")
+	}
+}
+
+func TestParseCRIEndpoint(t *testing.T) {
+	// the crictl config of the containerd node image, without a newline
+	endpoint, err := parseCRIEndpoint([]byte("runtime-endpoint: unix:///run/containerd/containerd.sock"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if endpoint != "unix:///run/containerd/containerd.sock" {
+		t.Errorf("unexpected endpoint %q", endpoint)
+	}
+	if _, err := parseCRIEndpoint([]byte("timeout: 10\n")); err == nil {
+		t.Error("expected an error for a config without a runtime-endpoint")
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	internalcreate "sigs.k8s.io/kind/pkg/cluster/internal/create"
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// OutputVersion is the version of the ClusterPlan and ClusterResult schemas.
+// Fields may be added within a version, it only changes when fields are
+// removed or change meaning, so tools wrapping kind can rely on it.
+const OutputVersion = "v1"
+
+// ClusterPlan describes what creating a cluster would do, for tools such as
+// infrastructure as code wrappers that track kind clusters as resources
+type ClusterPlan struct {
+	// Version is OutputVersion
+	Version string `json:"version"`
+	// Kind is always "ClusterPlan"
+	Kind string `json:"kind"`
+	// Name is the cluster name
+	Name string `json:"name"`
+	// Provider is the node provider, e.g. "docker"
+	Provider string `json:"provider"`
+	// KubeconfigPath is the file the cluster's kubeconfig would be merged into
+	KubeconfigPath string `json:"kubeconfigPath"`
+	// APIServer is where the API server would be published on the host
+	APIServer PlannedPort `json:"apiServer"`
+	// Nodes are the node containers that would be created
+	Nodes []PlannedNode `json:"nodes"`
+}
+
+// PlannedNode is a node container creating a cluster would create
+type PlannedNode struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Image string `json:"image"`
+	// Ports are the node's extraPortMappings
+	Ports []PlannedPort `json:"ports,omitempty"`
+}
+
+// PlannedPort is a port creating a cluster would publish on the host
+type PlannedPort struct {
+	ContainerPort int32 `json:"containerPort,omitempty"`
+	// Protocol is "tcp", "udp" or "sctp"
+	Protocol string `json:"protocol"`
+	HostIP   string `json:"hostIP"`
+	// HostPort is 0 if a free port will be picked when creating the cluster
+	HostPort int32 `json:"hostPort"`
+}
+
+// ClusterResult describes a created cluster, with the same identifying
+// fields as ClusterPlan
+type ClusterResult struct {
+	// Version is OutputVersion
+	Version string `json:"version"`
+	// Kind is always "ClusterResult"
+	Kind string `json:"kind"`
+	// Name is the cluster name
+	Name string `json:"name"`
+	// Provider is the node provider, e.g. "docker"
+	Provider string `json:"provider"`
+	// KubeconfigPath is the file the cluster's kubeconfig was merged into
+	KubeconfigPath string `json:"kubeconfigPath"`
+	// Network is the container network the nodes are attached to
+	Network *NetworkInfo `json:"network"`
+	// Nodes are the cluster's node containers
+	Nodes []CreatedNode `json:"nodes"`
+	// Ports are the ports the nodes publish on the host, see ListPorts
+	Ports []PortMapping `json:"ports"`
+}
+
+// CreatedNode is one of a cluster's node containers
+type CreatedNode struct {
+	Name        string `json:"name"`
+	Role        string `json:"role"`
+	ContainerID string `json:"containerID"`
+}
+
+// Plan returns what creating a cluster with the same name and options would
+// do, without creating anything. It fails like creating would if the config
+// is invalid, the cluster exists or a requested host port is in use.
+func (p *Provider) Plan(name string, options ...CreateOption) (*ClusterPlan, error) {
+	// apply options
+	opts := &internalcreate.ClusterOptions{
+		NameOverride: name,
+	}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return nil, err
+		}
+	}
+	planned, err := internalcreate.Plan(p.logger, p.provider, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newClusterPlan(p.Name(), kubeconfig.Path(opts.KubeconfigPath), opts.Config, planned), nil
+}
+
+// newClusterPlan returns the ClusterPlan of creating a cluster with the
+// defaulted config cfg, which would create the planned node containers
+func newClusterPlan(provider, kubeconfigPath string, cfg *config.Cluster, planned []internalcreate.PlannedNode) *ClusterPlan {
+	plan := &ClusterPlan{
+		Version:        OutputVersion,
+		Kind:           "ClusterPlan",
+		Name:           cfg.Name,
+		Provider:       provider,
+		KubeconfigPath: kubeconfigPath,
+		APIServer: PlannedPort{
+			Protocol: "tcp",
+			HostIP:   cfg.Networking.APIServerAddress,
+			HostPort: cfg.Networking.APIServerPort,
+		},
+		Nodes: []PlannedNode{},
+	}
+	for _, n := range planned {
+		node := PlannedNode{
+			Name:  n.Name,
+			Role:  n.Role,
+			Image: n.Image,
+		}
+		for _, m := range n.PortMappings {
+			node.Ports = append(node.Ports, PlannedPort{
+				ContainerPort: m.ContainerPort,
+				Protocol:      strings.ToLower(string(m.Protocol)),
+				HostIP:        m.ListenAddress,
+				HostPort:      m.HostPort,
+			})
+		}
+		plan.Nodes = append(plan.Nodes, node)
+	}
+	return plan
+}
+
+// Result describes the existing cluster name, where explicitKubeconfigPath
+// is the --kubeconfig value it was created with, if any
+func (p *Provider) Result(name, explicitKubeconfigPath string) (*ClusterResult, error) {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	network, err := p.GetNetworkInfo(name)
+	if err != nil {
+		return nil, err
+	}
+	ports, err := p.ListPorts(name)
+	if err != nil {
+		return nil, err
+	}
+	result := &ClusterResult{
+		Version:        OutputVersion,
+		Kind:           "ClusterResult",
+		Name:           defaultName(name),
+		Provider:       p.Name(),
+		KubeconfigPath: kubeconfig.Path(explicitKubeconfigPath),
+		Network:        network,
+		Nodes:          []CreatedNode{},
+		Ports:          ports,
+	}
+	for _, node := range n {
+		role, err := node.Role()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get role of node %q", node.String())
+		}
+		id, err := p.provider.ContainerID(node)
+		if err != nil {
+			return nil, err
+		}
+		result.Nodes = append(result.Nodes, CreatedNode{
+			Name:        node.String(),
+			Role:        role,
+			ContainerID: id,
+		})
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		return result.Nodes[i].Name < result.Nodes[j].Name
+	})
+	return result, nil
+}
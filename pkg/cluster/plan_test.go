@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	internalcreate "sigs.k8s.io/kind/pkg/cluster/internal/create"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+// The ClusterPlan and ClusterResult JSON is consumed by tools wrapping kind,
+// these golden files catch changes to it. Fields may only be added within an
+// OutputVersion.
+
+// expectGoldenJSON checks that v marshals to the contents of the golden file
+func expectGoldenJSON(t *testing.T, golden string, v interface{}) {
+	t.Helper()
+	actual, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	expected, err := os.ReadFile(filepath.Join("testdata", golden))
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	assert.StringEqual(t, string(expected), string(actual)+"\n")
+}
+
+func TestClusterPlanJSON(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Cluster{Name: "kind"}
+	config.SetDefaultsCluster(cfg)
+	cfg.Networking.APIServerPort = 6443
+	planned := []internalcreate.PlannedNode{
+		{
+			Name:  "kind-control-plane",
+			Role:  "control-plane",
+			Image: "kindest/node:v1.31.0",
+			PortMappings: []config.PortMapping{
+				{ContainerPort: 80, HostPort: 8080, ListenAddress: "127.0.0.1", Protocol: config.PortMappingProtocolTCP},
+				{ContainerPort: 53, ListenAddress: "0.0.0.0", Protocol: config.PortMappingProtocolUDP},
+			},
+		},
+		{
+			Name:  "kind-worker",
+			Role:  "worker",
+			Image: "kindest/node:v1.31.0",
+		},
+	}
+	plan := newClusterPlan("docker", "/home/user/.kube/config", cfg, planned)
+	expectGoldenJSON(t, "cluster-plan.json", plan)
+}
+
+func TestClusterResultJSON(t *testing.T) {
+	t.Parallel()
+	result := &ClusterResult{
+		Version:        OutputVersion,
+		Kind:           "ClusterResult",
+		Name:           "kind",
+		Provider:       "docker",
+		KubeconfigPath: "/home/user/.kube/config",
+		Network: &NetworkInfo{
+			Name:     "kind",
+			Subnets:  []string{"172.18.0.0/16", "fc00:f853:ccd:e793::/64"},
+			Gateways: []string{"172.18.0.1", "fc00:f853:ccd:e793::1"},
+			MTU:      1500,
+			Nodes: []NodeAddresses{
+				{Name: "kind-control-plane", IPv4: "172.18.0.2", IPv6: "fc00:f853:ccd:e793::2"},
+			},
+		},
+		Nodes: []CreatedNode{
+			{Name: "kind-control-plane", Role: "control-plane", ContainerID: "0123456789ab"},
+		},
+		Ports: []PortMapping{
+			{Node: "kind-control-plane", Role: "control-plane", ContainerPort: 6443, Protocol: "tcp", HostIP: "127.0.0.1", HostPort: 6443, APIServer: true},
+			{Node: "kind-control-plane", Role: "control-plane", ContainerPort: 80, Protocol: "tcp", HostIP: "0.0.0.0", HostPort: 8080},
+		},
+	}
+	expectGoldenJSON(t, "cluster-result.json", result)
+}
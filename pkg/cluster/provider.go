@@ -17,9 +17,12 @@ limitations under the License.
 package cluster
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/cmd/kind/version"
 
@@ -29,13 +32,22 @@ import (
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
+	"sigs.k8s.io/kind/pkg/cluster/internal/certs"
 	internalcreate "sigs.k8s.io/kind/pkg/cluster/internal/create"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/installloadbalancerpool"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/recordimagepulls"
 	internaldelete "sigs.k8s.io/kind/pkg/cluster/internal/delete"
+	"sigs.k8s.io/kind/pkg/cluster/internal/etcd"
 	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/protection"
 	internalproviders "sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers/docker"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/external"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers/nerdctl"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers/podman"
+	"sigs.k8s.io/kind/pkg/cluster/internal/ready"
+	"sigs.k8s.io/kind/pkg/cluster/internal/verify"
 )
 
 // DefaultName is the default cluster name
@@ -53,6 +65,8 @@ func defaultName(name string) string {
 type Provider struct {
 	provider internalproviders.Provider
 	logger   log.Logger
+	// kubeconfigContextTemplate names the clusters' kubeconfig entries
+	kubeconfigContextTemplate string
 }
 
 // NewProvider returns a new provider based on the supplied options
@@ -112,6 +126,11 @@ var NoNodeProviderDetectedError = errors.NewWithoutStack("failed to detect any s
 // In the future when this is not considered experimental,
 // that logic will be in a public API as well.
 func DetectNodeProvider() (ProviderOption, error) {
+	// podman configured to use a (socket activated) service is an explicit
+	// choice, even if a docker client is also installed
+	if podman.IsConfiguredRemote() && podman.IsAvailable() {
+		return ProviderWithPodman(), nil
+	}
 	// auto-detect based on each node provider's IsAvailable() function
 	if docker.IsAvailable() {
 		return ProviderWithDocker(), nil
@@ -125,6 +144,23 @@ func DetectNodeProvider() (ProviderOption, error) {
 	return nil, errors.WithStack(NoNodeProviderDetectedError)
 }
 
+// AvailableNodeProviders returns the names of the node providers whose
+// clients are available on the host, in auto-detection order.
+// This is intended for diagnostics, see DetectNodeProvider.
+func AvailableNodeProviders() []string {
+	available := []string{}
+	if docker.IsAvailable() {
+		available = append(available, "docker")
+	}
+	if nerdctl.IsAvailable() {
+		available = append(available, "nerdctl")
+	}
+	if podman.IsAvailable() {
+		available = append(available, "podman")
+	}
+	return available
+}
+
 // ProviderOption is an option for configuring a provider
 type ProviderOption interface {
 	apply(p *Provider)
@@ -147,6 +183,27 @@ func ProviderWithLogger(logger log.Logger) ProviderOption {
 	})
 }
 
+// providerKubeconfigOption is a trivial ProviderOption adapter
+type providerKubeconfigOption func(p *Provider)
+
+func (a providerKubeconfigOption) apply(p *Provider) {
+	a(p)
+}
+
+var _ ProviderOption = providerKubeconfigOption(nil)
+
+// ProviderWithKubeconfigContextTemplate configures the names of the
+// clusters' cluster, user and context entries in kubeconfig files.
+// contextTemplate is a text/template executed with the cluster's .Name,
+// e.g. "dev-{{.Name}}", and defaults to "kind-{{.Name}}" if empty.
+// KIND_KUBECONFIG_CONTEXT_TEMPLATE takes precedence over it if set.
+// The template is validated by Create before provisioning the cluster
+func ProviderWithKubeconfigContextTemplate(contextTemplate string) ProviderOption {
+	return providerKubeconfigOption(func(p *Provider) {
+		p.kubeconfigContextTemplate = contextTemplate
+	})
+}
+
 // providerRuntimeOption is a trivial ProviderOption adapter
 // we use a type specific to logging options so we can handle them first
 type providerRuntimeOption func(p *Provider)
@@ -178,23 +235,446 @@ func ProviderWithNerdctl(binaryName string) ProviderOption {
 	})
 }
 
+// ProviderWithExternal configures the provider to use the external provider
+// executable kind-provider-<name>, see sigs.k8s.io/kind/pkg/cluster/external
+func ProviderWithExternal(name string) ProviderOption {
+	return providerRuntimeOption(func(p *Provider) {
+		p.provider = external.NewProvider(p.logger, name)
+	})
+}
+
 // Create provisions and starts a kubernetes-in-docker cluster
 func (p *Provider) Create(name string, options ...CreateOption) error {
+	return p.CreateContext(context.Background(), name, options...)
+}
+
+// CreateContext is like Create, but cluster creation stops when ctx is
+// cancelled. The partially created cluster is then deleted, unless
+// CreateWithRetain is set, and an error is returned. Unlike
+// CreateWithRetain, CreateWithRetainOnFailure does not keep it.
+func (p *Provider) CreateContext(ctx context.Context, name string, options ...CreateOption) error {
 	// apply options
 	opts := &internalcreate.ClusterOptions{
-		NameOverride: name,
+		NameOverride:              name,
+		KubeconfigContextTemplate: p.kubeconfigContextTemplate,
 	}
 	for _, o := range options {
 		if err := o.apply(opts); err != nil {
 			return err
 		}
 	}
-	return internalcreate.Cluster(p.logger, p.provider, opts)
+	return internalcreate.Cluster(ctx, p.logger, p.provider, opts)
+}
+
+// Artifacts returns the container images that creating a cluster with the
+// same options would require, including the images preloaded in the node
+// images. This is useful for mirroring images into a private registry.
+func (p *Provider) Artifacts(options ...CreateOption) ([]string, error) {
+	// apply options
+	opts := &internalcreate.ClusterOptions{}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return nil, err
+		}
+	}
+	return internalcreate.Artifacts(p.provider, opts)
 }
 
 // Delete tears down a kubernetes-in-docker cluster
-func (p *Provider) Delete(name, explicitKubeconfigPath string) error {
-	return internaldelete.Cluster(p.logger, p.provider, defaultName(name), explicitKubeconfigPath)
+// Protected clusters are not deleted unless DeleteWithForce is set,
+// see DeleteWithExportLogs to capture the cluster logs first
+func (p *Provider) Delete(name, explicitKubeconfigPath string, options ...DeleteOption) error {
+	// apply options
+	opts := &deleteOptions{}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return err
+		}
+	}
+	if opts.ExportLogsDir != "" {
+		if err := p.exportLogsBeforeDelete(defaultName(name), opts.ExportLogsDir, opts.Force); err != nil {
+			return err
+		}
+	}
+	return internaldelete.Cluster(p.logger, p.provider, defaultName(name), p.kubeconfigContextTemplate, explicitKubeconfigPath, opts.Force)
+}
+
+// AddNode adds a worker node to the existing cluster and joins it to
+// Kubernetes, returning the new node's name. The node is configured like
+// the first worker the cluster was created with, or like its bootstrap
+// control plane if it has no workers.
+func (p *Provider) AddNode(ctx context.Context, name string, options ...AddNodeOption) (string, error) {
+	opts := &internalcreate.NodeOptions{}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return "", err
+		}
+	}
+	return internalcreate.Node(ctx, p.logger, p.provider, defaultName(name), opts)
+}
+
+// DeleteNode removes the worker node nodeName from the cluster, deleting it
+// from Kubernetes and then deleting its container.
+// See DeleteNodeWithDrain to evict its pods first.
+func (p *Provider) DeleteNode(name, nodeName string, options ...DeleteNodeOption) error {
+	// apply options, by default pods keep their own grace period
+	opts := &internaldelete.NodeOptions{GracePeriod: -1}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return err
+		}
+	}
+	return internaldelete.Node(p.logger, p.provider, defaultName(name), nodeName, *opts)
+}
+
+// exportLogsBeforeDelete collects the cluster logs into dir, only warning
+// on failure as this must not block deletion. Protection is checked first
+// unless force is set, so that logs are not exported for a cluster that
+// will not be deleted
+func (p *Provider) exportLogsBeforeDelete(name, dir string, force bool) error {
+	n, err := p.provider.ListNodes(name)
+	if err != nil || len(n) == 0 {
+		return nil
+	}
+	if !force {
+		if err := internaldelete.CheckProtection(name, n); err != nil {
+			return err
+		}
+	}
+	p.logger.V(0).Infof("Exporting logs for cluster %q to: %s", name, dir)
+	if err := p.CollectLogs(name, dir); err != nil {
+		p.logger.Warnf("Failed to export logs for cluster %q: %v", name, err)
+	}
+	return nil
+}
+
+// Protect protects the cluster from deletion, see DeleteWithForce
+func (p *Provider) Protect(name string) error {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return err
+	}
+	if len(n) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	return protection.Protect(n)
+}
+
+// Unprotect removes deletion protection from the cluster
+func (p *Provider) Unprotect(name string) error {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return err
+	}
+	if len(n) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	return protection.Unprotect(n)
+}
+
+// RenewCerts renews the cluster certificates on the control plane nodes,
+// restarts the control plane and then exports the renewed KUBECONFIG as
+// with ExportKubeConfig, where explicitKubeconfigPath is the --kubeconfig value
+func (p *Provider) RenewCerts(name, explicitKubeconfigPath string) error {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return err
+	}
+	if len(n) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	if err := certs.Renew(p.logger, n); err != nil {
+		return err
+	}
+	return kubeconfig.Export(p.provider, defaultName(name), p.kubeconfigContextTemplate, explicitKubeconfigPath, true, kubeconfig.FileOptions{})
+}
+
+// Wait waits up to timeout for condition to be met on the cluster, it
+// returns an error if the condition is not met in time or ctx is done.
+// The condition is one of "ready" (all control plane nodes are Ready, as
+// waited for by CreateWithWaitForReady), "apiserver-available" or
+// "nodes=<count>" (at least count nodes are Ready).
+func (p *Provider) Wait(ctx context.Context, name, condition string, timeout time.Duration) error {
+	c, err := ready.ParseCondition(condition)
+	if err != nil {
+		return err
+	}
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return err
+	}
+	if len(n) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	met, err := ready.Wait(ctx, n, c, time.Now().Add(timeout))
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !met {
+		return errors.Errorf("timed out after %s waiting for %s", timeout, c)
+	}
+	return nil
+}
+
+// Verify runs smoke tests against the cluster, logging a pass / fail matrix
+// and returning an error if any test failed
+func (p *Provider) Verify(ctx context.Context, name string) error {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return err
+	}
+	if len(n) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	results, err := verify.Run(ctx, n)
+	if err != nil {
+		return err
+	}
+	return verify.Report(p.logger, results)
+}
+
+// NetworkInfo describes the container network a cluster's nodes are
+// attached to
+type NetworkInfo struct {
+	// Name is the container runtime's name for the network
+	Name string `json:"name"`
+	// Subnets are the network's IPv4 and IPv6 subnets
+	Subnets []string `json:"subnets"`
+	// Gateways are the network's IPv4 and IPv6 gateways
+	Gateways []string `json:"gateways"`
+	// MTU is 0 if the network uses the container runtime's default MTU
+	MTU int `json:"mtu"`
+	// Nodes are the addresses of the cluster's nodes on the network
+	Nodes []NodeAddresses `json:"nodes"`
+	// LoadBalancerPool are the address ranges reserved for LoadBalancer
+	// services, see the loadBalancerPool addon
+	LoadBalancerPool []string `json:"loadBalancerPool,omitempty"`
+}
+
+// NodeAddresses are a node's addresses on the cluster network
+type NodeAddresses struct {
+	Name string `json:"name"`
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+// GetNetworkInfo returns the network the cluster's nodes are attached to
+// and their addresses on it
+func (p *Provider) GetNetworkInfo(name string) (*NetworkInfo, error) {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	network, err := p.provider.GetNetwork(defaultName(name))
+	if err != nil {
+		return nil, err
+	}
+	info := &NetworkInfo{
+		Name:     network.Name,
+		Subnets:  network.Subnets,
+		Gateways: network.Gateways,
+		MTU:      network.MTU,
+	}
+	for _, node := range n {
+		ipv4, ipv6, err := node.IP()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get addresses of node %q", node.String())
+		}
+		info.Nodes = append(info.Nodes, NodeAddresses{
+			Name: node.String(),
+			IPv4: ipv4,
+			IPv6: ipv6,
+		})
+	}
+	sort.Slice(info.Nodes, func(i, j int) bool {
+		return info.Nodes[i].Name < info.Nodes[j].Name
+	})
+	if info.LoadBalancerPool, err = installloadbalancerpool.ReadPool(n); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// PortMapping is a container port one of a cluster's nodes publishes on the
+// host
+type PortMapping struct {
+	// Node is the name of the node container publishing the port
+	Node string `json:"node"`
+	// Role is the node's role, e.g. "control-plane" or "external-load-balancer"
+	Role string `json:"role"`
+	// ContainerPort is the port inside the node container
+	ContainerPort int32 `json:"containerPort"`
+	// Protocol is "tcp", "udp" or "sctp"
+	Protocol string `json:"protocol"`
+	// HostIP is the host address the port is published on, an unspecified
+	// address such as "0.0.0.0" means all of the host's addresses
+	HostIP string `json:"hostIP"`
+	// HostPort is the port on the host
+	HostPort int32 `json:"hostPort"`
+	// APIServer is true for the cluster's API server endpoint
+	APIServer bool `json:"apiServer,omitempty"`
+}
+
+// ListPorts returns the ports the cluster's nodes publish on the host,
+// including the API server, the nodes' extraPortMappings and the control
+// plane load balancer, sorted by node and container port
+func (p *Provider) ListPorts(name string) ([]PortMapping, error) {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	apiServerNode, err := nodeutils.APIServerEndpointNode(n)
+	if err != nil {
+		return nil, err
+	}
+	ports := []PortMapping{}
+	for _, node := range n {
+		role, err := node.Role()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get role of node %q", node.String())
+		}
+		mappings, err := p.provider.ListPorts(node)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mappings {
+			ports = append(ports, PortMapping{
+				Node:          node.String(),
+				Role:          role,
+				ContainerPort: m.ContainerPort,
+				Protocol:      m.Protocol,
+				HostIP:        m.HostIP,
+				HostPort:      m.HostPort,
+				APIServer:     node.String() == apiServerNode.String() && m.ContainerPort == common.APIServerInternalPort && m.Protocol == "tcp",
+			})
+		}
+	}
+	sort.SliceStable(ports, func(i, j int) bool {
+		if ports[i].Node != ports[j].Node {
+			return ports[i].Node < ports[j].Node
+		}
+		return ports[i].ContainerPort < ports[j].ContainerPort
+	})
+	return ports, nil
+}
+
+// NodeEvent is a container runtime event for one of a cluster's node
+// containers
+type NodeEvent struct {
+	Time time.Time `json:"time"`
+	// Node is the name of the node container
+	Node string `json:"node"`
+	// Role is the node's role, e.g. "control-plane"
+	Role string `json:"role,omitempty"`
+	// Action is one of "start", "stop", "kill", "die", "oom" or "restart"
+	Action string `json:"action"`
+	// ExitCode is the node container's exit code for "die" events
+	ExitCode int `json:"exitCode,omitempty"`
+}
+
+// ListEvents returns the container runtime events for the cluster's node
+// containers since the given time, which may be zero for all of the events
+// the runtime still has
+func (p *Provider) ListEvents(name string, since time.Time) ([]NodeEvent, error) {
+	events := []NodeEvent{}
+	err := p.provider.Events(context.Background(), defaultName(name), since, false, func(e internalproviders.Event) {
+		events = append(events, nodeEvent(e))
+	})
+	return events, err
+}
+
+// WatchEvents calls handle with the container runtime events for the
+// cluster's node containers, starting with those since the given time if it
+// is not zero, until ctx is cancelled.
+// This allows failing fast when a node container dies or is OOM killed
+func (p *Provider) WatchEvents(ctx context.Context, name string, since time.Time, handle func(NodeEvent)) error {
+	return p.provider.Events(ctx, defaultName(name), since, true, func(e internalproviders.Event) {
+		handle(nodeEvent(e))
+	})
+}
+
+func nodeEvent(e internalproviders.Event) NodeEvent {
+	return NodeEvent{
+		Time:     e.Time,
+		Node:     e.Node,
+		Role:     e.Role,
+		Action:   e.Action,
+		ExitCode: e.ExitCode,
+	}
+}
+
+// PulledImages returns the fully qualified images the cluster's nodes were
+// asked to pull, sorted, if the cluster was created with recordImagePulls
+func (p *Provider) PulledImages(name string) ([]string, error) {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	return recordimagepulls.Read(n)
+}
+
+// ExportEtcdSnapshot saves a snapshot of the cluster's etcd to path
+func (p *Provider) ExportEtcdSnapshot(name, path string) error {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return err
+	}
+	if len(n) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to create snapshot file")
+	}
+	if err := etcd.Save(n, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// RestoreEtcdSnapshot replaces the cluster's etcd data with the snapshot at
+// path, as written by ExportEtcdSnapshot. The control plane is restarted.
+func (p *Provider) RestoreEtcdSnapshot(name, path string) error {
+	n, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return err
+	}
+	if len(n) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", defaultName(name))
+	}
+	snapshot, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read snapshot file")
+	}
+	return etcd.Restore(p.logger, n, snapshot)
+}
+
+// Name returns the name of the node provider, e.g. "docker"
+func (p *Provider) Name() string {
+	if s, ok := p.provider.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return "unknown"
+}
+
+// Check returns an error if the node provider cannot be used, e.g. because
+// its daemon or service is not running
+func (p *Provider) Check() error {
+	_, err := p.provider.Info()
+	return err
 }
 
 // List returns a list of clusters for which nodes exist
@@ -206,15 +686,78 @@ func (p *Provider) List() ([]string, error) {
 // If internal is true, this will contain the internal IP etc.
 // If internal is false, this will contain the host IP etc.
 func (p *Provider) KubeConfig(name string, internal bool) (string, error) {
-	return kubeconfig.Get(p.provider, defaultName(name), !internal)
+	return kubeconfig.Get(p.provider, defaultName(name), p.kubeconfigContextTemplate, !internal)
+}
+
+// ClientConfig is the endpoint and credentials needed to connect to a
+// cluster's API server. The fields are named after those of a client-go
+// rest.Config, which can be built without writing a kubeconfig to disk:
+//
+//	cfg := &rest.Config{
+//		Host: c.Host,
+//		TLSClientConfig: rest.TLSClientConfig{
+//			CAData:   c.CAData,
+//			CertData: c.CertData,
+//			KeyData:  c.KeyData,
+//		},
+//	}
+type ClientConfig struct {
+	// Host is the API server URL, e.g. https://127.0.0.1:6443
+	Host string
+	// CAData is the PEM encoded cluster CA certificate
+	CAData []byte
+	// CertData is the PEM encoded client certificate
+	CertData []byte
+	// KeyData is the PEM encoded client key
+	KeyData []byte
+}
+
+// ClientConfig returns the ClientConfig for the cluster, using the API
+// server's host endpoint or, if internal is true, its address on the
+// cluster network, for clients that run in a container on that network
+func (p *Provider) ClientConfig(name string, internal bool) (*ClientConfig, error) {
+	c, err := kubeconfig.GetClient(p.provider, defaultName(name), !internal)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientConfig{
+		Host:     c.Server,
+		CAData:   c.CertificateAuthorityData,
+		CertData: c.ClientCertificateData,
+		KeyData:  c.ClientKeyData,
+	}, nil
 }
 
 // ExportKubeConfig exports the KUBECONFIG for the cluster, merging
 // it into the selected file, following the rules from
 // https://kubernetes.io/docs/reference/generated/kubectl/kubectl-commands#config
 // where explicitPath is the --kubeconfig value.
-func (p *Provider) ExportKubeConfig(name string, explicitPath string, internal bool) error {
-	return kubeconfig.Export(p.provider, defaultName(name), explicitPath, !internal)
+// Existing files keep their mode and owner unless options set them.
+func (p *Provider) ExportKubeConfig(name string, explicitPath string, internal bool, options ...ExportKubeConfigOption) error {
+	opts := kubeconfig.FileOptions{}
+	for _, o := range options {
+		if err := o.apply(&opts); err != nil {
+			return err
+		}
+	}
+	return kubeconfig.Export(p.provider, defaultName(name), p.kubeconfigContextTemplate, explicitPath, !internal, opts)
+}
+
+// KubeConfigContext returns the name of the cluster's context in the
+// KUBECONFIG, which is also the name of its cluster and user entries.
+// This is "kind-<name>" unless KIND_KUBECONFIG_CONTEXT_TEMPLATE or
+// ProviderWithKubeconfigContextTemplate set a text/template, which is
+// executed with the cluster's .Name
+func (p *Provider) KubeConfigContext(name string) (string, error) {
+	return kubeconfig.ContextForCluster(defaultName(name), p.kubeconfigContextTemplate)
+}
+
+// UnsetKubeConfig switches the current context of the kubeconfig back to the
+// context that was current before the cluster's context was selected, if
+// the cluster's context is the current context. explicitPath is the
+// --kubeconfig value.
+func (p *Provider) UnsetKubeConfig(name string, explicitPath string) error {
+	return kubeconfig.Unset(defaultName(name), p.kubeconfigContextTemplate, explicitPath)
 }
 
 // ListNodes returns the list of container IDs for the "nodes" in the cluster
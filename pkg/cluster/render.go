@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+
+	internalcreate "sigs.k8s.io/kind/pkg/cluster/internal/create"
+	internalconfig "sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// RenderedConfig is what creating a cluster would generate from its config,
+// for debugging the config or golden file tests of it
+type RenderedConfig struct {
+	// Name is the cluster name
+	Name string `json:"name"`
+	// Provider is the node provider, e.g. "docker"
+	Provider string `json:"provider"`
+	// Config is the fully defaulted config, with allNodes merged into nodes
+	Config *v1alpha4.Cluster `json:"config"`
+	// Nodes are the node containers that would be created
+	Nodes []RenderedNode `json:"nodes"`
+}
+
+// RenderedNode is what creating a cluster would generate for one of its
+// node containers
+type RenderedNode struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Image string `json:"image"`
+	// RunArgs are the arguments the container runtime would create the node
+	// container with, host ports that would be picked at random are left
+	// empty, e.g. --publish=127.0.0.1::6443
+	RunArgs []string `json:"runArgs"`
+	// KubeadmConfig is the node's kubeadm config, with placeholders for the
+	// node's addresses. It is empty for nodes that are not Kubernetes nodes.
+	KubeadmConfig string `json:"kubeadmConfig,omitempty"`
+}
+
+// Render returns what creating a cluster with the same name and options
+// would generate, without creating anything or needing the container runtime.
+// The kubeadm configs are for kubernetesVersion if set, otherwise the version
+// in each node image's tag, and for a rootful container runtime.
+func (p *Provider) Render(name, kubernetesVersion string, options ...CreateOption) (*RenderedConfig, error) {
+	// apply options
+	opts := &internalcreate.ClusterOptions{
+		NameOverride: name,
+	}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return nil, err
+		}
+	}
+	nodes, err := internalcreate.Render(p.provider, opts, kubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	rendered := &RenderedConfig{
+		Name:     opts.Config.Name,
+		Provider: p.Name(),
+		Config:   internalconfig.ConvertToV1alpha4(opts.Config),
+		Nodes:    []RenderedNode{},
+	}
+	for _, n := range nodes {
+		rendered.Nodes = append(rendered.Nodes, RenderedNode{
+			Name:          n.Name,
+			Role:          n.Role,
+			Image:         n.Image,
+			RunArgs:       n.RunArgs,
+			KubeadmConfig: n.KubeadmConfig,
+		})
+	}
+	return rendered, nil
+}
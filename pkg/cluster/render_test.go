@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	internalproviders "sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// renderProvider is a provider for rendering only, Render must not call any
+// of the other methods as they would need the container runtime
+type renderProvider struct {
+	internalproviders.Provider
+}
+
+func (renderProvider) String() string {
+	return "fake"
+}
+
+func (renderProvider) RunArgs(cfg *config.Cluster) (map[string][]string, error) {
+	runArgs := map[string][]string{}
+	for _, name := range []string{"render-control-plane", "render-worker"} {
+		runArgs[name] = []string{"--hostname", name}
+	}
+	return runArgs, nil
+}
+
+// The rendered config is meant to be checked in as a golden file by users,
+// this catches unintended changes to it
+func TestRenderJSON(t *testing.T) {
+	t.Parallel()
+	p := &Provider{provider: renderProvider{}}
+	rendered, err := p.Render("render", "", CreateWithV1Alpha4Config(&v1alpha4.Cluster{
+		Nodes: []v1alpha4.Node{
+			{
+				Role:  v1alpha4.ControlPlaneRole,
+				Image: "kindest/node:v1.32.0",
+				ExtraPortMappings: []v1alpha4.PortMapping{
+					{ContainerPort: 80, HostPort: 8080},
+				},
+			},
+			{
+				Role:  v1alpha4.WorkerRole,
+				Image: "kindest/node:v1.32.0",
+			},
+		},
+		Networking: v1alpha4.Networking{
+			APIServerPort: 6443,
+		},
+	}))
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	expectGoldenJSON(t, "render.json", rendered)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// PruneVolumes deletes the image cache volumes of clusters that no longer
+// exist, or only those of the named cluster if name is not empty.
+// It returns the names of the deleted volumes.
+// See the imageCache node config field.
+func (p *Provider) PruneVolumes(name string) ([]string, error) {
+	clusters, err := p.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range clusters {
+		if name != "" && c == name {
+			return nil, errors.Errorf("cluster %q still exists, delete it before pruning its volumes", name)
+		}
+	}
+
+	volumes, err := p.provider.ListImageCacheVolumes()
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(clusters))
+	for _, c := range clusters {
+		existing[c] = true
+	}
+	var prune []string
+	for _, v := range volumes {
+		if existing[v.Cluster] || (name != "" && v.Cluster != name) {
+			continue
+		}
+		prune = append(prune, v.Name)
+	}
+	sort.Strings(prune)
+
+	if err := p.provider.DeleteVolumes(prune); err != nil {
+		return nil, err
+	}
+	return prune, nil
+}
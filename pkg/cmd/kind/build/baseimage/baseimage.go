@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package baseimage implements the `base-image` command
+package baseimage
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/build/base"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+type flagpole struct {
+	Image     string
+	Platforms []string
+	Builder   string
+	GoVersion string
+	BuildArgs []string
+	Push      bool
+}
+
+// NewCommand returns a new cobra.Command for building the base image
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.MaximumNArgs(1),
+		Use:   "base-image [base-image-source]",
+		Short: "Build the base image",
+		Long:  "Build the base image, from images/base or the given directory, that node images are built on",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, flags, args)
+		},
+	}
+	cmd.Flags().StringVar(
+		&flags.Image,
+		"image",
+		base.DefaultImage,
+		"name:tag of the resulting image to be built",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Platforms,
+		"platforms",
+		nil,
+		"platforms to build for, any of "+strings.Join(base.SupportedPlatforms, ", ")+". defaults to the docker server's platform",
+	)
+	cmd.Flags().StringVar(
+		&flags.Builder,
+		"builder",
+		"",
+		"buildx builder instance to build with, defaults to the current builder",
+	)
+	cmd.Flags().StringVar(
+		&flags.GoVersion,
+		"go-version",
+		"",
+		"Go version to build the base image components with, defaults to the .go-version of the repo containing the source",
+	)
+	cmd.Flags().StringArrayVar(
+		&flags.BuildArgs,
+		"build-arg",
+		nil,
+		"NAME=VALUE build argument of the base image Dockerfile, e.g. CONTAINERD_VERSION=v2.0.2, may be repeated",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Push,
+		"push",
+		false,
+		"push the built image to its registry instead of loading it, required for more than one platform",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, args []string) error {
+	options := []base.Option{
+		base.WithImage(flags.Image),
+		base.WithLogger(logger),
+		base.WithPlatforms(flags.Platforms),
+		base.WithBuilder(flags.Builder),
+		base.WithGoVersion(flags.GoVersion),
+		base.WithPush(flags.Push),
+	}
+	if len(args) > 0 {
+		options = append(options, base.WithSource(args[0]))
+	}
+	for _, buildArg := range flags.BuildArgs {
+		parts := strings.SplitN(buildArg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return errors.Errorf("invalid --build-arg %q, expected NAME=VALUE", buildArg)
+		}
+		options = append(options, base.WithBuildArg(parts[0], parts[1]))
+	}
+	// stop the build on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := base.BuildContext(ctx, options...); err != nil {
+		return errors.Wrap(err, "error building base image")
+	}
+	return nil
+}
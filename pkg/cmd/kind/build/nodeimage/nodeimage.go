@@ -17,6 +17,12 @@ limitations under the License.
 package nodeimage
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/build/nodeimage"
@@ -26,11 +32,23 @@ import (
 )
 
 type flagpole struct {
-	Source    string
-	BuildType string
-	Image     string
-	BaseImage string
-	Arch      string
+	Source      string
+	BuildType   string
+	Image       string
+	BaseImage   string
+	Arch        string
+	CRI         string
+	CRIOVersion string
+	WasmShims   bool
+	Push        bool
+	Compression string
+	Cache       bool
+	DebugTools  []string
+
+	ContainerdVersion string
+	RuncVersion       string
+	CrictlVersion     string
+	CNIPluginsVersion string
 }
 
 // NewCommand returns a new cobra.Command for building the node image
@@ -70,6 +88,72 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		"",
 		"architecture to build for, defaults to the host architecture",
 	)
+	cmd.Flags().StringVar(
+		&flags.CRI,
+		"cri",
+		"",
+		"container runtime to install, one of 'containerd' (the default) or 'crio' (experimental)",
+	)
+	cmd.Flags().StringVar(
+		&flags.CRIOVersion,
+		"crio-version",
+		"",
+		"install this CRI-O release with --cri crio, e.g. v1.31.1, instead of "+nodeimage.DefaultCRIOVersion,
+	)
+	cmd.Flags().BoolVar(
+		&flags.WasmShims,
+		"wasm-shims",
+		false,
+		"install the spin and wasmtime containerd shims for WebAssembly workloads",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Push,
+		"push",
+		false,
+		"push the built image to its registry, and print the image reference pinned by digest",
+	)
+	cmd.Flags().StringVar(
+		&flags.Compression,
+		"compression",
+		"zstd",
+		"layer compression used with --push, one of 'zstd' or 'gzip'. zstd requires docker buildx and the containerd image store",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Cache,
+		"cache",
+		false,
+		"cache the build stages as local kind-build-cache images, resuming from them when their inputs are unchanged",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.DebugTools,
+		"debug-tools",
+		nodeimage.DefaultDebugTools,
+		"debug tools to include in the image, any of: "+strings.Join(nodeimage.DebugTools, ", ")+". Use --debug-tools= for a minimal image without them",
+	)
+	cmd.Flags().StringVar(
+		&flags.ContainerdVersion,
+		"containerd-version",
+		"",
+		"install this containerd release, e.g. v2.0.2, instead of the base image's",
+	)
+	cmd.Flags().StringVar(
+		&flags.RuncVersion,
+		"runc-version",
+		"",
+		"install this runc release, e.g. v1.2.3, instead of the base image's",
+	)
+	cmd.Flags().StringVar(
+		&flags.CrictlVersion,
+		"crictl-version",
+		"",
+		"install this crictl release, e.g. v1.32.0, instead of the base image's",
+	)
+	cmd.Flags().StringVar(
+		&flags.CNIPluginsVersion,
+		"cni-plugins-version",
+		"",
+		"install this CNI plugins release, e.g. v1.6.1, instead of the base image's",
+	)
 	return cmd
 }
 
@@ -78,13 +162,27 @@ func runE(logger log.Logger, flags *flagpole, args []string) error {
 	if len(args) > 0 {
 		sourceSpec = args[0]
 	}
-	if err := nodeimage.Build(
+	// stop and remove the build container on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := nodeimage.BuildContext(
+		ctx,
 		nodeimage.WithImage(flags.Image),
 		nodeimage.WithBaseImage(flags.BaseImage),
 		nodeimage.WithKubeParam(sourceSpec),
 		nodeimage.WithLogger(logger),
 		nodeimage.WithArch(flags.Arch),
 		nodeimage.WithBuildType(flags.BuildType),
+		nodeimage.WithCRI(flags.CRI),
+		nodeimage.WithCRIOVersion(flags.CRIOVersion),
+		nodeimage.WithWasmShims(flags.WasmShims),
+		nodeimage.WithPush(flags.Push, flags.Compression),
+		nodeimage.WithCache(flags.Cache),
+		nodeimage.WithDebugTools(flags.DebugTools),
+		nodeimage.WithContainerdVersion(flags.ContainerdVersion),
+		nodeimage.WithRuncVersion(flags.RuncVersion),
+		nodeimage.WithCrictlVersion(flags.CrictlVersion),
+		nodeimage.WithCNIPluginsVersion(flags.CNIPluginsVersion),
 	); err != nil {
 		return errors.Wrap(err, "error building node image")
 	}
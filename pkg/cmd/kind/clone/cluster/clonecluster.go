@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster implements the `clone cluster` command
+package cluster
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	CopyImages bool
+	Wait       time.Duration
+	Kubeconfig string
+}
+
+// NewCommand returns a new cobra.Command for cloning a cluster
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		Use:   "cluster <source-cluster> <new-cluster>",
+		Short: "Creates a new cluster with the same config as an existing cluster",
+		Long: "Creates a new cluster with the same config the source cluster was created with, " +
+			"including its nodes, mounts and port mappings. Fixed host ports that are in use, " +
+			"e.g. by the source cluster, are replaced with free ports.\n\n" +
+			"With --copy-images the images loaded into the source cluster's nodes, e.g. with kind load, " +
+			"are copied to the new cluster's nodes.",
+		Example: "  kind clone cluster kind kind-2\n" +
+			"  kind clone cluster dev repro --copy-images",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// only override the source config's timeouts.nodesReady if set
+			return runE(logger, flags, args[0], args[1], cmd.Flags().Changed("wait"))
+		},
+	}
+	cmd.Flags().BoolVar(
+		&flags.CopyImages,
+		"copy-images",
+		false,
+		"copy the images loaded into the source cluster's nodes to the new cluster's nodes",
+	)
+	cmd.Flags().DurationVar(
+		&flags.Wait,
+		"wait",
+		time.Duration(0),
+		"wait for control plane node to be ready, overrides timeouts.nodesReady in the source config if set",
+	)
+	cmd.Flags().StringVar(
+		&flags.Kubeconfig,
+		"kubeconfig",
+		"",
+		"sets kubeconfig path instead of $KUBECONFIG or $HOME/.kube/config",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, src, dst string, wait bool) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+		runtime.KubeconfigContextTemplate(),
+	)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	opts := []cluster.CreateOption{
+		cluster.CreateWithKubeconfigPath(flags.Kubeconfig),
+		cluster.CreateWithDisplayUsage(true),
+		cluster.CreateWithDisplaySalutation(true),
+	}
+	if wait {
+		opts = append(opts, cluster.CreateWithWaitForReady(flags.Wait))
+	}
+	if err := provider.Clone(ctx, src, dst, flags.CopyImages, opts...); err != nil {
+		return errors.Wrapf(err, "failed to clone cluster %q", src)
+	}
+	return nil
+}
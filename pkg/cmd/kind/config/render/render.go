@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render implements the `render` command
+package render
+
+import (
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name              string
+	Config            string
+	ImageName         string
+	KubernetesVersion string
+}
+
+// the rendered config is written as separate documents, identified by kind
+type configDocument struct {
+	Kind     string            `json:"kind"`
+	Cluster  string            `json:"cluster"`
+	Provider string            `json:"provider"`
+	Config   *v1alpha4.Cluster `json:"config"`
+}
+
+type kubeadmConfigDocument struct {
+	Kind    string `json:"kind"`
+	Cluster string `json:"cluster"`
+	Node    string `json:"node"`
+	Config  string `json:"config"`
+}
+
+type runArgsDocument struct {
+	Kind    string   `json:"kind"`
+	Cluster string   `json:"cluster"`
+	Node    string   `json:"node"`
+	Args    []string `json:"args"`
+}
+
+// NewCommand returns a new cobra.Command for rendering what kind generates
+// from a config
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "render",
+		Short: "Prints what creating a cluster would generate from a config",
+		Long: "Prints what creating a cluster would generate from a config, without creating anything, " +
+			"as separate YAML documents: the fully defaulted config (kind: Config), " +
+			"the kubeadm config of each Kubernetes node (kind: KubeadmConfig) " +
+			"and the container run arguments of each node (kind: RunArgs).\n\n" +
+			"Node addresses are only known once the nodes exist, the kubeadm configs use placeholders for them. " +
+			"Host ports that would be picked at random are left empty in the run arguments, e.g. --publish=127.0.0.1::6443. " +
+			"The Kubernetes version is read from the node image tag unless --kubernetes-version is set. " +
+			"The kubeadm configs are rendered for a rootful container runtime, which need not be running.",
+		Example: "  kind config render\n" +
+			"  kind config render --config kind-config.yaml > rendered.yaml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		"",
+		"cluster name, overrides KIND_CLUSTER_NAME, config (default kind)",
+	)
+	cmd.Flags().StringVar(
+		&flags.Config,
+		"config",
+		"",
+		"path to a kind config file",
+	)
+	cmd.Flags().StringVar(
+		&flags.ImageName,
+		"image",
+		"",
+		"node docker image to use for booting the cluster",
+	)
+	cmd.Flags().StringVar(
+		&flags.KubernetesVersion,
+		"kubernetes-version",
+		"",
+		"Kubernetes version of the node images, e.g. v1.32.0, if their tags do not include it",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+
+	rendered, err := provider.Render(
+		flags.Name,
+		flags.KubernetesVersion,
+		cluster.CreateWithConfigFile(flags.Config),
+		cluster.CreateWithNodeImage(flags.ImageName),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to render config")
+	}
+
+	documents := []interface{}{
+		configDocument{
+			Kind:     "Config",
+			Cluster:  rendered.Name,
+			Provider: rendered.Provider,
+			Config:   rendered.Config,
+		},
+	}
+	for _, n := range rendered.Nodes {
+		if n.KubeadmConfig == "" {
+			continue
+		}
+		documents = append(documents, kubeadmConfigDocument{
+			Kind:    "KubeadmConfig",
+			Cluster: rendered.Name,
+			Node:    n.Name,
+			Config:  n.KubeadmConfig,
+		})
+	}
+	for _, n := range rendered.Nodes {
+		documents = append(documents, runArgsDocument{
+			Kind:    "RunArgs",
+			Cluster: rendered.Name,
+			Node:    n.Name,
+			Args:    n.RunArgs,
+		})
+	}
+
+	for i, document := range documents {
+		out, err := yaml.Marshal(document)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode rendered config")
+		}
+		if i > 0 {
+			out = append([]byte("---\n"), out...)
+		}
+		if _, err := streams.Out.Write(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,27 +18,53 @@ limitations under the License.
 package cluster
 
 import (
+	"context"
+	"encoding/json"
 	"io"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cmd"
+	kindversion "sigs.k8s.io/kind/pkg/cmd/kind/version"
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/debugbundle"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
+	"sigs.k8s.io/kind/pkg/internal/tracing"
+	"sigs.k8s.io/kind/pkg/internal/usersettings"
 )
 
 type flagpole struct {
-	Name       string
-	Config     string
-	ImageName  string
-	Retain     bool
-	Wait       time.Duration
-	Kubeconfig string
+	Name               string
+	NamePrefix         string
+	NameFile           string
+	Config             string
+	ImageName          string
+	ControlPlaneImage  string
+	WorkerImage        string
+	InsecureRegistries []string
+	Retain             bool
+	RetainOnFailure    bool
+	Protect            bool
+	AutoRemapPorts     bool
+	Wait               time.Duration
+	Verify             bool
+	Detach             bool
+	Kubeconfig         string
+	KubeconfigMode     string
+	KubeconfigOwner    string
+	DebugBundle        string
+	DryRun             bool
+	Output             string
+	StatusFD           int
+	StatusFile         string
 }
 
 // NewCommand returns a new cobra.Command for cluster creation
@@ -50,7 +76,13 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		Short: "Creates a local Kubernetes cluster",
 		Long:  "Creates a local Kubernetes cluster using Docker container 'nodes'",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cli.OverrideDefaultName(cmd.Flags())
+			if flags.NamePrefix != "" {
+				if cmd.Flags().Changed("name") {
+					return errors.New("--name and --name-prefix cannot be used together")
+				}
+			} else {
+				cli.OverrideDefaultName(cmd.Flags())
+			}
 			return runE(logger, streams, flags)
 		},
 	}
@@ -61,6 +93,18 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		"",
 		"cluster name, overrides KIND_CLUSTER_NAME, config (default kind)",
 	)
+	cmd.Flags().StringVar(
+		&flags.NamePrefix,
+		"name-prefix",
+		"",
+		"generate a unique cluster name from this prefix and a random suffix, e.g. for parallel CI jobs",
+	)
+	cmd.Flags().StringVar(
+		&flags.NameFile,
+		"name-file",
+		"",
+		"write the cluster name to this file before creating the cluster, useful with --name-prefix",
+	)
 	cmd.Flags().StringVar(
 		&flags.Config,
 		"config",
@@ -73,17 +117,65 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		"",
 		"node docker image to use for booting the cluster",
 	)
+	cmd.Flags().StringVar(
+		&flags.ControlPlaneImage,
+		"control-plane-image",
+		"",
+		"node image for the control plane nodes, overrides --image",
+	)
+	cmd.Flags().StringVar(
+		&flags.WorkerImage,
+		"worker-image",
+		"",
+		"node image for the worker nodes, overrides --image, e.g. an older Kubernetes version to test version skew",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.InsecureRegistries,
+		"insecure-registry",
+		nil,
+		"host:port of a registry the nodes may use over plain HTTP or without verifying its certificate, may be repeated",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Protect,
+		"protect",
+		false,
+		"protect the cluster from deletion without --force",
+	)
+	cmd.Flags().BoolVar(
+		&flags.AutoRemapPorts,
+		"auto-remap-ports",
+		false,
+		"replace requested host ports that are already in use with free ports",
+	)
 	cmd.Flags().BoolVar(
 		&flags.Retain,
 		"retain",
 		false,
-		"retain nodes for debugging when cluster creation fails",
+		"retain nodes for debugging when cluster creation fails or is interrupted",
+	)
+	cmd.Flags().BoolVar(
+		&flags.RetainOnFailure,
+		"retain-on-failure",
+		false,
+		"retain nodes for debugging when cluster creation fails, but delete them when it is interrupted",
 	)
 	cmd.Flags().DurationVar(
 		&flags.Wait,
 		"wait",
 		time.Duration(0),
-		"wait for control plane node to be ready (default 0s)",
+		"wait for control plane node to be ready, overrides timeouts.nodesReady in the config (default 0s)",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Verify,
+		"verify",
+		false,
+		"run smoke tests once the cluster is created, see `kind verify cluster`",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Detach,
+		"detach",
+		true,
+		"with --detach=false keep running once the cluster is created and delete it when interrupted",
 	)
 	cmd.Flags().StringVar(
 		&flags.Kubeconfig,
@@ -91,38 +183,287 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		"",
 		"sets kubeconfig path instead of $KUBECONFIG or $HOME/.kube/config",
 	)
+	cmd.Flags().StringVar(
+		&flags.KubeconfigMode,
+		"kubeconfig-mode",
+		"",
+		"sets the octal mode of the kubeconfig file, e.g. 0644 (default 0600 for new files)",
+	)
+	cmd.Flags().StringVar(
+		&flags.KubeconfigOwner,
+		"kubeconfig-owner",
+		"",
+		"sets the owner of the kubeconfig file as user[:group], e.g. $SUDO_UID:$SUDO_GID (requires root)",
+	)
+	cmd.Flags().StringVar(
+		&flags.DebugBundle,
+		"debug-bundle",
+		"",
+		"record all commands run, their input, output and timings to this directory, see `kind replay`",
+	)
+	cmd.Flags().BoolVar(
+		&flags.DryRun,
+		"dry-run",
+		false,
+		"only validate the options and print the plan of what would be created as JSON, without creating anything",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"",
+		"with json, print the plan (with --dry-run) or the created cluster as JSON on stdout",
+	)
+	cmd.Flags().IntVar(
+		&flags.StatusFD,
+		"status-fd",
+		0,
+		"write progress events as newline delimited JSON to this open file descriptor, e.g. for editor extensions",
+	)
+	cmd.Flags().StringVar(
+		&flags.StatusFile,
+		"status-file",
+		"",
+		"write progress events as newline delimited JSON to this file, like --status-fd",
+	)
 	return cmd
 }
 
-func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) (err error) {
+	switch flags.Output {
+	case "", "json":
+	default:
+		return errors.Errorf("unknown output format %q, must be json", flags.Output)
+	}
+	if flags.DryRun {
+		flags.Output = "json"
+	} else if flags.Output == "json" && !flags.Detach {
+		return errors.New("--output json cannot be used with --detach=false")
+	}
+
+	stopTracing, err := tracing.Setup(kindversion.Version())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := stopTracing(); err != nil {
+			logger.Warnf("Failed to export traces: %v", err)
+		}
+	}()
+	span := tracing.Start("kind create cluster")
+	defer func() { span.End(err) }()
+
+	// machine readable progress, separate from the logs
+	events, closeEvents, err := statusEventWriter(flags.StatusFD, flags.StatusFile)
+	if err != nil {
+		return err
+	}
+	if events != nil {
+		defer closeEvents()
+		maybeSetStatusEvents(logger, events)
+		defer func() { events.Done(err) }()
+	}
+
+	if flags.DebugBundle != "" {
+		recorder, err := debugbundle.Start(flags.DebugBundle, os.Args, kindversion.Version())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := recorder.Stop(); err != nil {
+				logger.Warnf("Failed to write debug bundle: %v", err)
+			}
+		}()
+	}
+
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
+		runtime.KubeconfigContextTemplate(),
 	)
 
+	// generate a unique name if requested
+	if flags.NamePrefix != "" {
+		existing, err := provider.List()
+		if err != nil {
+			return err
+		}
+		if flags.Name, err = generateName(flags.NamePrefix, existing); err != nil {
+			return err
+		}
+		logger.V(0).Infof("Using generated cluster name %q", flags.Name)
+	}
+	span.SetAttribute("kind.cluster.name", flags.Name)
+
+	// record the name before creating, so it is available to clean up
+	// after a failed or retained create
+	if flags.NameFile != "" {
+		if flags.Name == "" {
+			return errors.New("--name-file requires --name, --name-prefix or KIND_CLUSTER_NAME")
+		}
+		if err := os.WriteFile(flags.NameFile, []byte(flags.Name+"\n"), 0o644); err != nil {
+			return errors.Wrap(err, "failed to write cluster name file")
+		}
+	}
+
 	// handle config flag, we might need to read from stdin
 	withConfig, err := configOption(flags.Config, streams.In)
 	if err != nil {
 		return err
 	}
 
-	// create the cluster
-	if err = provider.Create(
-		flags.Name,
+	// defaults from the user settings file are lower precedence than both
+	// flags and the cluster config
+	settings := usersettings.Get()
+	kubeconfigMode, err := cli.ParseFileMode(flags.KubeconfigMode)
+	if err != nil {
+		return err
+	}
+	if kubeconfigMode == 0 {
+		kubeconfigMode = settings.KubeconfigFileMode()
+	}
+	kubeconfigOwner := flags.KubeconfigOwner
+	if kubeconfigOwner == "" {
+		kubeconfigOwner = settings.KubeconfigOwner
+	}
+	createOptions := []cluster.CreateOption{
 		withConfig,
 		cluster.CreateWithNodeImage(flags.ImageName),
+		cluster.CreateWithControlPlaneImage(flags.ControlPlaneImage),
+		cluster.CreateWithWorkerImage(flags.WorkerImage),
+		cluster.CreateWithInsecureRegistries(flags.InsecureRegistries...),
 		cluster.CreateWithRetain(flags.Retain),
+		cluster.CreateWithRetainOnFailure(flags.RetainOnFailure),
+		cluster.CreateWithProtection(flags.Protect),
+		cluster.CreateWithAutoRemapPorts(flags.AutoRemapPorts),
 		cluster.CreateWithWaitForReady(flags.Wait),
+		cluster.CreateWithDefaultNodeImage(settings.NodeImage),
+		cluster.CreateWithDefaultWaitForReady(settings.WaitDuration()),
+		cluster.CreateWithVerify(flags.Verify),
+		cluster.CreateWithForeground(!flags.Detach),
 		cluster.CreateWithKubeconfigPath(flags.Kubeconfig),
+		cluster.CreateWithKubeconfigMode(kubeconfigMode),
+		cluster.CreateWithKubeconfigOwner(kubeconfigOwner),
 		cluster.CreateWithDisplayUsage(true),
 		cluster.CreateWithDisplaySalutation(true),
-	); err != nil {
+	}
+
+	// the plan also resolves the cluster name, which may come from the config
+	var plan *cluster.ClusterPlan
+	if flags.Output == "json" {
+		if plan, err = provider.Plan(flags.Name, createOptions...); err != nil {
+			return errors.Wrap(err, "failed to plan cluster")
+		}
+		if flags.DryRun {
+			return writeJSON(streams.Out, plan)
+		}
+	}
+
+	// stop and clean up on interrupt, e.g. when a CI job times out, instead
+	// of leaving a partially created cluster behind
+	ctx, cancel := cancelOnSignal(logger, !flags.Detach)
+	defer cancel()
+
+	// create the cluster
+	if err = provider.CreateContext(ctx, flags.Name, createOptions...); err != nil {
 		return errors.Wrap(err, "failed to create cluster")
 	}
 
+	if plan != nil {
+		result, err := provider.Result(plan.Name, flags.Kubeconfig)
+		if err != nil {
+			return errors.Wrap(err, "failed to describe the created cluster")
+		}
+		return writeJSON(streams.Out, result)
+	}
 	return nil
 }
 
+// writeJSON writes v to out as indented JSON
+func writeJSON(out io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode output")
+	}
+	_, err = out.Write(append(b, '\n'))
+	return err
+}
+
+// statusEventWriter returns a writer for the progress events requested with
+// --status-fd or --status-file and a func closing it, or nil if neither is set
+func statusEventWriter(fd int, path string) (*cli.StatusEventWriter, func(), error) {
+	switch {
+	case fd != 0 && path != "":
+		return nil, nil, errors.New("--status-fd and --status-file cannot be used together")
+	case fd != 0:
+		f := os.NewFile(uintptr(fd), "status-fd")
+		if f == nil {
+			return nil, nil, errors.Errorf("invalid --status-fd %d", fd)
+		}
+		if _, err := f.Stat(); err != nil {
+			return nil, nil, errors.Errorf("invalid --status-fd %d: %v", fd, err)
+		}
+		// the standard streams are still needed for the logs
+		closeFile := func() {
+			if fd > 2 {
+				_ = f.Close()
+			}
+		}
+		return cli.NewStatusEventWriter(f), closeFile, nil
+	case path != "":
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to create status file")
+		}
+		return cli.NewStatusEventWriter(f), func() { _ = f.Close() }, nil
+	}
+	return nil, nil, nil
+}
+
+// maybeSetStatusEvents will call logger.SetStatusEvents(events) if logger
+// has a SetStatusEvents method
+func maybeSetStatusEvents(logger log.Logger, events *cli.StatusEventWriter) {
+	type statusEventsSetter interface {
+		SetStatusEvents(*cli.StatusEventWriter)
+	}
+	if v, ok := logger.(statusEventsSetter); ok {
+		v.SetStatusEvents(events)
+	}
+}
+
+// cancelOnSignal returns a context that is cancelled on the first SIGINT or
+// SIGTERM, a second signal then exits immediately without cleaning up
+//
+// In the foreground the cluster is always deleted once cancelled, so SIGHUP
+// (e.g. closing the terminal) also cancels, and further signals are ignored
+// until the deletion is done. Ignored signals are inherited by the commands
+// deleting the nodes, so a Ctrl-C sent to the whole process group does not
+// interrupt them either.
+func cancelOnSignal(logger log.Logger, foreground bool) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	watched := []os.Signal{os.Interrupt, syscall.SIGTERM}
+	if foreground {
+		watched = append(watched, syscall.SIGHUP)
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, watched...)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			if foreground {
+				signal.Ignore(watched...)
+				logger.Warnf("Received %v, deleting the cluster", sig)
+			} else {
+				logger.Warnf("Received %v, cancelling cluster creation and cleaning up. Interrupt again to exit immediately", sig)
+			}
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
 // configOption converts the raw --config flag value to a cluster creation
 // option matching it. it will read from stdin if the flag value is `-`
 func configOption(rawConfigFlag string, stdin io.Reader) (cluster.CreateOption, error) {
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/sets"
+)
+
+// nameSuffixChars are the characters of generated name suffixes, these are
+// valid in cluster names and container names
+const nameSuffixChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// nameSuffixLength is long enough that parallel jobs on a shared host are
+// unlikely to collide, while keeping node container names short
+const nameSuffixLength = 6
+
+// generateName returns prefix followed by a random suffix, avoiding the
+// names of the existing clusters
+func generateName(prefix string, existing []string) (string, error) {
+	taken := sets.NewString(existing...)
+	for i := 0; i < 10; i++ {
+		suffix, err := randomSuffix()
+		if err != nil {
+			return "", err
+		}
+		if name := prefix + "-" + suffix; !taken.Has(name) {
+			return name, nil
+		}
+	}
+	return "", errors.Errorf("failed to generate an unused cluster name with prefix %q", prefix)
+}
+
+func randomSuffix() (string, error) {
+	suffix := make([]byte, nameSuffixLength)
+	max := big.NewInt(int64(len(nameSuffixChars)))
+	for i := range suffix {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to generate cluster name")
+		}
+		suffix[i] = nameSuffixChars[n.Int64()]
+	}
+	return string(suffix), nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGenerateName(t *testing.T) {
+	t.Parallel()
+	nameRE := regexp.MustCompile(`^ci-[a-z0-9]{6}$`)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		name, err := generateName("ci", []string{"ci-aaaaaa"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !nameRE.MatchString(name) {
+			t.Fatalf("generated name %q does not match %s", name, nameRE)
+		}
+		if name == "ci-aaaaaa" {
+			t.Fatalf("generated name %q collides with an existing cluster", name)
+		}
+		seen[name] = true
+	}
+	if len(seen) < 90 {
+		t.Errorf("expected generated names to be unique, got %d distinct names of 100", len(seen))
+	}
+}
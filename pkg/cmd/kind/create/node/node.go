@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package node implements the `node` command
+package node
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name   string
+	Image  string
+	Labels map[string]string
+	Retain bool
+}
+
+// NewCommand returns a new cobra.Command for adding a node
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.MaximumNArgs(1),
+		Use:   "node [node-name]",
+		Short: "Adds a worker node to a cluster",
+		Long: "Adds a worker node to an existing cluster and joins it to Kubernetes.\n\n" +
+			"The node is configured like the first worker the cluster was created with, " +
+			"or like its control plane if it has no workers, without any extraPortMappings. " +
+			"If no name is given the next free <cluster>-worker<n> name is used.",
+		Example: "  kind create node --label tier=frontend",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			nodeName := ""
+			if len(args) > 0 {
+				nodeName = args[0]
+			}
+			return runE(logger, flags, nodeName)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster name",
+	)
+	cmd.Flags().StringVar(
+		&flags.Image,
+		"image",
+		"",
+		"node docker image to use for the new node, instead of the image of the node it is based on",
+	)
+	cmd.Flags().StringToStringVar(
+		&flags.Labels,
+		"label",
+		nil,
+		"labels to add to the new node, e.g. --label tier=frontend",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Retain,
+		"retain",
+		false,
+		"retain the node for debugging if adding it fails",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, nodeName string) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	name, err := provider.AddNode(
+		ctx,
+		flags.Name,
+		cluster.AddNodeWithName(nodeName),
+		cluster.AddNodeWithImage(flags.Image),
+		cluster.AddNodeWithLabels(flags.Labels),
+		cluster.AddNodeWithRetain(flags.Retain),
+	)
+	if err != nil {
+		return err
+	}
+	logger.V(0).Infof("Added node: %q", name)
+	return nil
+}
@@ -18,20 +18,28 @@ limitations under the License.
 package cluster
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cmd"
+	kindversion "sigs.k8s.io/kind/pkg/cmd/kind/version"
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/debugbundle"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
+	"sigs.k8s.io/kind/pkg/internal/tracing"
 )
 
 type flagpole struct {
-	Name       string
-	Kubeconfig string
+	Name               string
+	Kubeconfig         string
+	Force              bool
+	ExportLogsOnDelete string
+	DebugBundle        string
 }
 
 // NewCommand returns a new cobra.Command for cluster deletion
@@ -55,23 +63,75 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		cluster.DefaultName,
 		"the cluster name",
 	)
+	cmd.Flags().BoolVar(
+		&flags.Force,
+		"force",
+		false,
+		"delete protected clusters",
+	)
+	cmd.Flags().StringVar(
+		&flags.ExportLogsOnDelete,
+		"export-logs-on-delete",
+		"",
+		"export the cluster logs to this directory, or a tempdir if no directory is given, before deleting",
+	)
+	cmd.Flags().Lookup("export-logs-on-delete").NoOptDefVal = cli.ExportLogsTempDir
 	cmd.Flags().StringVar(
 		&flags.Kubeconfig,
 		"kubeconfig",
 		"",
 		"sets kubeconfig path instead of $KUBECONFIG or $HOME/.kube/config",
 	)
+	cmd.Flags().StringVar(
+		&flags.DebugBundle,
+		"debug-bundle",
+		"",
+		"record all commands run, their input, output and timings to this directory, see `kind replay`",
+	)
 	return cmd
 }
 
-func deleteCluster(logger log.Logger, flags *flagpole) error {
+func deleteCluster(logger log.Logger, flags *flagpole) (err error) {
+	stopTracing, err := tracing.Setup(kindversion.Version())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := stopTracing(); err != nil {
+			logger.Warnf("Failed to export traces: %v", err)
+		}
+	}()
+	span := tracing.Start("kind delete cluster", tracing.String("kind.cluster.name", flags.Name))
+	defer func() { span.End(err) }()
+
+	if flags.DebugBundle != "" {
+		recorder, err := debugbundle.Start(flags.DebugBundle, os.Args, kindversion.Version())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := recorder.Stop(); err != nil {
+				logger.Warnf("Failed to write debug bundle: %v", err)
+			}
+		}()
+	}
+
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
+		runtime.KubeconfigContextTemplate(),
 	)
+	// resolve where to export logs to, if anywhere
+	logsDir, err := cli.ExportLogsDir(flags.ExportLogsOnDelete)
+	if err != nil {
+		return err
+	}
 	// Delete individual cluster
 	logger.V(0).Infof("Deleting cluster %q ...", flags.Name)
-	if err := provider.Delete(flags.Name, flags.Kubeconfig); err != nil {
+	if err := provider.Delete(flags.Name, flags.Kubeconfig,
+		cluster.DeleteWithForce(flags.Force),
+		cluster.DeleteWithExportLogs(logsDir),
+	); err != nil {
 		return errors.Wrapf(err, "failed to delete cluster %q", flags.Name)
 	}
 	return nil
@@ -18,6 +18,8 @@ limitations under the License.
 package clusters
 
 import (
+	"path/filepath"
+
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
@@ -25,12 +27,15 @@ import (
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
+	"sigs.k8s.io/kind/pkg/internal/cli"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
 type flagpole struct {
-	Kubeconfig string
-	All        bool
+	Kubeconfig         string
+	Force              bool
+	ExportLogsOnDelete string
+	All                bool
 }
 
 // NewCommand returns a new cobra.Command for cluster deletion
@@ -50,6 +55,19 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 			return deleteClusters(logger, flags, args)
 		},
 	}
+	cmd.Flags().BoolVar(
+		&flags.Force,
+		"force",
+		false,
+		"delete protected clusters",
+	)
+	cmd.Flags().StringVar(
+		&flags.ExportLogsOnDelete,
+		"export-logs-on-delete",
+		"",
+		"export each cluster's logs to a subdirectory of this directory, or of a tempdir if no directory is given, before deleting",
+	)
+	cmd.Flags().Lookup("export-logs-on-delete").NoOptDefVal = cli.ExportLogsTempDir
 	cmd.Flags().StringVar(
 		&flags.Kubeconfig,
 		"kubeconfig",
@@ -70,6 +88,7 @@ func deleteClusters(logger log.Logger, flags *flagpole, clusters []string) error
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
+		runtime.KubeconfigContextTemplate(),
 	)
 	var err error
 	if flags.All {
@@ -78,13 +97,23 @@ func deleteClusters(logger log.Logger, flags *flagpole, clusters []string) error
 			return errors.Wrap(err, "failed listing clusters for delete")
 		}
 	}
+	// resolve where to export logs to, if anywhere
+	logsDir, err := cli.ExportLogsDir(flags.ExportLogsOnDelete)
+	if err != nil {
+		return err
+	}
+	withForce := cluster.DeleteWithForce(flags.Force)
 	var success []string
-	for _, cluster := range clusters {
-		if err = provider.Delete(cluster, flags.Kubeconfig); err != nil {
-			logger.V(0).Infof("%s\n", errors.Wrapf(err, "failed to delete cluster %q", cluster))
+	for _, name := range clusters {
+		withExportLogs := cluster.DeleteWithExportLogs("")
+		if logsDir != "" {
+			withExportLogs = cluster.DeleteWithExportLogs(filepath.Join(logsDir, name))
+		}
+		if err = provider.Delete(name, flags.Kubeconfig, withForce, withExportLogs); err != nil {
+			logger.V(0).Infof("%s\n", errors.Wrapf(err, "failed to delete cluster %q", name))
 			continue
 		}
-		success = append(success, cluster)
+		success = append(success, name)
 	}
 	logger.V(0).Infof("Deleted clusters: %q", success)
 	return nil
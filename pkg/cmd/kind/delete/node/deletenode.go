@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package node implements the `node` command
+package node
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name         string
+	Drain        bool
+	GracePeriod  int
+	DrainTimeout time.Duration
+}
+
+// NewCommand returns a new cobra.Command for deleting a node
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Use:   "node <node-name>",
+		Short: "Deletes a worker node from a cluster",
+		Long: "Deletes a worker node from a cluster, removing it from Kubernetes and deleting its container.\n\n" +
+			"With --drain the node is cordoned and its pods evicted first, respecting PodDisruptionBudgets, " +
+			"so workloads are rescheduled on the remaining nodes instead of being killed.",
+		Example: "  kind delete node kind-worker2 --drain --drain-timeout=5m",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, flags, args[0])
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster name",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Drain,
+		"drain",
+		false,
+		"cordon and drain the node before deleting it",
+	)
+	cmd.Flags().IntVar(
+		&flags.GracePeriod,
+		"grace-period",
+		-1,
+		"seconds given to each pod to terminate when draining, if negative the pod's own grace period is used",
+	)
+	cmd.Flags().DurationVar(
+		&flags.DrainTimeout,
+		"drain-timeout",
+		0,
+		"how long to wait for the drain before giving up without deleting the node, zero waits forever",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, nodeName string) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	return provider.DeleteNode(
+		flags.Name,
+		nodeName,
+		cluster.DeleteNodeWithDrain(flags.Drain),
+		cluster.DeleteNodeWithGracePeriod(time.Duration(flags.GracePeriod)*time.Second),
+		cluster.DeleteNodeWithDrainTimeout(flags.DrainTimeout),
+	)
+}
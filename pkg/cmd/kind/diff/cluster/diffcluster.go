@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster implements the `diff cluster` command
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Config    string
+	ImageName string
+	Output    string
+}
+
+// NewCommand returns a new cobra.Command for comparing a cluster with a config
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.MaximumNArgs(1),
+		Use:   "cluster [cluster-name] --config <file>",
+		Short: "Compares the config a cluster was created with to a new config",
+		Long: "Compares the config a cluster was created with to a new config, without changing anything, " +
+			"and prints which changes can be applied to the existing cluster and which require recreating it.\n\n" +
+			"Added TCP extraPortMappings can be forwarded with kind port-forward, and added or removed " +
+			"workers can be applied with kind create node and kind delete node. " +
+			"Timeouts and readyConditions are only used while creating a cluster. " +
+			"Any other change requires recreating the cluster.\n\n" +
+			"The cluster defaults to $KIND_CLUSTER_NAME or \"kind\". " +
+			"It must have been created by a kind version that records its config.",
+		Example: "  kind diff cluster --config new.yaml\n" +
+			"  kind diff cluster dev --config new.yaml -o json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, flags, args)
+		},
+	}
+	cmd.Flags().StringVar(
+		&flags.Config,
+		"config",
+		"",
+		"path to the new kind config file",
+	)
+	cmd.Flags().StringVar(
+		&flags.ImageName,
+		"image",
+		"",
+		"node docker image the new config would be created with",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"table",
+		"output format, one of: table, json, yaml",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole, args []string) error {
+	switch flags.Output {
+	case "table", "json", "yaml":
+	default:
+		return errors.Errorf("unknown output format %q, must be one of: table, json, yaml", flags.Output)
+	}
+
+	name := cluster.DefaultName
+	if env := os.Getenv("KIND_CLUSTER_NAME"); env != "" {
+		name = env
+	}
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	changes, err := provider.Diff(
+		name,
+		cluster.CreateWithConfigFile(flags.Config),
+		cluster.CreateWithNodeImage(flags.ImageName),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to compare cluster %q", name)
+	}
+
+	var out []byte
+	switch flags.Output {
+	case "table":
+		return printChanges(streams.Out, changes)
+	case "json":
+		out, err = json.MarshalIndent(changes, "", "  ")
+		out = append(out, '\n')
+	case "yaml":
+		out, err = yaml.Marshal(changes)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to encode changes")
+	}
+	_, err = streams.Out.Write(out)
+	return err
+}
+
+// printChanges prints changes as a table
+func printChanges(out io.Writer, changes []cluster.ConfigChange) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(out, "No changes")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tOLD\tNEW\tAPPLY")
+	for _, c := range changes {
+		apply := c.Apply
+		switch {
+		case !c.InPlace:
+			apply = "recreate the cluster"
+		case apply == "":
+			apply = "nothing to apply"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Field, orNone(c.Old), orNone(c.New), apply)
+	}
+	return w.Flush()
+}
+
+// orNone returns value, or "<none>" if it is empty
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
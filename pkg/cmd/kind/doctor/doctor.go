@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor implements the `doctor` command
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	FixFirewall bool
+}
+
+// NewCommand returns a new cobra.Command for diagnosing the kind setup
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "doctor",
+		Short: "Checks which container runtime kind will use and that it works",
+		Long: "Checks which container runtime kind will use and why, " +
+			"that the runtime is reachable, and that the host firewall " +
+			"does not block traffic on the kind network",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().BoolVar(
+		&flags.FixFirewall,
+		"fix-firewall",
+		false,
+		"add the host firewall rules that allow traffic on the kind network, requires root",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	if err := report(
+		streams.Out, runtime.Selected(), os.Getenv("KIND_EXPERIMENTAL_PROVIDER"),
+		os.Getenv("CONTAINER_HOST"), cluster.AvailableNodeProviders(),
+		provider.Name(), provider.Check(),
+	); err != nil {
+		return err
+	}
+
+	// the runtimes on other platforms run in a VM with its own firewall
+	if goruntime.GOOS != "linux" {
+		return nil
+	}
+	iface, err := networkInterface(provider.Name())
+	if err != nil {
+		fmt.Fprintf(streams.Out, "Firewall check: skipped, %v\n", err)
+		if flags.FixFirewall {
+			return errors.Wrap(err, "cannot fix the firewall")
+		}
+		return nil
+	}
+	findings, skipped := checkFirewall(iface)
+	reportFirewall(streams.Out, iface, findings, skipped)
+	if flags.FixFirewall {
+		return fixFirewall(streams.Out, findings)
+	}
+	return nil
+}
+
+// report writes how the runtime was selected and whether it works to w,
+// returning an error if it does not
+func report(w io.Writer, flag, env, containerHost string, available []string, selected string, checkErr error) error {
+	fmt.Fprintln(w, "Runtime selection:")
+	fmt.Fprintf(w, "  --runtime: %s\n", orUnset(flag))
+	fmt.Fprintf(w, "  KIND_EXPERIMENTAL_PROVIDER: %s\n", orUnset(env))
+	fmt.Fprintf(w, "  CONTAINER_HOST: %s\n", orUnset(containerHost))
+	if len(available) == 0 {
+		fmt.Fprintln(w, "  available runtimes: none")
+	} else {
+		fmt.Fprintf(w, "  available runtimes: %s\n", strings.Join(available, ", "))
+	}
+	reason := "auto-detected"
+	switch {
+	case flag != "":
+		reason = "from --runtime"
+	case env != "":
+		reason = "from KIND_EXPERIMENTAL_PROVIDER"
+	case len(available) == 0:
+		reason = "default, no runtime was detected"
+	case containerHost != "" && selected == "podman":
+		reason = "auto-detected, podman is preferred when CONTAINER_HOST is set"
+	}
+	fmt.Fprintf(w, "  selected: %s (%s)\n", selected, reason)
+	if checkErr != nil {
+		fmt.Fprintf(w, "Runtime check: %s is not working\n", selected)
+		return errors.Wrapf(checkErr, "%s is not working", selected)
+	}
+	fmt.Fprintf(w, "Runtime check: %s is working\n", selected)
+	return nil
+}
+
+func orUnset(value string) string {
+	if value == "" {
+		return "(unset)"
+	}
+	return value
+}
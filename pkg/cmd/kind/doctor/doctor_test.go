@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	err := report(&out, "", "", "unix:///run/user/1000/podman/podman.sock",
+		[]string{"docker", "podman"}, "podman", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"--runtime: (unset)",
+		"available runtimes: docker, podman",
+		"selected: podman (auto-detected, podman is preferred when CONTAINER_HOST is set)",
+		"podman is working",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected output to contain %q but got:\n%s", expected, out.String())
+		}
+	}
+
+	out.Reset()
+	err = report(&out, "docker", "", "", nil, "docker", errors.New("cannot connect"))
+	if err == nil || !strings.Contains(err.Error(), "cannot connect") {
+		t.Errorf("expected the check error but got: %v", err)
+	}
+	if !strings.Contains(out.String(), "selected: docker (from --runtime)") {
+		t.Errorf("expected the flag to be reported but got:\n%s", out.String())
+	}
+}
+
+func TestDockerBridgeName(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Inspect  string
+		Expected string
+	}{
+		{
+			Name:     "generated name",
+			Inspect:  " 0123456789abcdef0123456789abcdef",
+			Expected: "br-0123456789ab",
+		},
+		{
+			Name:     "bridge name option",
+			Inspect:  "kind0 0123456789abcdef0123456789abcdef",
+			Expected: "kind0",
+		},
+		{
+			Name:     "empty",
+			Inspect:  "",
+			Expected: "",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			if actual := dockerBridgeName(tc.Inspect); actual != tc.Expected {
+				t.Errorf("expected %q but got %q", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestFirewallFindings(t *testing.T) {
+	t.Parallel()
+	const iface = "br-0123456789ab"
+	cases := []struct {
+		Name     string
+		Findings []firewallFinding
+		Expected []string
+	}{
+		{
+			Name:     "firewalld not running",
+			Findings: firewalldFindings("not running", "", iface),
+		},
+		{
+			Name:     "firewalld docker zone",
+			Findings: firewalldFindings("running", "docker", iface),
+		},
+		{
+			Name:     "firewalld no zone",
+			Findings: firewalldFindings("running", "no zone", iface),
+			Expected: []string{"firewall-cmd --permanent --zone=trusted --add-interface=br-0123456789ab", "firewall-cmd --reload"},
+		},
+		{
+			Name:     "firewalld public zone",
+			Findings: firewalldFindings("running", "public", iface),
+			Expected: []string{"firewall-cmd --permanent --zone=trusted --add-interface=br-0123456789ab", "firewall-cmd --reload"},
+		},
+		{
+			Name: "ufw rules for other interfaces",
+			Findings: ufwFindings(`*filter
+:ufw-user-input - [0:0]
+### tuple ### allow tcp 22 0.0.0.0/0 any 0.0.0.0/0 in
+-A ufw-user-input -p tcp --dport 22 -j ACCEPT
+### tuple ### deny any any 0.0.0.0/0 any 0.0.0.0/0 in_eth0
+-A ufw-user-input -i eth0 -j DROP
+### tuple ### allow any any 0.0.0.0/0 any 0.0.0.0/0 in_br-0123456789ab
+-A ufw-user-input -i br-0123456789ab -j ACCEPT
+COMMIT
+`, iface),
+		},
+		{
+			Name: "ufw rules denying the kind bridge",
+			Findings: ufwFindings(`### tuple ### deny any any 0.0.0.0/0 any 0.0.0.0/0 in_br-0123456789ab
+-A ufw-user-input -i br-0123456789ab -j DROP
+### tuple ### route:reject any any 0.0.0.0/0 any 0.0.0.0/0 in_br-0123456789ab
+-A ufw-user-forward -i br-0123456789ab -j REJECT
+`+"\n"+`### tuple ### deny any any ::/0 any ::/0 in_br-0123456789ab
+-A ufw6-user-input -i br-0123456789ab -j DROP
+### tuple ### route:deny any any 0.0.0.0/0 any 0.0.0.0/0 out_br-0123456789ab
+-A ufw-user-forward -o br-0123456789ab -j DROP
+`, iface),
+			Expected: []string{
+				"ufw insert 1 allow in on br-0123456789ab",
+				"ufw route insert 1 allow in on br-0123456789ab",
+				"ufw route insert 1 allow out on br-0123456789ab",
+			},
+		},
+		{
+			Name: "nftables managed tables",
+			Findings: nftablesFindings(`table ip filter {
+	chain FORWARD {
+		type filter hook forward priority filter; policy drop;
+	}
+}
+table inet firewalld {
+	chain filter_FORWARD {
+		type filter hook forward priority filter + 10; policy accept;
+	}
+}
+`, iface),
+		},
+		{
+			Name: "nftables forward drop",
+			Findings: nftablesFindings(`table inet filter {
+	chain input {
+		type filter hook input priority filter; policy drop;
+	}
+	chain forward {
+		type filter hook forward priority filter; policy drop;
+	}
+}
+`, iface),
+			Expected: []string{"nft insert rule inet filter forward iifname br-0123456789ab accept", "nft insert rule inet filter forward oifname br-0123456789ab ct state established,related accept"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			var actual []string
+			for _, f := range tc.Findings {
+				for _, c := range f.Fix {
+					actual = append(actual, strings.Join(c, " "))
+				}
+			}
+			if strings.Join(actual, "\n") != strings.Join(tc.Expected, "\n") {
+				t.Errorf("expected fixes %q but got %q", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestReportFirewall(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	reportFirewall(&out, "br-0123456789ab", firewalldFindings("running", "", "br-0123456789ab"),
+		[]string{"nftables: listing the ruleset requires root"})
+	for _, expected := range []string{
+		"found rules that block br-0123456789ab",
+		"firewalld: interface br-0123456789ab is in the default zone",
+		"sudo firewall-cmd --permanent --zone=trusted --add-interface=br-0123456789ab",
+		"skipped nftables: listing the ruleset requires root",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected output to contain %q but got:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// firewallFinding is a host firewall configuration that breaks traffic
+// between the kind nodes, or between the nodes and the host
+type firewallFinding struct {
+	Firewall string
+	Problem  string
+	// Fix holds the commands that allow the traffic, in order
+	Fix [][]string
+}
+
+// networkInterface returns the host bridge interface of the network the
+// kind nodes are attached to for the given runtime
+func networkInterface(runtime string) (string, error) {
+	switch runtime {
+	case "docker":
+		network := networkName("KIND_EXPERIMENTAL_DOCKER_NETWORK")
+		lines, err := exec.OutputLines(exec.Command(
+			"docker", "network", "inspect", network,
+			"--format", `{{index .Options "com.docker.network.bridge.name"}} {{.Id}}`,
+		))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to inspect docker network %q", network)
+		}
+		if len(lines) != 1 {
+			return "", errors.Errorf("expected one line of output inspecting docker network %q, got %d", network, len(lines))
+		}
+		return dockerBridgeName(lines[0]), nil
+	case "podman":
+		network := networkName("KIND_EXPERIMENTAL_PODMAN_NETWORK")
+		lines, err := exec.OutputLines(exec.Command(
+			"podman", "network", "inspect", network,
+			"--format", "{{.NetworkInterface}}",
+		))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to inspect podman network %q", network)
+		}
+		if len(lines) != 1 || lines[0] == "" {
+			return "", errors.Errorf("podman network %q has no network interface", network)
+		}
+		return lines[0], nil
+	}
+	return "", errors.Errorf("checking the host firewall is not supported for %s", runtime)
+}
+
+func networkName(env string) string {
+	if n := os.Getenv(env); n != "" {
+		return n
+	}
+	return "kind"
+}
+
+// dockerBridgeName returns the bridge interface name from the
+// "<bridge name option> <network ID>" inspect output, docker names the
+// bridge br-<short network ID> unless the option is set
+func dockerBridgeName(inspect string) string {
+	parts := strings.Fields(inspect)
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		id := parts[0]
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		return "br-" + id
+	}
+	return parts[0]
+}
+
+// checkFirewall inspects the firewalls found on the host for rules that drop
+// traffic on iface, returning what it found and which checks were skipped
+func checkFirewall(iface string) (findings []firewallFinding, skipped []string) {
+	// firewalld
+	if lines, err := exec.OutputLines(exec.Command("firewall-cmd", "--state")); err == nil && len(lines) > 0 {
+		zone := ""
+		if lines, err := exec.OutputLines(exec.Command("firewall-cmd", "--get-zone-of-interface="+iface)); err == nil && len(lines) > 0 {
+			zone = lines[0]
+		}
+		findings = append(findings, firewalldFindings(lines[0], zone, iface)...)
+	}
+
+	// ufw keeps whether it is enabled in a world readable file, unlike
+	// `ufw status`, but its rules are only readable by root
+	if conf, err := os.ReadFile("/etc/ufw/ufw.conf"); err == nil && strings.EqualFold(shellVar(string(conf), "ENABLED"), "yes") {
+		if os.Geteuid() != 0 {
+			skipped = append(skipped, "ufw: reading its rules requires root")
+		} else {
+			rules := []string{}
+			for _, path := range []string{"/etc/ufw/user.rules", "/etc/ufw/user6.rules"} {
+				if contents, err := os.ReadFile(path); err == nil {
+					rules = append(rules, string(contents))
+				}
+			}
+			findings = append(findings, ufwFindings(strings.Join(rules, "\n"), iface)...)
+		}
+	}
+
+	// nftables rules that are not managed by firewalld or iptables
+	if _, err := osexec.LookPath("nft"); err == nil {
+		if os.Geteuid() != 0 {
+			skipped = append(skipped, "nftables: listing the ruleset requires root")
+		} else if out, err := exec.Output(exec.Command("nft", "list", "chains")); err == nil {
+			findings = append(findings, nftablesFindings(string(out), iface)...)
+		} else {
+			skipped = append(skipped, fmt.Sprintf("nftables: failed to list chains: %v", err))
+		}
+	}
+	return findings, skipped
+}
+
+// firewalldFindings checks that firewalld, if running, does not filter the
+// kind bridge with its default zone, which rejects forwarded traffic
+// (e.g. Fedora with docker < 20.10 or podman)
+func firewalldFindings(state, zone, iface string) []firewallFinding {
+	if strings.TrimSpace(state) != "running" {
+		return nil
+	}
+	switch zone = strings.TrimSpace(zone); zone {
+	case "docker", "trusted":
+		return nil
+	case "", "no zone":
+		zone = "the default zone"
+	default:
+		zone = fmt.Sprintf("zone %q", zone)
+	}
+	return []firewallFinding{{
+		Firewall: "firewalld",
+		Problem:  fmt.Sprintf("interface %s is in %s, which rejects traffic between the nodes and the host", iface, zone),
+		Fix: [][]string{
+			{"firewall-cmd", "--permanent", "--zone=trusted", "--add-interface=" + iface},
+			{"firewall-cmd", "--reload"},
+		},
+	}}
+}
+
+// ufwFindings checks the user rules of an enabled ufw, the contents of
+// /etc/ufw/user.rules and /etc/ufw/user6.rules, for rules that deny or
+// reject traffic on the kind bridge.
+//
+// ufw's stock default policies, which drop incoming and routed traffic, are
+// not reported: the runtimes add their own forwarding rules ahead of ufw's,
+// and the nodes do not need to reach the host.
+func ufwFindings(rules, iface string) []firewallFinding {
+	var findings []firewallFinding
+	seen := map[string]bool{}
+	for _, line := range strings.Split(rules, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "-A" {
+			continue
+		}
+		route := false
+		switch fields[1] {
+		case "ufw-user-input", "ufw6-user-input":
+		case "ufw-user-forward", "ufw6-user-forward":
+			route = true
+		default:
+			continue
+		}
+		direction, target := "", ""
+		for i := 2; i+1 < len(fields); i++ {
+			switch {
+			case fields[i] == "-i" && fields[i+1] == iface:
+				direction = "in"
+			case fields[i] == "-o" && fields[i+1] == iface:
+				direction = "out"
+			case fields[i] == "-j":
+				target = fields[i+1]
+			}
+		}
+		if direction == "" || !route && direction != "in" || target != "DROP" && target != "REJECT" {
+			continue
+		}
+		key := fmt.Sprintf("%t %s", route, direction)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		// inserted rules are checked before the rules blocking the traffic
+		if route {
+			findings = append(findings, firewallFinding{
+				Firewall: "ufw",
+				Problem:  fmt.Sprintf("a route rule denies traffic routed %s on %s, to or from the nodes", direction, iface),
+				Fix:      [][]string{{"ufw", "route", "insert", "1", "allow", direction, "on", iface}},
+			})
+		} else {
+			findings = append(findings, firewallFinding{
+				Firewall: "ufw",
+				Problem:  fmt.Sprintf("a rule denies traffic from the nodes on %s to the host", iface),
+				Fix:      [][]string{{"ufw", "insert", "1", "allow", "in", "on", iface}},
+			})
+		}
+	}
+	return findings
+}
+
+// shellVar returns the unquoted value of name in a shell style KEY=value file
+func shellVar(contents, name string) string {
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, name+"=") {
+			continue
+		}
+		return strings.Trim(strings.TrimPrefix(line, name+"="), `"'`)
+	}
+	return ""
+}
+
+// nftablesFindings checks the output of `nft list chains` for forward chains
+// that drop by default, skipping the tables managed by firewalld and by
+// iptables, which the runtimes already add their own rules to
+func nftablesFindings(chains, iface string) []firewallFinding {
+	var findings []firewallFinding
+	family, table, chain := "", "", ""
+	for _, line := range strings.Split(chains, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && fields[0] == "table":
+			family, table = fields[1], fields[2]
+		case len(fields) >= 2 && fields[0] == "chain":
+			chain = fields[1]
+		case strings.Contains(line, "hook forward") && strings.Contains(line, "policy drop"):
+			if table == "firewalld" || table == "filter" && (family == "ip" || family == "ip6") {
+				continue
+			}
+			findings = append(findings, firewallFinding{
+				Firewall: "nftables",
+				Problem:  fmt.Sprintf("chain %s %s %s drops forwarded traffic from the nodes on %s", family, table, chain, iface),
+				// only replies are accepted towards the nodes, so the
+				// rules do not open the nodes up to other networks
+				Fix: [][]string{
+					{"nft", "insert", "rule", family, table, chain, "iifname", iface, "accept"},
+					{"nft", "insert", "rule", family, table, chain, "oifname", iface, "ct", "state", "established,related", "accept"},
+				},
+			})
+		}
+	}
+	return findings
+}
+
+// reportFirewall writes the firewall findings and the commands that fix them to w
+func reportFirewall(w io.Writer, iface string, findings []firewallFinding, skipped []string) {
+	if len(findings) == 0 {
+		fmt.Fprintf(w, "Firewall check: no rules found that block %s\n", iface)
+	} else {
+		fmt.Fprintf(w, "Firewall check: found rules that block %s\n", iface)
+	}
+	for _, f := range findings {
+		fmt.Fprintf(w, "  %s: %s\n", f.Firewall, f.Problem)
+		fmt.Fprintln(w, "    to allow the traffic, run:")
+		for _, c := range f.Fix {
+			fmt.Fprintf(w, "      sudo %s\n", exec.PrettyCommand(c[0], c[1:]...))
+		}
+	}
+	for _, s := range skipped {
+		fmt.Fprintf(w, "  skipped %s\n", s)
+	}
+}
+
+// fixFirewall runs the commands that fix the findings, which requires root
+func fixFirewall(w io.Writer, findings []firewallFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	if os.Geteuid() != 0 {
+		return errors.New("--fix-firewall must be run as root")
+	}
+	for _, f := range findings {
+		for _, c := range f.Fix {
+			fmt.Fprintf(w, "Running: %s\n", exec.PrettyCommand(c[0], c[1:]...))
+			if err := exec.Command(c[0], c[1:]...).Run(); err != nil {
+				return errors.Wrapf(err, "failed to fix %s", f.Firewall)
+			}
+		}
+	}
+	return nil
+}
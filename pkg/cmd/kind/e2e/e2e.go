@@ -0,0 +1,381 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package e2e implements the `e2e` command
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/build/nodeimage"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+// defaultConfig is the cluster used when --config is not set, matching the
+// clusters Kubernetes CI tests with kind
+const defaultConfig = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+networking:
+  # don't pass through host search paths
+  dnsSearch: []
+nodes:
+- role: control-plane
+- role: worker
+- role: worker
+`
+
+// defaultFocus runs the conformance tests if no tests are selected
+const defaultFocus = `\[Conformance\]`
+
+type flagpole struct {
+	Name        string
+	Config      string
+	Image       string
+	Build       bool
+	KubeRoot    string
+	E2ETest     string
+	Ginkgo      string
+	Parallel    int
+	Focus       string
+	Skip        string
+	LabelFilter string
+	Artifacts   string
+	Wait        time.Duration
+	Retain      bool
+}
+
+// NewCommand returns a new cobra.Command for running Kubernetes e2e tests
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "e2e",
+		Short: "Runs the Kubernetes e2e tests against a new cluster",
+		Long: "Creates a cluster, runs the upstream Kubernetes e2e.test binary against it, " +
+			"exports the cluster logs and then deletes the cluster.\n\n" +
+			"With --build the node image is first built from the Kubernetes sources in --kube-root, " +
+			"where e2e.test and ginkgo are also found after `make all WHAT=\"test/e2e/e2e.test vendor/github.com/onsi/ginkgo/v2/ginkgo\"`. " +
+			"The conformance tests are run unless --focus or --label-filter is set.",
+		Example: "  kind e2e --build --kube-root=$HOME/kubernetes --focus='\\[sig-network\\]' --parallel=8",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		"kind-e2e",
+		"the name of the test cluster",
+	)
+	cmd.Flags().StringVar(
+		&flags.Config,
+		"config",
+		"",
+		"path to a kind config file for the test cluster, defaults to one control plane and two workers",
+	)
+	cmd.Flags().StringVar(
+		&flags.Image,
+		"image",
+		"",
+		"node image to test, with --build the name to build it as",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Build,
+		"build",
+		false,
+		"build the node image from --kube-root first",
+	)
+	cmd.Flags().StringVar(
+		&flags.KubeRoot,
+		"kube-root",
+		"",
+		"path to the Kubernetes sources, defaults to the current directory with --build",
+	)
+	cmd.Flags().StringVar(
+		&flags.E2ETest,
+		"e2e-test",
+		"",
+		"path to the e2e.test binary, defaults to _output/bin/e2e.test in --kube-root",
+	)
+	cmd.Flags().StringVar(
+		&flags.Ginkgo,
+		"ginkgo",
+		"",
+		"path to the ginkgo binary used with --parallel, defaults to _output/bin/ginkgo in --kube-root",
+	)
+	cmd.Flags().IntVar(
+		&flags.Parallel,
+		"parallel",
+		1,
+		"number of parallel ginkgo processes, skip [Serial] tests when greater than one",
+	)
+	cmd.Flags().StringVar(
+		&flags.Focus,
+		"focus",
+		"",
+		"only run tests matching this regular expression",
+	)
+	cmd.Flags().StringVar(
+		&flags.Skip,
+		"skip",
+		"",
+		"skip tests matching this regular expression",
+	)
+	cmd.Flags().StringVar(
+		&flags.LabelFilter,
+		"label-filter",
+		"",
+		"only run tests matching this ginkgo label filter",
+	)
+	cmd.Flags().StringVar(
+		&flags.Artifacts,
+		"artifacts",
+		"_artifacts",
+		"directory for the test reports, kubeconfig and cluster logs",
+	)
+	cmd.Flags().DurationVar(
+		&flags.Wait,
+		"wait",
+		time.Minute,
+		"wait for the control plane to be ready before testing",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Retain,
+		"retain",
+		false,
+		"keep the cluster after the tests, instead of deleting it",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	if flags.Parallel < 1 {
+		return errors.New("--parallel must be at least 1")
+	}
+	if flags.Build && flags.KubeRoot == "" {
+		flags.KubeRoot = "."
+	}
+	e2eTest := defaultBinary(flags.E2ETest, flags.KubeRoot, "e2e.test")
+	if e2eTest == "" {
+		return errors.New("--e2e-test or --kube-root is required to find the e2e.test binary")
+	}
+	ginkgo := ""
+	if flags.Parallel > 1 {
+		if ginkgo = defaultBinary(flags.Ginkgo, flags.KubeRoot, "ginkgo"); ginkgo == "" {
+			return errors.New("--ginkgo or --kube-root is required to run tests in parallel")
+		}
+	}
+	artifacts, err := filepath.Abs(flags.Artifacts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(artifacts, 0755); err != nil {
+		return errors.Wrap(err, "failed to create artifacts directory")
+	}
+
+	// stop the tests on interrupt, the cluster is still cleaned up
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if flags.Build {
+		image := flags.Image
+		if image == "" {
+			image = nodeimage.DefaultImage
+		}
+		if err := nodeimage.BuildContext(
+			ctx,
+			nodeimage.WithImage(image),
+			nodeimage.WithKubeParam(flags.KubeRoot),
+			nodeimage.WithLogger(logger),
+		); err != nil {
+			return errors.Wrap(err, "error building node image")
+		}
+		flags.Image = image
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+		runtime.KubeconfigContextTemplate(),
+	)
+	// the test cluster's kubeconfig is kept apart from the user's
+	kubeconfig := filepath.Join(artifacts, "kubeconfig")
+	createOpts := []cluster.CreateOption{
+		cluster.CreateWithKubeconfigPath(kubeconfig),
+		cluster.CreateWithNodeImage(flags.Image),
+		cluster.CreateWithRetain(true),
+		cluster.CreateWithWaitForReady(flags.Wait),
+		cluster.CreateWithDisplayUsage(false),
+		cluster.CreateWithDisplaySalutation(false),
+	}
+	if flags.Config != "" {
+		createOpts = append(createOpts, cluster.CreateWithConfigFile(flags.Config))
+	} else {
+		createOpts = append(createOpts, cluster.CreateWithRawConfig([]byte(defaultConfig)))
+	}
+	createErr := provider.CreateContext(ctx, flags.Name, createOpts...)
+	defer func() {
+		// always keep the logs, they are needed to debug failures
+		if err := provider.CollectLogs(flags.Name, filepath.Join(artifacts, "logs")); err != nil {
+			logger.Warnf("Failed to export logs: %v", err)
+		}
+		if flags.Retain {
+			logger.V(0).Infof("Retaining cluster %q, its kubeconfig is %s", flags.Name, kubeconfig)
+			return
+		}
+		if err := provider.Delete(flags.Name, kubeconfig); err != nil {
+			logger.Warnf("Failed to delete cluster %q: %v", flags.Name, err)
+		}
+	}()
+	if createErr != nil {
+		return errors.Wrap(createErr, "failed to create test cluster")
+	}
+
+	numNodes, err := schedulableNodes(provider, flags.Name)
+	if err != nil {
+		return err
+	}
+	env, err := testEnv(provider, flags.Name)
+	if err != nil {
+		return err
+	}
+	args := testArgs(flags, kubeconfig, artifacts, numNodes)
+	name := e2eTest
+	if ginkgo != "" {
+		name = ginkgo
+		args = append([]string{fmt.Sprintf("--nodes=%d", flags.Parallel), e2eTest, "--"}, args...)
+	}
+	logger.V(0).Infof("Running %s ...", exec.PrettyCommand(name, args...))
+	testCmd := exec.CommandContext(ctx, name, args...)
+	testCmd.SetEnv(append(os.Environ(), env...)...)
+	testCmd.SetStdout(streams.Out)
+	testCmd.SetStderr(streams.ErrOut)
+	if err := testCmd.Run(); err != nil {
+		return errors.Wrap(err, "e2e tests failed")
+	}
+	return nil
+}
+
+// testEnv configures e2e.test for the kind cluster name, the container
+// runtime is read from the node as it depends on the node image
+func testEnv(provider *cluster.Provider, name string) ([]string, error) {
+	nodes, err := provider.ListInternalNodes(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", name)
+	}
+	runtimeName, err := nodeutils.ContainerRuntime(nodes[0])
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := nodeutils.CRIEndpoint(nodes[0])
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		// prevents e2e.test from trying to run provider setup
+		"KUBERNETES_CONFORMANCE_TEST=y",
+		// required by the RuntimeClass tests
+		"KUBE_CONTAINER_RUNTIME=remote",
+		"KUBE_CONTAINER_RUNTIME_ENDPOINT=" + endpoint,
+		"KUBE_CONTAINER_RUNTIME_NAME=" + runtimeName,
+	}, nil
+}
+
+// testArgs returns the e2e.test arguments to run the selected tests against
+// the cluster
+func testArgs(flags *flagpole, kubeconfig, artifacts string, numNodes int) []string {
+	focus, skip := flags.Focus, flags.Skip
+	if focus == "" && flags.LabelFilter == "" {
+		focus = defaultFocus
+	}
+	// serial tests cannot run alongside others
+	if flags.Parallel > 1 {
+		if skip == "" {
+			skip = `\[Serial\]`
+		} else {
+			skip = `\[Serial\]|` + skip
+		}
+	}
+	args := []string{
+		"--kubeconfig=" + kubeconfig,
+		"--provider=skeleton",
+		fmt.Sprintf("--num-nodes=%d", numNodes),
+		"--report-dir=" + artifacts,
+		"--disable-log-dump=true",
+	}
+	if focus != "" {
+		args = append(args, "--ginkgo.focus="+focus)
+	}
+	if skip != "" {
+		args = append(args, "--ginkgo.skip="+skip)
+	}
+	if flags.LabelFilter != "" {
+		args = append(args, "--ginkgo.label-filter="+flags.LabelFilter)
+	}
+	return args
+}
+
+// defaultBinary returns path, or the binary in the Kubernetes build output
+// under kubeRoot if path is unset
+func defaultBinary(path, kubeRoot, binary string) string {
+	if path != "" || kubeRoot == "" {
+		return path
+	}
+	return filepath.Join(kubeRoot, "_output", "bin", binary)
+}
+
+// schedulableNodes returns the number of nodes tests may schedule pods on,
+// the workers, or the control plane nodes if there are none
+func schedulableNodes(provider *cluster.Provider, name string) (int, error) {
+	nodes, err := provider.ListInternalNodes(name)
+	if err != nil {
+		return 0, err
+	}
+	workers := 0
+	for _, node := range nodes {
+		role, err := node.Role()
+		if err != nil {
+			return 0, err
+		}
+		if role == constants.WorkerNodeRoleValue {
+			workers++
+		}
+	}
+	if workers == 0 {
+		return len(nodes), nil
+	}
+	return workers, nil
+}
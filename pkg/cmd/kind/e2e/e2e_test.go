@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestTestArgs(t *testing.T) {
+	t.Parallel()
+	common := []string{
+		"--kubeconfig=/artifacts/kubeconfig",
+		"--provider=skeleton",
+		"--num-nodes=2",
+		"--report-dir=/artifacts",
+		"--disable-log-dump=true",
+	}
+	cases := []struct {
+		Name     string
+		Flags    flagpole
+		Expected []string
+	}{
+		{
+			Name:     "conformance by default",
+			Flags:    flagpole{Parallel: 1},
+			Expected: append(common, `--ginkgo.focus=\[Conformance\]`),
+		},
+		{
+			Name:     "label filter without focus",
+			Flags:    flagpole{Parallel: 1, LabelFilter: "Feature: isEmpty"},
+			Expected: append(common, "--ginkgo.label-filter=Feature: isEmpty"),
+		},
+		{
+			Name:  "parallel skips serial tests",
+			Flags: flagpole{Parallel: 4, Focus: `\[sig-network\]`, Skip: "Slow"},
+			Expected: append(common,
+				`--ginkgo.focus=\[sig-network\]`,
+				`--ginkgo.skip=\[Serial\]|Slow`,
+			),
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.DeepEqual(t, tc.Expected, testArgs(&tc.Flags, "/artifacts/kubeconfig", "/artifacts", 2))
+		})
+	}
+}
+
+func TestDefaultBinary(t *testing.T) {
+	t.Parallel()
+	assert.StringEqual(t, "/bin/e2e.test", defaultBinary("/bin/e2e.test", "/kubernetes", "e2e.test"))
+	assert.StringEqual(t, "/kubernetes/_output/bin/e2e.test", defaultBinary("", "/kubernetes", "e2e.test"))
+	assert.StringEqual(t, "", defaultBinary("", "", "e2e.test"))
+}
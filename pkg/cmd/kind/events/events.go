@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events implements the `events` command
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name     string
+	Watch    bool
+	Since    time.Duration
+	FailFast bool
+	Output   string
+}
+
+// NewCommand returns a new cobra.Command for printing a cluster's node
+// container events
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "events",
+		Short: "Prints the container runtime events of a cluster's nodes",
+		Long: "Prints the container runtime events of a cluster's node containers: start, stop, kill, die, oom and restart.\n\n" +
+			"With --watch new events are streamed until interrupted, and with --fail-fast the command exits non-zero " +
+			"as soon as a node container dies or is OOM killed, so test harnesses can stop instead of waiting for timeouts.",
+		Example: "  kind events --since 1h\n" +
+			"  kind events --name my-cluster --watch -o json\n" +
+			"  kind events --watch --fail-fast &",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().BoolVarP(
+		&flags.Watch,
+		"watch",
+		"w",
+		false,
+		"keep streaming new events until interrupted",
+	)
+	cmd.Flags().DurationVar(
+		&flags.Since,
+		"since",
+		0,
+		"only print events newer than this, e.g. 10m, by default all events the runtime has are printed, or only new events with --watch",
+	)
+	cmd.Flags().BoolVar(
+		&flags.FailFast,
+		"fail-fast",
+		false,
+		"with --watch, exit non-zero when a node container dies or is OOM killed",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"text",
+		"output format, one of: text, json",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	switch flags.Output {
+	case "text", "json":
+	default:
+		return errors.Errorf("unknown output format %q, must be one of: text, json", flags.Output)
+	}
+	if flags.FailFast && !flags.Watch {
+		return errors.New("--fail-fast requires --watch")
+	}
+
+	var since time.Time
+	if flags.Since > 0 {
+		since = time.Now().Add(-flags.Since)
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+
+	if !flags.Watch {
+		events, err := provider.ListEvents(flags.Name, since)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list events of cluster %q", flags.Name)
+		}
+		for _, e := range events {
+			if err := printEvent(streams.Out, flags.Output, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var failed *cluster.NodeEvent
+	var printErr error
+	err := provider.WatchEvents(ctx, flags.Name, since, func(e cluster.NodeEvent) {
+		if printErr = printEvent(streams.Out, flags.Output, e); printErr != nil {
+			cancel()
+			return
+		}
+		if flags.FailFast && (e.Action == "die" || e.Action == "oom") && failed == nil {
+			failed = &e
+			cancel()
+		}
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to watch events of cluster %q", flags.Name)
+	}
+	if printErr != nil {
+		return printErr
+	}
+	if failed != nil {
+		if failed.Action == "oom" {
+			return errors.Errorf("node %q was OOM killed", failed.Node)
+		}
+		return errors.Errorf("node %q died with exit code %d", failed.Node, failed.ExitCode)
+	}
+	return nil
+}
+
+// printEvent prints e as a line of text or json, one event per line so that
+// the output can be consumed while streaming
+func printEvent(out io.Writer, output string, e cluster.NodeEvent) error {
+	if output == "json" {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode event")
+		}
+		_, err = fmt.Fprintf(out, "%s\n", b)
+		return err
+	}
+	line := fmt.Sprintf("%s  %s  %s", e.Time.Format(time.RFC3339), e.Node, e.Action)
+	if e.Action == "die" {
+		line += fmt.Sprintf(" (exit code %d)", e.ExitCode)
+	}
+	_, err := fmt.Fprintln(out, line)
+	return err
+}
@@ -26,12 +26,15 @@ import (
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
+	"sigs.k8s.io/kind/pkg/internal/usersettings"
 )
 
 type flagpole struct {
-	Name       string
-	Kubeconfig string
-	Internal   bool
+	Name            string
+	Kubeconfig      string
+	KubeconfigMode  string
+	KubeconfigOwner string
+	Internal        bool
 }
 
 // NewCommand returns a new cobra.Command for exporting the kubeconfig
@@ -60,6 +63,18 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		"",
 		"sets kubeconfig path instead of $KUBECONFIG or $HOME/.kube/config",
 	)
+	cmd.Flags().StringVar(
+		&flags.KubeconfigMode,
+		"kubeconfig-mode",
+		"",
+		"sets the octal mode of the kubeconfig file, e.g. 0644 (default 0600 for new files)",
+	)
+	cmd.Flags().StringVar(
+		&flags.KubeconfigOwner,
+		"kubeconfig-owner",
+		"",
+		"sets the owner of the kubeconfig file as user[:group], e.g. $SUDO_UID:$SUDO_GID (requires root)",
+	)
 	cmd.Flags().BoolVar(
 		&flags.Internal,
 		"internal",
@@ -73,12 +88,32 @@ func runE(logger log.Logger, flags *flagpole) error {
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
+		runtime.KubeconfigContextTemplate(),
 	)
-	if err := provider.ExportKubeConfig(flags.Name, flags.Kubeconfig, flags.Internal); err != nil {
+	// defaults from the user settings file are lower precedence than flags
+	settings := usersettings.Get()
+	mode, err := cli.ParseFileMode(flags.KubeconfigMode)
+	if err != nil {
+		return err
+	}
+	if mode == 0 {
+		mode = settings.KubeconfigFileMode()
+	}
+	owner := flags.KubeconfigOwner
+	if owner == "" {
+		owner = settings.KubeconfigOwner
+	}
+	if err := provider.ExportKubeConfig(
+		flags.Name, flags.Kubeconfig, flags.Internal,
+		cluster.ExportKubeConfigWithMode(mode),
+		cluster.ExportKubeConfigWithOwner(owner),
+	); err != nil {
+		return err
+	}
+	kctx, err := provider.KubeConfigContext(flags.Name)
+	if err != nil {
 		return err
 	}
-	// TODO: get kind-name from a method? OTOH we probably want to keep this
-	// naming scheme stable anyhow...
-	logger.V(0).Infof(`Set kubectl context to "kind-%s"`, flags.Name)
+	logger.V(0).Infof(`Set kubectl context to "%s"`, kctx)
 	return nil
 }
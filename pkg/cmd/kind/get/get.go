@@ -23,9 +23,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/cmd/kind/get/artifacts"
 	"sigs.k8s.io/kind/pkg/cmd/kind/get/clusters"
+	"sigs.k8s.io/kind/pkg/cmd/kind/get/images"
 	"sigs.k8s.io/kind/pkg/cmd/kind/get/kubeconfig"
+	"sigs.k8s.io/kind/pkg/cmd/kind/get/network"
 	"sigs.k8s.io/kind/pkg/cmd/kind/get/nodes"
+	"sigs.k8s.io/kind/pkg/cmd/kind/get/ports"
+	"sigs.k8s.io/kind/pkg/cmd/kind/get/pulledimages"
 	"sigs.k8s.io/kind/pkg/log"
 )
 
@@ -35,8 +40,8 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		Args: cobra.NoArgs,
 		// TODO(bentheelder): more detailed usage
 		Use:   "get",
-		Short: "Gets one of [clusters, nodes, kubeconfig]",
-		Long:  "Gets one of [clusters, nodes, kubeconfig]",
+		Short: "Gets one of [clusters, nodes, kubeconfig, artifacts, network, pulled-images, images, ports]",
+		Long:  "Gets one of [clusters, nodes, kubeconfig, artifacts, network, pulled-images, images, ports]",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := cmd.Help()
 			if err != nil {
@@ -49,5 +54,10 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 	cmd.AddCommand(clusters.NewCommand(logger, streams))
 	cmd.AddCommand(nodes.NewCommand(logger, streams))
 	cmd.AddCommand(kubeconfig.NewCommand(logger, streams))
+	cmd.AddCommand(artifacts.NewCommand(logger, streams))
+	cmd.AddCommand(network.NewCommand(logger, streams))
+	cmd.AddCommand(pulledimages.NewCommand(logger, streams))
+	cmd.AddCommand(images.NewCommand(logger, streams))
+	cmd.AddCommand(ports.NewCommand(logger, streams))
 	return cmd
 }
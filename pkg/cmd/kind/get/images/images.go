@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package images implements the `images` command
+package images
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name   string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for listing the images on a cluster's nodes
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "images",
+		Short: "Lists the images present on a cluster's nodes",
+		Long: "Lists the images in the container runtime of each of a cluster's nodes, like crictl images, " +
+			"with their digest and the nodes they are present on.\n\n" +
+			"An image is listed once per reference and image ID, so an image that differs between nodes, " +
+			"e.g. after kind load to only some nodes, is listed once for each version.",
+		Example: "  kind get images\n" +
+			"  kind get images --name my-cluster -o json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"table",
+		"output format, one of: table, json",
+	)
+	return cmd
+}
+
+// nodeImage is an image reference and the nodes it is present on
+type nodeImage struct {
+	Image  string   `json:"image"`
+	Digest string   `json:"digest,omitempty"`
+	ID     string   `json:"id"`
+	Nodes  []string `json:"nodes"`
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	switch flags.Output {
+	case "table", "json":
+	default:
+		return errors.Errorf("unknown output format %q, must be one of: table, json", flags.Output)
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	nodeList, err := provider.ListInternalNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	if len(nodeList) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", flags.Name)
+	}
+
+	// list the images on every node concurrently
+	nodeImages := make([][]nodeutils.Image, len(nodeList))
+	fns := []func() error{}
+	for i, node := range nodeList {
+		i, node := i, node // capture loop variables
+		fns = append(fns, func() error {
+			images, err := nodeutils.Images(node)
+			if err != nil {
+				return errors.Wrapf(err, "failed to list images on node %q", node.String())
+			}
+			nodeImages[i] = images
+			return nil
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return err
+	}
+	nodeNames := make([]string, len(nodeList))
+	for i, node := range nodeList {
+		nodeNames[i] = node.String()
+	}
+	images := mergeImages(nodeNames, nodeImages)
+
+	if flags.Output == "json" {
+		out, err := json.MarshalIndent(images, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode images")
+		}
+		_, err = streams.Out.Write(append(out, '\n'))
+		return err
+	}
+	return printImages(streams.Out, images)
+}
+
+// mergeImages merges the images of each node, listing each reference and
+// image ID once along with the nodes it is present on
+func mergeImages(nodeNames []string, nodeImages [][]nodeutils.Image) []*nodeImage {
+	merged := map[string]*nodeImage{}
+	for i, images := range nodeImages {
+		for _, image := range images {
+			refs := image.RepoTags
+			if len(refs) == 0 {
+				refs = image.RepoDigests
+			}
+			if len(refs) == 0 {
+				refs = []string{"<none>"}
+			}
+			for _, ref := range refs {
+				key := ref + "@" + image.ID
+				if _, ok := merged[key]; !ok {
+					merged[key] = &nodeImage{
+						Image:  ref,
+						Digest: repoDigest(ref, image.RepoDigests),
+						ID:     image.ID,
+					}
+				}
+				merged[key].Nodes = append(merged[key].Nodes, nodeNames[i])
+			}
+		}
+	}
+	result := make([]*nodeImage, 0, len(merged))
+	for _, image := range merged {
+		sort.Strings(image.Nodes)
+		result = append(result, image)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Image != result[j].Image {
+			return result[i].Image < result[j].Image
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// repoDigest returns the digest of ref from the image's repo digests, or ""
+// if it has none for ref's repository, e.g. for images loaded with kind load
+func repoDigest(ref string, repoDigests []string) string {
+	repository := ref
+	if i := strings.Index(ref, "@"); i != -1 {
+		repository = ref[:i]
+	} else if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		repository = ref[:i]
+	}
+	for _, repoDigest := range repoDigests {
+		if i := strings.Index(repoDigest, "@"); i != -1 && repoDigest[:i] == repository {
+			return repoDigest[i+1:]
+		}
+	}
+	return ""
+}
+
+func printImages(out io.Writer, images []*nodeImage) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "IMAGE\tDIGEST\tIMAGE ID\tNODES")
+	for _, image := range images {
+		digest := image.Digest
+		if digest == "" {
+			digest = "<none>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", image.Image, digest, shortID(image.ID), strings.Join(image.Nodes, ","))
+	}
+	return w.Flush()
+}
+
+// shortID truncates an image ID like crictl images
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 13 {
+		return id[:13]
+	}
+	return id
+}
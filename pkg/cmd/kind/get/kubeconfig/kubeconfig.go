@@ -68,6 +68,7 @@ func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
+		runtime.KubeconfigContextTemplate(),
 	)
 	cfg, err := provider.KubeConfig(flags.Name, flags.Internal)
 	if err != nil {
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package network implements the `network` command
+package network
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name   string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for getting a cluster's network
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "network",
+		Short: "Prints the network a cluster's nodes are attached to",
+		Long: "Prints the container network a cluster's nodes are attached to: its name, subnets, gateways and MTU, " +
+			"and the nodes' addresses on it.\n\n" +
+			"An MTU of 0 means the network uses the container runtime's default.",
+		Example: "  kind get network\n" +
+			"  kind get network --name my-cluster -o yaml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"json",
+		"output format, one of: json, yaml",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	info, err := provider.GetNetworkInfo(flags.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get network of cluster %q", flags.Name)
+	}
+
+	var out []byte
+	switch flags.Output {
+	case "json":
+		out, err = json.MarshalIndent(info, "", "  ")
+		out = append(out, '\n')
+	case "yaml":
+		out, err = yaml.Marshal(info)
+	default:
+		return errors.Errorf("unknown output format %q, must be one of: json, yaml", flags.Output)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to encode network")
+	}
+	_, err = streams.Out.Write(out)
+	return err
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ports implements the `ports` command
+package ports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name   string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for listing a cluster's published ports
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "ports",
+		Short: "Lists the ports a cluster's nodes publish on the host",
+		Long: "Lists the ports a cluster's nodes publish on the host, including the API server, " +
+			"extraPortMappings and the control plane load balancer, with the node container each maps to.",
+		Example: "  kind get ports\n" +
+			"  kind get ports --name my-cluster -o json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"table",
+		"output format, one of: table, json, yaml",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	switch flags.Output {
+	case "table", "json", "yaml":
+	default:
+		return errors.Errorf("unknown output format %q, must be one of: table, json, yaml", flags.Output)
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	ports, err := provider.ListPorts(flags.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list ports of cluster %q", flags.Name)
+	}
+
+	var out []byte
+	switch flags.Output {
+	case "table":
+		return printPorts(streams.Out, ports)
+	case "json":
+		out, err = json.MarshalIndent(ports, "", "  ")
+		out = append(out, '\n')
+	case "yaml":
+		out, err = yaml.Marshal(ports)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to encode ports")
+	}
+	_, err = streams.Out.Write(out)
+	return err
+}
+
+// printPorts prints ports as a table
+func printPorts(out io.Writer, ports []cluster.PortMapping) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tROLE\tCONTAINER PORT\tHOST ADDRESS\tNOTES")
+	for _, p := range ports {
+		notes := ""
+		if p.APIServer {
+			notes = "api-server"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d/%s\t%s\t%s\n",
+			p.Node, p.Role, p.ContainerPort, p.Protocol,
+			net.JoinHostPort(p.HostIP, strconv.Itoa(int(p.HostPort))), notes,
+		)
+	}
+	return w.Flush()
+}
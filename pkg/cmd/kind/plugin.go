@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kind
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/plugin"
+)
+
+// RunPlugin runs the kind-<name> plugin on PATH for args if they do not start
+// with one of root's commands, see `kind plugin`. It returns whether a plugin
+// was run and the error from running it.
+func RunPlugin(logger log.Logger, streams cmd.IOStreams, root *cobra.Command, args []string) (bool, error) {
+	path, pluginArgs, ok := plugin.Find(root, args)
+	if !ok {
+		return false, nil
+	}
+	return true, plugin.Run(logger, streams, path, pluginArgs)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package list implements the `list` command
+package list
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/plugin"
+)
+
+// NewCommand returns a new cobra.Command for listing plugins
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "list",
+		Short: "Lists the kind-<name> plugins on PATH",
+		Long: "Lists the paths of the kind-<name> plugin executables on PATH, " +
+			"warning about plugins that cannot run because a kind command has the same name",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, cmd.Root())
+		},
+	}
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, root *cobra.Command) error {
+	plugins := plugin.List()
+	if len(plugins) == 0 {
+		logger.V(0).Info("No plugins found on PATH.")
+		return nil
+	}
+	for _, p := range plugins {
+		fmt.Fprintln(streams.Out, p.Path)
+		if command := strings.Fields(p.Name)[0]; plugin.IsBuiltin(root, command) {
+			logger.Warnf("%s is shadowed by the kind %s command and will not run", p.Path, command)
+		}
+	}
+	return nil
+}
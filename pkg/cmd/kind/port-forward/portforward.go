@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package portforward implements the `port-forward` command
+package portforward
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name     string
+	Node     string
+	AllNodes bool
+	Address  string
+}
+
+// NewCommand returns a new cobra.Command for forwarding ports to nodes
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.MinimumNArgs(1),
+		Use:   "port-forward [LOCAL_PORT:]NODE_PORT...",
+		Short: "Forwards local ports to a cluster node",
+		Long: "Forwards one or more local ports to ports on a cluster node, for ports that were not mapped with extraPortMappings.\n\n" +
+			"Connections are relayed through the node provider, so this works wherever the node containers are not directly reachable from the host. " +
+			"The node defaults to the cluster's first control plane node. " +
+			"With --all-nodes connections are spread across all nodes, e.g. for a NodePort service.",
+		Example: "  # forward local port 8080 to port 30080 on the control plane node\n" +
+			"  kind port-forward 8080:30080\n\n" +
+			"  # forward local port 30080 to NodePort 30080 across all nodes\n" +
+			"  kind port-forward --all-nodes 30080",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, flags, args)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringVar(
+		&flags.Node,
+		"node",
+		"",
+		"the node to forward to, by name with or without the cluster name prefix",
+	)
+	cmd.Flags().BoolVar(
+		&flags.AllNodes,
+		"all-nodes",
+		false,
+		"spread connections across all nodes, e.g. for a NodePort service",
+	)
+	cmd.Flags().StringVar(
+		&flags.Address,
+		"address",
+		"127.0.0.1",
+		"the local address to listen on",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, args []string) error {
+	if flags.AllNodes && flags.Node != "" {
+		return errors.New("--node and --all-nodes are mutually exclusive")
+	}
+	forwards, err := parsePorts(args)
+	if err != nil {
+		return err
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	allNodes, err := provider.ListInternalNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	if len(allNodes) == 0 {
+		return fmt.Errorf("unknown cluster %q", flags.Name)
+	}
+	targets, err := selectTargets(allNodes, flags)
+	if err != nil {
+		return err
+	}
+
+	fns := []func() error{}
+	for _, f := range forwards {
+		f := f // capture f
+		l, err := net.Listen("tcp", net.JoinHostPort(flags.Address, strconv.Itoa(f.localPort)))
+		if err != nil {
+			return errors.Wrapf(err, "failed to listen on local port %d", f.localPort)
+		}
+		defer l.Close()
+		logger.V(0).Infof("Forwarding from %s -> %d", l.Addr(), f.nodePort)
+		fns = append(fns, func() error {
+			return serve(logger, l, targets, f.nodePort)
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
+
+// portForward is a parsed [LOCAL_PORT:]NODE_PORT argument
+type portForward struct {
+	localPort int
+	nodePort  int
+}
+
+// parsePorts parses [LOCAL_PORT:]NODE_PORT arguments
+func parsePorts(args []string) ([]portForward, error) {
+	forwards := make([]portForward, 0, len(args))
+	for _, arg := range args {
+		parts := strings.Split(arg, ":")
+		if len(parts) > 2 {
+			return nil, errors.Errorf("invalid port %q, expected [LOCAL_PORT:]NODE_PORT", arg)
+		}
+		ports := make([]int, 0, len(parts))
+		for _, part := range parts {
+			port, err := strconv.Atoi(part)
+			if err != nil || port < 1 || port > 65535 {
+				return nil, errors.Errorf("invalid port %q in %q", part, arg)
+			}
+			ports = append(ports, port)
+		}
+		forwards = append(forwards, portForward{
+			localPort: ports[0],
+			nodePort:  ports[len(ports)-1],
+		})
+	}
+	return forwards, nil
+}
+
+// selectTargets returns the nodes to forward connections to
+func selectTargets(allNodes []nodes.Node, flags *flagpole) ([]nodes.Node, error) {
+	if flags.AllNodes {
+		return allNodes, nil
+	}
+	if flags.Node == "" {
+		n, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+		if err != nil {
+			return nil, err
+		}
+		return []nodes.Node{n}, nil
+	}
+	for _, n := range allNodes {
+		if n.String() == flags.Node || n.String() == flags.Name+"-"+flags.Node {
+			return []nodes.Node{n}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown node %q in cluster %q", flags.Node, flags.Name)
+}
+
+// serve accepts connections on l, relaying each to port on the next of targets
+func serve(logger log.Logger, l net.Listener, targets []nodes.Node, port int) error {
+	var next uint32
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return errors.Wrap(err, "failed to accept connection")
+		}
+		target := targets[int(atomic.AddUint32(&next, 1)-1)%len(targets)]
+		go func() {
+			if err := relay(conn, target, port); err != nil {
+				logger.Warnf("Failed to forward connection to %s:%d: %v", target, port, err)
+			}
+		}()
+	}
+}
+
+// relayScript connects to $1:$2 from within the node, copying the
+// connection to and from stdio.
+// When the client is done sending (half-closes), the target's response is
+// still relayed until the target closes the connection. When the target is
+// done first the relay stops, as bash cannot half-close the client side.
+// stdin is duplicated first as background jobs otherwise read /dev/null
+const relayScript = `exec 3<>"/dev/tcp/$1/$2" 4<&0 || exit 1
+cat <&3 &
+reader=$!
+cat <&4 >&3 &
+wait -n
+if kill -0 "$reader" 2>/dev/null; then
+  wait "$reader"
+fi
+kill $(jobs -p) 2>/dev/null
+exit 0
+`
+
+// relay relays conn to port on node through the node provider
+func relay(conn net.Conn, node nodes.Node, port int) error {
+	defer conn.Close()
+	ipv4, ipv6, err := node.IP()
+	if err != nil {
+		return err
+	}
+	ip := ipv4
+	if ip == "" {
+		ip = ipv6
+	}
+
+	// NOTE: stdin is an os.File rather than conn so that the command does
+	// not wait on copying from conn after exiting, the copy is stopped by
+	// closing conn instead
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer pr.Close()
+	go func() {
+		_, _ = io.Copy(pw, conn)
+		pw.Close()
+	}()
+
+	return node.Command("bash", "-c", relayScript, "kind-port-forward", ip, strconv.Itoa(port)).
+		SetStdin(pr).
+		SetStdout(conn).
+		Run()
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"bufio"
+	"net"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_parsePorts(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []portForward
+		wantErr bool
+	}{
+		{
+			name: "same port",
+			args: []string{"30080"},
+			want: []portForward{{localPort: 30080, nodePort: 30080}},
+		},
+		{
+			name: "local and node port",
+			args: []string{"8080:80", "8443:443"},
+			want: []portForward{
+				{localPort: 8080, nodePort: 80},
+				{localPort: 8443, nodePort: 443},
+			},
+		},
+		{
+			name:    "too many parts",
+			args:    []string{"1:2:3"},
+			wantErr: true,
+		},
+		{
+			name:    "not a number",
+			args:    []string{"http"},
+			wantErr: true,
+		},
+		{
+			name:    "out of range",
+			args:    []string{"8080:65536"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePorts(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePorts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePorts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_relayScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	// the target only responds after reading the request, by which time the
+	// client has closed its side
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		_, _ = conn.Write([]byte("got " + line))
+	}()
+
+	port := l.Addr().(*net.TCPAddr).Port
+	cmd := exec.Command("bash", "-c", relayScript, "kind-port-forward", "127.0.0.1", strconv.Itoa(port))
+	cmd.Stdin = strings.NewReader("hello\n")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("relayScript failed: %v", err)
+	}
+	if got, want := string(out), "got hello\n"; got != want {
+		t.Errorf("relayScript relayed %q, want %q", got, want)
+	}
+}
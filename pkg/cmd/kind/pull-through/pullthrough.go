@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pullthrough implements the `pull-through` command
+package pullthrough
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name   string
+	Nodes  []string
+	Mirror string
+}
+
+// NewCommand returns a new cobra.Command for pulling images into the nodes
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("a list of image names is required")
+			}
+			return nil
+		},
+		Use:   "pull-through <IMAGE> [IMAGE...]",
+		Short: "Pulls images from a registry directly into nodes",
+		Long: "Pulls images from a registry directly into all or specified nodes by name, without saving them on the host first.\n\n" +
+			"Each node pulls with its own container runtime, so registry mirrors and credentials configured for the nodes are used. " +
+			"With --mirror the images are pulled from that registry instead and tagged with the requested names.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, flags, args)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Nodes,
+		"nodes",
+		nil,
+		"comma separated list of nodes to pull images into",
+	)
+	cmd.Flags().StringVar(
+		&flags.Mirror,
+		"mirror",
+		"",
+		"registry host to pull the images from instead, e.g. localhost:5001",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, args []string) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+
+	// Check if the cluster nodes exist
+	nodeList, err := provider.ListInternalNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	if len(nodeList) == 0 {
+		return fmt.Errorf("no nodes found for cluster %q", flags.Name)
+	}
+
+	// pick only the user selected nodes and ensure they exist
+	// the default is all nodes unless flags.Nodes is set
+	selectedNodes := nodeList
+	if len(flags.Nodes) > 0 {
+		nodesByName := map[string]nodes.Node{}
+		for _, node := range nodeList {
+			nodesByName[node.String()] = node
+		}
+		selectedNodes = []nodes.Node{}
+		for _, name := range flags.Nodes {
+			node, ok := nodesByName[name]
+			if !ok {
+				return fmt.Errorf("unknown node: %q", name)
+			}
+			selectedNodes = append(selectedNodes, node)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// pull on all nodes in parallel, each node pulls the images in order
+	fns := []func() error{}
+	for _, node := range selectedNodes {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			for _, image := range args {
+				if err := pullThrough(ctx, node, image, flags.Mirror); err != nil {
+					return errors.Wrapf(err, "failed to pull image %q on node %q", image, node.String())
+				}
+				logger.V(0).Infof("Image: %q present on node %q", image, node.String())
+			}
+			return nil
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
+
+// pullThrough pulls image onto node, from mirror if set, and verifies that
+// it is present afterwards
+func pullThrough(ctx context.Context, node nodes.Node, image, mirror string) error {
+	if mirror == "" {
+		if err := nodeutils.PullImage(ctx, node, image); err != nil {
+			return err
+		}
+	} else {
+		mirrored := mirrorImage(mirror, image)
+		if err := nodeutils.PullImage(ctx, node, mirrored); err != nil {
+			return err
+		}
+		id, err := nodeutils.ImageID(node, mirrored)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find pulled image %q", mirrored)
+		}
+		if err := nodeutils.ReTagImage(node, id, normalizeImage(image)); err != nil {
+			return errors.Wrapf(err, "failed to tag image %q", mirrored)
+		}
+	}
+	if _, err := nodeutils.ImageID(node, image); err != nil {
+		return errors.Wrap(err, "image not present after pulling")
+	}
+	return nil
+}
+
+// mirrorImage returns image with its registry replaced by mirror, e.g.
+// localhost:5001/library/nginx:latest for nginx
+func mirrorImage(mirror, image string) string {
+	normalized := normalizeImage(image)
+	return strings.TrimSuffix(mirror, "/") + normalized[strings.IndexRune(normalized, '/'):]
+}
+
+// normalizeImage returns the fully qualified form of image, e.g.
+// docker.io/library/nginx:latest for nginx
+func normalizeImage(image string) string {
+	const (
+		defaultDomain    = "docker.io"
+		officialRepoName = "library"
+	)
+	normalized := image
+	i := strings.IndexRune(normalized, '/')
+	if i == -1 || (!strings.ContainsAny(normalized[:i], ".:") && normalized[:i] != "localhost") {
+		if i == -1 {
+			normalized = officialRepoName + "/" + normalized
+		}
+		normalized = defaultDomain + "/" + normalized
+	}
+	// add the default tag if there is neither a tag nor a digest, the tag
+	// follows the last path component so that registry ports are not tags
+	name := normalized[strings.LastIndex(normalized, "/")+1:]
+	if !strings.ContainsAny(name, ":@") {
+		normalized += ":latest"
+	}
+	return normalized
+}
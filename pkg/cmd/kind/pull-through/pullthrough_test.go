@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullthrough
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestNormalizeImage(t *testing.T) {
+	cases := []struct {
+		Image    string
+		Expected string
+	}{
+		{Image: "nginx", Expected: "docker.io/library/nginx:latest"},
+		{Image: "nginx:1.27", Expected: "docker.io/library/nginx:1.27"},
+		{Image: "kindest/node:v1.31.0", Expected: "docker.io/kindest/node:v1.31.0"},
+		{Image: "registry.k8s.io/pause:3.10", Expected: "registry.k8s.io/pause:3.10"},
+		{Image: "localhost:5001/app", Expected: "localhost:5001/app:latest"},
+		{Image: "localhost/app", Expected: "localhost/app:latest"},
+		{Image: "nginx@sha256:abc", Expected: "docker.io/library/nginx@sha256:abc"},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Image, func(t *testing.T) {
+			t.Parallel()
+			assert.StringEqual(t, tc.Expected, normalizeImage(tc.Image))
+		})
+	}
+}
+
+func TestMirrorImage(t *testing.T) {
+	assert.StringEqual(t, "localhost:5001/library/nginx:latest", mirrorImage("localhost:5001", "nginx"))
+	assert.StringEqual(t, "mirror.gcr.io/kindest/node:v1.31.0", mirrorImage("mirror.gcr.io/", "kindest/node:v1.31.0"))
+	assert.StringEqual(t, "localhost:5001/pause:3.10", mirrorImage("localhost:5001", "registry.k8s.io/pause:3.10"))
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replay implements the `replay` command
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/debugbundle"
+)
+
+type flagpole struct {
+	Show    int
+	Execute bool
+}
+
+// NewCommand returns a new cobra.Command for replaying debug bundles
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Use:   "replay <debug-bundle-dir>",
+		Short: "Inspects or re-executes a debug bundle",
+		Long: "Inspects or re-executes a debug bundle recorded with --debug-bundle.\n\n" +
+			"By default the recorded commands are listed in the order they started, with their timings and results. " +
+			"With --execute the commands are run again one at a time in the same order, with their recorded input, " +
+			"reporting any whose result differs from the recording.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, flags, args[0])
+		},
+	}
+	cmd.Flags().IntVar(
+		&flags.Show,
+		"show",
+		-1,
+		"show the full input and output of the recorded command with this index",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Execute,
+		"execute",
+		false,
+		"re-execute the recorded commands",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole, dir string) error {
+	info, commands, err := debugbundle.Read(dir)
+	if err != nil {
+		return err
+	}
+	// commands are recorded as they finish, but replayed as they started
+	sort.SliceStable(commands, func(i, j int) bool {
+		return commands[i].Start.Before(commands[j].Start)
+	})
+
+	if flags.Show >= 0 {
+		for i := range commands {
+			if commands[i].Index == flags.Show {
+				return show(streams.Out, dir, &commands[i])
+			}
+		}
+		return errors.Errorf("no command with index %d in %s", flags.Show, dir)
+	}
+	if flags.Execute {
+		return execute(logger, dir, commands)
+	}
+	fmt.Fprintf(streams.Out, "Recorded %q with kind %s at %s\n\n",
+		strings.Join(info.Args, " "), info.Version, info.Start.Format(time.RFC3339))
+	list(streams.Out, info.Start, commands)
+	return nil
+}
+
+// list writes a line per command to w
+func list(w io.Writer, start time.Time, commands []debugbundle.Command) {
+	fmt.Fprintf(w, "%-6s %-9s %-9s %-6s %s\n", "INDEX", "START", "DURATION", "RESULT", "COMMAND")
+	for i := range commands {
+		c := &commands[i]
+		result := "ok"
+		if c.Error != "" {
+			result = "error"
+		}
+		fmt.Fprintf(w, "%-6d %-9s %-9s %-6s %s\n",
+			c.Index,
+			fmt.Sprintf("+%.1fs", c.Start.Sub(start).Seconds()),
+			fmt.Sprintf("%.2fs", c.DurationSeconds),
+			result,
+			exec.PrettyCommand(c.Command[0], c.Command[1:]...),
+		)
+	}
+}
+
+// show writes the full details of c to w
+func show(w io.Writer, dir string, c *debugbundle.Command) error {
+	stdin, err := debugbundle.ReadStdin(dir, c)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Command: %s\n", exec.PrettyCommand(c.Command[0], c.Command[1:]...))
+	fmt.Fprintf(w, "Started: %s\n", c.Start.Format("2006-01-02T15:04:05.000Z07:00"))
+	fmt.Fprintf(w, "Duration: %.3fs\n", c.DurationSeconds)
+	if c.Error != "" {
+		fmt.Fprintf(w, "Error: %s\n", c.Error)
+	}
+	if c.Stdin {
+		fmt.Fprintf(w, "\nInput%s%s:\n%s\n", truncatedNote(c.StdinTruncated), redactedNote(c.InputRedacted), stdin)
+	}
+	fmt.Fprintf(w, "\nOutput%s%s:\n%s\n", truncatedNote(c.OutputTruncated), redactedNote(c.OutputRedacted), c.Output)
+	return nil
+}
+
+func redactedNote(redacted bool) string {
+	if redacted {
+		return " (credentials redacted)"
+	}
+	return ""
+}
+
+func truncatedNote(truncated bool) string {
+	if truncated {
+		return fmt.Sprintf(" (truncated to %d bytes)", exec.MaxRecordedBytes)
+	}
+	return ""
+}
+
+// execute re-runs commands in order, logging those with a different result
+func execute(logger log.Logger, dir string, commands []debugbundle.Command) error {
+	differed := 0
+	for i := range commands {
+		c := &commands[i]
+		pretty := exec.PrettyCommand(c.Command[0], c.Command[1:]...)
+		if c.StdinTruncated {
+			logger.Warnf("Skipping command %d, its input was truncated: %s", c.Index, pretty)
+			continue
+		}
+		if c.InputRedacted {
+			logger.Warnf("Skipping command %d, credentials were redacted from it: %s", c.Index, pretty)
+			continue
+		}
+		logger.V(0).Infof("Running command %d: %s", c.Index, pretty)
+		cmd := exec.Command(c.Command[0], c.Command[1:]...)
+		if c.Stdin {
+			stdin, err := debugbundle.ReadStdin(dir, c)
+			if err != nil {
+				return err
+			}
+			cmd.SetStdin(bytes.NewReader(stdin))
+		}
+		err := cmd.Run()
+		if (err != nil) != (c.Error != "") {
+			differed++
+			logger.Warnf("Command %d succeeded=%t, recorded succeeded=%t", c.Index, err == nil, c.Error == "")
+			if err != nil {
+				logger.Warnf("%v", err)
+			}
+		}
+	}
+	if differed > 0 {
+		return errors.Errorf("%d of %d commands had a different result than recorded", differed, len(commands))
+	}
+	logger.V(0).Infof("All %d commands had the same result as recorded", len(commands))
+	return nil
+}
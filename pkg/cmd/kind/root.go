@@ -19,24 +19,53 @@ package kind
 
 import (
 	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cmd"
 	"sigs.k8s.io/kind/pkg/cmd/kind/build"
+	"sigs.k8s.io/kind/pkg/cmd/kind/clone"
 	"sigs.k8s.io/kind/pkg/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cmd/kind/config"
 	"sigs.k8s.io/kind/pkg/cmd/kind/create"
 	"sigs.k8s.io/kind/pkg/cmd/kind/delete"
+	"sigs.k8s.io/kind/pkg/cmd/kind/diff"
+	"sigs.k8s.io/kind/pkg/cmd/kind/doctor"
+	"sigs.k8s.io/kind/pkg/cmd/kind/e2e"
+	"sigs.k8s.io/kind/pkg/cmd/kind/events"
 	"sigs.k8s.io/kind/pkg/cmd/kind/export"
 	"sigs.k8s.io/kind/pkg/cmd/kind/get"
+	"sigs.k8s.io/kind/pkg/cmd/kind/kubeconfig"
 	"sigs.k8s.io/kind/pkg/cmd/kind/load"
+	"sigs.k8s.io/kind/pkg/cmd/kind/plugin"
+	portforward "sigs.k8s.io/kind/pkg/cmd/kind/port-forward"
+	"sigs.k8s.io/kind/pkg/cmd/kind/protect"
+	"sigs.k8s.io/kind/pkg/cmd/kind/prune"
+	pullthrough "sigs.k8s.io/kind/pkg/cmd/kind/pull-through"
+	"sigs.k8s.io/kind/pkg/cmd/kind/renew"
+	"sigs.k8s.io/kind/pkg/cmd/kind/replay"
+	"sigs.k8s.io/kind/pkg/cmd/kind/restore"
+	"sigs.k8s.io/kind/pkg/cmd/kind/runtimes"
+	"sigs.k8s.io/kind/pkg/cmd/kind/set"
+	"sigs.k8s.io/kind/pkg/cmd/kind/ssh"
+	"sigs.k8s.io/kind/pkg/cmd/kind/ui"
+	"sigs.k8s.io/kind/pkg/cmd/kind/unprotect"
+	"sigs.k8s.io/kind/pkg/cmd/kind/verify"
 	"sigs.k8s.io/kind/pkg/cmd/kind/version"
+	"sigs.k8s.io/kind/pkg/cmd/kind/wait"
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+	"sigs.k8s.io/kind/pkg/internal/usersettings"
 	"sigs.k8s.io/kind/pkg/log"
 )
 
 type flagpole struct {
 	Verbosity int32
 	Quiet     bool
+	Runtime   string
+	Progress  string
 }
 
 // NewCommand returns a new cobra.Command implementing the root command for kind
@@ -48,7 +77,7 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		Short: "kind is a tool for managing local Kubernetes clusters",
 		Long:  "kind creates and manages local Kubernetes clusters using Docker container 'nodes'",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return runE(logger, flags)
+			return runE(logger, flags, cmd.Flags().Changed("verbosity"))
 		},
 		SilenceUsage:  true,
 		SilenceErrors: true,
@@ -70,29 +99,96 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		false,
 		"silence all stderr output",
 	)
+	cmd.PersistentFlags().StringVar(
+		&flags.Runtime,
+		"runtime",
+		"",
+		"container runtime to use instead of auto-detecting one, one of: "+strings.Join(runtime.Names, ", "),
+	)
+	cmd.PersistentFlags().StringVar(
+		&flags.Progress,
+		"progress",
+		string(cli.ProgressAuto),
+		"how to display progress, one of: auto, tty, plain, quiet",
+	)
 	// add all top level subcommands
 	cmd.AddCommand(build.NewCommand(logger, streams))
+	cmd.AddCommand(clone.NewCommand(logger, streams))
 	cmd.AddCommand(completion.NewCommand(logger, streams))
+	cmd.AddCommand(config.NewCommand(logger, streams))
 	cmd.AddCommand(create.NewCommand(logger, streams))
 	cmd.AddCommand(delete.NewCommand(logger, streams))
+	cmd.AddCommand(diff.NewCommand(logger, streams))
+	cmd.AddCommand(doctor.NewCommand(logger, streams))
+	cmd.AddCommand(e2e.NewCommand(logger, streams))
+	cmd.AddCommand(events.NewCommand(logger, streams))
 	cmd.AddCommand(export.NewCommand(logger, streams))
 	cmd.AddCommand(get.NewCommand(logger, streams))
+	cmd.AddCommand(kubeconfig.NewCommand(logger, streams))
 	cmd.AddCommand(version.NewCommand(logger, streams))
 	cmd.AddCommand(load.NewCommand(logger, streams))
+	cmd.AddCommand(plugin.NewCommand(logger, streams))
+	cmd.AddCommand(portforward.NewCommand(logger, streams))
+	cmd.AddCommand(protect.NewCommand(logger, streams))
+	cmd.AddCommand(prune.NewCommand(logger, streams))
+	cmd.AddCommand(pullthrough.NewCommand(logger, streams))
+	cmd.AddCommand(renew.NewCommand(logger, streams))
+	cmd.AddCommand(replay.NewCommand(logger, streams))
+	cmd.AddCommand(restore.NewCommand(logger, streams))
+	cmd.AddCommand(runtimes.NewCommand(logger, streams))
+	cmd.AddCommand(set.NewCommand(logger, streams))
+	cmd.AddCommand(ssh.NewCommand(logger, streams))
+	cmd.AddCommand(ui.NewCommand(logger, streams))
+	cmd.AddCommand(unprotect.NewCommand(logger, streams))
+	cmd.AddCommand(verify.NewCommand(logger, streams))
+	cmd.AddCommand(wait.NewCommand(logger, streams))
 	return cmd
 }
 
-func runE(logger log.Logger, flags *flagpole) error {
+func runE(logger log.Logger, flags *flagpole, verbositySet bool) error {
+	// load the user settings file, these defaults apply below the flags
+	if err := usersettings.Load(); err != nil {
+		return err
+	}
+	settings := usersettings.Get()
 	// normal logger setup
 	if flags.Quiet {
 		// NOTE: if we are coming from app.Run handling this flag is
 		// redundant, however it doesn't hurt, and this may be called directly.
 		maybeSetWriter(logger, io.Discard)
 	}
-	maybeSetVerbosity(logger, log.Level(flags.Verbosity))
+	verbosity := flags.Verbosity
+	if !verbositySet && settings.Verbosity != 0 {
+		verbosity = settings.Verbosity
+	}
+	maybeSetVerbosity(logger, log.Level(verbosity))
+	progress, err := cli.ParseProgress(flags.Progress)
+	if err != nil {
+		return err
+	}
+	maybeSetProgress(logger, progress)
+	setProxyEnv(settings.Proxy)
+	if flags.Runtime != "" {
+		return runtime.Select(flags.Runtime)
+	}
 	return nil
 }
 
+// setProxyEnv sets the proxy environment variables that kind passes to the
+// nodes from the user settings, unless they are already set
+func setProxyEnv(proxy usersettings.Proxy) {
+	for name, value := range map[string]string{
+		"HTTP_PROXY":  proxy.HTTPProxy,
+		"HTTPS_PROXY": proxy.HTTPSProxy,
+		"NO_PROXY":    proxy.NoProxy,
+	} {
+		if value == "" || os.Getenv(name) != "" || os.Getenv(strings.ToLower(name)) != "" {
+			continue
+		}
+		_ = os.Setenv(name, value)
+	}
+}
+
 // maybeSetWriter will call logger.SetWriter(w) if logger has a SetWriter method
 func maybeSetWriter(logger log.Logger, w io.Writer) {
 	type writerSetter interface {
@@ -115,3 +211,15 @@ func maybeSetVerbosity(logger log.Logger, verbosity log.Level) {
 		v.SetVerbosity(verbosity)
 	}
 }
+
+// maybeSetProgress will call logger.SetProgress(progress) if logger
+// has a SetProgress method
+func maybeSetProgress(logger log.Logger, progress cli.Progress) {
+	type progressSetter interface {
+		SetProgress(cli.Progress)
+	}
+	v, ok := logger.(progressSetter)
+	if ok {
+		v.SetProgress(progress)
+	}
+}
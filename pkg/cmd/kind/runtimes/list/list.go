@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package list implements the `list` command
+package list
+
+import (
+	"fmt"
+	"io"
+	osexec "os/exec"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+// NewCommand returns a new cobra.Command for listing runtimes
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "list",
+		Short: "Lists the container runtimes kind supports and which is selected",
+		Long: "Lists the container runtimes that can be selected with --runtime, " +
+			"whether they are installed, and which one kind will use",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams)
+		},
+	}
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams) error {
+	selected, source := runtime.Selection()
+	if selected == "" {
+		opt, err := cluster.DetectNodeProvider()
+		if err == nil {
+			selected = cluster.NewProvider(cluster.ProviderWithLogger(logger), opt).Name()
+			source = "auto-detected"
+		}
+	}
+	installed := map[string]bool{}
+	for _, name := range runtime.Names {
+		_, err := osexec.LookPath(name)
+		installed[name] = err == nil
+	}
+	return printRuntimes(streams.Out, runtime.Names, installed, selected, source)
+}
+
+func printRuntimes(out io.Writer, names []string, installed map[string]bool, selected, source string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tINSTALLED\tSELECTED")
+	for _, name := range names {
+		isInstalled := "no"
+		if installed[name] {
+			isInstalled = "yes"
+		}
+		isSelected := ""
+		if name == selected {
+			isSelected = "yes (" + source + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, isInstalled, isSelected)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintRuntimes(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	err := printRuntimes(&out,
+		[]string{"docker", "podman", "nerdctl"},
+		map[string]bool{"docker": true, "podman": true},
+		"podman", "--runtime",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "NAME      INSTALLED   SELECTED\n" +
+		"docker    yes         \n" +
+		"podman    yes         yes (--runtime)\n" +
+		"nerdctl   no          \n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%q\nbut got:\n%q", expected, out.String())
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loglevel implements the `set log-level` command
+package loglevel
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name               string
+	KubeletVerbosity   int32
+	ContainerdLogLevel string
+	Nodes              []string
+}
+
+// NewCommand returns a new cobra.Command for setting the log levels of a
+// cluster's node components
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "log-level",
+		Short: "Sets the log levels of the kubelet and containerd on a cluster's nodes",
+		Long: "Sets the log levels of the kubelet and containerd on a cluster's nodes, " +
+			"or only on the nodes given with --nodes. The components are restarted to pick up " +
+			"the levels, running containers are not affected. The levels are kept across node restarts.\n\n" +
+			"To go back to the defaults, set --kubelet-verbosity 0 --containerd-log-level info.",
+		Example: "  kind set log-level --kubelet-verbosity 6\n" +
+			"  kind set log-level --name my-cluster --containerd-log-level debug --nodes my-cluster-worker",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			levels := cluster.LogLevels{
+				ContainerdLogLevel: flags.ContainerdLogLevel,
+			}
+			if cmd.Flags().Changed("kubelet-verbosity") {
+				levels.KubeletVerbosity = &flags.KubeletVerbosity
+			}
+			return runE(logger, flags, levels)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().Int32Var(
+		&flags.KubeletVerbosity,
+		"kubelet-verbosity",
+		0,
+		"the kubelet's log verbosity, from 0 to 10",
+	)
+	cmd.Flags().StringVar(
+		&flags.ContainerdLogLevel,
+		"containerd-log-level",
+		"",
+		"containerd's log level, one of: trace, debug, info, warn, error",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Nodes,
+		"nodes",
+		nil,
+		"comma separated list of nodes to set the log levels on, by default all nodes",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, levels cluster.LogLevels) error {
+	if levels.KubeletVerbosity == nil && levels.ContainerdLogLevel == "" {
+		return errors.New("at least one of --kubelet-verbosity or --containerd-log-level is required")
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	if err := provider.SetLogLevel(flags.Name, levels, flags.Nodes...); err != nil {
+		return errors.Wrapf(err, "failed to set log levels of cluster %q", flags.Name)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ssh implements the `ssh` command
+package ssh
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/env"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+// shellPath is the PATH set for the shell, the node image's default PATH
+// is not inherited by exec'd processes on all providers
+const shellPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// NewCommand returns a new cobra.Command for opening a shell in a node
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Args:  cobra.MaximumNArgs(2),
+		Use:   "ssh [cluster-name] [node-name]",
+		Short: "Opens an interactive shell in a cluster node",
+		Long: "Opens an interactive shell in a cluster node.\n\n" +
+			"The cluster defaults to $KIND_CLUSTER_NAME or \"kind\", and the node defaults to the cluster's first control plane node. " +
+			"The node may be given by its full name or without the cluster name prefix, e.g. \"worker\".",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, args)
+		},
+	}
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, args []string) error {
+	name := cluster.DefaultName
+	if env := os.Getenv("KIND_CLUSTER_NAME"); env != "" {
+		name = env
+	}
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	allNodes, err := provider.ListNodes(name)
+	if err != nil {
+		return err
+	}
+	if len(allNodes) == 0 {
+		return fmt.Errorf("unknown cluster %q", name)
+	}
+
+	var node nodes.Node
+	if len(args) > 1 {
+		node, err = selectNode(allNodes, name, args[1])
+	} else {
+		node, err = nodeutils.BootstrapControlPlaneNode(allNodes)
+	}
+	if err != nil {
+		return err
+	}
+
+	term := os.Getenv("TERM")
+	if term == "" {
+		term = "xterm"
+	}
+	shell := node.Command("bash", "-l").
+		SetEnv("TERM="+term, "PATH="+shellPath).
+		SetStdin(streams.In).
+		SetStdout(streams.Out).
+		SetStderr(streams.ErrOut)
+	// allocate a TTY for the shell when used from a terminal
+	if ttyCmd, ok := shell.(exec.TTYCmd); ok && isTerminal(streams) {
+		shell = ttyCmd.SetTTY(true)
+	}
+	return shell.Run()
+}
+
+// isTerminal returns true if both the input and output of streams are
+// terminals
+func isTerminal(streams cmd.IOStreams) bool {
+	f, ok := streams.In.(*os.File)
+	return ok && env.IsTerminal(f) && env.IsTerminal(streams.Out)
+}
+
+// selectNode returns the node named nodeName, which may omit the
+// "<clusterName>-" prefix
+func selectNode(allNodes []nodes.Node, clusterName, nodeName string) (nodes.Node, error) {
+	for _, n := range allNodes {
+		if n.String() == nodeName || n.String() == clusterName+"-"+nodeName {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown node %q in cluster %q", nodeName, clusterName)
+}
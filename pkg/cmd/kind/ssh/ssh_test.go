@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+type fakeNode struct {
+	nodes.Node
+	name string
+}
+
+func (n *fakeNode) String() string {
+	return n.name
+}
+
+func Test_selectNode(t *testing.T) {
+	allNodes := []nodes.Node{
+		&fakeNode{name: "kind-control-plane"},
+		&fakeNode{name: "kind-worker"},
+		&fakeNode{name: "kind-worker2"},
+	}
+	tests := []struct {
+		name     string
+		nodeName string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "full name",
+			nodeName: "kind-worker2",
+			want:     "kind-worker2",
+		},
+		{
+			name:     "without cluster prefix",
+			nodeName: "worker",
+			want:     "kind-worker",
+		},
+		{
+			name:     "unknown node",
+			nodeName: "worker3",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectNode(allNodes, "kind", tt.nodeName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectNode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("selectNode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ui implements the `ui` command
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cmd"
+	load "sigs.k8s.io/kind/pkg/cmd/kind/load/docker-image"
+	"sigs.k8s.io/kind/pkg/cmd/kind/ssh"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/fs"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/env"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Refresh time.Duration
+}
+
+// NewCommand returns a new cobra.Command for the interactive terminal UI
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "ui",
+		Short: "Starts an interactive terminal UI for managing kind clusters",
+		Long: "Starts an interactive terminal UI listing kind clusters and their nodes with live status.\n\n" +
+			"Clusters can be deleted, have their logs exported or images loaded, and nodes can be shelled into, " +
+			"by typing the action's key followed by enter.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().DurationVar(
+		&flags.Refresh,
+		"refresh",
+		5*time.Second,
+		"how often to refresh cluster and node status",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	if !env.IsTerminal(streams.Out) {
+		return errors.New("kind ui requires an interactive terminal")
+	}
+	if flags.Refresh <= 0 {
+		return errors.Errorf("--refresh must be positive, got %v", flags.Refresh)
+	}
+	u := &ui{
+		logger:  logger,
+		streams: streams,
+		provider: cluster.NewProvider(
+			cluster.ProviderWithLogger(logger),
+			runtime.GetDefault(logger),
+			runtime.KubeconfigContextTemplate(),
+		),
+		in:    bufio.NewReader(streams.In),
+		clear: env.IsSmartTerminal(streams.Out),
+	}
+	return u.run(flags.Refresh)
+}
+
+// ui holds the state of the terminal UI
+type ui struct {
+	logger   log.Logger
+	streams  cmd.IOStreams
+	provider *cluster.Provider
+	in       *bufio.Reader
+	// clear is true if the screen may be cleared with VT escape codes
+	clear bool
+
+	clusters []string
+	selected string
+	// message is shown below the nodes until the next action
+	message string
+}
+
+// run draws the UI and handles input until the user quits
+func (u *ui) run(refresh time.Duration) error {
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+	for {
+		if err := u.draw(); err != nil {
+			return err
+		}
+		// NOTE: a line is only read while waiting here, so that actions
+		// such as the node shell can take over the input in between
+		lines := u.readLine()
+	wait:
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					return nil
+				}
+				quit, err := u.handle(strings.TrimSpace(line))
+				if err != nil || quit {
+					return err
+				}
+				break wait
+			case <-ticker.C:
+				if err := u.draw(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// readLine reads the next line of input in the background, the returned
+// channel is closed without a value at the end of the input
+func (u *ui) readLine() <-chan string {
+	lines := make(chan string, 1)
+	go func() {
+		defer close(lines)
+		line, err := u.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return
+		}
+		lines <- line
+	}()
+	return lines
+}
+
+// prompt asks the user for input, returning "" at the end of the input
+func (u *ui) prompt(format string, args ...interface{}) string {
+	fmt.Fprintf(u.streams.Out, format, args...)
+	return strings.TrimSpace(<-u.readLine())
+}
+
+// refresh updates the list of clusters and the selected cluster
+func (u *ui) refresh() error {
+	clusters, err := u.provider.List()
+	if err != nil {
+		return err
+	}
+	u.clusters = clusters
+	for _, c := range clusters {
+		if c == u.selected {
+			return nil
+		}
+	}
+	u.selected = ""
+	if len(clusters) > 0 {
+		u.selected = clusters[0]
+	}
+	return nil
+}
+
+// draw refreshes the state and renders the screen
+func (u *ui) draw() error {
+	if err := u.refresh(); err != nil {
+		return errors.Wrap(err, "failed to list clusters")
+	}
+	var rows []nodeRow
+	if u.selected != "" {
+		n, err := u.provider.ListNodes(u.selected)
+		if err != nil {
+			return errors.Wrap(err, "failed to list nodes")
+		}
+		rows = nodeRows(n)
+	}
+	if u.clear {
+		// move the cursor home and clear the screen
+		fmt.Fprint(u.streams.Out, "\x1b[H\x1b[2J")
+	}
+	render(u.streams.Out, u.clusters, u.selected, rows, u.message)
+	return nil
+}
+
+// handle performs the action for a line of input, returning true to quit
+func (u *ui) handle(input string) (bool, error) {
+	u.message = ""
+	if input == "" || input == "r" {
+		return false, nil
+	}
+	if input == "q" {
+		return true, nil
+	}
+	if i, err := strconv.Atoi(input); err == nil {
+		if i < 1 || i > len(u.clusters) {
+			u.message = fmt.Sprintf("No cluster number %d", i)
+			return false, nil
+		}
+		u.selected = u.clusters[i-1]
+		return false, nil
+	}
+	if u.selected == "" {
+		u.message = "No cluster selected"
+		return false, nil
+	}
+	var err error
+	switch input {
+	case "d":
+		err = u.deleteCluster()
+	case "l":
+		err = u.exportLogs()
+	case "s":
+		err = u.shell()
+	case "i":
+		err = u.loadImage()
+	default:
+		u.message = fmt.Sprintf("Unknown action %q", input)
+	}
+	if err != nil {
+		u.message = fmt.Sprintf("ERROR: %v", err)
+	}
+	return false, nil
+}
+
+func (u *ui) deleteCluster() error {
+	if answer := u.prompt("Delete cluster %q? [y/N]: ", u.selected); answer != "y" && answer != "Y" {
+		return nil
+	}
+	if err := u.provider.Delete(u.selected, ""); err != nil {
+		return errors.Wrapf(err, "failed to delete cluster %q", u.selected)
+	}
+	u.message = fmt.Sprintf("Deleted cluster %q", u.selected)
+	return nil
+}
+
+func (u *ui) exportLogs() error {
+	dir, err := fs.TempDir("", "")
+	if err != nil {
+		return err
+	}
+	if err := u.provider.CollectLogs(u.selected, dir); err != nil {
+		return errors.Wrapf(err, "failed to export logs for cluster %q", u.selected)
+	}
+	u.message = fmt.Sprintf("Exported logs for cluster %q to: %s", u.selected, dir)
+	return nil
+}
+
+func (u *ui) shell() error {
+	args := []string{u.selected}
+	if node := u.prompt("Node [control plane]: "); node != "" {
+		args = append(args, node)
+	}
+	fmt.Fprintln(u.streams.Out, "Starting a shell, exit it to return to kind ui")
+	// NOTE: the command reads the input directly rather than through u.in,
+	// so that the shell exiting does not wait for another line
+	c := ssh.NewCommand(u.logger, u.streams)
+	c.SetArgs(args)
+	c.SilenceUsage = true
+	c.SilenceErrors = true
+	// the shell's exit status is not an error of the UI
+	if err := c.Execute(); err != nil && exec.RunErrorForError(err) == nil {
+		return err
+	}
+	return nil
+}
+
+func (u *ui) loadImage() error {
+	image := u.prompt("Image to load into cluster %q: ", u.selected)
+	if image == "" {
+		return nil
+	}
+	c := load.NewCommand(u.logger, u.streams)
+	c.SetArgs([]string{"--name", u.selected, image})
+	c.SilenceUsage = true
+	c.SilenceErrors = true
+	if err := c.Execute(); err != nil {
+		return err
+	}
+	u.message = fmt.Sprintf("Loaded image %q into cluster %q", image, u.selected)
+	return nil
+}
+
+// nodeRow is the rendered state of a node
+type nodeRow struct {
+	Name   string
+	Role   string
+	Status string
+	IP     string
+}
+
+// nodeRows gathers the current state of allNodes
+func nodeRows(allNodes []nodes.Node) []nodeRow {
+	rows := make([]nodeRow, 0, len(allNodes))
+	for _, n := range allNodes {
+		row := nodeRow{
+			Name:   n.String(),
+			Status: nodeStatus(n),
+		}
+		if role, err := n.Role(); err == nil {
+			row.Role = role
+		}
+		if ipv4, ipv6, err := n.IP(); err == nil {
+			row.IP = ipv4
+			if row.IP == "" {
+				row.IP = ipv6
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// nodeStatus returns the systemd state of the node, or "stopped" if the
+// node cannot be reached
+func nodeStatus(n nodes.Node) string {
+	// NOTE: is-system-running exits non-zero for states other than running,
+	// the state is still printed
+	lines, _ := exec.OutputLines(n.Command("systemctl", "is-system-running"))
+	if len(lines) == 0 || lines[0] == "" {
+		return "stopped"
+	}
+	return lines[0]
+}
+
+// render writes the screen for the given state to w
+func render(w io.Writer, clusters []string, selected string, rows []nodeRow, message string) {
+	fmt.Fprintf(w, "kind ui - %s\n\n", time.Now().Format("15:04:05"))
+	fmt.Fprintln(w, "CLUSTERS")
+	if len(clusters) == 0 {
+		fmt.Fprintln(w, "  No kind clusters found.")
+	}
+	for i, c := range clusters {
+		marker := " "
+		if c == selected {
+			marker = ">"
+		}
+		fmt.Fprintf(w, "%s %d) %s\n", marker, i+1, c)
+	}
+	if selected != "" {
+		fmt.Fprintf(w, "\nNODES (%s)\n", selected)
+		fmt.Fprintf(w, "  %-32s %-15s %-12s %s\n", "NAME", "ROLE", "STATUS", "IP")
+		for _, r := range rows {
+			fmt.Fprintf(w, "  %-32s %-15s %-12s %s\n", r.Name, r.Role, r.Status, r.IP)
+		}
+	}
+	if message != "" {
+		fmt.Fprintf(w, "\n%s\n", message)
+	}
+	fmt.Fprintln(w, "\n[1-9] select cluster  [s] shell into node  [l] export logs  [i] load image  [d] delete  [r] refresh  [q] quit")
+	fmt.Fprint(w, "> ")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		clusters []string
+		selected string
+		rows     []nodeRow
+		message  string
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:    "no clusters",
+			want:    []string{"No kind clusters found."},
+			notWant: []string{"NODES"},
+		},
+		{
+			name:     "selected cluster",
+			clusters: []string{"kind", "dev"},
+			selected: "dev",
+			rows: []nodeRow{
+				{Name: "dev-control-plane", Role: "control-plane", Status: "running", IP: "172.18.0.2"},
+			},
+			message: "Deleted cluster \"other\"",
+			want: []string{
+				"  1) kind\n",
+				"> 2) dev\n",
+				"NODES (dev)",
+				"dev-control-plane",
+				"172.18.0.2",
+				"Deleted cluster \"other\"",
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var buff bytes.Buffer
+			render(&buff, tc.clusters, tc.selected, tc.rows, tc.message)
+			out := buff.String()
+			for _, s := range tc.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("expected output to contain %q, got:\n%s", s, out)
+				}
+			}
+			for _, s := range tc.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("expected output not to contain %q, got:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wait implements the `wait` command
+package wait
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	For     string
+	Timeout time.Duration
+}
+
+// NewCommand returns a new cobra.Command for waiting on cluster conditions
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.MaximumNArgs(1),
+		Use:   "wait [cluster-name]",
+		Short: "Waits for a condition on a cluster",
+		Long: "Waits for a condition on a cluster, exiting non-zero if it is not met before the timeout.\n\n" +
+			"The cluster defaults to $KIND_CLUSTER_NAME or \"kind\". The conditions are:\n" +
+			"  ready                all control plane nodes are Ready, as with kind create cluster --wait\n" +
+			"  apiserver-available  the API server reports that it is ready\n" +
+			"  nodes=<count>        at least count nodes are Ready\n" +
+			"  daemonset=<ns>/<name>   the DaemonSet exists and all of its pods are ready\n" +
+			"  deployment=<ns>/<name>  the Deployment exists and is Available\n" +
+			"  crd=<name>              the CustomResourceDefinition exists and is Established",
+		Example: "  kind wait --for=ready --timeout=5m\n" +
+			"  kind wait --for=nodes=3 my-cluster\n" +
+			"  kind wait --for=daemonset=kube-system/kindnet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, flags, args)
+		},
+	}
+	cmd.Flags().StringVar(
+		&flags.For,
+		"for",
+		"ready",
+		"the condition to wait for: ready, apiserver-available, nodes=<count>, daemonset=<ns>/<name>, deployment=<ns>/<name> or crd=<name>",
+	)
+	cmd.Flags().DurationVar(
+		&flags.Timeout,
+		"timeout",
+		5*time.Minute,
+		"how long to wait for the condition",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, args []string) error {
+	name := cluster.DefaultName
+	if env := os.Getenv("KIND_CLUSTER_NAME"); env != "" {
+		name = env
+	}
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := provider.Wait(ctx, name, flags.For, flags.Timeout); err != nil {
+		return errors.Wrapf(err, "failed waiting for cluster %q", name)
+	}
+	logger.V(1).Infof("Cluster %q met condition %s", name, flags.For)
+	return nil
+}
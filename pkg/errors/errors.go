@@ -92,3 +92,43 @@ func StackTrace(err error) pkgerrors.StackTrace {
 	}
 	return nil
 }
+
+// Find returns the deepest error in err's chain for which match returns
+// true, or nil if there is none. Both Cause and Unwrap chains are followed,
+// as are the errors of an Aggregate, of which the first with a match is used.
+//
+// This allows finding errors such as those from running commands after they
+// have been wrapped by fmt.Errorf or joined by UntilErrorConcurrent.
+func Find(err error, match func(error) bool) error {
+	var found error
+	for err != nil {
+		if match(err) {
+			found = err
+		}
+		if agg, ok := err.(Aggregate); ok {
+			for _, e := range agg.Errors() {
+				if f := Find(e, match); f != nil {
+					return f
+				}
+			}
+			break
+		}
+		next := unwrap(err)
+		if next == err {
+			break
+		}
+		err = next
+	}
+	return found
+}
+
+// unwrap returns the error err wraps, preferring Cause to Unwrap
+func unwrap(err error) error {
+	switch e := err.(type) {
+	case Causer:
+		return e.Cause()
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	}
+	return nil
+}
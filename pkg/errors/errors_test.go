@@ -17,6 +17,7 @@ limitations under the License.
 package errors
 
 import (
+	"fmt"
 	"testing"
 
 	pkgerrors "github.com/pkg/errors"
@@ -40,3 +41,76 @@ func TestStackTrace(t *testing.T) {
 		assert.DeepEqual(t, expected, result)
 	})
 }
+
+type matchError struct {
+	inner error
+}
+
+func (e *matchError) Error() string { return "match" }
+
+func (e *matchError) Cause() error {
+	if e.inner != nil {
+		return e.inner
+	}
+	return e
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	isMatch := func(err error) bool {
+		_, ok := err.(*matchError)
+		return ok
+	}
+	deepest := &matchError{}
+	cases := []struct {
+		Name     string
+		Err      error
+		Expected error
+	}{
+		{
+			Name:     "nil",
+			Err:      nil,
+			Expected: nil,
+		},
+		{
+			Name:     "no match",
+			Err:      Wrap(New("foo"), "bar"),
+			Expected: nil,
+		},
+		{
+			Name:     "self cause",
+			Err:      deepest,
+			Expected: deepest,
+		},
+		{
+			Name:     "wrapped chain",
+			Err:      Wrap(WithStack(deepest), "bar"),
+			Expected: deepest,
+		},
+		{
+			Name:     "deepest match",
+			Err:      Wrap(&matchError{inner: Wrap(deepest, "foo")}, "bar"),
+			Expected: deepest,
+		},
+		{
+			Name:     "fmt wrapped",
+			Err:      fmt.Errorf("bar: %w", Wrap(deepest, "foo")),
+			Expected: deepest,
+		},
+		{
+			Name:     "aggregate",
+			Err:      Wrap(NewAggregate([]error{New("foo"), Wrap(deepest, "bar"), &matchError{}}), "baz"),
+			Expected: deepest,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			result := Find(tc.Err, isMatch)
+			if result != tc.Expected {
+				t.Errorf("expected %v but got %v", tc.Expected, result)
+			}
+		})
+	}
+}
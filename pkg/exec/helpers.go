@@ -42,21 +42,28 @@ func PrettyCommand(name string, args ...string) string {
 
 // RunErrorForError returns a RunError if the error contains a RunError.
 // Otherwise it returns nil
+//
+// Cause and Unwrap chains are followed, as are the errors of an
+// errors.Aggregate such as those from errors.UntilErrorConcurrent.
 func RunErrorForError(err error) *RunError {
-	var runError *RunError
-	for {
-		if rErr, ok := err.(*RunError); ok {
-			runError = rErr
-		}
-		if causerErr, ok := err.(errors.Causer); ok {
-			err = causerErr.Cause()
-		} else {
-			break
-		}
-	}
+	runError, _ := errors.Find(err, isRunError).(*RunError)
 	return runError
 }
 
+func isRunError(err error) bool {
+	_, ok := err.(*RunError)
+	return ok
+}
+
+// CommandErrorForError returns a CommandError if the error contains a
+// RunError, see RunErrorForError. Otherwise it returns nil
+func CommandErrorForError(err error) CommandError {
+	if runError := RunErrorForError(err); runError != nil {
+		return commandError{runError}
+	}
+	return nil
+}
+
 // CombinedOutputLines is like os/exec's cmd.CombinedOutput(),
 // but over our Cmd interface, and instead of returning the byte buffer of
 // stderr + stdout, it scans these for lines and returns a slice of output lines
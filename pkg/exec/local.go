@@ -20,8 +20,10 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"os"
 	osexec "os/exec"
 	"sync"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/errors"
 )
@@ -29,26 +31,33 @@ import (
 // LocalCmd wraps os/exec.Cmd, implementing the kind/pkg/exec.Cmd interface
 type LocalCmd struct {
 	*osexec.Cmd
+	recorder Recorder
 }
 
 var _ Cmd = &LocalCmd{}
 
 // LocalCmder is a factory for LocalCmd, implementing Cmder
-type LocalCmder struct{}
+type LocalCmder struct {
+	mu       sync.Mutex
+	recorder Recorder
+	added    []*Recorder
+}
 
 var _ Cmder = &LocalCmder{}
 
 // Command returns a new exec.Cmd backed by Cmd
 func (c *LocalCmder) Command(name string, arg ...string) Cmd {
 	return &LocalCmd{
-		Cmd: osexec.Command(name, arg...),
+		Cmd:      osexec.Command(name, arg...),
+		recorder: c.getRecorder(),
 	}
 }
 
 // CommandContext is like Command but includes a context
 func (c *LocalCmder) CommandContext(ctx context.Context, name string, arg ...string) Cmd {
 	return &LocalCmd{
-		Cmd: osexec.CommandContext(ctx, name, arg...),
+		Cmd:      osexec.CommandContext(ctx, name, arg...),
+		recorder: c.getRecorder(),
 	}
 }
 
@@ -119,15 +128,37 @@ func (cmd *LocalCmd) Run() error {
 			cmd.Stderr = combinedOutputWriter
 		}
 	}
+	// capture stdin for the recorder, files are not captured so that
+	// they are still passed directly to the command
+	var stdin *cappedBuffer
+	if _, isFile := cmd.Stdin.(*os.File); cmd.recorder != nil && cmd.Stdin != nil && !isFile {
+		stdin = &cappedBuffer{}
+		cmd.Stdin = io.TeeReader(cmd.Stdin, stdin)
+	}
+	start := time.Now()
 	// TODO: should be in the caller or logger should be injected somehow ...
+	var runErr error
 	if err := cmd.Cmd.Run(); err != nil {
-		return errors.WithStack(&RunError{
+		runErr = errors.WithStack(&RunError{
 			Command: cmd.Args,
 			Output:  combinedOutput.Bytes(),
 			Inner:   err,
 		})
 	}
-	return nil
+	if cmd.recorder != nil {
+		record := &Record{
+			Command:  cmd.Args,
+			Start:    start,
+			Duration: time.Since(start),
+			Err:      runErr,
+		}
+		record.Output, record.OutputTruncated = truncate(combinedOutput.Bytes())
+		if stdin != nil {
+			record.Stdin, record.StdinTruncated = stdin.buff.Bytes(), stdin.truncated
+		}
+		cmd.recorder(record)
+	}
+	return runErr
 }
 
 // interfaceEqual protects against panics from doing equality tests on
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"bytes"
+	"time"
+)
+
+// MaxRecordedBytes is the maximum length of the stdin and output in a Record
+const MaxRecordedBytes = 1 << 20
+
+// Record describes a command run by a LocalCmder
+type Record struct {
+	// Command is [Name Args...]
+	Command []string
+	// Start is when the command was started
+	Start time.Time
+	// Duration is how long the command ran for
+	Duration time.Duration
+	// Stdin is the input supplied to the command, if it was not a file
+	Stdin []byte
+	// StdinTruncated is true if Stdin was longer than MaxRecordedBytes
+	StdinTruncated bool
+	// Output is the combined stdout and stderr of the command
+	Output []byte
+	// OutputTruncated is true if Output was longer than MaxRecordedBytes
+	OutputTruncated bool
+	// Err is the error running the command, if any
+	Err error
+}
+
+// Recorder is called with a Record after each command a LocalCmder runs,
+// it may be called concurrently
+type Recorder func(*Record)
+
+// SetRecorder sets the Recorder for commands created by c after this call,
+// nil disables recording
+func (c *LocalCmder) SetRecorder(recorder Recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recorder = recorder
+}
+
+// AddRecorder adds a Recorder for commands created by c after this call,
+// alongside the one set with SetRecorder. Calling remove removes it again.
+func (c *LocalCmder) AddRecorder(recorder Recorder) (remove func()) {
+	added := &recorder
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.added = append(c.added, added)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i := range c.added {
+			if c.added[i] == added {
+				c.added = append(c.added[:i], c.added[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (c *LocalCmder) getRecorder() Recorder {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	recorders := []Recorder{}
+	if c.recorder != nil {
+		recorders = append(recorders, c.recorder)
+	}
+	for _, added := range c.added {
+		recorders = append(recorders, *added)
+	}
+	switch len(recorders) {
+	case 0:
+		return nil
+	case 1:
+		return recorders[0]
+	}
+	return func(record *Record) {
+		for _, recorder := range recorders {
+			recorder(record)
+		}
+	}
+}
+
+// cappedBuffer is an io.Writer keeping at most MaxRecordedBytes
+type cappedBuffer struct {
+	buff      bytes.Buffer
+	truncated bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if remaining := MaxRecordedBytes - c.buff.Len(); n > remaining {
+		p = p[:remaining]
+		c.truncated = true
+	}
+	c.buff.Write(p)
+	return n, nil
+}
+
+// truncate returns at most MaxRecordedBytes of b, and true if b was longer
+func truncate(b []byte) ([]byte, bool) {
+	if len(b) > MaxRecordedBytes {
+		return b[:MaxRecordedBytes], true
+	}
+	return b, false
+}
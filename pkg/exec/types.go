@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	osexec "os/exec"
+
+	"sigs.k8s.io/kind/pkg/errors"
 )
 
 // Cmd abstracts over running a command somewhere, this is useful for testing
@@ -34,6 +37,16 @@ type Cmd interface {
 	SetStderr(io.Writer) Cmd
 }
 
+// TTYCmd is implemented by Cmds that can allocate a TTY for the command,
+// such as the commands of nodes, for interactive use like shells.
+// A TTY combines stderr with stdout and rewrites line endings, so this is
+// opt-in
+type TTYCmd interface {
+	Cmd
+	// SetTTY allocates a TTY for the command if tty is true
+	SetTTY(tty bool) Cmd
+}
+
 // Cmder abstracts over creating commands
 type Cmder interface {
 	// command, args..., just like os/exec.Cmd
@@ -42,6 +55,9 @@ type Cmder interface {
 }
 
 // RunError represents an error running a Cmd
+//
+// Library consumers should prefer CommandErrorForError, which also finds
+// the RunError in errors that have been wrapped or aggregated.
 type RunError struct {
 	Command []string // [Name Args...]
 	Output  []byte   // Captured Stdout / Stderr of the command
@@ -68,3 +84,51 @@ func (e *RunError) Cause() error {
 	}
 	return e
 }
+
+// Unwrap returns the underlying error, for the standard library errors package
+func (e *RunError) Unwrap() error {
+	return e.Inner
+}
+
+// ExitCode returns the exit code of the command, or -1 if the command did
+// not exit or the exit code is otherwise not known
+func (e *RunError) ExitCode() int {
+	if exitErr, ok := errors.Find(e.Inner, isExitError).(*osexec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func isExitError(err error) bool {
+	_, ok := err.(*osexec.ExitError)
+	return ok
+}
+
+// CommandError provides typed accessors for an error from running a Cmd,
+// allowing consumers of kind's public APIs to report failed commands
+// with their output like the kind CLI does
+type CommandError interface {
+	error
+	// Command returns the command that failed as [Name Args...]
+	Command() []string
+	// Output returns the captured stdout / stderr of the command
+	Output() []byte
+	// ExitCode returns the exit code of the command, or -1 if not known
+	ExitCode() int
+}
+
+// commandError implements CommandError for a RunError, whose fields
+// predate the interface
+type commandError struct {
+	*RunError
+}
+
+var _ CommandError = commandError{}
+
+func (e commandError) Command() []string {
+	return e.RunError.Command
+}
+
+func (e commandError) Output() []byte {
+	return e.RunError.Output
+}
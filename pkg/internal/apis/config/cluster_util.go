@@ -16,12 +16,66 @@ limitations under the License.
 
 package config
 
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
 // ClusterHasIPv6 returns true if the cluster should have IPv6 enabled due to either
 // being IPv6 cluster family or Dual Stack
 func ClusterHasIPv6(c *Cluster) bool {
 	return c.Networking.IPFamily == IPv6Family || c.Networking.IPFamily == DualStackFamily
 }
 
+// ClusterPrimaryIPFamily returns the primary IP family of the cluster, for
+// dual-stack clusters this is the family of the first service subnet
+func ClusterPrimaryIPFamily(c *Cluster) ClusterIPFamily {
+	if c.Networking.IPFamily != DualStackFamily {
+		return c.Networking.IPFamily
+	}
+	if family := subnetFamily(strings.Split(c.Networking.ServiceSubnet, ",")[0]); family != "" {
+		return family
+	}
+	return IPv4Family
+}
+
+// subnetFamily returns the IP family of the CIDR, or "" if it is not valid
+func subnetFamily(cidr string) ClusterIPFamily {
+	ip, _, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	switch {
+	case err != nil:
+		return ""
+	case ip.To4() != nil:
+		return IPv4Family
+	default:
+		return IPv6Family
+	}
+}
+
+// orderSubnetsLike returns the comma separated dual-stack subnets reordered
+// so that the first has the same IP family as the first of like.
+// Anything other than one subnet of each family is returned unchanged, for
+// validation to report.
+func orderSubnetsLike(subnets, like string) string {
+	parts := strings.Split(subnets, ",")
+	likeParts := strings.Split(like, ",")
+	if len(parts) != 2 || len(likeParts) != 2 {
+		return subnets
+	}
+	first, second := subnetFamily(parts[0]), subnetFamily(parts[1])
+	if first == "" || second == "" || first == second {
+		return subnets
+	}
+	if first != subnetFamily(likeParts[0]) {
+		return parts[1] + "," + parts[0]
+	}
+	return subnets
+}
+
 // ClusterHasImplicitLoadBalancer returns true if this cluster has an implicit api-server LoadBalancer
 func ClusterHasImplicitLoadBalancer(c *Cluster) bool {
 	controlPlanes := 0
@@ -32,3 +86,44 @@ func ClusterHasImplicitLoadBalancer(c *Cluster) bool {
 	}
 	return controlPlanes > 1
 }
+
+// ClusterHasPodSecurity returns true if this cluster sets any PodSecurity
+// admission defaults or exemptions
+func ClusterHasPodSecurity(c *Cluster) bool {
+	p := c.PodSecurity
+	return p.Enforce != "" || p.Audit != "" || p.Warn != "" ||
+		len(p.Exemptions.Usernames) > 0 ||
+		len(p.Exemptions.RuntimeClasses) > 0 ||
+		len(p.Exemptions.Namespaces) > 0
+}
+
+// TimeoutDuration parses one of the Timeouts fields, returning zero when
+// it is unset, meaning there is no timeout.
+// Timeouts are validated before use, so unparseable values are also zero.
+func TimeoutDuration(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// ParsePortRange parses an inclusive host port range such as "30000-30100"
+func ParsePortRange(portRange string) (min, max int32, err error) {
+	parts := strings.Split(portRange, "-")
+	if len(parts) != 2 {
+		return 0, 0, errors.Errorf("%q is not a port range of the form min-max", portRange)
+	}
+	bounds := make([]int32, 2)
+	for i, part := range parts {
+		port, err := strconv.ParseInt(strings.TrimSpace(part), 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return 0, 0, errors.Errorf("invalid port %q in port range %q", part, portRange)
+		}
+		bounds[i] = int32(port)
+	}
+	if bounds[0] > bounds[1] {
+		return 0, 0, errors.Errorf("port range %q ends before it starts", portRange)
+	}
+	return bounds[0], bounds[1], nil
+}
@@ -136,3 +136,135 @@ func TestClusterHasImplicitLoadBalancer(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterHasPodSecurity(t *testing.T) {
+	t.Parallel()
+	assert.BoolEqual(t, false, ClusterHasPodSecurity(&Cluster{}))
+	assert.BoolEqual(t, true, ClusterHasPodSecurity(&Cluster{
+		PodSecurity: PodSecurity{Warn: RestrictedPodSecurityLevel},
+	}))
+	assert.BoolEqual(t, true, ClusterHasPodSecurity(&Cluster{
+		PodSecurity: PodSecurity{Exemptions: PodSecurityExemptions{Namespaces: []string{"ci"}}},
+	}))
+}
+
+func TestClusterPrimaryIPFamily(t *testing.T) {
+	cases := []struct {
+		Name          string
+		IPFamily      ClusterIPFamily
+		ServiceSubnet string
+		expected      ClusterIPFamily
+	}{
+		{
+			Name:          "IPv6",
+			IPFamily:      IPv6Family,
+			ServiceSubnet: "fd00:10:96::/112",
+			expected:      IPv6Family,
+		},
+		{
+			Name:          "DualStack IPv4 first",
+			IPFamily:      DualStackFamily,
+			ServiceSubnet: "10.96.0.0/16,fd00:10:96::/112",
+			expected:      IPv4Family,
+		},
+		{
+			Name:          "DualStack IPv6 first",
+			IPFamily:      DualStackFamily,
+			ServiceSubnet: "fd00:10:96::/112,10.96.0.0/16",
+			expected:      IPv6Family,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture loop var
+		t.Run(tc.Name, func(t *testing.T) {
+			c := &Cluster{Networking: Networking{IPFamily: tc.IPFamily, ServiceSubnet: tc.ServiceSubnet}}
+			assert.StringEqual(t, string(tc.expected), string(ClusterPrimaryIPFamily(c)))
+		})
+	}
+}
+
+func TestDualStackSubnetOrdering(t *testing.T) {
+	cases := []struct {
+		Name                  string
+		PodSubnet             string
+		ServiceSubnet         string
+		expectedPodSubnet     string
+		expectedServiceSubnet string
+	}{
+		{
+			Name:                  "defaults",
+			expectedPodSubnet:     "10.244.0.0/16,fd00:10:244::/56",
+			expectedServiceSubnet: "10.96.0.0/16,fd00:10:96::/112",
+		},
+		{
+			Name:                  "pod subnet follows the service subnet",
+			PodSubnet:             "10.244.0.0/16,fd00:10:244::/56",
+			ServiceSubnet:         "fd00:10:96::/112,10.96.0.0/16",
+			expectedPodSubnet:     "fd00:10:244::/56,10.244.0.0/16",
+			expectedServiceSubnet: "fd00:10:96::/112,10.96.0.0/16",
+		},
+		{
+			Name:                  "default pod subnet follows the service subnet",
+			ServiceSubnet:         "fd00:10:96::/112,10.96.0.0/16",
+			expectedPodSubnet:     "fd00:10:244::/56,10.244.0.0/16",
+			expectedServiceSubnet: "fd00:10:96::/112,10.96.0.0/16",
+		},
+		{
+			Name:                  "default service subnet follows the pod subnet",
+			PodSubnet:             "fd00:10:244::/56,10.244.0.0/16",
+			expectedPodSubnet:     "fd00:10:244::/56,10.244.0.0/16",
+			expectedServiceSubnet: "fd00:10:96::/112,10.96.0.0/16",
+		},
+		{
+			Name:                  "single family is left to validation",
+			PodSubnet:             "fd00:10:244::/56",
+			ServiceSubnet:         "10.96.0.0/16,fd00:10:96::/112",
+			expectedPodSubnet:     "fd00:10:244::/56",
+			expectedServiceSubnet: "10.96.0.0/16,fd00:10:96::/112",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture loop var
+		t.Run(tc.Name, func(t *testing.T) {
+			c := &Cluster{Networking: Networking{
+				IPFamily:      DualStackFamily,
+				PodSubnet:     tc.PodSubnet,
+				ServiceSubnet: tc.ServiceSubnet,
+			}}
+			SetDefaultsCluster(c)
+			assert.StringEqual(t, tc.expectedPodSubnet, c.Networking.PodSubnet)
+			assert.StringEqual(t, tc.expectedServiceSubnet, c.Networking.ServiceSubnet)
+		})
+	}
+}
+
+func TestParsePortRange(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		PortRange   string
+		ExpectMin   int32
+		ExpectMax   int32
+		ExpectError bool
+	}{
+		{Name: "range", PortRange: "30000-30100", ExpectMin: 30000, ExpectMax: 30100},
+		{Name: "single port", PortRange: "6443-6443", ExpectMin: 6443, ExpectMax: 6443},
+		{Name: "spaces", PortRange: "30000 - 30100", ExpectMin: 30000, ExpectMax: 30100},
+		{Name: "no max", PortRange: "30000", ExpectError: true},
+		{Name: "reversed", PortRange: "30100-30000", ExpectError: true},
+		{Name: "zero", PortRange: "0-100", ExpectError: true},
+		{Name: "too large", PortRange: "60000-70000", ExpectError: true},
+		{Name: "not a number", PortRange: "a-b", ExpectError: true},
+	}
+	for _, tc := range cases {
+		tc := tc // capture loop var
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			min, max, err := ParsePortRange(tc.PortRange)
+			assert.ExpectError(t, tc.ExpectError, err)
+			if !tc.ExpectError {
+				assert.DeepEqual(t, []int32{tc.ExpectMin, tc.ExpectMax}, []int32{min, max})
+			}
+		})
+	}
+}
@@ -28,34 +28,132 @@ func Convertv1alpha4(in *v1alpha4.Cluster) *Cluster {
 		Nodes:                           make([]Node, len(in.Nodes)),
 		FeatureGates:                    in.FeatureGates,
 		RuntimeConfig:                   in.RuntimeConfig,
+		SwapBehavior:                    SwapBehavior(in.SwapBehavior),
 		KubeadmConfigPatches:            in.KubeadmConfigPatches,
 		KubeadmConfigPatchesJSON6902:    make([]PatchJSON6902, len(in.KubeadmConfigPatchesJSON6902)),
+		KubeadmPatches:                  make([]KubeadmPatch, len(in.KubeadmPatches)),
+		ComponentImages:                 ComponentImages(in.ComponentImages),
+		KubeadmInitSkipPhases:           in.KubeadmInitSkipPhases,
+		ReadyConditions:                 in.ReadyConditions,
 		ContainerdConfigPatches:         in.ContainerdConfigPatches,
 		ContainerdConfigPatchesJSON6902: in.ContainerdConfigPatchesJSON6902,
+		InsecureRegistries:              in.InsecureRegistries,
+		NeverPullRegistries:             in.NeverPullRegistries,
+		RecordImagePulls:                in.RecordImagePulls,
+		Diagnostics:                     Diagnostics(in.Diagnostics),
+		HollowNodes:                     HollowNodes(in.HollowNodes),
 	}
 
 	for i := range in.Nodes {
+		mergev1alpha4NodeDefaults(&in.AllNodes, &in.Nodes[i])
 		convertv1alpha4Node(&in.Nodes[i], &out.Nodes[i])
 	}
 
 	convertv1alpha4Networking(&in.Networking, &out.Networking)
+	convertv1alpha4Addons(&in.Addons, &out.Addons)
+	convertv1alpha4Certificates(&in.Certificates, &out.Certificates)
+	convertv1alpha4EncryptionAtRest(&in.EncryptionAtRest, &out.EncryptionAtRest)
+	convertv1alpha4PodSecurity(&in.PodSecurity, &out.PodSecurity)
+	convertv1alpha4Timeouts(&in.Timeouts, &out.Timeouts)
 
 	for i := range in.KubeadmConfigPatchesJSON6902 {
 		convertv1alpha4PatchJSON6902(&in.KubeadmConfigPatchesJSON6902[i], &out.KubeadmConfigPatchesJSON6902[i])
 	}
 
+	for i := range in.KubeadmPatches {
+		convertv1alpha4KubeadmPatch(&in.KubeadmPatches[i], &out.KubeadmPatches[i])
+	}
+
 	return out
 }
 
+// mergev1alpha4NodeDefaults merges defaults into node, the node's own
+// settings take precedence
+func mergev1alpha4NodeDefaults(defaults *v1alpha4.NodeDefaults, node *v1alpha4.Node) {
+	node.Labels = mergeStringMaps(defaults.Labels, node.Labels)
+	node.Env = mergeStringMaps(defaults.Env, node.Env)
+	node.Sysctls = mergeStringMaps(defaults.Sysctls, node.Sysctls)
+	if node.ContainerdSnapshotter == "" {
+		node.ContainerdSnapshotter = defaults.ContainerdSnapshotter
+	}
+	node.ImageCache = node.ImageCache || defaults.ImageCache
+	if node.Logging.KubeletVerbosity == nil {
+		node.Logging.KubeletVerbosity = defaults.Logging.KubeletVerbosity
+	}
+	if node.Logging.ContainerdLogLevel == "" {
+		node.Logging.ContainerdLogLevel = defaults.Logging.ContainerdLogLevel
+	}
+	if node.Logging.JournalRateLimitInterval == "" {
+		node.Logging.JournalRateLimitInterval = defaults.Logging.JournalRateLimitInterval
+	}
+	if node.Logging.JournalRateLimitBurst == nil {
+		node.Logging.JournalRateLimitBurst = defaults.Logging.JournalRateLimitBurst
+	}
+
+	if len(defaults.ExtraMounts) > 0 {
+		nodeContainerPaths := make(map[string]bool, len(node.ExtraMounts))
+		for _, m := range node.ExtraMounts {
+			nodeContainerPaths[m.ContainerPath] = true
+		}
+		mounts := make([]v1alpha4.Mount, 0, len(defaults.ExtraMounts)+len(node.ExtraMounts))
+		for _, m := range defaults.ExtraMounts {
+			if !nodeContainerPaths[m.ContainerPath] {
+				mounts = append(mounts, m)
+			}
+		}
+		node.ExtraMounts = append(mounts, node.ExtraMounts...)
+	}
+}
+
+// mergeStringMaps returns the union of defaults and overrides, with the
+// values in overrides taking precedence
+func mergeStringMaps(defaults, overrides map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return overrides
+	}
+	merged := make(map[string]string, len(defaults)+len(overrides))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
 func convertv1alpha4Node(in *v1alpha4.Node, out *Node) {
 	out.Role = NodeRole(in.Role)
 	out.Image = in.Image
 
 	out.Labels = in.Labels
+	out.Env = in.Env
+	out.Sysctls = in.Sysctls
+	out.Memory = in.Memory
+	out.MemorySwap = in.MemorySwap
+	out.KubeReserved = in.KubeReserved
+	out.SystemReserved = in.SystemReserved
+	out.EvictionHard = in.EvictionHard
+	out.SandboxImage = in.SandboxImage
+	out.ContainerdSnapshotter = ContainerdSnapshotter(in.ContainerdSnapshotter)
+	out.ImageCache = in.ImageCache
+	out.Logging = NodeLogging(in.Logging)
 	out.KubeadmConfigPatches = in.KubeadmConfigPatches
+	out.Nesting = Nesting{
+		DevMount: in.Nesting.DevMount,
+		CgroupNS: CgroupNSMode(in.Nesting.CgroupNS),
+	}
+	out.Simulate = NodeSimulation{
+		Preset: SimulationPreset(in.Simulate.Preset),
+		OS:     in.Simulate.OS,
+		Arch:   in.Simulate.Arch,
+		Taints: in.Simulate.Taints,
+	}
 	out.ExtraMounts = make([]Mount, len(in.ExtraMounts))
 	out.ExtraPortMappings = make([]PortMapping, len(in.ExtraPortMappings))
 	out.KubeadmConfigPatchesJSON6902 = make([]PatchJSON6902, len(in.KubeadmConfigPatchesJSON6902))
+	out.SystemdUnits = make([]SystemdUnit, len(in.SystemdUnits))
+	out.StaticPods = make([]StaticPod, len(in.StaticPods))
+	out.AdditionalNetworks = make([]NodeNetwork, len(in.AdditionalNetworks))
 
 	for i := range in.ExtraMounts {
 		convertv1alpha4Mount(&in.ExtraMounts[i], &out.ExtraMounts[i])
@@ -68,6 +166,29 @@ func convertv1alpha4Node(in *v1alpha4.Node, out *Node) {
 	for i := range in.KubeadmConfigPatchesJSON6902 {
 		convertv1alpha4PatchJSON6902(&in.KubeadmConfigPatchesJSON6902[i], &out.KubeadmConfigPatchesJSON6902[i])
 	}
+
+	for i := range in.SystemdUnits {
+		convertv1alpha4SystemdUnit(&in.SystemdUnits[i], &out.SystemdUnits[i])
+	}
+
+	for i := range in.StaticPods {
+		convertv1alpha4StaticPod(&in.StaticPods[i], &out.StaticPods[i])
+	}
+
+	for i := range in.AdditionalNetworks {
+		out.AdditionalNetworks[i] = NodeNetwork(in.AdditionalNetworks[i])
+	}
+}
+
+func convertv1alpha4SystemdUnit(in *v1alpha4.SystemdUnit, out *SystemdUnit) {
+	out.Name = in.Name
+	out.Content = in.Content
+	out.Enabled = in.Enabled
+}
+
+func convertv1alpha4StaticPod(in *v1alpha4.StaticPod, out *StaticPod) {
+	out.Name = in.Name
+	out.Manifest = in.Manifest
 }
 
 func convertv1alpha4PatchJSON6902(in *v1alpha4.PatchJSON6902, out *PatchJSON6902) {
@@ -77,15 +198,83 @@ func convertv1alpha4PatchJSON6902(in *v1alpha4.PatchJSON6902, out *PatchJSON6902
 	out.Patch = in.Patch
 }
 
+func convertv1alpha4KubeadmPatch(in *v1alpha4.KubeadmPatch, out *KubeadmPatch) {
+	out.Target = KubeadmPatchTarget(in.Target)
+	out.PatchType = KubeadmPatchType(in.PatchType)
+	out.Patch = in.Patch
+	out.Path = in.Path
+}
+
 func convertv1alpha4Networking(in *v1alpha4.Networking, out *Networking) {
 	out.IPFamily = ClusterIPFamily(in.IPFamily)
 	out.APIServerPort = in.APIServerPort
+	out.APIServerPortRange = in.APIServerPortRange
 	out.APIServerAddress = in.APIServerAddress
+	out.APIServerAdditionalAddresses = in.APIServerAdditionalAddresses
+	out.APIServerCertSANs = in.APIServerCertSANs
 	out.PodSubnet = in.PodSubnet
 	out.KubeProxyMode = ProxyMode(in.KubeProxyMode)
 	out.ServiceSubnet = in.ServiceSubnet
+	out.NodeCIDRMaskSizeIPv4 = in.NodeCIDRMaskSizeIPv4
+	out.NodeCIDRMaskSizeIPv6 = in.NodeCIDRMaskSizeIPv6
 	out.DisableDefaultCNI = in.DisableDefaultCNI
+	out.KeepPortmap = in.KeepPortmap
+	out.HostRoutes = in.HostRoutes
 	out.DNSSearch = in.DNSSearch
+	if in.BridgedNetwork != nil {
+		out.BridgedNetwork = &BridgedNetwork{
+			Driver:  BridgedNetworkDriver(in.BridgedNetwork.Driver),
+			Parent:  in.BridgedNetwork.Parent,
+			Subnet:  in.BridgedNetwork.Subnet,
+			IPRange: in.BridgedNetwork.IPRange,
+			Gateway: in.BridgedNetwork.Gateway,
+		}
+	}
+	out.AdditionalNetworks = make([]AdditionalNetwork, len(in.AdditionalNetworks))
+	for i := range in.AdditionalNetworks {
+		out.AdditionalNetworks[i] = AdditionalNetwork(in.AdditionalNetworks[i])
+	}
+}
+
+func convertv1alpha4Addons(in *v1alpha4.Addons, out *Addons) {
+	out.MetricsServer = in.MetricsServer
+	out.DefaultStorage = DefaultStorage(in.DefaultStorage)
+	out.SharedStorage.HostPath = in.SharedStorage.HostPath
+	out.LoadBalancerPool.Size = in.LoadBalancerPool.Size
+	out.LoadBalancerPool.MetalLB = in.LoadBalancerPool.MetalLB
+}
+
+func convertv1alpha4Certificates(in *v1alpha4.Certificates, out *Certificates) {
+	out.CACertFile = in.CACertFile
+	out.CAKeyFile = in.CAKeyFile
+	out.ExportCADir = in.ExportCADir
+	out.CertificateValidityPeriod = in.CertificateValidityPeriod
+	out.CACertificateValidityPeriod = in.CACertificateValidityPeriod
+}
+
+func convertv1alpha4EncryptionAtRest(in *v1alpha4.EncryptionAtRest, out *EncryptionAtRest) {
+	out.Provider = EncryptionProvider(in.Provider)
+	out.Resources = in.Resources
+	out.KMSEndpoint = in.KMSEndpoint
+}
+
+func convertv1alpha4PodSecurity(in *v1alpha4.PodSecurity, out *PodSecurity) {
+	out.Enforce = PodSecurityLevel(in.Enforce)
+	out.Audit = PodSecurityLevel(in.Audit)
+	out.Warn = PodSecurityLevel(in.Warn)
+	out.Exemptions.Usernames = in.Exemptions.Usernames
+	out.Exemptions.RuntimeClasses = in.Exemptions.RuntimeClasses
+	out.Exemptions.Namespaces = in.Exemptions.Namespaces
+}
+
+func convertv1alpha4Timeouts(in *v1alpha4.Timeouts, out *Timeouts) {
+	out.ImagePull = in.ImagePull
+	out.ImagePullRetries = in.ImagePullRetries
+	out.ImagePullBackoff = in.ImagePullBackoff
+	out.ControlPlaneInit = in.ControlPlaneInit
+	out.Join = in.Join
+	out.CNIReady = in.CNIReady
+	out.NodesReady = in.NodesReady
 }
 
 func convertv1alpha4Mount(in *v1alpha4.Mount, out *Mount) {
@@ -93,7 +282,9 @@ func convertv1alpha4Mount(in *v1alpha4.Mount, out *Mount) {
 	out.HostPath = in.HostPath
 	out.Readonly = in.Readonly
 	out.SelinuxRelabel = in.SelinuxRelabel
+	out.SelinuxRelabelMode = SelinuxRelabelMode(in.SelinuxRelabelMode)
 	out.Propagation = MountPropagation(in.Propagation)
+	out.CreateHostPath = in.CreateHostPath
 }
 
 func convertv1alpha4PortMapping(in *v1alpha4.PortMapping, out *PortMapping) {
@@ -102,3 +293,180 @@ func convertv1alpha4PortMapping(in *v1alpha4.PortMapping, out *PortMapping) {
 	out.ListenAddress = in.ListenAddress
 	out.Protocol = PortMappingProtocol(in.Protocol)
 }
+
+// ConvertToV1alpha4 converts an internal cluster back to v1alpha4, e.g. to
+// show the defaulted config in the public API. Nodes have allNodes merged in,
+// so the result sets each node's fields rather than allNodes.
+func ConvertToV1alpha4(in *Cluster) *v1alpha4.Cluster {
+	in = in.DeepCopy() // deep copy first to avoid touching the original
+	out := &v1alpha4.Cluster{
+		TypeMeta: v1alpha4.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: "kind.x-k8s.io/v1alpha4",
+		},
+		Name:                            in.Name,
+		Nodes:                           make([]v1alpha4.Node, len(in.Nodes)),
+		FeatureGates:                    in.FeatureGates,
+		RuntimeConfig:                   in.RuntimeConfig,
+		SwapBehavior:                    v1alpha4.SwapBehavior(in.SwapBehavior),
+		KubeadmConfigPatches:            in.KubeadmConfigPatches,
+		KubeadmConfigPatchesJSON6902:    make([]v1alpha4.PatchJSON6902, len(in.KubeadmConfigPatchesJSON6902)),
+		KubeadmPatches:                  make([]v1alpha4.KubeadmPatch, len(in.KubeadmPatches)),
+		ComponentImages:                 v1alpha4.ComponentImages(in.ComponentImages),
+		KubeadmInitSkipPhases:           in.KubeadmInitSkipPhases,
+		ReadyConditions:                 in.ReadyConditions,
+		ContainerdConfigPatches:         in.ContainerdConfigPatches,
+		ContainerdConfigPatchesJSON6902: in.ContainerdConfigPatchesJSON6902,
+		InsecureRegistries:              in.InsecureRegistries,
+		NeverPullRegistries:             in.NeverPullRegistries,
+		RecordImagePulls:                in.RecordImagePulls,
+		Diagnostics:                     v1alpha4.Diagnostics(in.Diagnostics),
+		HollowNodes:                     v1alpha4.HollowNodes(in.HollowNodes),
+	}
+
+	for i := range in.Nodes {
+		convertNodeToV1alpha4(&in.Nodes[i], &out.Nodes[i])
+	}
+
+	convertNetworkingToV1alpha4(&in.Networking, &out.Networking)
+	convertAddonsToV1alpha4(&in.Addons, &out.Addons)
+	out.Certificates = v1alpha4.Certificates(in.Certificates)
+	out.EncryptionAtRest = v1alpha4.EncryptionAtRest{
+		Provider:    v1alpha4.EncryptionProvider(in.EncryptionAtRest.Provider),
+		Resources:   in.EncryptionAtRest.Resources,
+		KMSEndpoint: in.EncryptionAtRest.KMSEndpoint,
+	}
+	out.PodSecurity = v1alpha4.PodSecurity{
+		Enforce:    v1alpha4.PodSecurityLevel(in.PodSecurity.Enforce),
+		Audit:      v1alpha4.PodSecurityLevel(in.PodSecurity.Audit),
+		Warn:       v1alpha4.PodSecurityLevel(in.PodSecurity.Warn),
+		Exemptions: v1alpha4.PodSecurityExemptions(in.PodSecurity.Exemptions),
+	}
+	out.Timeouts = v1alpha4.Timeouts(in.Timeouts)
+
+	for i := range in.KubeadmConfigPatchesJSON6902 {
+		out.KubeadmConfigPatchesJSON6902[i] = v1alpha4.PatchJSON6902(in.KubeadmConfigPatchesJSON6902[i])
+	}
+
+	for i := range in.KubeadmPatches {
+		out.KubeadmPatches[i] = v1alpha4.KubeadmPatch{
+			Target:    v1alpha4.KubeadmPatchTarget(in.KubeadmPatches[i].Target),
+			PatchType: v1alpha4.KubeadmPatchType(in.KubeadmPatches[i].PatchType),
+			Patch:     in.KubeadmPatches[i].Patch,
+			Path:      in.KubeadmPatches[i].Path,
+		}
+	}
+
+	return out
+}
+
+func convertNodeToV1alpha4(in *Node, out *v1alpha4.Node) {
+	out.Role = v1alpha4.NodeRole(in.Role)
+	out.Image = in.Image
+
+	out.Labels = in.Labels
+	out.Env = in.Env
+	out.Sysctls = in.Sysctls
+	out.Memory = in.Memory
+	out.MemorySwap = in.MemorySwap
+	out.KubeReserved = in.KubeReserved
+	out.SystemReserved = in.SystemReserved
+	out.EvictionHard = in.EvictionHard
+	out.SandboxImage = in.SandboxImage
+	out.ContainerdSnapshotter = v1alpha4.ContainerdSnapshotter(in.ContainerdSnapshotter)
+	out.ImageCache = in.ImageCache
+	out.Logging = v1alpha4.NodeLogging(in.Logging)
+	out.KubeadmConfigPatches = in.KubeadmConfigPatches
+	out.Nesting = v1alpha4.Nesting{
+		DevMount: in.Nesting.DevMount,
+		CgroupNS: v1alpha4.CgroupNSMode(in.Nesting.CgroupNS),
+	}
+	out.Simulate = v1alpha4.NodeSimulation{
+		Preset: v1alpha4.SimulationPreset(in.Simulate.Preset),
+		OS:     in.Simulate.OS,
+		Arch:   in.Simulate.Arch,
+		Taints: in.Simulate.Taints,
+	}
+	out.ExtraMounts = make([]v1alpha4.Mount, len(in.ExtraMounts))
+	out.ExtraPortMappings = make([]v1alpha4.PortMapping, len(in.ExtraPortMappings))
+	out.KubeadmConfigPatchesJSON6902 = make([]v1alpha4.PatchJSON6902, len(in.KubeadmConfigPatchesJSON6902))
+	out.SystemdUnits = make([]v1alpha4.SystemdUnit, len(in.SystemdUnits))
+	out.StaticPods = make([]v1alpha4.StaticPod, len(in.StaticPods))
+	out.AdditionalNetworks = make([]v1alpha4.NodeNetwork, len(in.AdditionalNetworks))
+
+	for i, m := range in.ExtraMounts {
+		out.ExtraMounts[i] = v1alpha4.Mount{
+			ContainerPath:      m.ContainerPath,
+			HostPath:           m.HostPath,
+			Readonly:           m.Readonly,
+			SelinuxRelabel:     m.SelinuxRelabel,
+			SelinuxRelabelMode: v1alpha4.SelinuxRelabelMode(m.SelinuxRelabelMode),
+			Propagation:        v1alpha4.MountPropagation(m.Propagation),
+			CreateHostPath:     m.CreateHostPath,
+		}
+	}
+
+	for i, pm := range in.ExtraPortMappings {
+		out.ExtraPortMappings[i] = v1alpha4.PortMapping{
+			ContainerPort: pm.ContainerPort,
+			HostPort:      pm.HostPort,
+			ListenAddress: pm.ListenAddress,
+			Protocol:      v1alpha4.PortMappingProtocol(pm.Protocol),
+		}
+	}
+
+	for i := range in.KubeadmConfigPatchesJSON6902 {
+		out.KubeadmConfigPatchesJSON6902[i] = v1alpha4.PatchJSON6902(in.KubeadmConfigPatchesJSON6902[i])
+	}
+
+	for i := range in.SystemdUnits {
+		out.SystemdUnits[i] = v1alpha4.SystemdUnit(in.SystemdUnits[i])
+	}
+
+	for i := range in.StaticPods {
+		out.StaticPods[i] = v1alpha4.StaticPod(in.StaticPods[i])
+	}
+
+	for i := range in.AdditionalNetworks {
+		out.AdditionalNetworks[i] = v1alpha4.NodeNetwork(in.AdditionalNetworks[i])
+	}
+}
+
+func convertNetworkingToV1alpha4(in *Networking, out *v1alpha4.Networking) {
+	out.IPFamily = v1alpha4.ClusterIPFamily(in.IPFamily)
+	out.APIServerPort = in.APIServerPort
+	out.APIServerPortRange = in.APIServerPortRange
+	out.APIServerAddress = in.APIServerAddress
+	out.APIServerAdditionalAddresses = in.APIServerAdditionalAddresses
+	out.APIServerCertSANs = in.APIServerCertSANs
+	out.PodSubnet = in.PodSubnet
+	out.KubeProxyMode = v1alpha4.ProxyMode(in.KubeProxyMode)
+	out.ServiceSubnet = in.ServiceSubnet
+	out.NodeCIDRMaskSizeIPv4 = in.NodeCIDRMaskSizeIPv4
+	out.NodeCIDRMaskSizeIPv6 = in.NodeCIDRMaskSizeIPv6
+	out.DisableDefaultCNI = in.DisableDefaultCNI
+	out.KeepPortmap = in.KeepPortmap
+	out.HostRoutes = in.HostRoutes
+	out.DNSSearch = in.DNSSearch
+	if in.BridgedNetwork != nil {
+		out.BridgedNetwork = &v1alpha4.BridgedNetwork{
+			Driver:  v1alpha4.BridgedNetworkDriver(in.BridgedNetwork.Driver),
+			Parent:  in.BridgedNetwork.Parent,
+			Subnet:  in.BridgedNetwork.Subnet,
+			IPRange: in.BridgedNetwork.IPRange,
+			Gateway: in.BridgedNetwork.Gateway,
+		}
+	}
+	out.AdditionalNetworks = make([]v1alpha4.AdditionalNetwork, len(in.AdditionalNetworks))
+	for i := range in.AdditionalNetworks {
+		out.AdditionalNetworks[i] = v1alpha4.AdditionalNetwork(in.AdditionalNetworks[i])
+	}
+}
+
+func convertAddonsToV1alpha4(in *Addons, out *v1alpha4.Addons) {
+	out.MetricsServer = in.MetricsServer
+	out.DefaultStorage = v1alpha4.DefaultStorage(in.DefaultStorage)
+	out.SharedStorage.HostPath = in.SharedStorage.HostPath
+	out.LoadBalancerPool.Size = in.LoadBalancerPool.Size
+	out.LoadBalancerPool.MetalLB = in.LoadBalancerPool.MetalLB
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	v1alpha4 "sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestConvertv1alpha4AllNodes(t *testing.T) {
+	t.Parallel()
+	in := &v1alpha4.Cluster{
+		AllNodes: v1alpha4.NodeDefaults{
+			Labels: map[string]string{"tier": "all", "cache": "true"},
+			ExtraMounts: []v1alpha4.Mount{
+				{HostPath: "/cache", ContainerPath: "/cache"},
+				{HostPath: "/data", ContainerPath: "/data"},
+			},
+			ContainerdSnapshotter: v1alpha4.FuseOverlayfsSnapshotter,
+			Logging:               v1alpha4.NodeLogging{ContainerdLogLevel: "debug"},
+			ImageCache:            true,
+		},
+		Nodes: []v1alpha4.Node{
+			{
+				Role: v1alpha4.ControlPlaneRole,
+			},
+			{
+				Role:   v1alpha4.WorkerRole,
+				Labels: map[string]string{"tier": "worker"},
+				ExtraMounts: []v1alpha4.Mount{
+					{HostPath: "/worker-data", ContainerPath: "/data"},
+				},
+				ContainerdSnapshotter: v1alpha4.NativeSnapshotter,
+				Logging:               v1alpha4.NodeLogging{ContainerdLogLevel: "trace"},
+			},
+		},
+	}
+	out := Convertv1alpha4(in)
+
+	assert.DeepEqual(t, map[string]string{"tier": "all", "cache": "true"}, out.Nodes[0].Labels)
+	assert.DeepEqual(t, []Mount{
+		{HostPath: "/cache", ContainerPath: "/cache"},
+		{HostPath: "/data", ContainerPath: "/data"},
+	}, out.Nodes[0].ExtraMounts)
+	assert.StringEqual(t, string(FuseOverlayfsSnapshotter), string(out.Nodes[0].ContainerdSnapshotter))
+	assert.StringEqual(t, "debug", out.Nodes[0].Logging.ContainerdLogLevel)
+	if !out.Nodes[0].ImageCache {
+		t.Errorf("expected allNodes.imageCache to enable the node's ImageCache")
+	}
+
+	assert.DeepEqual(t, map[string]string{"tier": "worker", "cache": "true"}, out.Nodes[1].Labels)
+	assert.DeepEqual(t, []Mount{
+		{HostPath: "/cache", ContainerPath: "/cache"},
+		{HostPath: "/worker-data", ContainerPath: "/data"},
+	}, out.Nodes[1].ExtraMounts)
+	assert.StringEqual(t, string(NativeSnapshotter), string(out.Nodes[1].ContainerdSnapshotter))
+	assert.StringEqual(t, "trace", out.Nodes[1].Logging.ContainerdLogLevel)
+
+	// the input should not be modified
+	if in.Nodes[0].Labels != nil || len(in.Nodes[1].ExtraMounts) != 1 {
+		t.Errorf("input cluster was modified: %+v", in.Nodes)
+	}
+}
+
+func TestConvertv1alpha4Simulate(t *testing.T) {
+	t.Parallel()
+	in := &v1alpha4.Cluster{
+		Nodes: []v1alpha4.Node{
+			{
+				Role:     v1alpha4.WorkerRole,
+				Simulate: v1alpha4.NodeSimulation{Preset: v1alpha4.SimulationPresetWindows},
+			},
+			{
+				Role:     v1alpha4.WorkerRole,
+				Simulate: v1alpha4.NodeSimulation{Preset: v1alpha4.SimulationPresetARM64, Taints: []string{}},
+			},
+		},
+	}
+	out := Convertv1alpha4(in)
+	SetDefaultsCluster(out)
+	assert.DeepEqual(t, NodeSimulation{
+		Preset: SimulationPresetWindows,
+		OS:     "windows",
+		Arch:   "amd64",
+		Taints: []string{"os=windows:NoSchedule"},
+	}, out.Nodes[0].Simulate)
+	// explicitly set fields take precedence over the preset
+	assert.DeepEqual(t, NodeSimulation{
+		Preset: SimulationPresetARM64,
+		Arch:   "arm64",
+		Taints: []string{},
+	}, out.Nodes[1].Simulate)
+}
+
+func TestConvertToV1alpha4RoundTrip(t *testing.T) {
+	t.Parallel()
+	verbosity := int32(4)
+	in := &v1alpha4.Cluster{
+		Name: "round-trip",
+		Nodes: []v1alpha4.Node{
+			{
+				Role:   v1alpha4.ControlPlaneRole,
+				Labels: map[string]string{"tier": "control-plane"},
+				ExtraMounts: []v1alpha4.Mount{
+					{HostPath: "/data", ContainerPath: "/data", SelinuxRelabel: true, SelinuxRelabelMode: v1alpha4.SelinuxRelabelModeShared},
+				},
+				ExtraPortMappings: []v1alpha4.PortMapping{
+					{ContainerPort: 80, HostPort: 8080, Protocol: v1alpha4.PortMappingProtocolTCP},
+				},
+				SystemdUnits: []v1alpha4.SystemdUnit{{Name: "foo.service", Content: "[Unit]", Enabled: true}},
+				Logging:      v1alpha4.NodeLogging{KubeletVerbosity: &verbosity},
+				Simulate:     v1alpha4.NodeSimulation{Preset: v1alpha4.SimulationPresetARM64},
+			},
+			{
+				Role:               v1alpha4.WorkerRole,
+				AdditionalNetworks: []v1alpha4.NodeNetwork{{Name: "net1"}},
+			},
+		},
+		Networking: v1alpha4.Networking{
+			IPFamily:           v1alpha4.DualStackFamily,
+			AdditionalNetworks: []v1alpha4.AdditionalNetwork{{Name: "net1", Subnet: "10.10.0.0/24"}},
+		},
+		KubeadmPatches: []v1alpha4.KubeadmPatch{
+			{Target: v1alpha4.KubeadmPatchTargetKubeAPIServer, PatchType: v1alpha4.KubeadmPatchTypeStrategic, Patch: "spec: {}"},
+		},
+		FeatureGates: map[string]bool{"Foo": true},
+	}
+	expected := Convertv1alpha4(in)
+	SetDefaultsCluster(expected)
+
+	out := ConvertToV1alpha4(expected)
+	assert.StringEqual(t, "Cluster", out.Kind)
+	assert.StringEqual(t, "kind.x-k8s.io/v1alpha4", out.APIVersion)
+	assert.DeepEqual(t, expected, Convertv1alpha4(out))
+}
@@ -60,6 +60,10 @@ func SetDefaultsCluster(obj *Cluster) {
 		}
 	}
 
+	// subnets set by the user decide the dual-stack family order below
+	podSubnetSet := obj.Networking.PodSubnet != ""
+	serviceSubnetSet := obj.Networking.ServiceSubnet != ""
+
 	// default the pod CIDR
 	if obj.Networking.PodSubnet == "" {
 		obj.Networking.PodSubnet = "10.244.0.0/16"
@@ -86,10 +90,51 @@ func SetDefaultsCluster(obj *Cluster) {
 			obj.Networking.ServiceSubnet = "10.96.0.0/16,fd00:10:96::/112"
 		}
 	}
+	// dual-stack pod and service subnets must list the same family first,
+	// the first service subnet decides the primary family unless only the
+	// pod subnet was set
+	if obj.Networking.IPFamily == DualStackFamily {
+		if podSubnetSet && !serviceSubnetSet {
+			obj.Networking.ServiceSubnet = orderSubnetsLike(obj.Networking.ServiceSubnet, obj.Networking.PodSubnet)
+		} else {
+			obj.Networking.PodSubnet = orderSubnetsLike(obj.Networking.PodSubnet, obj.Networking.ServiceSubnet)
+		}
+	}
 	// default the KubeProxyMode using iptables as it's already the default
 	if obj.Networking.KubeProxyMode == "" {
 		obj.Networking.KubeProxyMode = IPTablesProxyMode
 	}
+	// default the bridged network driver to macvlan
+	if obj.Networking.BridgedNetwork != nil && obj.Networking.BridgedNetwork.Driver == "" {
+		obj.Networking.BridgedNetwork.Driver = MacvlanDriver
+	}
+	// default to the local-path-provisioner shipped in the node image
+	if obj.Addons.DefaultStorage == "" {
+		obj.Addons.DefaultStorage = LocalPathDefaultStorage
+	}
+	// default kubeadm patches to strategic merge patches, matching kubeadm
+	for i := range obj.KubeadmPatches {
+		if obj.KubeadmPatches[i].PatchType == "" {
+			obj.KubeadmPatches[i].PatchType = KubeadmPatchTypeStrategic
+		}
+	}
+	// default encryption at rest to encrypting secrets
+	if obj.EncryptionAtRest.Provider != "" {
+		if len(obj.EncryptionAtRest.Resources) == 0 {
+			obj.EncryptionAtRest.Resources = []string{"secrets"}
+		}
+		if obj.EncryptionAtRest.Provider == KMSEncryptionProvider && obj.EncryptionAtRest.KMSEndpoint == "" {
+			obj.EncryptionAtRest.KMSEndpoint = "unix:///var/run/kmsplugin/socket.sock"
+		}
+	}
+	// default image pulls to a few retries with a linear backoff
+	if obj.Timeouts.ImagePullRetries == nil {
+		retries := int32(4)
+		obj.Timeouts.ImagePullRetries = &retries
+	}
+	if obj.Timeouts.ImagePullBackoff == "" {
+		obj.Timeouts.ImagePullBackoff = "1s"
+	}
 }
 
 // SetDefaultsNode sets uninitialized fields to their default value.
@@ -101,4 +146,38 @@ func SetDefaultsNode(obj *Node) {
 	if obj.Role == "" {
 		obj.Role = ControlPlaneRole
 	}
+
+	// default interface names to the network names
+	for i := range obj.AdditionalNetworks {
+		if obj.AdditionalNetworks[i].Interface == "" {
+			obj.AdditionalNetworks[i].Interface = obj.AdditionalNetworks[i].Name
+		}
+	}
+
+	setDefaultsNodeSimulation(&obj.Simulate)
+}
+
+// setDefaultsNodeSimulation expands the preset into the unset fields
+func setDefaultsNodeSimulation(obj *NodeSimulation) {
+	var goos, goarch string
+	var taints []string
+	switch obj.Preset {
+	case SimulationPresetWindows:
+		goos, goarch = "windows", "amd64"
+		taints = []string{"os=windows:NoSchedule"}
+	case SimulationPresetARM64:
+		goarch = "arm64"
+		taints = []string{"kubernetes.io/arch=arm64:NoSchedule"}
+	default:
+		return
+	}
+	if obj.OS == "" {
+		obj.OS = goos
+	}
+	if obj.Arch == "" {
+		obj.Arch = goarch
+	}
+	if obj.Taints == nil {
+		obj.Taints = taints
+	}
 }
@@ -19,6 +19,7 @@ package encoding
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 
 	yaml "gopkg.in/yaml.v3"
 
@@ -47,7 +48,17 @@ func Load(path string) (*config.Cluster, error) {
 		return nil, errors.Wrap(err, "error reading file")
 	}
 
-	return Parse(raw)
+	cfg, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	// kubeadm patch files are relative to the config file
+	for i := range cfg.KubeadmPatches {
+		if p := cfg.KubeadmPatches[i].Path; p != "" && !filepath.IsAbs(p) {
+			cfg.KubeadmPatches[i].Path = filepath.Join(filepath.Dir(path), p)
+		}
+	}
+	return cfg, nil
 }
 
 // Parse parses a cluster config from raw (yaml) bytes
@@ -17,7 +17,10 @@ limitations under the License.
 package encoding
 
 import (
+	"path/filepath"
 	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
 )
 
 func TestLoadCurrent(t *testing.T) {
@@ -67,11 +70,26 @@ func TestLoadCurrent(t *testing.T) {
 			Path:        "./testdata/v1alpha4/valid-kind-workers-patches.yaml",
 			ExpectError: false,
 		},
+		{
+			TestName:    "v1alpha4 config with kubeadm component patches",
+			Path:        "./testdata/v1alpha4/valid-kubeadm-patches.yaml",
+			ExpectError: false,
+		},
 		{
 			TestName:    "v1alpha4 config with port mapping and mount",
 			Path:        "./testdata/v1alpha4/valid-port-and-mount.yaml",
 			ExpectError: false,
 		},
+		{
+			TestName:    "v1alpha4 config with selinux relabel mounts",
+			Path:        "./testdata/v1alpha4/valid-selinux-relabel.yaml",
+			ExpectError: false,
+		},
+		{
+			TestName:    "v1alpha4 invalid selinux relabel",
+			Path:        "./testdata/v1alpha4/invalid-selinux-relabel.yaml",
+			ExpectError: true,
+		},
 		{
 			TestName:    "v1alpha4 non-existent field",
 			Path:        "./testdata/v1alpha4/invalid-bogus-field.yaml",
@@ -123,3 +141,22 @@ func TestLoadCurrent(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadKubeadmPatchPaths(t *testing.T) {
+	t.Parallel()
+	cfg, err := Load("./testdata/v1alpha4/valid-kubeadm-patches.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error while Loading config: %v", err)
+	}
+	expected := []string{
+		"",
+		"",
+		filepath.Join("testdata", "v1alpha4", "patches", "kube-scheduler.yaml"),
+		"/etc/kind/patches/kube-controller-manager.yaml",
+	}
+	actual := []string{}
+	for _, p := range cfg.KubeadmPatches {
+		actual = append(actual, p.Path)
+	}
+	assert.DeepEqual(t, expected, actual)
+}
@@ -39,6 +39,27 @@ type Cluster struct {
 	// Networking contains cluster wide network settings
 	Networking Networking
 
+	// Addons contains settings for optional addons installed by kind
+	Addons Addons
+
+	// Certificates contains settings for the cluster CA and certificates
+	Certificates Certificates
+
+	// EncryptionAtRest configures the API server to encrypt resources such
+	// as secrets before storing them in etcd
+	EncryptionAtRest EncryptionAtRest
+
+	// PodSecurity configures the cluster-wide defaults of the PodSecurity
+	// admission controller
+	PodSecurity PodSecurity
+
+	// Timeouts contains timeouts and retries for the phases of cluster creation
+	Timeouts Timeouts
+
+	// ReadyConditions are additional conditions to wait for before cluster
+	// creation completes, in the format accepted by `kind wait --for`
+	ReadyConditions []string
+
 	// FeatureGates contains a map of Kubernetes feature gates to whether they
 	// are enabled. The feature gates specified here are passed to all Kubernetes components as flags or in config.
 	//
@@ -50,6 +71,9 @@ type Cluster struct {
 	// Use this to enable alpha APIs.
 	RuntimeConfig map[string]string
 
+	// SwapBehavior configures how the kubelet lets workloads use swap
+	SwapBehavior SwapBehavior
+
 	// KubeadmConfigPatches are applied to the generated kubeadm config as
 	// strategic merge patches to `kustomize build` internally
 	// https://github.com/kubernetes/community/blob/a9cf5c8f3380bb52ebe57b1e2dbdec136d8dd484/contributors/devel/sig-api-machinery/strategic-merge-patch.md
@@ -60,6 +84,16 @@ type Cluster struct {
 	// as patchesJson6902 to `kustomize build`
 	KubeadmConfigPatchesJSON6902 []PatchJSON6902
 
+	// KubeadmPatches are per-component patches written to every node and
+	// passed to kubeadm via its patches directory support.
+	KubeadmPatches []KubeadmPatch
+
+	// ComponentImages overrides the images of the control plane components
+	ComponentImages ComponentImages
+
+	// KubeadmInitSkipPhases lists additional `kubeadm init` phases to skip
+	KubeadmInitSkipPhases []string
+
 	// ContainerdConfigPatches are applied to every node's containerd config
 	// in the order listed.
 	// These should be toml stringsto be applied as merge patches
@@ -69,6 +103,41 @@ type Cluster struct {
 	// in the order listed.
 	// These should be YAML or JSON formatting RFC 6902 JSON patches
 	ContainerdConfigPatchesJSON6902 []string
+
+	// InsecureRegistries lists registries as "host:port" that every node may
+	// use over plain HTTP, or HTTPS without verifying the certificate
+	InsecureRegistries []string
+
+	// NeverPullRegistries lists registries as "host[:port]" that the nodes
+	// never pull from, images from these must be preloaded
+	NeverPullRegistries []string
+
+	// RecordImagePulls records every image the nodes' container runtime is
+	// asked to pull
+	RecordImagePulls bool
+
+	// Diagnostics configures capturing diagnostics on the nodes
+	Diagnostics Diagnostics
+
+	// HollowNodes adds kubemark hollow nodes to the cluster
+	HollowNodes HollowNodes
+}
+
+// HollowNodes configures kubemark hollow nodes
+type HollowNodes struct {
+	// Count is the number of hollow nodes to create
+	Count int32
+	// Image is the kubemark image the hollow nodes run
+	Image string
+}
+
+// Diagnostics configures capturing diagnostics on the nodes into
+// /var/log/kind-diagnostics
+type Diagnostics struct {
+	// OOMEvents records the kernel OOM killer's messages on each node
+	OOMEvents bool
+	// CoreDumps enables core dumps for the kubelet and containerd
+	CoreDumps bool
 }
 
 // Node contains settings for a node in the `kind` Cluster.
@@ -81,6 +150,10 @@ type Node struct {
 	// Defaults to "control-plane"
 	Role NodeRole
 
+	// Name overrides the name the provider would otherwise generate for the
+	// node, this is only set for nodes added to an existing cluster
+	Name string
+
 	// Image is the node image to use when creating this node
 	// If unset a default image will be used, see defaults.Image
 	Image string
@@ -98,6 +171,10 @@ type Node struct {
 	// binded to a host Port
 	ExtraPortMappings []PortMapping
 
+	// AdditionalNetworks attaches the node to some of the cluster's
+	// networking.additionalNetworks, each as a secondary interface
+	AdditionalNetworks []NodeNetwork
+
 	// KubeadmConfigPatches are applied to the generated kubeadm config as
 	// strategic merge patches to `kustomize build` internally
 	// https://github.com/kubernetes/community/blob/a9cf5c8f3380bb52ebe57b1e2dbdec136d8dd484/contributors/devel/sig-api-machinery/strategic-merge-patch.md
@@ -107,6 +184,153 @@ type Node struct {
 	// KubeadmConfigPatchesJSON6902 are applied to the generated kubeadm config
 	// as patchesJson6902 to `kustomize build`
 	KubeadmConfigPatchesJSON6902 []PatchJSON6902
+
+	// SystemdUnits are extra systemd units installed on the node before
+	// kubeadm runs, e.g. for a node-local cache or a logging agent
+	SystemdUnits []SystemdUnit
+
+	// StaticPods are extra static pod manifests installed on the node before
+	// kubeadm runs, e.g. for node agents or control plane sidecars that must
+	// be running before the API server is
+	StaticPods []StaticPod
+
+	// Env are environment variables set for the node container, and for the
+	// kubelet on the node
+	Env map[string]string
+
+	// Sysctls are namespaced sysctls set on the node container
+	Sysctls map[string]string
+
+	// Memory is the memory limit of the node container
+	Memory string
+
+	// MemorySwap is the limit of memory plus swap of the node container
+	MemorySwap string
+
+	// KubeReserved and SystemReserved are the node's kubelet resource
+	// reservations
+	KubeReserved   map[string]string
+	SystemReserved map[string]string
+
+	// EvictionHard are the node's kubelet hard eviction thresholds
+	EvictionHard map[string]string
+
+	// SandboxImage overrides the container runtime's sandbox image
+	SandboxImage string
+
+	// ContainerdSnapshotter is the snapshotter containerd uses on this node
+	ContainerdSnapshotter ContainerdSnapshotter
+
+	// ImageCache keeps the node's /var/lib/containerd in a named volume
+	// that outlives the cluster
+	ImageCache bool
+
+	// Logging configures the logging of the node's components
+	Logging NodeLogging
+
+	// Nesting configures this node for creating kind clusters inside of it
+	Nesting Nesting
+
+	// Simulate presents this node as a different operating system or
+	// architecture to the scheduler
+	Simulate NodeSimulation
+}
+
+// NodeSimulation configures the platform a node presents itself as
+type NodeSimulation struct {
+	// Preset is a well known platform, expanded into the other fields
+	// by SetDefaultsNode
+	Preset SimulationPreset
+	// OS is reported in the node's kubernetes.io/os label
+	OS string
+	// Arch is reported in the node's kubernetes.io/arch label
+	Arch string
+	// Taints are registered with the node, in the form key[=value]:effect
+	Taints []string
+}
+
+// SimulationPreset is a well known platform for NodeSimulation
+type SimulationPreset string
+
+const (
+	// SimulationPresetWindows presents the node as a windows/amd64 node
+	SimulationPresetWindows SimulationPreset = "windows"
+	// SimulationPresetARM64 presents the node as a linux/arm64 node
+	SimulationPresetARM64 SimulationPreset = "arm64"
+)
+
+// Nesting configures a node for running nested kind clusters
+type Nesting struct {
+	// DevMount bind mounts the host's /dev into the node
+	DevMount bool
+	// CgroupNS is the cgroup namespace mode of the node container
+	CgroupNS CgroupNSMode
+}
+
+// NodeLogging configures the logging of a node's components, unset fields
+// keep the components' defaults
+type NodeLogging struct {
+	// KubeletVerbosity is the kubelet's log verbosity (--v)
+	KubeletVerbosity *int32
+	// ContainerdLogLevel is containerd's log level (--log-level)
+	ContainerdLogLevel string
+	// JournalRateLimitInterval and JournalRateLimitBurst are the rate limit
+	// of the node's journal
+	JournalRateLimitInterval string
+	JournalRateLimitBurst    *int32
+}
+
+// ContainerdSnapshotter is a containerd snapshotter supported by the node image
+type ContainerdSnapshotter string
+
+const (
+	// OverlayfsSnapshotter uses the kernel's overlayfs
+	OverlayfsSnapshotter ContainerdSnapshotter = "overlayfs"
+	// FuseOverlayfsSnapshotter uses overlayfs in userspace
+	FuseOverlayfsSnapshotter ContainerdSnapshotter = "fuse-overlayfs"
+	// NativeSnapshotter copies each layer, and works on any filesystem
+	NativeSnapshotter ContainerdSnapshotter = "native"
+	// StargzSnapshotter lazily pulls eStargz images
+	StargzSnapshotter ContainerdSnapshotter = "stargz"
+)
+
+// SwapBehavior is the kubelet's swap behavior
+type SwapBehavior string
+
+const (
+	// NoSwapBehavior does not let workloads use swap
+	NoSwapBehavior SwapBehavior = "NoSwap"
+	// LimitedSwapBehavior lets Burstable pods use swap in proportion to
+	// their memory requests
+	LimitedSwapBehavior SwapBehavior = "LimitedSwap"
+)
+
+// CgroupNSMode is the cgroup namespace mode of a node container
+type CgroupNSMode string
+
+const (
+	// CgroupNSPrivate runs the node in its own cgroup namespace
+	CgroupNSPrivate CgroupNSMode = "private"
+	// CgroupNSHost runs the node in the host's cgroup namespace
+	CgroupNSHost CgroupNSMode = "host"
+)
+
+// StaticPod is a static pod manifest installed on a node
+type StaticPod struct {
+	// Name is the manifest file name without the ".yaml" suffix
+	Name string
+	// Manifest is the Pod manifest
+	Manifest string
+}
+
+// SystemdUnit is a systemd unit installed on a node
+type SystemdUnit struct {
+	// Name is the unit file name including its type suffix, e.g. "ntp.service"
+	Name string
+	// Content is the content of the unit file
+	Content string
+	// Enabled enables and starts the unit once it is installed
+	Enabled bool
 }
 
 // NodeRole defines possible role for nodes in a Kubernetes cluster managed by `kind`
@@ -122,6 +346,159 @@ const (
 	WorkerRole NodeRole = "worker"
 )
 
+// Addons contains settings for optional addons installed by kind
+type Addons struct {
+	// MetricsServer installs a metrics-server configured for kind after the
+	// CNI, so that `kubectl top` and the HorizontalPodAutoscaler work.
+	// The metrics-server image is pulled when the cluster is created.
+	//
+	// Defaults to false
+	MetricsServer bool
+
+	// DefaultStorage selects the default storage provisioner installed by kind
+	DefaultStorage DefaultStorage
+
+	// SharedStorage configures an additional ReadWriteMany StorageClass
+	SharedStorage SharedStorage
+
+	// LoadBalancerPool reserves node network addresses for LoadBalancer
+	// services
+	LoadBalancerPool LoadBalancerPool
+}
+
+// LoadBalancerPool reserves addresses on the node network for LoadBalancer
+// services
+type LoadBalancerPool struct {
+	// Size is the number of addresses to reserve for each IP family,
+	// no addresses are reserved when unset
+	Size int32
+	// MetalLB installs MetalLB in L2 mode with the reserved addresses
+	MetalLB bool
+}
+
+// SharedStorage configures storage shared between all nodes
+type SharedStorage struct {
+	// HostPath is a directory on the host mounted into every node,
+	// shared storage is disabled when unset
+	HostPath string
+}
+
+// DefaultStorage selects a default storage provisioner
+type DefaultStorage string
+
+const (
+	// NoneDefaultStorage installs no storage provisioner or default StorageClass
+	NoneDefaultStorage DefaultStorage = "none"
+	// LocalPathDefaultStorage installs the local-path-provisioner shipped in
+	// the node image
+	LocalPathDefaultStorage DefaultStorage = "local-path"
+	// CSIHostPathDefaultStorage installs the CSI hostpath driver, which also
+	// supports volume snapshots
+	CSIHostPathDefaultStorage DefaultStorage = "csi-hostpath"
+)
+
+// ComponentImages contains images replacing those of the control plane
+// components, unset fields keep the images in the node image
+type ComponentImages struct {
+	KubeAPIServer         string
+	KubeControllerManager string
+	KubeScheduler         string
+}
+
+// Certificates contains settings for the cluster CA and certificates
+type Certificates struct {
+	// CACertFile and CAKeyFile are paths on the host to a CA certificate and
+	// key to use as the cluster CA, instead of kubeadm generating a new one
+	CACertFile string
+	CAKeyFile  string
+	// ExportCADir is a directory on the host the cluster CA certificate and
+	// key are written to after the control plane is created
+	ExportCADir string
+	// CertificateValidityPeriod is the validity of the certificates kubeadm
+	// signs with the cluster CA, requires Kubernetes v1.31+
+	CertificateValidityPeriod string
+	// CACertificateValidityPeriod is the validity of the CA certificates
+	// kubeadm generates, requires Kubernetes v1.31+
+	CACertificateValidityPeriod string
+}
+
+// EncryptionAtRest configures encryption at rest of API resources with a
+// generated EncryptionConfiguration
+type EncryptionAtRest struct {
+	// Provider is the encryption provider, unset disables encryption at rest
+	Provider EncryptionProvider
+	// Resources are the resources to encrypt
+	Resources []string
+	// KMSEndpoint is the endpoint of the KMS v2 plugin for the kms provider
+	KMSEndpoint string
+}
+
+// EncryptionProvider is an encryption at rest provider
+type EncryptionProvider string
+
+const (
+	// AESCBCEncryptionProvider encrypts with AES-CBC and a generated key
+	AESCBCEncryptionProvider EncryptionProvider = "aescbc"
+	// AESGCMEncryptionProvider encrypts with AES-GCM and a generated key
+	AESGCMEncryptionProvider EncryptionProvider = "aesgcm"
+	// SecretboxEncryptionProvider encrypts with XSalsa20 and Poly1305 and a
+	// generated key
+	SecretboxEncryptionProvider EncryptionProvider = "secretbox"
+	// KMSEncryptionProvider encrypts with a KMS v2 plugin
+	KMSEncryptionProvider EncryptionProvider = "kms"
+)
+
+// PodSecurity configures the cluster-wide defaults of the PodSecurity
+// admission controller with a generated AdmissionConfiguration
+type PodSecurity struct {
+	// Enforce, Audit and Warn are the default levels, unset levels are
+	// privileged
+	Enforce PodSecurityLevel
+	Audit   PodSecurityLevel
+	Warn    PodSecurityLevel
+	// Exemptions are exempt from all of the levels
+	Exemptions PodSecurityExemptions
+}
+
+// PodSecurityLevel is a Pod Security Standards level
+type PodSecurityLevel string
+
+const (
+	// PrivilegedPodSecurityLevel is unrestricted
+	PrivilegedPodSecurityLevel PodSecurityLevel = "privileged"
+	// BaselinePodSecurityLevel prevents known privilege escalations
+	BaselinePodSecurityLevel PodSecurityLevel = "baseline"
+	// RestrictedPodSecurityLevel follows pod hardening best practices
+	RestrictedPodSecurityLevel PodSecurityLevel = "restricted"
+)
+
+// PodSecurityExemptions are exempt from the PodSecurity admission controller
+type PodSecurityExemptions struct {
+	Usernames      []string
+	RuntimeClasses []string
+	Namespaces     []string
+}
+
+// Timeouts contains timeouts and retries for the phases of cluster creation,
+// see TimeoutDuration
+type Timeouts struct {
+	// ImagePull is the timeout for each attempt at pulling a node image
+	ImagePull string
+	// ImagePullRetries is how many times a failed node image pull is retried
+	ImagePullRetries *int32
+	// ImagePullBackoff is the delay before retrying a failed node image pull,
+	// each further retry waits this much longer than the last
+	ImagePullBackoff string
+	// ControlPlaneInit is the timeout for `kubeadm init`
+	ControlPlaneInit string
+	// Join is the timeout for `kubeadm join` on each of the other nodes
+	Join string
+	// CNIReady is how long to wait for the default CNI to be ready
+	CNIReady string
+	// NodesReady is how long to wait for the control plane nodes to be Ready
+	NodesReady string
+}
+
 // Networking contains cluster wide network settings
 type Networking struct {
 	// IPFamily is the network cluster model, currently it can be ipv4 or ipv6
@@ -134,24 +511,113 @@ type Networking struct {
 	// This is potentially useful for remote hosts, BUT it means when the container
 	// is restarted it will be randomized. Leave this unset to allow kind to pick it.
 	APIServerPort int32
+	// APIServerPortRange restricts the host port kind picks for the
+	// Kubernetes API Server when APIServerPort is unset to a range such as
+	// "30000-30100", e.g. for hosts whose firewall only allows some ports.
+	// It cannot be used together with APIServerPort.
+	APIServerPortRange string
 	// APIServerAddress is the listen address on the host for the Kubernetes
 	// API Server. This should be an IP address.
 	//
 	// Defaults to 127.0.0.1
 	APIServerAddress string
+	// APIServerAdditionalAddresses are additional listen addresses on the host
+	// for the Kubernetes API Server, e.g. a LAN IP in addition to 127.0.0.1.
+	// These should be IP addresses, and they are added to the API Server
+	// certificate SANs unless they are unspecified addresses (0.0.0.0 / ::).
+	//
+	// The API Server is published on the same port on every address, so
+	// APIServerPort must be set when using this field.
+	APIServerAdditionalAddresses []string
+	// APIServerCertSANs are additional Subject Alternative Names (IP addresses
+	// or DNS names) for the Kubernetes API Server serving certificate
+	APIServerCertSANs []string
 	// PodSubnet is the CIDR used for pod IPs
 	// kind will select a default if unspecified
 	PodSubnet string
 	// ServiceSubnet is the CIDR used for services VIPs
 	// kind will select a default if unspecified
 	ServiceSubnet string
+	// NodeCIDRMaskSizeIPv4 is the mask size of the IPv4 pod CIDR allocated
+	// to each node from the PodSubnet, if zero the kube-controller-manager
+	// default is used
+	NodeCIDRMaskSizeIPv4 int32
+	// NodeCIDRMaskSizeIPv6 is the mask size of the IPv6 pod CIDR allocated
+	// to each node from the PodSubnet, if zero the kube-controller-manager
+	// default is used
+	NodeCIDRMaskSizeIPv6 int32
 	// If DisableDefaultCNI is true, kind will not install the default CNI setup.
 	// Instead the user should install their own CNI after creating the cluster.
 	DisableDefaultCNI bool
+	// If KeepPortmap is true with DisableDefaultCNI, kind chains the portmap
+	// CNI plugin into any CNI config on the nodes lacking it, so that
+	// hostPorts keep working
+	KeepPortmap bool
+	// If HostRoutes is true, kind adds routes on the host to each node's
+	// pod CIDRs via the node, so that pods are reachable from the host
+	HostRoutes bool
 	// KubeProxyMode defines if kube-proxy should operate in iptables, ipvs or nftables mode
 	KubeProxyMode ProxyMode
 	// DNSSearch defines the DNS search domain to use for nodes. If not set, this will be inherited from the host.
 	DNSSearch *[]string
+	// BridgedNetwork additionally attaches the nodes to a macvlan or ipvlan
+	// network on a host interface, so that they get addresses on the host's
+	// LAN. This is useful for testing external load balancers, BGP and
+	// on-prem like topologies. Only Linux hosts with rootful docker are
+	// supported.
+	BridgedNetwork *BridgedNetwork
+	// AdditionalNetworks are networks kind creates for the cluster, in
+	// addition to the network all nodes are attached to. Nodes are attached
+	// to them with their additionalNetworks, e.g. to test Multus and other
+	// multi-homing CNIs.
+	AdditionalNetworks []AdditionalNetwork
+}
+
+// BridgedNetwork configures a macvlan or ipvlan network on a host interface
+type BridgedNetwork struct {
+	// Driver is the network driver, macvlan or ipvlan
+	//
+	// Defaults to macvlan
+	Driver BridgedNetworkDriver
+	// Parent is the host interface to attach the network to, e.g. eth0
+	Parent string
+	// Subnet is the CIDR of the network on the parent interface's LAN,
+	// e.g. 192.168.1.0/24
+	Subnet string
+	// IPRange is the CIDR within Subnet to allocate node addresses from.
+	// This should not overlap any addresses assigned by the LAN's DHCP
+	// server.
+	//
+	// Defaults to all of Subnet
+	IPRange string
+	// Gateway is the LAN's gateway address within Subnet
+	//
+	// Defaults to the first address in Subnet
+	Gateway string
+}
+
+// BridgedNetworkDriver is the driver of a BridgedNetwork
+type BridgedNetworkDriver string
+
+const (
+	// MacvlanDriver gives every node its own MAC address on the LAN
+	MacvlanDriver BridgedNetworkDriver = "macvlan"
+	// IPVlanDriver shares the parent interface's MAC address, for LANs
+	// that limit the MAC addresses per port such as most wireless networks
+	IPVlanDriver BridgedNetworkDriver = "ipvlan"
+)
+
+// AdditionalNetwork is a network kind creates for the cluster, see
+// Networking.AdditionalNetworks
+type AdditionalNetwork struct {
+	// Name identifies the network in a node's additionalNetworks. This is
+	// also the default name of the node interfaces on the network, so it
+	// must be a valid interface name of at most 15 characters.
+	Name string
+	// Subnet is the network's CIDR
+	//
+	// Defaults to a subnet picked by the node backend (docker)
+	Subnet string
 }
 
 // ClusterIPFamily defines cluster network IP family
@@ -191,6 +657,50 @@ type PatchJSON6902 struct {
 	Patch string
 }
 
+// KubeadmPatch represents a single kubeadm component patch
+// Exactly one of Patch or Path must be set.
+type KubeadmPatch struct {
+	// Target is the component to patch, one of: etcd, kube-apiserver,
+	// kube-controller-manager, kube-scheduler, kubeletconfiguration
+	Target KubeadmPatchTarget
+	// PatchType is the type of patch, one of: strategic, merge, json
+	PatchType KubeadmPatchType
+	// Patch is the inline contents of the patch
+	Patch string
+	// Path is the path to a file on the host containing the patch
+	Path string
+}
+
+// KubeadmPatchTarget represents an "enum" for kubeadm patch targets,
+// see also KubeadmPatch.
+type KubeadmPatchTarget string
+
+const (
+	// KubeadmPatchTargetEtcd targets the etcd static pod manifest
+	KubeadmPatchTargetEtcd KubeadmPatchTarget = "etcd"
+	// KubeadmPatchTargetKubeAPIServer targets the kube-apiserver static pod manifest
+	KubeadmPatchTargetKubeAPIServer KubeadmPatchTarget = "kube-apiserver"
+	// KubeadmPatchTargetKubeControllerManager targets the kube-controller-manager static pod manifest
+	KubeadmPatchTargetKubeControllerManager KubeadmPatchTarget = "kube-controller-manager"
+	// KubeadmPatchTargetKubeScheduler targets the kube-scheduler static pod manifest
+	KubeadmPatchTargetKubeScheduler KubeadmPatchTarget = "kube-scheduler"
+	// KubeadmPatchTargetKubeletConfiguration targets the KubeletConfiguration
+	KubeadmPatchTargetKubeletConfiguration KubeadmPatchTarget = "kubeletconfiguration"
+)
+
+// KubeadmPatchType represents an "enum" for kubeadm patch types,
+// see also KubeadmPatch.
+type KubeadmPatchType string
+
+const (
+	// KubeadmPatchTypeStrategic specifies a strategic merge patch
+	KubeadmPatchTypeStrategic KubeadmPatchType = "strategic"
+	// KubeadmPatchTypeMerge specifies a JSON merge patch (RFC 7386)
+	KubeadmPatchTypeMerge KubeadmPatchType = "merge"
+	// KubeadmPatchTypeJSON specifies a JSON patch (RFC 6902)
+	KubeadmPatchTypeJSON KubeadmPatchType = "json"
+)
+
 // Mount specifies a host volume to mount into a container.
 // This is a close copy of the upstream cri Mount type
 // see: k8s.io/kubernetes/pkg/kubelet/apis/cri/runtime/v1alpha2
@@ -202,10 +712,13 @@ type PatchJSON6902 struct {
 //	containerPath: /foo
 //	hostPath: /bar
 //	readOnly: true
-//	selinuxRelabel: false
+//	selinuxRelabel: true
+//	selinuxRelabelMode: Private
 //	propagation: None
+//	createHostPath: true
 //
 // Propagation may be one of: None, HostToContainer, Bidirectional
+// SelinuxRelabelMode may be one of: Shared, Private
 type Mount struct {
 	// Path of the mount within the container.
 	ContainerPath string
@@ -217,8 +730,13 @@ type Mount struct {
 	Readonly bool
 	// If set, the mount needs SELinux relabeling.
 	SelinuxRelabel bool
+	// SelinuxRelabelMode selects how the mount is relabeled if SelinuxRelabel
+	// is set, see SelinuxRelabelMode. Defaults to Private.
+	SelinuxRelabelMode SelinuxRelabelMode
 	// Requested propagation mode.
 	Propagation MountPropagation
+	// CreateHostPath creates the hostPath directory if it doesn't exist
+	CreateHostPath bool
 }
 
 // PortMapping specifies a host port mapped into a container port.
@@ -263,6 +781,19 @@ const (
 	MountPropagationBidirectional MountPropagation = "Bidirectional"
 )
 
+// SelinuxRelabelMode represents an "enum" for SELinux relabeling options,
+// see also Mount.
+type SelinuxRelabelMode string
+
+const (
+	// SelinuxRelabelModeShared relabels the mount so that it can be shared
+	// with other containers ("z" in docker / podman).
+	SelinuxRelabelModeShared SelinuxRelabelMode = "Shared"
+	// SelinuxRelabelModePrivate relabels the mount so that only the node
+	// container can use it ("Z" in docker / podman).
+	SelinuxRelabelModePrivate SelinuxRelabelMode = "Private"
+)
+
 // PortMappingProtocol represents an "enum" for port mapping protocol options,
 // see also PortMapping.
 type PortMappingProtocol string
@@ -275,3 +806,13 @@ const (
 	// PortMappingProtocolSCTP specifies SCTP protocol
 	PortMappingProtocolSCTP PortMappingProtocol = "SCTP"
 )
+
+// NodeNetwork attaches a node to one of the cluster's AdditionalNetworks
+type NodeNetwork struct {
+	// Name is the name of the network in networking.additionalNetworks
+	Name string
+	// Interface is the name of the node's interface on the network
+	//
+	// Defaults to the network's name
+	Interface string
+}
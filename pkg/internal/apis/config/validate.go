@@ -19,8 +19,13 @@ package config
 import (
 	"fmt"
 	"net"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/internal/sets"
@@ -32,6 +37,115 @@ import (
 // https://godoc.org/github.com/docker/docker/daemon/names#pkg-constants
 var validNameRE = regexp.MustCompile(`^[a-z0-9.-]+$`)
 
+// validDNSNameRE matches a DNS subdomain (RFC 1123), optionally with a
+// leading wildcard label, as allowed in certificate SANs
+var validDNSNameRE = regexp.MustCompile(`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// validSystemdUnitNameRE matches the systemd unit file names kind can install
+var validSystemdUnitNameRE = regexp.MustCompile(`^[a-zA-Z0-9:_.@\-]+\.(service|socket|timer|path|mount|target)$`)
+
+// nodeImageSystemdUnits are the systemd units the node image ships, which
+// must not be replaced
+var nodeImageSystemdUnits = sets.NewString(
+	"containerd.service", "containerd-fuse-overlayfs.service", "containerd-stargz.service",
+	"kubelet.service", "undo-mount-hacks.service",
+)
+
+// validStaticPodNameRE matches the static pod manifest names kind can
+// install, these are used as file names
+var validStaticPodNameRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// kubeadmStaticPods are the static pod manifests kubeadm writes on control
+// plane nodes, which must not be replaced
+var kubeadmStaticPods = sets.NewString("etcd", "kube-apiserver", "kube-controller-manager", "kube-scheduler")
+
+// validNetworkNameRE matches additional network names, these are also the
+// default interface names so they are limited to 15 characters
+var validNetworkNameRE = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,13}[a-z0-9])?$`)
+
+// validInterfaceNameRE matches the network interface names Linux allows
+var validInterfaceNameRE = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,15}$`)
+
+// validEnvNameRE matches the environment variable names that may be set on
+// a node, these are also written to a systemd unit
+var validEnvNameRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// validMemoryRE matches the memory sizes accepted by docker and podman, a
+// number of bytes with an optional b, k, m or g unit suffix
+var validMemoryRE = regexp.MustCompile(`^[0-9]+[bkmgBKMG]?$`)
+
+// validLabelValueRE matches kubernetes label values, for the simulated
+// node OS and architecture
+var validLabelValueRE = regexp.MustCompile(`^([a-zA-Z0-9]([-a-zA-Z0-9_.]{0,61}[a-zA-Z0-9])?)?$`)
+
+// validTaintRE matches taints in the kubelet's key[=value]:effect form
+var validTaintRE = regexp.MustCompile(`^([a-zA-Z0-9.-]+/)?[a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?(=([a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?)?)?:(NoSchedule|PreferNoSchedule|NoExecute)$`)
+
+// reservedResources are the resources the kubelet can reserve
+var reservedResources = map[string]bool{
+	"cpu":               true,
+	"memory":            true,
+	"ephemeral-storage": true,
+	"pid":               true,
+}
+
+// evictionSignals are the kubelet's hard eviction signals
+var evictionSignals = map[string]bool{
+	"memory.available":       true,
+	"nodefs.available":       true,
+	"nodefs.inodesFree":      true,
+	"imagefs.available":      true,
+	"imagefs.inodesFree":     true,
+	"containerfs.available":  true,
+	"containerfs.inodesFree": true,
+	"pid.available":          true,
+}
+
+// validKubeletQuantityRE matches values for the kubelet's reservations and
+// eviction thresholds, which are passed as comma separated flags
+var validKubeletQuantityRE = regexp.MustCompile(`^[0-9.]+([a-zA-Z]{0,2}|%)$`)
+
+// namespacedSysctls are the sysctls outside of namespacedSysctlPrefixes that
+// are namespaced, and therefore may be set per container.
+// This matches the validation in docker and podman.
+var namespacedSysctls = sets.NewString(
+	"kernel.msgmax",
+	"kernel.msgmnb",
+	"kernel.msgmni",
+	"kernel.sem",
+	"kernel.shmall",
+	"kernel.shmmax",
+	"kernel.shmmni",
+	"kernel.shm_rmid_forced",
+)
+
+// namespacedSysctlPrefixes are the prefixes of namespaced sysctls
+var namespacedSysctlPrefixes = []string{"fs.mqueue.", "net."}
+
+// validKubeadmInitPhases are the `kubeadm init` phases that may be skipped
+// https://kubernetes.io/docs/reference/setup-tools/kubeadm/kubeadm-init-phase/
+var validKubeadmInitPhases = sets.NewString(
+	"preflight",
+	"certs", "certs/all", "certs/ca", "certs/apiserver", "certs/apiserver-kubelet-client",
+	"certs/front-proxy-ca", "certs/front-proxy-client", "certs/etcd-ca", "certs/etcd-server",
+	"certs/etcd-peer", "certs/etcd-healthcheck-client", "certs/apiserver-etcd-client", "certs/sa",
+	"kubeconfig", "kubeconfig/all", "kubeconfig/admin", "kubeconfig/super-admin", "kubeconfig/kubelet",
+	"kubeconfig/controller-manager", "kubeconfig/scheduler",
+	"etcd", "etcd/local",
+	"control-plane", "control-plane/all", "control-plane/apiserver",
+	"control-plane/controller-manager", "control-plane/scheduler",
+	"kubelet-start",
+	"wait-control-plane",
+	"upload-config", "upload-config/all", "upload-config/kubeadm", "upload-config/kubelet",
+	"upload-certs",
+	"mark-control-plane",
+	"bootstrap-token",
+	"kubelet-finalize", "kubelet-finalize/all", "kubelet-finalize/enable-client-cert-rotation",
+	"kubelet-finalize/experimental-cert-rotation",
+	"addon", "addon/all", "addon/coredns", "addon/kube-proxy",
+	"show-join-command",
+)
+
 // Validate returns a ConfigErrors with an entry for each problem
 // with the config, or nil if there are none
 func (c *Cluster) Validate() error {
@@ -52,19 +166,69 @@ func (c *Cluster) Validate() error {
 		}
 	}
 
+	// the api server port range only applies to the port kind picks
+	if c.Networking.APIServerPortRange != "" {
+		if _, _, err := ParsePortRange(c.Networking.APIServerPortRange); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid apiServerPortRange"))
+		} else if c.Networking.APIServerPort != 0 {
+			errs = append(errs, errors.New("apiServerPortRange cannot be used with apiServerPort"))
+		}
+	}
+
+	// the default CNI already chains portmap
+	if c.Networking.KeepPortmap && !c.Networking.DisableDefaultCNI {
+		errs = append(errs, errors.New("keepPortmap requires disableDefaultCNI"))
+	}
+
+	// additional api server addresses share the api server port
+	if err := validateAPIServerAdditionalAddresses(c.Networking.APIServerAdditionalAddresses, c.Networking.APIServerAddress, c.Networking.APIServerPort); err != nil {
+		errs = append(errs, errors.Wrapf(err, "invalid apiServerAdditionalAddresses"))
+	}
+
+	// api server cert SANs should be IPs or DNS names
+	for _, san := range c.Networking.APIServerCertSANs {
+		if net.ParseIP(san) == nil && (len(san) > 253 || !validDNSNameRE.MatchString(san)) {
+			errs = append(errs, errors.Errorf("invalid apiServerCertSANs entry %q: must be an IP address or a DNS name", san))
+		}
+	}
+
 	// ipFamily should be ipv4, ipv6, or dual
 	if c.Networking.IPFamily != IPv4Family && c.Networking.IPFamily != IPv6Family && c.Networking.IPFamily != DualStackFamily {
 		errs = append(errs, errors.Errorf("invalid ipFamily: %s", c.Networking.IPFamily))
 	}
 
 	// podSubnet should be a valid CIDR
+	numNodes := len(c.Nodes)
+	if c.HollowNodes.Count > 0 {
+		numNodes += int(c.HollowNodes.Count)
+	}
 	if err := validateSubnets(c.Networking.PodSubnet, c.Networking.IPFamily); err != nil {
 		errs = append(errs, errors.Errorf("invalid pod subnet %v", err))
+	} else if err := validatePodSubnetSizing(c.Networking.PodSubnet, c.Networking.NodeCIDRMaskSizeIPv4, c.Networking.NodeCIDRMaskSizeIPv6, numNodes); err != nil {
+		// podSubnet should have room for a node CIDR for every node,
+		// including hollow nodes
+		errs = append(errs, errors.Errorf("invalid pod subnet %v", err))
 	}
 
 	// serviceSubnet should be a valid CIDR
 	if err := validateSubnets(c.Networking.ServiceSubnet, c.Networking.IPFamily); err != nil {
 		errs = append(errs, errors.Errorf("invalid service subnet %v", err))
+	} else if err := validateServiceSubnetSizing(c.Networking.ServiceSubnet); err != nil {
+		// serviceSubnet should not be larger than the API server allows
+		errs = append(errs, errors.Errorf("invalid service subnet %v", err))
+	}
+
+	// dual-stack subnets must agree on the primary family, these are
+	// ordered when defaulting so this is only reached by API users
+	if c.Networking.IPFamily == DualStackFamily {
+		podSubnets := strings.Split(c.Networking.PodSubnet, ",")
+		serviceSubnets := strings.Split(c.Networking.ServiceSubnet, ",")
+		if len(podSubnets) == 2 && len(serviceSubnets) == 2 {
+			podFamily, serviceFamily := subnetFamily(podSubnets[0]), subnetFamily(serviceSubnets[0])
+			if podFamily != "" && serviceFamily != "" && podFamily != serviceFamily {
+				errs = append(errs, errors.Errorf("dual-stack pod subnet %q and service subnet %q must list the same IP family first", c.Networking.PodSubnet, c.Networking.ServiceSubnet))
+			}
+		}
 	}
 
 	// KubeProxyMode should be iptables or ipvs
@@ -73,6 +237,129 @@ func (c *Cluster) Validate() error {
 		errs = append(errs, errors.Errorf("invalid kubeProxyMode: %s", c.Networking.KubeProxyMode))
 	}
 
+	// the bridged network needs a host interface and the LAN's subnet
+	if c.Networking.BridgedNetwork != nil {
+		if err := validateBridgedNetwork(c.Networking.BridgedNetwork); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid bridgedNetwork"))
+		}
+	}
+
+	// additional networks must have unique names for nodes to refer to
+	networkNames := sets.NewString()
+	for _, n := range c.Networking.AdditionalNetworks {
+		if !validNetworkNameRE.MatchString(n.Name) {
+			errs = append(errs, errors.Errorf("%q is not a valid additionalNetworks name, names must match `%s`", n.Name, validNetworkNameRE.String()))
+		} else if networkNames.Has(n.Name) {
+			errs = append(errs, errors.Errorf("duplicate additionalNetworks name %q", n.Name))
+		}
+		networkNames.Insert(n.Name)
+		if n.Subnet != "" {
+			if _, _, err := net.ParseCIDR(n.Subnet); err != nil {
+				errs = append(errs, errors.Errorf("invalid additionalNetworks %q subnet %q: must be a CIDR", n.Name, n.Subnet))
+			}
+		}
+	}
+
+	// SwapBehavior should be unset or one of those the kubelet supports
+	switch c.SwapBehavior {
+	case "", NoSwapBehavior, LimitedSwapBehavior:
+	default:
+		errs = append(errs, errors.Errorf("invalid swapBehavior: %s", c.SwapBehavior))
+	}
+
+	// DefaultStorage should be one of the provisioners kind can install
+	switch c.Addons.DefaultStorage {
+	case NoneDefaultStorage, LocalPathDefaultStorage, CSIHostPathDefaultStorage:
+	default:
+		errs = append(errs, errors.Errorf("invalid addons.defaultStorage: %s", c.Addons.DefaultStorage))
+	}
+
+	// the load balancer pool needs addresses for MetalLB to assign
+	if c.Addons.LoadBalancerPool.Size < 0 {
+		errs = append(errs, errors.Errorf("invalid addons.loadBalancerPool.size: %d", c.Addons.LoadBalancerPool.Size))
+	}
+	if c.Addons.LoadBalancerPool.MetalLB && c.Addons.LoadBalancerPool.Size == 0 {
+		errs = append(errs, errors.New("addons.loadBalancerPool.metalLB requires addons.loadBalancerPool.size"))
+	}
+
+	// hollow nodes need a kubemark image to run
+	if c.HollowNodes.Count < 0 {
+		errs = append(errs, errors.Errorf("invalid hollowNodes.count: %d", c.HollowNodes.Count))
+	}
+	if c.HollowNodes.Count > 0 && c.HollowNodes.Image == "" {
+		errs = append(errs, errors.New("hollowNodes.count requires hollowNodes.image"))
+	}
+
+	// validate certificates settings
+	if err := c.Certificates.Validate(); err != nil {
+		errs = append(errs, errors.Wrapf(err, "invalid certificates"))
+	}
+
+	// validate encryption at rest settings
+	if err := c.EncryptionAtRest.Validate(); err != nil {
+		errs = append(errs, errors.Wrapf(err, "invalid encryptionAtRest"))
+	}
+
+	// validate pod security admission defaults
+	if err := c.PodSecurity.Validate(); err != nil {
+		errs = append(errs, errors.Wrapf(err, "invalid podSecurity"))
+	}
+
+	// validate timeouts
+	if err := c.Timeouts.Validate(); err != nil {
+		errs = append(errs, errors.Wrapf(err, "invalid timeouts"))
+	}
+
+	// validate ready conditions, these are parsed when creating the cluster
+	for i, condition := range c.ReadyConditions {
+		if strings.TrimSpace(condition) == "" {
+			errs = append(errs, errors.Errorf("invalid readyConditions entry %d: must not be empty", i))
+		}
+	}
+
+	// validate insecure registries, these are used as paths on the nodes
+	for _, registry := range c.InsecureRegistries {
+		if err := validateRegistryHost(registry); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid insecureRegistries entry %q", registry))
+		}
+	}
+
+	// validate never pull registries, these also configure the registry
+	// hosts so a registry may not be insecure too
+	insecureRegistries := sets.NewString(c.InsecureRegistries...)
+	for _, registry := range c.NeverPullRegistries {
+		if err := validateRegistryHost(registry); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid neverPullRegistries entry %q", registry))
+		} else if insecureRegistries.Has(registry) {
+			errs = append(errs, errors.Errorf("registry %q cannot be in both insecureRegistries and neverPullRegistries", registry))
+		}
+	}
+
+	// validate kubeadm patches
+	for i, p := range c.KubeadmPatches {
+		if err := p.Validate(); err != nil {
+			errs = append(errs, errors.Errorf("invalid kubeadmPatches entry %d: %v", i, err))
+		}
+	}
+
+	// validate component images, these are written to the static pod manifests
+	for _, image := range []string{
+		c.ComponentImages.KubeAPIServer,
+		c.ComponentImages.KubeControllerManager,
+		c.ComponentImages.KubeScheduler,
+	} {
+		if strings.ContainsAny(image, " \t\n\"\\") {
+			errs = append(errs, errors.Errorf("%q is not a valid component image", image))
+		}
+	}
+
+	// validate kubeadm init phases to skip
+	for _, phase := range c.KubeadmInitSkipPhases {
+		if !validKubeadmInitPhases.Has(phase) {
+			errs = append(errs, errors.Errorf("%q is not a valid kubeadm init phase to skip", phase))
+		}
+	}
+
 	// validate nodes
 	numByRole := make(map[NodeRole]int32)
 	// All nodes in the config should be valid
@@ -81,6 +368,12 @@ func (c *Cluster) Validate() error {
 		if err := n.Validate(); err != nil {
 			errs = append(errs, errors.Errorf("invalid configuration for node %d: %v", i, err))
 		}
+		// nodes can only be attached to the cluster's additional networks
+		for _, network := range n.AdditionalNetworks {
+			if !networkNames.Has(network.Name) {
+				errs = append(errs, errors.Errorf("invalid configuration for node %d: network %q is not in networking.additionalNetworks", i, network.Name))
+			}
+		}
 		// update role count
 		if num, ok := numByRole[n.Role]; ok {
 			numByRole[n.Role] = 1 + num
@@ -134,10 +427,354 @@ func (n *Node) Validate() error {
 		errs = append(errs, errors.Wrapf(err, "invalid portMapping"))
 	}
 
+	// validate extra mounts
+	errs = append(errs, validateMounts(n.ExtraMounts)...)
+
+	// validate systemd units, names must be unique per node
+	unitNames := sets.NewString()
+	for _, unit := range n.SystemdUnits {
+		if !validSystemdUnitNameRE.MatchString(unit.Name) {
+			errs = append(errs, errors.Errorf("%q is not a valid systemd unit name", unit.Name))
+		} else if nodeImageSystemdUnits.Has(unit.Name) {
+			errs = append(errs, errors.Errorf("systemd unit %q would replace the one the node image ships", unit.Name))
+		} else if unitNames.Has(unit.Name) {
+			errs = append(errs, errors.Errorf("duplicate systemd unit %q", unit.Name))
+		}
+		unitNames.Insert(unit.Name)
+		if strings.TrimSpace(unit.Content) == "" {
+			errs = append(errs, errors.Errorf("systemd unit %q has no content", unit.Name))
+		}
+	}
+
+	// validate static pods, names must be unique per node
+	staticPodNames := sets.NewString()
+	for _, pod := range n.StaticPods {
+		if !validStaticPodNameRE.MatchString(pod.Name) {
+			errs = append(errs, errors.Errorf("%q is not a valid static pod name", pod.Name))
+		} else if kubeadmStaticPods.Has(pod.Name) {
+			errs = append(errs, errors.Errorf("static pod %q would replace the one kubeadm installs", pod.Name))
+		} else if staticPodNames.Has(pod.Name) {
+			errs = append(errs, errors.Errorf("duplicate static pod %q", pod.Name))
+		}
+		staticPodNames.Insert(pod.Name)
+		if err := validateStaticPodManifest(pod.Manifest); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid manifest for static pod %q", pod.Name))
+		}
+	}
+
+	// validate additional networks, the node's interfaces must not clash
+	nodeNetworks := sets.NewString()
+	interfaces := sets.NewString("eth0", "lo")
+	for _, network := range n.AdditionalNetworks {
+		if nodeNetworks.Has(network.Name) {
+			errs = append(errs, errors.Errorf("duplicate additionalNetworks entry %q", network.Name))
+		}
+		nodeNetworks.Insert(network.Name)
+		if !validInterfaceNameRE.MatchString(network.Interface) {
+			errs = append(errs, errors.Errorf("%q is not a valid interface name", network.Interface))
+		} else if interfaces.Has(network.Interface) {
+			errs = append(errs, errors.Errorf("duplicate interface name %q", network.Interface))
+		}
+		interfaces.Insert(network.Interface)
+	}
+
+	// validate env, the values are not restricted
+	for name := range n.Env {
+		if !validEnvNameRE.MatchString(name) {
+			errs = append(errs, errors.Errorf("%q is not a valid environment variable name", name))
+		}
+	}
+
+	// validate sysctls are namespaced, others cannot be set per node
+	for name := range n.Sysctls {
+		if !isNamespacedSysctl(name) {
+			errs = append(errs, errors.Errorf("sysctl %q is not namespaced and cannot be set per node, set it on the host instead", name))
+		}
+	}
+
+	// validate memory limits, swap is limited together with memory
+	if n.Memory != "" && !validMemoryRE.MatchString(n.Memory) {
+		errs = append(errs, errors.Errorf("%q is not a valid memory limit", n.Memory))
+	}
+	if n.MemorySwap != "" {
+		if n.Memory == "" {
+			errs = append(errs, errors.New("memorySwap requires memory to be set"))
+		} else if n.MemorySwap != "-1" && !validMemoryRE.MatchString(n.MemorySwap) {
+			errs = append(errs, errors.Errorf("%q is not a valid memorySwap limit", n.MemorySwap))
+		}
+	}
+
+	// validate the kubelet reservations and eviction thresholds
+	for field, reserved := range map[string]map[string]string{
+		"kubeReserved":   n.KubeReserved,
+		"systemReserved": n.SystemReserved,
+	} {
+		for resource, quantity := range reserved {
+			if !reservedResources[resource] {
+				errs = append(errs, errors.Errorf("%s: %q is not a resource the kubelet can reserve", field, resource))
+			} else if !validKubeletQuantityRE.MatchString(quantity) {
+				errs = append(errs, errors.Errorf("%s: %q is not a valid quantity for %s", field, quantity, resource))
+			}
+		}
+	}
+	for signal, threshold := range n.EvictionHard {
+		if !evictionSignals[signal] {
+			errs = append(errs, errors.Errorf("evictionHard: %q is not a valid eviction signal", signal))
+		} else if !validKubeletQuantityRE.MatchString(threshold) {
+			errs = append(errs, errors.Errorf("evictionHard: %q is not a valid threshold for %s", threshold, signal))
+		}
+	}
+
+	// the sandbox image is written to the container runtime's config
+	if strings.ContainsAny(n.SandboxImage, " \t\n\"\\") {
+		errs = append(errs, errors.Errorf("%q is not a valid sandboxImage", n.SandboxImage))
+	}
+
+	// validate the snapshotter, empty means the node picks one
+	switch n.ContainerdSnapshotter {
+	case "", OverlayfsSnapshotter, FuseOverlayfsSnapshotter, NativeSnapshotter, StargzSnapshotter:
+	default:
+		errs = append(errs, errors.Errorf("%q is not a valid containerdSnapshotter", n.ContainerdSnapshotter))
+	}
+
+	// validate the log levels
+	if err := n.Logging.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid logging"))
+	}
+
+	// validate nesting cgroup namespace mode, empty means the default
+	switch n.Nesting.CgroupNS {
+	case "", CgroupNSPrivate, CgroupNSHost:
+	default:
+		errs = append(errs, errors.Errorf("%q is not a valid nesting cgroupNS", n.Nesting.CgroupNS))
+	}
+
+	// validate the simulated platform
+	switch n.Simulate.Preset {
+	case "", SimulationPresetWindows, SimulationPresetARM64:
+	default:
+		errs = append(errs, errors.Errorf("%q is not a valid simulate preset", n.Simulate.Preset))
+	}
+	if !validLabelValueRE.MatchString(n.Simulate.OS) {
+		errs = append(errs, errors.Errorf("%q is not a valid simulate os", n.Simulate.OS))
+	}
+	if !validLabelValueRE.MatchString(n.Simulate.Arch) {
+		errs = append(errs, errors.Errorf("%q is not a valid simulate arch", n.Simulate.Arch))
+	}
+	for _, taint := range n.Simulate.Taints {
+		if !validTaintRE.MatchString(taint) {
+			errs = append(errs, errors.Errorf("%q is not a valid taint, expected key[=value]:effect", taint))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+
+	return nil
+}
+
+// isNamespacedSysctl returns true if the sysctl name is namespaced
+func isNamespacedSysctl(name string) bool {
+	if namespacedSysctls.Has(name) {
+		return true
+	}
+	for _, prefix := range namespacedSysctlPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns a ConfigErrors with an entry for each problem
+// with the NodeLogging, or nil if there are none
+func (l *NodeLogging) Validate() error {
+	errs := []error{}
+
+	if l.KubeletVerbosity != nil && (*l.KubeletVerbosity < 0 || *l.KubeletVerbosity > 10) {
+		errs = append(errs, errors.Errorf("invalid kubeletVerbosity %d: must be from 0 to 10", *l.KubeletVerbosity))
+	}
+	switch l.ContainerdLogLevel {
+	case "", "trace", "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, errors.Errorf("invalid containerdLogLevel %q: must be one of trace, debug, info, warn or error", l.ContainerdLogLevel))
+	}
+	if l.JournalRateLimitInterval != "" {
+		if d, err := time.ParseDuration(l.JournalRateLimitInterval); err != nil || d < 0 {
+			errs = append(errs, errors.Errorf("invalid journalRateLimitInterval %q: must be a duration of zero or more", l.JournalRateLimitInterval))
+		}
+	}
+	if l.JournalRateLimitBurst != nil && *l.JournalRateLimitBurst < 0 {
+		errs = append(errs, errors.Errorf("invalid journalRateLimitBurst %d: must be zero or more", *l.JournalRateLimitBurst))
+	}
+
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+	return nil
+}
+
+// Validate returns a ConfigErrors with an entry for each problem
+// with the EncryptionAtRest, or nil if there are none
+func (e *EncryptionAtRest) Validate() error {
+	errs := []error{}
+
+	switch e.Provider {
+	case "":
+		if len(e.Resources) > 0 {
+			errs = append(errs, errors.New("resources requires a provider"))
+		}
+	case AESCBCEncryptionProvider, AESGCMEncryptionProvider, SecretboxEncryptionProvider, KMSEncryptionProvider:
+	default:
+		errs = append(errs, errors.Errorf("invalid provider %q: must be one of aescbc, aesgcm, secretbox or kms", e.Provider))
+	}
+
+	// the KMS v2 plugin is reached over a unix socket on the node
+	if e.KMSEndpoint != "" {
+		if e.Provider != KMSEncryptionProvider {
+			errs = append(errs, errors.New("kmsEndpoint requires the kms provider"))
+		} else if !strings.HasPrefix(e.KMSEndpoint, "unix:///") {
+			errs = append(errs, errors.Errorf("invalid kmsEndpoint %q: must be a unix:/// socket", e.KMSEndpoint))
+		}
+	}
+
+	for _, resource := range e.Resources {
+		if resource == "" {
+			errs = append(errs, errors.New("resources must not be empty strings"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+	return nil
+}
+
+// Validate returns a ConfigErrors with an entry for each problem
+// with the PodSecurity, or nil if there are none
+func (p *PodSecurity) Validate() error {
+	errs := []error{}
+
+	modes := []string{"enforce", "audit", "warn"}
+	for i, level := range []PodSecurityLevel{p.Enforce, p.Audit, p.Warn} {
+		switch level {
+		case "", PrivilegedPodSecurityLevel, BaselinePodSecurityLevel, RestrictedPodSecurityLevel:
+		default:
+			errs = append(errs, errors.Errorf("invalid %s level %q: must be one of privileged, baseline or restricted", modes[i], level))
+		}
+	}
+
+	fields := []string{"usernames", "runtimeClasses", "namespaces"}
+	for i, values := range [][]string{p.Exemptions.Usernames, p.Exemptions.RuntimeClasses, p.Exemptions.Namespaces} {
+		for _, value := range values {
+			if value == "" {
+				errs = append(errs, errors.Errorf("exemptions %s must not be empty strings", fields[i]))
+				break
+			}
+		}
+	}
+
 	if len(errs) > 0 {
 		return errors.NewAggregate(errs)
 	}
+	return nil
+}
+
+// Validate returns a ConfigErrors with an entry for each problem
+// with the Certificates, or nil if there are none
+func (c *Certificates) Validate() error {
+	errs := []error{}
+
+	// a custom CA needs both the certificate and the key
+	if (c.CACertFile == "") != (c.CAKeyFile == "") {
+		errs = append(errs, errors.New("caCertFile and caKeyFile must be set together"))
+	}
 
+	// validity periods should be positive durations
+	for _, period := range []struct{ name, value string }{
+		{"certificateValidityPeriod", c.CertificateValidityPeriod},
+		{"caCertificateValidityPeriod", c.CACertificateValidityPeriod},
+	} {
+		if period.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(period.value); err != nil || d <= 0 {
+			errs = append(errs, errors.Errorf("invalid %s %q: must be a positive duration", period.name, period.value))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+	return nil
+}
+
+// Validate returns a ConfigErrors with an entry for each problem
+// with the Timeouts, or nil if there are none
+func (t *Timeouts) Validate() error {
+	errs := []error{}
+
+	// timeouts should be non-negative durations, zero meaning no timeout
+	for _, timeout := range []struct{ name, value string }{
+		{"imagePull", t.ImagePull},
+		{"imagePullBackoff", t.ImagePullBackoff},
+		{"controlPlaneInit", t.ControlPlaneInit},
+		{"join", t.Join},
+		{"cniReady", t.CNIReady},
+		{"nodesReady", t.NodesReady},
+	} {
+		if timeout.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(timeout.value); err != nil || d < 0 {
+			errs = append(errs, errors.Errorf("invalid %s %q: must be a non-negative duration", timeout.name, timeout.value))
+		}
+	}
+
+	if t.ImagePullRetries != nil && *t.ImagePullRetries < 0 {
+		errs = append(errs, errors.Errorf("invalid imagePullRetries %d: must not be negative", *t.ImagePullRetries))
+	}
+
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+	return nil
+}
+
+// Validate returns a ConfigErrors with an entry for each problem
+// with the KubeadmPatch, or nil if there are none
+func (p *KubeadmPatch) Validate() error {
+	errs := []error{}
+
+	// target should be one of the components kubeadm supports patching
+	switch p.Target {
+	case KubeadmPatchTargetEtcd,
+		KubeadmPatchTargetKubeAPIServer,
+		KubeadmPatchTargetKubeControllerManager,
+		KubeadmPatchTargetKubeScheduler,
+		KubeadmPatchTargetKubeletConfiguration:
+	default:
+		errs = append(errs, errors.Errorf("%q is not a valid patch target", p.Target))
+	}
+
+	// patchType should be one of the patch types kubeadm supports
+	switch p.PatchType {
+	case KubeadmPatchTypeStrategic,
+		KubeadmPatchTypeMerge,
+		KubeadmPatchTypeJSON:
+	default:
+		errs = append(errs, errors.Errorf("%q is not a valid patch type", p.PatchType))
+	}
+
+	// exactly one of patch or path should be set
+	if (p.Patch == "") == (p.Path == "") {
+		errs = append(errs, errors.New("exactly one of patch or path must be set"))
+	}
+
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
 	return nil
 }
 
@@ -202,6 +839,119 @@ func validatePortMappings(portMappings []PortMapping) error {
 	return nil
 }
 
+// validateMounts checks the mount fields, the hostPath itself is checked on
+// the host when creating the cluster
+func validateMounts(mounts []Mount) []error {
+	errs := []error{}
+	containerPaths := sets.NewString()
+	for _, m := range mounts {
+		if m.HostPath == "" {
+			errs = append(errs, errors.Errorf("extraMounts entry for containerPath %q is missing hostPath", m.ContainerPath))
+		}
+		if !path.IsAbs(m.ContainerPath) {
+			errs = append(errs, errors.Errorf("extraMounts containerPath %q must be an absolute path", m.ContainerPath))
+		} else if containerPaths.Has(path.Clean(m.ContainerPath)) {
+			errs = append(errs, errors.Errorf("duplicate extraMounts containerPath %q", m.ContainerPath))
+		}
+		containerPaths.Insert(path.Clean(m.ContainerPath))
+		switch m.SelinuxRelabelMode {
+		case "", SelinuxRelabelModeShared, SelinuxRelabelModePrivate:
+		default:
+			errs = append(errs, errors.Errorf("invalid extraMounts selinuxRelabelMode %q for containerPath %q, must be one of: Shared, Private", m.SelinuxRelabelMode, m.ContainerPath))
+		}
+		if m.SelinuxRelabelMode != "" && !m.SelinuxRelabel {
+			errs = append(errs, errors.Errorf("extraMounts selinuxRelabelMode for containerPath %q requires selinuxRelabel: true", m.ContainerPath))
+		}
+		switch m.Propagation {
+		case "", MountPropagationNone, MountPropagationHostToContainer, MountPropagationBidirectional:
+		default:
+			errs = append(errs, errors.Errorf("invalid extraMounts propagation %q for containerPath %q, must be one of: None, HostToContainer, Bidirectional", m.Propagation, m.ContainerPath))
+		}
+	}
+	return errs
+}
+
+func validateAPIServerAdditionalAddresses(addresses []string, apiServerAddress string, apiServerPort int32) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	// every address is published on the same port, so kind cannot pick a
+	// random port for each of them
+	if apiServerPort <= 0 {
+		return errors.New("apiServerPort must be set to a fixed port")
+	}
+	seen := sets.NewString()
+	if ip := net.ParseIP(apiServerAddress); ip != nil {
+		seen.Insert(ip.String())
+	}
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			return errors.Errorf("%q is not a valid IP address", address)
+		}
+		if seen.Has(ip.String()) {
+			return errors.Errorf("duplicate address %q", address)
+		}
+		seen.Insert(ip.String())
+	}
+	return nil
+}
+
+func validateBridgedNetwork(n *BridgedNetwork) error {
+	if n.Driver != MacvlanDriver && n.Driver != IPVlanDriver {
+		return errors.Errorf("invalid driver: %s", n.Driver)
+	}
+	if n.Parent == "" {
+		return errors.New("parent must be set to a host interface")
+	}
+	if strings.ContainsAny(n.Parent, " \t/") {
+		return errors.Errorf("invalid parent interface %q", n.Parent)
+	}
+	_, subnet, err := net.ParseCIDR(n.Subnet)
+	if err != nil {
+		return errors.Errorf("invalid subnet %q: must be a CIDR", n.Subnet)
+	}
+	if n.IPRange != "" {
+		ip, ipRange, err := net.ParseCIDR(n.IPRange)
+		if err != nil {
+			return errors.Errorf("invalid ipRange %q: must be a CIDR", n.IPRange)
+		}
+		rangeSize, _ := ipRange.Mask.Size()
+		subnetSize, _ := subnet.Mask.Size()
+		if !subnet.Contains(ip) || rangeSize < subnetSize {
+			return errors.Errorf("ipRange %q is not within subnet %q", n.IPRange, n.Subnet)
+		}
+	}
+	if n.Gateway != "" {
+		gateway := net.ParseIP(n.Gateway)
+		if gateway == nil {
+			return errors.Errorf("invalid gateway %q: must be an IP address", n.Gateway)
+		}
+		if !subnet.Contains(gateway) {
+			return errors.Errorf("gateway %q is not within subnet %q", n.Gateway, n.Subnet)
+		}
+	}
+	return nil
+}
+
+// validateRegistryHost checks that registry is a "host" or "host:port"
+func validateRegistryHost(registry string) error {
+	if strings.Contains(registry, "/") {
+		return errors.New("must be host:port without a scheme or path")
+	}
+	host := registry
+	if h, port, err := net.SplitHostPort(registry); err == nil {
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return errors.Errorf("invalid port %q", port)
+		}
+		host = h
+	}
+	if net.ParseIP(host) == nil && (strings.HasPrefix(host, "*") || !validDNSNameRE.MatchString(host)) {
+		return errors.Errorf("invalid host %q", host)
+	}
+	return nil
+}
+
 func validatePort(port int32) error {
 	// NOTE: -1 is a special value for auto-selecting the port in the container
 	// backend where possible as opposed to in kind itself.
@@ -255,6 +1005,80 @@ func validateSubnets(subnetStr string, ipFamily ClusterIPFamily) error {
 	return nil
 }
 
+const (
+	// defaultNodeCIDRMaskSizeIPv4 is the kube-controller-manager default
+	// for --node-cidr-mask-size(-ipv4)
+	defaultNodeCIDRMaskSizeIPv4 = 24
+	// defaultNodeCIDRMaskSizeIPv6 is the kube-controller-manager default
+	// for --node-cidr-mask-size(-ipv6)
+	defaultNodeCIDRMaskSizeIPv6 = 64
+	// maxNodeCIDRMaskSizeDiff is the maximum difference between the pod
+	// subnet and node CIDR mask sizes supported by kube-controller-manager
+	maxNodeCIDRMaskSizeDiff = 16
+	// maxServiceSubnetHostBits is the maximum number of host bits in the
+	// service subnet supported by kube-apiserver
+	maxServiceSubnetHostBits = 20
+)
+
+// validatePodSubnetSizing checks that each pod subnet can be divided into
+// node CIDRs of the given mask size for every node
+func validatePodSubnetSizing(subnetStr string, maskSizeIPv4, maskSizeIPv6 int32, numNodes int) error {
+	allErrs := []error{}
+	if maskSizeIPv4 == 0 {
+		maskSizeIPv4 = defaultNodeCIDRMaskSizeIPv4
+	}
+	if maskSizeIPv6 == 0 {
+		maskSizeIPv6 = defaultNodeCIDRMaskSizeIPv6
+	}
+	for _, cidrString := range strings.Split(subnetStr, ",") {
+		_, cidr, err := net.ParseCIDR(cidrString)
+		if err != nil {
+			// this is validated by validateSubnets
+			continue
+		}
+		ones, bits := cidr.Mask.Size()
+		maskSize := int(maskSizeIPv4)
+		if cidr.IP.To4() == nil {
+			maskSize = int(maskSizeIPv6)
+		}
+		switch {
+		case maskSize > bits:
+			allErrs = append(allErrs, errors.Errorf("node CIDR mask size %d is invalid for %s", maskSize, cidr))
+		case maskSize < ones:
+			allErrs = append(allErrs, errors.Errorf("node CIDR mask size %d is larger than %s", maskSize, cidr))
+		case maskSize-ones > maxNodeCIDRMaskSizeDiff:
+			allErrs = append(allErrs, errors.Errorf("node CIDR mask size %d must be at most %d bits longer than the %s mask", maskSize, maxNodeCIDRMaskSizeDiff, cidr))
+		case 1<<(maskSize-ones) < numNodes:
+			allErrs = append(allErrs, errors.Errorf("%s only has room for %d node CIDRs of mask size %d, but the cluster has %d nodes", cidr, 1<<(maskSize-ones), maskSize, numNodes))
+		}
+	}
+	if len(allErrs) > 0 {
+		return errors.NewAggregate(allErrs)
+	}
+	return nil
+}
+
+// validateServiceSubnetSizing checks that each service subnet is not larger
+// than kube-apiserver supports
+func validateServiceSubnetSizing(subnetStr string) error {
+	allErrs := []error{}
+	for _, cidrString := range strings.Split(subnetStr, ",") {
+		_, cidr, err := net.ParseCIDR(cidrString)
+		if err != nil {
+			// this is validated by validateSubnets
+			continue
+		}
+		ones, bits := cidr.Mask.Size()
+		if bits-ones > maxServiceSubnetHostBits {
+			allErrs = append(allErrs, errors.Errorf("%s is too large, the mask must be at least /%d", cidr, bits-maxServiceSubnetHostBits))
+		}
+	}
+	if len(allErrs) > 0 {
+		return errors.NewAggregate(allErrs)
+	}
+	return nil
+}
+
 // isDualStackCIDRs returns if
 // - all are valid cidrs
 // - at least one cidr from each family (v4 or v6)
@@ -279,3 +1103,22 @@ func isDualStackCIDRs(cidrs []*net.IPNet) (bool, error) {
 
 	return v4Found && v6Found, nil
 }
+
+// validateStaticPodManifest checks that manifest is a single v1 Pod, the
+// kubelet ignores anything else in its static pod directory
+func validateStaticPodManifest(manifest string) error {
+	if strings.TrimSpace(manifest) == "" {
+		return errors.New("manifest is empty")
+	}
+	var typeMeta struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	if err := yaml.Unmarshal([]byte(manifest), &typeMeta); err != nil {
+		return err
+	}
+	if typeMeta.APIVersion != "v1" || typeMeta.Kind != "Pod" {
+		return errors.Errorf("manifest must be a v1 Pod, not %s %s", typeMeta.APIVersion, typeMeta.Kind)
+	}
+	return nil
+}
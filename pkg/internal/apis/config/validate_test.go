@@ -87,6 +87,234 @@ func TestClusterValidate(t *testing.T) {
 			}(),
 			ExpectErrors: 1,
 		},
+		{
+			Name: "valid apiServerPortRange",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.APIServerPortRange = "30000-30100"
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "bogus apiServerPortRange",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.APIServerPortRange = "30100-30000"
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "apiServerPortRange with apiServerPort",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.APIServerPort = 6443
+				c.Networking.APIServerPortRange = "30000-30100"
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "keepPortmap with disableDefaultCNI",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.DisableDefaultCNI = true
+				c.Networking.KeepPortmap = true
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "keepPortmap without disableDefaultCNI",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.KeepPortmap = true
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid apiServerAdditionalAddresses and apiServerCertSANs",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.APIServerPort = 6443
+				c.Networking.APIServerAdditionalAddresses = []string{"192.168.1.10", "0.0.0.0"}
+				c.Networking.APIServerCertSANs = []string{"kind.example.com", "*.kind.example.com", "10.0.0.1"}
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "apiServerAdditionalAddresses without apiServerPort",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.APIServerAdditionalAddresses = []string{"192.168.1.10"}
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "bogus apiServerAdditionalAddresses",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.APIServerPort = 6443
+				c.Networking.APIServerAdditionalAddresses = []string{"my-laptop.local"}
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "apiServerAdditionalAddresses duplicating apiServerAddress",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.APIServerPort = 6443
+				c.Networking.APIServerAdditionalAddresses = []string{"127.0.0.1"}
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid bridgedNetwork",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.BridgedNetwork = &BridgedNetwork{
+					Parent:  "eth0",
+					Subnet:  "192.168.1.0/24",
+					IPRange: "192.168.1.192/27",
+					Gateway: "192.168.1.1",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "bridgedNetwork without parent",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.BridgedNetwork = &BridgedNetwork{
+					Subnet: "192.168.1.0/24",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "bogus bridgedNetwork driver",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.BridgedNetwork = &BridgedNetwork{
+					Driver: "bridge",
+					Parent: "eth0",
+					Subnet: "192.168.1.0/24",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "bridgedNetwork ipRange outside subnet",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.BridgedNetwork = &BridgedNetwork{
+					Driver:  IPVlanDriver,
+					Parent:  "eth0",
+					Subnet:  "192.168.1.0/24",
+					IPRange: "192.168.0.0/16",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid additionalNetworks",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.AdditionalNetworks = []AdditionalNetwork{{Name: "net1"}, {Name: "storage", Subnet: "10.10.0.0/24"}}
+				c.Nodes = []Node{{
+					Role:               ControlPlaneRole,
+					AdditionalNetworks: []NodeNetwork{{Name: "net1"}, {Name: "storage", Interface: "eth-storage"}},
+				}}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "bogus additionalNetworks",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.AdditionalNetworks = []AdditionalNetwork{{Name: "net1"}, {Name: "net1"}, {Name: "Not_Valid"}, {Name: "net2", Subnet: "10.10.0.0"}}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 3,
+		},
+		{
+			Name: "node attached to unknown network",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.AdditionalNetworks = []AdditionalNetwork{{Name: "net1"}}
+				c.Nodes = []Node{{
+					Role:               ControlPlaneRole,
+					AdditionalNetworks: []NodeNetwork{{Name: "net2"}},
+				}}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "node with clashing interfaces",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.AdditionalNetworks = []AdditionalNetwork{{Name: "net1"}, {Name: "net2"}}
+				c.Nodes = []Node{{
+					Role:               ControlPlaneRole,
+					AdditionalNetworks: []NodeNetwork{{Name: "net1", Interface: "eth0"}, {Name: "net2", Interface: "net1"}, {Name: "net2"}},
+				}}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "bridgedNetwork gateway outside subnet",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.BridgedNetwork = &BridgedNetwork{
+					Parent:  "eth0",
+					Subnet:  "192.168.1.0/24",
+					Gateway: "10.0.0.1",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "bogus apiServerCertSANs",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.APIServerCertSANs = []string{"not a name", "Upper.example.com"}
+				return c
+			}(),
+			ExpectErrors: 2,
+		},
 		{
 			Name: "bogus kubeProxyMode",
 			Cluster: func() Cluster {
@@ -132,20 +360,32 @@ func TestClusterValidate(t *testing.T) {
 			Cluster: func() Cluster {
 				c := Cluster{}
 				SetDefaultsCluster(&c)
-				c.Networking.PodSubnet = "192.168.0.2/24,fd00:1::/25"
-				c.Networking.ServiceSubnet = "192.168.0.2/24,fd00:1::/25"
+				c.Networking.PodSubnet = "192.168.0.2/24,fd00:1::/56"
+				c.Networking.ServiceSubnet = "192.168.0.2/24,fd00:1::/112"
 				c.Networking.IPFamily = DualStackFamily
 				return c
 			}(),
 			ExpectErrors: 0,
 		},
+		{
+			Name: "invalid dual stack podSubnet and serviceSubnet family order",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.PodSubnet = "fd00:1::/56,192.168.0.2/24"
+				c.Networking.ServiceSubnet = "192.168.0.2/24,fd00:1::/112"
+				c.Networking.IPFamily = DualStackFamily
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
 		{
 			Name: "invalid dual stack podSubnet and multiple serviceSubnet",
 			Cluster: func() Cluster {
 				c := Cluster{}
 				SetDefaultsCluster(&c)
-				c.Networking.PodSubnet = "192.168.0.2/24,fd00:1::/25"
-				c.Networking.ServiceSubnet = "192.168.0.2/24,fd00:1::/25,10.0.0.0/16"
+				c.Networking.PodSubnet = "192.168.0.2/24,fd00:1::/56"
+				c.Networking.ServiceSubnet = "192.168.0.2/24,fd00:1::/112,10.0.0.0/16"
 				c.Networking.IPFamily = DualStackFamily
 				return c
 			}(),
@@ -156,7 +396,7 @@ func TestClusterValidate(t *testing.T) {
 			Cluster: func() Cluster {
 				c := Cluster{}
 				SetDefaultsCluster(&c)
-				c.Networking.PodSubnet = "192.168.0.2/24,fd00:1::/25"
+				c.Networking.PodSubnet = "192.168.0.2/24,fd00:1::/56"
 				c.Networking.ServiceSubnet = "192.168.0.2/24"
 				c.Networking.IPFamily = DualStackFamily
 				return c
@@ -169,7 +409,7 @@ func TestClusterValidate(t *testing.T) {
 				c := Cluster{}
 				SetDefaultsCluster(&c)
 				c.Networking.PodSubnet = "192.168.0.2/24"
-				c.Networking.ServiceSubnet = "192.168.0.2/24,fd00:1::/25"
+				c.Networking.ServiceSubnet = "192.168.0.2/24,fd00:1::/112"
 				c.Networking.IPFamily = DualStackFamily
 				return c
 			}(),
@@ -258,77 +498,513 @@ func TestClusterValidate(t *testing.T) {
 			}(),
 			ExpectErrors: 1,
 		},
-	}
-
-	for _, tc := range cases {
-		tc := tc //capture loop variable
-		t.Run(tc.Name, func(t *testing.T) {
-			t.Parallel()
-			err := tc.Cluster.Validate()
-			// the error can be:
-			// - nil, in which case we should expect no errors or fail
-			if err == nil {
-				if tc.ExpectErrors != 0 {
-					t.Error("received no errors but expected errors for case")
-				}
-				return
-			}
-			// get the list of errors
-			errs := errors.Errors(err)
-			if errs == nil {
-				errs = []error{err}
-			}
-			// we expect a certain number of errors
-			if len(errs) != tc.ExpectErrors {
-				t.Errorf("expected %d errors but got len(%v) = %d", tc.ExpectErrors, errs, len(errs))
-			}
-		})
-	}
-}
-
-func newDefaultedNode(role NodeRole) Node {
-	n := Node{
-		Role:  role,
-		Image: "myImage:latest",
-	}
-	SetDefaultsNode(&n)
-	return n
-}
-
-func TestNodeValidate(t *testing.T) {
-	t.Parallel()
-	cases := []struct {
-		TestName     string
-		Node         Node
-		ExpectErrors int
-	}{
 		{
-			TestName:     "Canonical node",
-			Node:         newDefaultedNode(ControlPlaneRole),
+			Name: "valid kubeadm patches",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.KubeadmPatches = []KubeadmPatch{
+					{
+						Target: KubeadmPatchTargetKubeAPIServer,
+						Patch:  "metadata:\n  annotations:\n    foo: bar",
+					},
+					{
+						Target:    KubeadmPatchTargetEtcd,
+						PatchType: KubeadmPatchTypeJSON,
+						Path:      "./etcd-patch.json",
+					},
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
 			ExpectErrors: 0,
 		},
 		{
-			TestName:     "Canonical node 2",
-			Node:         newDefaultedNode(WorkerRole),
-			ExpectErrors: 0,
+			Name: "bogus kubeadm patch target",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.KubeadmPatches = []KubeadmPatch{
+					{
+						Target: "kube-proxy",
+						Patch:  "{}",
+					},
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
 		},
 		{
-			TestName: "Empty image field",
-			Node: func() Node {
-				cfg := newDefaultedNode(ControlPlaneRole)
-				cfg.Image = ""
-				return cfg
+			Name: "kubeadm patch with both patch and path",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.KubeadmPatches = []KubeadmPatch{
+					{
+						Target: KubeadmPatchTargetKubeScheduler,
+						Patch:  "{}",
+						Path:   "./scheduler-patch.yaml",
+					},
+				}
+				SetDefaultsCluster(&c)
+				return c
 			}(),
 			ExpectErrors: 1,
 		},
 		{
-			TestName: "Empty role field",
-			Node: func() Node {
-				cfg := newDefaultedNode(ControlPlaneRole)
-				cfg.Role = ""
-				return cfg
+			Name: "valid component images",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.ComponentImages.KubeScheduler = "localhost:5001/kube-scheduler:dev"
+				c.ComponentImages.KubeControllerManager = "registry.k8s.io/kube-controller-manager:v1.32.0"
+				return c
 			}(),
-			ExpectErrors: 1,
+			ExpectErrors: 0,
+		},
+		{
+			Name: "bogus component image",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.ComponentImages.KubeScheduler = "kube-scheduler dev"
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "podSubnet too small for nodes",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.PodSubnet = "10.244.0.0/23"
+				c.Nodes = append(c.Nodes, newDefaultedNode(WorkerRole), newDefaultedNode(WorkerRole))
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "podSubnet fits nodes with custom node CIDR mask size",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.PodSubnet = "10.244.0.0/23"
+				c.Networking.NodeCIDRMaskSizeIPv4 = 25
+				c.Nodes = append(c.Nodes, newDefaultedNode(WorkerRole), newDefaultedNode(WorkerRole))
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "node CIDR mask size larger than dual stack podSubnet",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Networking.IPFamily = DualStackFamily
+				c.Networking.NodeCIDRMaskSizeIPv6 = 48
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "serviceSubnet too large",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ServiceSubnet = "10.0.0.0/8"
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid kubeadm init skip phases",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.KubeadmInitSkipPhases = []string{"addon/kube-proxy", "addon/coredns"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "bogus kubeadm init skip phase",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.KubeadmInitSkipPhases = []string{"addon/kube-proxy", "addon/bogus"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid insecure registries",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.InsecureRegistries = []string{"localhost:5001", "kind-registry:5000", "registry.example.com", "[::1]:5000"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "invalid insecure registries",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.InsecureRegistries = []string{"http://localhost:5001", "localhost:99999", "Bad_Host:5000", "localhost:5001/v2"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 4,
+		},
+		{
+			Name: "valid never pull registries",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.InsecureRegistries = []string{"localhost:5001"}
+				c.NeverPullRegistries = []string{"docker.io", "registry.k8s.io", "quay.io:443"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "invalid never pull registries",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.InsecureRegistries = []string{"localhost:5001"}
+				c.NeverPullRegistries = []string{"docker.io/library", "localhost:5001"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 2,
+		},
+		{
+			Name: "valid ready conditions",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.ReadyConditions = []string{"daemonset=kube-system/kindnet", "crd=widgets.example.com"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "empty ready condition",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.ReadyConditions = []string{"daemonset=kube-system/kindnet", " "}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "invalid defaultStorage",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Addons.DefaultStorage = "nfs"
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid loadBalancerPool",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Addons.LoadBalancerPool.Size = 32
+				c.Addons.LoadBalancerPool.MetalLB = true
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "negative loadBalancerPool size",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Addons.LoadBalancerPool.Size = -1
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "metalLB without loadBalancerPool size",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Addons.LoadBalancerPool.MetalLB = true
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid hollowNodes",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.HollowNodes = HollowNodes{Count: 100, Image: "registry.k8s.io/kubemark:v1.32.0"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			Name: "hollowNodes without image",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.HollowNodes.Count = 100
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "too many hollowNodes for the pod subnet",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.HollowNodes = HollowNodes{Count: 300, Image: "registry.k8s.io/kubemark:v1.32.0"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "negative hollowNodes count",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.HollowNodes = HollowNodes{Count: -1, Image: "registry.k8s.io/kubemark:v1.32.0"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "invalid swapBehavior",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.SwapBehavior = "UnlimitedSwap"
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid certificates",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Certificates = Certificates{
+					CACertFile:                "ca.crt",
+					CAKeyFile:                 "ca.key",
+					CertificateValidityPeriod: "2h",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+		},
+		{
+			Name: "invalid certificates",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Certificates = Certificates{
+					CACertFile:                  "ca.crt",
+					CACertificateValidityPeriod: "-1h",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 2,
+		},
+		{
+			Name: "valid encryption at rest",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.EncryptionAtRest = EncryptionAtRest{
+					Provider:  KMSEncryptionProvider,
+					Resources: []string{"secrets", "configmaps"},
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+		},
+		{
+			Name: "invalid encryption at rest",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.EncryptionAtRest = EncryptionAtRest{
+					Provider:    "rot13",
+					KMSEndpoint: "localhost:8080",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 2,
+		},
+		{
+			Name: "encryption at rest resources without a provider",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.EncryptionAtRest.Resources = []string{"secrets"}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid pod security",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.PodSecurity = PodSecurity{
+					Enforce: BaselinePodSecurityLevel,
+					Warn:    RestrictedPodSecurityLevel,
+					Exemptions: PodSecurityExemptions{
+						Namespaces: []string{"ingress-nginx"},
+					},
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+		},
+		{
+			Name: "invalid pod security",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.PodSecurity = PodSecurity{
+					Enforce: "strict",
+					Audit:   "Restricted",
+					Exemptions: PodSecurityExemptions{
+						Usernames: []string{""},
+					},
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 3,
+		},
+		{
+			Name: "valid timeouts",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				c.Timeouts = Timeouts{
+					ImagePull:        "10m",
+					ControlPlaneInit: "8m",
+					Join:             "0s",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+		},
+		{
+			Name: "invalid timeouts",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				retries := int32(-1)
+				c.Timeouts = Timeouts{
+					ImagePullRetries: &retries,
+					ControlPlaneInit: "-1m",
+					CNIReady:         "soon",
+				}
+				SetDefaultsCluster(&c)
+				return c
+			}(),
+			ExpectErrors: 3,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc //capture loop variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.Cluster.Validate()
+			// the error can be:
+			// - nil, in which case we should expect no errors or fail
+			if err == nil {
+				if tc.ExpectErrors != 0 {
+					t.Error("received no errors but expected errors for case")
+				}
+				return
+			}
+			// get the list of errors
+			errs := errors.Errors(err)
+			if errs == nil {
+				errs = []error{err}
+			}
+			// we expect a certain number of errors
+			if len(errs) != tc.ExpectErrors {
+				t.Errorf("expected %d errors but got len(%v) = %d", tc.ExpectErrors, errs, len(errs))
+			}
+		})
+	}
+}
+
+func newDefaultedNode(role NodeRole) Node {
+	n := Node{
+		Role:  role,
+		Image: "myImage:latest",
+	}
+	SetDefaultsNode(&n)
+	return n
+}
+
+func TestNodeValidate(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		TestName     string
+		Node         Node
+		ExpectErrors int
+	}{
+		{
+			TestName:     "Canonical node",
+			Node:         newDefaultedNode(ControlPlaneRole),
+			ExpectErrors: 0,
+		},
+		{
+			TestName:     "Canonical node 2",
+			Node:         newDefaultedNode(WorkerRole),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Valid extraMounts",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.ExtraMounts = []Mount{
+					{HostPath: "/data", ContainerPath: "/data", SelinuxRelabel: true, SelinuxRelabelMode: SelinuxRelabelModeShared},
+					{HostPath: "./cache", ContainerPath: "/cache", SelinuxRelabel: true, Propagation: MountPropagationHostToContainer},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid extraMounts",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.ExtraMounts = []Mount{
+					{ContainerPath: "/data"},
+					{HostPath: "/data", ContainerPath: "data"},
+					{HostPath: "/data", ContainerPath: "/data/"},
+					{HostPath: "/cache", ContainerPath: "/cache", SelinuxRelabel: true, SelinuxRelabelMode: "z", Propagation: "rshared"},
+					{HostPath: "/srv", ContainerPath: "/srv", SelinuxRelabelMode: SelinuxRelabelModeShared},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 6,
+		},
+		{
+			TestName: "Empty image field",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.Image = ""
+				return cfg
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			TestName: "Empty role field",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.Role = ""
+				return cfg
+			}(),
+			ExpectErrors: 1,
 		},
 		{
 			TestName: "Unknown role field",
@@ -339,6 +1015,336 @@ func TestNodeValidate(t *testing.T) {
 			}(),
 			ExpectErrors: 1,
 		},
+		{
+			TestName: "Valid systemd units",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.SystemdUnits = []SystemdUnit{
+					{
+						Name:    "cache.service",
+						Content: "[Service]\nExecStart=/usr/local/bin/cache\n",
+						Enabled: true,
+					},
+					{
+						Name:    "cache-gc@daily.timer",
+						Content: "[Timer]\nOnCalendar=daily\n",
+					},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid systemd units",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.SystemdUnits = []SystemdUnit{
+					{
+						Name:    "../cache.service",
+						Content: "[Service]\n",
+					},
+					{
+						Name:    "cache",
+						Content: "[Service]\n",
+					},
+					{
+						Name: "empty.service",
+					},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 3,
+		},
+		{
+			TestName: "Node image systemd units",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.SystemdUnits = []SystemdUnit{
+					{
+						Name:    "kubelet.service",
+						Content: "[Service]\n",
+					},
+					{
+						Name:    "containerd.service",
+						Content: "[Service]\n",
+					},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 2,
+		},
+		{
+			TestName: "Duplicate systemd units",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.SystemdUnits = []SystemdUnit{
+					{
+						Name:    "cache.service",
+						Content: "[Service]\n",
+					},
+					{
+						Name:    "cache.service",
+						Content: "[Service]\n",
+					},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			TestName: "Valid static pods",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.StaticPods = []StaticPod{
+					{
+						Name:     "node-agent",
+						Manifest: "apiVersion: v1\nkind: Pod\nmetadata:\n  name: node-agent\n",
+					},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid static pods",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.StaticPods = []StaticPod{
+					{
+						Name:     "../agent",
+						Manifest: "apiVersion: v1\nkind: Pod\n",
+					},
+					{
+						Name:     "kube-apiserver",
+						Manifest: "apiVersion: v1\nkind: Pod\n",
+					},
+					{
+						Name:     "deployment",
+						Manifest: "apiVersion: apps/v1\nkind: Deployment\n",
+					},
+					{
+						Name: "empty",
+					},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 4,
+		},
+		{
+			TestName: "Duplicate static pods",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.StaticPods = []StaticPod{
+					{
+						Name:     "agent",
+						Manifest: "apiVersion: v1\nkind: Pod\n",
+					},
+					{
+						Name:     "agent",
+						Manifest: "apiVersion: v1\nkind: Pod\n",
+					},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			TestName: "Valid nesting",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Nesting = Nesting{DevMount: true, CgroupNS: CgroupNSHost}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid nesting cgroupNS",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Nesting = Nesting{CgroupNS: "shared"}
+				return cfg
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			TestName: "Valid simulated windows node",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Simulate = NodeSimulation{Preset: SimulationPresetWindows}
+				SetDefaultsNode(&cfg)
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Valid simulated node",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Simulate = NodeSimulation{Arch: "s390x", Taints: []string{"arch:NoExecute", "example.com/os=plan9:PreferNoSchedule"}}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid simulated node",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Simulate = NodeSimulation{Preset: "macos", OS: "mac os", Taints: []string{"os=windows", "os=windows:Never"}}
+				return cfg
+			}(),
+			ExpectErrors: 4,
+		},
+		{
+			TestName: "Valid env and sysctls",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Env = map[string]string{"GODEBUG": "http2debug=1", "_FOO": ""}
+				cfg.Sysctls = map[string]string{
+					"net.core.somaxconn": "1024",
+					"kernel.shmmax":      "68719476736",
+					"fs.mqueue.msg_max":  "100",
+				}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid env names",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Env = map[string]string{"1FOO": "bar", "FOO=BAR": "baz"}
+				return cfg
+			}(),
+			ExpectErrors: 2,
+		},
+		{
+			TestName: "Non-namespaced sysctls",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Sysctls = map[string]string{
+					"vm.max_map_count":            "262144",
+					"fs.inotify.max_user_watches": "524288",
+				}
+				return cfg
+			}(),
+			ExpectErrors: 2,
+		},
+		{
+			TestName: "Valid memory and swap",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Memory = "4g"
+				cfg.MemorySwap = "-1"
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid memory and swap",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.Memory = "4GiB"
+				cfg.MemorySwap = "lots"
+				return cfg
+			}(),
+			ExpectErrors: 2,
+		},
+		{
+			TestName: "Swap without memory",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.MemorySwap = "6g"
+				return cfg
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			TestName: "Valid kubelet reservations",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.KubeReserved = map[string]string{"cpu": "500m", "memory": "1Gi"}
+				cfg.SystemReserved = map[string]string{"memory": "512Mi", "pid": "1000"}
+				cfg.EvictionHard = map[string]string{"memory.available": "5%"}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid kubelet reservations",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.KubeReserved = map[string]string{"gpu": "1"}
+				cfg.SystemReserved = map[string]string{"memory": "1Gi,cpu=1"}
+				cfg.EvictionHard = map[string]string{"memory.free": "1Gi", "nodefs.available": "lots"}
+				return cfg
+			}(),
+			ExpectErrors: 4,
+		},
+		{
+			TestName: "Valid sandboxImage",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.SandboxImage = "registry.example.com:5000/pause:3.10"
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid sandboxImage",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.SandboxImage = `pause" other = "x`
+				return cfg
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			TestName: "Valid containerdSnapshotter",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.ContainerdSnapshotter = StargzSnapshotter
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid containerdSnapshotter",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.ContainerdSnapshotter = "zfs"
+				return cfg
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			TestName: "Valid logging",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				verbosity, burst := int32(4), int32(0)
+				cfg.Logging = NodeLogging{
+					KubeletVerbosity:         &verbosity,
+					ContainerdLogLevel:       "debug",
+					JournalRateLimitInterval: "0s",
+					JournalRateLimitBurst:    &burst,
+				}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid logging",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				verbosity := int32(11)
+				cfg.Logging = NodeLogging{
+					KubeletVerbosity:         &verbosity,
+					ContainerdLogLevel:       "verbose",
+					JournalRateLimitInterval: "-1s",
+				}
+				return cfg
+			}(),
+			ExpectErrors: 3,
+		},
 		{
 			TestName: "Invalid ContainerPort",
 			Node: func() Node {
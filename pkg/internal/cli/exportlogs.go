@@ -0,0 +1,35 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"sigs.k8s.io/kind/pkg/fs"
+)
+
+// ExportLogsTempDir is the --export-logs-on-delete value when no directory
+// is given, logs are then exported to a new temporary directory
+const ExportLogsTempDir = "<tempdir>"
+
+// ExportLogsDir returns the directory to export logs to for an
+// --export-logs-on-delete value, creating a temporary directory for
+// ExportLogsTempDir. It returns "" if logs should not be exported
+func ExportLogsDir(value string) (string, error) {
+	if value != ExportLogsTempDir {
+		return value, nil
+	}
+	return fs.TempDir("", "")
+}
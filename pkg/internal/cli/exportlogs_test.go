@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"os"
+	"testing"
+)
+
+func TestExportLogsDir(t *testing.T) {
+	t.Parallel()
+	for _, value := range []string{"", "/tmp/kind-logs"} {
+		dir, err := ExportLogsDir(value)
+		if err != nil || dir != value {
+			t.Errorf("expected %q to be kept, got %q and error %v", value, dir, err)
+		}
+	}
+	dir, err := ExportLogsDir(ExportLogsTempDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("expected a temporary directory, got %q", dir)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseFileMode(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Mode        string
+		Expected    os.FileMode
+		ExpectError bool
+	}{
+		{Mode: "", Expected: 0},
+		{Mode: "0644", Expected: 0644},
+		{Mode: "600", Expected: 0600},
+		{Mode: "0", ExpectError: true},
+		{Mode: "1777", ExpectError: true},
+		{Mode: "0648", ExpectError: true},
+		{Mode: "rw-r--r--", ExpectError: true},
+	}
+	for _, tc := range cases {
+		mode, err := ParseFileMode(tc.Mode)
+		if (err != nil) != tc.ExpectError {
+			t.Errorf("%q: expected error %v but got %v", tc.Mode, tc.ExpectError, err)
+		}
+		if mode != tc.Expected {
+			t.Errorf("%q: expected %v but got %v", tc.Mode, tc.Expected, mode)
+		}
+	}
+}
@@ -38,6 +38,8 @@ type Logger struct {
 	bufferPool *bufferPool
 	// kind special additions
 	isSmartWriter bool
+	progress      Progress
+	statusEvents  *StatusEventWriter
 }
 
 var _ log.Logger = &Logger{}
@@ -58,7 +60,57 @@ func (l *Logger) SetWriter(w io.Writer) {
 	defer l.writerMu.Unlock()
 	l.writer = w
 	_, isSpinner := w.(*Spinner)
-	l.isSmartWriter = isSpinner || env.IsSmartTerminal(w)
+	l.isSmartWriter = (isSpinner && !env.NoColor()) || env.IsSmartTerminal(w)
+}
+
+// SetProgress sets how status progress is presented, see Progress
+func (l *Logger) SetProgress(progress Progress) {
+	l.writerMu.Lock()
+	defer l.writerMu.Unlock()
+	l.progress = progress
+	spinner, isSpinner := l.writer.(*Spinner)
+	switch progress {
+	case ProgressTTY:
+		// force the spinner, even if we don't think this is a smart terminal
+		if !isSpinner {
+			l.writer = NewSpinner(l.writer)
+		}
+		l.isSmartWriter = !env.NoColor()
+	case ProgressPlain:
+		// plain output never contains escape codes
+		if isSpinner {
+			l.writer = spinner.writer
+		}
+		l.isSmartWriter = false
+	}
+}
+
+// SetStatusEvents sets where Status writes machine readable StatusEvents in
+// addition to its usual output, nil disables them
+func (l *Logger) SetStatusEvents(w *StatusEventWriter) {
+	l.writerMu.Lock()
+	defer l.writerMu.Unlock()
+	l.statusEvents = w
+}
+
+// getStatusEvents returns the StatusEventWriter set by SetStatusEvents
+func (l *Logger) getStatusEvents() *StatusEventWriter {
+	l.writerMu.Lock()
+	defer l.writerMu.Unlock()
+	return l.statusEvents
+}
+
+// getProgress returns the effective progress mode, resolving ProgressAuto
+func (l *Logger) getProgress() Progress {
+	l.writerMu.Lock()
+	defer l.writerMu.Unlock()
+	if l.progress != "" && l.progress != ProgressAuto {
+		return l.progress
+	}
+	if _, isSpinner := l.writer.(*Spinner); isSpinner {
+		return ProgressTTY
+	}
+	return ProgressPlain
 }
 
 // ColorEnabled returns true if the caller is OK to write colored output
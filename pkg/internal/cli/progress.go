@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Progress controls how Status presents progress
+type Progress string
+
+const (
+	// ProgressAuto uses ProgressTTY when writing to a smart terminal and
+	// ProgressPlain otherwise
+	ProgressAuto Progress = "auto"
+	// ProgressTTY uses a loading spinner and colored output
+	ProgressTTY Progress = "tty"
+	// ProgressPlain writes one timestamped line per event with no escape
+	// codes, this is suitable for CI logs
+	ProgressPlain Progress = "plain"
+	// ProgressQuiet suppresses status output entirely, other log output is
+	// not affected
+	ProgressQuiet Progress = "quiet"
+)
+
+// Progresses contains all of the valid Progress values
+var Progresses = []Progress{ProgressAuto, ProgressTTY, ProgressPlain, ProgressQuiet}
+
+// ParseProgress parses s as a Progress
+func ParseProgress(s string) (Progress, error) {
+	for _, p := range Progresses {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	names := make([]string, 0, len(Progresses))
+	for _, p := range Progresses {
+		names = append(names, string(p))
+	}
+	return "", fmt.Errorf("invalid progress %q, must be one of: %s", s, strings.Join(names, ", "))
+}
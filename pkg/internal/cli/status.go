@@ -18,16 +18,28 @@ package cli
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/log"
 )
 
+// now is time.Now, overridden in tests
+var now = time.Now
+
 // Status is used to track ongoing status in a CLI, with a nice loading spinner
 // when attached to a terminal
 type Status struct {
-	spinner *Spinner
-	status  string
-	logger  log.Logger
+	mu       sync.Mutex
+	spinner  *Spinner
+	status   string
+	started  time.Time
+	logger   log.Logger
+	progress Progress
+	events   *StatusEventWriter
+	// per-node results for the current status, see NodeEnd
+	nodes     []string
+	nodesDone int
 	// for controlling coloring etc
 	successFormat string
 	failureFormat string
@@ -42,11 +54,15 @@ func StatusForLogger(l log.Logger) *Status {
 		successFormat: " ✓ %s\n",
 		failureFormat: " ✗ %s\n",
 	}
-	// if we're using the CLI logger, check for if it has a spinner setup
-	// and wire the status to that
+	// if we're using the CLI logger, check for the progress mode and
+	// if it has a spinner setup and wire the status to that
 	if v, ok := l.(*Logger); ok {
-		if v2, ok := v.writer.(*Spinner); ok {
+		s.progress = v.getProgress()
+		s.events = v.getStatusEvents()
+		if v2, ok := v.writer.(*Spinner); ok && s.progress == ProgressTTY {
 			s.spinner = v2
+		}
+		if s.spinner != nil && v.ColorEnabled() {
 			// use colored success / failure messages
 			s.successFormat = " \x1b[32m✓\x1b[0m %s\n"
 			s.failureFormat = " \x1b[31m✗\x1b[0m %s\n"
@@ -59,32 +75,99 @@ func StatusForLogger(l log.Logger) *Status {
 // there will be a loading spinner with this status
 func (s *Status) Start(status string) {
 	s.End(true)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	// set new status
 	s.status = status
-	if s.spinner != nil {
+	s.started = now()
+	s.events.Write(StatusEvent{Time: s.started, Type: StatusEventStart, Status: status})
+	switch {
+	case s.progress == ProgressQuiet:
+	case s.spinner != nil:
 		s.spinner.SetSuffix(fmt.Sprintf(" %s ", s.status))
 		s.spinner.Start()
-	} else {
-		s.logger.V(0).Infof(" • %s  ...\n", s.status)
+	default:
+		s.logger.V(0).Infof(s.timestamp(s.started)+" • %s  ...\n", s.status)
+	}
+}
+
+// NodeEnd records that the current status has completed for node,
+// this is used by phases that operate on multiple nodes concurrently.
+// On a terminal the results are grouped under the status when it ends,
+// otherwise they are written immediately, one line per node.
+func (s *Status) NodeEnd(node string, success bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status == "" {
+		return
+	}
+	format := s.successFormat
+	if !success {
+		format = s.failureFormat
+	}
+	s.nodesDone++
+	s.events.Write(StatusEvent{Type: StatusEventNode, Status: s.status, Node: node, Success: &success})
+	switch {
+	case s.progress == ProgressQuiet:
+	case s.spinner != nil:
+		s.nodes = append(s.nodes, "  "+fmt.Sprintf(format, node))
+		s.spinner.SetSuffix(fmt.Sprintf(" %s (%d done) ", s.status, s.nodesDone))
+	default:
+		s.logger.V(0).Infof(s.timestamp(now())+"  "+format, node)
 	}
 }
 
 // End completes the current status, ending any previous spinning and
 // marking the status as success or failure
 func (s *Status) End(success bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.status == "" {
 		return
 	}
 
-	if s.spinner != nil {
+	format := s.successFormat
+	if !success {
+		format = s.failureFormat
+	}
+	ended := now()
+	s.events.Write(StatusEvent{
+		Time:           ended,
+		Type:           StatusEventEnd,
+		Status:         s.status,
+		Success:        &success,
+		ElapsedSeconds: ended.Sub(s.started).Seconds(),
+	})
+	switch {
+	case s.progress == ProgressQuiet:
+	case s.spinner != nil:
 		s.spinner.Stop()
 		fmt.Fprint(s.spinner.writer, "\r")
-	}
-	if success {
-		s.logger.V(0).Infof(s.successFormat, s.status)
-	} else {
-		s.logger.V(0).Infof(s.failureFormat, s.status)
+		s.logger.V(0).Infof(format, s.status)
+		for _, node := range s.nodes {
+			s.logger.V(0).Info(node)
+		}
+	default:
+		s.logger.V(0).Infof(s.timestamp(ended)+format, s.status+s.elapsed(ended))
 	}
 
 	s.status = ""
+	s.nodes = nil
+	s.nodesDone = 0
+}
+
+// timestamp returns the line prefix for plain progress output
+func (s *Status) timestamp(t time.Time) string {
+	if s.progress != ProgressPlain {
+		return ""
+	}
+	return "[" + t.Format("15:04:05") + "]"
+}
+
+// elapsed returns the status duration suffix for plain progress output
+func (s *Status) elapsed(ended time.Time) string {
+	if s.progress != ProgressPlain {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", ended.Sub(s.started).Round(time.Millisecond))
 }
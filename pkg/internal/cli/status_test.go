@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParseProgress(t *testing.T) {
+	t.Parallel()
+	for _, p := range Progresses {
+		parsed, err := ParseProgress(string(p))
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", p, err)
+		}
+		if parsed != p {
+			t.Fatalf("expected %q but got %q", p, parsed)
+		}
+	}
+	if _, err := ParseProgress("fancy"); err == nil {
+		t.Fatal("expected error parsing invalid progress")
+	}
+}
+
+// NOTE: this test overrides now and therefore is not parallel
+func TestStatusPlain(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	calls := 0
+	now = func() time.Time {
+		calls++
+		return start.Add(time.Duration(calls-1) * time.Second)
+	}
+	defer func() { now = time.Now }()
+
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf, 0)
+	logger.SetProgress(ProgressPlain)
+	s := StatusForLogger(logger)
+	s.Start("Joining worker nodes")
+	s.NodeEnd("kind-worker", true)
+	s.NodeEnd("kind-worker2", false)
+	s.End(false)
+
+	expected := "[12:00:00] • Joining worker nodes  ...\n" +
+		"[12:00:01]   ✓ kind-worker\n" +
+		"[12:00:02]   ✗ kind-worker2\n" +
+		"[12:00:03] ✗ Joining worker nodes (3s)\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestStatusQuiet(t *testing.T) {
+	t.Parallel()
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf, 0)
+	logger.SetProgress(ProgressQuiet)
+	s := StatusForLogger(logger)
+	s.Start("Writing configuration")
+	s.NodeEnd("kind-control-plane", true)
+	s.End(true)
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output but got: %q", buf.String())
+	}
+	// other output is unaffected
+	logger.V(0).Info("hello")
+	if buf.String() != "hello\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestStatusEvents(t *testing.T) {
+	t.Parallel()
+	events := &bytes.Buffer{}
+	logger := NewLogger(&bytes.Buffer{}, 0)
+	// events are written even when status output is suppressed
+	logger.SetProgress(ProgressQuiet)
+	logger.SetStatusEvents(NewStatusEventWriter(events))
+	s := StatusForLogger(logger)
+	s.Start("Joining worker nodes")
+	s.NodeEnd("kind-worker", false)
+	s.End(false)
+	NewStatusEventWriter(events).Done(errors.New("boom"))
+
+	decoder := json.NewDecoder(events)
+	expected := []StatusEvent{
+		{Type: StatusEventStart, Status: "Joining worker nodes"},
+		{Type: StatusEventNode, Status: "Joining worker nodes", Node: "kind-worker", Success: new(bool)},
+		{Type: StatusEventEnd, Status: "Joining worker nodes", Success: new(bool)},
+		{Type: StatusEventDone, Success: new(bool), Error: "boom"},
+	}
+	for _, e := range expected {
+		actual := StatusEvent{}
+		if err := decoder.Decode(&actual); err != nil {
+			t.Fatalf("failed to decode event: %v", err)
+		}
+		if actual.Time.IsZero() {
+			t.Errorf("expected %s event to have a time", actual.Type)
+		}
+		actual.Time = time.Time{}
+		actual.ElapsedSeconds = 0
+		assert.DeepEqual(t, e, actual)
+	}
+	if decoder.More() {
+		t.Errorf("unexpected extra events")
+	}
+}
+
+func TestStatusOtherLogger(t *testing.T) {
+	t.Parallel()
+	// loggers other than the cli logger get untimestamped lines
+	s := StatusForLogger(log.NoopLogger{})
+	s.Start("Writing configuration")
+	s.End(true)
+	if s.timestamp(time.Now()) != "" || s.elapsed(time.Now()) != "" {
+		t.Fatal("expected no timestamps for other loggers")
+	}
+}
+
+func TestSetProgress(t *testing.T) {
+	t.Parallel()
+	logger := NewLogger(&bytes.Buffer{}, 0)
+	if p := logger.getProgress(); p != ProgressPlain {
+		t.Fatalf("expected auto to resolve to %q for a non-terminal, got %q", ProgressPlain, p)
+	}
+	logger.SetProgress(ProgressTTY)
+	if _, ok := logger.writer.(*Spinner); !ok {
+		t.Fatal("expected tty progress to use a spinner")
+	}
+	if p := logger.getProgress(); p != ProgressTTY {
+		t.Fatalf("expected %q, got %q", ProgressTTY, p)
+	}
+	logger.SetProgress(ProgressPlain)
+	if _, ok := logger.writer.(*Spinner); ok {
+		t.Fatal("expected plain progress not to use a spinner")
+	}
+	if logger.ColorEnabled() {
+		t.Fatal("expected plain progress not to use color")
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// StatusEvent is a machine readable progress event, written as a line of
+// JSON for wrappers such as editor extensions, see Logger.SetStatusEvents
+type StatusEvent struct {
+	// Time is when the event happened
+	Time time.Time `json:"time"`
+	// Type is one of the StatusEvent types, e.g. StatusEventStart
+	Type string `json:"type"`
+	// Status is the human readable status the event is for, if any
+	Status string `json:"status,omitempty"`
+	// Node is the node a StatusEventNode is for
+	Node string `json:"node,omitempty"`
+	// Success is set for StatusEventNode, StatusEventEnd and StatusEventDone
+	Success *bool `json:"success,omitempty"`
+	// ElapsedSeconds is how long the status took, for StatusEventEnd
+	ElapsedSeconds float64 `json:"elapsedSeconds,omitempty"`
+	// Error is the error the command failed with, for StatusEventDone
+	Error string `json:"error,omitempty"`
+}
+
+const (
+	// StatusEventStart is written when a status starts
+	StatusEventStart = "start"
+	// StatusEventNode is written when a status completes for one node
+	StatusEventNode = "node"
+	// StatusEventEnd is written when a status ends
+	StatusEventEnd = "end"
+	// StatusEventDone is written once when the command completes
+	StatusEventDone = "done"
+)
+
+// StatusEventWriter writes StatusEvents as newline delimited JSON
+type StatusEventWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewStatusEventWriter returns a StatusEventWriter writing to w
+func NewStatusEventWriter(w io.Writer) *StatusEventWriter {
+	return &StatusEventWriter{encoder: json.NewEncoder(w)}
+}
+
+// Write writes e, filling in the time if it is unset. Errors are ignored,
+// a reader going away must not fail the command.
+func (w *StatusEventWriter) Write(e StatusEvent) {
+	if w == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = now()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_ = w.encoder.Encode(e)
+}
+
+// Done writes the StatusEventDone event for err
+func (w *StatusEventWriter) Done(err error) {
+	success := err == nil
+	e := StatusEvent{Type: StatusEventDone, Success: &success}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	w.Write(e)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debugbundle implements recording the commands kind runs into a
+// directory for triaging issues, and reading them back for replay
+package debugbundle
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/redact"
+)
+
+const (
+	// infoFile contains the Info for the bundle
+	infoFile = "info.json"
+	// commandsFile contains one Command per line, in the order they finished
+	commandsFile = "commands.jsonl"
+	// stdinDir contains the stdin of each Command with Stdin set, by Index
+	stdinDir = "stdin"
+)
+
+// Info describes the kind invocation a bundle was recorded for
+type Info struct {
+	// Args are the kind command line arguments
+	Args []string `json:"args"`
+	// Version is the kind version
+	Version string `json:"version"`
+	// Start is when recording started
+	Start time.Time `json:"start"`
+}
+
+// Command is a command recorded in a bundle
+type Command struct {
+	// Index is the order the command finished in, starting at 0
+	Index int `json:"index"`
+	// Command is [Name Args...]
+	Command []string `json:"command"`
+	// Start is when the command was started
+	Start time.Time `json:"start"`
+	// DurationSeconds is how long the command ran for
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Error is the error running the command, if any
+	Error string `json:"error,omitempty"`
+	// Output is the combined stdout and stderr of the command
+	Output string `json:"output,omitempty"`
+	// OutputTruncated is true if the output was longer than exec.MaxRecordedBytes
+	OutputTruncated bool `json:"outputTruncated,omitempty"`
+	// Stdin is true if input was supplied to the command, it is stored in
+	// the bundle's stdin directory
+	Stdin bool `json:"stdin,omitempty"`
+	// StdinTruncated is true if the input was longer than exec.MaxRecordedBytes
+	StdinTruncated bool `json:"stdinTruncated,omitempty"`
+	// InputRedacted is true if credentials or keys were removed from the
+	// command or its input, so it cannot be re-executed as recorded
+	InputRedacted bool `json:"inputRedacted,omitempty"`
+	// OutputRedacted is true if credentials or keys were removed from Output
+	OutputRedacted bool `json:"outputRedacted,omitempty"`
+}
+
+// Recorder records the commands run by exec.DefaultCmder into a bundle.
+// Credentials and private keys, such as those in admin.conf, the CA keys and
+// kubeadm's bootstrap tokens, are redacted from the recorded commands, input
+// and output so that bundles can be attached to bug reports.
+type Recorder struct {
+	dir string
+
+	mu       sync.Mutex
+	commands *os.File
+	next     int
+	errs     []error
+}
+
+// Start creates a bundle in dir for the kind invocation with args, and
+// starts recording into it
+func Start(dir string, args []string, kindVersion string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Join(dir, stdinDir), os.ModePerm); err != nil {
+		return nil, errors.Wrap(err, "failed to create debug bundle")
+	}
+	info, err := json.MarshalIndent(&Info{
+		Args:    args,
+		Version: kindVersion,
+		Start:   time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, infoFile), info, 0644); err != nil {
+		return nil, errors.Wrap(err, "failed to write debug bundle")
+	}
+	f, err := os.Create(filepath.Join(dir, commandsFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write debug bundle")
+	}
+	r := &Recorder{
+		dir:      dir,
+		commands: f,
+	}
+	exec.DefaultCmder.SetRecorder(r.record)
+	return r, nil
+}
+
+// Stop stops recording and closes the bundle, returning any errors
+// writing to it
+func (r *Recorder) Stop() error {
+	exec.DefaultCmder.SetRecorder(nil)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.commands.Close(); err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return errors.NewAggregate(r.errs)
+}
+
+func (r *Recorder) record(record *exec.Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	command, argsRedacted := redact.Args(record.Command)
+	output, outputRedacted := redact.Bytes(record.Output)
+	stdin, stdinRedacted := redact.Bytes(record.Stdin)
+	c := &Command{
+		Index:           r.next,
+		Command:         command,
+		Start:           record.Start,
+		DurationSeconds: record.Duration.Seconds(),
+		Output:          string(output),
+		OutputTruncated: record.OutputTruncated,
+		Stdin:           record.Stdin != nil,
+		StdinTruncated:  record.StdinTruncated,
+		InputRedacted:   argsRedacted || stdinRedacted,
+		OutputRedacted:  outputRedacted,
+	}
+	r.next++
+	if record.Err != nil {
+		// errors include the command and its output
+		c.Error = redact.String(record.Err.Error())
+	}
+	if c.Stdin {
+		if err := os.WriteFile(stdinPath(r.dir, c.Index), stdin, 0644); err != nil {
+			r.errs = append(r.errs, err)
+		}
+	}
+	line, err := json.Marshal(c)
+	if err != nil {
+		r.errs = append(r.errs, err)
+		return
+	}
+	if _, err := r.commands.Write(append(line, '\n')); err != nil {
+		r.errs = append(r.errs, err)
+	}
+}
+
+// Read reads the bundle in dir
+func Read(dir string) (*Info, []Command, error) {
+	rawInfo, err := os.ReadFile(filepath.Join(dir, infoFile))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read debug bundle")
+	}
+	info := &Info{}
+	if err := json.Unmarshal(rawInfo, info); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse debug bundle info")
+	}
+	f, err := os.Open(filepath.Join(dir, commandsFile))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read debug bundle")
+	}
+	defer f.Close()
+	commands := []Command{}
+	scanner := bufio.NewScanner(f)
+	// lines may contain up to exec.MaxRecordedBytes of escaped output
+	scanner.Buffer(nil, 8*exec.MaxRecordedBytes)
+	for scanner.Scan() {
+		c := Command{}
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse debug bundle command %d", len(commands))
+		}
+		commands = append(commands, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read debug bundle")
+	}
+	return info, commands, nil
+}
+
+// ReadStdin reads the recorded stdin of c from the bundle in dir
+func ReadStdin(dir string, c *Command) ([]byte, error) {
+	if !c.Stdin {
+		return nil, nil
+	}
+	return os.ReadFile(stdinPath(dir, c.Index))
+}
+
+func stdinPath(dir string, index int) string {
+	return filepath.Join(dir, stdinDir, strconv.Itoa(index))
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugbundle
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	dir := t.TempDir()
+	r, err := Start(dir, []string{"kind", "create", "cluster"}, "v0.0.0-test")
+	if err != nil {
+		t.Fatalf("unexpected error starting recording: %v", err)
+	}
+	if err := exec.Command("cat").SetStdin(strings.NewReader("hello")).Run(); err != nil {
+		t.Fatalf("unexpected error running command: %v", err)
+	}
+	if err := exec.Command("false").Run(); err == nil {
+		t.Fatal("expected error running false")
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatalf("unexpected error stopping recording: %v", err)
+	}
+	// commands after stopping are not recorded
+	if err := exec.Command("true").Run(); err != nil {
+		t.Fatalf("unexpected error running command: %v", err)
+	}
+
+	info, commands, err := Read(dir)
+	if err != nil {
+		t.Fatalf("unexpected error reading bundle: %v", err)
+	}
+	assert.StringEqual(t, "kind create cluster", strings.Join(info.Args, " "))
+	assert.StringEqual(t, "v0.0.0-test", info.Version)
+	if len(commands) != 2 {
+		t.Fatalf("expected 2 commands, got %d", len(commands))
+	}
+	assert.StringEqual(t, "cat", strings.Join(commands[0].Command, " "))
+	assert.StringEqual(t, "hello", commands[0].Output)
+	assert.StringEqual(t, "", commands[0].Error)
+	stdin, err := ReadStdin(dir, &commands[0])
+	if err != nil {
+		t.Fatalf("unexpected error reading stdin: %v", err)
+	}
+	assert.StringEqual(t, "hello", string(stdin))
+	assert.StringEqual(t, "false", strings.Join(commands[1].Command, " "))
+	if commands[1].Error == "" || commands[1].Stdin {
+		t.Errorf("unexpected recorded command: %+v", commands[1])
+	}
+}
@@ -42,6 +42,13 @@ func IsTerminal(w io.Writer) bool {
 	return false
 }
 
+// NoColor returns true if the user explicitly requested no colored output
+// https://no-color.org/
+func NoColor() bool {
+	_, set := os.LookupEnv("NO_COLOR")
+	return set
+}
+
 // IsSmartTerminal returns true if the writer w is a terminal AND
 // we think that the terminal is smart enough to use VT escape codes etc.
 func IsSmartTerminal(w io.Writer) bool {
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin implements discovering and running kind-<name> plugins,
+// executables on PATH that extend the kind CLI like kubectl plugins
+package plugin
+
+import (
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/cmd/kind/version"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+	"sigs.k8s.io/kind/pkg/internal/usersettings"
+)
+
+// Prefix is the prefix of plugin executable names, `kind foo` runs kind-foo
+const Prefix = "kind-"
+
+// The environment variables plugins are run with, in addition to kind's
+// environment, so that they can act on the same clusters as kind
+const (
+	// BinaryEnv is the path to the kind binary running the plugin
+	BinaryEnv = "KIND_BINARY"
+	// VersionEnv is the version of the kind binary running the plugin
+	VersionEnv = "KIND_VERSION"
+	// ProviderEnv is the container runtime kind would use, as accepted by
+	// --runtime, or empty if none was found
+	ProviderEnv = "KIND_PROVIDER"
+)
+
+// lookPath is osexec.LookPath, replaced in tests
+var lookPath = osexec.LookPath
+
+// Plugin is a plugin executable found on PATH
+type Plugin struct {
+	// Name is the command the plugin implements, e.g. "foo bar" for
+	// kind-foo-bar
+	Name string
+	// Path is the path to the executable
+	Path string
+}
+
+// Find returns the plugin executable to run for args and the arguments to
+// pass to it, if args do not start with one of root's commands or a flag.
+// Like kubectl, the longest matching name wins, so `kind foo bar` runs
+// kind-foo-bar rather than kind-foo, and dashes in arguments are replaced
+// with underscores, so `kind foo-bar` runs kind-foo_bar.
+func Find(root *cobra.Command, args []string) (path string, pluginArgs []string, ok bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || IsBuiltin(root, args[0]) {
+		return "", nil, false
+	}
+	names := []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		names = append(names, strings.ReplaceAll(arg, "-", "_"))
+	}
+	for i := len(names); i > 0; i-- {
+		if path, err := lookPath(Prefix + strings.Join(names[:i], "-")); err == nil {
+			return path, args[i:], true
+		}
+	}
+	return "", nil, false
+}
+
+// IsBuiltin returns whether name is one of root's commands, including the
+// help and shell completion commands cobra adds
+func IsBuiltin(root *cobra.Command, name string) bool {
+	switch name {
+	case "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, c := range root.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the plugin at path with args, streams and the provider context
+// in its environment. If the plugin exits non-zero the error is an
+// *exec.ExitError from os/exec.
+//
+// Unlike sigs.k8s.io/kind/pkg/exec the output is not captured, and the
+// streams are passed to the plugin as is so that it may detect a terminal.
+func Run(logger log.Logger, streams cmd.IOStreams, path string, args []string) error {
+	env := os.Environ()
+	if binary, err := os.Executable(); err == nil {
+		env = append(env, BinaryEnv+"="+binary)
+	}
+	env = append(env,
+		VersionEnv+"="+version.Version(),
+		ProviderEnv+"="+providerName(logger),
+	)
+	c := osexec.Command(path, args...)
+	c.Env = env
+	c.Stdin = streams.In
+	c.Stdout = streams.Out
+	c.Stderr = streams.ErrOut
+	if err := c.Run(); err != nil {
+		if _, ok := err.(*osexec.ExitError); ok {
+			return err
+		}
+		return errors.Wrapf(err, "failed to run plugin %s", path)
+	}
+	return nil
+}
+
+// providerName returns the container runtime kind would use, like
+// `kind runtimes list`
+func providerName(logger log.Logger) string {
+	if err := usersettings.Load(); err != nil {
+		logger.Warnf("Failed to load user settings: %v", err)
+	}
+	if selected, _ := runtime.Selection(); selected != "" {
+		return selected
+	}
+	if opt, err := cluster.DetectNodeProvider(); err == nil {
+		return cluster.NewProvider(cluster.ProviderWithLogger(logger), opt).Name()
+	}
+	return ""
+}
+
+// List returns the plugins on PATH, sorted by name. Only the first of
+// several executables with the same name is returned, as that is the one
+// that runs.
+func List() []Plugin {
+	seen := map[string]bool{}
+	plugins := []Plugin{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] || !isExecutable(filepath.Join(dir, entry.Name())) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{
+				Name: name,
+				Path: filepath.Join(dir, entry.Name()),
+			})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// pluginName returns the command a plugin executable implements, e.g.
+// "foo bar" for kind-foo-bar
+func pluginName(file string) (string, bool) {
+	if goruntime.GOOS == "windows" {
+		file = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	if !strings.HasPrefix(file, Prefix) || len(file) == len(Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	return strings.ReplaceAll(strings.ReplaceAll(name, "-", " "), "_", "-"), true
+}
+
+// isExecutable returns whether path is a regular file the user may run
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if goruntime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0o111 != 0
+}
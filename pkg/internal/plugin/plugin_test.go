@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestFind(t *testing.T) {
+	root := &cobra.Command{Use: "kind"}
+	root.AddCommand(&cobra.Command{Use: "create"})
+	root.AddCommand(&cobra.Command{Use: "get", Aliases: []string{"g"}})
+
+	onPath := map[string]bool{
+		"kind-foo":     true,
+		"kind-foo-bar": true,
+		"kind-foo_baz": true,
+		"kind-create":  true,
+	}
+	defer func(original func(string) (string, error)) { lookPath = original }(lookPath)
+	lookPath = func(file string) (string, error) {
+		if onPath[file] {
+			return "/usr/local/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	cases := []struct {
+		Name         string
+		Args         []string
+		ExpectedPath string
+		ExpectedArgs []string
+		ExpectedOK   bool
+	}{
+		{
+			Name: "no args",
+		},
+		{
+			Name: "flag first",
+			Args: []string{"--verbosity", "1", "foo"},
+		},
+		{
+			Name: "builtin command",
+			Args: []string{"create", "cluster"},
+		},
+		{
+			Name: "builtin alias",
+			Args: []string{"g", "nodes"},
+		},
+		{
+			Name: "help",
+			Args: []string{"help", "foo"},
+		},
+		{
+			Name: "unknown",
+			Args: []string{"unknown"},
+		},
+		{
+			Name:         "plugin",
+			Args:         []string{"foo", "--name", "kind"},
+			ExpectedPath: "/usr/local/bin/kind-foo",
+			ExpectedArgs: []string{"--name", "kind"},
+			ExpectedOK:   true,
+		},
+		{
+			Name:         "longest match",
+			Args:         []string{"foo", "bar", "qux"},
+			ExpectedPath: "/usr/local/bin/kind-foo-bar",
+			ExpectedArgs: []string{"qux"},
+			ExpectedOK:   true,
+		},
+		{
+			Name:         "dash in argument",
+			Args:         []string{"foo-baz"},
+			ExpectedPath: "/usr/local/bin/kind-foo_baz",
+			ExpectedArgs: []string{},
+			ExpectedOK:   true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			path, args, ok := Find(root, tc.Args)
+			assert.BoolEqual(t, tc.ExpectedOK, ok)
+			assert.StringEqual(t, tc.ExpectedPath, path)
+			assert.DeepEqual(t, tc.ExpectedArgs, args)
+		})
+	}
+}
+
+func TestPluginName(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		File       string
+		Expected   string
+		ExpectedOK bool
+	}{
+		{File: "kind-foo", Expected: "foo", ExpectedOK: true},
+		{File: "kind-foo-bar", Expected: "foo bar", ExpectedOK: true},
+		{File: "kind-foo_bar", Expected: "foo-bar", ExpectedOK: true},
+		{File: "kind-"},
+		{File: "kind"},
+		{File: "kubectl-foo"},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.File, func(t *testing.T) {
+			t.Parallel()
+			name, ok := pluginName(tc.File)
+			assert.BoolEqual(t, tc.ExpectedOK, ok)
+			assert.StringEqual(t, tc.Expected, name)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redact removes credentials and private keys from the commands
+// kind runs and their output before they are written anywhere, such as
+// debug bundles and traces
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the credentials and keys that are removed
+const Redacted = "REDACTED"
+
+// secretFlags are the command line flags whose values are credentials
+var secretFlags = []string{
+	"--token",
+	"--tls-bootstrap-token",
+	"--discovery-token",
+	"--certificate-key",
+	"--password",
+}
+
+var (
+	// privateKeyPattern matches PEM encoded private keys, such as the CA keys
+	privateKeyPattern = regexp.MustCompile(`(?s)(-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----).*?(-----END [A-Z0-9 ]*PRIVATE KEY-----)`)
+	// yamlSecretPattern matches the credentials in kubeconfigs such as
+	// admin.conf and the bootstrap tokens in kubeadm configs
+	yamlSecretPattern = regexp.MustCompile(`(?m)^(\s*(?:- )?(?:client-key-data|client-certificate-data|token|password|certificateKey):[ \t]*)\S.*$`)
+	// jsonSecretPattern is yamlSecretPattern for JSON output
+	jsonSecretPattern = regexp.MustCompile(`("(?:client-key-data|client-certificate-data|token|password|certificateKey)"\s*:\s*)"[^"]*"`)
+	// flagSecretPattern matches secretFlags and their value in output,
+	// such as the join commands printed by kubeadm init
+	flagSecretPattern = regexp.MustCompile(`(` + strings.Join(secretFlags, "|") + `)([ =])\S+`)
+	// userinfoPattern matches the user and password in URLs, such as
+	// proxies and registry mirrors
+	userinfoPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/?#@\s]+@`)
+)
+
+// Bytes returns b with credentials and private keys replaced, and true
+// if anything was replaced
+func Bytes(b []byte) ([]byte, bool) {
+	out := privateKeyPattern.ReplaceAll(b, []byte("$1\n"+Redacted+"\n$2"))
+	out = yamlSecretPattern.ReplaceAll(out, []byte("${1}"+Redacted))
+	out = jsonSecretPattern.ReplaceAll(out, []byte(`${1}"`+Redacted+`"`))
+	out = flagSecretPattern.ReplaceAll(out, []byte("${1}${2}"+Redacted))
+	out = userinfoPattern.ReplaceAll(out, []byte("${1}"+Redacted+"@"))
+	return out, string(out) != string(b)
+}
+
+// String is Bytes for strings, such as error messages
+func String(s string) string {
+	out, _ := Bytes([]byte(s))
+	return string(out)
+}
+
+// Args returns command with the values of secretFlags and the userinfo of
+// URLs replaced, and true if anything was replaced
+func Args(command []string) ([]string, bool) {
+	out := make([]string, len(command))
+	changed := false
+	for i := range command {
+		if i > 0 && isSecretFlag(command[i-1]) {
+			out[i] = Redacted
+			changed = true
+			continue
+		}
+		// this also covers --flag=value and flags within shell scripts
+		out[i] = flagSecretPattern.ReplaceAllString(command[i], "${1}${2}"+Redacted)
+		out[i] = userinfoPattern.ReplaceAllString(out[i], "${1}"+Redacted+"@")
+		changed = changed || out[i] != command[i]
+	}
+	return out, changed
+}
+
+func isSecretFlag(arg string) bool {
+	for _, flag := range secretFlags {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
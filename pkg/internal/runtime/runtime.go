@@ -4,25 +4,118 @@ import (
 	"os"
 
 	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/external"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/usersettings"
 	"sigs.k8s.io/kind/pkg/log"
 )
 
-// GetDefault selected the default runtime from the environment override
+// Names are the runtimes that can be selected with the --runtime flag or
+// KIND_EXPERIMENTAL_PROVIDER, as can external providers with an executable
+// on PATH, see sigs.k8s.io/kind/pkg/cluster/external
+var Names = []string{"docker", "podman", "nerdctl", "finch", "nerdctl.lima"}
+
+// selected is the runtime selected with the --runtime flag
+var selected string
+
+// Select selects the runtime to use, taking precedence over
+// KIND_EXPERIMENTAL_PROVIDER, this implements the --runtime flag
+func Select(name string) error {
+	if !isKnown(name) {
+		return errors.Errorf("unknown runtime %q, must be one of %v or an external provider with %s%s on PATH", name, Names, external.Prefix, name)
+	}
+	selected = name
+	return nil
+}
+
+// Selected returns the runtime selected with Select, if any
+func Selected() string {
+	return selected
+}
+
+// Selection returns the runtime selected with the --runtime flag,
+// KIND_EXPERIMENTAL_PROVIDER or the user settings file and which of them
+// selected it, or empty strings if the runtime is auto-detected
+func Selection() (name, source string) {
+	if selected != "" {
+		return selected, "--runtime"
+	}
+	if p := os.Getenv("KIND_EXPERIMENTAL_PROVIDER"); isKnown(p) {
+		return p, "KIND_EXPERIMENTAL_PROVIDER"
+	}
+	if p := usersettings.Get().Runtime; isKnown(p) {
+		return p, usersettings.Path()
+	}
+	return "", ""
+}
+
+// GetDefault selected the default runtime from the --runtime flag, the
+// environment override or the user settings file
 func GetDefault(logger log.Logger) cluster.ProviderOption {
-	switch p := os.Getenv("KIND_EXPERIMENTAL_PROVIDER"); p {
-	case "":
+	if selected != "" {
+		logger.V(1).Infof("using %s due to --runtime", selected)
+		return providerOption(selected)
+	}
+	p := os.Getenv("KIND_EXPERIMENTAL_PROVIDER")
+	if p == "" {
+		return settingsProviderOption(logger)
+	}
+	if !isKnown(p) {
+		logger.Warnf("ignoring unknown value %q for KIND_EXPERIMENTAL_PROVIDER", p)
+		return nil
+	}
+	logger.Warnf("using %s due to KIND_EXPERIMENTAL_PROVIDER", p)
+	return providerOption(p)
+}
+
+// KubeconfigContextTemplate returns the option naming the clusters'
+// kubeconfig entries with the kubeconfigContextTemplate from the user
+// settings file, if any. KIND_KUBECONFIG_CONTEXT_TEMPLATE takes precedence
+func KubeconfigContextTemplate() cluster.ProviderOption {
+	tmpl := usersettings.Get().KubeconfigContextTemplate
+	if tmpl == "" {
+		return nil
+	}
+	return cluster.ProviderWithKubeconfigContextTemplate(tmpl)
+}
+
+// settingsProviderOption returns the runtime from the user settings file,
+// if any
+func settingsProviderOption(logger log.Logger) cluster.ProviderOption {
+	p := usersettings.Get().Runtime
+	if p == "" {
 		return nil
+	}
+	if !isKnown(p) {
+		logger.Warnf("ignoring unknown runtime %q in %s", p, usersettings.Path())
+		return nil
+	}
+	logger.V(1).Infof("using %s due to %s", p, usersettings.Path())
+	return providerOption(p)
+}
+
+func isKnown(name string) bool {
+	return isBuiltin(name) || external.IsAvailable(name)
+}
+
+func isBuiltin(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func providerOption(name string) cluster.ProviderOption {
+	switch name {
 	case "podman":
-		logger.Warn("using podman due to KIND_EXPERIMENTAL_PROVIDER")
 		return cluster.ProviderWithPodman()
 	case "docker":
-		logger.Warn("using docker due to KIND_EXPERIMENTAL_PROVIDER")
 		return cluster.ProviderWithDocker()
-	case "nerdctl", "finch", "nerdctl.lima":
-		logger.Warnf("using %s due to KIND_EXPERIMENTAL_PROVIDER", p)
-		return cluster.ProviderWithNerdctl(p)
-	default:
-		logger.Warnf("ignoring unknown value %q for KIND_EXPERIMENTAL_PROVIDER", p)
-		return nil
 	}
+	if !isBuiltin(name) {
+		return cluster.ProviderWithExternal(name)
+	}
+	return cluster.ProviderWithNerdctl(name)
 }
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/redact"
+)
+
+// exportTimeout bounds how long exporting may delay the command exiting
+const exportTimeout = 10 * time.Second
+
+// the subset of the OTLP JSON encoding kind needs, see
+// https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/trace/v1/trace.proto
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+// export sends the ended spans to the endpoint
+func (t *Tracer) export() error {
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(t.exportRequest(spans))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "invalid OTLP traces endpoint")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	client := &http.Client{Timeout: exportTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to export traces")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("failed to export traces: %s: %s", resp.Status, msg)
+	}
+	return nil
+}
+
+func (t *Tracer) exportRequest(spans []*Span) *exportRequest {
+	traceID := hex.EncodeToString(t.traceID[:])
+	exported := make([]span, 0, len(spans))
+	for _, s := range spans {
+		e := span{
+			TraceID:           traceID,
+			SpanID:            hex.EncodeToString(s.id[:]),
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+			Status:            status{Code: statusCodeOK},
+		}
+		if s.parent != nil {
+			e.ParentSpanID = hex.EncodeToString(s.parent.id[:])
+		} else if t.parentID != [8]byte{} {
+			e.ParentSpanID = hex.EncodeToString(t.parentID[:])
+		}
+		for _, a := range s.attributes {
+			e.Attributes = append(e.Attributes, keyValue{Key: a.Key, Value: anyValue{StringValue: a.Value}})
+		}
+		if s.err != nil {
+			// errors from commands include their command line and output
+			e.Status = status{Code: statusCodeError, Message: redact.String(s.err.Error())}
+		}
+		exported = append(exported, e)
+	}
+	resourceAttributes := []keyValue{
+		{Key: "service.name", Value: anyValue{StringValue: t.serviceName}},
+	}
+	if t.serviceVersion != "" {
+		resourceAttributes = append(resourceAttributes, keyValue{Key: "service.version", Value: anyValue{StringValue: t.serviceVersion}})
+	}
+	return &exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{Attributes: resourceAttributes},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: "sigs.k8s.io/kind", Version: t.serviceVersion},
+				Spans: exported,
+			}},
+		}},
+	}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
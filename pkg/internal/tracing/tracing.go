@@ -0,0 +1,264 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing implements minimal OpenTelemetry tracing for kind
+// commands, exporting spans with OTLP over HTTP/JSON.
+//
+// It is configured with the standard OTEL_EXPORTER_OTLP_* environment
+// variables and does nothing if no endpoint is set. kind deliberately avoids
+// the OpenTelemetry SDK to keep its dependencies minimal.
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/redact"
+)
+
+// Attribute is a span attribute
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// String returns an Attribute
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a timed operation, it is nil if tracing is disabled
+type Span struct {
+	tracer     *Tracer
+	id         [8]byte
+	parent     *Span
+	name       string
+	start      time.Time
+	end        time.Time
+	attributes []Attribute
+	err        error
+}
+
+// SetAttribute sets an attribute on the span before it ends
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.attributes = append(s.attributes, String(key, value))
+}
+
+// End ends the span, err is recorded as the span's status
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.tracer.end(s, err)
+}
+
+// Tracer collects spans for a single trace until they are exported
+type Tracer struct {
+	endpoint       string
+	headers        map[string]string
+	serviceName    string
+	serviceVersion string
+	traceID        [16]byte
+	// parentID is the span ID from TRACEPARENT, if any, so that kind's spans
+	// appear under the calling pipeline's
+	parentID [8]byte
+
+	mu     sync.Mutex
+	active *Span
+	spans  []*Span
+}
+
+// current is the Tracer set up by Setup, if any
+var current *Tracer
+var currentMu sync.Mutex
+
+// Setup starts tracing if an OTLP endpoint is configured in the environment,
+// including the commands run by exec.DefaultCmder. The returned stop func
+// stops tracing and exports the collected spans.
+func Setup(serviceVersion string) (stop func() error, err error) {
+	t, err := fromEnv(serviceVersion)
+	if err != nil || t == nil {
+		return func() error { return nil }, err
+	}
+	removeRecorder := exec.DefaultCmder.AddRecorder(t.recordCommand)
+	currentMu.Lock()
+	current = t
+	currentMu.Unlock()
+	return func() error {
+		removeRecorder()
+		currentMu.Lock()
+		current = nil
+		currentMu.Unlock()
+		return t.export()
+	}, nil
+}
+
+// Start starts a span as a child of the active span, it is nil if tracing
+// is not set up. The span is active until it ends.
+func Start(name string, attributes ...Attribute) *Span {
+	currentMu.Lock()
+	t := current
+	currentMu.Unlock()
+	if t == nil {
+		return nil
+	}
+	return t.start(name, attributes...)
+}
+
+func fromEnv(serviceVersion string) (*Tracer, error) {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil, nil
+	}
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if endpoint == "" {
+			return nil, nil
+		}
+		endpoint = strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	}
+	headers := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS")
+	if headers == "" {
+		headers = os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")
+	}
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "kind"
+	}
+	t := &Tracer{
+		endpoint:       endpoint,
+		headers:        parseHeaders(headers),
+		serviceName:    serviceName,
+		serviceVersion: serviceVersion,
+	}
+	if traceID, parentID, ok := parseTraceParent(os.Getenv("TRACEPARENT")); ok {
+		t.traceID, t.parentID = traceID, parentID
+	} else if _, err := rand.Read(t.traceID[:]); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Tracer) start(name string, attributes ...Attribute) *Span {
+	s := &Span{
+		tracer:     t,
+		name:       name,
+		start:      time.Now(),
+		attributes: attributes,
+	}
+	// span IDs only need to be unique within the trace, a failure to read
+	// randomness is not worth failing the command over
+	_, _ = rand.Read(s.id[:])
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s.parent = t.active
+	t.active = s
+	return s
+}
+
+func (t *Tracer) end(s *Span, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s.end = time.Now()
+	s.err = err
+	if t.active == s {
+		t.active = s.parent
+	}
+	t.spans = append(t.spans, s)
+}
+
+// recordCommand records commands as spans under the active span.
+// Credentials are redacted from the command line, as traces are exported
+func (t *Tracer) recordCommand(record *exec.Record) {
+	command, _ := redact.Args(record.Command)
+	s := &Span{
+		tracer: t,
+		name:   commandSpanName(record.Command),
+		start:  record.Start,
+		end:    record.Start.Add(record.Duration),
+		attributes: []Attribute{
+			String("process.command_line", strings.Join(command, " ")),
+		},
+		err: record.Err,
+	}
+	_, _ = rand.Read(s.id[:])
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s.parent = t.active
+	t.spans = append(t.spans, s)
+}
+
+// commandSpanName returns e.g. "docker run" for a command, subcommands are
+// more useful than the binary alone when looking for where time is spent
+func commandSpanName(command []string) string {
+	if len(command) == 0 {
+		return "exec"
+	}
+	if len(command) > 1 && !strings.HasPrefix(command[1], "-") {
+		return command[0] + " " + command[1]
+	}
+	return command[0]
+}
+
+// parseTraceParent parses a W3C traceparent header value,
+// https://www.w3.org/TR/trace-context/#traceparent-header
+func parseTraceParent(traceParent string) (traceID [16]byte, parentID [8]byte, ok bool) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		len(parts[1]) != 2*len(traceID) || len(parts[2]) != 2*len(parentID) {
+		return traceID, parentID, false
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil {
+		return traceID, parentID, false
+	}
+	if _, err := hex.Decode(parentID[:], []byte(parts[2])); err != nil {
+		return traceID, parentID, false
+	}
+	if traceID == [16]byte{} || parentID == [8]byte{} {
+		return traceID, parentID, false
+	}
+	return traceID, parentID, true
+}
+
+// parseHeaders parses the OTEL_EXPORTER_OTLP_HEADERS format, comma separated
+// key=value pairs with URL encoded values
+func parseHeaders(headers string) map[string]string {
+	parsed := map[string]string{}
+	for _, header := range strings.Split(headers, ",") {
+		kv := strings.SplitN(header, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		value, err := url.PathUnescape(strings.TrimSpace(kv[1]))
+		if key == "" || err != nil {
+			continue
+		}
+		parsed[key] = value
+	}
+	return parsed
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usersettings implements the optional user settings file, which
+// holds defaults for kind commands
+package usersettings
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/cli"
+)
+
+// Settings are the defaults read from the user settings file. Command line
+// flags, environment variables and cluster configs take precedence over
+// these.
+type Settings struct {
+	// Runtime is the container runtime to use, as with --runtime
+	Runtime string `yaml:"runtime,omitempty"`
+	// Verbosity is the log verbosity, as with --verbosity
+	Verbosity int32 `yaml:"verbosity,omitempty"`
+	// NodeImage is the image for nodes that do not set one in the cluster
+	// config
+	NodeImage string `yaml:"nodeImage,omitempty"`
+	// Wait is how long to wait for the control plane to be ready when
+	// neither --wait nor timeouts.nodesReady are set, e.g. "5m"
+	Wait string `yaml:"wait,omitempty"`
+	// Proxy settings are used when the proxy environment variables are unset
+	Proxy Proxy `yaml:"proxy,omitempty"`
+	// KubeconfigContextTemplate is the template for the names of the
+	// clusters' kubeconfig entries, used when
+	// KIND_KUBECONFIG_CONTEXT_TEMPLATE is unset, e.g. "dev-{{.Name}}"
+	KubeconfigContextTemplate string `yaml:"kubeconfigContextTemplate,omitempty"`
+	// KubeconfigMode is the mode of written kubeconfig files when
+	// --kubeconfig-mode is not set, e.g. "0644"
+	KubeconfigMode string `yaml:"kubeconfigMode,omitempty"`
+	// KubeconfigOwner is the owner of written kubeconfig files when
+	// --kubeconfig-owner is not set, e.g. "1000:1000"
+	KubeconfigOwner string `yaml:"kubeconfigOwner,omitempty"`
+}
+
+// Proxy contains proxy settings for the nodes
+type Proxy struct {
+	HTTPProxy  string `yaml:"httpProxy,omitempty"`
+	HTTPSProxy string `yaml:"httpsProxy,omitempty"`
+	NoProxy    string `yaml:"noProxy,omitempty"`
+}
+
+// WaitDuration returns Wait as a duration
+func (s *Settings) WaitDuration() time.Duration {
+	d, _ := time.ParseDuration(s.Wait)
+	return d
+}
+
+// KubeconfigFileMode returns KubeconfigMode as a file mode, or zero if unset
+func (s *Settings) KubeconfigFileMode() os.FileMode {
+	mode, _ := cli.ParseFileMode(s.KubeconfigMode)
+	return mode
+}
+
+// current holds the settings loaded by Load
+var current Settings
+
+// Get returns the settings loaded by Load, or empty settings
+func Get() Settings {
+	return current
+}
+
+// Path returns the path of the user settings file, KIND_USER_CONFIG if set,
+// otherwise kind/config.yaml under $XDG_CONFIG_HOME or ~/.config
+func Path() string {
+	if p := os.Getenv("KIND_USER_CONFIG"); p != "" {
+		return p
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "kind", "config.yaml")
+}
+
+// Load reads the user settings file at Path, if it exists, for Get
+func Load() error {
+	path := Path()
+	if path == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to read user settings")
+	}
+	s, err := parse(raw)
+	if err != nil {
+		return errors.Wrapf(err, "invalid user settings file %q", path)
+	}
+	current = s
+	return nil
+}
+
+func parse(raw []byte) (Settings, error) {
+	s := Settings{}
+	d := yaml.NewDecoder(bytes.NewReader(raw))
+	d.KnownFields(true)
+	if err := d.Decode(&s); err != nil && err != io.EOF {
+		return Settings{}, errors.WithStack(err)
+	}
+	if s.Wait != "" {
+		if _, err := time.ParseDuration(s.Wait); err != nil {
+			return Settings{}, errors.Errorf("invalid wait %q: %v", s.Wait, err)
+		}
+	}
+	if s.KubeconfigContextTemplate != "" {
+		if _, err := template.New("context").Parse(s.KubeconfigContextTemplate); err != nil {
+			return Settings{}, errors.Errorf("invalid kubeconfigContextTemplate %q: %v", s.KubeconfigContextTemplate, err)
+		}
+	}
+	if _, err := cli.ParseFileMode(s.KubeconfigMode); err != nil {
+		return Settings{}, errors.Errorf("invalid kubeconfigMode: %v", err)
+	}
+	return s, nil
+}
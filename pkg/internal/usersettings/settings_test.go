@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usersettings
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	s, err := parse([]byte(`runtime: podman
+verbosity: 2
+nodeImage: kindest/node:v1.31.0
+wait: 5m
+proxy:
+  httpProxy: http://proxy.example.com:3128
+  noProxy: .example.com
+kubeconfigContextTemplate: dev-{{.Name}}
+kubeconfigMode: "0644"
+kubeconfigOwner: "1000:1000"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.DeepEqual(t, Settings{
+		Runtime:   "podman",
+		Verbosity: 2,
+		NodeImage: "kindest/node:v1.31.0",
+		Wait:      "5m",
+		Proxy: Proxy{
+			HTTPProxy: "http://proxy.example.com:3128",
+			NoProxy:   ".example.com",
+		},
+		KubeconfigContextTemplate: "dev-{{.Name}}",
+		KubeconfigMode:            "0644",
+		KubeconfigOwner:           "1000:1000",
+	}, s)
+	if d := s.WaitDuration(); d != 5*time.Minute {
+		t.Errorf("expected a wait of 5m but got %v", d)
+	}
+	if m := s.KubeconfigFileMode(); m != 0644 {
+		t.Errorf("expected a kubeconfig mode of 0644 but got %v", m)
+	}
+
+	if s, err := parse([]byte("")); err != nil || s != (Settings{}) {
+		t.Errorf("expected empty settings for an empty file but got %v, %v", s, err)
+	}
+	if _, err := parse([]byte("image: kindest/node:v1.31.0\n")); err == nil {
+		t.Errorf("expected an error for an unknown field")
+	}
+	if _, err := parse([]byte("wait: soon\n")); err == nil {
+		t.Errorf("expected an error for an invalid wait")
+	}
+	if _, err := parse([]byte("kubeconfigContextTemplate: dev-{{.Name\n")); err == nil {
+		t.Errorf("expected an error for an invalid kubeconfigContextTemplate")
+	}
+	if _, err := parse([]byte("kubeconfigMode: rw-r--r--\n")); err == nil {
+		t.Errorf("expected an error for an invalid kubeconfigMode")
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Setenv("KIND_USER_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	assert.StringEqual(t, filepath.Join("/xdg", "kind", "config.yaml"), Path())
+	t.Setenv("KIND_USER_CONFIG", "/tmp/kind.yaml")
+	assert.StringEqual(t, "/tmp/kind.yaml", Path())
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kindtest provides helpers for Go tests that run against a kind
+// cluster, creating a uniquely named cluster that is deleted when the test
+// or the package's tests are done.
+//
+// A cluster for a single test:
+//
+//	func TestFoo(t *testing.T) {
+//		c := kindtest.NewCluster(t, kindtest.Options{})
+//		out, err := c.Exec(c.ControlPlane(), "crictl", "ps")
+//		...
+//	}
+//
+// A cluster shared by every test in a package:
+//
+//	var shared *kindtest.Cluster
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(kindtest.Main(m, kindtest.Options{}, func(c *kindtest.Cluster) {
+//			shared = c
+//		}))
+//	}
+//
+// kind does not depend on client-go, to get a rest.Config for a cluster use
+// either Cluster.ClientConfig or the kubeconfig at Cluster.KubeconfigPath:
+//
+//	cfg, err := clientcmd.BuildConfigFromFlags("", c.KubeconfigPath())
+package kindtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// DefaultNamePrefix is the default prefix of the generated cluster names
+const DefaultNamePrefix = "kindtest"
+
+// DefaultWait is how long cluster creation waits for the control plane to be
+// ready by default
+const DefaultWait = 5 * time.Minute
+
+// Options configures the cluster created by NewCluster and Main
+type Options struct {
+	// NamePrefix is the prefix of the cluster name, a random suffix is
+	// appended so concurrent tests and packages do not conflict.
+	// Defaults to DefaultNamePrefix
+	NamePrefix string
+
+	// Config is the cluster config, its name is ignored.
+	// Defaults to a single node cluster
+	Config *v1alpha4.Cluster
+
+	// Image overrides the node image of every node
+	Image string
+
+	// Wait is how long to wait for the control plane to be ready.
+	// Defaults to DefaultWait, a negative value does not wait
+	Wait time.Duration
+
+	// LogsDir is a directory the cluster logs are exported to before the
+	// cluster is deleted, if the test failed.
+	// The logs are exported to a subdirectory named after the cluster
+	LogsDir string
+
+	// KeepOnFailure keeps the cluster instead of deleting it if the test
+	// failed, so it can be inspected. It must be deleted with kind delete
+	KeepOnFailure bool
+
+	// Logger is the logger for cluster creation and deletion.
+	// Defaults to discarding the logs
+	Logger log.Logger
+
+	// ProviderOptions select the container runtime, by default it is
+	// detected as by the kind CLI
+	ProviderOptions []cluster.ProviderOption
+}
+
+// Cluster is a kind cluster created for tests
+type Cluster struct {
+	name           string
+	provider       *cluster.Provider
+	kubeconfigPath string
+	tempDir        string
+	opts           Options
+}
+
+// NewCluster creates a cluster for the test t, which is deleted when the
+// test and its subtests complete. The test fails immediately if the
+// cluster cannot be created.
+func NewCluster(t testing.TB, opts Options) *Cluster {
+	t.Helper()
+	c, err := Start(opts)
+	if err != nil {
+		t.Fatalf("failed to create kind cluster: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.stop(t.Failed()); err != nil {
+			t.Errorf("failed to delete kind cluster %s: %v", c.name, err)
+		}
+	})
+	return c
+}
+
+// Main creates a cluster, calls use with it, then runs the tests and
+// deletes the cluster. It returns the exit code for os.Exit. The cluster is
+// kept or its logs exported per Options if any test failed.
+//
+// Main is meant to be called from TestMain, to share one cluster between
+// the tests in a package.
+func Main(m *testing.M, opts Options, use func(*Cluster)) int {
+	c, err := Start(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create kind cluster: %v\n", err)
+		return 1
+	}
+	use(c)
+	code := m.Run()
+	if err := c.stop(code != 0); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to delete kind cluster %s: %v\n", c.name, err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	return code
+}
+
+// Start creates a cluster outside of a test, it must be deleted with Delete
+func Start(opts Options) (*Cluster, error) {
+	if opts.NamePrefix == "" {
+		opts.NamePrefix = DefaultNamePrefix
+	}
+	if opts.Wait == 0 {
+		opts.Wait = DefaultWait
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.NoopLogger{}
+	}
+	name, err := uniqueName(opts.NamePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// write the kubeconfig to a temporary file, rather than the user's
+	tempDir, err := os.MkdirTemp("", name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a temporary directory")
+	}
+	c := &Cluster{
+		name: name,
+		provider: cluster.NewProvider(
+			append([]cluster.ProviderOption{cluster.ProviderWithLogger(opts.Logger)}, opts.ProviderOptions...)...,
+		),
+		kubeconfigPath: filepath.Join(tempDir, "kubeconfig"),
+		tempDir:        tempDir,
+		opts:           opts,
+	}
+
+	createOptions := []cluster.CreateOption{
+		cluster.CreateWithKubeconfigPath(c.kubeconfigPath),
+		cluster.CreateWithDisplayUsage(false),
+		cluster.CreateWithDisplaySalutation(false),
+	}
+	if opts.Config != nil {
+		createOptions = append(createOptions, cluster.CreateWithV1Alpha4Config(opts.Config))
+	}
+	if opts.Image != "" {
+		createOptions = append(createOptions, cluster.CreateWithNodeImage(opts.Image))
+	}
+	if opts.Wait > 0 {
+		createOptions = append(createOptions, cluster.CreateWithWaitForReady(opts.Wait))
+	}
+	if err := c.provider.Create(name, createOptions...); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, err
+	}
+	return c, nil
+}
+
+// Delete deletes the cluster
+func (c *Cluster) Delete() error {
+	return c.stop(false)
+}
+
+// stop deletes the cluster, unless it should be kept for a failed test
+func (c *Cluster) stop(failed bool) error {
+	if failed && c.opts.KeepOnFailure {
+		c.opts.Logger.Warnf("Keeping kind cluster %s, delete it with: kind delete cluster --name %s", c.name, c.name)
+		return nil
+	}
+	var options []cluster.DeleteOption
+	if failed && c.opts.LogsDir != "" {
+		options = append(options, cluster.DeleteWithExportLogs(filepath.Join(c.opts.LogsDir, c.name)))
+	}
+	if err := c.provider.Delete(c.name, c.kubeconfigPath, options...); err != nil {
+		return err
+	}
+	return os.RemoveAll(c.tempDir)
+}
+
+// Name returns the name of the cluster
+func (c *Cluster) Name() string {
+	return c.name
+}
+
+// Provider returns the provider the cluster was created with, for the
+// operations this package does not wrap
+func (c *Cluster) Provider() *cluster.Provider {
+	return c.provider
+}
+
+// KubeconfigPath returns the path of a kubeconfig file for the cluster,
+// which only contains this cluster
+func (c *Cluster) KubeconfigPath() string {
+	return c.kubeconfigPath
+}
+
+// ClientConfig returns the endpoint and credentials of the cluster's API
+// server, see cluster.ClientConfig for building a rest.Config from it
+func (c *Cluster) ClientConfig() (*cluster.ClientConfig, error) {
+	return c.provider.ClientConfig(c.name, false)
+}
+
+// Nodes returns the nodes of the cluster
+func (c *Cluster) Nodes() ([]nodes.Node, error) {
+	return c.provider.ListNodes(c.name)
+}
+
+// ControlPlane returns the name of the node the control plane was
+// initialized on, or "" if it cannot be found
+func (c *Cluster) ControlPlane() string {
+	allNodes, err := c.Nodes()
+	if err != nil {
+		return ""
+	}
+	node, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return ""
+	}
+	return node.String()
+}
+
+// Exec runs a command on the named node, returning its combined output
+func (c *Cluster) Exec(node, command string, args ...string) (string, error) {
+	allNodes, err := c.Nodes()
+	if err != nil {
+		return "", err
+	}
+	for _, n := range allNodes {
+		if n.String() != node {
+			continue
+		}
+		var out bytes.Buffer
+		err := n.Command(command, args...).SetStdout(&out).SetStderr(&out).Run()
+		return out.String(), err
+	}
+	return "", errors.Errorf("unknown node %q in cluster %s", node, c.name)
+}
+
+// uniqueName returns prefix with a random suffix
+func uniqueName(prefix string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", errors.Wrap(err, "failed to generate a cluster name")
+	}
+	return prefix + "-" + hex.EncodeToString(suffix), nil
+}
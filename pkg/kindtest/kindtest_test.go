@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kindtest
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestUniqueName(t *testing.T) {
+	t.Parallel()
+	first, err := uniqueName(DefaultNamePrefix)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := uniqueName(DefaultNamePrefix)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^kindtest-[0-9a-f]{8}$`).MatchString(first) {
+		t.Errorf("unexpected name %q", first)
+	}
+	if first == second {
+		t.Errorf("expected unique names but got %q twice", first)
+	}
+}